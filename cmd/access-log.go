@@ -0,0 +1,370 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Requests against buckets with access logging enabled are recorded in
+// the s3 server access log format, buffered in memory and periodically
+// delivered as log objects to the target bucket of the logged bucket.
+
+const (
+	// Interval between two deliveries of buffered access logs.
+	accessLogFlushInterval = 5 * time.Minute
+
+	// Buffered access logs of a bucket are delivered right away once
+	// they grow beyond this size.
+	maxAccessLogBufferSize = 1 * 1024 * 1024
+
+	// Time format used in access log records.
+	accessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+	// Time format used in the name of access log objects.
+	accessLogObjectTimeFormat = "2006-01-02-15-04-05"
+)
+
+// accessLogs - buffered access log records of all buckets.
+type accessLogs struct {
+	mutex *sync.Mutex
+
+	// Newline separated log records per logged bucket.
+	records map[string]*bytes.Buffer
+
+	// Signals the flusher to deliver the buffered logs early.
+	flushCh chan struct{}
+}
+
+// Variable represents buffered access logs in memory.
+var globalAccessLogs = newAccessLogs()
+
+func newAccessLogs() *accessLogs {
+	return &accessLogs{
+		mutex:   &sync.Mutex{},
+		records: make(map[string]*bytes.Buffer),
+		flushCh: make(chan struct{}, 1),
+	}
+}
+
+// Record appends a log record for a bucket.
+func (al *accessLogs) Record(bucket, record string) {
+	al.mutex.Lock()
+	defer al.mutex.Unlock()
+
+	buf, ok := al.records[bucket]
+	if !ok {
+		buf = &bytes.Buffer{}
+		al.records[bucket] = buf
+	}
+	buf.WriteString(record)
+	buf.WriteByte('\n')
+
+	if buf.Len() >= maxAccessLogBufferSize {
+		// Flush is already pending if the channel is full.
+		select {
+		case al.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush delivers all buffered log records to the target buckets of the
+// logged buckets. Records of buckets which no longer have access
+// logging enabled are dropped.
+func (al *accessLogs) Flush(objAPI ObjectLayer, now time.Time) {
+	al.mutex.Lock()
+	records := al.records
+	al.records = make(map[string]*bytes.Buffer)
+	al.mutex.Unlock()
+
+	for bucket, buf := range records {
+		lCfg := getBucketLogging(bucket)
+		if lCfg == nil {
+			continue
+		}
+		objectName := getAccessLogObjectName(lCfg.LoggingEnabled.TargetPrefix, now)
+		data := buf.Bytes()
+		metadata := map[string]string{"content-type": "text/plain"}
+		_, err := objAPI.PutObject(context.Background(), lCfg.LoggingEnabled.TargetBucket, objectName, int64(len(data)), bytes.NewReader(data), metadata, getSHA256Hash(data))
+		errorIf(err, "Unable to deliver access logs of bucket %s.", bucket)
+	}
+}
+
+// Returns the name of a new access log object, like s3 it is the
+// target prefix followed by the delivery time and a unique string.
+func getAccessLogObjectName(prefix string, now time.Time) string {
+	unique := strings.ToUpper(strings.Replace(mustGetUUID(), "-", "", -1))[:16]
+	return prefix + now.UTC().Format(accessLogObjectTimeFormat) + "-" + unique
+}
+
+// Start delivering buffered access logs in the background, every node
+// delivers the logs of the requests it served.
+func startAccessLogFlusher(objAPI ObjectLayer) {
+	go func() {
+		ticker := time.NewTicker(accessLogFlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-globalAccessLogs.flushCh:
+			case <-globalServiceDoneCh:
+				// Deliver what is left before going away.
+				globalAccessLogs.Flush(objAPI, time.Now().UTC())
+				return
+			}
+			globalAccessLogs.Flush(objAPI, time.Now().UTC())
+		}
+	}()
+}
+
+// accessLogHandler - records requests against buckets with access
+// logging enabled.
+type accessLogHandler struct {
+	handler http.Handler
+}
+
+func setAccessLogHandler(h http.Handler) http.Handler {
+	return accessLogHandler{handler: h}
+}
+
+func (h accessLogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, object := urlPath2BucketObjectName(r.URL)
+	if getBucketLogging(bucket) == nil {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	startTime := time.Now().UTC()
+	lw := &accessLogResponseWriter{ResponseWriter: w}
+	h.handler.ServeHTTP(lw, r)
+	globalAccessLogs.Record(bucket, getAccessLogRecord(r, lw, bucket, object, startTime, time.Now().UTC()))
+}
+
+// accessLogResponseWriter - records the status, the size and for error
+// responses the error code of a response.
+type accessLogResponseWriter struct {
+	http.ResponseWriter
+
+	status    int
+	bytesSent int64
+
+	// Beginning of the body of error responses.
+	errorBody bytes.Buffer
+}
+
+// Error codes are looked up in the first bytes of error responses.
+const maxAccessLogErrorBodySize = 1024
+
+func (lw *accessLogResponseWriter) WriteHeader(status int) {
+	if lw.status == 0 {
+		lw.status = status
+	}
+	lw.ResponseWriter.WriteHeader(status)
+}
+
+func (lw *accessLogResponseWriter) Write(p []byte) (int, error) {
+	if lw.status == 0 {
+		lw.status = http.StatusOK
+	}
+	if lw.status >= http.StatusBadRequest && lw.errorBody.Len() < maxAccessLogErrorBodySize {
+		n := maxAccessLogErrorBodySize - lw.errorBody.Len()
+		if n > len(p) {
+			n = len(p)
+		}
+		lw.errorBody.Write(p[:n])
+	}
+	n, err := lw.ResponseWriter.Write(p)
+	lw.bytesSent += int64(n)
+	return n, err
+}
+
+// Flush - handlers flush responses while writing them.
+func (lw *accessLogResponseWriter) Flush() {
+	if f, ok := lw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Returns the s3 error code of an error response, empty otherwise.
+func (lw *accessLogResponseWriter) errorCode() string {
+	body := lw.errorBody.String()
+	start := strings.Index(body, "<Code>")
+	if start < 0 {
+		return ""
+	}
+	body = body[start+len("<Code>"):]
+	end := strings.Index(body, "</Code>")
+	if end < 0 {
+		return ""
+	}
+	return body[:end]
+}
+
+// Returns the operation of a request in the access log format, like
+// REST.GET.OBJECT or REST.PUT.BUCKETPOLICY.
+func getAccessLogOperation(r *http.Request, object string) string {
+	query := r.URL.Query()
+	resource := "BUCKET"
+	if object != "" {
+		resource = "OBJECT"
+	}
+
+	switch {
+	case object != "" && query.Get("uploadId") != "":
+		resource = "UPLOAD"
+		if r.Method == httpPUT {
+			resource = "PART"
+		}
+	case object == "" && r.Method == httpPOST && queryHas(query, "delete"):
+		resource = "MULTI_OBJECT_DELETE"
+	default:
+		for _, subResource := range accessLogSubResources {
+			if queryHas(query, subResource.name) {
+				resource = subResource.resource
+				if object != "" && subResource.name == "tagging" {
+					resource = "OBJECT_TAGGING"
+				}
+				break
+			}
+		}
+	}
+	return "REST." + r.Method + "." + resource
+}
+
+// Sub-resources named in access log operations.
+var accessLogSubResources = []struct {
+	name     string
+	resource string
+}{
+	{"acl", "ACL"},
+	{"cors", "CORS"},
+	{"encryption", "ENCRYPTION"},
+	{"legal-hold", "LEGAL_HOLD"},
+	{"lifecycle", "LIFECYCLE"},
+	{"location", "LOCATION"},
+	{"logging", "LOGGING_STATUS"},
+	{"notification", "NOTIFICATION"},
+	{"object-lock", "OBJECT_LOCK_CONFIGURATION"},
+	{"policy", "BUCKETPOLICY"},
+	{"retention", "RETENTION"},
+	{"select", "SELECT"},
+	{"tagging", "TAGGING"},
+	{"uploads", "UPLOADS"},
+	{"versioning", "VERSIONING"},
+	{"versions", "VERSIONS"},
+	{"website", "WEBSITE"},
+}
+
+// Returns true if the query carries the key, sub-resources usually
+// come without a value.
+func queryHas(query url.Values, key string) bool {
+	_, ok := query[key]
+	return ok
+}
+
+// Returns the size of the object a response is about, -1 if unknown.
+func getAccessLogObjectSize(r *http.Request, header http.Header, object string) int64 {
+	if object == "" {
+		return -1
+	}
+	if r.Method == httpPUT {
+		return r.ContentLength
+	}
+	// Ranged requests carry the full size in Content-Range.
+	if contentRange := header.Get("Content-Range"); contentRange != "" {
+		if i := strings.LastIndex(contentRange, "/"); i >= 0 {
+			if size, err := strconv.ParseInt(contentRange[i+1:], 10, 64); err == nil {
+				return size
+			}
+		}
+	}
+	if size, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil {
+		return size
+	}
+	return -1
+}
+
+// Returns a log field, "-" stands for values which are not known or do
+// not apply.
+func accessLogField(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// Returns a numeric log field, negative values are not known.
+func accessLogIntField(value int64) string {
+	if value < 0 {
+		return "-"
+	}
+	return strconv.FormatInt(value, 10)
+}
+
+// getAccessLogRecord - returns the access log record of a served
+// request in the s3 server access log format.
+func getAccessLogRecord(r *http.Request, lw *accessLogResponseWriter, bucket, object string, startTime, endTime time.Time) string {
+	remoteIP := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remoteIP); err == nil {
+		remoteIP = host
+	}
+	requestURI := r.RequestURI
+	if requestURI == "" {
+		requestURI = r.URL.RequestURI()
+	}
+	status := lw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	key := ""
+	if object != "" {
+		key = getURLEncodedName(object)
+	}
+	header := lw.Header()
+	totalTime := endTime.Sub(startTime).Nanoseconds() / int64(time.Millisecond)
+
+	return fmt.Sprintf("%s %s [%s] %s %s %s %s %s \"%s %s %s\" %d %s %s %s %d - \"%s\" \"%s\" %s",
+		globalMinioDefaultOwnerID,
+		bucket,
+		startTime.Format(accessLogTimeFormat),
+		accessLogField(remoteIP),
+		accessLogField(getRequestAccessKey(r)),
+		accessLogField(header.Get(responseRequestIDKey)),
+		getAccessLogOperation(r, object),
+		accessLogField(key),
+		r.Method, requestURI, r.Proto,
+		status,
+		accessLogField(lw.errorCode()),
+		accessLogIntField(lw.bytesSent),
+		accessLogIntField(getAccessLogObjectSize(r, header, object)),
+		totalTime,
+		accessLogField(r.Referer()),
+		accessLogField(r.UserAgent()),
+		accessLogField(header.Get("x-amz-version-id")),
+	)
+}
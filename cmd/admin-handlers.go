@@ -17,13 +17,16 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -46,6 +49,21 @@ const (
 	mgmtDryRun    mgmtQueryKey = "dry-run"
 )
 
+// Only valid query params for IAM management APIs.
+const (
+	mgmtAccessKey  mgmtQueryKey = "accessKey"
+	mgmtStatus     mgmtQueryKey = "status"
+	mgmtPolicyName mgmtQueryKey = "name"
+	mgmtPolicies   mgmtQueryKey = "policies"
+)
+
+// Only valid query params for the trace management API.
+const (
+	mgmtAPI       mgmtQueryKey = "api"
+	mgmtErrors    mgmtQueryKey = "errors"
+	mgmtInternode mgmtQueryKey = "internode"
+)
+
 // ServiceStatusHandler - GET /?service
 // HTTP header x-minio-operation: status
 // ----------
@@ -61,7 +79,7 @@ func (adminAPI adminAPIHandlers) ServiceStatusHandler(w http.ResponseWriter, r *
 	jsonBytes, err := json.Marshal(storageInfo)
 	if err != nil {
 		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to marshal storage info into json.")
+		errorIfCtx(r.Context(), err, "Failed to marshal storage info into json.")
 		return
 	}
 	// Reply with storage information (across nodes in a
@@ -124,7 +142,7 @@ func (adminAPI adminAPIHandlers) ServiceCredentialsHandler(w http.ResponseWriter
 	var req setCredsReq
 	err = xml.Unmarshal(inputData, &req)
 	if err != nil {
-		errorIf(err, "Cannot unmarshal credentials request")
+		errorIfCtx(r.Context(), err, "Cannot unmarshal credentials request")
 		writeErrorResponse(w, ErrMalformedXML, r.URL)
 		return
 	}
@@ -143,7 +161,7 @@ func (adminAPI adminAPIHandlers) ServiceCredentialsHandler(w http.ResponseWriter
 	// Notify all other Minio peers to update credentials
 	updateErrs := updateCredsOnPeers(cred)
 	for peer, err := range updateErrs {
-		errorIf(err, "Unable to update credentials on peer %s.", peer)
+		errorIfCtx(r.Context(), err, "Unable to update credentials on peer %s.", peer)
 	}
 
 	// Update local credentials
@@ -211,7 +229,7 @@ func (adminAPI adminAPIHandlers) ListLocksHandler(w http.ResponseWriter, r *http
 	volLocks, err := listPeerLocksInfo(globalAdminPeers, bucket, prefix, relTime)
 	if err != nil {
 		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to fetch lock information from remote nodes.")
+		errorIfCtx(r.Context(), err, "Failed to fetch lock information from remote nodes.")
 		return
 	}
 
@@ -219,7 +237,7 @@ func (adminAPI adminAPIHandlers) ListLocksHandler(w http.ResponseWriter, r *http
 	jsonBytes, err := json.Marshal(volLocks)
 	if err != nil {
 		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to marshal lock information into json.")
+		errorIfCtx(r.Context(), err, "Failed to marshal lock information into json.")
 		return
 	}
 
@@ -253,7 +271,7 @@ func (adminAPI adminAPIHandlers) ClearLocksHandler(w http.ResponseWriter, r *htt
 	volLocks, err := listPeerLocksInfo(globalAdminPeers, bucket, prefix, relTime)
 	if err != nil {
 		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to fetch lock information from remote nodes.")
+		errorIfCtx(r.Context(), err, "Failed to fetch lock information from remote nodes.")
 		return
 	}
 
@@ -261,7 +279,7 @@ func (adminAPI adminAPIHandlers) ClearLocksHandler(w http.ResponseWriter, r *htt
 	jsonBytes, err := json.Marshal(volLocks)
 	if err != nil {
 		writeErrorResponse(w, ErrInternalError, r.URL)
-		errorIf(err, "Failed to marshal lock information into json.")
+		errorIfCtx(r.Context(), err, "Failed to marshal lock information into json.")
 		return
 	}
 
@@ -335,7 +353,7 @@ func (adminAPI adminAPIHandlers) ListObjectsHealHandler(w http.ResponseWriter, r
 	}
 
 	// Get the list objects to be healed.
-	objectInfos, err := objLayer.ListObjectsHeal(bucket, prefix, marker, delimiter, maxKey)
+	objectInfos, err := objLayer.ListObjectsHeal(r.Context(), bucket, prefix, marker, delimiter, maxKey)
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -363,7 +381,7 @@ func (adminAPI adminAPIHandlers) ListBucketsHealHandler(w http.ResponseWriter, r
 	}
 
 	// Get the list buckets to be healed.
-	bucketsInfo, err := objLayer.ListBucketsHeal()
+	bucketsInfo, err := objLayer.ListBucketsHeal(r.Context())
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -408,7 +426,7 @@ func (adminAPI adminAPIHandlers) HealBucketHandler(w http.ResponseWriter, r *htt
 	}
 
 	// Heal the given bucket.
-	err := objLayer.HealBucket(bucket)
+	err := objLayer.HealBucket(r.Context(), bucket)
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -456,7 +474,7 @@ func (adminAPI adminAPIHandlers) HealObjectHandler(w http.ResponseWriter, r *htt
 	}
 
 	// Check if object exists.
-	if _, err := objLayer.GetObjectInfo(bucket, object); err != nil {
+	if _, err := objLayer.GetObjectInfo(r.Context(), bucket, object); err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -467,7 +485,7 @@ func (adminAPI adminAPIHandlers) HealObjectHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	err := objLayer.HealObject(bucket, object)
+	err := objLayer.HealObject(r.Context(), bucket, object)
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -542,3 +560,403 @@ func (adminAPI adminAPIHandlers) HealFormatHandler(w http.ResponseWriter, r *htt
 	// Return 200 on success.
 	writeSuccessResponseHeadersOnly(w)
 }
+
+// addUserReq - add user request body.
+type addUserReq struct {
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	Status    string `json:"status,omitempty"`
+}
+
+// userInfo - IAM user as listed by the admin API, the secret key is
+// never returned.
+type userInfo struct {
+	Status   string   `json:"status"`
+	Policies []string `json:"policies,omitempty"`
+}
+
+// AddUserHandler - POST /?iam
+// HTTP header x-minio-operation: add-user
+// ----------
+// Creates an IAM user or updates the secret key and status of an
+// existing one, policies attached to the user are kept. Users are
+// enabled unless the request asks otherwise.
+func (adminAPI adminAPIHandlers) AddUserHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	inputData, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+
+	var req addUserReq
+	if err = json.Unmarshal(inputData, &req); err != nil {
+		writeErrorResponse(w, ErrAdminInvalidArgument, r.URL)
+		return
+	}
+	if req.Status == "" {
+		req.Status = iamUserEnabled
+	}
+	if !isValidIAMUserStatus(req.Status) {
+		writeErrorResponse(w, ErrAdminInvalidArgument, r.URL)
+		return
+	}
+
+	cred, err := getCredential(req.AccessKey, req.SecretKey)
+	switch err {
+	case errInvalidAccessKeyLength:
+		writeErrorResponse(w, ErrAdminInvalidAccessKey, r.URL)
+		return
+	case errInvalidSecretKeyLength:
+		writeErrorResponse(w, ErrAdminInvalidSecretKey, r.URL)
+		return
+	}
+
+	// The server credentials can not be shadowed by a user.
+	if cred.AccessKey == serverConfig.GetCredential().AccessKey {
+		writeErrorResponse(w, ErrAdminInvalidAccessKey, r.URL)
+		return
+	}
+
+	if err = setIAMUser(cred, req.Status, objectAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// RemoveUserHandler - POST /?iam&accessKey=myuser
+// HTTP header x-minio-operation: remove-user
+// ----------
+// Removes an IAM user.
+func (adminAPI adminAPIHandlers) RemoveUserHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	accessKey := r.URL.Query().Get(string(mgmtAccessKey))
+	if err := removeIAMUser(accessKey, objectAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// SetUserStatusHandler - POST /?iam&accessKey=myuser&status=disabled
+// HTTP header x-minio-operation: set-user-status
+// ----------
+// Enables or disables an IAM user, requests signed by disabled users
+// are rejected.
+func (adminAPI adminAPIHandlers) SetUserStatusHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	status := vars.Get(string(mgmtStatus))
+	if !isValidIAMUserStatus(status) {
+		writeErrorResponse(w, ErrAdminInvalidArgument, r.URL)
+		return
+	}
+
+	if err := setIAMUserStatus(vars.Get(string(mgmtAccessKey)), status, objectAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// ListUsersHandler - GET /?iam
+// HTTP header x-minio-operation: list-users
+// ----------
+// Lists all IAM users along with their status and policies.
+func (adminAPI adminAPIHandlers) ListUsersHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	users := make(map[string]userInfo)
+	for accessKey, user := range globalIAMSys.ListUsers() {
+		users[accessKey] = userInfo{
+			Status:   user.Status,
+			Policies: user.Policies,
+		}
+	}
+
+	jsonBytes, err := json.Marshal(users)
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIfCtx(r.Context(), err, "Failed to marshal users into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// AddPolicyHandler - POST /?iam&name=mypolicy
+// HTTP header x-minio-operation: add-policy
+// ----------
+// Creates or replaces a named IAM policy. The request body is a policy
+// document using the bucket policy grammar.
+func (adminAPI adminAPIHandlers) AddPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	name := r.URL.Query().Get(string(mgmtPolicyName))
+	if name == "" {
+		writeErrorResponse(w, ErrAdminInvalidArgument, r.URL)
+		return
+	}
+
+	policyBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxAccessPolicySize+1))
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if len(policyBytes) > maxAccessPolicySize {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+
+	policy := &bucketPolicy{}
+	if err = parseIAMPolicy(bytes.NewReader(policyBytes), policy); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to parse IAM policy.")
+		writeErrorResponse(w, ErrAdminMalformedPolicy, r.URL)
+		return
+	}
+
+	if err = setIAMPolicy(name, policy, objectAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// RemovePolicyHandler - POST /?iam&name=mypolicy
+// HTTP header x-minio-operation: remove-policy
+// ----------
+// Removes a named IAM policy.
+func (adminAPI adminAPIHandlers) RemovePolicyHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	name := r.URL.Query().Get(string(mgmtPolicyName))
+	if err := removeIAMPolicy(name, objectAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// ListPoliciesHandler - GET /?iam
+// HTTP header x-minio-operation: list-policies
+// ----------
+// Lists all named IAM policies.
+func (adminAPI adminAPIHandlers) ListPoliciesHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	jsonBytes, err := json.Marshal(globalIAMSys.ListPolicies())
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		errorIfCtx(r.Context(), err, "Failed to marshal policies into json.")
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// SetUserPolicyHandler - POST /?iam&accessKey=myuser&policies=readonly,logs
+// HTTP header x-minio-operation: set-user-policy
+// ----------
+// Replaces the policies attached to an IAM user, an empty list
+// detaches all of them.
+func (adminAPI adminAPIHandlers) SetUserPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	vars := r.URL.Query()
+	var policies []string
+	if policiesStr := vars.Get(string(mgmtPolicies)); policiesStr != "" {
+		policies = strings.Split(policiesStr, ",")
+	}
+
+	if err := setIAMUserPolicies(vars.Get(string(mgmtAccessKey)), policies, objectAPI); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// getTraceFilter - returns the filter of the traced requests a trace
+// request asks for.
+func getTraceFilter(qval url.Values) (traceFilter, APIErrorCode) {
+	filter := traceFilter{
+		Bucket:     qval.Get(string(mgmtBucket)),
+		API:        qval.Get(string(mgmtAPI)),
+		ErrorsOnly: qval.Get(string(mgmtErrors)) == "yes",
+		Internode:  qval.Get(string(mgmtInternode)) == "yes",
+	}
+	if filter.Bucket != "" && !IsValidBucketName(filter.Bucket) {
+		return traceFilter{}, ErrInvalidBucketName
+	}
+	return filter, ErrNone
+}
+
+// TraceHandler - GET /?trace&bucket=mybucket&api=REST.GET.OBJECT&errors=yes&internode=yes
+// - all query parameters are optional
+// HTTP header x-minio-operation: trace
+// ----------
+// Streams the requests served by all servers as json lines until the
+// client goes away, empty lines keep the connection alive.
+func (adminAPI adminAPIHandlers) TraceHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	filter, adminAPIErr := getTraceFilter(r.URL.Query())
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Requests served locally are sent right away, those of remote
+	// peers are polled for.
+	localCh := globalTrace.Subscribe(filter)
+	defer globalTrace.Unsubscribe(localCh)
+	peerCh := make(chan []requestTrace)
+	if len(globalAdminPeers) > 1 {
+		for _, peer := range globalAdminPeers[1:] {
+			go pollPeerTrace(peer, filter, peerCh, doneCh)
+		}
+	}
+
+	var closeCh <-chan bool
+	if cn, ok := w.(http.CloseNotifier); ok {
+		closeCh = cn.CloseNotify()
+	}
+
+	writeResponse(w, http.StatusOK, nil, mimeJSON)
+	w.(http.Flusher).Flush()
+
+	enc := json.NewEncoder(w)
+	for {
+		var err error
+		select {
+		case info := <-localCh:
+			err = enc.Encode(info)
+		case entries := <-peerCh:
+			for _, info := range entries {
+				if err = enc.Encode(info); err != nil {
+					break
+				}
+			}
+		case <-time.After(globalSNSConnAlive):
+			_, err = w.Write([]byte("\n"))
+		case <-closeCh:
+			return
+		}
+		if err != nil {
+			return
+		}
+		w.(http.Flusher).Flush()
+	}
+}
+
+// ReloadLoggerHandler - POST /?logger
+// HTTP header x-minio-operation: reload
+// ----------
+// Replaces the loggers of all servers with the loggers of their config
+// files, servers whose logger config is invalid keep their loggers.
+func (adminAPI adminAPIHandlers) ReloadLoggerHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkRequestAuthType(r, "", "", "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponse(w, adminAPIErr, r.URL)
+		return
+	}
+
+	if err := reloadLoggers(); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to reload the loggers.")
+		writeErrorResponse(w, ErrAdminInvalidLogger, r.URL)
+		return
+	}
+
+	// Reload the loggers of the other servers.
+	if len(globalAdminPeers) > 1 {
+		for peer, err := range reloadPeerLoggers(globalAdminPeers[1:]) {
+			errorIfCtx(r.Context(), err, "Unable to reload the loggers of peer %s.", peer)
+		}
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
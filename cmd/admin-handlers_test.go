@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"io/ioutil"
@@ -592,13 +593,13 @@ func TestListObjectsHealHandler(t *testing.T) {
 	}
 	defer adminTestBed.TearDown()
 
-	err = adminTestBed.objLayer.MakeBucket("mybucket")
+	err = adminTestBed.objLayer.MakeBucket(context.Background(), "mybucket")
 	if err != nil {
 		t.Fatalf("Failed to make bucket - %v", err)
 	}
 
 	// Delete bucket after running all test cases.
-	defer adminTestBed.objLayer.DeleteBucket("mybucket")
+	defer adminTestBed.objLayer.DeleteBucket(context.Background(), "mybucket")
 
 	testCases := []struct {
 		bucket     string
@@ -723,13 +724,13 @@ func TestHealBucketHandler(t *testing.T) {
 	}
 	defer adminTestBed.TearDown()
 
-	err = adminTestBed.objLayer.MakeBucket("mybucket")
+	err = adminTestBed.objLayer.MakeBucket(context.Background(), "mybucket")
 	if err != nil {
 		t.Fatalf("Failed to make bucket - %v", err)
 	}
 
 	// Delete bucket after running all test cases.
-	defer adminTestBed.objLayer.DeleteBucket("mybucket")
+	defer adminTestBed.objLayer.DeleteBucket(context.Background(), "mybucket")
 
 	testCases := []struct {
 		bucket     string
@@ -800,12 +801,12 @@ func TestHealObjectHandler(t *testing.T) {
 	// Create an object myobject under bucket mybucket.
 	bucketName := "mybucket"
 	objName := "myobject"
-	err = adminTestBed.objLayer.MakeBucket(bucketName)
+	err = adminTestBed.objLayer.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		t.Fatalf("Failed to make bucket %s - %v", bucketName, err)
 	}
 
-	_, err = adminTestBed.objLayer.PutObject(bucketName, objName,
+	_, err = adminTestBed.objLayer.PutObject(context.Background(), bucketName, objName,
 		int64(len("hello")), bytes.NewReader([]byte("hello")), nil, "")
 	if err != nil {
 		t.Fatalf("Failed to create %s - %v", objName, err)
@@ -813,8 +814,8 @@ func TestHealObjectHandler(t *testing.T) {
 
 	// Delete bucket and object after running all test cases.
 	defer func(objLayer ObjectLayer, bucketName, objName string) {
-		objLayer.DeleteObject(bucketName, objName)
-		objLayer.DeleteBucket(bucketName)
+		objLayer.DeleteObject(context.Background(), bucketName, objName)
+		objLayer.DeleteBucket(context.Background(), bucketName)
 	}(adminTestBed.objLayer, bucketName, objName)
 
 	testCases := []struct {
@@ -921,3 +922,230 @@ func TestHealFormatHandler(t *testing.T) {
 		t.Errorf("Expected to succeed but failed with %d", rec.Code)
 	}
 }
+
+// Returns a signed IAM management request.
+func getIAMCmdRequest(method, op string, queryVal url.Values, body []byte, cred credential) (*http.Request, error) {
+	if queryVal == nil {
+		queryVal = url.Values{}
+	}
+	queryVal.Set("iam", "")
+	req, err := newTestRequest(method, "/?"+queryVal.Encode(), int64(len(body)), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(minioAdminOpHeader, op)
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// TestIAMHandlers - Tests managing IAM users and policies and
+// requests signed by IAM users.
+func TestIAMHandlers(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+	initGlobalS3Peers(nil)
+
+	// S3 API handlers for requests signed by IAM users.
+	apiRouter := initTestAPIEndPoints(adminTestBed.objLayer, nil)
+
+	bucketName := getRandomBucketName()
+	if err = adminTestBed.objLayer.MakeBucket(context.Background(), bucketName); err != nil {
+		t.Fatalf("Failed to make bucket - %v", err)
+	}
+	if _, err = adminTestBed.objLayer.PutObject(context.Background(), bucketName, "object", 4, bytes.NewReader([]byte("data")), nil, ""); err != nil {
+		t.Fatalf("Failed to put object - %v", err)
+	}
+
+	serverCred := serverConfig.GetCredential()
+	userCred := credential{AccessKey: "newuser", SecretKey: "newuser123"}
+	readOnlyPolicy := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::` + bucketName + `/*"]}]}`
+
+	testCases := []struct {
+		method     string
+		op         string
+		queryVal   url.Values
+		body       string
+		cred       credential
+		statusCode int
+	}{
+		// 1. Add a user.
+		{"POST", "add-user", nil, `{"accessKey": "newuser", "secretKey": "newuser123"}`, serverCred, http.StatusOK},
+		// 2. Users can not shadow the server credentials.
+		{"POST", "add-user", nil, `{"accessKey": "` + serverCred.AccessKey + `", "secretKey": "newuser123"}`, serverCred, http.StatusBadRequest},
+		// 3. Invalid secret key.
+		{"POST", "add-user", nil, `{"accessKey": "otheruser", "secretKey": "short"}`, serverCred, http.StatusBadRequest},
+		// 4. Invalid status.
+		{"POST", "add-user", nil, `{"accessKey": "otheruser", "secretKey": "otheruser123", "status": "unknown"}`, serverCred, http.StatusBadRequest},
+		// 5. Add a policy.
+		{"POST", "add-policy", url.Values{"name": {"readonly"}}, readOnlyPolicy, serverCred, http.StatusOK},
+		// 6. Malformed policy.
+		{"POST", "add-policy", url.Values{"name": {"broken"}}, `{"Version": "2012-10-17"}`, serverCred, http.StatusBadRequest},
+		// 7. Missing policy name.
+		{"POST", "add-policy", nil, readOnlyPolicy, serverCred, http.StatusBadRequest},
+		// 8. Attach a missing policy.
+		{"POST", "set-user-policy", url.Values{"accessKey": {"newuser"}, "policies": {"missing"}}, "", serverCred, http.StatusNotFound},
+		// 9. Attach the policy.
+		{"POST", "set-user-policy", url.Values{"accessKey": {"newuser"}, "policies": {"readonly"}}, "", serverCred, http.StatusOK},
+		// 10. Invalid user status.
+		{"POST", "set-user-status", url.Values{"accessKey": {"newuser"}, "status": {"unknown"}}, "", serverCred, http.StatusBadRequest},
+		// 11. Remove a missing user.
+		{"POST", "remove-user", url.Values{"accessKey": {"unknown"}}, "", serverCred, http.StatusNotFound},
+		// 12. Remove a missing policy.
+		{"POST", "remove-policy", url.Values{"name": {"missing"}}, "", serverCred, http.StatusNotFound},
+		// 13. IAM users can not use the admin API.
+		{"GET", "list-users", nil, "", userCred, http.StatusForbidden},
+	}
+	for i, test := range testCases {
+		req, err := getIAMCmdRequest(test.method, test.op, test.queryVal, []byte(test.body), test.cred)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct %s request - %v", i+1, test.op, err)
+		}
+		rec := httptest.NewRecorder()
+		adminTestBed.mux.ServeHTTP(rec, req)
+		if test.statusCode != rec.Code {
+			t.Errorf("Test %d - Expected HTTP status code %d but received %d: %s",
+				i+1, test.statusCode, rec.Code, rec.Body.String())
+		}
+	}
+
+	// List users, the secret key is never returned.
+	req, err := getIAMCmdRequest("GET", "list-users", nil, nil, serverCred)
+	if err != nil {
+		t.Fatalf("Failed to construct list users request - %v", err)
+	}
+	rec := httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	var users map[string]userInfo
+	if err = json.Unmarshal(rec.Body.Bytes(), &users); err != nil {
+		t.Fatalf("Failed to unmarshal users - %v", err)
+	}
+	if user, ok := users["newuser"]; !ok || user.Status != iamUserEnabled || len(user.Policies) != 1 {
+		t.Errorf("Unexpected users %v", users)
+	}
+	if bytes.Contains(rec.Body.Bytes(), []byte(userCred.SecretKey)) {
+		t.Errorf("Expected secret key not to be listed")
+	}
+
+	// Requests signed by the user are subject to its policies.
+	s3TestCases := []struct {
+		method     string
+		urlStr     string
+		statusCode int
+	}{
+		{"GET", getGetObjectURL("", bucketName, "object"), http.StatusOK},
+		{"PUT", getPutObjectURL("", bucketName, "object"), http.StatusForbidden},
+		{"GET", getListBucketURL(""), http.StatusForbidden},
+		{"PUT", getPutPolicyURL("", bucketName), http.StatusForbidden},
+	}
+	for i, test := range s3TestCases {
+		for _, signer := range []signerType{signerV2, signerV4} {
+			req, err = newTestSignedRequest(test.method, test.urlStr, 0, nil, userCred.AccessKey, userCred.SecretKey, signer)
+			if err != nil {
+				t.Fatalf("Test %d - Failed to construct S3 request - %v", i+1, err)
+			}
+			rec = httptest.NewRecorder()
+			apiRouter.ServeHTTP(rec, req)
+			if test.statusCode != rec.Code {
+				t.Errorf("Test %d - Expected HTTP status code %d but received %d: %s",
+					i+1, test.statusCode, rec.Code, rec.Body.String())
+			}
+		}
+	}
+
+	// Requests of disabled users are rejected.
+	req, err = getIAMCmdRequest("POST", "set-user-status", url.Values{"accessKey": {"newuser"}, "status": {iamUserDisabled}}, nil, serverCred)
+	if err != nil {
+		t.Fatalf("Failed to construct set user status request - %v", err)
+	}
+	rec = httptest.NewRecorder()
+	adminTestBed.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected to disable user but failed with %d", rec.Code)
+	}
+	req, err = newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, "object"), 0, nil, userCred.AccessKey, userCred.SecretKey)
+	if err != nil {
+		t.Fatalf("Failed to construct S3 request - %v", err)
+	}
+	rec = httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected disabled user to be rejected, received %d", rec.Code)
+	}
+}
+
+// Tests that loggers are reloaded from the config file.
+func TestReloadLoggerHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	log.mu.Lock()
+	savedLoggers, savedClosers := log.loggers, log.closers
+	log.loggers, log.closers = nil, nil
+	log.mu.Unlock()
+	defer func() {
+		log.mu.Lock()
+		for _, closer := range log.closers {
+			closer.Close()
+		}
+		log.loggers, log.closers = savedLoggers, savedClosers
+		log.mu.Unlock()
+	}()
+
+	mux := router.NewRouter()
+	registerAdminRouter(mux)
+
+	fileCfg := loggerFile{Enable: true, Filename: rootPath + "/minio.log", Level: "error", MaxSize: 100}
+	testCases := []struct {
+		cfg                loggerConfig
+		expectedStatusCode int
+		expectedLoggers    int
+	}{
+		// Test case - 1.
+		// File logger.
+		{loggerConfig{File: fileCfg}, http.StatusOK, 1},
+		// Test case - 2.
+		// Invalid console level, the loggers are kept.
+		{loggerConfig{Console: loggerConsole{Enable: true, Level: "loud"}}, http.StatusBadRequest, 1},
+		// Test case - 3.
+		// Console and file loggers.
+		{loggerConfig{Console: loggerConsole{Enable: true, Level: "error"}, File: fileCfg}, http.StatusOK, 2},
+	}
+	cred := serverConfig.GetCredential()
+	for i, testCase := range testCases {
+		// Change the config file only, the loggers are loaded from it.
+		loadedCfg := serverConfig.GetLogger()
+		serverConfig.SetLogger(testCase.cfg)
+		if err = serverConfig.Save(); err != nil {
+			t.Fatalf("Test %d: Unable to save config. %s", i+1, err)
+		}
+		serverConfig.SetLogger(loadedCfg)
+
+		req, err := newTestRequest("POST", "/?logger", 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create reload request - %v", i+1, err)
+		}
+		req.Header.Set(minioAdminOpHeader, "reload")
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Test %d: Failed to sign reload request - %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatusCode {
+			t.Errorf("Test %d: Expected HTTP status code %d but received %d", i+1, testCase.expectedStatusCode, rec.Code)
+		}
+		if loggers := getLoggers(); len(loggers) != testCase.expectedLoggers {
+			t.Errorf("Test %d: Expected %d loggers, got %d", i+1, testCase.expectedLoggers, len(loggers))
+		}
+		if rec.Code == http.StatusOK && serverConfig.GetFileLogger() != testCase.cfg.File {
+			t.Errorf("Test %d: Expected file logger config %#v, got %#v", i+1, testCase.cfg.File, serverConfig.GetFileLogger())
+		}
+	}
+}
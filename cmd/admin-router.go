@@ -59,4 +59,33 @@ func registerAdminRouter(mux *router.Router) {
 	adminRouter.Methods("POST").Queries("heal", "").Headers(minioAdminOpHeader, "object").HandlerFunc(adminAPI.HealObjectHandler)
 	// Heal Format.
 	adminRouter.Methods("POST").Queries("heal", "").Headers(minioAdminOpHeader, "format").HandlerFunc(adminAPI.HealFormatHandler)
+
+	/// Trace operations
+
+	// Trace requests.
+	adminRouter.Methods("GET").Queries("trace", "").Headers(minioAdminOpHeader, "trace").HandlerFunc(adminAPI.TraceHandler)
+
+	/// Logger operations
+
+	// Reload loggers.
+	adminRouter.Methods("POST").Queries("logger", "").Headers(minioAdminOpHeader, "reload").HandlerFunc(adminAPI.ReloadLoggerHandler)
+
+	/// IAM operations
+
+	// Add user.
+	adminRouter.Methods("POST").Queries("iam", "").Headers(minioAdminOpHeader, "add-user").HandlerFunc(adminAPI.AddUserHandler)
+	// Remove user.
+	adminRouter.Methods("POST").Queries("iam", "").Headers(minioAdminOpHeader, "remove-user").HandlerFunc(adminAPI.RemoveUserHandler)
+	// Enable or disable user.
+	adminRouter.Methods("POST").Queries("iam", "").Headers(minioAdminOpHeader, "set-user-status").HandlerFunc(adminAPI.SetUserStatusHandler)
+	// List users.
+	adminRouter.Methods("GET").Queries("iam", "").Headers(minioAdminOpHeader, "list-users").HandlerFunc(adminAPI.ListUsersHandler)
+	// Add policy.
+	adminRouter.Methods("POST").Queries("iam", "").Headers(minioAdminOpHeader, "add-policy").HandlerFunc(adminAPI.AddPolicyHandler)
+	// Remove policy.
+	adminRouter.Methods("POST").Queries("iam", "").Headers(minioAdminOpHeader, "remove-policy").HandlerFunc(adminAPI.RemovePolicyHandler)
+	// List policies.
+	adminRouter.Methods("GET").Queries("iam", "").Headers(minioAdminOpHeader, "list-policies").HandlerFunc(adminAPI.ListPoliciesHandler)
+	// Attach policies to user.
+	adminRouter.Methods("POST").Queries("iam", "").Headers(minioAdminOpHeader, "set-user-policy").HandlerFunc(adminAPI.SetUserPolicyHandler)
 }
@@ -39,6 +39,8 @@ type adminCmdRunner interface {
 	Restart() error
 	ListLocks(bucket, prefix string, relTime time.Duration) ([]VolumeLockInfo, error)
 	ReInitDisks() error
+	Trace(filter traceFilter, duration time.Duration) ([]requestTrace, error)
+	ReloadLogger() error
 }
 
 // Restart - Sends a message over channel to the go-routine
@@ -88,6 +90,39 @@ func (rc remoteAdminClient) ReInitDisks() error {
 	return rc.Call("Admin.ReInitDisks", &args, &reply)
 }
 
+// Trace - Collects the requests served locally while the call lasts.
+func (lc localAdminClient) Trace(filter traceFilter, duration time.Duration) ([]requestTrace, error) {
+	return collectTrace(filter, duration, maxTracePollEntries), nil
+}
+
+// Trace - Collects the requests served by a remote server via RPC
+// while the call lasts.
+func (rc remoteAdminClient) Trace(filter traceFilter, duration time.Duration) ([]requestTrace, error) {
+	args := TraceArgs{
+		Filter:   filter,
+		Duration: duration,
+	}
+	var reply TraceReply
+	if err := rc.Call("Admin.Trace", &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Entries, nil
+}
+
+// ReloadLogger - Replaces the local loggers with the loggers of the
+// config file.
+func (lc localAdminClient) ReloadLogger() error {
+	return reloadLoggers()
+}
+
+// ReloadLogger - Signals a remote server via RPC to replace its loggers
+// with the loggers of its config file.
+func (rc remoteAdminClient) ReloadLogger() error {
+	args := AuthRPCArgs{}
+	reply := AuthRPCReply{}
+	return rc.Call("Admin.ReloadLogger", &args, &reply)
+}
+
 // adminPeer - represents an entity that implements Restart methods.
 type adminPeer struct {
 	addr      string
@@ -241,3 +276,53 @@ func reInitPeerDisks(peers adminPeers) error {
 	wg.Wait()
 	return nil
 }
+
+// pollPeerTrace - sends the requests served by a peer to traceCh until
+// doneCh is closed, the peer is asked for them over and over again.
+func pollPeerTrace(peer adminPeer, filter traceFilter, traceCh chan<- []requestTrace, doneCh <-chan struct{}) {
+	for {
+		entries, err := peer.cmdRunner.Trace(filter, maxTracePollDuration)
+		if err != nil {
+			errorIf(err, "Unable to trace requests of %s.", peer.addr)
+			// Wait before retrying unreachable peers.
+			select {
+			case <-time.After(maxTracePollDuration):
+				continue
+			case <-doneCh:
+				return
+			}
+		}
+		for i := range entries {
+			entries[i].Node = peer.addr
+		}
+		select {
+		case traceCh <- entries:
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// reloadPeerLoggers - reloads the loggers of peer servers, returns the
+// errors of the peers which failed by their address.
+func reloadPeerLoggers(peers adminPeers) map[string]error {
+	errs := make([]error, len(peers))
+
+	wg := sync.WaitGroup{}
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+			errs[idx] = peer.cmdRunner.ReloadLogger()
+		}(i, peer)
+	}
+	wg.Wait()
+
+	peerErrs := make(map[string]error)
+	for i, err := range errs {
+		if err != nil {
+			peerErrs[peers[i].addr] = err
+		}
+	}
+	return peerErrs
+}
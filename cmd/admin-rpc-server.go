@@ -48,6 +48,19 @@ type ListLocksReply struct {
 	volLocks []VolumeLockInfo
 }
 
+// TraceArgs - wraps Trace query values to send over RPC.
+type TraceArgs struct {
+	AuthRPCArgs
+	Filter   traceFilter
+	Duration time.Duration
+}
+
+// TraceReply - wraps the requests traced by a server over RPC.
+type TraceReply struct {
+	AuthRPCReply
+	Entries []requestTrace
+}
+
 // Restart - Restart this instance of minio server.
 func (s *adminCmd) Restart(args *AuthRPCArgs, reply *AuthRPCReply) error {
 	if err := args.IsAuthenticated(); err != nil {
@@ -68,6 +81,29 @@ func (s *adminCmd) ListLocks(query *ListLocksQuery, reply *ListLocksReply) error
 	return nil
 }
 
+// Trace - returns the requests served by this server instance while
+// the call lasts.
+func (s *adminCmd) Trace(args *TraceArgs, reply *TraceReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+	duration := args.Duration
+	if duration > maxTracePollDuration {
+		duration = maxTracePollDuration
+	}
+	*reply = TraceReply{Entries: collectTrace(args.Filter, duration, maxTracePollEntries)}
+	return nil
+}
+
+// ReloadLogger - replaces the loggers of this server instance with the
+// loggers of its config file.
+func (s *adminCmd) ReloadLogger(args *AuthRPCArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+	return reloadLoggers()
+}
+
 // ReInitDisk - reinitialize storage disks and object layer to use the
 // new format.
 func (s *adminCmd) ReInitDisks(args *AuthRPCArgs, reply *AuthRPCReply) error {
@@ -115,6 +151,6 @@ func registerAdminRPCRouter(mux *router.Router) error {
 		return traceError(err)
 	}
 	adminRouter := mux.NewRoute().PathPrefix(reservedBucket).Subrouter()
-	adminRouter.Path(adminPath).Handler(adminRPCServer)
+	adminRouter.Path(adminPath).Handler(newRPCHandler(adminRPCServer))
 	return nil
 }
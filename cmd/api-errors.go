@@ -111,6 +111,77 @@ const (
 	ErrInvalidQueryParams
 	ErrBucketAlreadyOwnedByYou
 	ErrInvalidDuration
+	ErrNoSuchVersion
+	ErrInvalidVersionID
+	ErrNoSuchLifecycleConfiguration
+	ErrLifecycleInvalidRuleID
+	ErrLifecycleDuplicateRuleID
+	ErrLifecycleInvalidDays
+	ErrLifecycleInvalidDate
+	ErrNoSuchCORSConfiguration
+	ErrCorsInvalidMethod
+	ErrCorsInvalidOrigin
+	ErrCorsInvalidHeader
+	ErrCorsForbidden
+	ErrTooManyTags
+	ErrInvalidTagKey
+	ErrInvalidTagValue
+	ErrDuplicateTagKey
+	ErrInvalidTaggingDirective
+	ErrNoSuchTagSet
+	ErrInsecureSSECustomerRequest
+	ErrInvalidSSECustomerAlgorithm
+	ErrMissingSSECustomerKey
+	ErrMissingSSECustomerKeyMD5
+	ErrInvalidSSECustomerKey
+	ErrSSECustomerKeyMD5Mismatch
+	ErrSSEEncryptedObject
+	ErrSSEKeyMismatch
+	ErrInvalidEncryptionParameters
+	ErrObjectTampered
+	ErrInvalidEncryptionMethod
+	ErrKMSNotConfigured
+	ErrKMSKeyNotFound
+	ErrNoSuchBucketEncryption
+	ErrInvalidExpressionType
+	ErrInvalidCompressionFormat
+	ErrInvalidRequestParameter
+	ErrMissingRequiredParameter
+	ErrParseUnexpectedToken
+	ErrParseUnsupportedSyntax
+	ErrInvalidCopyPartRange
+	ErrInvalidCopyPartRangeSource
+	ErrObjectLocked
+	ErrObjectLockConfigurationNotFound
+	ErrObjectLockCannotBeEnabled
+	ErrInvalidBucketObjectLockConfiguration
+	ErrNoSuchObjectLockConfiguration
+	ErrObjectLockInvalidHeaders
+	ErrInvalidObjectLockMode
+	ErrInvalidObjectLockLegalHold
+	ErrInvalidRetainUntilDate
+	ErrPastObjectLockRetainDate
+	ErrNoSuchWebsiteConfiguration
+	ErrWebsiteInvalidIndexDocument
+	ErrWebsiteInvalidRedirect
+	ErrWebsiteInvalidRoutingRule
+	ErrInvalidTargetBucketForLogging
+	ErrReplicationConfigurationNotFound
+	ErrReplicationInvalidRule
+	ErrReplicationInvalidDestination
+	ErrInvalidToken
+	ErrExpiredToken
+	ErrSTSInvalidParameterValue
+	ErrSTSMalformedPolicyDocument
+	ErrSTSAssumeRoleNotAllowed
+	ErrSTSLDAPNotConfigured
+	ErrSTSLDAPAuthentication
+	ErrSTSOpenIDNotConfigured
+	ErrSTSInvalidIdentityToken
+	ErrInvalidCannedACL
+	ErrMalformedACLError
+	ErrUnsupportedACL
+	ErrInvalidStorageClass
 	// Add new error codes here.
 
 	// Bucket notification related errors.
@@ -143,6 +214,11 @@ const (
 
 	ErrAdminInvalidAccessKey
 	ErrAdminInvalidSecretKey
+	ErrAdminNoSuchUser
+	ErrAdminNoSuchPolicy
+	ErrAdminMalformedPolicy
+	ErrAdminInvalidArgument
+	ErrAdminInvalidLogger
 )
 
 // error code to APIError structure, these fields carry respective
@@ -486,6 +562,361 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Relative duration provided in the request is invalid.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrNoSuchVersion: {
+		Code:           "NoSuchVersion",
+		Description:    "The specified version does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidVersionID: {
+		Code:           "InvalidArgument",
+		Description:    "Invalid version id specified",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchLifecycleConfiguration: {
+		Code:           "NoSuchLifecycleConfiguration",
+		Description:    "The lifecycle configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrLifecycleInvalidRuleID: {
+		Code:           "InvalidArgument",
+		Description:    "ID length should not exceed allowed limit of 255",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrLifecycleDuplicateRuleID: {
+		Code:           "InvalidArgument",
+		Description:    "Rule ID must be unique. Found same ID for more than one rule",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrLifecycleInvalidDays: {
+		Code:           "InvalidArgument",
+		Description:    "'Days' in a lifecycle action must be a positive integer",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrLifecycleInvalidDate: {
+		Code:           "InvalidArgument",
+		Description:    "'Date' must be at midnight GMT",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchCORSConfiguration: {
+		Code:           "NoSuchCORSConfiguration",
+		Description:    "The CORS configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrCorsInvalidMethod: {
+		Code:           "InvalidRequest",
+		Description:    "Found unsupported HTTP method in CORS config.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrCorsInvalidOrigin: {
+		Code:           "InvalidRequest",
+		Description:    "AllowedOrigin can not have more than one wildcard.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrCorsInvalidHeader: {
+		Code:           "InvalidRequest",
+		Description:    "AllowedHeader can not have more than one wildcard.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrCorsForbidden: {
+		Code:           "AccessForbidden",
+		Description:    "CORSResponse: This CORS request is not allowed. This is usually because the evalution of Origin, request method / Access-Control-Request-Method or Access-Control-Request-Headers are not whitelisted by the resource's CORS spec.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrTooManyTags: {
+		Code:           "InvalidTag",
+		Description:    "The number of tags exceeds the limit",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTagKey: {
+		Code:           "InvalidTag",
+		Description:    "The TagKey you have provided is invalid",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTagValue: {
+		Code:           "InvalidTag",
+		Description:    "The TagValue you have provided is invalid",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrDuplicateTagKey: {
+		Code:           "InvalidTag",
+		Description:    "Cannot provide multiple Tags with the same key",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTaggingDirective: {
+		Code:           "InvalidArgument",
+		Description:    "Unknown tagging directive.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchTagSet: {
+		Code:           "NoSuchTagSet",
+		Description:    "The TagSet does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInsecureSSECustomerRequest: {
+		Code:           "InvalidRequest",
+		Description:    "Requests specifying Server Side Encryption with Customer provided keys must be made over a secure connection.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidSSECustomerAlgorithm: {
+		Code:           "InvalidArgument",
+		Description:    "Requests specifying Server Side Encryption with Customer provided keys must provide a valid encryption algorithm.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMissingSSECustomerKey: {
+		Code:           "InvalidArgument",
+		Description:    "Requests specifying Server Side Encryption with Customer provided keys must provide an appropriate secret key.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMissingSSECustomerKeyMD5: {
+		Code:           "InvalidArgument",
+		Description:    "Requests specifying Server Side Encryption with Customer provided keys must provide the client calculated MD5 of the secret key.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidSSECustomerKey: {
+		Code:           "InvalidArgument",
+		Description:    "The secret key was invalid for the specified algorithm.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSSECustomerKeyMD5Mismatch: {
+		Code:           "InvalidArgument",
+		Description:    "The calculated MD5 hash of the key did not match the hash that was provided.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSSEEncryptedObject: {
+		Code:           "InvalidRequest",
+		Description:    "The object was stored using a form of Server Side Encryption. The correct parameters must be provided to retrieve the object.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSSEKeyMismatch: {
+		Code:           "AccessDenied",
+		Description:    "The provided encryption parameters did not match the ones used originally.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrInvalidEncryptionParameters: {
+		Code:           "InvalidRequest",
+		Description:    "The encryption parameters are not applicable to this object.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrObjectTampered: {
+		Code:           "XMinioObjectTampered",
+		Description:    "The requested object was modified and may be compromised.",
+		HTTPStatusCode: http.StatusPreconditionFailed,
+	},
+	ErrInvalidEncryptionMethod: {
+		Code:           "InvalidArgument",
+		Description:    "The encryption method specified is not supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrKMSNotConfigured: {
+		Code:           "XMinioKMSNotConfigured",
+		Description:    "Server side encryption requires a key management service, none is configured.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrKMSKeyNotFound: {
+		Code:           "KMS.NotFoundException",
+		Description:    "The specified KMS master key does not exist.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchBucketEncryption: {
+		Code:           "ServerSideEncryptionConfigurationNotFoundError",
+		Description:    "The server side encryption configuration was not found.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidExpressionType: {
+		Code:           "InvalidExpressionType",
+		Description:    "The ExpressionType is invalid. Only SQL expressions are supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidCompressionFormat: {
+		Code:           "InvalidCompressionFormat",
+		Description:    "The file is not in a supported compression format. Only GZIP is supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidRequestParameter: {
+		Code:           "InvalidRequestParameter",
+		Description:    "The value of a parameter in SelectRequest element is invalid. Check the service API documentation and try again.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMissingRequiredParameter: {
+		Code:           "MissingRequiredParameter",
+		Description:    "The SelectRequest entity is missing a required parameter. Check the service documentation and try again.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrParseUnexpectedToken: {
+		Code:           "ParseUnexpectedToken",
+		Description:    "The SQL expression contains an unexpected token.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrParseUnsupportedSyntax: {
+		Code:           "ParseUnsupportedSyntax",
+		Description:    "The SQL expression contains unsupported syntax.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidCopyPartRange: {
+		Code:           "InvalidArgument",
+		Description:    "The x-amz-copy-source-range value must be of the form bytes=first-last where first and last are the zero-based offsets of the first and last bytes to copy",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidCopyPartRangeSource: {
+		Code:           "InvalidArgument",
+		Description:    "Range specified is not valid for source object",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrObjectLocked: {
+		Code:           "AccessDenied",
+		Description:    "Access Denied because object protected by object lock.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrObjectLockConfigurationNotFound: {
+		Code:           "ObjectLockConfigurationNotFoundError",
+		Description:    "Object Lock configuration does not exist for this bucket",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrObjectLockCannotBeEnabled: {
+		Code:           "InvalidBucketState",
+		Description:    "Object Lock configuration cannot be enabled on existing buckets",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrInvalidBucketObjectLockConfiguration: {
+		Code:           "InvalidRequest",
+		Description:    "Bucket is missing ObjectLockConfiguration",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchObjectLockConfiguration: {
+		Code:           "NoSuchObjectLockConfiguration",
+		Description:    "The specified object does not have a ObjectLock configuration",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrObjectLockInvalidHeaders: {
+		Code:           "InvalidRequest",
+		Description:    "x-amz-object-lock-retain-until-date and x-amz-object-lock-mode must both be supplied",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidObjectLockMode: {
+		Code:           "InvalidArgument",
+		Description:    "Unknown object lock mode, the mode has to be GOVERNANCE or COMPLIANCE",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidObjectLockLegalHold: {
+		Code:           "InvalidArgument",
+		Description:    "Legal Hold must be either of 'ON' or 'OFF'",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidRetainUntilDate: {
+		Code:           "InvalidArgument",
+		Description:    "The retain until date must be provided in ISO 8601 format",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrPastObjectLockRetainDate: {
+		Code:           "InvalidArgument",
+		Description:    "The retain until date must be in the future",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchWebsiteConfiguration: {
+		Code:           "NoSuchWebsiteConfiguration",
+		Description:    "The specified bucket does not have a website configuration",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrWebsiteInvalidIndexDocument: {
+		Code:           "InvalidArgument",
+		Description:    "The IndexDocument Suffix is not well formed",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrWebsiteInvalidRedirect: {
+		Code:           "InvalidArgument",
+		Description:    "The website redirect is not valid",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrWebsiteInvalidRoutingRule: {
+		Code:           "InvalidArgument",
+		Description:    "The website routing rules are not valid",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTargetBucketForLogging: {
+		Code:           "InvalidTargetBucketForLogging",
+		Description:    "The target bucket for logging does not exist",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrReplicationConfigurationNotFound: {
+		Code:           "ReplicationConfigurationNotFoundError",
+		Description:    "The replication configuration was not found",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrReplicationInvalidRule: {
+		Code:           "InvalidArgument",
+		Description:    "The replication rules are not valid",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrReplicationInvalidDestination: {
+		Code:           "InvalidArgument",
+		Description:    "The replication destination is not valid",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidToken: {
+		Code:           "InvalidToken",
+		Description:    "The provided token is malformed or otherwise invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrExpiredToken: {
+		Code:           "ExpiredToken",
+		Description:    "The provided token has expired.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSTSInvalidParameterValue: {
+		Code:           "InvalidParameterValue",
+		Description:    "An invalid or out-of-range value was supplied for the input parameter.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSTSMalformedPolicyDocument: {
+		Code:           "MalformedPolicyDocument",
+		Description:    "The policy document is malformed.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSTSAssumeRoleNotAllowed: {
+		Code:           "AccessDenied",
+		Description:    "Temporary credentials cannot be used to assume a role.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrSTSLDAPNotConfigured: {
+		Code:           "InvalidParameterValue",
+		Description:    "LDAP authentication is not configured on the server.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSTSLDAPAuthentication: {
+		Code:           "AccessDenied",
+		Description:    "The LDAP username or password is not valid.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrSTSOpenIDNotConfigured: {
+		Code:           "InvalidParameterValue",
+		Description:    "OpenID Connect authentication is not configured on the server.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSTSInvalidIdentityToken: {
+		Code:           "InvalidIdentityToken",
+		Description:    "The web identity token that was passed could not be validated.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidCannedACL: {
+		Code:           "InvalidArgument",
+		Description:    "The canned ACL is not valid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMalformedACLError: {
+		Code:           "MalformedACLError",
+		Description:    "The XML you provided was not well-formed or did not validate against our published schema.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrUnsupportedACL: {
+		Code:           "NotImplemented",
+		Description:    "Only the canned ACLs private, public-read, public-read-write and authenticated-read are supported.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrInvalidStorageClass: {
+		Code:           "InvalidStorageClass",
+		Description:    "The storage class you specified is not valid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 
 	/// Bucket notification related errors.
 	ErrEventNotification: {
@@ -587,6 +1018,31 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "The secret key is invalid.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrAdminNoSuchUser: {
+		Code:           "XMinioAdminNoSuchUser",
+		Description:    "The specified user does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminNoSuchPolicy: {
+		Code:           "XMinioAdminNoSuchPolicy",
+		Description:    "The specified policy does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminMalformedPolicy: {
+		Code:           "XMinioAdminMalformedPolicy",
+		Description:    "The policy document is malformed or uses unsupported actions.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidArgument: {
+		Code:           "XMinioAdminInvalidArgument",
+		Description:    "Invalid arguments specified.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidLogger: {
+		Code:           "XMinioAdminInvalidLogger",
+		Description:    "The logger configuration of the config file is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 
 	// Add your error structure here.
 }
@@ -606,6 +1062,34 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrSignatureDoesNotMatch
 	case errContentSHA256Mismatch:
 		apiErr = ErrContentSHA256Mismatch
+	case errSSEKeyMismatch:
+		apiErr = ErrSSEKeyMismatch
+	case errObjectTampered:
+		apiErr = ErrObjectTampered
+	case errKMSNotConfigured:
+		apiErr = ErrKMSNotConfigured
+	case errKMSKeyNotFound:
+		apiErr = ErrKMSKeyNotFound
+	case errNoSuchBucketEncryption:
+		apiErr = ErrNoSuchBucketEncryption
+	case errNoSuchObjectLockConfig:
+		apiErr = ErrObjectLockConfigurationNotFound
+	case errNoSuchUser:
+		apiErr = ErrAdminNoSuchUser
+	case errNoSuchIAMPolicy:
+		apiErr = ErrAdminNoSuchPolicy
+	case errSTSAssumeRoleNotAllowed:
+		apiErr = ErrSTSAssumeRoleNotAllowed
+	case errLDAPNotConfigured:
+		apiErr = ErrSTSLDAPNotConfigured
+	case errLDAPAuthentication:
+		apiErr = ErrSTSLDAPAuthentication
+	case errOpenIDNotConfigured:
+		apiErr = ErrSTSOpenIDNotConfigured
+	case errOpenIDInvalidToken:
+		apiErr = ErrSTSInvalidIdentityToken
+	case errOpenIDExpiredToken:
+		apiErr = ErrExpiredToken
 	}
 
 	if apiErr != ErrNone {
@@ -634,6 +1118,10 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrBucketAlreadyOwnedByYou
 	case ObjectNotFound:
 		apiErr = ErrNoSuchKey
+	case VersionNotFound:
+		apiErr = ErrNoSuchVersion
+	case ObjectLocked:
+		apiErr = ErrObjectLocked
 	case ObjectNameInvalid:
 		apiErr = ErrInvalidObjectName
 	case InvalidUploadID:
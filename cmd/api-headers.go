@@ -22,19 +22,38 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
+// Last request ID generated.
+var lastRequestID int64
+
 // Returns a hexadecimal representation of time at the
-// time response is sent to the client.
+// time response is sent to the client. IDs are unique,
+// a request arriving within the same nanosecond as the
+// previous one gets the next nanosecond.
 func mustGetRequestID(t time.Time) string {
-	return fmt.Sprintf("%X", t.UnixNano())
+	id := t.UnixNano()
+	for {
+		last := atomic.LoadInt64(&lastRequestID)
+		if id <= last {
+			id = last + 1
+		}
+		if atomic.CompareAndSwapInt64(&lastRequestID, last, id) {
+			return fmt.Sprintf("%X", id)
+		}
+	}
 }
 
 // Write http common headers
 func setCommonHeaders(w http.ResponseWriter) {
-	// Set unique request ID for each reply.
-	w.Header().Set(responseRequestIDKey, mustGetRequestID(time.Now().UTC()))
+	// Set unique request ID for each reply, requests are
+	// assigned one as they arrive.
+	if w.Header().Get(responseRequestIDKey) == "" {
+		w.Header().Set(responseRequestIDKey, mustGetRequestID(time.Now().UTC()))
+	}
 	w.Header().Set("Server", globalServerUserAgent)
 	w.Header().Set("Accept-Ranges", "bytes")
 }
@@ -65,11 +84,23 @@ func setObjectHeaders(w http.ResponseWriter, objInfo ObjectInfo, contentRange *h
 		w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
 	}
 
-	// Set all other user defined metadata.
+	// Set all other user defined metadata, reserved entries stay on the
+	// server.
 	for k, v := range objInfo.UserDefined {
+		if strings.HasPrefix(k, reservedMetadataPrefix) {
+			continue
+		}
 		w.Header().Set(k, v)
 	}
 
+	// Set number of tags if the object is tagged.
+	if objInfo.UserTags != "" {
+		w.Header().Set("x-amz-tagging-count", strconv.Itoa(objectTagsCount(objInfo.UserTags)))
+	}
+
+	// Set version id if available.
+	setVersionHeaders(w, objInfo)
+
 	// for providing ranged content
 	if contentRange != nil && contentRange.offsetBegin > -1 {
 		// Override content-length
@@ -78,3 +109,13 @@ func setObjectHeaders(w http.ResponseWriter, objInfo ObjectInfo, contentRange *h
 		w.WriteHeader(http.StatusPartialContent)
 	}
 }
+
+// Write version id and delete marker headers of an object version.
+func setVersionHeaders(w http.ResponseWriter, objInfo ObjectInfo) {
+	if objInfo.VersionID != "" {
+		w.Header().Set("x-amz-version-id", objInfo.VersionID)
+	}
+	if objInfo.DeleteMarker {
+		w.Header().Set("x-amz-delete-marker", "true")
+	}
+}
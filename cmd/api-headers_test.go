@@ -39,3 +39,16 @@ func TestNewRequestID(t *testing.T) {
 		}
 	}
 }
+
+// Tests that requests arriving at the same time get unique IDs.
+func TestNewRequestIDUnique(t *testing.T) {
+	now := time.Now().UTC()
+	ids := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := mustGetRequestID(now)
+		if ids[id] {
+			t.Fatalf("Request ID %s returned twice", id)
+		}
+		ids[id] = true
+	}
+}
@@ -99,6 +99,34 @@ type ListObjectsV2Response struct {
 	EncodingType string `xml:"EncodingType,omitempty"`
 }
 
+// ListVersionsResponse - format for list object versions response.
+type ListVersionsResponse struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListVersionsResult" json:"-"`
+
+	Name            string
+	Prefix          string
+	KeyMarker       string
+	VersionIDMarker string `xml:"VersionIdMarker"`
+
+	// When response is truncated, the key and version id to continue
+	// listing from in the subsequent request.
+	NextKeyMarker       string `xml:"NextKeyMarker,omitempty"`
+	NextVersionIDMarker string `xml:"NextVersionIdMarker,omitempty"`
+
+	MaxKeys   int
+	Delimiter string
+	// A flag that indicates whether or not ListObjectVersions returned all
+	// of the results that satisfied the search criteria.
+	IsTruncated bool
+
+	// Object versions and delete markers, in listing order.
+	Versions       []interface{}
+	CommonPrefixes []CommonPrefix
+
+	// Encoding type used to encode object keys in the response.
+	EncodingType string `xml:"EncodingType,omitempty"`
+}
+
 // Part container for part metadata.
 type Part struct {
 	PartNumber   int
@@ -201,6 +229,35 @@ type Object struct {
 	HealObjectInfo *HealObjectInfo `xml:"HealObjectInfo,omitempty"`
 }
 
+// ObjectVersion container for object version metadata
+type ObjectVersion struct {
+	XMLName      xml.Name `xml:"Version" json:"-"`
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool
+	LastModified string // time string of format "2006-01-02T15:04:05.000Z"
+	ETag         string
+	Size         int64
+
+	// Owner of the object.
+	Owner Owner
+
+	// The class of storage used to store the object.
+	StorageClass string
+}
+
+// DeleteMarkerVersion container for delete marker metadata
+type DeleteMarkerVersion struct {
+	XMLName      xml.Name `xml:"DeleteMarker" json:"-"`
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool
+	LastModified string // time string of format "2006-01-02T15:04:05.000Z"
+
+	// Owner of the delete marker.
+	Owner Owner
+}
+
 // CopyObjectResponse container returns ETag and LastModified of the successfully copied object
 type CopyObjectResponse struct {
 	XMLName      xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyObjectResult" json:"-"`
@@ -208,6 +265,13 @@ type CopyObjectResponse struct {
 	ETag         string   // md5sum of the copied object.
 }
 
+// CopyObjectPartResponse container returns ETag and LastModified of the successfully copied object part
+type CopyObjectPartResponse struct {
+	XMLName      xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyPartResult" json:"-"`
+	LastModified string   // time string of format "2006-01-02T15:04:05.000Z"
+	ETag         string   // md5sum of the copied object part.
+}
+
 // Initiator inherit from Owner struct, fields are same
 type Initiator Owner
 
@@ -317,7 +381,7 @@ func generateListObjectsV1Response(bucket, prefix, marker, delimiter string, max
 			content.ETag = "\"" + object.MD5Sum + "\""
 		}
 		content.Size = object.Size
-		content.StorageClass = globalMinioDefaultStorageClass
+		content.StorageClass = getStorageClass(object.UserDefined)
 		content.Owner = owner
 		// object.HealObjectInfo is non-empty only when resp is constructed in ListObjectsHeal.
 		content.HealObjectInfo = object.HealObjectInfo
@@ -343,6 +407,65 @@ func generateListObjectsV1Response(bucket, prefix, marker, delimiter string, max
 	return data
 }
 
+// generates a ListObjectVersions response for the said bucket with other enumerated options.
+func generateListVersionsResponse(bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int, resp ListObjectVersionsInfo) ListVersionsResponse {
+	var versions []interface{}
+	var prefixes []CommonPrefix
+	var owner = Owner{}
+	var data = ListVersionsResponse{}
+
+	owner.ID = globalMinioDefaultOwnerID
+	owner.DisplayName = globalMinioDefaultOwnerID
+
+	for _, object := range resp.Objects {
+		if object.Name == "" {
+			continue
+		}
+		lastModified := object.ModTime.UTC().Format(timeFormatAMZLong)
+		if object.DeleteMarker {
+			versions = append(versions, DeleteMarkerVersion{
+				Key:          object.Name,
+				VersionID:    versionIDOrNull(object.VersionID),
+				IsLatest:     object.IsLatest,
+				LastModified: lastModified,
+				Owner:        owner,
+			})
+			continue
+		}
+		var content = ObjectVersion{}
+		content.Key = object.Name
+		content.VersionID = versionIDOrNull(object.VersionID)
+		content.IsLatest = object.IsLatest
+		content.LastModified = lastModified
+		if object.MD5Sum != "" {
+			content.ETag = "\"" + object.MD5Sum + "\""
+		}
+		content.Size = object.Size
+		content.StorageClass = getStorageClass(object.UserDefined)
+		content.Owner = owner
+		versions = append(versions, content)
+	}
+	data.Name = bucket
+	data.Versions = versions
+
+	data.Prefix = prefix
+	data.KeyMarker = keyMarker
+	data.VersionIDMarker = versionIDMarker
+	data.Delimiter = delimiter
+	data.MaxKeys = maxKeys
+
+	data.NextKeyMarker = resp.NextKeyMarker
+	data.NextVersionIDMarker = resp.NextVersionIDMarker
+	data.IsTruncated = resp.IsTruncated
+	for _, prefix := range resp.Prefixes {
+		var prefixItem = CommonPrefix{}
+		prefixItem.Prefix = prefix
+		prefixes = append(prefixes, prefixItem)
+	}
+	data.CommonPrefixes = prefixes
+	return data
+}
+
 // generates an ListObjectsV2 response for the said bucket with other enumerated options.
 func generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter string, fetchOwner bool, maxKeys int, resp ListObjectsInfo) ListObjectsV2Response {
 	var contents []Object
@@ -366,7 +489,7 @@ func generateListObjectsV2Response(bucket, prefix, token, startAfter, delimiter
 			content.ETag = "\"" + object.MD5Sum + "\""
 		}
 		content.Size = object.Size
-		content.StorageClass = globalMinioDefaultStorageClass
+		content.StorageClass = getStorageClass(object.UserDefined)
 		content.Owner = owner
 		contents = append(contents, content)
 	}
@@ -399,6 +522,14 @@ func generateCopyObjectResponse(etag string, lastModified time.Time) CopyObjectR
 	}
 }
 
+// generates CopyObjectPartResponse from etag and lastModified time.
+func generateCopyObjectPartResponse(etag string, lastModified time.Time) CopyObjectPartResponse {
+	return CopyObjectPartResponse{
+		ETag:         "\"" + etag + "\"",
+		LastModified: lastModified.UTC().Format(timeFormatAMZLong),
+	}
+}
+
 // generates InitiateMultipartUploadResponse for given bucket, key and uploadID.
 func generateInitiateMultipartUploadResponse(bucket, key, uploadID string) InitiateMultipartUploadResponse {
 	return InitiateMultipartUploadResponse{
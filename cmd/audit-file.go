@@ -0,0 +1,56 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "os"
+
+// auditFile - audit log file target configuration, records are
+// appended to the file one per line.
+type auditFile struct {
+	Enable   bool   `json:"enable"`
+	Filename string `json:"filename"`
+}
+
+// auditFileTarget - appends audit records to a file.
+type auditFileTarget struct {
+	file *os.File
+}
+
+func newAuditFileTarget(cfg auditFile) (*auditFileTarget, error) {
+	if cfg.Filename == "" {
+		return nil, errInvalidArgument
+	}
+	file, err := os.OpenFile(cfg.Filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &auditFileTarget{file: file}, nil
+}
+
+// Send appends a record as a line of its own.
+func (t *auditFileTarget) Send(record []byte) error {
+	// Records are shared between targets, copy before appending.
+	line := make([]byte, len(record)+1)
+	copy(line, record)
+	line[len(record)] = '\n'
+	_, err := t.file.Write(line)
+	return err
+}
+
+func (t *auditFileTarget) Close() error {
+	return t.file.Close()
+}
@@ -0,0 +1,108 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// auditWebhook - audit log webhook target configuration, records are
+// posted to the endpoint one at a time.
+type auditWebhook struct {
+	Enable    bool   `json:"enable"`
+	Endpoint  string `json:"endpoint"`
+	AuthToken string `json:"authToken,omitempty"`
+}
+
+// auditWebhookTarget - posts audit records to a webhook.
+type auditWebhookTarget struct {
+	client    *http.Client
+	endpoint  string
+	authToken string
+}
+
+func newAuditWebhookTarget(cfg auditWebhook) (*auditWebhookTarget, error) {
+	if cfg.Endpoint == "" {
+		return nil, errInvalidArgument
+	}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errInvalidArgument
+	}
+
+	return &auditWebhookTarget{
+		// Configure aggressive timeouts for client posts.
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 5 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout:   3 * time.Second,
+				ResponseHeaderTimeout: 3 * time.Second,
+				ExpectContinueTimeout: 2 * time.Second,
+			},
+		},
+		endpoint:  cfg.Endpoint,
+		authToken: cfg.AuthToken,
+	}, nil
+}
+
+// Send posts a record, any status other than 2xx fails.
+func (t *auditWebhookTarget) Send(record []byte) error {
+	req, err := http.NewRequest("POST", t.endpoint, bytes.NewReader(record))
+	if err != nil {
+		return err
+	}
+
+	// Set content-type.
+	req.Header.Set("Content-Type", "application/json")
+
+	// Set proper server user-agent.
+	req.Header.Set("User-Agent", globalServerUserAgent)
+
+	if t.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+t.authToken)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	// Drain the body so that the connection is reused.
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Unable to send audit record %s", resp.Status)
+	}
+	return nil
+}
+
+func (t *auditWebhookTarget) Close() error {
+	return nil
+}
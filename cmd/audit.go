@@ -0,0 +1,333 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Every S3, browser and admin request is recorded once served. The
+// records are delivered to all enabled targets in the same order,
+// each record carries the SHA-256 of the record before it so that
+// records removed or altered afterwards break the chain.
+
+// Version of the audit record format.
+const auditRecordVersion = "1"
+
+// Maximum number of records buffered per target, requests wait for a
+// slot once a target falls this far behind.
+const auditQueueSize = 10000
+
+// Records failing to be delivered are retried after auditRetryInterval,
+// doubled on every failure up to maxAuditRetryInterval. Targets are
+// given auditCloseTimeout to deliver the records queued at shutdown.
+var (
+	auditRetryInterval    = time.Second
+	maxAuditRetryInterval = time.Minute
+	auditCloseTimeout     = 10 * time.Second
+)
+
+// audit - audit log targets configuration.
+type audit struct {
+	Webhook map[string]auditWebhook `json:"webhook"`
+	File    map[string]auditFile    `json:"file"`
+}
+
+// auditRecord - an audited request.
+type auditRecord struct {
+	Version         string        `json:"version"`
+	Time            time.Time     `json:"time"`
+	Node            string        `json:"node"`
+	RequestID       string        `json:"requestID"`
+	Type            string        `json:"type"`
+	AccessKey       string        `json:"accessKey,omitempty"`
+	SourceIP        string        `json:"sourceIP"`
+	API             string        `json:"api"`
+	Bucket          string        `json:"bucket,omitempty"`
+	Object          string        `json:"object,omitempty"`
+	Method          string        `json:"method"`
+	Path            string        `json:"path"`
+	Query           string        `json:"query,omitempty"`
+	RequestHeader   http.Header   `json:"requestHeader,omitempty"`
+	ResponseHeader  http.Header   `json:"responseHeader,omitempty"`
+	StatusCode      int           `json:"statusCode"`
+	TimeToFirstByte time.Duration `json:"timeToFirstByte"`
+	Duration        time.Duration `json:"duration"`
+	PrevHash        string        `json:"prevHash"`
+}
+
+// auditTarget - delivers audit records, one JSON document each.
+type auditTarget interface {
+	// Send delivers a record, failed records are sent again.
+	Send(record []byte) error
+	Close() error
+}
+
+// auditQueue - buffers the records of a target and delivers them in
+// order, retrying failed records.
+type auditQueue struct {
+	name     string
+	target   auditTarget
+	recordCh chan []byte
+	doneCh   chan struct{}
+	wg       *sync.WaitGroup
+}
+
+func newAuditQueue(name string, target auditTarget) *auditQueue {
+	q := &auditQueue{
+		name:     name,
+		target:   target,
+		recordCh: make(chan []byte, auditQueueSize),
+		doneCh:   make(chan struct{}),
+		wg:       &sync.WaitGroup{},
+	}
+	q.wg.Add(1)
+	go q.run()
+	return q
+}
+
+func (q *auditQueue) run() {
+	defer q.wg.Done()
+	for record := range q.recordCh {
+		interval := auditRetryInterval
+		for {
+			err := q.target.Send(record)
+			if err == nil {
+				break
+			}
+			errorIf(err, "Unable to send audit record to %s.", q.name)
+			select {
+			case <-time.After(interval):
+			case <-q.doneCh:
+				return
+			}
+			interval *= 2
+			if interval > maxAuditRetryInterval {
+				interval = maxAuditRetryInterval
+			}
+		}
+	}
+}
+
+// Close delivers the buffered records, records not delivered within
+// auditCloseTimeout are dropped.
+func (q *auditQueue) Close() error {
+	close(q.recordCh)
+
+	waitCh := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(waitCh)
+	}()
+	select {
+	case <-waitCh:
+	case <-time.After(auditCloseTimeout):
+		close(q.doneCh)
+		<-waitCh
+	}
+	return q.target.Close()
+}
+
+// auditLogger - chains audit records and queues them for all targets.
+type auditLogger struct {
+	mutex    *sync.Mutex
+	queues   []*auditQueue
+	prevHash string
+	closed   bool
+}
+
+// Variable represents the audit targets, set up once at startup.
+var globalAuditLogger = newAuditLogger(nil)
+
+func newAuditLogger(queues []*auditQueue) *auditLogger {
+	return &auditLogger{
+		mutex:  &sync.Mutex{},
+		queues: queues,
+	}
+}
+
+// IsEnabled checks if any audit target is enabled.
+func (l *auditLogger) IsEnabled() bool {
+	return len(l.queues) > 0
+}
+
+// Log chains a record to the records logged before it and queues it
+// for all targets, it waits while any target queue is full.
+func (l *auditLogger) Log(record auditRecord) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.closed {
+		return
+	}
+
+	record.PrevHash = l.prevHash
+	data, err := json.Marshal(record)
+	if err != nil {
+		errorIf(err, "Unable to marshal audit record.")
+		return
+	}
+	sum := sha256.Sum256(data)
+	l.prevHash = hex.EncodeToString(sum[:])
+
+	for _, q := range l.queues {
+		q.recordCh <- data
+	}
+}
+
+// Close delivers the queued records and closes all targets, records
+// logged afterwards are dropped.
+func (l *auditLogger) Close() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.closed {
+		return
+	}
+	l.closed = true
+	for _, q := range l.queues {
+		errorIf(q.Close(), "Unable to close audit target %s.", q.name)
+	}
+}
+
+// initAudit - sets up the enabled audit targets of the server config.
+func initAudit() {
+	cfg := serverConfig.GetAudit()
+
+	var queues []*auditQueue
+	for id, webhook := range cfg.Webhook {
+		if !webhook.Enable {
+			continue
+		}
+		target, err := newAuditWebhookTarget(webhook)
+		fatalIf(err, "Unable to initialize audit webhook %s.", id)
+		queues = append(queues, newAuditQueue("webhook "+id, target))
+	}
+	for id, file := range cfg.File {
+		if !file.Enable {
+			continue
+		}
+		target, err := newAuditFileTarget(file)
+		fatalIf(err, "Unable to initialize audit file %s.", id)
+		queues = append(queues, newAuditQueue("file "+id, target))
+	}
+	globalAuditLogger = newAuditLogger(queues)
+}
+
+// auditResponseWriter - records the status of a response and when its
+// first byte was written.
+type auditResponseWriter struct {
+	http.ResponseWriter
+
+	status    int
+	firstByte time.Time
+}
+
+func (aw *auditResponseWriter) WriteHeader(status int) {
+	if aw.status == 0 {
+		aw.status = status
+		aw.firstByte = time.Now().UTC()
+	}
+	aw.ResponseWriter.WriteHeader(status)
+}
+
+func (aw *auditResponseWriter) Write(p []byte) (int, error) {
+	if aw.status == 0 {
+		aw.status = http.StatusOK
+		aw.firstByte = time.Now().UTC()
+	}
+	return aw.ResponseWriter.Write(p)
+}
+
+// Flush - handlers flush responses while writing them.
+func (aw *auditResponseWriter) Flush() {
+	if f, ok := aw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Returns the access key a request is signed with, browser requests
+// carry it in their token.
+func getAuditAccessKey(r *http.Request) string {
+	if accessKey := getRequestAccessKey(r); accessKey != "" {
+		return accessKey
+	}
+	if accessKey, err := webRequestSubject(r); err == nil {
+		return accessKey
+	}
+	return ""
+}
+
+// auditHandler - records S3, admin and browser requests to the audit
+// targets. Health checks are not recorded, neither are RPC
+// connections.
+type auditHandler struct {
+	handler http.Handler
+}
+
+func setAuditHandler(h http.Handler) http.Handler {
+	return auditHandler{handler: h}
+}
+
+func (h auditHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !globalAuditLogger.IsEnabled() || r.Method == "CONNECT" ||
+		strings.HasPrefix(r.URL.Path, reservedBucket+"/health/") {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	record := auditRecord{
+		Version:       auditRecordVersion,
+		Time:          time.Now().UTC(),
+		Node:          globalMinioAddr,
+		Type:          getRequestType(r),
+		AccessKey:     getAuditAccessKey(r),
+		SourceIP:      r.RemoteAddr,
+		Method:        r.Method,
+		Path:          r.URL.Path,
+		Query:         redactQuery(r.URL.Query()),
+		RequestHeader: redactHeader(r.Header),
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		record.SourceIP = host
+	}
+
+	aw := &auditResponseWriter{ResponseWriter: w}
+	h.handler.ServeHTTP(aw, r)
+
+	end := time.Now().UTC()
+	if info := reqInfoFromContext(r.Context()); info != nil {
+		record.RequestID = info.RequestID
+		record.API = info.API
+		record.Bucket = info.Bucket
+		record.Object = info.Object
+	}
+	record.ResponseHeader = redactHeader(w.Header())
+	record.StatusCode = aw.status
+	if record.StatusCode == 0 {
+		record.StatusCode = http.StatusOK
+		aw.firstByte = end
+	}
+	record.TimeToFirstByte = aw.firstByte.Sub(record.Time)
+	record.Duration = end.Sub(record.Time)
+	globalAuditLogger.Log(record)
+}
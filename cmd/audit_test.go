@@ -0,0 +1,240 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// auditTestTarget - keeps the records it is sent, fails as many
+// sends as asked first.
+type auditTestTarget struct {
+	mutex    *sync.Mutex
+	failures int
+	records  [][]byte
+}
+
+func newAuditTestTarget(failures int) *auditTestTarget {
+	return &auditTestTarget{mutex: &sync.Mutex{}, failures: failures}
+}
+
+func (t *auditTestTarget) Send(record []byte) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.failures > 0 {
+		t.failures--
+		return errors.New("target unavailable")
+	}
+	t.records = append(t.records, record)
+	return nil
+}
+
+func (t *auditTestTarget) Close() error {
+	return nil
+}
+
+// Tests that records chain to the records logged before them and
+// failed records are sent again.
+func TestAuditLoggerChain(t *testing.T) {
+	defer func(interval time.Duration) { auditRetryInterval = interval }(auditRetryInterval)
+	auditRetryInterval = time.Millisecond
+
+	target := newAuditTestTarget(2)
+	logger := newAuditLogger([]*auditQueue{newAuditQueue("test", target)})
+	for _, api := range []string{"REST.PUT.OBJECT", "REST.GET.OBJECT", "REST.DELETE.OBJECT"} {
+		logger.Log(auditRecord{API: api})
+	}
+	logger.Close()
+
+	// Logging to a closed logger must not panic.
+	logger.Log(auditRecord{})
+
+	if len(target.records) != 3 {
+		t.Fatalf("Expected 3 records but received %d", len(target.records))
+	}
+	prevHash := ""
+	for i, data := range target.records {
+		var record auditRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			t.Fatalf("Record %d: unable to unmarshal. %s", i+1, err)
+		}
+		if record.PrevHash != prevHash {
+			t.Errorf("Record %d: expected previous hash %s but found %s", i+1, prevHash, record.PrevHash)
+		}
+		sum := sha256.Sum256(data)
+		prevHash = hex.EncodeToString(sum[:])
+	}
+}
+
+// Tests that records are posted to webhooks until they accept them.
+func TestAuditWebhookTarget(t *testing.T) {
+	defer func(interval time.Duration) { auditRetryInterval = interval }(auditRetryInterval)
+	auditRetryInterval = time.Millisecond
+
+	var mutex sync.Mutex
+	var received []string
+	failures := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		received = append(received, string(body))
+	}))
+	defer server.Close()
+
+	if _, err := newAuditWebhookTarget(auditWebhook{Endpoint: "ftp://localhost"}); err != errInvalidArgument {
+		t.Errorf("Expected %s for an unsupported endpoint but received %v", errInvalidArgument, err)
+	}
+	if _, err := newAuditWebhookTarget(auditWebhook{}); err != errInvalidArgument {
+		t.Errorf("Expected %s for a missing endpoint but received %v", errInvalidArgument, err)
+	}
+
+	target, err := newAuditWebhookTarget(auditWebhook{Enable: true, Endpoint: server.URL, AuthToken: "secret"})
+	if err != nil {
+		t.Fatalf("Unable to initialize audit webhook. %s", err)
+	}
+	q := newAuditQueue("webhook", target)
+	q.recordCh <- []byte(`{"api":"1"}`)
+	q.recordCh <- []byte(`{"api":"2"}`)
+	if err = q.Close(); err != nil {
+		t.Fatalf("Unable to close audit webhook. %s", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if strings.Join(received, ",") != `{"api":"1"},{"api":"2"}` {
+		t.Errorf("Unexpected records posted %v", received)
+	}
+}
+
+// Tests that records are appended to files one per line.
+func TestAuditFileTarget(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minio-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+
+	filename := filepath.Join(dir, "audit.log")
+	for i := 0; i < 2; i++ {
+		target, err := newAuditFileTarget(auditFile{Enable: true, Filename: filename})
+		if err != nil {
+			t.Fatalf("Unable to initialize audit file. %s", err)
+		}
+		if err = target.Send([]byte(`{"api":"api"}`)); err != nil {
+			t.Fatalf("Unable to send audit record. %s", err)
+		}
+		if err = target.Close(); err != nil {
+			t.Fatalf("Unable to close audit file. %s", err)
+		}
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 2 || lines[0] != `{"api":"api"}` || lines[1] != lines[0] {
+		t.Errorf("Unexpected audit file contents %v", lines)
+	}
+}
+
+// Tests that served requests are recorded with their secrets redacted.
+func TestAuditHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	target := newAuditTestTarget(0)
+	defer func(logger *auditLogger) { globalAuditLogger = logger }(globalAuditLogger)
+	globalAuditLogger = newAuditLogger([]*auditQueue{newAuditQueue("test", target)})
+
+	handler := setRequestIDHandler(setAuditHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", "etag")
+		w.WriteHeader(http.StatusCreated)
+	})))
+
+	// Health checks are not audited.
+	cred := serverConfig.GetCredential()
+	var requestID string
+	for _, path := range []string{reservedBucket + healthLivenessPath, "/bucket/object"} {
+		req, err := newTestRequest("PUT", path, 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request - %v", err)
+		}
+		req.RemoteAddr = "10.0.0.1:1234"
+		if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+			t.Fatalf("Failed to sign request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected HTTP status code %d but received %d", http.StatusCreated, rec.Code)
+		}
+		requestID = rec.Header().Get(responseRequestIDKey)
+	}
+	globalAuditLogger.Close()
+
+	if len(target.records) != 1 {
+		t.Fatalf("Expected 1 record but received %d", len(target.records))
+	}
+	var record auditRecord
+	if err = json.Unmarshal(target.records[0], &record); err != nil {
+		t.Fatalf("Unable to unmarshal record. %s", err)
+	}
+	if record.RequestID != requestID || record.Type != requestTypeS3 ||
+		record.AccessKey != cred.AccessKey || record.SourceIP != "10.0.0.1" ||
+		record.API != "REST.PUT.OBJECT" || record.Bucket != "bucket" || record.Object != "object" ||
+		record.StatusCode != http.StatusCreated || record.ResponseHeader.Get("ETag") != "etag" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	if !strings.HasSuffix(record.RequestHeader.Get("Authorization"), "Signature="+redactedValue) {
+		t.Errorf("Expected the signature to be redacted, found %s", record.RequestHeader.Get("Authorization"))
+	}
+	if record.TimeToFirstByte > record.Duration {
+		t.Errorf("Time to first byte %s exceeds duration %s", record.TimeToFirstByte, record.Duration)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -104,6 +105,32 @@ func getRequestAuthType(r *http.Request) authType {
 	return authTypeUnknown
 }
 
+// Returns the access key a request is signed with, empty for anonymous
+// requests and for signatures which can not be parsed.
+func getRequestAccessKey(r *http.Request) string {
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypeStreamingSigned:
+		if sv, s3Error := parseSignV4(r.Header.Get("Authorization")); s3Error == ErrNone {
+			return sv.Credential.accessKey
+		}
+	case authTypePresigned:
+		if psv, s3Error := parsePreSignV4(r.URL.Query()); s3Error == ErrNone {
+			return psv.Credential.accessKey
+		}
+	case authTypeSignedV2:
+		// Authorization = "AWS" + " " + AWSAccessKeyId + ":" + Signature
+		authFields := strings.Split(r.Header.Get("Authorization"), " ")
+		if len(authFields) == 2 {
+			if i := strings.Index(authFields[1], ":"); i > 0 {
+				return authFields[1][:i]
+			}
+		}
+	case authTypePresignedV2:
+		return r.URL.Query().Get("AWSAccessKeyId")
+	}
+	return ""
+}
+
 func checkRequestAuthType(r *http.Request, bucket, policyAction, region string) APIErrorCode {
 	reqAuthType := getRequestAuthType(r)
 
@@ -112,26 +139,47 @@ func checkRequestAuthType(r *http.Request, bucket, policyAction, region string)
 		// Signature V2 validation.
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			errorIfCtx(r.Context(), errSignatureMismatch, dumpRequest(r))
+			return s3Error
 		}
-		return s3Error
+		return enforceUserPolicy(getRequestAccessKey(r), bucket, policyAction, r.URL, r)
 	case authTypeSigned, authTypePresigned:
 		s3Error := isReqAuthenticated(r, region)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			errorIfCtx(r.Context(), errSignatureMismatch, dumpRequest(r))
+			return s3Error
 		}
-		return s3Error
+		return enforceUserPolicy(getRequestAccessKey(r), bucket, policyAction, r.URL, r)
 	}
 
-	if reqAuthType == authTypeAnonymous && policyAction != "" {
+	// Anonymous requests are allowed what the bucket policy allows,
+	// requests without a bucket (e.g. list buckets) have no policy.
+	if reqAuthType == authTypeAnonymous && bucket != "" && supportedActionMap.Contains(policyAction) {
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
-		return enforceBucketPolicy(bucket, policyAction, r.URL)
+		return enforceBucketPolicy(bucket, policyAction, r.URL, r)
 	}
 
 	// By default return ErrAccessDenied
 	return ErrAccessDenied
 }
 
+// checkCopySourceAuth - verifies the caller of a request authenticated by
+// checkRequestAuthType may read the source object of a copy. Signed
+// requests are allowed what the policies of their user allow, anonymous
+// requests what the bucket policy of the source allows.
+func checkCopySourceAuth(r *http.Request, srcBucket, srcObject string) APIErrorCode {
+	// Policies are evaluated on the resource of the source object.
+	srcURL := &url.URL{Path: "/" + srcBucket + "/" + srcObject}
+
+	switch getRequestAuthType(r) {
+	case authTypePresignedV2, authTypeSignedV2, authTypeSigned, authTypePresigned, authTypeStreamingSigned:
+		return enforceUserPolicy(getRequestAccessKey(r), srcBucket, "s3:GetObject", srcURL, r)
+	case authTypeAnonymous:
+		return enforceBucketPolicy(srcBucket, "s3:GetObject", srcURL, r)
+	}
+	return ErrAccessDenied
+}
+
 // Verify if request has valid AWS Signature Version '2'.
 func isReqAuthenticatedV2(r *http.Request) (s3Error APIErrorCode) {
 	if isRequestSignatureV2(r) {
@@ -162,7 +210,7 @@ func isReqAuthenticated(r *http.Request, region string) (s3Error APIErrorCode) {
 	}
 	payload, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		errorIf(err, "Unable to read request body for signature verification")
+		errorIfCtx(r.Context(), err, "Unable to read request body for signature verification")
 		return ErrInternalError
 	}
 	// Verify Content-Md5, if payload is set.
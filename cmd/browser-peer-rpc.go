@@ -30,8 +30,9 @@ func (br *browserPeerAPIHandlers) Login(args *LoginRPCArgs, reply *LoginRPCReply
 		return err
 	}
 
-	// Authenticate using JWT.
-	token, err := authenticateWeb(args.Username, args.Password)
+	// Authenticate using JWT, peers use the token for inter-node
+	// calls like SetAuthPeer.
+	token, err := authenticateJWT(args.Username, args.Password, jwtAudienceNode, defaultJWTExpiry)
 	if err != nil {
 		return err
 	}
@@ -46,6 +46,6 @@ func registerBrowserPeerRPCRouter(mux *router.Router) error {
 	}
 
 	bpRouter := mux.NewRoute().PathPrefix(reservedBucket).Subrouter()
-	bpRouter.Path(browserPeerPath).Handler(bpRPCServer)
+	bpRouter.Path(browserPeerPath).Handler(newRPCHandler(bpRPCServer))
 	return nil
 }
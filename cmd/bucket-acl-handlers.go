@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// PutBucketACLHandler - PUT Bucket ACL
+// -----------------
+// This implementation of the PUT operation uses the acl subresource
+// to set the canned ACL of a bucket, the ACL replaces the anonymous
+// access the bucket policy grants to the whole bucket.
+func (api objectAPIHandlers) PutBucketACLHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutBucketAcl", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	acl, s3Error := readACL(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Acquire a write lock on bucket before modifying its policy.
+	bucketLock := globalNSMutex.NewNSLock(bucket, "")
+	bucketLock.Lock()
+	defer bucketLock.Unlock()
+
+	if err = writeBucketACL(bucket, acl, objectAPI); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to save bucket ACL.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketACLHandler - GET Bucket ACL
+// -----------------
+// This implementation of the GET operation uses the acl subresource
+// to return the grants of the anonymous access the bucket policy
+// grants to the whole bucket.
+func (api objectAPIHandlers) GetBucketACLHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetBucketAcl", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	acp, err := readBucketACL(bucket, objectAPI)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to read bucket ACL.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	aclBytes, err := xml.Marshal(acp)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to marshal bucket ACL into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, aclBytes)
+}
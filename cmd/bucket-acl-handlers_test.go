@@ -0,0 +1,145 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling Put/GetBucketACL handler tests for both XL multiple disks and single node setup.
+func TestBucketACLHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketACLHandlers, []string{
+		"PutBucketACL",
+		"GetBucketACL",
+		"GetObject",
+		"PutObject",
+	})
+}
+
+func testBucketACLHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Sends an ACL request and returns the recorded response.
+	sendRequest := func(method, bucket, acl, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, getBucketConfigURL("", bucket, "acl"),
+			int64(len(body)), bytes.NewReader([]byte(body)), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s ACL: <ERROR> %v", instanceType, method, err)
+		}
+		if acl != "" {
+			req.Header.Set(amzACLHeader, acl)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	// Returns the canned ACL of the bucket as seen by GetBucketAcl.
+	getACL := func() string {
+		rec := sendRequest("GET", bucketName, "", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+		}
+		acp := accessControlPolicy{}
+		if err := xml.Unmarshal(rec.Body.Bytes(), &acp); err != nil {
+			t.Fatalf("%s: Unable to parse response %s", instanceType, err)
+		}
+		acl, ok := getCannedACL(acp)
+		if !ok {
+			t.Fatalf("%s: Unexpected grants %v", instanceType, acp.AccessControlList)
+		}
+		return acl
+	}
+	// Sends an anonymous GET of an object and returns the response status.
+	anonGet := func(object string) int {
+		rec := httptest.NewRecorder()
+		req, err := newTestRequest("GET", getGetObjectURL("", bucketName, object), 0, nil)
+		if err != nil {
+			t.Fatalf("%s: Failed to create an anonymous request: <ERROR> %v", instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if _, err := obj.PutObject(context.Background(), bucketName, "object", int64(len("hello")), bytes.NewReader([]byte("hello")), nil, ""); err != nil {
+		t.Fatalf("%s: Unable to upload object %s", instanceType, err)
+	}
+
+	// Buckets without a policy are private.
+	if acl := getACL(); acl != cannedACLPrivate {
+		t.Errorf("%s: Expected ACL %s, got %s", instanceType, cannedACLPrivate, acl)
+	}
+
+	testCases := []struct {
+		bucketName         string
+		acl                string
+		body               string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Canned ACL header.
+		{bucketName, cannedACLPublicReadWrite, "", http.StatusOK},
+		// Test case - 2.
+		// Grants of a canned ACL.
+		{bucketName, "", `<AccessControlPolicy><AccessControlList><Grant><Grantee><URI>` + aclAllUsersGroup + `</URI></Grantee><Permission>READ</Permission></Grant></AccessControlList></AccessControlPolicy>`, http.StatusOK},
+		// Test case - 3.
+		// Unknown canned ACL.
+		{bucketName, "log-delivery-write", "", http.StatusBadRequest},
+		// Test case - 4.
+		// Neither canned ACL nor grants.
+		{bucketName, "", "", http.StatusLengthRequired},
+		// Test case - 5.
+		// Non-existent bucket.
+		{"non-existent-bucket", cannedACLPublicRead, "", http.StatusNotFound},
+	}
+	for i, testCase := range testCases {
+		rec := sendRequest("PUT", testCase.bucketName, testCase.acl, testCase.body)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// The ACL is translated into the bucket policy.
+	if acl := getACL(); acl != cannedACLPublicRead {
+		t.Errorf("%s: Expected ACL %s, got %s", instanceType, cannedACLPublicRead, acl)
+	}
+	policy, err := readBucketPolicy(bucketName, obj)
+	if err != nil {
+		t.Fatalf("%s: Unable to read bucket policy %s", instanceType, err)
+	}
+	// Peers are not notified in tests, load the policy ourselves.
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, policy})
+	defer globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{IsRemove: true})
+	if code := anonGet("object"); code != http.StatusOK {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+
+	// A private bucket has no policy left.
+	if rec := sendRequest("PUT", bucketName, cannedACLPrivate, ""); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if acl := getACL(); acl != cannedACLPrivate {
+		t.Errorf("%s: Expected ACL %s, got %s", instanceType, cannedACLPrivate, acl)
+	}
+	if _, err = readBucketPolicy(bucketName, obj); !isErrBucketPolicyNotFound(err) {
+		t.Errorf("%s: Expected the bucket policy to be removed, got %v", instanceType, err)
+	}
+}
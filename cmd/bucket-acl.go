@@ -0,0 +1,97 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/minio/minio-go/pkg/policy"
+)
+
+// Bucket ACLs are not saved on their own, a canned ACL is translated
+// into the anonymous access the bucket policy grants to the whole
+// bucket. Requests signed by users are governed by their policies, so
+// authenticated-read grants as little to anonymous requests as private.
+
+// Returns the canned bucket policy granting the anonymous access of a
+// canned ACL.
+func cannedACLToBucketPolicy(acl string) policy.BucketPolicy {
+	switch acl {
+	case cannedACLPublicRead:
+		return policy.BucketPolicyReadOnly
+	case cannedACLPublicReadWrite:
+		return policy.BucketPolicyReadWrite
+	}
+	return policy.BucketPolicyNone
+}
+
+// Returns the grants of the anonymous access a canned bucket policy
+// grants, write only access has no canned ACL and is reported as the
+// grants of public-read-write without the read grant.
+func bucketPolicyToAccessControlPolicy(bucketP policy.BucketPolicy) accessControlPolicy {
+	switch bucketP {
+	case policy.BucketPolicyReadOnly:
+		return newAccessControlPolicy(cannedACLPublicRead)
+	case policy.BucketPolicyReadWrite:
+		return newAccessControlPolicy(cannedACLPublicReadWrite)
+	case policy.BucketPolicyWriteOnly:
+		acp := newAccessControlPolicy(cannedACLPrivate)
+		acp.AccessControlList = append(acp.AccessControlList, newGroupGrant(aclAllUsersGroup, aclPermissionWrite))
+		return acp
+	}
+	return newAccessControlPolicy(cannedACLPrivate)
+}
+
+// readBucketACL - returns the grants of the anonymous access the bucket
+// policy of a bucket grants to the whole bucket.
+func readBucketACL(bucket string, objAPI ObjectLayer) (accessControlPolicy, error) {
+	policyInfo, err := readBucketAccessPolicy(objAPI, bucket)
+	if err != nil {
+		return accessControlPolicy{}, err
+	}
+	return bucketPolicyToAccessControlPolicy(policy.GetPolicy(policyInfo.Statements, bucket, "")), nil
+}
+
+// writeBucketACL - replaces the anonymous access the bucket policy of a
+// bucket grants to the whole bucket by the access of a canned ACL,
+// statements for prefixes are kept. The bucket policy is removed when
+// no statements are left. Callers hold the bucket lock.
+func writeBucketACL(bucket, acl string, objAPI ObjectLayer) error {
+	policyInfo, err := readBucketAccessPolicy(objAPI, bucket)
+	if err != nil {
+		return err
+	}
+	policyInfo.Statements = policy.SetPolicy(policyInfo.Statements, cannedACLToBucketPolicy(acl), bucket, "")
+	if len(policyInfo.Statements) == 0 {
+		err = persistAndNotifyBucketPolicyChange(bucket, policyChange{true, nil}, objAPI)
+		if isErrBucketPolicyNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	data, err := json.Marshal(policyInfo)
+	if err != nil {
+		return err
+	}
+	bpy := &bucketPolicy{}
+	if err = parseBucketPolicy(bytes.NewReader(data), bpy); err != nil {
+		return err
+	}
+	return persistAndNotifyBucketPolicyChange(bucket, policyChange{false, bpy}, objAPI)
+}
@@ -0,0 +1,247 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"path"
+	"sync"
+)
+
+// bucketConfig - a kind of XML bucket configuration, saved as
+// `.minio.sys/buckets/<bucket>/<name>` and kept in memory on every
+// node. Nodes are notified about changes by the name of the
+// configuration and reload it from the backend.
+type bucketConfig struct {
+	// Name of the configuration file, e.g. `lifecycle.xml`.
+	name string
+	// Kind of configuration used in log messages, e.g. `lifecycle`.
+	desc string
+	// Returned when a bucket has no configuration of this kind.
+	errNoSuchConfig error
+	// Returns an empty configuration to unmarshal into.
+	newConfig func() interface{}
+
+	rwMutex *sync.RWMutex
+
+	// Configuration of each bucket, only buckets which have a
+	// configuration are present.
+	configs map[string]interface{}
+}
+
+func newBucketConfig(name, desc string, errNoSuchConfig error, newConfig func() interface{}) *bucketConfig {
+	return &bucketConfig{
+		name:            name,
+		desc:            desc,
+		errNoSuchConfig: errNoSuchConfig,
+		newConfig:       newConfig,
+		rwMutex:         &sync.RWMutex{},
+		configs:         make(map[string]interface{}),
+	}
+}
+
+// Bucket configurations kept in memory, loaded when the object layer
+// is initialized.
+var globalBucketConfigs = []*bucketConfig{
+	globalBucketVersioning,
+	globalBucketLifecycles,
+	globalBucketCors,
+	globalBucketEncryption,
+	globalBucketObjectLock,
+	globalBucketWebsite,
+	globalBucketLogging,
+	globalBucketReplication,
+}
+
+// Returns the bucket configuration saved under name, nil if unknown.
+func getBucketConfigByName(name string) *bucketConfig {
+	for _, bc := range globalBucketConfigs {
+		if bc.name == name {
+			return bc
+		}
+	}
+	return nil
+}
+
+// Get - returns the configuration of a bucket, nil if none is set.
+func (bc *bucketConfig) Get(bucket string) interface{} {
+	bc.rwMutex.RLock()
+	defer bc.rwMutex.RUnlock()
+	return bc.configs[bucket]
+}
+
+// Set - sets a new configuration for a bucket, a nil configuration
+// removes the bucket from the collection.
+func (bc *bucketConfig) Set(bucket string, cfg interface{}) {
+	bc.rwMutex.Lock()
+	defer bc.rwMutex.Unlock()
+
+	if cfg == nil {
+		delete(bc.configs, bucket)
+		return
+	}
+	bc.configs[bucket] = cfg
+}
+
+// GetAll - returns a copy of the configurations of all buckets.
+func (bc *bucketConfig) GetAll() map[string]interface{} {
+	bc.rwMutex.RLock()
+	defer bc.rwMutex.RUnlock()
+
+	configs := make(map[string]interface{}, len(bc.configs))
+	for bucket, cfg := range bc.configs {
+		configs[bucket] = cfg
+	}
+	return configs
+}
+
+// Intialize all the configurations of all buckets.
+func initBucketConfigs(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	// List buckets to proceed loading all configurations.
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		errorIf(err, "Unable to list buckets.")
+		return errorCause(err)
+	}
+
+	for _, bc := range globalBucketConfigs {
+		configs := make(map[string]interface{})
+		for _, bucket := range buckets {
+			cfg, cErr := bc.read(bucket.Name, objAPI)
+			if cErr != nil {
+				if isErrIgnored(cErr, errDiskNotFound, bc.errNoSuchConfig) {
+					continue
+				}
+				return cErr
+			}
+			configs[bucket.Name] = cfg
+		}
+
+		// Populate global bucket collection.
+		bc.rwMutex.Lock()
+		bc.configs = configs
+		bc.rwMutex.Unlock()
+	}
+
+	// Success.
+	return nil
+}
+
+// read - reads the configuration of a bucket, returns errNoSuchConfig
+// if none is set.
+func (bc *bucketConfig) read(bucket string, objAPI ObjectLayer) (interface{}, error) {
+	cfgPath := path.Join(bucketConfigPrefix, bucket, bc.name)
+
+	// Acquire a read lock on config before reading.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, cfgPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, cfgPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, bc.errNoSuchConfig
+		}
+		errorIf(err, "Unable to load %s configuration for bucket %s.", bc.desc, bucket)
+		return nil, errorCause(err)
+	}
+
+	cfg := bc.newConfig()
+	if err = xml.Unmarshal(buffer.Bytes(), cfg); err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return cfg, nil
+}
+
+// write - saves a validated configuration of a bucket.
+func (bc *bucketConfig) write(bucket string, cfg interface{}, objAPI ObjectLayer) error {
+	buf, err := xml.Marshal(cfg)
+	if err != nil {
+		errorIf(err, "Unable to marshal %s configuration into XML.", bc.desc)
+		return err
+	}
+
+	cfgPath := path.Join(bucketConfigPrefix, bucket, bc.name)
+	// Acquire a write lock on config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, cfgPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	sha256Sum := getSHA256Hash(buf)
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, cfgPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum); err != nil {
+		errorIf(err, "Unable to write %s configuration for bucket %s.", bc.desc, bucket)
+		return errorCause(err)
+	}
+	return nil
+}
+
+// remove - removes the configuration of a bucket and notifies all nodes
+// in the cluster, used by the DELETE handlers and DeleteBucket.
+func (bc *bucketConfig) remove(bucket string, objAPI ObjectLayer) error {
+	cfgPath := path.Join(bucketConfigPrefix, bucket, bc.name)
+
+	// Acquire a write lock on config before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, cfgPath)
+	objLock.Lock()
+	err := objAPI.DeleteObject(context.Background(), minioMetaBucket, cfgPath)
+	objLock.Unlock()
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			return bc.errNoSuchConfig
+		}
+		return err
+	}
+
+	// Notify all peers (including self) to forget the configuration.
+	S3PeersLoadConfig(bc.name, bucket)
+	return nil
+}
+
+// persistAndNotify - saves the configuration of a bucket and notifies
+// all nodes in the cluster about the change.
+func (bc *bucketConfig) persistAndNotify(bucket string, cfg interface{}, objAPI ObjectLayer) error {
+	if err := bc.write(bucket, cfg, objAPI); err != nil {
+		return err
+	}
+
+	// Notify all peers (including self) to update in-memory state.
+	S3PeersLoadConfig(bc.name, bucket)
+	return nil
+}
+
+// load - reloads the configuration of a bucket into memory, called on
+// every node notified about a change.
+func (bc *bucketConfig) load(bucket string, objAPI ObjectLayer) error {
+	cfg, err := bc.read(bucket, objAPI)
+	if err != nil {
+		if err != bc.errNoSuchConfig {
+			return err
+		}
+		cfg = nil
+	}
+	bc.Set(bucket, cfg)
+	return nil
+}
@@ -0,0 +1,180 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// CORS configuration can be at most 64KiB, like on s3.
+const maxCorsConfigSize = 64 * 1024
+
+// PutBucketCorsHandler - PUT Bucket cors
+// -----------------
+// This implementation of the PUT operation uses the cors
+// subresource to replace the CORS rules of an existing bucket.
+func (api objectAPIHandlers) PutBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutBucketCORS", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// If Content-Length is unknown or zero, deny the request.
+	// PutBucketCors always needs a Content-Length.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		return
+	}
+	if r.ContentLength > maxCorsConfigSize {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+
+	// Reads the incoming CORS configuration.
+	var buffer bytes.Buffer
+	if _, err = io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to read incoming body.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	var cCfg corsConfig
+	if err = xml.Unmarshal(buffer.Bytes(), &cCfg); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to parse CORS configuration XML.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	if s3Error := validateCorsConfig(cCfg); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err = globalBucketCors.persistAndNotify(bucket, &cCfg, objectAPI); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to save CORS configuration.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketCorsHandler - GET Bucket cors
+// -----------------
+// This implementation of the GET operation uses the cors
+// subresource to return the CORS rules of a bucket.
+func (api objectAPIHandlers) GetBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetBucketCORS", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	cCfg, err := globalBucketCors.read(bucket, objectAPI)
+	if err != nil {
+		if err == errNoSuchCorsConfig {
+			writeErrorResponse(w, ErrNoSuchCORSConfiguration, r.URL)
+			return
+		}
+		errorIfCtx(r.Context(), err, "Unable to read CORS configuration.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	corsBytes, err := xml.Marshal(cCfg)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to marshal CORS configuration into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, corsBytes)
+}
+
+// DeleteBucketCorsHandler - DELETE Bucket cors
+// -----------------
+// This implementation of the DELETE operation uses the cors
+// subresource to remove all CORS rules of a bucket.
+func (api objectAPIHandlers) DeleteBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutBucketCORS", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Removing a non-existent configuration succeeds, like s3 does.
+	if err = globalBucketCors.remove(bucket, objectAPI); err != nil && err != errNoSuchCorsConfig {
+		errorIfCtx(r.Context(), err, "Unable to remove CORS configuration.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
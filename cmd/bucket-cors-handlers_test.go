@@ -0,0 +1,101 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling Put/Get/DeleteBucketCors handler tests for both XL multiple disks and single node setup.
+func TestBucketCorsHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketCorsHandlers, []string{
+		"PutBucketCors",
+		"GetBucketCors",
+		"DeleteBucketCors",
+	})
+}
+
+func testBucketCorsHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Sends a CORS request and returns the recorded response.
+	sendRequest := func(method, bucket, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, getBucketConfigURL("", bucket, "cors"),
+			int64(len(body)), bytes.NewReader([]byte(body)), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s CORS: <ERROR> %v", instanceType, method, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Never configured.
+	if rec := sendRequest("GET", bucketName, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+
+	testCases := []struct {
+		bucketName         string
+		body               string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Valid configuration.
+		{bucketName, `<CORSConfiguration><CORSRule><ID>upload</ID><AllowedOrigin>https://www.example.com</AllowedOrigin><AllowedMethod>PUT</AllowedMethod><MaxAgeSeconds>3000</MaxAgeSeconds></CORSRule></CORSConfiguration>`, http.StatusOK},
+		// Test case - 2.
+		// Unsupported method.
+		{bucketName, `<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>PATCH</AllowedMethod></CORSRule></CORSConfiguration>`, http.StatusBadRequest},
+		// Test case - 3.
+		// Malformed configuration.
+		{bucketName, `<CORSConfiguration><CORSRule>`, http.StatusBadRequest},
+		// Test case - 4.
+		// Non-existent bucket.
+		{"non-existent-bucket", `<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>GET</AllowedMethod></CORSRule></CORSConfiguration>`, http.StatusNotFound},
+	}
+	for i, testCase := range testCases {
+		rec := sendRequest("PUT", testCase.bucketName, testCase.body)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Read back the valid configuration.
+	rec := sendRequest("GET", bucketName, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	cCfg := corsConfig{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &cCfg); err != nil {
+		t.Fatalf("%s: Unable to parse response %s", instanceType, err)
+	}
+	if len(cCfg.CORSRules) != 1 || cCfg.CORSRules[0].ID != "upload" || cCfg.CORSRules[0].MaxAgeSeconds != 3000 {
+		t.Errorf("%s: Unexpected CORS configuration %#v", instanceType, cCfg)
+	}
+
+	// Remove the configuration.
+	if rec = sendRequest("DELETE", bucketName, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if rec = sendRequest("GET", bucketName, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+
+	"github.com/teamwork/minio/pkg/wildcard"
+)
+
+const (
+	// Bucket CORS config name.
+	bucketCorsConfig = "cors.xml"
+
+	// Maximum number of rules in a CORS configuration.
+	maxCorsRules = 100
+)
+
+// errNoSuchCorsConfig - bucket has no CORS configuration.
+var errNoSuchCorsConfig = errors.New("The specified bucket does not have a CORS configuration")
+
+// corsConfig - represents the CORS rules of a bucket as set by
+// PutBucketCors.
+type corsConfig struct {
+	XMLName   xml.Name   `xml:"CORSConfiguration"`
+	CORSRules []corsRule `xml:"CORSRule"`
+}
+
+// corsRule - a single CORS rule, the first rule matching the origin,
+// method and headers of a request decides the response headers.
+type corsRule struct {
+	ID             string   `xml:"ID,omitempty"`
+	AllowedOrigins []string `xml:"AllowedOrigin"`
+	AllowedMethods []string `xml:"AllowedMethod"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty"`
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty"`
+}
+
+// HTTP methods which can be allowed by a CORS rule.
+var corsAllowableMethods = map[string]bool{
+	httpGET:    true,
+	httpPUT:    true,
+	httpHEAD:   true,
+	httpPOST:   true,
+	httpDELETE: true,
+}
+
+// Validates a single CORS rule.
+func validateCorsRule(rule corsRule) APIErrorCode {
+	if len(rule.AllowedOrigins) == 0 || len(rule.AllowedMethods) == 0 {
+		return ErrMalformedXML
+	}
+	for _, method := range rule.AllowedMethods {
+		if !corsAllowableMethods[method] {
+			return ErrCorsInvalidMethod
+		}
+	}
+	for _, origin := range rule.AllowedOrigins {
+		if strings.Count(origin, "*") > 1 {
+			return ErrCorsInvalidOrigin
+		}
+	}
+	for _, header := range rule.AllowedHeaders {
+		if strings.Count(header, "*") > 1 {
+			return ErrCorsInvalidHeader
+		}
+	}
+	if rule.MaxAgeSeconds < 0 {
+		return ErrMalformedXML
+	}
+	return ErrNone
+}
+
+// Validates CORS configuration.
+func validateCorsConfig(cCfg corsConfig) APIErrorCode {
+	if len(cCfg.CORSRules) == 0 || len(cCfg.CORSRules) > maxCorsRules {
+		return ErrMalformedXML
+	}
+	for _, rule := range cCfg.CORSRules {
+		if s3Error := validateCorsRule(rule); s3Error != ErrNone {
+			return s3Error
+		}
+	}
+	return ErrNone
+}
+
+// Returns true if value matches any of the patterns, each pattern may
+// carry a single '*' wildcard.
+func corsMatchAny(patterns []string, value string, ignoreCase bool) bool {
+	for _, pattern := range patterns {
+		if ignoreCase {
+			pattern, value = strings.ToLower(pattern), strings.ToLower(value)
+		}
+		if wildcard.MatchSimple(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the rule allows the given origin, method and request
+// headers.
+func (rule corsRule) matches(origin, method string, headers []string) bool {
+	if !corsMatchAny(rule.AllowedOrigins, origin, false) {
+		return false
+	}
+	allowedMethod := false
+	for _, m := range rule.AllowedMethods {
+		if m == method {
+			allowedMethod = true
+			break
+		}
+	}
+	if !allowedMethod {
+		return false
+	}
+	for _, header := range headers {
+		if !corsMatchAny(rule.AllowedHeaders, header, true) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if the rule allows any origin.
+func (rule corsRule) allowsAnyOrigin() bool {
+	for _, origin := range rule.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the first rule matching the origin, method and request headers,
+// nil if the request is not allowed.
+func (cCfg corsConfig) matchRule(origin, method string, headers []string) *corsRule {
+	for i := range cCfg.CORSRules {
+		if cCfg.CORSRules[i].matches(origin, method, headers) {
+			return &cCfg.CORSRules[i]
+		}
+	}
+	return nil
+}
+
+// Variable represents bucket CORS configurations in memory, looked up
+// on every request carrying an Origin header.
+var globalBucketCors = newBucketConfig(bucketCorsConfig, "CORS", errNoSuchCorsConfig, func() interface{} {
+	return &corsConfig{}
+})
+
+// getBucketCors - returns the CORS configuration of a bucket, nil if
+// none is set.
+func getBucketCors(bucket string) *corsConfig {
+	cCfg, _ := globalBucketCors.Get(bucket).(*corsConfig)
+	return cCfg
+}
@@ -0,0 +1,62 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+// Tests validation of CORS configurations.
+func TestValidateCorsConfig(t *testing.T) {
+	testCases := []struct {
+		config        string
+		expectedError APIErrorCode
+	}{
+		// Test case - 1.
+		// Valid configuration.
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>https://*.example.com</AllowedOrigin><AllowedMethod>PUT</AllowedMethod><AllowedMethod>POST</AllowedMethod><AllowedHeader>*</AllowedHeader><ExposeHeader>ETag</ExposeHeader><MaxAgeSeconds>3000</MaxAgeSeconds></CORSRule></CORSConfiguration>`, ErrNone},
+		// Test case - 2.
+		// No rules.
+		{`<CORSConfiguration></CORSConfiguration>`, ErrMalformedXML},
+		// Test case - 3.
+		// No origin.
+		{`<CORSConfiguration><CORSRule><AllowedMethod>GET</AllowedMethod></CORSRule></CORSConfiguration>`, ErrMalformedXML},
+		// Test case - 4.
+		// No method.
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin></CORSRule></CORSConfiguration>`, ErrMalformedXML},
+		// Test case - 5.
+		// Unsupported method.
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>PATCH</AllowedMethod></CORSRule></CORSConfiguration>`, ErrCorsInvalidMethod},
+		// Test case - 6.
+		// Origin with two wildcards.
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>*.*.com</AllowedOrigin><AllowedMethod>GET</AllowedMethod></CORSRule></CORSConfiguration>`, ErrCorsInvalidOrigin},
+		// Test case - 7.
+		// Header with two wildcards.
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>GET</AllowedMethod><AllowedHeader>x-*-*</AllowedHeader></CORSRule></CORSConfiguration>`, ErrCorsInvalidHeader},
+	}
+
+	for i, testCase := range testCases {
+		var cCfg corsConfig
+		if err := xml.Unmarshal([]byte(testCase.config), &cCfg); err != nil {
+			t.Fatalf("Test %d: Unable to parse configuration %s", i+1, err)
+		}
+		if s3Error := validateCorsConfig(cCfg); s3Error != testCase.expectedError {
+			t.Errorf("Test %d: Expected error %d, got %d", i+1, testCase.expectedError, s3Error)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Encryption configuration can be at most 64KiB.
+const maxEncryptionConfigSize = 64 * 1024
+
+// PutBucketEncryptionHandler - PUT Bucket encryption
+// -----------------
+// This implementation of the PUT operation uses the encryption
+// subresource to set the default encryption of an existing bucket.
+func (api objectAPIHandlers) PutBucketEncryptionHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutEncryptionConfiguration", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// If Content-Length is unknown or zero, deny the request.
+	// PutBucketEncryption always needs a Content-Length.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		return
+	}
+	if r.ContentLength > maxEncryptionConfigSize {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+
+	// Reads the incoming encryption configuration.
+	var buffer bytes.Buffer
+	if _, err = io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to read incoming body.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	var eCfg encryptionConfig
+	if err = xml.Unmarshal(buffer.Bytes(), &eCfg); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to parse encryption configuration XML.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	if s3Error := validateEncryptionConfig(eCfg); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err = globalBucketEncryption.persistAndNotify(bucket, &eCfg, objectAPI); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to save encryption configuration.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketEncryptionHandler - GET Bucket encryption
+// -----------------
+// This implementation of the GET operation uses the encryption
+// subresource to return the default encryption of a bucket.
+func (api objectAPIHandlers) GetBucketEncryptionHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetEncryptionConfiguration", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	eCfg, err := globalBucketEncryption.read(bucket, objectAPI)
+	if err != nil {
+		if err == errNoSuchBucketEncryption {
+			writeErrorResponse(w, ErrNoSuchBucketEncryption, r.URL)
+			return
+		}
+		errorIfCtx(r.Context(), err, "Unable to read encryption configuration.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	encryptionBytes, err := xml.Marshal(eCfg)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to marshal encryption configuration into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, encryptionBytes)
+}
+
+// DeleteBucketEncryptionHandler - DELETE Bucket encryption
+// -----------------
+// This implementation of the DELETE operation uses the encryption
+// subresource to remove the default encryption of a bucket.
+func (api objectAPIHandlers) DeleteBucketEncryptionHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutEncryptionConfiguration", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Removing a non-existent configuration succeeds, like s3 does.
+	if err = globalBucketEncryption.remove(bucket, objectAPI); err != nil && err != errNoSuchBucketEncryption {
+		errorIfCtx(r.Context(), err, "Unable to remove encryption configuration.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
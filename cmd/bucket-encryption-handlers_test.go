@@ -0,0 +1,121 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling Put/Get/DeleteBucketEncryption handler tests for both XL multiple disks and single node setup.
+func TestBucketEncryptionHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketEncryptionHandlers, []string{
+		"PutBucketEncryption",
+		"GetBucketEncryption",
+		"DeleteBucketEncryption",
+	})
+}
+
+func testBucketEncryptionHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	defer func(kms KMS) { globalKMS = kms }(globalKMS)
+
+	// Sends an encryption request and returns the recorded response.
+	sendRequest := func(method, bucket, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, getBucketConfigURL("", bucket, "encryption"),
+			int64(len(body)), bytes.NewReader([]byte(body)), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s encryption: <ERROR> %v", instanceType, method, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Default encryption needs a KMS.
+	sseS3Config := `<ServerSideEncryptionConfiguration><Rule><ApplyServerSideEncryptionByDefault><SSEAlgorithm>AES256</SSEAlgorithm></ApplyServerSideEncryptionByDefault></Rule></ServerSideEncryptionConfiguration>`
+	globalKMS = nil
+	if rec := sendRequest("PUT", bucketName, sseS3Config); rec.Code != http.StatusNotImplemented {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotImplemented, rec.Code)
+	}
+	kms, err := newMasterKeyKMS("my-key", bytes.Repeat([]byte{'m'}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalKMS = kms
+
+	// Never configured.
+	if rec := sendRequest("GET", bucketName, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+
+	testCases := []struct {
+		bucketName         string
+		body               string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Valid SSE-S3 configuration.
+		{bucketName, sseS3Config, http.StatusOK},
+		// Test case - 2.
+		// SSE-S3 does not take a master key.
+		{bucketName, `<ServerSideEncryptionConfiguration><Rule><ApplyServerSideEncryptionByDefault><SSEAlgorithm>AES256</SSEAlgorithm><KMSMasterKeyID>my-key</KMSMasterKeyID></ApplyServerSideEncryptionByDefault></Rule></ServerSideEncryptionConfiguration>`, http.StatusBadRequest},
+		// Test case - 3.
+		// Unsupported algorithm.
+		{bucketName, `<ServerSideEncryptionConfiguration><Rule><ApplyServerSideEncryptionByDefault><SSEAlgorithm>DES</SSEAlgorithm></ApplyServerSideEncryptionByDefault></Rule></ServerSideEncryptionConfiguration>`, http.StatusBadRequest},
+		// Test case - 4.
+		// Malformed configuration.
+		{bucketName, `<ServerSideEncryptionConfiguration><Rule>`, http.StatusBadRequest},
+		// Test case - 5.
+		// Non-existent bucket.
+		{"non-existent-bucket", sseS3Config, http.StatusNotFound},
+		// Test case - 6.
+		// Valid SSE-KMS configuration.
+		{bucketName, `<ServerSideEncryptionConfiguration><Rule><ApplyServerSideEncryptionByDefault><SSEAlgorithm>aws:kms</SSEAlgorithm><KMSMasterKeyID>my-key</KMSMasterKeyID></ApplyServerSideEncryptionByDefault></Rule></ServerSideEncryptionConfiguration>`, http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		rec := sendRequest("PUT", testCase.bucketName, testCase.body)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Read back the last valid configuration.
+	rec := sendRequest("GET", bucketName, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	eCfg := encryptionConfig{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &eCfg); err != nil {
+		t.Fatalf("%s: Unable to parse response %s", instanceType, err)
+	}
+	if len(eCfg.Rules) != 1 || eCfg.Rules[0].Default.SSEAlgorithm != sseAlgorithmKMS || eCfg.Rules[0].Default.KMSMasterKeyID != "my-key" {
+		t.Errorf("%s: Unexpected encryption configuration %#v", instanceType, eCfg)
+	}
+
+	// Remove the configuration.
+	if rec = sendRequest("DELETE", bucketName, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if rec = sendRequest("GET", bucketName, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+// Bucket encryption config name.
+const bucketEncryptionConfig = "encryption.xml"
+
+// errNoSuchBucketEncryption - bucket has no default encryption.
+var errNoSuchBucketEncryption = errors.New("The server side encryption configuration was not found")
+
+// encryptionConfig - represents the default encryption of a bucket as
+// set by PutBucketEncryption, applied to every object written without
+// encryption headers.
+type encryptionConfig struct {
+	XMLName xml.Name         `xml:"ServerSideEncryptionConfiguration"`
+	Rules   []encryptionRule `xml:"Rule"`
+}
+
+// encryptionRule - a single default encryption rule.
+type encryptionRule struct {
+	Default struct {
+		SSEAlgorithm   string `xml:"SSEAlgorithm"`
+		KMSMasterKeyID string `xml:"KMSMasterKeyID,omitempty"`
+	} `xml:"ApplyServerSideEncryptionByDefault"`
+}
+
+// Validates bucket encryption configuration.
+func validateEncryptionConfig(eCfg encryptionConfig) APIErrorCode {
+	if len(eCfg.Rules) != 1 {
+		return ErrMalformedXML
+	}
+	rule := eCfg.Rules[0].Default
+	switch rule.SSEAlgorithm {
+	case sseAlgorithmAES256:
+		if rule.KMSMasterKeyID != "" {
+			return ErrInvalidEncryptionParameters
+		}
+	case sseAlgorithmKMS:
+	default:
+		return ErrInvalidEncryptionMethod
+	}
+	if globalKMS == nil {
+		return ErrKMSNotConfigured
+	}
+	return ErrNone
+}
+
+// Variable represents bucket encryption configurations in memory,
+// looked up on every write.
+var globalBucketEncryption = newBucketConfig(bucketEncryptionConfig, "encryption", errNoSuchBucketEncryption, func() interface{} {
+	return &encryptionConfig{}
+})
+
+// getBucketEncryption - returns the default encryption algorithm and
+// KMS master key of a bucket, an empty algorithm if none is set.
+func getBucketEncryption(bucket string) (algorithm, keyID string) {
+	eCfg, _ := globalBucketEncryption.Get(bucket).(*encryptionConfig)
+	if eCfg == nil || len(eCfg.Rules) == 0 {
+		return "", ""
+	}
+	return eCfg.Rules[0].Default.SSEAlgorithm, eCfg.Rules[0].Default.KMSMasterKeyID
+}
@@ -28,12 +28,11 @@ import (
 	"sync"
 
 	mux "github.com/gorilla/mux"
-	"github.com/minio/minio-go/pkg/set"
 )
 
 // http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
 // Enforces bucket policies for a bucket for a given tatusaction.
-func enforceBucketPolicy(bucket string, action string, reqURL *url.URL) (s3Error APIErrorCode) {
+func enforceBucketPolicy(bucket string, action string, reqURL *url.URL, r *http.Request) (s3Error APIErrorCode) {
 	// Verify if bucket actually exists
 	if err := checkBucketExist(bucket, newObjectLayerFn()); err != nil {
 		err = errorCause(err)
@@ -45,7 +44,7 @@ func enforceBucketPolicy(bucket string, action string, reqURL *url.URL) (s3Error
 			// For no bucket found we return NoSuchBucket instead.
 			return ErrNoSuchBucket
 		}
-		errorIf(err, "Unable to read bucket policy.")
+		errorIfCtx(r.Context(), err, "Unable to read bucket policy.")
 		// Return internal error for any other errors so that we can investigate.
 		return ErrInternalError
 	}
@@ -56,35 +55,34 @@ func enforceBucketPolicy(bucket string, action string, reqURL *url.URL) (s3Error
 		return ErrAccessDenied
 	}
 
+	// Validate action, resource and conditions with current policy statements.
+	if evalBucketPolicy(policy, bucket, action, reqURL, r) != policyAllow {
+		return ErrAccessDenied
+	}
+	return ErrNone
+}
+
+// evalBucketPolicy - evaluates a bucket policy for the action on the
+// resource of the request.
+func evalBucketPolicy(policy *bucketPolicy, bucket, action string, reqURL *url.URL, r *http.Request) policyDecision {
 	// Construct resource in 'arn:aws:s3:::examplebucket/object' format.
 	resource := bucketARNPrefix + strings.TrimSuffix(strings.TrimPrefix(reqURL.Path, "/"), "/")
 
 	// Get conditions for policy verification.
-	conditionKeyMap := make(map[string]set.StringSet)
-	for queryParam := range reqURL.Query() {
-		conditionKeyMap[queryParam] = set.CreateStringSet(reqURL.Query().Get(queryParam))
-	}
+	conditionKeyMap := getPolicyConditionValues(policy, bucket, reqURL, r)
 
-	// Validate action, resource and conditions with current policy statements.
-	if !bucketPolicyEvalStatements(action, resource, conditionKeyMap, policy.Statements) {
-		return ErrAccessDenied
-	}
-	return ErrNone
+	return bucketPolicyEval(action, resource, conditionKeyMap, policy.Statements)
 }
 
-// Check if the action is allowed on the bucket/prefix.
-func isBucketActionAllowed(action, bucket, prefix string) bool {
+// Check if the action is allowed on the bucket/prefix for the request.
+func isBucketActionAllowed(r *http.Request, action, bucket, prefix string) bool {
 	policy := globalBucketPolicies.GetBucketPolicy(bucket)
 	if policy == nil {
 		return false
 	}
-	resource := bucketARNPrefix + path.Join(bucket, prefix)
-	var conditionKeyMap map[string]set.StringSet
+	reqURL := &url.URL{Path: path.Join("/", bucket, prefix)}
 	// Validate action, resource and conditions with current policy statements.
-	if !bucketPolicyEvalStatements(action, resource, conditionKeyMap, policy.Statements) {
-		return false
-	}
-	return true
+	return evalBucketPolicy(policy, bucket, action, reqURL, r) == policyAllow
 }
 
 // GetBucketLocationHandler - GET Bucket location.
@@ -110,8 +108,8 @@ func (api objectAPIHandlers) GetBucketLocationHandler(w http.ResponseWriter, r *
 		return
 	}
 
-	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
-		errorIf(err, "Unable to fetch bucket info.")
+	if _, err := objectAPI.GetBucketInfo(r.Context(), bucket); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to fetch bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -137,7 +135,6 @@ func (api objectAPIHandlers) GetBucketLocationHandler(w http.ResponseWriter, r *
 // using the Initiate Multipart Upload request, but has not yet been
 // completed or aborted. This operation returns at most 1,000 multipart
 // uploads in the response.
-//
 func (api objectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
@@ -166,9 +163,9 @@ func (api objectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter,
 		}
 	}
 
-	listMultipartsInfo, err := objectAPI.ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
+	listMultipartsInfo, err := objectAPI.ListMultipartUploads(r.Context(), bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
 	if err != nil {
-		errorIf(err, "Unable to list multipart uploads.")
+		errorIfCtx(r.Context(), err, "Unable to list multipart uploads.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -191,20 +188,20 @@ func (api objectAPIHandlers) ListBucketsHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// ListBuckets does not have any bucket action.
-	s3Error := checkRequestAuthType(r, "", "", globalMinioDefaultRegion)
+	// ListBuckets is not granted by bucket policies.
+	s3Error := checkRequestAuthType(r, "", "s3:ListAllMyBuckets", globalMinioDefaultRegion)
 	if s3Error == ErrInvalidRegion {
 		// Clients like boto3 send listBuckets() call signed with region that is configured.
-		s3Error = checkRequestAuthType(r, "", "", serverConfig.GetRegion())
+		s3Error = checkRequestAuthType(r, "", "s3:ListAllMyBuckets", serverConfig.GetRegion())
 	}
 	if s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 	// Invoke the list buckets.
-	bucketsInfo, err := objectAPI.ListBuckets()
+	bucketsInfo, err := objectAPI.ListBuckets(r.Context())
 	if err != nil {
-		errorIf(err, "Unable to list buckets.")
+		errorIfCtx(r.Context(), err, "Unable to list buckets.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -252,7 +249,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 
 	// Read incoming body XML bytes.
 	if _, err := io.ReadFull(r.Body, deleteXMLBytes); err != nil {
-		errorIf(err, "Unable to read HTTP body.")
+		errorIfCtx(r.Context(), err, "Unable to read HTTP body.")
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
@@ -260,7 +257,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 	// Unmarshal list of keys to be deleted.
 	deleteObjects := &DeleteObjectsRequest{}
 	if err := xml.Unmarshal(deleteXMLBytes, deleteObjects); err != nil {
-		errorIf(err, "Unable to unmarshal delete objects request XML.")
+		errorIfCtx(r.Context(), err, "Unable to unmarshal delete objects request XML.")
 		writeErrorResponse(w, ErrMalformedXML, r.URL)
 		return
 	}
@@ -273,7 +270,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 		wg.Add(1)
 		go func(i int, obj ObjectIdentifier) {
 			defer wg.Done()
-			dErr := objectAPI.DeleteObject(bucket, obj.ObjectName)
+			dErr := objectAPI.DeleteObject(r.Context(), bucket, obj.ObjectName)
 			if dErr != nil {
 				dErrs[i] = dErr
 			}
@@ -297,7 +294,7 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 			deletedObjects = append(deletedObjects, object)
 			continue
 		}
-		errorIf(err, "Unable to delete object. %s", object.ObjectName)
+		errorIfCtx(r.Context(), err, "Unable to delete object. %s", object.ObjectName)
 		// Error during delete should be collected separately.
 		deleteErrors = append(deleteErrors, DeleteError{
 			Code:    errorCodeResponse[toAPIErrorCode(err)].Code,
@@ -338,20 +335,20 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// PutBucket does not have any bucket action.
-	s3Error := checkRequestAuthType(r, "", "", globalMinioDefaultRegion)
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// PutBucket is not granted by bucket policies.
+	s3Error := checkRequestAuthType(r, bucket, "s3:CreateBucket", globalMinioDefaultRegion)
 	if s3Error == ErrInvalidRegion {
 		// Clients like boto3 send putBucket() call signed with region that is configured.
-		s3Error = checkRequestAuthType(r, "", "", serverConfig.GetRegion())
+		s3Error = checkRequestAuthType(r, bucket, "s3:CreateBucket", serverConfig.GetRegion())
 	}
 	if s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
-	vars := mux.Vars(r)
-	bucket := vars["bucket"]
-
 	// Validate if incoming location constraint is valid, reject
 	// requests which do not follow valid region requirements.
 	if s3Error := isValidLocationConstraint(r); s3Error != ErrNone {
@@ -359,18 +356,43 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// A canned ACL may be applied to the new bucket.
+	acl, s3Error := getACLFromHeader(r.Header)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	bucketLock := globalNSMutex.NewNSLock(bucket, "")
 	bucketLock.Lock()
 	defer bucketLock.Unlock()
 
 	// Proceed to creating a bucket.
-	err := objectAPI.MakeBucket(bucket)
+	err := objectAPI.MakeBucket(r.Context(), bucket)
 	if err != nil {
-		errorIf(err, "Unable to create a bucket.")
+		errorIfCtx(r.Context(), err, "Unable to create a bucket.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
+	// Object lock can only be enabled when the bucket is created.
+	if strings.EqualFold(r.Header.Get(amzBucketObjectLockEnabled), "true") {
+		lCfg := &objectLockConfig{ObjectLockEnabled: objectLockEnabled}
+		if err = globalBucketObjectLock.persistAndNotify(bucket, lCfg, objectAPI); err != nil {
+			errorIfCtx(r.Context(), err, "Unable to enable object lock.")
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+	}
+
+	if acl != "" && acl != cannedACLPrivate {
+		if err = writeBucketACL(bucket, acl, objectAPI); err != nil {
+			errorIfCtx(r.Context(), err, "Unable to save bucket ACL.")
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+	}
+
 	// Make sure to add Location information here only for bucket
 	w.Header().Set("Location", getLocation(r))
 
@@ -392,14 +414,14 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	// be loaded in memory, the remaining being put in temporary files.
 	reader, err := r.MultipartReader()
 	if err != nil {
-		errorIf(err, "Unable to initialize multipart reader.")
+		errorIfCtx(r.Context(), err, "Unable to initialize multipart reader.")
 		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
 		return
 	}
 
 	fileBody, fileName, formValues, err := extractPostPolicyFormValues(reader)
 	if err != nil {
-		errorIf(err, "Unable to parse form values.")
+		errorIfCtx(r.Context(), err, "Unable to parse form values.")
 		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
 		return
 	}
@@ -420,6 +442,14 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 		return
 	}
 
+	// IAM users must be allowed to upload the object.
+	objectURL := &url.URL{Path: path.Join("/", bucket, object)}
+	apiErr = enforceUserPolicy(getPolicyAccessKey(formValues), bucket, "s3:PutObject", objectURL, r)
+	if apiErr != ErrNone {
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
 	policyBytes, err := base64.StdEncoding.DecodeString(formValues["Policy"])
 	if err != nil {
 		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
@@ -459,15 +489,37 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	// Extract metadata to be saved from received Form.
 	metadata := extractMetadataFromForm(formValues)
 
+	// Buckets with a default retention retain uploads.
+	if s3Error := setObjectLockMetadata(http.Header{}, bucket, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Objects matching a replication rule wait to be replicated.
+	setReplicationMetadata(http.Header{}, bucket, object, metadata)
+
+	// Buckets with default encryption encrypt uploads on the fly.
+	if algorithm, keyID := getBucketEncryption(bucket); algorithm != "" {
+		objectKey, s3Error := newSSEKMSObjectKey(algorithm, keyID, bucket, object, metadata)
+		if s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		if fileBody, err = newSSEEncryptReader(fileBody, -1, objectKey, 1, "", ""); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+	}
+
 	sha256sum := ""
 
 	objectLock := globalNSMutex.NewNSLock(bucket, object)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
-	objInfo, err := objectAPI.PutObject(bucket, object, -1, fileBody, metadata, sha256sum)
+	objInfo, err := objectAPI.PutObject(r.Context(), bucket, object, -1, fileBody, metadata, sha256sum)
 	if err != nil {
-		errorIf(err, "Unable to create object.")
+		errorIfCtx(r.Context(), err, "Unable to create object.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -542,8 +594,8 @@ func (api objectAPIHandlers) HeadBucketHandler(w http.ResponseWriter, r *http.Re
 	bucketLock.RLock()
 	defer bucketLock.RUnlock()
 
-	if _, err := objectAPI.GetBucketInfo(bucket); err != nil {
-		errorIf(err, "Unable to fetch bucket info.")
+	if _, err := objectAPI.GetBucketInfo(r.Context(), bucket); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to fetch bucket info.")
 		writeErrorResponseHeadersOnly(w, toAPIErrorCode(err))
 		return
 	}
@@ -559,22 +611,22 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// DeleteBucket does not have any bucket action.
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	// DeleteBucket is not granted by bucket policies.
+	if s3Error := checkRequestAuthType(r, bucket, "s3:DeleteBucket", serverConfig.GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
-	vars := mux.Vars(r)
-	bucket := vars["bucket"]
-
 	bucketLock := globalNSMutex.NewNSLock(bucket, "")
 	bucketLock.Lock()
 	defer bucketLock.Unlock()
 
 	// Attempt to delete bucket.
-	if err := objectAPI.DeleteBucket(bucket); err != nil {
-		errorIf(err, "Unable to delete a bucket.")
+	if err := objectAPI.DeleteBucket(r.Context(), bucket); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to delete a bucket.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -588,6 +640,17 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 	// Delete listener config, if present - ignore any errors.
 	_ = removeListenerConfig(bucket, objectAPI)
 
+	// Delete bucket tags, if present - ignore any errors.
+	_ = removeBucketTagging(bucket, objectAPI)
+
+	// Delete all bucket configurations, if present - errors are only
+	// logged, a left over object lock configuration must not go unnoticed.
+	for _, bc := range globalBucketConfigs {
+		if err := bc.remove(bucket, objectAPI); err != nil && err != bc.errNoSuchConfig {
+			errorIfCtx(r.Context(), err, "Unable to remove %s configuration.", bc.desc)
+		}
+	}
+
 	// Write success response.
 	writeSuccessNoContent(w)
 }
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"io/ioutil"
 	"net/http"
@@ -632,7 +633,7 @@ func testAPIDeleteMultipleObjectsHandler(obj ObjectLayer, instanceType, bucketNa
 	for i := 0; i < 10; i++ {
 		objectName := "test-object-" + strconv.Itoa(i)
 		// uploading the object.
-		_, err = obj.PutObject(bucketName, objectName, int64(len(contentBytes)), bytes.NewBuffer(contentBytes),
+		_, err = obj.PutObject(context.Background(), bucketName, objectName, int64(len(contentBytes)), bytes.NewBuffer(contentBytes),
 			make(map[string]string), sha256sum)
 		// if object upload fails stop the test.
 		if err != nil {
@@ -0,0 +1,181 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Lifecycle configuration of up to maxLifecycleRules rules fits in
+// half a megabyte.
+const maxLifecycleConfigSize = 512 * 1024
+
+// PutBucketLifecycleHandler - PUT Bucket lifecycle
+// -----------------
+// This implementation of the PUT operation uses the lifecycle
+// subresource to replace the lifecycle rules of an existing bucket.
+func (api objectAPIHandlers) PutBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutLifecycleConfiguration", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// If Content-Length is unknown or zero, deny the request.
+	// PutBucketLifecycle always needs a Content-Length.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		return
+	}
+	if r.ContentLength > maxLifecycleConfigSize {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+
+	// Reads the incoming lifecycle configuration.
+	var buffer bytes.Buffer
+	if _, err = io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to read incoming body.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	var lCfg lifecycleConfig
+	if err = xml.Unmarshal(buffer.Bytes(), &lCfg); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to parse lifecycle configuration XML.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	if s3Error := validateLifecycleConfig(lCfg); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err = globalBucketLifecycles.persistAndNotify(bucket, &lCfg, objectAPI); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to save lifecycle configuration.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketLifecycleHandler - GET Bucket lifecycle
+// -----------------
+// This implementation of the GET operation uses the lifecycle
+// subresource to return the lifecycle rules of a bucket.
+func (api objectAPIHandlers) GetBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetLifecycleConfiguration", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	lCfg, err := globalBucketLifecycles.read(bucket, objectAPI)
+	if err != nil {
+		if err == errNoSuchLifecycleConfig {
+			writeErrorResponse(w, ErrNoSuchLifecycleConfiguration, r.URL)
+			return
+		}
+		errorIfCtx(r.Context(), err, "Unable to read lifecycle configuration.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	lifecycleBytes, err := xml.Marshal(lCfg)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to marshal lifecycle configuration into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, lifecycleBytes)
+}
+
+// DeleteBucketLifecycleHandler - DELETE Bucket lifecycle
+// -----------------
+// This implementation of the DELETE operation uses the lifecycle
+// subresource to remove all lifecycle rules of a bucket.
+func (api objectAPIHandlers) DeleteBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutLifecycleConfiguration", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Removing a non-existent configuration succeeds, like s3 does.
+	if err = globalBucketLifecycles.remove(bucket, objectAPI); err != nil && err != errNoSuchLifecycleConfig {
+		errorIfCtx(r.Context(), err, "Unable to remove lifecycle configuration.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
@@ -0,0 +1,101 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling Put/Get/DeleteBucketLifecycle handler tests for both XL multiple disks and single node setup.
+func TestBucketLifecycleHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketLifecycleHandlers, []string{
+		"PutBucketLifecycle",
+		"GetBucketLifecycle",
+		"DeleteBucketLifecycle",
+	})
+}
+
+func testBucketLifecycleHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Sends a lifecycle request and returns the recorded response.
+	sendRequest := func(method, bucket, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, getBucketConfigURL("", bucket, "lifecycle"),
+			int64(len(body)), bytes.NewReader([]byte(body)), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s lifecycle: <ERROR> %v", instanceType, method, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Never configured.
+	if rec := sendRequest("GET", bucketName, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+
+	testCases := []struct {
+		bucketName         string
+		body               string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Valid configuration.
+		{bucketName, `<LifecycleConfiguration><Rule><ID>tmp</ID><Filter><Prefix>tmp/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, http.StatusOK},
+		// Test case - 2.
+		// Invalid days.
+		{bucketName, `<LifecycleConfiguration><Rule><Status>Enabled</Status><Expiration><Days>0</Days></Expiration></Rule></LifecycleConfiguration>`, http.StatusBadRequest},
+		// Test case - 3.
+		// Malformed configuration.
+		{bucketName, `<LifecycleConfiguration><Rule>`, http.StatusBadRequest},
+		// Test case - 4.
+		// Non-existent bucket.
+		{"non-existent-bucket", `<LifecycleConfiguration><Rule><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, http.StatusNotFound},
+	}
+	for i, testCase := range testCases {
+		rec := sendRequest("PUT", testCase.bucketName, testCase.body)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Read back the valid configuration.
+	rec := sendRequest("GET", bucketName, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	lCfg := lifecycleConfig{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &lCfg); err != nil {
+		t.Fatalf("%s: Unable to parse response %s", instanceType, err)
+	}
+	if len(lCfg.Rules) != 1 || lCfg.Rules[0].ID != "tmp" || lCfg.Rules[0].prefix() != "tmp/" {
+		t.Errorf("%s: Unexpected lifecycle configuration %#v", instanceType, lCfg)
+	}
+
+	// Remove the configuration.
+	if rec = sendRequest("DELETE", bucketName, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if rec = sendRequest("GET", bucketName, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Interval between two lifecycle sweeps, lifecycle rules have a day
+// granularity so there is no need to sweep more often.
+const lifecycleSweepInterval = time.Hour
+
+// Start the background lifecycle sweeper, in a distributed setup only
+// one node needs to run it.
+func startLifecycleSweeper(objAPI ObjectLayer) {
+	go func() {
+		ticker := time.NewTicker(lifecycleSweepInterval)
+		defer ticker.Stop()
+
+		// Start with random sleep time, so as to not sweep right
+		// while the server is busy coming up.
+		time.Sleep(time.Duration(rand.Float64() * float64(lifecycleSweepInterval)))
+		for {
+			sweepBucketLifecycles(objAPI, time.Now().UTC())
+			select {
+			case <-ticker.C:
+			case <-globalServiceDoneCh:
+				return
+			}
+		}
+	}()
+}
+
+// sweepBucketLifecycles - applies the lifecycle rules of all buckets
+// as of now.
+func sweepBucketLifecycles(objAPI ObjectLayer, now time.Time) {
+	for bucket, cfg := range globalBucketLifecycles.GetAll() {
+		lCfg := cfg.(*lifecycleConfig)
+		for _, rule := range lCfg.Rules {
+			if rule.Status != lifecycleRuleEnabled {
+				continue
+			}
+			if rule.Expiration != nil {
+				expireObjects(objAPI, bucket, rule.prefix(), rule.tags(), *rule.Expiration, now)
+			}
+			if rule.AbortIncompleteMultipartUpload != nil {
+				abortIncompleteUploads(objAPI, bucket, rule.prefix(), *rule.AbortIncompleteMultipartUpload, now)
+			}
+		}
+	}
+}
+
+// expireObjects - removes all objects under prefix carrying all of the
+// given tags which are expired as of now. On versioned buckets this
+// places delete markers, just like a regular DeleteObject.
+func expireObjects(objAPI ObjectLayer, bucket, prefix string, tags []tag, expiration lifecycleExpiration, now time.Time) {
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(context.Background(), bucket, prefix, marker, "", maxObjectList)
+		if err != nil {
+			errorIf(err, "Unable to list objects of bucket %s for lifecycle expiration.", bucket)
+			return
+		}
+		for _, objInfo := range result.Objects {
+			if !expiration.isExpired(objInfo.ModTime, now) {
+				continue
+			}
+			// Listings do not carry tags on all backends, stat
+			// the object for them.
+			if len(tags) > 0 && !objectHasTags(objAPI, bucket, objInfo.Name, tags) {
+				continue
+			}
+
+			objectLock := globalNSMutex.NewNSLock(bucket, objInfo.Name)
+			objectLock.Lock()
+			err = objAPI.DeleteObject(context.Background(), bucket, objInfo.Name)
+			objectLock.Unlock()
+			// Objects retained by object lock expire once released.
+			if err != nil && !isErrObjectNotFound(err) && !isErrObjectLocked(err) {
+				errorIf(err, "Unable to expire object %s/%s.", bucket, objInfo.Name)
+			}
+		}
+		if !result.IsTruncated {
+			return
+		}
+		marker = result.NextMarker
+	}
+}
+
+// Returns true if the object carries all of the given tags.
+func objectHasTags(objAPI ObjectLayer, bucket, object string, tags []tag) bool {
+	objInfo, err := objAPI.GetObjectInfo(context.Background(), bucket, object)
+	if err != nil {
+		return false
+	}
+	objTags, err := decodeTags(objInfo.UserTags)
+	if err != nil {
+		return false
+	}
+	return containsTags(objTags, tags)
+}
+
+// abortIncompleteUploads - aborts all multipart uploads under prefix
+// which were initiated too long ago as of now.
+func abortIncompleteUploads(objAPI ObjectLayer, bucket, prefix string, abort lifecycleAbortIncompleteUpload, now time.Time) {
+	keyMarker, uploadIDMarker := "", ""
+	for {
+		result, err := objAPI.ListMultipartUploads(context.Background(), bucket, prefix, keyMarker, uploadIDMarker, "", maxUploadsList)
+		if err != nil {
+			errorIf(err, "Unable to list multipart uploads of bucket %s for lifecycle cleanup.", bucket)
+			return
+		}
+		for _, upload := range result.Uploads {
+			if !abort.isExpired(upload.Initiated, now) {
+				continue
+			}
+			err = objAPI.AbortMultipartUpload(context.Background(), bucket, upload.Object, upload.UploadID)
+			if err != nil && !isErrInvalidUploadID(err) {
+				errorIf(err, "Unable to abort multipart upload %s of %s/%s.", upload.UploadID, bucket, upload.Object)
+			}
+		}
+		if !result.IsTruncated {
+			return
+		}
+		keyMarker, uploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+	}
+}
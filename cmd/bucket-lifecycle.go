@@ -0,0 +1,239 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"errors"
+	"time"
+)
+
+const (
+	// Bucket lifecycle config name.
+	bucketLifecycleConfig = "lifecycle.xml"
+
+	// Maximum number of rules in a lifecycle configuration.
+	maxLifecycleRules = 1000
+
+	// Maximum length of a lifecycle rule ID.
+	maxLifecycleRuleIDLength = 255
+
+	// Status values of a lifecycle rule.
+	lifecycleRuleEnabled  = "Enabled"
+	lifecycleRuleDisabled = "Disabled"
+)
+
+// errNoSuchLifecycleConfig - bucket has no lifecycle configuration.
+var errNoSuchLifecycleConfig = errors.New("The specified bucket does not have a lifecycle configuration")
+
+// lifecycleConfig - represents the lifecycle rules of a bucket as set
+// by PutBucketLifecycle.
+type lifecycleConfig struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration"`
+	Rules   []lifecycleRule `xml:"Rule"`
+}
+
+// lifecycleRule - a single lifecycle rule, the objects it applies to
+// are chosen either by the legacy top level prefix or by a filter.
+type lifecycleRule struct {
+	ID                             string                          `xml:"ID,omitempty"`
+	Prefix                         string                          `xml:"Prefix,omitempty"`
+	Filter                         *lifecycleFilter                `xml:"Filter,omitempty"`
+	Status                         string                          `xml:"Status"`
+	Expiration                     *lifecycleExpiration            `xml:"Expiration,omitempty"`
+	AbortIncompleteMultipartUpload *lifecycleAbortIncompleteUpload `xml:"AbortIncompleteMultipartUpload,omitempty"`
+}
+
+// lifecycleFilter - selects the objects a rule applies to, either by
+// prefix, by a single tag or by a conjunction of both.
+type lifecycleFilter struct {
+	Prefix string              `xml:"Prefix,omitempty"`
+	Tag    *tag                `xml:"Tag,omitempty"`
+	And    *lifecycleFilterAnd `xml:"And,omitempty"`
+}
+
+// lifecycleFilterAnd - selects objects under a prefix carrying all of
+// the given tags.
+type lifecycleFilterAnd struct {
+	Prefix string `xml:"Prefix,omitempty"`
+	Tags   []tag  `xml:"Tag"`
+}
+
+// lifecycleExpiration - expires objects either a number of days after
+// their creation or on a given date.
+type lifecycleExpiration struct {
+	Days int        `xml:"Days,omitempty"`
+	Date *time.Time `xml:"Date,omitempty"`
+}
+
+// lifecycleAbortIncompleteUpload - aborts multipart uploads which were
+// not completed a number of days after their initiation.
+type lifecycleAbortIncompleteUpload struct {
+	DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+}
+
+// Returns the object name prefix the rule applies to.
+func (r lifecycleRule) prefix() string {
+	if r.Filter != nil {
+		if r.Filter.And != nil {
+			return r.Filter.And.Prefix
+		}
+		return r.Filter.Prefix
+	}
+	return r.Prefix
+}
+
+// Returns the tags an object needs to carry for the rule to apply.
+func (r lifecycleRule) tags() []tag {
+	if r.Filter == nil {
+		return nil
+	}
+	if r.Filter.Tag != nil {
+		return []tag{*r.Filter.Tag}
+	}
+	if r.Filter.And != nil {
+		return r.Filter.And.Tags
+	}
+	return nil
+}
+
+// Returns the time after which an object or upload created at modTime
+// is past the given number of days, rounded up to the next midnight
+// UTC like s3 does.
+func lifecycleDueTime(modTime time.Time, days int) time.Time {
+	due := modTime.UTC().Add(time.Duration(days) * 24 * time.Hour)
+	midnight := due.Truncate(24 * time.Hour)
+	if midnight.Equal(due) {
+		return due
+	}
+	return midnight.Add(24 * time.Hour)
+}
+
+// Returns true if an object last modified at modTime is expired at now.
+func (e lifecycleExpiration) isExpired(modTime, now time.Time) bool {
+	if e.Date != nil {
+		return !now.Before(*e.Date)
+	}
+	return !now.Before(lifecycleDueTime(modTime, e.Days))
+}
+
+// Returns true if a multipart upload initiated at initiated is to be
+// aborted at now.
+func (a lifecycleAbortIncompleteUpload) isExpired(initiated, now time.Time) bool {
+	return !now.Before(lifecycleDueTime(initiated, a.DaysAfterInitiation))
+}
+
+// Validates the filter of a lifecycle rule.
+func validateLifecycleFilter(filter lifecycleFilter) APIErrorCode {
+	// Only one of Prefix, Tag and And may be set.
+	var count int
+	if filter.Prefix != "" {
+		count++
+	}
+	if filter.Tag != nil {
+		count++
+	}
+	if filter.And != nil {
+		count++
+	}
+	if count > 1 {
+		return ErrMalformedXML
+	}
+	if filter.Tag != nil {
+		return validateTags([]tag{*filter.Tag}, 1)
+	}
+	if filter.And != nil {
+		if len(filter.And.Tags) == 0 {
+			return ErrMalformedXML
+		}
+		return validateTags(filter.And.Tags, maxObjectTags)
+	}
+	return ErrNone
+}
+
+// Validates a single lifecycle rule.
+func validateLifecycleRule(rule lifecycleRule) APIErrorCode {
+	if len(rule.ID) > maxLifecycleRuleIDLength {
+		return ErrLifecycleInvalidRuleID
+	}
+	if rule.Status != lifecycleRuleEnabled && rule.Status != lifecycleRuleDisabled {
+		return ErrMalformedXML
+	}
+	// A rule selects its objects either way, not both.
+	if rule.Filter != nil && rule.Prefix != "" {
+		return ErrMalformedXML
+	}
+	if rule.Filter != nil {
+		if s3Error := validateLifecycleFilter(*rule.Filter); s3Error != ErrNone {
+			return s3Error
+		}
+		// Multipart uploads carry no tags.
+		if len(rule.tags()) > 0 && rule.AbortIncompleteMultipartUpload != nil {
+			return ErrMalformedXML
+		}
+	}
+	// A rule needs at least one action.
+	if rule.Expiration == nil && rule.AbortIncompleteMultipartUpload == nil {
+		return ErrMalformedXML
+	}
+	if rule.Expiration != nil {
+		expiration := rule.Expiration
+		if expiration.Date != nil && expiration.Days != 0 {
+			return ErrMalformedXML
+		}
+		if expiration.Date != nil {
+			date := expiration.Date.UTC()
+			if !date.Equal(date.Truncate(24 * time.Hour)) {
+				return ErrLifecycleInvalidDate
+			}
+		} else if expiration.Days <= 0 {
+			return ErrLifecycleInvalidDays
+		}
+	}
+	if rule.AbortIncompleteMultipartUpload != nil && rule.AbortIncompleteMultipartUpload.DaysAfterInitiation <= 0 {
+		return ErrLifecycleInvalidDays
+	}
+	return ErrNone
+}
+
+// Validates lifecycle configuration.
+func validateLifecycleConfig(lCfg lifecycleConfig) APIErrorCode {
+	if len(lCfg.Rules) == 0 || len(lCfg.Rules) > maxLifecycleRules {
+		return ErrMalformedXML
+	}
+
+	ruleIDs := make(map[string]struct{})
+	for _, rule := range lCfg.Rules {
+		if s3Error := validateLifecycleRule(rule); s3Error != ErrNone {
+			return s3Error
+		}
+		if rule.ID == "" {
+			continue
+		}
+		if _, ok := ruleIDs[rule.ID]; ok {
+			return ErrLifecycleDuplicateRuleID
+		}
+		ruleIDs[rule.ID] = struct{}{}
+	}
+	return ErrNone
+}
+
+// Variable represents bucket lifecycles in memory, looked up by the
+// lifecycle sweeper.
+var globalBucketLifecycles = newBucketConfig(bucketLifecycleConfig, "lifecycle", errNoSuchLifecycleConfig, func() interface{} {
+	return &lifecycleConfig{}
+})
@@ -0,0 +1,211 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+// Tests validation of lifecycle configurations.
+func TestValidateLifecycleConfig(t *testing.T) {
+	testCases := []struct {
+		config        string
+		expectedError APIErrorCode
+	}{
+		// Test case - 1.
+		// Expiration in days with a legacy prefix.
+		{`<LifecycleConfiguration><Rule><ID>tmp</ID><Prefix>tmp/</Prefix><Status>Enabled</Status><Expiration><Days>7</Days></Expiration></Rule></LifecycleConfiguration>`, ErrNone},
+		// Test case - 2.
+		// Expiration on a date with a filter.
+		{`<LifecycleConfiguration><Rule><Filter><Prefix>logs/</Prefix></Filter><Status>Enabled</Status><Expiration><Date>2017-01-01T00:00:00.000Z</Date></Expiration></Rule></LifecycleConfiguration>`, ErrNone},
+		// Test case - 3.
+		// Multipart cleanup for the whole bucket.
+		{`<LifecycleConfiguration><Rule><Filter></Filter><Status>Disabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>1</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`, ErrNone},
+		// Test case - 4.
+		// No rules.
+		{`<LifecycleConfiguration></LifecycleConfiguration>`, ErrMalformedXML},
+		// Test case - 5.
+		// Unknown status.
+		{`<LifecycleConfiguration><Rule><Status>On</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, ErrMalformedXML},
+		// Test case - 6.
+		// No action.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status></Rule></LifecycleConfiguration>`, ErrMalformedXML},
+		// Test case - 7.
+		// Both prefix and filter.
+		{`<LifecycleConfiguration><Rule><Prefix>a</Prefix><Filter><Prefix>b</Prefix></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, ErrMalformedXML},
+		// Test case - 8.
+		// Both days and date.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status><Expiration><Days>1</Days><Date>2017-01-01T00:00:00Z</Date></Expiration></Rule></LifecycleConfiguration>`, ErrMalformedXML},
+		// Test case - 9.
+		// Date not at midnight.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status><Expiration><Date>2017-01-01T10:00:00Z</Date></Expiration></Rule></LifecycleConfiguration>`, ErrLifecycleInvalidDate},
+		// Test case - 10.
+		// Negative days.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status><Expiration><Days>-1</Days></Expiration></Rule></LifecycleConfiguration>`, ErrLifecycleInvalidDays},
+		// Test case - 11.
+		// Zero days after initiation.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>0</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`, ErrLifecycleInvalidDays},
+		// Test case - 12.
+		// Duplicate rule IDs.
+		{`<LifecycleConfiguration><Rule><ID>a</ID><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule><Rule><ID>a</ID><Status>Enabled</Status><Expiration><Days>2</Days></Expiration></Rule></LifecycleConfiguration>`, ErrLifecycleDuplicateRuleID},
+		// Test case - 13.
+		// Rule ID too long.
+		{`<LifecycleConfiguration><Rule><ID>` + string(bytes.Repeat([]byte("a"), 256)) + `</ID><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, ErrLifecycleInvalidRuleID},
+		// Test case - 14.
+		// Expiration of tagged objects.
+		{`<LifecycleConfiguration><Rule><Filter><And><Prefix>tmp/</Prefix><Tag><Key>temp</Key><Value>true</Value></Tag></And></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, ErrNone},
+		// Test case - 15.
+		// Both prefix and tag.
+		{`<LifecycleConfiguration><Rule><Filter><Prefix>tmp/</Prefix><Tag><Key>temp</Key><Value>true</Value></Tag></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, ErrMalformedXML},
+		// Test case - 16.
+		// Multipart cleanup of tagged objects.
+		{`<LifecycleConfiguration><Rule><Filter><Tag><Key>temp</Key><Value>true</Value></Tag></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>1</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`, ErrMalformedXML},
+		// Test case - 17.
+		// Reserved tag key.
+		{`<LifecycleConfiguration><Rule><Filter><Tag><Key>aws:temp</Key><Value>true</Value></Tag></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, ErrInvalidTagKey},
+	}
+
+	for i, testCase := range testCases {
+		var lCfg lifecycleConfig
+		if err := xml.Unmarshal([]byte(testCase.config), &lCfg); err != nil {
+			t.Fatalf("Test %d: Unable to parse configuration %s", i+1, err)
+		}
+		if s3Error := validateLifecycleConfig(lCfg); s3Error != testCase.expectedError {
+			t.Errorf("Test %d: Expected error %d, got %d", i+1, testCase.expectedError, s3Error)
+		}
+	}
+}
+
+// Tests rounding of lifecycle actions to midnight UTC.
+func TestLifecycleDueTime(t *testing.T) {
+	testCases := []struct {
+		modTime  time.Time
+		days     int
+		expected time.Time
+	}{
+		{time.Date(2017, 1, 1, 10, 30, 0, 0, time.UTC), 1, time.Date(2017, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 1, time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2017, 1, 31, 23, 59, 59, 0, time.UTC), 30, time.Date(2017, 3, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	for i, testCase := range testCases {
+		if due := lifecycleDueTime(testCase.modTime, testCase.days); !due.Equal(testCase.expected) {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, due)
+		}
+	}
+}
+
+// Wrapper for calling lifecycle sweeper tests for both XL multiple disks and single node setup.
+func TestSweepBucketLifecycles(t *testing.T) {
+	ExecObjectLayerTest(t, testSweepBucketLifecycles)
+}
+
+func testSweepBucketLifecycles(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "lifecycle-bucket"
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	for _, object := range []string{"tmp/a", "tmp/b", "keep/c"} {
+		if _, err := obj.PutObject(context.Background(), bucket, object, int64(len("hello")), bytes.NewBufferString("hello"), nil, ""); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+	for _, object := range []string{"tagged/f", "tagged/g"} {
+		metadata := map[string]string{}
+		if object == "tagged/f" {
+			metadata[objectTagsMetaKey] = "temp=true"
+		}
+		if _, err := obj.PutObject(context.Background(), bucket, object, int64(len("hello")), bytes.NewBufferString("hello"), metadata, ""); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+	for _, object := range []string{"tmp/d", "keep/e"} {
+		if _, err := obj.NewMultipartUpload(context.Background(), bucket, object, nil); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+
+	globalBucketLifecycles.Set(bucket, &lifecycleConfig{
+		Rules: []lifecycleRule{
+			{
+				Prefix:     "tmp/",
+				Status:     lifecycleRuleEnabled,
+				Expiration: &lifecycleExpiration{Days: 1},
+				AbortIncompleteMultipartUpload: &lifecycleAbortIncompleteUpload{
+					DaysAfterInitiation: 2,
+				},
+			},
+			{
+				Filter:     &lifecycleFilter{Prefix: "keep/"},
+				Status:     lifecycleRuleDisabled,
+				Expiration: &lifecycleExpiration{Days: 1},
+			},
+			{
+				Filter:     &lifecycleFilter{Tag: &tag{"temp", "true"}},
+				Status:     lifecycleRuleEnabled,
+				Expiration: &lifecycleExpiration{Days: 1},
+			},
+		},
+	})
+	defer globalBucketLifecycles.Set(bucket, nil)
+
+	listObjects := func() (objects []string) {
+		result, err := obj.ListObjects(context.Background(), bucket, "", "", "", 1000)
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		for _, objInfo := range result.Objects {
+			objects = append(objects, objInfo.Name)
+		}
+		return objects
+	}
+	listUploads := func() (objects []string) {
+		result, err := obj.ListMultipartUploads(context.Background(), bucket, "", "", "", "", 1000)
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		for _, upload := range result.Uploads {
+			objects = append(objects, upload.Object)
+		}
+		return objects
+	}
+
+	// Nothing is due yet.
+	sweepBucketLifecycles(obj, time.Now().UTC())
+	if objects := listObjects(); !stringSlicesEqual(objects, []string{"keep/c", "tagged/f", "tagged/g", "tmp/a", "tmp/b"}) {
+		t.Errorf("%s: Unexpected objects %v", instanceType, objects)
+	}
+
+	// Objects are expired, uploads are kept one more day.
+	sweepBucketLifecycles(obj, time.Now().UTC().Add(48*time.Hour))
+	if objects := listObjects(); !stringSlicesEqual(objects, []string{"keep/c", "tagged/g"}) {
+		t.Errorf("%s: Unexpected objects %v", instanceType, objects)
+	}
+	if uploads := listUploads(); !stringSlicesEqual(uploads, []string{"keep/e", "tmp/d"}) {
+		t.Errorf("%s: Unexpected uploads %v", instanceType, uploads)
+	}
+
+	// Uploads are aborted.
+	sweepBucketLifecycles(obj, time.Now().UTC().Add(72*time.Hour))
+	if uploads := listUploads(); !stringSlicesEqual(uploads, []string{"keep/e"}) {
+		t.Errorf("%s: Unexpected uploads %v", instanceType, uploads)
+	}
+}
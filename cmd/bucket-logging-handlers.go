@@ -0,0 +1,165 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Logging configuration can be at most 64KiB, like on s3.
+const maxLoggingConfigSize = 64 * 1024
+
+// PutBucketLoggingHandler - PUT Bucket logging
+// -----------------
+// This implementation of the PUT operation uses the logging
+// subresource to enable or disable access logging of a bucket.
+func (api objectAPIHandlers) PutBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutBucketLogging", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// If Content-Length is unknown or zero, deny the request.
+	// PutBucketLogging always needs a Content-Length.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		return
+	}
+	if r.ContentLength > maxLoggingConfigSize {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+
+	// Reads the incoming logging configuration.
+	var buffer bytes.Buffer
+	if _, err = io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to read incoming body.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	var lCfg loggingConfig
+	if err = xml.Unmarshal(buffer.Bytes(), &lCfg); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to parse logging configuration XML.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	if s3Error := validateLoggingConfig(lCfg); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// An empty logging status disables access logging.
+	if lCfg.LoggingEnabled == nil {
+		if err = globalBucketLogging.remove(bucket, objectAPI); err != nil && err != errNoSuchLoggingConfig {
+			errorIfCtx(r.Context(), err, "Unable to remove logging configuration.")
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		writeSuccessResponseHeadersOnly(w)
+		return
+	}
+
+	// Access logs can only be delivered to an existing bucket.
+	if _, err = objectAPI.GetBucketInfo(r.Context(), lCfg.LoggingEnabled.TargetBucket); err != nil {
+		if _, ok := errorCause(err).(BucketNotFound); ok {
+			writeErrorResponse(w, ErrInvalidTargetBucketForLogging, r.URL)
+			return
+		}
+		errorIfCtx(r.Context(), err, "Unable to find target bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	if err = globalBucketLogging.persistAndNotify(bucket, &lCfg, objectAPI); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to save logging configuration.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketLoggingHandler - GET Bucket logging
+// -----------------
+// This implementation of the GET operation uses the logging
+// subresource to return the access logging status of a bucket.
+func (api objectAPIHandlers) GetBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetBucketLogging", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	lCfg, err := globalBucketLogging.read(bucket, objectAPI)
+	if err != nil {
+		if err != errNoSuchLoggingConfig {
+			errorIfCtx(r.Context(), err, "Unable to read logging configuration.")
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		// Access logging is disabled, reply with an empty status.
+		lCfg = &loggingConfig{}
+	}
+
+	loggingBytes, err := xml.Marshal(lCfg)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to marshal logging configuration into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, loggingBytes)
+}
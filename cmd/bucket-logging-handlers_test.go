@@ -0,0 +1,139 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Wrapper for calling Put/GetBucketLogging handler tests for both XL multiple disks and single node setup.
+func TestBucketLoggingHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketLoggingHandlers, []string{
+		"PutBucketLogging",
+		"GetBucketLogging",
+		"GetObject",
+	})
+}
+
+func testBucketLoggingHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Logging configuration changes reach the in-memory cache through the peers.
+	initGlobalS3Peers(nil)
+
+	targetBucket := getRandomBucketName()
+	if err := obj.MakeBucket(context.Background(), targetBucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	// Sends a logging request and returns the recorded response.
+	sendRequest := func(method, bucket, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, getBucketConfigURL("", bucket, "logging"),
+			int64(len(body)), bytes.NewReader([]byte(body)), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s logging: <ERROR> %v", instanceType, method, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Never configured, logging is disabled.
+	rec := sendRequest("GET", bucketName, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	lCfg := loggingConfig{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &lCfg); err != nil {
+		t.Fatalf("%s: Unable to parse response %s", instanceType, err)
+	}
+	if lCfg.LoggingEnabled != nil {
+		t.Errorf("%s: Expected logging to be disabled, got %#v", instanceType, lCfg.LoggingEnabled)
+	}
+
+	testCases := []struct {
+		bucketName         string
+		body               string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Non-existent target bucket.
+		{bucketName, `<BucketLoggingStatus><LoggingEnabled><TargetBucket>non-existent-bucket</TargetBucket><TargetPrefix>access/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`, http.StatusBadRequest},
+		// Test case - 2.
+		// Malformed configuration.
+		{bucketName, `<BucketLoggingStatus><LoggingEnabled>`, http.StatusBadRequest},
+		// Test case - 3.
+		// Non-existent bucket.
+		{"non-existent-bucket", `<BucketLoggingStatus/>`, http.StatusNotFound},
+		// Test case - 4.
+		// Valid configuration.
+		{bucketName, `<BucketLoggingStatus><LoggingEnabled><TargetBucket>` + targetBucket + `</TargetBucket><TargetPrefix>access/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`, http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		rec = sendRequest("PUT", testCase.bucketName, testCase.body)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Read back the valid configuration.
+	rec = sendRequest("GET", bucketName, "")
+	lCfg = loggingConfig{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &lCfg); err != nil {
+		t.Fatalf("%s: Unable to parse response %s", instanceType, err)
+	}
+	if lCfg.LoggingEnabled == nil || lCfg.LoggingEnabled.TargetBucket != targetBucket || lCfg.LoggingEnabled.TargetPrefix != "access/" {
+		t.Errorf("%s: Unexpected logging configuration %#v", instanceType, lCfg)
+	}
+
+	// Requests against the bucket are recorded.
+	savedAccessLogs := globalAccessLogs
+	defer func() { globalAccessLogs = savedAccessLogs }()
+	globalAccessLogs = newAccessLogs()
+
+	rec = httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("GET", getGetObjectURL("", bucketName, "missing"),
+		0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+	}
+	setAccessLogHandler(apiRouter).ServeHTTP(rec, req)
+	records := globalAccessLogs.records[bucketName]
+	if records == nil {
+		t.Fatalf("%s: Expected the request to be recorded", instanceType)
+	}
+	record := records.String()
+	for _, field := range []string{" " + credentials.AccessKey + " ", " REST.GET.OBJECT missing ", " 404 NoSuchKey "} {
+		if !strings.Contains(record, field) {
+			t.Errorf("%s: Expected %q in record %s", instanceType, field, record)
+		}
+	}
+
+	// Disable logging.
+	if rec = sendRequest("PUT", bucketName, `<BucketLoggingStatus/>`); rec.Code != http.StatusOK {
+		t.Errorf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if getBucketLogging(bucketName) != nil {
+		t.Errorf("%s: Expected logging to be disabled", instanceType)
+	}
+}
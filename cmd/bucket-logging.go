@@ -0,0 +1,73 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+// Bucket logging config name.
+const bucketLoggingConfig = "logging.xml"
+
+// errNoSuchLoggingConfig - access logging is not enabled on the bucket.
+var errNoSuchLoggingConfig = errors.New("The specified bucket does not have access logging enabled")
+
+// loggingConfig - represents the access logging status of a bucket as
+// set by PutBucketLogging, a status without LoggingEnabled disables
+// access logging.
+type loggingConfig struct {
+	XMLName        xml.Name        `xml:"BucketLoggingStatus"`
+	LoggingEnabled *loggingEnabled `xml:"LoggingEnabled,omitempty"`
+}
+
+// loggingEnabled - bucket and prefix access logs are written to.
+type loggingEnabled struct {
+	TargetBucket string `xml:"TargetBucket"`
+	TargetPrefix string `xml:"TargetPrefix"`
+}
+
+// Validates bucket logging configuration, the target bucket is checked
+// for existence by the caller.
+func validateLoggingConfig(lCfg loggingConfig) APIErrorCode {
+	if lCfg.LoggingEnabled == nil {
+		return ErrNone
+	}
+	if lCfg.LoggingEnabled.TargetBucket == "" {
+		return ErrMalformedXML
+	}
+	if !IsValidObjectPrefix(lCfg.LoggingEnabled.TargetPrefix) {
+		return ErrInvalidObjectName
+	}
+	return ErrNone
+}
+
+// Variable represents bucket logging configurations in memory, looked
+// up on every request.
+var globalBucketLogging = newBucketConfig(bucketLoggingConfig, "logging", errNoSuchLoggingConfig, func() interface{} {
+	return &loggingConfig{}
+})
+
+// getBucketLogging - returns the logging configuration of a bucket,
+// nil if access logging is not enabled.
+func getBucketLogging(bucket string) *loggingConfig {
+	lCfg, _ := globalBucketLogging.Get(bucket).(*loggingConfig)
+	if lCfg == nil || lCfg.LoggingEnabled == nil {
+		return nil
+	}
+	return lCfg
+}
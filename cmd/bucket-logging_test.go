@@ -0,0 +1,175 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests validation of logging configurations.
+func TestValidateLoggingConfig(t *testing.T) {
+	testCases := []struct {
+		config        string
+		expectedError APIErrorCode
+	}{
+		// Test case - 1.
+		// Logging disabled.
+		{`<BucketLoggingStatus></BucketLoggingStatus>`, ErrNone},
+		// Test case - 2.
+		// Logging enabled.
+		{`<BucketLoggingStatus><LoggingEnabled><TargetBucket>logs</TargetBucket><TargetPrefix>access/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`, ErrNone},
+		// Test case - 3.
+		// Missing target bucket.
+		{`<BucketLoggingStatus><LoggingEnabled><TargetPrefix>access/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`, ErrMalformedXML},
+		// Test case - 4.
+		// Invalid target prefix.
+		{`<BucketLoggingStatus><LoggingEnabled><TargetBucket>logs</TargetBucket><TargetPrefix>a\b</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`, ErrInvalidObjectName},
+	}
+	for i, testCase := range testCases {
+		lCfg := loggingConfig{}
+		if err := xml.Unmarshal([]byte(testCase.config), &lCfg); err != nil {
+			t.Fatalf("Test %d: Unable to parse configuration %s", i+1, err)
+		}
+		if s3Error := validateLoggingConfig(lCfg); s3Error != testCase.expectedError {
+			t.Errorf("Test %d: Expected error %d, got %d", i+1, testCase.expectedError, s3Error)
+		}
+	}
+}
+
+// Tests the operation of requests in access log records.
+func TestGetAccessLogOperation(t *testing.T) {
+	testCases := []struct {
+		method            string
+		url               string
+		object            string
+		expectedOperation string
+	}{
+		// Test case - 1.
+		{"GET", "/bucket", "", "REST.GET.BUCKET"},
+		// Test case - 2.
+		{"PUT", "/bucket/object", "object", "REST.PUT.OBJECT"},
+		// Test case - 3.
+		{"PUT", "/bucket?policy", "", "REST.PUT.BUCKETPOLICY"},
+		// Test case - 4.
+		{"GET", "/bucket/object?tagging", "object", "REST.GET.OBJECT_TAGGING"},
+		// Test case - 5.
+		{"PUT", "/bucket/object?partNumber=1&uploadId=abc", "object", "REST.PUT.PART"},
+		// Test case - 6.
+		{"POST", "/bucket/object?uploadId=abc", "object", "REST.POST.UPLOAD"},
+		// Test case - 7.
+		{"POST", "/bucket?delete", "", "REST.POST.MULTI_OBJECT_DELETE"},
+		// Test case - 8.
+		{"GET", "/bucket?logging", "", "REST.GET.LOGGING_STATUS"},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.url, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		if operation := getAccessLogOperation(req, testCase.object); operation != testCase.expectedOperation {
+			t.Errorf("Test %d: Expected operation %s, got %s", i+1, testCase.expectedOperation, operation)
+		}
+	}
+}
+
+// Tests the access log record of a request.
+func TestGetAccessLogRecord(t *testing.T) {
+	req, err := http.NewRequest("GET", "http://localhost:9000/bucket/dir/my%20object?versionId=v1", nil)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	}
+	req.RemoteAddr = "10.0.0.1:54321"
+	req.Header.Set("User-Agent", "test-agent")
+
+	lw := &accessLogResponseWriter{ResponseWriter: httptest.NewRecorder()}
+	lw.Header().Set(responseRequestIDKey, "REQUESTID")
+	lw.Header().Set("Content-Length", "10")
+	lw.Header().Set("Content-Range", "bytes 0-9/100")
+	lw.WriteHeader(http.StatusPartialContent)
+	lw.Write([]byte("0123456789"))
+
+	startTime := time.Date(2017, time.March, 5, 10, 30, 0, 0, time.UTC)
+	record := getAccessLogRecord(req, lw, "bucket", "dir/my object", startTime, startTime.Add(25*time.Millisecond))
+	expected := globalMinioDefaultOwnerID + ` bucket [05/Mar/2017:10:30:00 +0000] 10.0.0.1 - REQUESTID REST.GET.OBJECT dir/my%20object "GET /bucket/dir/my%20object?versionId=v1 HTTP/1.1" 206 - 10 100 25 - "-" "test-agent" -`
+	if record != expected {
+		t.Errorf("Expected record\n%s\ngot\n%s", expected, record)
+	}
+
+	// Error responses carry the s3 error code.
+	lw = &accessLogResponseWriter{ResponseWriter: httptest.NewRecorder()}
+	lw.WriteHeader(http.StatusNotFound)
+	lw.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code></Error>`))
+	if code := lw.errorCode(); code != "NoSuchKey" {
+		t.Errorf("Expected error code NoSuchKey, got %s", code)
+	}
+}
+
+// Tests delivery of buffered access logs to the target bucket.
+func TestAccessLogsFlush(t *testing.T) {
+	ExecObjectLayerTest(t, testAccessLogsFlush)
+}
+
+func testAccessLogsFlush(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	for _, bucket := range []string{"source", "logs"} {
+		if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+	globalBucketLogging.Set("source", &loggingConfig{
+		LoggingEnabled: &loggingEnabled{TargetBucket: "logs", TargetPrefix: "access/"},
+	})
+	defer globalBucketLogging.Set("source", nil)
+
+	accessLogs := newAccessLogs()
+	accessLogs.Record("source", "first")
+	accessLogs.Record("source", "second")
+	// Records of buckets without logging are dropped.
+	accessLogs.Record("other", "dropped")
+	accessLogs.Flush(obj, time.Date(2017, time.March, 5, 10, 30, 0, 0, time.UTC))
+
+	result, err := obj.ListObjects(context.Background(), "logs", "", "", "", 10)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if len(result.Objects) != 1 {
+		t.Fatalf("%s: Expected 1 log object, got %d", instanceType, len(result.Objects))
+	}
+	name := result.Objects[0].Name
+	if !strings.HasPrefix(name, "access/2017-03-05-10-30-00-") || len(name) != len("access/2017-03-05-10-30-00-")+16 {
+		t.Errorf("%s: Unexpected log object name %s", instanceType, name)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject(context.Background(), "logs", name, 0, -1, &buffer); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if buffer.String() != "first\nsecond\n" {
+		t.Errorf("%s: Unexpected log object content %q", instanceType, buffer.String())
+	}
+
+	// Nothing is left to deliver.
+	accessLogs.Flush(obj, time.Now().UTC())
+	if result, err = obj.ListObjects(context.Background(), "logs", "", "", "", 10); err != nil || len(result.Objects) != 1 {
+		t.Errorf("%s: Expected no new log objects, got %d, %v", instanceType, len(result.Objects), err)
+	}
+}
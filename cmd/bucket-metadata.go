@@ -30,6 +30,9 @@ type BucketMetaState interface {
 	// Updates bucket policy
 	UpdateBucketPolicy(args *SetBucketPolicyPeerArgs) error
 
+	// Reloads a bucket configuration or IAM from the backend.
+	LoadConfig(args *LoadConfigPeerArgs) error
+
 	// Sends event
 	SendEvent(args *EventArgs) error
 }
@@ -88,6 +91,26 @@ func (lc *localBucketMetaState) UpdateBucketPolicy(args *SetBucketPolicyPeerArgs
 	return globalBucketPolicies.SetBucketPolicy(args.Bucket, pCh)
 }
 
+// localBucketMetaState.LoadConfig - reloads in-memory bucket
+// configuration or IAM users and policies from the backend.
+func (lc *localBucketMetaState) LoadConfig(args *LoadConfigPeerArgs) error {
+	// check if object layer is available.
+	objAPI := lc.ObjectAPI()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	if args.Config == iamConfigName {
+		return loadIAM(objAPI)
+	}
+
+	bc := getBucketConfigByName(args.Config)
+	if bc == nil {
+		return errInvalidArgument
+	}
+	return bc.load(args.Bucket, objAPI)
+}
+
 // localBucketMetaState.SendEvent - sends event to local event notifier via
 // `globalEventNotifier`
 func (lc *localBucketMetaState) SendEvent(args *EventArgs) error {
@@ -126,6 +149,13 @@ func (rc *remoteBucketMetaState) UpdateBucketPolicy(args *SetBucketPolicyPeerArg
 	return rc.Call("S3.SetBucketPolicyPeer", args, &reply)
 }
 
+// remoteBucketMetaState.LoadConfig - sends bucket configuration or
+// IAM change to remote peer via RPC call.
+func (rc *remoteBucketMetaState) LoadConfig(args *LoadConfigPeerArgs) error {
+	reply := AuthRPCReply{}
+	return rc.Call("S3.LoadConfigPeer", args, &reply)
+}
+
 // remoteBucketMetaState.SendEvent - sends event for bucket listener to remote
 // peer via RPC call.
 func (rc *remoteBucketMetaState) SendEvent(args *EventArgs) error {
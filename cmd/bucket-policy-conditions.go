@@ -0,0 +1,283 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/teamwork/minio/pkg/wildcard"
+)
+
+// Negated condition types along with the condition type they negate, a
+// negated condition matches requests without the condition key.
+var negatedConditionsType = map[string]string{
+	"StringNotEquals":           "StringEquals",
+	"StringNotEqualsIgnoreCase": "StringEqualsIgnoreCase",
+	"StringNotLike":             "StringLike",
+	"NumericNotEquals":          "NumericEquals",
+	"DateNotEquals":             "DateEquals",
+	"NotIpAddress":              "IpAddress",
+}
+
+// Condition keys with values taken from the headers of a request.
+var headerConditionKeys = map[string]string{
+	"aws:Referer":                     "Referer",
+	"aws:UserAgent":                   "User-Agent",
+	"s3:x-amz-server-side-encryption": amzServerSideEncryption,
+	"s3:x-amz-server-side-encryption-aws-kms-key-id": amzServerSideEncryptionKMSKeyID,
+	"s3:x-amz-copy-source":                           "X-Amz-Copy-Source",
+	"s3:x-amz-metadata-directive":                    "X-Amz-Metadata-Directive",
+}
+
+// Condition keys with values taken from the connection of a request or
+// from the server.
+var contextConditionKeys = set.CreateStringSet("aws:SourceIp", "aws:SecureTransport",
+	"aws:CurrentTime", "aws:EpochTime")
+
+// Returns true for the StringEquals and StringNotEquals condition types.
+func isStringEqualityCondition(conditionType string) bool {
+	conditionType = strings.TrimSuffix(conditionType, "IfExists")
+	return conditionType == "StringEquals" || conditionType == "StringNotEquals"
+}
+
+// isValidConditionValues - are the values of a condition key valid for
+// the condition type. IP address conditions are only valid for the
+// aws:SourceIp key which only supports them.
+func isValidConditionValues(conditionType, key string, values set.StringSet) (err error) {
+	conditionType = strings.TrimSuffix(conditionType, "IfExists")
+	isIPCondition := conditionType == "IpAddress" || conditionType == "NotIpAddress"
+	if isIPCondition != (key == "aws:SourceIp") && conditionType != "Null" {
+		err = fmt.Errorf("Unsupported condition type '%s' for key '%s', please validate your policy document", conditionType, key)
+		return err
+	}
+	for value := range values {
+		switch {
+		case isIPCondition:
+			_, err = parseConditionCIDR(value)
+		case strings.HasPrefix(conditionType, "Numeric"):
+			_, err = strconv.ParseFloat(value, 64)
+		case strings.HasPrefix(conditionType, "Date"):
+			_, err = parseConditionDate(value)
+		case conditionType == "Bool", conditionType == "Null":
+			_, err = strconv.ParseBool(value)
+		}
+		if err != nil {
+			err = fmt.Errorf("Invalid condition value '%s' for key '%s', please validate your policy document", value, key)
+			return err
+		}
+	}
+	return nil
+}
+
+// parseConditionCIDR - parses an IP address condition value, a single
+// IP address is a network of its own.
+func parseConditionCIDR(value string) (*net.IPNet, error) {
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %s", value)
+		}
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 8*net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, ipNet, err := net.ParseCIDR(value)
+	return ipNet, err
+}
+
+// parseConditionDate - parses a date condition value, either in ISO 8601
+// format or as seconds since the epoch.
+func parseConditionDate(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %s", value)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// conditionValueKey - returns the key of the values of a condition key
+// in the conditions of a request, which are keyed like the condition
+// keys without the `s3:` prefix.
+func conditionValueKey(key string) string {
+	return strings.TrimPrefix(key, "s3:")
+}
+
+// getConditionValues - returns the values of the condition keys for a
+// request on reqURL, the query parameters of the URL along with the
+// values taken from the request r. The request may be nil when there
+// is none to take values from, keys which are not known are left out.
+func getConditionValues(reqURL *url.URL, r *http.Request) map[string]set.StringSet {
+	conditions := make(map[string]set.StringSet)
+	for queryParam := range reqURL.Query() {
+		// Query parameters must not pose as object tags.
+		if isObjectTagConditionKey("s3:" + queryParam) {
+			continue
+		}
+		conditions[queryParam] = set.CreateStringSet(reqURL.Query().Get(queryParam))
+	}
+
+	// Nor as the keys set below.
+	for key := range headerConditionKeys {
+		delete(conditions, conditionValueKey(key))
+	}
+	for key := range contextConditionKeys {
+		delete(conditions, conditionValueKey(key))
+	}
+
+	now := time.Now().UTC()
+	conditions["aws:CurrentTime"] = set.CreateStringSet(now.Format(time.RFC3339))
+	conditions["aws:EpochTime"] = set.CreateStringSet(strconv.FormatInt(now.Unix(), 10))
+	if r == nil {
+		return conditions
+	}
+
+	conditions["aws:SecureTransport"] = set.CreateStringSet(strconv.FormatBool(r.TLS != nil))
+	sourceIP := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(sourceIP); err == nil {
+		sourceIP = host
+	}
+	if sourceIP != "" {
+		conditions["aws:SourceIp"] = set.CreateStringSet(sourceIP)
+	}
+	for key, header := range headerConditionKeys {
+		if value := r.Header.Get(header); value != "" {
+			conditions[conditionValueKey(key)] = set.CreateStringSet(value)
+		}
+	}
+	return conditions
+}
+
+// getPolicyConditionValues - returns the values of the condition keys
+// for a request on reqURL along with the tags of the request and of the
+// existing object the policy refers to.
+func getPolicyConditionValues(policy *bucketPolicy, bucket string, reqURL *url.URL, r *http.Request) map[string]set.StringSet {
+	conditions := getConditionValues(reqURL, r)
+	var reqHeader http.Header
+	if r != nil {
+		reqHeader = r.Header
+	}
+	_, object := path2BucketAndObject(reqURL.Path)
+	addObjectTagConditions(conditions, policy, bucket, object, reqHeader)
+	return conditions
+}
+
+// conditionKeyMatch - verifies a condition key of a policy statement
+// against the values of the request. The condition matches if any value
+// of the request matches any value of the statement, negated conditions
+// match if none does. Requests without the key only match negated
+// conditions and conditions suffixed by `IfExists`, the Null condition
+// checks whether the key is present.
+func conditionKeyMatch(conditionType, key string, values set.StringSet, conditions map[string]set.StringSet) bool {
+	operator := strings.TrimSuffix(conditionType, "IfExists")
+	requestValues := conditions[conditionValueKey(key)]
+	if operator == "Null" {
+		isNull := strconv.FormatBool(requestValues.IsEmpty())
+		return !values.FuncMatch(strings.EqualFold, isNull).IsEmpty()
+	}
+
+	positiveOperator, negated := negatedConditionsType[operator]
+	if !negated {
+		positiveOperator = operator
+	}
+	if requestValues.IsEmpty() {
+		return negated || operator != conditionType
+	}
+
+	for requestValue := range requestValues {
+		for value := range values {
+			if conditionValueMatch(positiveOperator, value, requestValue) {
+				return !negated
+			}
+		}
+	}
+	return negated
+}
+
+// conditionValueMatch - matches a value of the request against a value
+// of a statement for a condition type which is not negated.
+func conditionValueMatch(conditionType, value, requestValue string) bool {
+	switch conditionType {
+	case "StringEquals":
+		return value == requestValue
+	case "StringEqualsIgnoreCase", "Bool":
+		return strings.EqualFold(value, requestValue)
+	case "StringLike":
+		return wildcard.MatchSimple(value, requestValue)
+	case "IpAddress":
+		ipNet, err := parseConditionCIDR(value)
+		ip := net.ParseIP(requestValue)
+		return err == nil && ip != nil && ipNet.Contains(ip)
+	}
+
+	var cmp int
+	switch {
+	case strings.HasPrefix(conditionType, "Numeric"):
+		n, err1 := strconv.ParseFloat(value, 64)
+		requestN, err2 := strconv.ParseFloat(requestValue, 64)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if requestN < n {
+			cmp = -1
+		} else if requestN > n {
+			cmp = 1
+		}
+		conditionType = strings.TrimPrefix(conditionType, "Numeric")
+	case strings.HasPrefix(conditionType, "Date"):
+		t, err1 := parseConditionDate(value)
+		requestT, err2 := parseConditionDate(requestValue)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if requestT.Before(t) {
+			cmp = -1
+		} else if requestT.After(t) {
+			cmp = 1
+		}
+		conditionType = strings.TrimPrefix(conditionType, "Date")
+	default:
+		return false
+	}
+
+	switch conditionType {
+	case "Equals":
+		return cmp == 0
+	case "LessThan":
+		return cmp < 0
+	case "LessThanEquals":
+		return cmp <= 0
+	case "GreaterThan":
+		return cmp > 0
+	case "GreaterThanEquals":
+		return cmp >= 0
+	}
+	return false
+}
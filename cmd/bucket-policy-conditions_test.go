@@ -0,0 +1,108 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// Tests the condition values taken from a request.
+func TestGetConditionValues(t *testing.T) {
+	reqURL, err := url.Parse("/mybucket?prefix=docs/&aws:SourceIp=10.0.0.1&x-amz-server-side-encryption=AES256&RequestObjectTag/team=blue")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a request only the query parameters and the time are known.
+	conditions := getConditionValues(reqURL, nil)
+	if !conditions["prefix"].Equals(set.CreateStringSet("docs/")) {
+		t.Errorf("Expected prefix docs/, got %v", conditions["prefix"])
+	}
+	for _, key := range []string{"aws:SourceIp", "x-amz-server-side-encryption", "RequestObjectTag/team", "aws:SecureTransport"} {
+		if _, ok := conditions[key]; ok {
+			t.Errorf("Expected no value for %s, got %v", key, conditions[key])
+		}
+	}
+	if conditions["aws:CurrentTime"].IsEmpty() || conditions["aws:EpochTime"].IsEmpty() {
+		t.Errorf("Expected the current time, got %v", conditions)
+	}
+
+	r := &http.Request{
+		URL:        reqURL,
+		Header:     http.Header{},
+		RemoteAddr: "192.168.1.1:52000",
+		TLS:        &tls.ConnectionState{},
+	}
+	r.Header.Set("User-Agent", "aws-cli/1.11.13")
+	r.Header.Set(amzServerSideEncryption, "aws:kms")
+	conditions = getConditionValues(reqURL, r)
+	expected := map[string]string{
+		"prefix":                       "docs/",
+		"aws:SourceIp":                 "192.168.1.1",
+		"aws:SecureTransport":          "true",
+		"aws:UserAgent":                "aws-cli/1.11.13",
+		"x-amz-server-side-encryption": "aws:kms",
+	}
+	for key, value := range expected {
+		if !conditions[key].Equals(set.CreateStringSet(value)) {
+			t.Errorf("Expected %s for %s, got %v", value, key, conditions[key])
+		}
+	}
+	if _, ok := conditions["aws:Referer"]; ok {
+		t.Errorf("Expected no referer, got %v", conditions["aws:Referer"])
+	}
+}
+
+// Tests parsing the values of date and IP address conditions.
+func TestParseConditionValues(t *testing.T) {
+	for _, value := range []string{"2017-01-01T00:00:00Z", "2017-01-01T00:00Z", "2017-01-01", "1483228800"} {
+		date, err := parseConditionDate(value)
+		if err != nil {
+			t.Errorf("Expected %s to parse, got %v", value, err)
+			continue
+		}
+		if date.Unix() != 1483228800 {
+			t.Errorf("Expected %s to be 2017-01-01, got %v", value, date)
+		}
+	}
+	if _, err := parseConditionDate("yesterday"); err == nil {
+		t.Error("Expected yesterday not to parse")
+	}
+
+	testCases := []struct {
+		value    string
+		ip       string
+		expected bool
+	}{
+		{"10.0.0.0/8", "10.255.0.1", true},
+		{"10.0.0.0/8", "11.0.0.1", false},
+		{"192.168.1.1", "192.168.1.1", true},
+		{"192.168.1.1", "192.168.1.2", false},
+		{"2001:db8::/32", "2001:db8::1", true},
+		{"2001:db8::/32", "10.0.0.1", false},
+	}
+	for i, testCase := range testCases {
+		if matches := conditionValueMatch("IpAddress", testCase.value, testCase.ip); matches != testCase.expected {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, matches)
+		}
+	}
+}
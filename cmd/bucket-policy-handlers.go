@@ -31,21 +31,41 @@ import (
 // maximum supported access policy size.
 const maxAccessPolicySize = 20 * humanize.KiByte
 
+// policyDecision - outcome of evaluating policy statements.
+type policyDecision int
+
+const (
+	// No statement matches, the request is denied unless another
+	// policy allows it.
+	policyImplicitDeny policyDecision = iota
+	// An Allow statement matches and no Deny statement does.
+	policyAllow
+	// A Deny statement matches, the request is denied whatever
+	// other statements or policies allow.
+	policyExplicitDeny
+)
+
 // Verify if a given action is valid for the url path based on the
 // existing bucket access policy.
 func bucketPolicyEvalStatements(action string, resource string, conditions map[string]set.StringSet, statements []policyStatement) bool {
+	return bucketPolicyEval(action, resource, conditions, statements) == policyAllow
+}
+
+// Evaluates the statements of a policy for the action on the resource,
+// a matching Deny statement overrides any matching Allow statement
+// whatever the order of the statements.
+func bucketPolicyEval(action string, resource string, conditions map[string]set.StringSet, statements []policyStatement) policyDecision {
+	decision := policyImplicitDeny
 	for _, statement := range statements {
-		if bucketPolicyMatchStatement(action, resource, conditions, statement) {
-			if statement.Effect == "Allow" {
-				return true
-			}
-			// Do not uncomment kept here for readability.
-			// else statement.Effect == "Deny"
-			return false
+		if !bucketPolicyMatchStatement(action, resource, conditions, statement) {
+			continue
+		}
+		if statement.Effect == "Deny" {
+			return policyExplicitDeny
 		}
+		decision = policyAllow
 	}
-	// None match so deny.
-	return false
+	return decision
 }
 
 // Verify if action, resource and conditions match input policy statement.
@@ -80,38 +100,29 @@ func bucketPolicyResourceMatch(resource string, statement policyStatement) bool
 	return !statement.Resources.FuncMatch(resourceMatch, resource).IsEmpty()
 }
 
-// Verify if given condition matches with policy statement.
+// Verify if given condition matches with policy statement, every key
+// of every condition must match.
 func bucketPolicyConditionMatch(conditions map[string]set.StringSet, statement policyStatement) bool {
-	// Supports following conditions.
-	// - StringEquals
-	// - StringNotEquals
-	//
-	// Supported applicable condition keys for each conditions.
-	// - s3:prefix
-	// - s3:max-keys
-	var conditionMatches = true
+	// Supports the conditions listed in supportedConditionsType on
+	// the keys listed in supportedConditionsKey along with the object
+	// tag keys.
+	// - s3:ExistingObjectTag/<key>
+	// - s3:RequestObjectTag/<key>
+	// - s3:RequestObjectTagKeys
 	for condition, conditionKeyVal := range statement.Conditions {
-		if condition == "StringEquals" {
-			if !conditionKeyVal["s3:prefix"].Equals(conditions["prefix"]) {
-				conditionMatches = false
-				break
-			}
-			if !conditionKeyVal["s3:max-keys"].Equals(conditions["max-keys"]) {
-				conditionMatches = false
-				break
-			}
-		} else if condition == "StringNotEquals" {
-			if !conditionKeyVal["s3:prefix"].Equals(conditions["prefix"]) {
-				conditionMatches = false
-				break
+		if !objectTagConditionsMatch(condition, conditions, conditionKeyVal) {
+			return false
+		}
+		for key, values := range conditionKeyVal {
+			if isObjectTagConditionKey(key) {
+				continue
 			}
-			if !conditionKeyVal["s3:max-keys"].Equals(conditions["max-keys"]) {
-				conditionMatches = false
-				break
+			if !conditionKeyMatch(condition, key, values, conditions) {
+				return false
 			}
 		}
 	}
-	return conditionMatches
+	return true
 }
 
 // PutBucketPolicyHandler - PUT Bucket policy
@@ -125,18 +136,18 @@ func (api objectAPIHandlers) PutBucketPolicyHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutBucketPolicy", serverConfig.GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
-	vars := mux.Vars(r)
-	bucket := vars["bucket"]
-
 	// Before proceeding validate if bucket exists.
-	_, err := objAPI.GetBucketInfo(bucket)
+	_, err := objAPI.GetBucketInfo(r.Context(), bucket)
 	if err != nil {
-		errorIf(err, "Unable to find bucket info.")
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -158,7 +169,7 @@ func (api objectAPIHandlers) PutBucketPolicyHandler(w http.ResponseWriter, r *ht
 	// bucket policies are limited to 20KB in size, using a limit reader.
 	policyBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxAccessPolicySize))
 	if err != nil {
-		errorIf(err, "Unable to read from client.")
+		errorIfCtx(r.Context(), err, "Unable to read from client.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -184,18 +195,18 @@ func (api objectAPIHandlers) DeleteBucketPolicyHandler(w http.ResponseWriter, r
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:DeleteBucketPolicy", serverConfig.GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
-	vars := mux.Vars(r)
-	bucket := vars["bucket"]
-
 	// Before proceeding validate if bucket exists.
-	_, err := objAPI.GetBucketInfo(bucket)
+	_, err := objAPI.GetBucketInfo(r.Context(), bucket)
 	if err != nil {
-		errorIf(err, "Unable to find bucket info.")
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -227,18 +238,18 @@ func (api objectAPIHandlers) GetBucketPolicyHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, "", "", serverConfig.GetRegion()); s3Error != ErrNone {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetBucketPolicy", serverConfig.GetRegion()); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
-	vars := mux.Vars(r)
-	bucket := vars["bucket"]
-
 	// Before proceeding validate if bucket exists.
-	_, err := objAPI.GetBucketInfo(bucket)
+	_, err := objAPI.GetBucketInfo(r.Context(), bucket)
 	if err != nil {
-		errorIf(err, "Unable to find bucket info.")
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -246,7 +257,7 @@ func (api objectAPIHandlers) GetBucketPolicyHandler(w http.ResponseWriter, r *ht
 	// Read bucket access policy.
 	policy, err := readBucketPolicy(bucket, objAPI)
 	if err != nil {
-		errorIf(err, "Unable to read bucket policy.")
+		errorIfCtx(r.Context(), err, "Unable to read bucket policy.")
 		switch err.(type) {
 		case BucketPolicyNotFound:
 			writeErrorResponse(w, ErrNoSuchBucketPolicy, r.URL)
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -251,7 +252,7 @@ func testPutBucketPolicyHandler(obj ObjectLayer, instanceType, bucketName string
 	initBucketPolicies(obj)
 
 	bucketName1 := fmt.Sprintf("%s-1", bucketName)
-	if err := obj.MakeBucket(bucketName1); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -907,41 +908,158 @@ func TestBucketPolicyConditionMatch(t *testing.T) {
 			expectedMatch: false,
 		},
 		// Test case - 5.
-		// StringNotEquals condition matches.
+		// StringNotEquals condition doesn't match.
 		{
 
 			statementCondition: getStatementWithCondition("StringNotEquals", "s3:prefix", "Asia/"),
 			condition:          getInnerMap("prefix", "Asia/"),
 
-			expectedMatch: true,
+			expectedMatch: false,
 		},
 		// Test case - 6.
-		// StringNotEquals condition doesn't match.
+		// StringNotEquals condition matches.
 		{
 
 			statementCondition: getStatementWithCondition("StringNotEquals", "s3:prefix", "Asia/"),
 			condition:          getInnerMap("prefix", "Africa/"),
 
-			expectedMatch: false,
+			expectedMatch: true,
 		},
 		// Test case - 7.
-		// StringNotEquals condition matches.
+		// StringNotEquals condition doesn't match.
 		{
 
 			statementCondition: getStatementWithCondition("StringNotEquals", "s3:max-keys", "Asia/"),
 			condition:          getInnerMap("max-keys", "Asia/"),
 
-			expectedMatch: true,
+			expectedMatch: false,
 		},
 		// Test case - 8.
-		// StringNotEquals condition doesn't match.
+		// StringNotEquals condition matches.
 		{
 
 			statementCondition: getStatementWithCondition("StringNotEquals", "s3:max-keys", "Asia/"),
 			condition:          getInnerMap("max-keys", "Africa/"),
 
+			expectedMatch: true,
+		},
+		// Test case - 9.
+		// StringNotEquals condition matches requests without the key.
+		{
+
+			statementCondition: getStatementWithCondition("StringNotEquals", "s3:prefix", "Asia/"),
+			condition:          getInnerMap("max-keys", "Asia/"),
+
+			expectedMatch: true,
+		},
+		// Test case - 10.
+		// StringEquals condition doesn't match requests without the key.
+		{
+
+			statementCondition: getStatementWithCondition("StringEquals", "s3:prefix", "Asia/"),
+			condition:          getInnerMap("max-keys", "Asia/"),
+
+			expectedMatch: false,
+		},
+		// Test case - 11.
+		// StringEqualsIfExists condition matches requests without the key.
+		{
+
+			statementCondition: getStatementWithCondition("StringEqualsIfExists", "s3:prefix", "Asia/"),
+			condition:          getInnerMap("max-keys", "Asia/"),
+
+			expectedMatch: true,
+		},
+		// Test case - 12.
+		// StringLike condition matches.
+		{
+
+			statementCondition: getStatementWithCondition("StringLike", "aws:UserAgent", "aws-cli/*"),
+			condition:          getInnerMap("aws:UserAgent", "aws-cli/1.11.13 Python/2.7.12"),
+
+			expectedMatch: true,
+		},
+		// Test case - 13.
+		// StringEqualsIgnoreCase condition matches.
+		{
+
+			statementCondition: getStatementWithCondition("StringEqualsIgnoreCase", "s3:x-amz-server-side-encryption", "aes256"),
+			condition:          getInnerMap("x-amz-server-side-encryption", "AES256"),
+
+			expectedMatch: true,
+		},
+		// Test case - 14.
+		// IpAddress condition matches.
+		{
+
+			statementCondition: getStatementWithCondition("IpAddress", "aws:SourceIp", "10.0.0.0/8"),
+			condition:          getInnerMap("aws:SourceIp", "10.1.2.3"),
+
+			expectedMatch: true,
+		},
+		// Test case - 15.
+		// IpAddress condition doesn't match.
+		{
+
+			statementCondition: getStatementWithCondition("IpAddress", "aws:SourceIp", "10.0.0.0/8"),
+			condition:          getInnerMap("aws:SourceIp", "192.168.1.1"),
+
+			expectedMatch: false,
+		},
+		// Test case - 16.
+		// NotIpAddress condition matches.
+		{
+
+			statementCondition: getStatementWithCondition("NotIpAddress", "aws:SourceIp", "10.0.0.0/8"),
+			condition:          getInnerMap("aws:SourceIp", "192.168.1.1"),
+
+			expectedMatch: true,
+		},
+		// Test case - 17.
+		// Bool condition doesn't match.
+		{
+
+			statementCondition: getStatementWithCondition("Bool", "aws:SecureTransport", "true"),
+			condition:          getInnerMap("aws:SecureTransport", "false"),
+
+			expectedMatch: false,
+		},
+		// Test case - 18.
+		// DateLessThan condition matches.
+		{
+
+			statementCondition: getStatementWithCondition("DateLessThan", "aws:CurrentTime", "2017-01-01T00:00:00Z"),
+			condition:          getInnerMap("aws:CurrentTime", "2016-12-31T23:59:59Z"),
+
+			expectedMatch: true,
+		},
+		// Test case - 19.
+		// DateGreaterThan condition doesn't match.
+		{
+
+			statementCondition: getStatementWithCondition("DateGreaterThan", "aws:EpochTime", "1483228800"),
+			condition:          getInnerMap("aws:EpochTime", "1483228800"),
+
 			expectedMatch: false,
 		},
+		// Test case - 20.
+		// NumericLessThanEquals condition doesn't match.
+		{
+
+			statementCondition: getStatementWithCondition("NumericLessThanEquals", "s3:max-keys", "100"),
+			condition:          getInnerMap("max-keys", "1000"),
+
+			expectedMatch: false,
+		},
+		// Test case - 21.
+		// Null condition matches requests without the key.
+		{
+
+			statementCondition: getStatementWithCondition("Null", "aws:Referer", "true"),
+			condition:          getInnerMap("prefix", "Asia/"),
+
+			expectedMatch: true,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -32,14 +32,42 @@ import (
 // supportedActionMap - lists all the actions supported by minio.
 var supportedActionMap = set.CreateStringSet("*", "s3:*", "s3:GetObject",
 	"s3:ListBucket", "s3:PutObject", "s3:GetBucketLocation", "s3:DeleteObject",
-	"s3:AbortMultipartUpload", "s3:ListBucketMultipartUploads", "s3:ListMultipartUploadParts")
+	"s3:AbortMultipartUpload", "s3:ListBucketMultipartUploads", "s3:ListMultipartUploadParts",
+	"s3:GetObjectTagging", "s3:PutObjectTagging", "s3:DeleteObjectTagging",
+	"s3:GetObjectRetention", "s3:PutObjectRetention", "s3:GetObjectLegalHold",
+	"s3:PutObjectLegalHold", "s3:BypassGovernanceRetention",
+	"s3:GetObjectAcl", "s3:PutObjectAcl", "s3:GetBucketAcl", "s3:PutBucketAcl",
+	"s3:ListAllMyBuckets", "s3:CreateBucket", "s3:DeleteBucket",
+	"s3:GetBucketPolicy", "s3:PutBucketPolicy", "s3:DeleteBucketPolicy",
+	"s3:GetBucketNotification", "s3:PutBucketNotification", "s3:ListenBucketNotification",
+	"s3:GetBucketVersioning", "s3:PutBucketVersioning",
+	"s3:GetLifecycleConfiguration", "s3:PutLifecycleConfiguration",
+	"s3:GetBucketCORS", "s3:PutBucketCORS",
+	"s3:GetEncryptionConfiguration", "s3:PutEncryptionConfiguration",
+	"s3:GetBucketTagging", "s3:PutBucketTagging",
+	"s3:GetBucketObjectLockConfiguration", "s3:PutBucketObjectLockConfiguration",
+	"s3:GetBucketWebsite", "s3:PutBucketWebsite", "s3:DeleteBucketWebsite",
+	"s3:GetBucketLogging", "s3:PutBucketLogging",
+	"s3:GetReplicationConfiguration", "s3:PutReplicationConfiguration")
 
-// supported Conditions type.
-var supportedConditionsType = set.CreateStringSet("StringEquals", "StringNotEquals")
+// supported Conditions type, each of them may be suffixed by
+// `IfExists` to also match requests without the condition key.
+var supportedConditionsType = set.CreateStringSet(
+	"StringEquals", "StringNotEquals", "StringEqualsIgnoreCase", "StringNotEqualsIgnoreCase",
+	"StringLike", "StringNotLike",
+	"NumericEquals", "NumericNotEquals", "NumericLessThan", "NumericLessThanEquals",
+	"NumericGreaterThan", "NumericGreaterThanEquals",
+	"DateEquals", "DateNotEquals", "DateLessThan", "DateLessThanEquals",
+	"DateGreaterThan", "DateGreaterThanEquals",
+	"Bool", "IpAddress", "NotIpAddress", "Null")
 
-// Validate s3:prefix, s3:max-keys are present if not
-// supported keys for the conditions.
-var supportedConditionsKey = set.CreateStringSet("s3:prefix", "s3:max-keys")
+// Validate the condition keys are supported, object tag keys are
+// validated by isObjectTagConditionKey.
+var supportedConditionsKey = set.CreateStringSet("s3:prefix", "s3:max-keys", "s3:delimiter",
+	"s3:x-amz-server-side-encryption", "s3:x-amz-server-side-encryption-aws-kms-key-id",
+	"s3:x-amz-copy-source", "s3:x-amz-metadata-directive",
+	"aws:SourceIp", "aws:SecureTransport", "aws:Referer", "aws:UserAgent",
+	"aws:CurrentTime", "aws:EpochTime")
 
 // supportedEffectMap - supported effects.
 var supportedEffectMap = set.CreateStringSet("Allow", "Deny")
@@ -181,19 +209,27 @@ func isValidPrincipals(principal interface{}) (err error) {
 // isValidConditions - are valid conditions.
 func isValidConditions(conditions map[string]map[string]set.StringSet) (err error) {
 	// Verify conditions should be valid.
-	// Validate if stringEquals, stringNotEquals are present
+	// Validate if condition types are supported
 	// if not throw an error.
 	conditionKeyVal := make(map[string]set.StringSet)
 	for conditionType := range conditions {
-		if !supportedConditionsType.Contains(conditionType) {
+		if !supportedConditionsType.Contains(strings.TrimSuffix(conditionType, "IfExists")) {
 			err = fmt.Errorf("Unsupported condition type '%s', please validate your policy document", conditionType)
 			return err
 		}
 		for key, value := range conditions[conditionType] {
-			if !supportedConditionsKey.Contains(key) {
+			if !supportedConditionsKey.Contains(key) && !isObjectTagConditionKey(key) {
 				err = fmt.Errorf("Unsupported condition key '%s', please validate your policy document", conditionType)
 				return err
 			}
+			if err = isValidConditionValues(conditionType, key, value); err != nil {
+				return err
+			}
+			// Only string equality conditions on the same values
+			// contradict each other.
+			if !isStringEqualityCondition(conditionType) {
+				continue
+			}
 			conditionVal, ok := conditionKeyVal[key]
 			if ok && !value.Intersection(conditionVal).IsEmpty() {
 				err = fmt.Errorf("Ambigious condition values for key '%s', please validate your policy document", key)
@@ -207,9 +243,39 @@ func isValidConditions(conditions map[string]map[string]set.StringSet) (err erro
 
 // List of actions for which prefixes are not allowed.
 var invalidPrefixActions = set.StringSet{
-	"s3:GetBucketLocation":          {},
-	"s3:ListBucket":                 {},
-	"s3:ListBucketMultipartUploads": {},
+	"s3:GetBucketLocation":                {},
+	"s3:ListBucket":                       {},
+	"s3:ListBucketMultipartUploads":       {},
+	"s3:ListAllMyBuckets":                 {},
+	"s3:CreateBucket":                     {},
+	"s3:DeleteBucket":                     {},
+	"s3:GetBucketPolicy":                  {},
+	"s3:PutBucketPolicy":                  {},
+	"s3:DeleteBucketPolicy":               {},
+	"s3:GetBucketNotification":            {},
+	"s3:PutBucketNotification":            {},
+	"s3:ListenBucketNotification":         {},
+	"s3:GetBucketVersioning":              {},
+	"s3:PutBucketVersioning":              {},
+	"s3:GetLifecycleConfiguration":        {},
+	"s3:PutLifecycleConfiguration":        {},
+	"s3:GetBucketCORS":                    {},
+	"s3:PutBucketCORS":                    {},
+	"s3:GetEncryptionConfiguration":       {},
+	"s3:PutEncryptionConfiguration":       {},
+	"s3:GetBucketTagging":                 {},
+	"s3:PutBucketTagging":                 {},
+	"s3:GetBucketObjectLockConfiguration": {},
+	"s3:PutBucketObjectLockConfiguration": {},
+	"s3:GetBucketWebsite":                 {},
+	"s3:PutBucketWebsite":                 {},
+	"s3:DeleteBucketWebsite":              {},
+	"s3:GetBucketLogging":                 {},
+	"s3:PutBucketLogging":                 {},
+	"s3:GetReplicationConfiguration":      {},
+	"s3:PutReplicationConfiguration":      {},
+	"s3:GetBucketAcl":                     {},
+	"s3:PutBucketAcl":                     {},
 	// Add actions which do not honor prefixes.
 }
 
@@ -199,6 +199,11 @@ func TestIsValidActions(t *testing.T) {
 			"s3:PutObject", "s3:GetBucketLocation", "s3:DeleteObject",
 			"s3:AbortMultipartUpload", "s3:ListBucketMultipartUploads",
 			"s3:ListMultipartUploadParts"}...), nil, true},
+		// Test Case - 5.
+		// Bucket actions.
+		{set.CreateStringSet([]string{
+			"s3:ListAllMyBuckets", "s3:DeleteBucket", "s3:GetBucketPolicy",
+			"s3:PutBucketPolicy", "s3:GetObjectTagging"}...), nil, true},
 	}
 	for i, testCase := range testCases {
 		err := isValidActions(testCase.actions)
@@ -421,6 +426,12 @@ func TestIsValidConditions(t *testing.T) {
 		generateConditions("StringEquals", "s3:max-keys", "100"),
 		generateConditions("StringNotEquals", "s3:prefix", "Asia/"),
 		generateConditions("StringNotEquals", "s3:max-keys", "100"),
+		generateConditions("IpAddress", "aws:SourceIp", "10.0.0.0/8"),
+		generateConditions("BoolIfExists", "aws:SecureTransport", "true"),
+		generateConditions("IpAddress", "aws:SourceIp", "10.0.0.0/33"),
+		generateConditions("IpAddress", "s3:prefix", "10.0.0.0/8"),
+		generateConditions("DateLessThan", "aws:CurrentTime", "yesterday"),
+		generateConditions("NumericGreaterThan", "s3:max-keys", "ten"),
 	}
 
 	testCases := []struct {
@@ -466,6 +477,26 @@ func TestIsValidConditions(t *testing.T) {
 		{testConditions[10], nil, true},
 		// Test case 10.
 		{testConditions[11], nil, true},
+		// Test case - 13.
+		{testConditions[12], nil, true},
+		// Test case - 14.
+		{testConditions[13], nil, true},
+		// Test case - 15.
+		// Invalid network for IpAddress.
+		{testConditions[14], fmt.Errorf("Invalid condition value '10.0.0.0/33' for key 'aws:SourceIp', " +
+			"please validate your policy document"), false},
+		// Test case - 16.
+		// IpAddress is only supported for aws:SourceIp.
+		{testConditions[15], fmt.Errorf("Unsupported condition type 'IpAddress' for key 's3:prefix', " +
+			"please validate your policy document"), false},
+		// Test case - 17.
+		// Invalid date for DateLessThan.
+		{testConditions[16], fmt.Errorf("Invalid condition value 'yesterday' for key 'aws:CurrentTime', " +
+			"please validate your policy document"), false},
+		// Test case - 18.
+		// Invalid number for NumericGreaterThan.
+		{testConditions[17], fmt.Errorf("Invalid condition value 'ten' for key 's3:max-keys', " +
+			"please validate your policy document"), false},
 	}
 	for i, testCase := range testCases {
 		actualErr := isValidConditions(testCase.inputCondition)
@@ -0,0 +1,203 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// Bucket replication config name.
+	bucketReplicationConfig = "replication.xml"
+
+	// Maximum number of rules in a replication configuration.
+	maxReplicationRules = 1000
+
+	// Replication rule states.
+	replicationRuleEnabled  = "Enabled"
+	replicationRuleDisabled = "Disabled"
+)
+
+// Replication status of an object, saved with its metadata and
+// returned as is on GET and HEAD.
+const (
+	amzReplicationStatus = "X-Amz-Replication-Status"
+
+	// Object waits to be copied to the destination.
+	replicationPending = "PENDING"
+	// Object was copied to the destination.
+	replicationCompleted = "COMPLETED"
+	// Copying the object failed, it is retried in the background.
+	replicationFailed = "FAILED"
+	// Object is a copy written by replication, it is never replicated
+	// any further.
+	replicationReplica = "REPLICA"
+)
+
+// errNoSuchReplicationConfig - bucket has no replication configuration.
+var errNoSuchReplicationConfig = errors.New("The specified bucket does not have a replication configuration")
+
+// replicationConfig - represents the replication configuration of a
+// bucket as set by PutBucketReplication.
+type replicationConfig struct {
+	XMLName xml.Name          `xml:"ReplicationConfiguration"`
+	Role    string            `xml:"Role,omitempty"`
+	Rules   []replicationRule `xml:"Rule"`
+}
+
+// replicationRule - objects under prefix are copied to the destination,
+// the first enabled rule matching an object applies.
+type replicationRule struct {
+	ID                      string                              `xml:"ID,omitempty"`
+	Status                  string                              `xml:"Status"`
+	Prefix                  string                              `xml:"Prefix"`
+	DeleteMarkerReplication *replicationDeleteMarkerReplication `xml:"DeleteMarkerReplication,omitempty"`
+	Destination             replicationDestination              `xml:"Destination"`
+}
+
+// replicationDeleteMarkerReplication - object deletes are replicated
+// only when enabled.
+type replicationDeleteMarkerReplication struct {
+	Status string `xml:"Status"`
+}
+
+// replicationDestination - the bucket objects are copied to on a
+// remote S3 compatible server and the credentials to access it with.
+// The secret key is never returned by GetBucketReplication.
+type replicationDestination struct {
+	Bucket    string `xml:"Bucket"`
+	Endpoint  string `xml:"Endpoint"`
+	Region    string `xml:"Region,omitempty"`
+	AccessKey string `xml:"AccessKey"`
+	SecretKey string `xml:"SecretKey,omitempty"`
+}
+
+// Returns the name of the destination bucket, given either as a name
+// or as a bucket ARN.
+func (d replicationDestination) bucketName() string {
+	return strings.TrimPrefix(d.Bucket, bucketARNPrefix)
+}
+
+// Returns the region requests to the destination are signed for.
+func (d replicationDestination) region() string {
+	if d.Region == "" {
+		return "us-east-1"
+	}
+	return d.Region
+}
+
+// Returns true if object deletes are replicated by the rule.
+func (r replicationRule) replicatesDeletes() bool {
+	return r.DeleteMarkerReplication != nil && r.DeleteMarkerReplication.Status == replicationRuleEnabled
+}
+
+// Validates bucket replication configuration.
+func validateReplicationConfig(rCfg replicationConfig) APIErrorCode {
+	if len(rCfg.Rules) == 0 || len(rCfg.Rules) > maxReplicationRules {
+		return ErrReplicationInvalidRule
+	}
+	ids := make(map[string]bool)
+	for _, rule := range rCfg.Rules {
+		if rule.ID != "" {
+			if ids[rule.ID] {
+				return ErrReplicationInvalidRule
+			}
+			ids[rule.ID] = true
+		}
+		if rule.Status != replicationRuleEnabled && rule.Status != replicationRuleDisabled {
+			return ErrReplicationInvalidRule
+		}
+		if !IsValidObjectPrefix(rule.Prefix) {
+			return ErrReplicationInvalidRule
+		}
+		if dm := rule.DeleteMarkerReplication; dm != nil {
+			if dm.Status != replicationRuleEnabled && dm.Status != replicationRuleDisabled {
+				return ErrReplicationInvalidRule
+			}
+		}
+		if s3Error := validateReplicationDestination(rule.Destination); s3Error != ErrNone {
+			return s3Error
+		}
+	}
+	return ErrNone
+}
+
+// Validates the destination of a replication rule.
+func validateReplicationDestination(dest replicationDestination) APIErrorCode {
+	if !IsValidBucketName(dest.bucketName()) {
+		return ErrReplicationInvalidDestination
+	}
+	u, err := url.Parse(dest.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrReplicationInvalidDestination
+	}
+	if u.Path != "" && u.Path != "/" {
+		return ErrReplicationInvalidDestination
+	}
+	if dest.AccessKey == "" || dest.SecretKey == "" {
+		return ErrReplicationInvalidDestination
+	}
+	return ErrNone
+}
+
+// Returns the rule replicating an object, nil if no enabled rule
+// matches the object.
+func (rCfg replicationConfig) getRule(object string) *replicationRule {
+	for i, rule := range rCfg.Rules {
+		if rule.Status == replicationRuleEnabled && strings.HasPrefix(object, rule.Prefix) {
+			return &rCfg.Rules[i]
+		}
+	}
+	return nil
+}
+
+// Variable represents bucket replication configurations in memory,
+// looked up on every write.
+var globalBucketReplication = newBucketConfig(bucketReplicationConfig, "replication", errNoSuchReplicationConfig, func() interface{} {
+	return &replicationConfig{}
+})
+
+// getReplicationRule - returns the rule replicating an object, nil if
+// the object is not replicated.
+func getReplicationRule(bucket, object string) *replicationRule {
+	rCfg, _ := globalBucketReplication.Get(bucket).(*replicationConfig)
+	if rCfg == nil {
+		return nil
+	}
+	return rCfg.getRule(object)
+}
+
+// setReplicationMetadata - saves the replication status of a new
+// object into its metadata. Objects written by replication on another
+// server are marked as replicas, objects matching a replication rule
+// are pending.
+func setReplicationMetadata(header http.Header, bucket, object string, metadata map[string]string) {
+	// The status of another object is never copied along.
+	delete(metadata, amzReplicationStatus)
+
+	if header.Get(amzReplicationStatus) == replicationReplica {
+		metadata[amzReplicationStatus] = replicationReplica
+		return
+	}
+	if getReplicationRule(bucket, object) != nil {
+		metadata[amzReplicationStatus] = replicationPending
+	}
+}
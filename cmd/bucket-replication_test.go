@@ -0,0 +1,209 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Tests validation of replication configurations.
+func TestValidateReplicationConfig(t *testing.T) {
+	destination := `<Destination><Bucket>arn:aws:s3:::backup</Bucket><Endpoint>http://localhost:9001</Endpoint><AccessKey>minio</AccessKey><SecretKey>minio123</SecretKey></Destination>`
+	testCases := []struct {
+		config        string
+		expectedError APIErrorCode
+	}{
+		// Test case - 1.
+		// Valid configuration.
+		{`<ReplicationConfiguration><Rule><ID>r1</ID><Status>Enabled</Status><Prefix>docs/</Prefix>` + destination + `</Rule></ReplicationConfiguration>`, ErrNone},
+		// Test case - 2.
+		// Delete replication.
+		{`<ReplicationConfiguration><Rule><Status>Enabled</Status><Prefix></Prefix><DeleteMarkerReplication><Status>Enabled</Status></DeleteMarkerReplication>` + destination + `</Rule></ReplicationConfiguration>`, ErrNone},
+		// Test case - 3.
+		// No rules.
+		{`<ReplicationConfiguration></ReplicationConfiguration>`, ErrReplicationInvalidRule},
+		// Test case - 4.
+		// Invalid status.
+		{`<ReplicationConfiguration><Rule><Status>On</Status><Prefix></Prefix>` + destination + `</Rule></ReplicationConfiguration>`, ErrReplicationInvalidRule},
+		// Test case - 5.
+		// Duplicate rule ids.
+		{`<ReplicationConfiguration><Rule><ID>r1</ID><Status>Enabled</Status><Prefix>a/</Prefix>` + destination + `</Rule><Rule><ID>r1</ID><Status>Enabled</Status><Prefix>b/</Prefix>` + destination + `</Rule></ReplicationConfiguration>`, ErrReplicationInvalidRule},
+		// Test case - 6.
+		// Invalid destination bucket.
+		{`<ReplicationConfiguration><Rule><Status>Enabled</Status><Prefix></Prefix><Destination><Bucket>B</Bucket><Endpoint>http://localhost:9001</Endpoint><AccessKey>minio</AccessKey><SecretKey>minio123</SecretKey></Destination></Rule></ReplicationConfiguration>`, ErrReplicationInvalidDestination},
+		// Test case - 7.
+		// Invalid destination endpoint.
+		{`<ReplicationConfiguration><Rule><Status>Enabled</Status><Prefix></Prefix><Destination><Bucket>backup</Bucket><Endpoint>localhost:9001</Endpoint><AccessKey>minio</AccessKey><SecretKey>minio123</SecretKey></Destination></Rule></ReplicationConfiguration>`, ErrReplicationInvalidDestination},
+		// Test case - 8.
+		// Missing credentials.
+		{`<ReplicationConfiguration><Rule><Status>Enabled</Status><Prefix></Prefix><Destination><Bucket>backup</Bucket><Endpoint>http://localhost:9001</Endpoint></Destination></Rule></ReplicationConfiguration>`, ErrReplicationInvalidDestination},
+	}
+	for i, testCase := range testCases {
+		rCfg := replicationConfig{}
+		if err := xml.Unmarshal([]byte(testCase.config), &rCfg); err != nil {
+			t.Fatalf("Test %d: Unable to parse configuration %s", i+1, err)
+		}
+		if s3Error := validateReplicationConfig(rCfg); s3Error != testCase.expectedError {
+			t.Errorf("Test %d: Expected error %d, got %d", i+1, testCase.expectedError, s3Error)
+		}
+	}
+}
+
+// Tests the replication status saved with new objects.
+func TestSetReplicationMetadata(t *testing.T) {
+	globalBucketReplication.Set("bucket", &replicationConfig{Rules: []replicationRule{
+		{Status: replicationRuleDisabled, Prefix: "off/"},
+		{Status: replicationRuleEnabled, Prefix: "docs/"},
+	}})
+	defer globalBucketReplication.Set("bucket", nil)
+
+	testCases := []struct {
+		bucket         string
+		object         string
+		header         http.Header
+		metadata       map[string]string
+		expectedStatus string
+	}{
+		// Test case - 1.
+		// Object matching a rule.
+		{"bucket", "docs/a.txt", http.Header{}, map[string]string{}, replicationPending},
+		// Test case - 2.
+		// Object matching a disabled rule only.
+		{"bucket", "off/a.txt", http.Header{}, map[string]string{}, ""},
+		// Test case - 3.
+		// Bucket without replication.
+		{"other", "docs/a.txt", http.Header{}, map[string]string{}, ""},
+		// Test case - 4.
+		// Replica written by another server.
+		{"bucket", "docs/a.txt", http.Header{amzReplicationStatus: []string{replicationReplica}}, map[string]string{}, replicationReplica},
+		// Test case - 5.
+		// Status of a copy source is not copied.
+		{"other", "docs/a.txt", http.Header{}, map[string]string{amzReplicationStatus: replicationCompleted}, ""},
+	}
+	for i, testCase := range testCases {
+		setReplicationMetadata(testCase.header, testCase.bucket, testCase.object, testCase.metadata)
+		if status := testCase.metadata[amzReplicationStatus]; status != testCase.expectedStatus {
+			t.Errorf("Test %d: Expected status %q, got %q", i+1, testCase.expectedStatus, status)
+		}
+	}
+}
+
+// Wrapper for calling replication tests for both XL multiple disks and single node setup.
+func TestReplicateObject(t *testing.T) {
+	ExecObjectLayerTest(t, testReplicateObject)
+}
+
+func testReplicateObject(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Destination recording the replicated objects.
+	var mutex sync.Mutex
+	replicas := make(map[string][]byte)
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), signV4Algorithm+" Credential=remote/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.Method {
+		case httpPUT:
+			// Replicas are marked, they are not replicated any further.
+			if r.Header.Get(amzReplicationStatus) != replicationReplica {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			data, _ := ioutil.ReadAll(r.Body)
+			replicas[r.URL.Path] = data
+			w.WriteHeader(http.StatusOK)
+		case httpDELETE:
+			delete(replicas, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	bucket := "replicated"
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	globalBucketReplication.Set(bucket, &replicationConfig{
+		Rules: []replicationRule{{
+			Status:                  replicationRuleEnabled,
+			DeleteMarkerReplication: &replicationDeleteMarkerReplication{Status: replicationRuleEnabled},
+			Destination: replicationDestination{
+				Bucket:    "arn:aws:s3:::backup",
+				Endpoint:  server.URL,
+				AccessKey: "remote",
+				SecretKey: "remote-secret",
+			},
+		}},
+	})
+	defer globalBucketReplication.Set(bucket, nil)
+
+	metadata := map[string]string{}
+	setReplicationMetadata(http.Header{}, bucket, "dir/object", metadata)
+	objInfo, err := obj.PutObject(context.Background(), bucket, "dir/object", int64(len("hello")), bytes.NewReader([]byte("hello")), metadata, "")
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	task := replicationTask{Bucket: bucket, Object: "dir/object", ETag: objInfo.MD5Sum}
+
+	// Replication fails while the destination is down.
+	processReplication(task, obj)
+	if objInfo, err = obj.GetObjectInfo(context.Background(), bucket, "dir/object"); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if status := objInfo.UserDefined[amzReplicationStatus]; status != replicationFailed {
+		t.Errorf("%s: Expected status %s, got %s", instanceType, replicationFailed, status)
+	}
+
+	// Failed replications are retried once the destination is back.
+	mutex.Lock()
+	failing = false
+	mutex.Unlock()
+	retryFailedReplications(obj)
+	if objInfo, err = obj.GetObjectInfo(context.Background(), bucket, "dir/object"); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if status := objInfo.UserDefined[amzReplicationStatus]; status != replicationCompleted {
+		t.Errorf("%s: Expected status %s, got %s", instanceType, replicationCompleted, status)
+	}
+	if data := replicas["/backup/dir/object"]; string(data) != "hello" {
+		t.Errorf("%s: Unexpected replica %q", instanceType, string(data))
+	}
+	if tasks, tErr := takeFailedReplications(bucket, obj); tErr != nil || len(tasks) != 0 {
+		t.Errorf("%s: Expected no failed replications, got %v, %v", instanceType, tasks, tErr)
+	}
+
+	// Deletes are replicated.
+	processReplication(replicationTask{Bucket: bucket, Object: "dir/object", Delete: true}, obj)
+	if _, ok := replicas["/backup/dir/object"]; ok {
+		t.Errorf("%s: Expected the replica to be deleted", instanceType)
+	}
+}
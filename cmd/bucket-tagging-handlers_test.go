@@ -0,0 +1,101 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling Put/Get/DeleteBucketTagging handler tests for both XL multiple disks and single node setup.
+func TestBucketTaggingHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketTaggingHandlers, []string{
+		"PutBucketTagging",
+		"GetBucketTagging",
+		"DeleteBucketTagging",
+	})
+}
+
+func testBucketTaggingHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Sends a tagging request and returns the recorded response.
+	sendRequest := func(method, bucket, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, getBucketConfigURL("", bucket, "tagging"),
+			int64(len(body)), bytes.NewReader([]byte(body)), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s tagging: <ERROR> %v", instanceType, method, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Never tagged.
+	if rec := sendRequest("GET", bucketName, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+
+	testCases := []struct {
+		bucketName         string
+		body               string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Valid tags.
+		{bucketName, `<Tagging><TagSet><Tag><Key>project</Key><Value>alpha</Value></Tag></TagSet></Tagging>`, http.StatusNoContent},
+		// Test case - 2.
+		// Invalid key.
+		{bucketName, `<Tagging><TagSet><Tag><Key></Key><Value>alpha</Value></Tag></TagSet></Tagging>`, http.StatusBadRequest},
+		// Test case - 3.
+		// Malformed tags.
+		{bucketName, `<Tagging>`, http.StatusBadRequest},
+		// Test case - 4.
+		// Non-existent bucket.
+		{"non-existent-bucket", `<Tagging><TagSet><Tag><Key>project</Key><Value>alpha</Value></Tag></TagSet></Tagging>`, http.StatusNotFound},
+	}
+	for i, testCase := range testCases {
+		rec := sendRequest("PUT", testCase.bucketName, testCase.body)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Read back the valid tags.
+	rec := sendRequest("GET", bucketName, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	bTags := tagging{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &bTags); err != nil {
+		t.Fatalf("%s: Unable to parse response %s", instanceType, err)
+	}
+	if len(bTags.TagSet) != 1 || bTags.TagSet[0] != (tag{"project", "alpha"}) {
+		t.Errorf("%s: Unexpected tags %#v", instanceType, bTags)
+	}
+
+	// Remove the tags.
+	if rec = sendRequest("DELETE", bucketName, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if rec = sendRequest("GET", bucketName, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"path"
+)
+
+// Bucket tagging config name.
+const bucketTaggingConfig = "tagging.xml"
+
+// errNoSuchBucketTagging - bucket has no tags.
+var errNoSuchBucketTagging = errors.New("The specified bucket does not have any tags")
+
+// readBucketTagging - reads the tags of a bucket, returns
+// errNoSuchBucketTagging if none are set. Bucket tags are only read by
+// GetBucketTagging, they are not cached in memory.
+func readBucketTagging(bucket string, objAPI ObjectLayer) (*tagging, error) {
+	btPath := path.Join(bucketConfigPrefix, bucket, bucketTaggingConfig)
+
+	// Acquire a read lock on bucket tags before reading.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, btPath)
+	objLock.RLock()
+	defer objLock.RUnlock()
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, btPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return nil, errNoSuchBucketTagging
+		}
+		errorIf(err, "Unable to load tags for bucket %s.", bucket)
+		return nil, errorCause(err)
+	}
+
+	bTags := &tagging{}
+	if err = xml.Unmarshal(buffer.Bytes(), bTags); err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return bTags, nil
+}
+
+// writeBucketTagging - saves validated bucket tags.
+func writeBucketTagging(bucket string, bTags *tagging, objAPI ObjectLayer) error {
+	buf, err := xml.Marshal(bTags)
+	if err != nil {
+		errorIf(err, "Unable to marshal bucket tags into XML.")
+		return err
+	}
+
+	btPath := path.Join(bucketConfigPrefix, bucket, bucketTaggingConfig)
+	// Acquire a write lock on bucket tags before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, btPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	sha256Sum := getSHA256Hash(buf)
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, btPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum); err != nil {
+		errorIf(err, "Unable to write tags for bucket %s.", bucket)
+		return errorCause(err)
+	}
+	return nil
+}
+
+// Removes tagging.xml for a given bucket, used by DeleteBucketTagging
+// and DeleteBucket.
+func removeBucketTagging(bucket string, objAPI ObjectLayer) error {
+	btPath := path.Join(bucketConfigPrefix, bucket, bucketTaggingConfig)
+
+	// Acquire a write lock on bucket tags before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, btPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	if err := objAPI.DeleteObject(context.Background(), minioMetaBucket, btPath); err != nil {
+		if isErrObjectNotFound(err) {
+			return errNoSuchBucketTagging
+		}
+		return err
+	}
+	return nil
+}
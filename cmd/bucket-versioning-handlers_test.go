@@ -0,0 +1,235 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Wrapper for calling Put/GetBucketVersioning handler tests for both XL multiple disks and single node setup.
+func TestBucketVersioningHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketVersioningHandlers, []string{
+		"PutBucketVersioning",
+		"GetBucketVersioning",
+	})
+}
+
+func testBucketVersioningHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Reads back the versioning state of the bucket.
+	getStatus := func() string {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("GET", getBucketConfigURL("", bucketName, "versioning"),
+			0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for GetBucketVersioning: <ERROR> %v", instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+		}
+		vCfg := versioningConfig{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &vCfg); err != nil {
+			t.Fatalf("%s: Unable to parse response %s", instanceType, err)
+		}
+		return vCfg.Status
+	}
+
+	// Never configured.
+	if status := getStatus(); status != "" {
+		t.Errorf("%s: Expected no versioning state, got %s", instanceType, status)
+	}
+
+	testCases := []struct {
+		bucketName         string
+		body               string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Enabling versioning.
+		{bucketName, `<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Status>Enabled</Status></VersioningConfiguration>`, http.StatusOK},
+		// Test case - 2.
+		// Suspending versioning.
+		{bucketName, `<VersioningConfiguration><Status>Suspended</Status></VersioningConfiguration>`, http.StatusOK},
+		// Test case - 3.
+		// Unknown versioning state.
+		{bucketName, `<VersioningConfiguration><Status>Disabled</Status></VersioningConfiguration>`, http.StatusBadRequest},
+		// Test case - 4.
+		// Malformed configuration.
+		{bucketName, `<VersioningConfiguration><Status>`, http.StatusBadRequest},
+		// Test case - 5.
+		// Non-existent bucket.
+		{"non-existent-bucket", `<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>`, http.StatusNotFound},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("PUT", getBucketConfigURL("", testCase.bucketName, "versioning"),
+			int64(len(testCase.body)), bytes.NewReader([]byte(testCase.body)), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for PutBucketVersioning: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	if status := getStatus(); status != versioningSuspended {
+		t.Errorf("%s: Expected versioning state %s, got %s", instanceType, versioningSuspended, status)
+	}
+}
+
+// Wrapper for calling versioned object handler tests for both XL multiple disks and single node setup.
+func TestObjectVersionHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testObjectVersionHandlers, []string{
+		"ListObjectVersions",
+		"HeadObject",
+		"GetObject",
+		"DeleteObject",
+	})
+}
+
+func testObjectVersionHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+	globalBucketVersioning.Set(bucketName, &versioningConfig{Status: versioningEnabled})
+	defer globalBucketVersioning.Set(bucketName, nil)
+
+	objectName := "object"
+	objInfo, err := obj.PutObject(context.Background(), bucketName, objectName, int64(len("hello")), bytes.NewBufferString("hello"), nil, "")
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	versionID := objInfo.VersionID
+
+	// Delete places a delete marker.
+	rec := httptest.NewRecorder()
+	req, err := newTestSignedRequestV4("DELETE", getDeleteObjectURL("", bucketName, objectName),
+		0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for DeleteObject: <ERROR> %v", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if rec.Header().Get("x-amz-delete-marker") != "true" {
+		t.Errorf("%s: Expected delete marker header", instanceType)
+	}
+	markerID := rec.Header().Get("x-amz-version-id")
+	if markerID == "" {
+		t.Fatalf("%s: Expected version id header", instanceType)
+	}
+
+	// List the versions.
+	rec = httptest.NewRecorder()
+	req, err = newTestSignedRequestV4("GET", getListObjectVersionsURL("", bucketName),
+		0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for ListObjectVersions: <ERROR> %v", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	type listedVersion struct {
+		Key       string
+		VersionID string `xml:"VersionId"`
+		IsLatest  bool
+	}
+	listing := struct {
+		Versions      []listedVersion `xml:"Version"`
+		DeleteMarkers []listedVersion `xml:"DeleteMarker"`
+	}{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), &listing); err != nil {
+		t.Fatalf("%s: Unable to parse response %s", instanceType, err)
+	}
+	if len(listing.Versions) != 1 || listing.Versions[0].VersionID != versionID || listing.Versions[0].IsLatest {
+		t.Errorf("%s: Unexpected versions %#v", instanceType, listing.Versions)
+	}
+	if len(listing.DeleteMarkers) != 1 || listing.DeleteMarkers[0].VersionID != markerID || !listing.DeleteMarkers[0].IsLatest {
+		t.Errorf("%s: Unexpected delete markers %#v", instanceType, listing.DeleteMarkers)
+	}
+
+	testCases := []struct {
+		method             string
+		versionID          string
+		expectedRespStatus int
+		expectedBody       string
+	}{
+		// Test case - 1.
+		// Reading the older version.
+		{"GET", versionID, http.StatusOK, "hello"},
+		// Test case - 2.
+		// Stat of the older version.
+		{"HEAD", versionID, http.StatusOK, ""},
+		// Test case - 3.
+		// Reading the delete marker.
+		{"GET", markerID, http.StatusMethodNotAllowed, ""},
+		// Test case - 4.
+		// Stat of the delete marker.
+		{"HEAD", markerID, http.StatusMethodNotAllowed, ""},
+		// Test case - 5.
+		// Reading a non-existent version.
+		{"GET", mustGetUUID(), http.StatusNotFound, ""},
+		// Test case - 6.
+		// Current version is the delete marker.
+		{"GET", "", http.StatusNotFound, ""},
+	}
+	for i, testCase := range testCases {
+		rec = httptest.NewRecorder()
+		target := getGetObjectURL("", bucketName, objectName)
+		if testCase.versionID != "" {
+			target = getObjectVersionURL("", bucketName, objectName, testCase.versionID)
+		}
+		req, err = newTestSignedRequestV4(testCase.method, target, 0, nil, credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+			continue
+		}
+		if testCase.expectedBody != "" && rec.Body.String() != testCase.expectedBody {
+			t.Errorf("Test %d: %s: Expected body %q, got %q", i+1, instanceType, testCase.expectedBody, rec.Body.String())
+		}
+		if rec.Code == http.StatusOK && rec.Header().Get("x-amz-version-id") != testCase.versionID {
+			t.Errorf("Test %d: %s: Expected version id header %s, got %s", i+1, instanceType, testCase.versionID, rec.Header().Get("x-amz-version-id"))
+		}
+	}
+
+	// Removing the delete marker restores the object.
+	rec = httptest.NewRecorder()
+	req, err = newTestSignedRequestV4("DELETE", getObjectVersionURL("", bucketName, objectName, markerID),
+		0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for DeleteObject: <ERROR> %v", instanceType, err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if _, err = obj.GetObjectInfo(context.Background(), bucketName, objectName); err != nil {
+		t.Errorf("%s: Expected object to be restored, got %s", instanceType, err)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+const (
+	// Bucket versioning config name.
+	bucketVersioningConfig = "versioning.xml"
+
+	// Versioning states of a bucket, a bucket which never had
+	// versioning enabled has no state at all.
+	versioningEnabled   = "Enabled"
+	versioningSuspended = "Suspended"
+)
+
+// errNoSuchVersioningConfig - bucket never had versioning configured.
+var errNoSuchVersioningConfig = errors.New("The specified bucket does not have a versioning configuration")
+
+// versioningConfig - represents the versioning state of a bucket
+// as set by PutBucketVersioning.
+type versioningConfig struct {
+	XMLName xml.Name `xml:"VersioningConfiguration"`
+	Status  string   `xml:"Status,omitempty"`
+}
+
+// Validates the versioning state requested by a client.
+func (v versioningConfig) isValid() bool {
+	return v.Status == versioningEnabled || v.Status == versioningSuspended
+}
+
+// Variable represents bucket versioning states in memory, looked up by
+// the object layer on every operation which replaces or removes an object.
+var globalBucketVersioning = newBucketConfig(bucketVersioningConfig, "versioning", errNoSuchVersioningConfig, func() interface{} {
+	return &versioningConfig{}
+})
+
+// getBucketVersioningStatus - returns the versioning state of a bucket,
+// empty if versioning was never configured on it.
+func getBucketVersioningStatus(bucket string) string {
+	vCfg, ok := globalBucketVersioning.Get(bucket).(*versioningConfig)
+	if !ok {
+		return ""
+	}
+	return vCfg.Status
+}
@@ -0,0 +1,200 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Wrapper for calling Put/Get/DeleteBucketWebsite handler tests for both XL multiple disks and single node setup.
+func TestBucketWebsiteHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketWebsiteHandlers, []string{
+		"PutBucketWebsite",
+		"GetBucketWebsite",
+		"DeleteBucketWebsite",
+	})
+}
+
+func testBucketWebsiteHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Sends a website request and returns the recorded response.
+	sendRequest := func(method, bucket, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, getBucketConfigURL("", bucket, "website"),
+			int64(len(body)), bytes.NewReader([]byte(body)), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s website: <ERROR> %v", instanceType, method, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Never configured.
+	if rec := sendRequest("GET", bucketName, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+
+	testCases := []struct {
+		bucketName         string
+		body               string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Valid configuration.
+		{bucketName, `<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument><Key>error.html</Key></ErrorDocument></WebsiteConfiguration>`, http.StatusOK},
+		// Test case - 2.
+		// Invalid index document.
+		{bucketName, `<WebsiteConfiguration><IndexDocument><Suffix></Suffix></IndexDocument></WebsiteConfiguration>`, http.StatusBadRequest},
+		// Test case - 3.
+		// Malformed configuration.
+		{bucketName, `<WebsiteConfiguration><IndexDocument>`, http.StatusBadRequest},
+		// Test case - 4.
+		// Non-existent bucket.
+		{"non-existent-bucket", `<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument></WebsiteConfiguration>`, http.StatusNotFound},
+	}
+	for i, testCase := range testCases {
+		rec := sendRequest("PUT", testCase.bucketName, testCase.body)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Read back the valid configuration.
+	rec := sendRequest("GET", bucketName, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	wCfg := websiteConfig{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &wCfg); err != nil {
+		t.Fatalf("%s: Unable to parse response %s", instanceType, err)
+	}
+	if wCfg.IndexDocument == nil || wCfg.IndexDocument.Suffix != "index.html" || wCfg.ErrorDocument == nil || wCfg.ErrorDocument.Key != "error.html" {
+		t.Errorf("%s: Unexpected website configuration %#v", instanceType, wCfg)
+	}
+
+	// Remove the configuration.
+	if rec = sendRequest("DELETE", bucketName, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if rec = sendRequest("GET", bucketName, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+}
+
+// Wrapper for calling website serving tests for both XL multiple disks and single node setup.
+func TestServeWebsite(t *testing.T) {
+	ExecObjectLayerAPITest(t, testServeWebsite, []string{"GetObject"})
+}
+
+func testServeWebsite(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	savedDomain := globalWebsiteDomain
+	defer func() { globalWebsiteDomain = savedDomain }()
+	globalWebsiteDomain = "web.example.com"
+
+	for object, content := range map[string]string{
+		"index.html":      "home",
+		"docs/index.html": "docs",
+		"page.html":       "page",
+		"error.html":      "not here",
+	} {
+		if _, err := obj.PutObject(context.Background(), bucketName, object, int64(len(content)), strings.NewReader(content), nil, ""); err != nil {
+			t.Fatalf("%s : %s", instanceType, err)
+		}
+	}
+	globalBucketWebsite.Set(bucketName, &websiteConfig{
+		IndexDocument: &websiteIndexDocument{Suffix: "index.html"},
+		ErrorDocument: &websiteErrorDocument{Key: "error.html"},
+		RoutingRules: []websiteRoutingRule{
+			{Condition: &websiteCondition{KeyPrefixEquals: "old/"}, Redirect: websiteRedirect{ReplaceKeyPrefixWith: "docs/", HTTPRedirectCode: http.StatusFound}},
+		},
+	})
+	defer globalBucketWebsite.Set(bucketName, nil)
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, &bucketPolicy{
+		Version:    "1.0",
+		Statements: []policyStatement{getReadOnlyObjectStatement(bucketName, "")},
+	}})
+	defer globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{IsRemove: true})
+
+	handler := setWebsiteHandler(apiRouter)
+	testCases := []struct {
+		host             string
+		path             string
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+	}{
+		// Test case - 1.
+		// Index document of the root.
+		{bucketName + ".web.example.com", "/", http.StatusOK, "home", ""},
+		// Test case - 2.
+		// Index document of a directory.
+		{bucketName + ".web.example.com", "/docs/", http.StatusOK, "docs", ""},
+		// Test case - 3.
+		// Directory without a trailing slash.
+		{bucketName + ".web.example.com", "/docs", http.StatusFound, "", "/docs/"},
+		// Test case - 4.
+		// Regular object.
+		{bucketName + ".web.example.com:9000", "/page.html", http.StatusOK, "page", ""},
+		// Test case - 5.
+		// Missing object renders the error document.
+		{bucketName + ".web.example.com", "/missing.html", http.StatusNotFound, "not here", ""},
+		// Test case - 6.
+		// Routing rule.
+		{bucketName + ".web.example.com", "/old/index.html", http.StatusFound, "", "http://" + bucketName + ".web.example.com/docs/index.html"},
+		// Test case - 7.
+		// Bucket without a website.
+		{"other-bucket.web.example.com", "/", http.StatusNotFound, "", ""},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "http://"+testCase.host+testCase.path, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		handler.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedStatus, rec.Code)
+		}
+		if testCase.expectedBody != "" && rec.Body.String() != testCase.expectedBody {
+			t.Errorf("Test %d: %s: Expected body %q, got %q", i+1, instanceType, testCase.expectedBody, rec.Body.String())
+		}
+		if location := rec.Header().Get("Location"); location != testCase.expectedLocation {
+			t.Errorf("Test %d: %s: Expected location %q, got %q", i+1, instanceType, testCase.expectedLocation, location)
+		}
+	}
+
+	// Objects which are not public are not served.
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{IsRemove: true})
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "http://"+bucketName+".web.example.com/page.html", nil)
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request: <ERROR> %v", instanceType, err)
+	}
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, rec.Code)
+	}
+}
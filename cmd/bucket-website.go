@@ -0,0 +1,186 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+)
+
+const (
+	// Bucket website config name.
+	bucketWebsiteConfig = "website.xml"
+
+	// Maximum number of routing rules in a website configuration.
+	maxWebsiteRoutingRules = 50
+)
+
+// errNoSuchWebsiteConfig - bucket has no website configuration.
+var errNoSuchWebsiteConfig = errors.New("The specified bucket does not have a website configuration")
+
+// websiteConfig - represents the website configuration of a bucket as
+// set by PutBucketWebsite. Either all requests are redirected to
+// another host or objects are served with an index document.
+type websiteConfig struct {
+	XMLName               xml.Name              `xml:"WebsiteConfiguration"`
+	RedirectAllRequestsTo *websiteRedirectAll   `xml:"RedirectAllRequestsTo,omitempty"`
+	IndexDocument         *websiteIndexDocument `xml:"IndexDocument,omitempty"`
+	ErrorDocument         *websiteErrorDocument `xml:"ErrorDocument,omitempty"`
+	RoutingRules          []websiteRoutingRule  `xml:"RoutingRules>RoutingRule,omitempty"`
+}
+
+// websiteRedirectAll - host all requests are redirected to.
+type websiteRedirectAll struct {
+	HostName string `xml:"HostName"`
+	Protocol string `xml:"Protocol,omitempty"`
+}
+
+// websiteIndexDocument - suffix appended to requests for directories.
+type websiteIndexDocument struct {
+	Suffix string `xml:"Suffix"`
+}
+
+// websiteErrorDocument - object returned along with 4XX errors.
+type websiteErrorDocument struct {
+	Key string `xml:"Key"`
+}
+
+// websiteRoutingRule - redirects requests matching its condition, the
+// first matching rule applies.
+type websiteRoutingRule struct {
+	Condition *websiteCondition `xml:"Condition,omitempty"`
+	Redirect  websiteRedirect   `xml:"Redirect"`
+}
+
+// websiteCondition - requests matched by a routing rule. A condition
+// on the error code is matched once the object was looked up.
+type websiteCondition struct {
+	KeyPrefixEquals             string `xml:"KeyPrefixEquals,omitempty"`
+	HTTPErrorCodeReturnedEquals int    `xml:"HttpErrorCodeReturnedEquals,omitempty"`
+}
+
+// websiteRedirect - where a routing rule redirects to, unset fields
+// keep the value of the request.
+type websiteRedirect struct {
+	Protocol             string `xml:"Protocol,omitempty"`
+	HostName             string `xml:"HostName,omitempty"`
+	ReplaceKeyPrefixWith string `xml:"ReplaceKeyPrefixWith,omitempty"`
+	ReplaceKeyWith       string `xml:"ReplaceKeyWith,omitempty"`
+	HTTPRedirectCode     int    `xml:"HttpRedirectCode,omitempty"`
+}
+
+// Checks if a redirect protocol is known, empty keeps the protocol of
+// the request.
+func isValidWebsiteProtocol(protocol string) bool {
+	return protocol == "" || protocol == "http" || protocol == "https"
+}
+
+// Validates a single routing rule.
+func validateWebsiteRoutingRule(rule websiteRoutingRule) APIErrorCode {
+	if rule.Condition != nil {
+		code := rule.Condition.HTTPErrorCodeReturnedEquals
+		if rule.Condition.KeyPrefixEquals == "" && code == 0 {
+			return ErrWebsiteInvalidRoutingRule
+		}
+		if code != 0 && (code < 400 || code > 599) {
+			return ErrWebsiteInvalidRoutingRule
+		}
+	}
+	redirect := rule.Redirect
+	if redirect == (websiteRedirect{}) {
+		return ErrWebsiteInvalidRoutingRule
+	}
+	if redirect.ReplaceKeyPrefixWith != "" && redirect.ReplaceKeyWith != "" {
+		return ErrWebsiteInvalidRoutingRule
+	}
+	if !isValidWebsiteProtocol(redirect.Protocol) {
+		return ErrWebsiteInvalidRedirect
+	}
+	if code := redirect.HTTPRedirectCode; code != 0 && (code < 300 || code > 399) {
+		return ErrWebsiteInvalidRedirect
+	}
+	return ErrNone
+}
+
+// Validates website configuration.
+func validateWebsiteConfig(wCfg websiteConfig) APIErrorCode {
+	if wCfg.RedirectAllRequestsTo != nil {
+		// Redirecting all requests excludes everything else.
+		if wCfg.IndexDocument != nil || wCfg.ErrorDocument != nil || len(wCfg.RoutingRules) > 0 {
+			return ErrMalformedXML
+		}
+		if wCfg.RedirectAllRequestsTo.HostName == "" || !isValidWebsiteProtocol(wCfg.RedirectAllRequestsTo.Protocol) {
+			return ErrWebsiteInvalidRedirect
+		}
+		return ErrNone
+	}
+	if wCfg.IndexDocument == nil {
+		return ErrMalformedXML
+	}
+	if suffix := wCfg.IndexDocument.Suffix; suffix == "" || strings.Contains(suffix, slashSeparator) {
+		return ErrWebsiteInvalidIndexDocument
+	}
+	if wCfg.ErrorDocument != nil && wCfg.ErrorDocument.Key == "" {
+		return ErrMalformedXML
+	}
+	if len(wCfg.RoutingRules) > maxWebsiteRoutingRules {
+		return ErrWebsiteInvalidRoutingRule
+	}
+	for _, rule := range wCfg.RoutingRules {
+		if s3Error := validateWebsiteRoutingRule(rule); s3Error != ErrNone {
+			return s3Error
+		}
+	}
+	return ErrNone
+}
+
+// Returns true if the rule applies to the object key, errorCode is the
+// status of the object lookup, 0 before the object was looked up.
+func (rule websiteRoutingRule) matches(key string, errorCode int) bool {
+	if rule.Condition == nil {
+		return errorCode == 0
+	}
+	if !strings.HasPrefix(key, rule.Condition.KeyPrefixEquals) {
+		return false
+	}
+	return rule.Condition.HTTPErrorCodeReturnedEquals == errorCode
+}
+
+// Returns the first routing rule matching the object key and the error
+// code of its lookup, nil if there is none.
+func (wCfg websiteConfig) matchRoutingRule(key string, errorCode int) *websiteRoutingRule {
+	for i := range wCfg.RoutingRules {
+		if wCfg.RoutingRules[i].matches(key, errorCode) {
+			return &wCfg.RoutingRules[i]
+		}
+	}
+	return nil
+}
+
+// Variable represents bucket website configurations in memory, looked
+// up on every request to the website domain.
+var globalBucketWebsite = newBucketConfig(bucketWebsiteConfig, "website", errNoSuchWebsiteConfig, func() interface{} {
+	return &websiteConfig{}
+})
+
+// getBucketWebsite - returns the website configuration of a bucket, nil
+// if none is set.
+func getBucketWebsite(bucket string) *websiteConfig {
+	wCfg, _ := globalBucketWebsite.Get(bucket).(*websiteConfig)
+	return wCfg
+}
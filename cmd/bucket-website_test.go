@@ -0,0 +1,147 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+// Tests validation of website configurations.
+func TestValidateWebsiteConfig(t *testing.T) {
+	testCases := []struct {
+		config        string
+		expectedError APIErrorCode
+	}{
+		// Test case - 1.
+		// Valid configuration.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument><Key>error.html</Key></ErrorDocument></WebsiteConfiguration>`, ErrNone},
+		// Test case - 2.
+		// Valid redirect of all requests.
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName><Protocol>https</Protocol></RedirectAllRequestsTo></WebsiteConfiguration>`, ErrNone},
+		// Test case - 3.
+		// Valid routing rules.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Condition><KeyPrefixEquals>docs/</KeyPrefixEquals></Condition><Redirect><ReplaceKeyPrefixWith>documents/</ReplaceKeyPrefixWith></Redirect></RoutingRule><RoutingRule><Condition><HttpErrorCodeReturnedEquals>404</HttpErrorCodeReturnedEquals></Condition><Redirect><HostName>example.com</HostName><HttpRedirectCode>302</HttpRedirectCode></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, ErrNone},
+		// Test case - 4.
+		// No index document.
+		{`<WebsiteConfiguration><ErrorDocument><Key>error.html</Key></ErrorDocument></WebsiteConfiguration>`, ErrMalformedXML},
+		// Test case - 5.
+		// Index document with a slash.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>dir/index.html</Suffix></IndexDocument></WebsiteConfiguration>`, ErrWebsiteInvalidIndexDocument},
+		// Test case - 6.
+		// Redirect of all requests along with an index document.
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName></RedirectAllRequestsTo><IndexDocument><Suffix>index.html</Suffix></IndexDocument></WebsiteConfiguration>`, ErrMalformedXML},
+		// Test case - 7.
+		// Redirect of all requests without a host.
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><Protocol>https</Protocol></RedirectAllRequestsTo></WebsiteConfiguration>`, ErrWebsiteInvalidRedirect},
+		// Test case - 8.
+		// Unknown protocol.
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName><Protocol>ftp</Protocol></RedirectAllRequestsTo></WebsiteConfiguration>`, ErrWebsiteInvalidRedirect},
+		// Test case - 9.
+		// Routing rule without redirect.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Condition><KeyPrefixEquals>docs/</KeyPrefixEquals></Condition></RoutingRule></RoutingRules></WebsiteConfiguration>`, ErrWebsiteInvalidRoutingRule},
+		// Test case - 10.
+		// Routing rule replacing both key and prefix.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Redirect><ReplaceKeyWith>a</ReplaceKeyWith><ReplaceKeyPrefixWith>b</ReplaceKeyPrefixWith></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, ErrWebsiteInvalidRoutingRule},
+		// Test case - 11.
+		// Routing rule with an invalid redirect code.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Redirect><HostName>example.com</HostName><HttpRedirectCode>200</HttpRedirectCode></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, ErrWebsiteInvalidRedirect},
+		// Test case - 12.
+		// Routing rule matching a success code.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Condition><HttpErrorCodeReturnedEquals>200</HttpErrorCodeReturnedEquals></Condition><Redirect><HostName>example.com</HostName></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, ErrWebsiteInvalidRoutingRule},
+	}
+
+	for i, testCase := range testCases {
+		var wCfg websiteConfig
+		if err := xml.Unmarshal([]byte(testCase.config), &wCfg); err != nil {
+			t.Fatalf("Test %d: Unable to parse configuration %s", i+1, err)
+		}
+		if s3Error := validateWebsiteConfig(wCfg); s3Error != testCase.expectedError {
+			t.Errorf("Test %d: Expected error %d, got %d", i+1, testCase.expectedError, s3Error)
+		}
+	}
+}
+
+// Tests matching routing rules against requests.
+func TestWebsiteMatchRoutingRule(t *testing.T) {
+	wCfg := websiteConfig{
+		RoutingRules: []websiteRoutingRule{
+			{Condition: &websiteCondition{KeyPrefixEquals: "docs/"}, Redirect: websiteRedirect{ReplaceKeyPrefixWith: "documents/"}},
+			{Condition: &websiteCondition{HTTPErrorCodeReturnedEquals: 404}, Redirect: websiteRedirect{HostName: "example.com"}},
+			{Condition: &websiteCondition{KeyPrefixEquals: "old/", HTTPErrorCodeReturnedEquals: 403}, Redirect: websiteRedirect{ReplaceKeyWith: "denied.html"}},
+		},
+	}
+	testCases := []struct {
+		key           string
+		errorCode     int
+		expectedIndex int
+	}{
+		// Test case - 1.
+		// Matching prefix before the lookup.
+		{"docs/index.html", 0, 0},
+		// Test case - 2.
+		// No match before the lookup.
+		{"images/logo.png", 0, -1},
+		// Test case - 3.
+		// Matching error code.
+		{"images/logo.png", 404, 1},
+		// Test case - 4.
+		// Matching error code and prefix.
+		{"old/page.html", 403, 2},
+		// Test case - 5.
+		// Error code matches, prefix does not.
+		{"new/page.html", 403, -1},
+	}
+	for i, testCase := range testCases {
+		rule := wCfg.matchRoutingRule(testCase.key, testCase.errorCode)
+		switch {
+		case testCase.expectedIndex == -1 && rule != nil:
+			t.Errorf("Test %d: Expected no rule, got %#v", i+1, rule)
+		case testCase.expectedIndex != -1 && rule != &wCfg.RoutingRules[testCase.expectedIndex]:
+			t.Errorf("Test %d: Expected rule %d, got %#v", i+1, testCase.expectedIndex, rule)
+		}
+	}
+}
+
+// Tests finding the bucket of website requests.
+func TestGetWebsiteBucket(t *testing.T) {
+	savedDomain := globalWebsiteDomain
+	defer func() { globalWebsiteDomain = savedDomain }()
+
+	globalWebsiteDomain = ""
+	if bucket := getWebsiteBucket("site.web.example.com"); bucket != "" {
+		t.Errorf("Expected no website bucket without a website domain, got %q", bucket)
+	}
+
+	globalWebsiteDomain = "web.example.com"
+	testCases := []struct {
+		host           string
+		expectedBucket string
+	}{
+		{"site.web.example.com", "site"},
+		{"Site.Web.Example.com:9000", "site"},
+		{"my.site.web.example.com", "my.site"},
+		{"web.example.com", ""},
+		{"localhost:9000", ""},
+		{"site.example.com", ""},
+	}
+	for i, testCase := range testCases {
+		if bucket := getWebsiteBucket(testCase.host); bucket != testCase.expectedBucket {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.expectedBucket, bucket)
+		}
+	}
+}
@@ -74,6 +74,30 @@ func migrateConfig() error {
 	if err := migrateV12ToV13(); err != nil {
 		return err
 	}
+	// Migration version '13' to '14'.
+	if err := migrateV13ToV14(); err != nil {
+		return err
+	}
+	// Migration version '14' to '15'.
+	if err := migrateV14ToV15(); err != nil {
+		return err
+	}
+	// Migration version '15' to '16'.
+	if err := migrateV15ToV16(); err != nil {
+		return err
+	}
+	// Migration version '16' to '17'.
+	if err := migrateV16ToV17(); err != nil {
+		return err
+	}
+	// Migration version '17' to '18'.
+	if err := migrateV17ToV18(); err != nil {
+		return err
+	}
+	// Migration version '18' to '19'.
+	if err := migrateV18ToV19(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -934,3 +958,359 @@ func migrateV12ToV13() error {
 	)
 	return nil
 }
+
+// Version '13' to '14' migration. Add support for the format of the
+// console and file loggers.
+func migrateV13ToV14() error {
+	cv13, err := loadConfigV13()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Unable to load config version ‘13’. %v", err)
+	}
+	if cv13.Version != "13" {
+		return nil
+	}
+
+	// Copy over fields from V13 into V14 config struct
+	srvConfig := &serverConfigV14{}
+	srvConfig.Version = "14"
+	srvConfig.Credential = cv13.Credential
+	srvConfig.Region = cv13.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// V13 loggers have no format, each logger keeps its default format.
+	srvConfig.Logger.Console = loggerConsole{
+		Enable: cv13.Logger.Console.Enable,
+		Level:  cv13.Logger.Console.Level,
+	}
+	srvConfig.Logger.File = loggerFileV14{
+		Enable:   cv13.Logger.File.Enable,
+		Filename: cv13.Logger.File.Filename,
+		Level:    cv13.Logger.File.Level,
+	}
+	srvConfig.Notify = cv13.Notify
+
+	qc, err := quick.New(srvConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize the quick config. %v",
+			err)
+	}
+	configFile, err := getConfigFile()
+	if err != nil {
+		return fmt.Errorf("Unable to get config file. %v", err)
+	}
+
+	err = qc.Save(configFile)
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to migrate config from ‘"+
+				cv13.Version+"’ to ‘"+srvConfig.Version+
+				"’ failed. %v", err,
+		)
+	}
+
+	console.Println(
+		"Migration from version ‘" +
+			cv13.Version + "’ to ‘" + srvConfig.Version +
+			"’ completed successfully.",
+	)
+	return nil
+}
+
+// Version '14' to '15' migration. Add support for audit log targets.
+func migrateV14ToV15() error {
+	cv14, err := loadConfigV14()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Unable to load config version ‘14’. %v", err)
+	}
+	if cv14.Version != "14" {
+		return nil
+	}
+
+	// Copy over fields from V14 into V15 config struct
+	srvConfig := &serverConfigV15{}
+	srvConfig.Version = "15"
+	srvConfig.Credential = cv14.Credential
+	srvConfig.Region = cv14.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+	srvConfig.Logger = cv14.Logger
+	srvConfig.Notify = cv14.Notify
+
+	// V14 will not have an audit config. So we initialize one here.
+	srvConfig.Audit.Webhook = make(map[string]auditWebhook)
+	srvConfig.Audit.Webhook["1"] = auditWebhook{}
+	srvConfig.Audit.File = make(map[string]auditFile)
+	srvConfig.Audit.File["1"] = auditFile{}
+
+	qc, err := quick.New(srvConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize the quick config. %v",
+			err)
+	}
+	configFile, err := getConfigFile()
+	if err != nil {
+		return fmt.Errorf("Unable to get config file. %v", err)
+	}
+
+	err = qc.Save(configFile)
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to migrate config from ‘"+
+				cv14.Version+"’ to ‘"+srvConfig.Version+
+				"’ failed. %v", err,
+		)
+	}
+
+	console.Println(
+		"Migration from version ‘" +
+			cv14.Version + "’ to ‘" + srvConfig.Version +
+			"’ completed successfully.",
+	)
+	return nil
+}
+
+// Version '15' to '16' migration. Add support for the syslog logger and
+// the rotation of the file logger.
+func migrateV15ToV16() error {
+	cv15, err := loadConfigV15()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Unable to load config version ‘15’. %v", err)
+	}
+	if cv15.Version != "15" {
+		return nil
+	}
+
+	// Copy over fields from V15 into V16 config struct
+	srvConfig := &serverConfigV16{}
+	srvConfig.Version = "16"
+	srvConfig.Credential = cv15.Credential
+	srvConfig.Region = cv15.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// V15 has no syslog logger and never rotates the log file.
+	srvConfig.Logger.Console = cv15.Logger.Console
+	srvConfig.Logger.File = loggerFile{
+		Enable:   cv15.Logger.File.Enable,
+		Filename: cv15.Logger.File.Filename,
+		Level:    cv15.Logger.File.Level,
+		Format:   cv15.Logger.File.Format,
+	}
+	srvConfig.Notify = cv15.Notify
+	srvConfig.Audit = cv15.Audit
+
+	qc, err := quick.New(srvConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize the quick config. %v",
+			err)
+	}
+	configFile, err := getConfigFile()
+	if err != nil {
+		return fmt.Errorf("Unable to get config file. %v", err)
+	}
+
+	err = qc.Save(configFile)
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to migrate config from ‘"+
+				cv15.Version+"’ to ‘"+srvConfig.Version+
+				"’ failed. %v", err,
+		)
+	}
+
+	console.Println(
+		"Migration from version ‘" +
+			cv15.Version + "’ to ‘" + srvConfig.Version +
+			"’ completed successfully.",
+	)
+	return nil
+}
+
+// Version '16' to '17' migration. Add support for storage classes.
+func migrateV16ToV17() error {
+	cv16, err := loadConfigV16()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Unable to load config version ‘16’. %v", err)
+	}
+	if cv16.Version != "16" {
+		return nil
+	}
+
+	// Copy over fields from V16 into V17 config struct
+	srvConfig := &serverConfigV17{}
+	srvConfig.Version = "17"
+	srvConfig.Credential = cv16.Credential
+	srvConfig.Region = cv16.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+	srvConfig.Logger = cv16.Logger
+	srvConfig.Notify = cv16.Notify
+	srvConfig.Audit = cv16.Audit
+
+	// V16 will not have a storage class config, its zero value picks
+	// the default parity of each storage class.
+
+	qc, err := quick.New(srvConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize the quick config. %v",
+			err)
+	}
+	configFile, err := getConfigFile()
+	if err != nil {
+		return fmt.Errorf("Unable to get config file. %v", err)
+	}
+
+	err = qc.Save(configFile)
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to migrate config from ‘"+
+				cv16.Version+"’ to ‘"+srvConfig.Version+
+				"’ failed. %v", err,
+		)
+	}
+
+	console.Println(
+		"Migration from version ‘" +
+			cv16.Version + "’ to ‘" + srvConfig.Version +
+			"’ completed successfully.",
+	)
+	return nil
+}
+
+// Version '17' to '18' migration. Add support for LDAP user
+// authentication.
+func migrateV17ToV18() error {
+	cv17, err := loadConfigV17()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Unable to load config version ‘17’. %v", err)
+	}
+	if cv17.Version != "17" {
+		return nil
+	}
+
+	// Copy over fields from V17 into V18 config struct
+	srvConfig := &serverConfigV18{}
+	srvConfig.Version = "18"
+	srvConfig.Credential = cv17.Credential
+	srvConfig.Region = cv17.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+	srvConfig.Logger = cv17.Logger
+	srvConfig.Notify = cv17.Notify
+	srvConfig.Audit = cv17.Audit
+	srvConfig.StorageClass = cv17.StorageClass
+
+	// V17 will not have an LDAP config, LDAP stays disabled unless it
+	// is configured by the environment.
+
+	qc, err := quick.New(srvConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize the quick config. %v",
+			err)
+	}
+	configFile, err := getConfigFile()
+	if err != nil {
+		return fmt.Errorf("Unable to get config file. %v", err)
+	}
+
+	err = qc.Save(configFile)
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to migrate config from ‘"+
+				cv17.Version+"’ to ‘"+srvConfig.Version+
+				"’ failed. %v", err,
+		)
+	}
+
+	console.Println(
+		"Migration from version ‘" +
+			cv17.Version + "’ to ‘" + srvConfig.Version +
+			"’ completed successfully.",
+	)
+	return nil
+}
+
+// Version '18' to '19' migration. Add support for OpenID Connect user
+// authentication.
+func migrateV18ToV19() error {
+	cv18, err := loadConfigV18()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Unable to load config version ‘18’. %v", err)
+	}
+	if cv18.Version != "18" {
+		return nil
+	}
+
+	// Copy over fields from V18 into V19 config struct
+	srvConfig := &serverConfigV19{}
+	srvConfig.Version = "19"
+	srvConfig.Credential = cv18.Credential
+	srvConfig.Region = cv18.Region
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+	srvConfig.Logger = cv18.Logger
+	srvConfig.Notify = cv18.Notify
+	srvConfig.Audit = cv18.Audit
+	srvConfig.StorageClass = cv18.StorageClass
+	srvConfig.LDAP = cv18.LDAP
+
+	// V18 will not have an OpenID Connect config, OpenID Connect stays
+	// disabled unless it is configured by the environment.
+
+	qc, err := quick.New(srvConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize the quick config. %v",
+			err)
+	}
+	configFile, err := getConfigFile()
+	if err != nil {
+		return fmt.Errorf("Unable to get config file. %v", err)
+	}
+
+	err = qc.Save(configFile)
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to migrate config from ‘"+
+				cv18.Version+"’ to ‘"+srvConfig.Version+
+				"’ failed. %v", err,
+		)
+	}
+
+	console.Println(
+		"Migration from version ‘" +
+			cv18.Version + "’ to ‘" + srvConfig.Version +
+			"’ completed successfully.",
+	)
+	return nil
+}
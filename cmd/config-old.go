@@ -283,6 +283,19 @@ func loadConfigV5() (*configV5, error) {
 	return c, nil
 }
 
+// consoleLogger - console logger of config versions '6' to '13'.
+type consoleLogger struct {
+	Enable bool   `json:"enable"`
+	Level  string `json:"level"`
+}
+
+// fileLogger - file logger of config versions '6' to '13'.
+type fileLogger struct {
+	Enable   bool   `json:"enable"`
+	Filename string `json:"fileName"`
+	Level    string `json:"level"`
+}
+
 type loggerV6 struct {
 	Console consoleLogger  `json:"console"`
 	File    fileLogger     `json:"file"`
@@ -586,3 +599,262 @@ func loadConfigV12() (*serverConfigV12, error) {
 	}
 	return srvCfg, nil
 }
+
+// logger - logger config of config versions '10' to '13'.
+type logger struct {
+	Console consoleLogger `json:"console"`
+	File    fileLogger    `json:"file"`
+}
+
+// serverConfigV13 server configuration version '13' which is like
+// version '12' except it adds support for webhook notification.
+type serverConfigV13 struct {
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential `json:"credential"`
+	Region     string     `json:"region"`
+
+	// Additional error logging configuration.
+	Logger logger `json:"logger"`
+
+	// Notification queue configuration.
+	Notify notifier `json:"notify"`
+}
+
+func loadConfigV13() (*serverConfigV13, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(configFile); err != nil {
+		return nil, err
+	}
+	srvCfg := &serverConfigV13{}
+	srvCfg.Version = "13"
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.Load(configFile); err != nil {
+		return nil, err
+	}
+	return srvCfg, nil
+}
+
+// loggerFileV14 - file logger of config versions '14' and '15'.
+type loggerFileV14 struct {
+	Enable   bool   `json:"enable"`
+	Filename string `json:"fileName"`
+	Level    string `json:"level"`
+	Format   string `json:"format,omitempty"`
+}
+
+// loggerV14 - logger config of config versions '14' and '15'.
+type loggerV14 struct {
+	Console loggerConsole `json:"console"`
+	File    loggerFileV14 `json:"file"`
+}
+
+// serverConfigV14 server configuration version '14' which is like
+// version '13' except it adds support for the format of the console
+// and file loggers.
+type serverConfigV14 struct {
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential `json:"credential"`
+	Region     string     `json:"region"`
+
+	// Additional error logging configuration.
+	Logger loggerV14 `json:"logger"`
+
+	// Notification queue configuration.
+	Notify notifier `json:"notify"`
+}
+
+func loadConfigV14() (*serverConfigV14, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(configFile); err != nil {
+		return nil, err
+	}
+	srvCfg := &serverConfigV14{}
+	srvCfg.Version = "14"
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.Load(configFile); err != nil {
+		return nil, err
+	}
+	return srvCfg, nil
+}
+
+// serverConfigV15 server configuration version '15' which is like
+// version '14' except it adds support for audit log targets.
+type serverConfigV15 struct {
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential `json:"credential"`
+	Region     string     `json:"region"`
+
+	// Additional error logging configuration.
+	Logger loggerV14 `json:"logger"`
+
+	// Notification queue configuration.
+	Notify notifier `json:"notify"`
+
+	// Audit log configuration.
+	Audit audit `json:"audit"`
+}
+
+func loadConfigV15() (*serverConfigV15, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(configFile); err != nil {
+		return nil, err
+	}
+	srvCfg := &serverConfigV15{}
+	srvCfg.Version = "15"
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.Load(configFile); err != nil {
+		return nil, err
+	}
+	return srvCfg, nil
+}
+
+// serverConfigV16 server configuration version '16' which is like
+// version '15' except it adds support for the syslog logger and the
+// rotation of the file logger.
+type serverConfigV16 struct {
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential `json:"credential"`
+	Region     string     `json:"region"`
+
+	// Additional error logging configuration.
+	Logger loggerConfig `json:"logger"`
+
+	// Notification queue configuration.
+	Notify notifier `json:"notify"`
+
+	// Audit log configuration.
+	Audit audit `json:"audit"`
+}
+
+func loadConfigV16() (*serverConfigV16, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(configFile); err != nil {
+		return nil, err
+	}
+	srvCfg := &serverConfigV16{}
+	srvCfg.Version = "16"
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.Load(configFile); err != nil {
+		return nil, err
+	}
+	return srvCfg, nil
+}
+
+// serverConfigV17 server configuration version '17' which is like
+// version '16' except it adds support for storage classes.
+type serverConfigV17 struct {
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential `json:"credential"`
+	Region     string     `json:"region"`
+
+	// Additional error logging configuration.
+	Logger loggerConfig `json:"logger"`
+
+	// Notification queue configuration.
+	Notify notifier `json:"notify"`
+
+	// Audit log configuration.
+	Audit audit `json:"audit"`
+
+	// Storage class configuration.
+	StorageClass storageClassConfig `json:"storageclass"`
+}
+
+func loadConfigV17() (*serverConfigV17, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(configFile); err != nil {
+		return nil, err
+	}
+	srvCfg := &serverConfigV17{}
+	srvCfg.Version = "17"
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.Load(configFile); err != nil {
+		return nil, err
+	}
+	return srvCfg, nil
+}
+
+// serverConfigV18 server configuration version '18' which is like
+// version '17' except it adds support for LDAP user authentication.
+type serverConfigV18 struct {
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential credential `json:"credential"`
+	Region     string     `json:"region"`
+
+	// Additional error logging configuration.
+	Logger loggerConfig `json:"logger"`
+
+	// Notification queue configuration.
+	Notify notifier `json:"notify"`
+
+	// Audit log configuration.
+	Audit audit `json:"audit"`
+
+	// Storage class configuration.
+	StorageClass storageClassConfig `json:"storageclass"`
+
+	// LDAP user authentication configuration.
+	LDAP ldapConfig `json:"ldap"`
+}
+
+func loadConfigV18() (*serverConfigV18, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(configFile); err != nil {
+		return nil, err
+	}
+	srvCfg := &serverConfigV18{}
+	srvCfg.Version = "18"
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.Load(configFile); err != nil {
+		return nil, err
+	}
+	return srvCfg, nil
+}
@@ -26,9 +26,10 @@ import (
 // Read Write mutex for safe access to ServerConfig.
 var serverConfigMu sync.RWMutex
 
-// serverConfigV13 server configuration version '13' which is like
-// version '12' except it adds support for webhook notification.
-type serverConfigV13 struct {
+// serverConfigV19 server configuration version '19' which is like
+// version '18' except it adds support for OpenID Connect user
+// authentication.
+type serverConfigV19 struct {
 	Version string `json:"version"`
 
 	// S3 API configuration.
@@ -36,10 +37,22 @@ type serverConfigV13 struct {
 	Region     string     `json:"region"`
 
 	// Additional error logging configuration.
-	Logger logger `json:"logger"`
+	Logger loggerConfig `json:"logger"`
 
 	// Notification queue configuration.
 	Notify notifier `json:"notify"`
+
+	// Audit log configuration.
+	Audit audit `json:"audit"`
+
+	// Storage class configuration.
+	StorageClass storageClassConfig `json:"storageclass"`
+
+	// LDAP user authentication configuration.
+	LDAP ldapConfig `json:"ldap"`
+
+	// OpenID Connect user authentication configuration.
+	OpenID openIDConfig `json:"openid"`
 }
 
 // initConfig - initialize server config and indicate if we are
@@ -47,13 +60,13 @@ type serverConfigV13 struct {
 func initConfig() (bool, error) {
 	if !isConfigFileExists() {
 		// Initialize server config.
-		srvCfg := &serverConfigV13{}
+		srvCfg := &serverConfigV19{}
 		srvCfg.Version = globalMinioConfigVersion
 		srvCfg.Region = globalMinioDefaultRegion
 		srvCfg.Credential = newCredential()
 
 		// Enable console logger by default on a fresh run.
-		srvCfg.Logger.Console = consoleLogger{
+		srvCfg.Logger.Console = loggerConsole{
 			Enable: true,
 			Level:  "error",
 		}
@@ -74,6 +87,12 @@ func initConfig() (bool, error) {
 		srvCfg.Notify.Webhook = make(map[string]webhookNotify)
 		srvCfg.Notify.Webhook["1"] = webhookNotify{}
 
+		// Make sure to initialize audit configs.
+		srvCfg.Audit.Webhook = make(map[string]auditWebhook)
+		srvCfg.Audit.Webhook["1"] = auditWebhook{}
+		srvCfg.Audit.File = make(map[string]auditFile)
+		srvCfg.Audit.File["1"] = auditFile{}
+
 		// Create config path.
 		err := createConfigPath()
 		if err != nil {
@@ -97,7 +116,7 @@ func initConfig() (bool, error) {
 	if _, err = os.Stat(configFile); err != nil {
 		return false, err
 	}
-	srvCfg := &serverConfigV13{}
+	srvCfg := &serverConfigV19{}
 	srvCfg.Version = globalMinioConfigVersion
 	qc, err := quick.New(srvCfg)
 	if err != nil {
@@ -118,11 +137,29 @@ func initConfig() (bool, error) {
 	return false, nil
 }
 
+// loadLoggerConfig - reads the logger config of the config file.
+func loadLoggerConfig() (loggerConfig, error) {
+	configFile, err := getConfigFile()
+	if err != nil {
+		return loggerConfig{}, err
+	}
+	srvCfg := &serverConfigV19{}
+	srvCfg.Version = globalMinioConfigVersion
+	qc, err := quick.New(srvCfg)
+	if err != nil {
+		return loggerConfig{}, err
+	}
+	if err = qc.Load(configFile); err != nil {
+		return loggerConfig{}, err
+	}
+	return srvCfg.Logger, nil
+}
+
 // serverConfig server config.
-var serverConfig *serverConfigV13
+var serverConfig *serverConfigV19
 
 // GetVersion get current config version.
-func (s serverConfigV13) GetVersion() string {
+func (s serverConfigV19) GetVersion() string {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -131,14 +168,14 @@ func (s serverConfigV13) GetVersion() string {
 
 /// Logger related.
 
-func (s *serverConfigV13) SetAMQPNotifyByID(accountID string, amqpn amqpNotify) {
+func (s *serverConfigV19) SetAMQPNotifyByID(accountID string, amqpn amqpNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.AMQP[accountID] = amqpn
 }
 
-func (s serverConfigV13) GetAMQP() map[string]amqpNotify {
+func (s serverConfigV19) GetAMQP() map[string]amqpNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -146,7 +183,7 @@ func (s serverConfigV13) GetAMQP() map[string]amqpNotify {
 }
 
 // GetAMQPNotify get current AMQP logger.
-func (s serverConfigV13) GetAMQPNotifyByID(accountID string) amqpNotify {
+func (s serverConfigV19) GetAMQPNotifyByID(accountID string) amqpNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -154,35 +191,35 @@ func (s serverConfigV13) GetAMQPNotifyByID(accountID string) amqpNotify {
 }
 
 //
-func (s *serverConfigV13) SetNATSNotifyByID(accountID string, natsn natsNotify) {
+func (s *serverConfigV19) SetNATSNotifyByID(accountID string, natsn natsNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.NATS[accountID] = natsn
 }
 
-func (s serverConfigV13) GetNATS() map[string]natsNotify {
+func (s serverConfigV19) GetNATS() map[string]natsNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 	return s.Notify.NATS
 }
 
 // GetNATSNotify get current NATS logger.
-func (s serverConfigV13) GetNATSNotifyByID(accountID string) natsNotify {
+func (s serverConfigV19) GetNATSNotifyByID(accountID string) natsNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.NATS[accountID]
 }
 
-func (s *serverConfigV13) SetElasticSearchNotifyByID(accountID string, esNotify elasticSearchNotify) {
+func (s *serverConfigV19) SetElasticSearchNotifyByID(accountID string, esNotify elasticSearchNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.ElasticSearch[accountID] = esNotify
 }
 
-func (s serverConfigV13) GetElasticSearch() map[string]elasticSearchNotify {
+func (s serverConfigV19) GetElasticSearch() map[string]elasticSearchNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -190,28 +227,28 @@ func (s serverConfigV13) GetElasticSearch() map[string]elasticSearchNotify {
 }
 
 // GetElasticSearchNotify get current ElasicSearch logger.
-func (s serverConfigV13) GetElasticSearchNotifyByID(accountID string) elasticSearchNotify {
+func (s serverConfigV19) GetElasticSearchNotifyByID(accountID string) elasticSearchNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.ElasticSearch[accountID]
 }
 
-func (s *serverConfigV13) SetRedisNotifyByID(accountID string, rNotify redisNotify) {
+func (s *serverConfigV19) SetRedisNotifyByID(accountID string, rNotify redisNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.Redis[accountID] = rNotify
 }
 
-func (s serverConfigV13) GetRedis() map[string]redisNotify {
+func (s serverConfigV19) GetRedis() map[string]redisNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.Redis
 }
 
-func (s serverConfigV13) GetWebhook() map[string]webhookNotify {
+func (s serverConfigV19) GetWebhook() map[string]webhookNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -219,14 +256,14 @@ func (s serverConfigV13) GetWebhook() map[string]webhookNotify {
 }
 
 // GetWebhookNotifyByID get current Webhook logger.
-func (s serverConfigV13) GetWebhookNotifyByID(accountID string) webhookNotify {
+func (s serverConfigV19) GetWebhookNotifyByID(accountID string) webhookNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.Webhook[accountID]
 }
 
-func (s *serverConfigV13) SetWebhookNotifyByID(accountID string, pgn webhookNotify) {
+func (s *serverConfigV19) SetWebhookNotifyByID(accountID string, pgn webhookNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
@@ -234,28 +271,28 @@ func (s *serverConfigV13) SetWebhookNotifyByID(accountID string, pgn webhookNoti
 }
 
 // GetRedisNotify get current Redis logger.
-func (s serverConfigV13) GetRedisNotifyByID(accountID string) redisNotify {
+func (s serverConfigV19) GetRedisNotifyByID(accountID string) redisNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.Redis[accountID]
 }
 
-func (s *serverConfigV13) SetPostgreSQLNotifyByID(accountID string, pgn postgreSQLNotify) {
+func (s *serverConfigV19) SetPostgreSQLNotifyByID(accountID string, pgn postgreSQLNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.PostgreSQL[accountID] = pgn
 }
 
-func (s serverConfigV13) GetPostgreSQL() map[string]postgreSQLNotify {
+func (s serverConfigV19) GetPostgreSQL() map[string]postgreSQLNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.PostgreSQL
 }
 
-func (s serverConfigV13) GetPostgreSQLNotifyByID(accountID string) postgreSQLNotify {
+func (s serverConfigV19) GetPostgreSQLNotifyByID(accountID string) postgreSQLNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -263,29 +300,141 @@ func (s serverConfigV13) GetPostgreSQLNotifyByID(accountID string) postgreSQLNot
 }
 
 // Kafka related functions
-func (s *serverConfigV13) SetKafkaNotifyByID(accountID string, kn kafkaNotify) {
+func (s *serverConfigV19) SetKafkaNotifyByID(accountID string, kn kafkaNotify) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
 	s.Notify.Kafka[accountID] = kn
 }
 
-func (s serverConfigV13) GetKafka() map[string]kafkaNotify {
+func (s serverConfigV19) GetKafka() map[string]kafkaNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.Kafka
 }
 
-func (s serverConfigV13) GetKafkaNotifyByID(accountID string) kafkaNotify {
+func (s serverConfigV19) GetKafkaNotifyByID(accountID string) kafkaNotify {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
 	return s.Notify.Kafka[accountID]
 }
 
+/// Audit related.
+
+// SetAuditWebhookByID set new audit webhook target.
+func (s *serverConfigV19) SetAuditWebhookByID(targetID string, webhook auditWebhook) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Audit.Webhook[targetID] = webhook
+}
+
+// GetAuditWebhookByID get current audit webhook target.
+func (s serverConfigV19) GetAuditWebhookByID(targetID string) auditWebhook {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Audit.Webhook[targetID]
+}
+
+// SetAuditFileByID set new audit file target.
+func (s *serverConfigV19) SetAuditFileByID(targetID string, file auditFile) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Audit.File[targetID] = file
+}
+
+// GetAuditFileByID get current audit file target.
+func (s serverConfigV19) GetAuditFileByID(targetID string) auditFile {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Audit.File[targetID]
+}
+
+// GetAudit get current audit targets.
+func (s serverConfigV19) GetAudit() audit {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Audit
+}
+
+/// Storage class related.
+
+// SetStorageClass set new storage class configuration.
+func (s *serverConfigV19) SetStorageClass(sc storageClassConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.StorageClass = sc
+}
+
+// GetStorageClass get current storage class configuration.
+func (s serverConfigV19) GetStorageClass() storageClassConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.StorageClass
+}
+
+/// LDAP related.
+
+// SetLDAP set new LDAP configuration.
+func (s *serverConfigV19) SetLDAP(ldap ldapConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.LDAP = ldap
+}
+
+// GetLDAP get current LDAP configuration.
+func (s serverConfigV19) GetLDAP() ldapConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.LDAP
+}
+
+/// OpenID Connect related.
+
+// SetOpenID set new OpenID Connect configuration.
+func (s *serverConfigV19) SetOpenID(openID openIDConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.OpenID = openID
+}
+
+// GetOpenID get current OpenID Connect configuration.
+func (s serverConfigV19) GetOpenID() openIDConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.OpenID
+}
+
+// SetLogger set new loggers.
+func (s *serverConfigV19) SetLogger(l loggerConfig) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	s.Logger = l
+}
+
+// GetLogger get current loggers.
+func (s serverConfigV19) GetLogger() loggerConfig {
+	serverConfigMu.RLock()
+	defer serverConfigMu.RUnlock()
+
+	return s.Logger
+}
+
 // SetFileLogger set new file logger.
-func (s *serverConfigV13) SetFileLogger(flogger fileLogger) {
+func (s *serverConfigV19) SetFileLogger(flogger loggerFile) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
@@ -293,7 +442,7 @@ func (s *serverConfigV13) SetFileLogger(flogger fileLogger) {
 }
 
 // GetFileLogger get current file logger.
-func (s serverConfigV13) GetFileLogger() fileLogger {
+func (s serverConfigV19) GetFileLogger() loggerFile {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -301,7 +450,7 @@ func (s serverConfigV13) GetFileLogger() fileLogger {
 }
 
 // SetConsoleLogger set new console logger.
-func (s *serverConfigV13) SetConsoleLogger(clogger consoleLogger) {
+func (s *serverConfigV19) SetConsoleLogger(clogger loggerConsole) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
@@ -309,7 +458,7 @@ func (s *serverConfigV13) SetConsoleLogger(clogger consoleLogger) {
 }
 
 // GetConsoleLogger get current console logger.
-func (s serverConfigV13) GetConsoleLogger() consoleLogger {
+func (s serverConfigV19) GetConsoleLogger() loggerConsole {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -317,7 +466,7 @@ func (s serverConfigV13) GetConsoleLogger() consoleLogger {
 }
 
 // SetRegion set new region.
-func (s *serverConfigV13) SetRegion(region string) {
+func (s *serverConfigV19) SetRegion(region string) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
@@ -325,7 +474,7 @@ func (s *serverConfigV13) SetRegion(region string) {
 }
 
 // GetRegion get current region.
-func (s serverConfigV13) GetRegion() string {
+func (s serverConfigV19) GetRegion() string {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -333,7 +482,7 @@ func (s serverConfigV13) GetRegion() string {
 }
 
 // SetCredentials set new credentials.
-func (s *serverConfigV13) SetCredential(creds credential) {
+func (s *serverConfigV19) SetCredential(creds credential) {
 	serverConfigMu.Lock()
 	defer serverConfigMu.Unlock()
 
@@ -341,7 +490,7 @@ func (s *serverConfigV13) SetCredential(creds credential) {
 }
 
 // GetCredentials get current credentials.
-func (s serverConfigV13) GetCredential() credential {
+func (s serverConfigV19) GetCredential() credential {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
@@ -349,7 +498,7 @@ func (s serverConfigV13) GetCredential() credential {
 }
 
 // Save config.
-func (s serverConfigV13) Save() error {
+func (s serverConfigV19) Save() error {
 	serverConfigMu.RLock()
 	defer serverConfigMu.RUnlock()
 
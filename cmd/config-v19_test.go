@@ -74,22 +74,59 @@ func TestServerConfig(t *testing.T) {
 		t.Errorf("Expecting Webhook config %#v found %#v", webhookNotify{}, savedNotifyCfg3)
 	}
 
+	// Set new audit webhook target id.
+	serverConfig.SetAuditWebhookByID("2", auditWebhook{})
+	savedAuditCfg1 := serverConfig.GetAuditWebhookByID("2")
+	if !reflect.DeepEqual(savedAuditCfg1, auditWebhook{}) {
+		t.Errorf("Expecting audit webhook config %#v found %#v", auditWebhook{}, savedAuditCfg1)
+	}
+
+	// Set new audit file target id.
+	serverConfig.SetAuditFileByID("2", auditFile{})
+	savedAuditCfg2 := serverConfig.GetAuditFileByID("2")
+	if !reflect.DeepEqual(savedAuditCfg2, auditFile{}) {
+		t.Errorf("Expecting audit file config %#v found %#v", auditFile{}, savedAuditCfg2)
+	}
+
+	// Set new storage class config.
+	scConfig := storageClassConfig{Standard: storageClass{Parity: 6}, RRS: storageClass{Parity: 2}}
+	serverConfig.SetStorageClass(scConfig)
+	if savedSCConfig := serverConfig.GetStorageClass(); !reflect.DeepEqual(savedSCConfig, scConfig) {
+		t.Errorf("Expecting storage class config %#v found %#v", scConfig, savedSCConfig)
+	}
+	// Object layers of other tests are set up with the default parity.
+	serverConfig.SetStorageClass(storageClassConfig{})
+
+	// Set new LDAP config.
+	ldapCfg := ldapConfig{ServerURL: "ldap://localhost", UserDNFormat: "uid=%s,dc=example,dc=com"}
+	serverConfig.SetLDAP(ldapCfg)
+	if savedLDAPCfg := serverConfig.GetLDAP(); !reflect.DeepEqual(savedLDAPCfg, ldapCfg) {
+		t.Errorf("Expecting LDAP config %#v found %#v", ldapCfg, savedLDAPCfg)
+	}
+
+	// Set new OpenID Connect config.
+	openIDCfg := openIDConfig{Issuer: "https://openid.example.com", JWKSURL: "https://openid.example.com/jwks"}
+	serverConfig.SetOpenID(openIDCfg)
+	if savedOpenIDCfg := serverConfig.GetOpenID(); !reflect.DeepEqual(savedOpenIDCfg, openIDCfg) {
+		t.Errorf("Expecting OpenID Connect config %#v found %#v", openIDCfg, savedOpenIDCfg)
+	}
+
 	// Set new console logger.
-	serverConfig.SetConsoleLogger(consoleLogger{
+	serverConfig.SetConsoleLogger(loggerConsole{
 		Enable: true,
 	})
 	consoleCfg := serverConfig.GetConsoleLogger()
-	if !reflect.DeepEqual(consoleCfg, consoleLogger{Enable: true}) {
-		t.Errorf("Expecting console logger config %#v found %#v", consoleLogger{Enable: true}, consoleCfg)
+	if !reflect.DeepEqual(consoleCfg, loggerConsole{Enable: true}) {
+		t.Errorf("Expecting console logger config %#v found %#v", loggerConsole{Enable: true}, consoleCfg)
 	}
 
 	// Set new file logger.
-	serverConfig.SetFileLogger(fileLogger{
+	serverConfig.SetFileLogger(loggerFile{
 		Enable: true,
 	})
 	fileCfg := serverConfig.GetFileLogger()
-	if !reflect.DeepEqual(fileCfg, fileLogger{Enable: true}) {
-		t.Errorf("Expecting file logger config %#v found %#v", fileLogger{Enable: true}, consoleCfg)
+	if !reflect.DeepEqual(fileCfg, loggerFile{Enable: true}) {
+		t.Errorf("Expecting file logger config %#v found %#v", loggerFile{Enable: true}, consoleCfg)
 	}
 
 	// Match version.
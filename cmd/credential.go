@@ -63,14 +63,16 @@ func isSecretKeyValid(secretKey string) bool {
 	return len(secretKey) >= secretKeyMinLen && len(secretKey) <= secretKeyMaxLen
 }
 
-// credential container for access and secret keys.
+// credential container for access and secret keys, temporary
+// credentials also carry the session token issued with them.
 type credential struct {
-	AccessKey string `json:"accessKey"`
-	SecretKey string `json:"secretKey"`
+	AccessKey    string `json:"accessKey"`
+	SecretKey    string `json:"secretKey"`
+	SessionToken string `json:"sessionToken,omitempty"`
 }
 
 func newCredential() credential {
-	return credential{mustGetAccessKey(), mustGetSecretKey()}
+	return credential{AccessKey: mustGetAccessKey(), SecretKey: mustGetSecretKey()}
 }
 
 func getCredential(accessKey, secretKey string) (credential, error) {
@@ -82,5 +84,5 @@ func getCredential(accessKey, secretKey string) (credential, error) {
 		return credential{}, errInvalidSecretKeyLength
 	}
 
-	return credential{accessKey, secretKey}, nil
+	return credential{AccessKey: accessKey, SecretKey: secretKey}, nil
 }
@@ -0,0 +1,851 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"path"
+)
+
+// Encrypted objects are stored as a sequence of packages, each package
+// seals up to ssePackagePayloadSize bytes of plaintext with AES-256-GCM
+// under a random key of the object. Every part of a multipart object is
+// a separate sequence of packages. A package is laid out as
+//
+//   version (1) | cipher (1) | payload size - 1 (2) | sequence number (4) |
+//   random nonce (8) | encrypted payload (1 - 64KiB) | tag (16)
+//
+// The sequence number and the random nonce of a sequence form the GCM
+// nonce. The first four bytes of the header and the part number are
+// authenticated as well, the cipher of the last package of a sequence
+// is marked by ssePackageFinal so truncated parts fail to decrypt.
+// Since all but the last package of a sequence are full, any range of
+// plaintext maps to a range of packages without decrypting the object.
+const (
+	ssePackageVersion     = 0x10
+	ssePackageCipherAES   = 0x00
+	ssePackageFinal       = 0x80
+	ssePackageHeaderSize  = 16
+	ssePackageTagSize     = 16
+	ssePackagePayloadSize = 64 * 1024
+	ssePackageOverhead    = ssePackageHeaderSize + ssePackageTagSize
+	ssePackageSize        = ssePackagePayloadSize + ssePackageOverhead
+)
+
+const (
+	// SSE-C request headers, the copy source of CopyObject is sent with
+	// the same headers prefixed by `X-Amz-Copy-Source-`.
+	amzSSECustomerAlgorithm = "X-Amz-Server-Side-Encryption-Customer-Algorithm"
+	amzSSECustomerKey       = "X-Amz-Server-Side-Encryption-Customer-Key"
+	amzSSECustomerKeyMD5    = "X-Amz-Server-Side-Encryption-Customer-Key-Md5"
+	amzSSECopySourcePrefix  = "X-Amz-Copy-Source-"
+
+	// The only algorithm supported for SSE-C.
+	sseCustomerAlgorithmAES256 = "AES256"
+
+	// SSE-S3 and SSE-KMS request headers.
+	amzServerSideEncryption         = "X-Amz-Server-Side-Encryption"
+	amzServerSideEncryptionKMSKeyID = "X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"
+
+	// Algorithms of SSE-S3 and SSE-KMS, both seal object keys with a
+	// data key of the KMS.
+	sseAlgorithmAES256 = "AES256"
+	sseAlgorithmKMS    = "aws:kms"
+
+	// Metadata entries with this prefix are reserved for the server,
+	// they are never returned as response headers.
+	reservedMetadataPrefix = "X-Minio-Internal-"
+
+	// Reserved metadata entries of encrypted objects.
+	sseIVMetaKey            = "X-Minio-Internal-Server-Side-Encryption-Iv"
+	sseSealedKeyMetaKey     = "X-Minio-Internal-Server-Side-Encryption-Sealed-Key"
+	sseSealAlgorithmMetaKey = "X-Minio-Internal-Server-Side-Encryption-Seal-Algorithm"
+	sseCustomerMetaKey      = "X-Minio-Internal-Server-Side-Encryption-Customer"
+
+	// Reserved metadata entries of objects encrypted by the KMS, the
+	// master key id and the sealed data key allow to rotate keys later.
+	sseAlgorithmMetaKey    = "X-Minio-Internal-Server-Side-Encryption"
+	sseKMSKeyIDMetaKey     = "X-Minio-Internal-Server-Side-Encryption-Kms-Key-Id"
+	sseKMSSealedKeyMetaKey = "X-Minio-Internal-Server-Side-Encryption-Kms-Sealed-Key"
+
+	// Algorithm sealing the object key, the key encryption key is
+	// derived by HMAC-SHA256 and seals the object key with AES-256-GCM.
+	sseSealAlgorithm = "HMAC-SHA256-AES-GCM"
+)
+
+var (
+	// errObjectTampered - an encrypted package failed authentication.
+	errObjectTampered = errors.New("The encrypted object has been tampered with")
+
+	// errSSEKeyMismatch - the key sent by the client does not unseal
+	// the key of the object.
+	errSSEKeyMismatch = errors.New("The provided encryption key does not match the object")
+
+	// errEncryptedObject - an encrypted object is read without its key.
+	errEncryptedObject = errors.New("The object was stored using a form of server side encryption")
+)
+
+// All reserved metadata entries of encrypted objects.
+var sseMetadataKeys = []string{
+	sseIVMetaKey, sseSealedKeyMetaKey, sseSealAlgorithmMetaKey, sseCustomerMetaKey,
+	sseAlgorithmMetaKey, sseKMSKeyIDMetaKey, sseKMSSealedKeyMetaKey,
+}
+
+// Returns true if the metadata belongs to an encrypted object.
+func isEncrypted(metadata map[string]string) bool {
+	_, ok := metadata[sseSealedKeyMetaKey]
+	return ok
+}
+
+// Returns true if the metadata belongs to an object encrypted with a
+// customer provided key.
+func isSSECustomerEncrypted(metadata map[string]string) bool {
+	return isEncrypted(metadata) && metadata[sseCustomerMetaKey] == "true"
+}
+
+// Removes all encryption entries from metadata, used when copying the
+// metadata of an object to a new one.
+func removeEncryptionMetadata(metadata map[string]string) {
+	for _, key := range sseMetadataKeys {
+		delete(metadata, key)
+	}
+}
+
+// Returns true if the request carries any SSE-C header, prefix selects
+// the headers of the copy source.
+func hasSSECustomerHeader(header http.Header, prefix string) bool {
+	for _, key := range []string{amzSSECustomerAlgorithm, amzSSECustomerKey, amzSSECustomerKeyMD5} {
+		if _, ok := header[prefix+key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSSECustomerKey - returns the client key of SSE-C headers, prefix
+// selects the headers of the copy source. Keys are only accepted over
+// TLS so they are never sent in the clear.
+func parseSSECustomerKey(header http.Header, prefix string) ([]byte, APIErrorCode) {
+	if !globalIsSSL {
+		return nil, ErrInsecureSSECustomerRequest
+	}
+	if header.Get(prefix+amzSSECustomerAlgorithm) != sseCustomerAlgorithmAES256 {
+		return nil, ErrInvalidSSECustomerAlgorithm
+	}
+	if header.Get(prefix+amzSSECustomerKey) == "" {
+		return nil, ErrMissingSSECustomerKey
+	}
+	if header.Get(prefix+amzSSECustomerKeyMD5) == "" {
+		return nil, ErrMissingSSECustomerKeyMD5
+	}
+	key, err := base64.StdEncoding.DecodeString(header.Get(prefix + amzSSECustomerKey))
+	if err != nil || len(key) != 32 {
+		return nil, ErrInvalidSSECustomerKey
+	}
+	keyMD5, err := base64.StdEncoding.DecodeString(header.Get(prefix + amzSSECustomerKeyMD5))
+	if err != nil {
+		return nil, ErrSSECustomerKeyMD5Mismatch
+	}
+	if sum := md5.Sum(key); !hmac.Equal(sum[:], keyMD5) {
+		return nil, ErrSSECustomerKeyMD5Mismatch
+	}
+	return key, ErrNone
+}
+
+// Write the SSE-C response headers, the key itself is never returned.
+func setSSECustomerHeaders(w http.ResponseWriter, header http.Header) {
+	w.Header().Set(amzSSECustomerAlgorithm, sseCustomerAlgorithmAES256)
+	w.Header().Set(amzSSECustomerKeyMD5, header.Get(amzSSECustomerKeyMD5))
+}
+
+// Write the encryption response headers of an object, nothing is set
+// for objects stored in plaintext.
+func setSSEHeaders(w http.ResponseWriter, metadata map[string]string, header http.Header) {
+	if !isEncrypted(metadata) {
+		return
+	}
+	if isSSECustomerEncrypted(metadata) {
+		setSSECustomerHeaders(w, header)
+		return
+	}
+	w.Header().Set(amzServerSideEncryption, metadata[sseAlgorithmMetaKey])
+	if metadata[sseAlgorithmMetaKey] == sseAlgorithmKMS {
+		w.Header().Set(amzServerSideEncryptionKMSKeyID, metadata[sseKMSKeyIDMetaKey])
+	}
+}
+
+// Returns the key encryption key deriving from the sealing key, bound
+// to the object so sealed keys cannot be moved between objects.
+func sseKeyEncryptionKey(sealingKey, iv []byte, bucket, object string) []byte {
+	mac := hmac.New(sha256.New, sealingKey)
+	mac.Write(iv)
+	mac.Write([]byte(sseSealAlgorithm))
+	mac.Write([]byte(path.Join(bucket, object)))
+	return mac.Sum(nil)
+}
+
+// Returns an AES-256-GCM cipher for key.
+func newSSECipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// newObjectKey - generates a random object key, seals it with the
+// sealing key and saves the sealed key into metadata.
+func newObjectKey(sealingKey []byte, bucket, object string, metadata map[string]string) ([]byte, error) {
+	objectKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, objectKey); err != nil {
+		return nil, err
+	}
+	iv := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+
+	aead, err := newSSECipher(sseKeyEncryptionKey(sealingKey, iv, bucket, object))
+	if err != nil {
+		return nil, err
+	}
+	// Every key encryption key seals exactly one object key, a zero
+	// nonce is never reused.
+	sealedKey := aead.Seal(nil, make([]byte, aead.NonceSize()), objectKey, nil)
+
+	metadata[sseIVMetaKey] = base64.StdEncoding.EncodeToString(iv)
+	metadata[sseSealedKeyMetaKey] = base64.StdEncoding.EncodeToString(sealedKey)
+	metadata[sseSealAlgorithmMetaKey] = sseSealAlgorithm
+	return objectKey, nil
+}
+
+// unsealObjectKey - returns the object key sealed in the metadata of an
+// encrypted object, errSSEKeyMismatch if the sealing key is wrong.
+func unsealObjectKey(sealingKey []byte, bucket, object string, metadata map[string]string) ([]byte, error) {
+	if metadata[sseSealAlgorithmMetaKey] != sseSealAlgorithm {
+		return nil, errObjectTampered
+	}
+	iv, err := base64.StdEncoding.DecodeString(metadata[sseIVMetaKey])
+	if err != nil {
+		return nil, errObjectTampered
+	}
+	sealedKey, err := base64.StdEncoding.DecodeString(metadata[sseSealedKeyMetaKey])
+	if err != nil {
+		return nil, errObjectTampered
+	}
+
+	aead, err := newSSECipher(sseKeyEncryptionKey(sealingKey, iv, bucket, object))
+	if err != nil {
+		return nil, err
+	}
+	objectKey, err := aead.Open(nil, make([]byte, aead.NonceSize()), sealedKey, nil)
+	if err != nil {
+		return nil, errSSEKeyMismatch
+	}
+	return objectKey, nil
+}
+
+// Returns the size of size bytes of plaintext once encrypted.
+func sseEncryptedSize(size int64) int64 {
+	encSize := (size / ssePackagePayloadSize) * ssePackageSize
+	if rem := size % ssePackagePayloadSize; rem > 0 {
+		encSize += rem + ssePackageOverhead
+	}
+	return encSize
+}
+
+// Returns the plaintext size of encSize bytes of packages.
+func sseDecryptedSize(encSize int64) (int64, error) {
+	size := (encSize / ssePackageSize) * ssePackagePayloadSize
+	if rem := encSize % ssePackageSize; rem > 0 {
+		if rem <= ssePackageOverhead {
+			return 0, errObjectTampered
+		}
+		size += rem - ssePackageOverhead
+	}
+	return size, nil
+}
+
+// Returns the parts of an encrypted object, objects written by
+// PutObject consist of a single part.
+func sseObjectParts(objInfo ObjectInfo) []objectPartInfo {
+	if len(objInfo.Parts) > 0 {
+		return objInfo.Parts
+	}
+	return []objectPartInfo{{Number: 1, Size: objInfo.Size}}
+}
+
+// Returns the plaintext size of an encrypted object.
+func sseObjectSize(objInfo ObjectInfo) (int64, error) {
+	var size int64
+	for _, part := range sseObjectParts(objInfo) {
+		partSize, err := sseDecryptedSize(part.Size)
+		if err != nil {
+			return 0, err
+		}
+		size += partSize
+	}
+	return size, nil
+}
+
+// Returns the number of packages of a part.
+func ssePartPackages(part objectPartInfo) uint32 {
+	return uint32((part.Size + ssePackageSize - 1) / ssePackageSize)
+}
+
+// Returns the data authenticated along with a package, the first four
+// bytes of its header followed by the number of its part.
+func sseAdditionalData(header []byte, partNumber uint32) []byte {
+	aad := make([]byte, 8)
+	copy(aad, header[:4])
+	binary.LittleEndian.PutUint32(aad[4:], partNumber)
+	return aad
+}
+
+// sseEncryptedRange - maps length bytes of plaintext at offset to the
+// range of packages holding them. Returns the offset and length of the
+// packages, the index of the part and the sequence number of the first
+// package and the number of plaintext bytes of the first package
+// preceding offset.
+func sseEncryptedRange(parts []objectPartInfo, offset, length int64) (encOffset, encLength int64, partIdx int, seqNum uint32, skip int64) {
+	if length == 0 {
+		return 0, 0, 0, 0, 0
+	}
+	end := offset + length // Exclusive.
+
+	var plainStart, encStart int64
+	encEnd := int64(-1)
+	for i, part := range parts {
+		plainSize, _ := sseDecryptedSize(part.Size)
+		plainEnd := plainStart + plainSize
+		if offset >= plainStart && offset < plainEnd {
+			pkg := (offset - plainStart) / ssePackagePayloadSize
+			encOffset = encStart + pkg*ssePackageSize
+			partIdx, seqNum = i, uint32(pkg)
+			skip = offset - plainStart - pkg*ssePackagePayloadSize
+		}
+		if end > plainStart && end <= plainEnd {
+			pkg := (end - plainStart - 1) / ssePackagePayloadSize
+			encEnd = encStart + (pkg+1)*ssePackageSize
+			if partEnd := encStart + part.Size; encEnd > partEnd {
+				encEnd = partEnd
+			}
+			break
+		}
+		plainStart, encStart = plainEnd, encStart+part.Size
+	}
+	return encOffset, encEnd - encOffset, partIdx, seqNum, skip
+}
+
+// sseEncryptReader - encrypts plaintext into a sequence of packages.
+// Since the object layer only sees ciphertext, the md5 and sha256 sums
+// sent by the client are verified here, before the last package is
+// handed out.
+type sseEncryptReader struct {
+	src       io.Reader
+	remaining int64
+	// Bytes read ahead into plain by streams of unknown size.
+	carry int
+
+	aead       cipher.AEAD
+	nonce      [8]byte
+	partNumber uint32
+	seqNum     uint32
+
+	md5Hash, sha256Hash   hash.Hash
+	md5Hex, sha256Hex     string
+	buffer, plain, sealed []byte
+	err                   error
+}
+
+// newSSEEncryptReader - returns a reader encrypting size bytes of
+// plaintext of part partNumber with the object key, a negative size
+// encrypts src until EOF. Objects written by PutObject consist of part
+// 1. md5Hex and sha256Hex are verified unless empty.
+func newSSEEncryptReader(src io.Reader, size int64, objectKey []byte, partNumber int, md5Hex, sha256Hex string) (io.Reader, error) {
+	aead, err := newSSECipher(objectKey)
+	if err != nil {
+		return nil, err
+	}
+	r := &sseEncryptReader{
+		src:        src,
+		remaining:  size,
+		aead:       aead,
+		partNumber: uint32(partNumber),
+		md5Hash:    md5.New(),
+		sha256Hash: sha256.New(),
+		md5Hex:     md5Hex,
+		sha256Hex:  sha256Hex,
+		plain:      make([]byte, ssePackagePayloadSize+1),
+		sealed:     make([]byte, 0, ssePackageSize),
+	}
+	if _, err = io.ReadFull(rand.Reader, r.nonce[:]); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reads and seals the next package.
+func (r *sseEncryptReader) nextPackage() error {
+	if r.remaining == 0 {
+		return io.EOF
+	}
+	var n int
+	var final bool
+	if r.remaining > 0 {
+		n = ssePackagePayloadSize
+		if r.remaining < int64(n) {
+			n = int(r.remaining)
+		}
+		if _, err := io.ReadFull(r.src, r.plain[:n]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		r.remaining -= int64(n)
+		final = r.remaining == 0
+	} else {
+		// Streams of unknown size are read one byte ahead, a package
+		// is the last one if the stream ends before that byte.
+		m, err := io.ReadFull(r.src, r.plain[r.carry:])
+		switch err {
+		case nil:
+			n = ssePackagePayloadSize
+		case io.EOF, io.ErrUnexpectedEOF:
+			n, final, r.remaining = r.carry+m, true, 0
+			if n == 0 {
+				return io.EOF
+			}
+		default:
+			return err
+		}
+	}
+	r.md5Hash.Write(r.plain[:n])
+	r.sha256Hash.Write(r.plain[:n])
+
+	// Verify checksums before the last package is handed out.
+	if final {
+		if md5Hex := hex.EncodeToString(r.md5Hash.Sum(nil)); r.md5Hex != "" && r.md5Hex != md5Hex {
+			return BadDigest{r.md5Hex, md5Hex}
+		}
+		if r.sha256Hex != "" && r.sha256Hex != hex.EncodeToString(r.sha256Hash.Sum(nil)) {
+			return SHA256Mismatch{}
+		}
+	}
+
+	header := r.sealed[:ssePackageHeaderSize]
+	header[0] = ssePackageVersion
+	header[1] = ssePackageCipherAES
+	if final {
+		header[1] |= ssePackageFinal
+	}
+	binary.LittleEndian.PutUint16(header[2:4], uint16(n-1))
+	binary.LittleEndian.PutUint32(header[4:8], r.seqNum)
+	copy(header[8:16], r.nonce[:])
+	r.sealed = r.aead.Seal(header, header[4:16], r.plain[:n], sseAdditionalData(header, r.partNumber))
+	r.seqNum++
+	r.buffer = r.sealed
+
+	// Keep the byte read ahead for the next package.
+	if r.remaining < 0 {
+		r.plain[0], r.carry = r.plain[ssePackagePayloadSize], 1
+	}
+	return nil
+}
+
+func (r *sseEncryptReader) Read(p []byte) (int, error) {
+	if len(r.buffer) == 0 && r.err == nil {
+		r.err = r.nextPackage()
+	}
+	if len(r.buffer) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.buffer)
+	r.buffer = r.buffer[n:]
+	return n, nil
+}
+
+// sseDecryptWriter - decrypts a range of packages, writing length bytes
+// of plaintext after skipping the first skip bytes. Packages have to
+// follow each other exactly, the sequence number starts over at the
+// part boundaries known from the parts of the object.
+type sseDecryptWriter struct {
+	dst     io.Writer
+	aead    cipher.AEAD
+	parts   []objectPartInfo
+	partIdx int
+	seqNum  uint32
+
+	skip, length int64
+	buffer       bytes.Buffer
+	plain        []byte
+}
+
+// newSSEDecryptWriter - returns a writer decrypting packages of parts
+// with the object key, starting at sequence number seqNum of the part
+// at partIdx.
+func newSSEDecryptWriter(dst io.Writer, objectKey []byte, parts []objectPartInfo, partIdx int, seqNum uint32, skip, length int64) (*sseDecryptWriter, error) {
+	aead, err := newSSECipher(objectKey)
+	if err != nil {
+		return nil, err
+	}
+	return &sseDecryptWriter{
+		dst:     dst,
+		aead:    aead,
+		parts:   parts,
+		partIdx: partIdx,
+		seqNum:  seqNum,
+		skip:    skip,
+		length:  length,
+		plain:   make([]byte, 0, ssePackagePayloadSize),
+	}, nil
+}
+
+// Opens the package at the start of buf.
+func (w *sseDecryptWriter) openPackage(buf []byte) (err error) {
+	// Empty parts have no packages.
+	for w.partIdx < len(w.parts) && w.parts[w.partIdx].Size == 0 {
+		w.partIdx++
+	}
+	if w.partIdx >= len(w.parts) {
+		return errObjectTampered
+	}
+	part := w.parts[w.partIdx]
+	header := buf[:ssePackageHeaderSize]
+	if header[0] != ssePackageVersion || header[1]&^ssePackageFinal != ssePackageCipherAES {
+		return errObjectTampered
+	}
+	// Only the last package of a part is marked final.
+	final := w.seqNum+1 == ssePartPackages(part)
+	if binary.LittleEndian.Uint32(header[4:8]) != w.seqNum || (header[1]&ssePackageFinal != 0) != final {
+		return errObjectTampered
+	}
+	w.plain, err = w.aead.Open(w.plain[:0], header[4:16], buf[ssePackageHeaderSize:], sseAdditionalData(header, uint32(part.Number)))
+	if err != nil {
+		return errObjectTampered
+	}
+	if final {
+		w.partIdx, w.seqNum = w.partIdx+1, 0
+	} else {
+		w.seqNum++
+	}
+
+	plain := w.plain
+	if w.skip > 0 {
+		if w.skip >= int64(len(plain)) {
+			w.skip -= int64(len(plain))
+			return nil
+		}
+		plain, w.skip = plain[w.skip:], 0
+	}
+	if int64(len(plain)) > w.length {
+		plain = plain[:w.length]
+	}
+	w.length -= int64(len(plain))
+	_, err = w.dst.Write(plain)
+	return err
+}
+
+func (w *sseDecryptWriter) Write(p []byte) (int, error) {
+	w.buffer.Write(p)
+	for w.buffer.Len() >= ssePackageHeaderSize {
+		buf := w.buffer.Bytes()
+		size := int(binary.LittleEndian.Uint16(buf[2:4])) + 1 + ssePackageOverhead
+		if len(buf) < size {
+			break
+		}
+		if err := w.openPackage(buf[:size]); err != nil {
+			return 0, err
+		}
+		w.buffer.Next(size)
+	}
+	return len(p), nil
+}
+
+// Close - verifies that all packages were complete and the whole range
+// was written.
+func (w *sseDecryptWriter) Close() error {
+	if w.buffer.Len() > 0 || w.length > 0 {
+		return errObjectTampered
+	}
+	return nil
+}
+
+// newSSECustomerObjectKey - generates the key of an object about to be
+// written with SSE-C, sealed by the client key into metadata.
+func newSSECustomerObjectKey(header http.Header, bucket, object string, metadata map[string]string) ([]byte, APIErrorCode) {
+	key, s3Error := parseSSECustomerKey(header, "")
+	if s3Error != ErrNone {
+		return nil, s3Error
+	}
+	objectKey, err := newObjectKey(key, bucket, object, metadata)
+	if err != nil {
+		return nil, toAPIErrorCode(err)
+	}
+	metadata[sseCustomerMetaKey] = "true"
+	return objectKey, ErrNone
+}
+
+// newSSEKMSObjectKey - generates the key of an object about to be
+// written with SSE-S3 or SSE-KMS, sealed by a data key of the KMS. An
+// empty keyID selects the default master key of the KMS.
+func newSSEKMSObjectKey(algorithm, keyID, bucket, object string, metadata map[string]string) ([]byte, APIErrorCode) {
+	switch algorithm {
+	case sseAlgorithmAES256:
+		if keyID != "" {
+			return nil, ErrInvalidEncryptionParameters
+		}
+	case sseAlgorithmKMS:
+	default:
+		return nil, ErrInvalidEncryptionMethod
+	}
+	if globalKMS == nil {
+		return nil, ErrKMSNotConfigured
+	}
+	if keyID == "" {
+		keyID = globalKMS.DefaultKeyID()
+	}
+
+	dataKey, sealedKey, err := globalKMS.GenerateKey(keyID, []byte(path.Join(bucket, object)))
+	if err != nil {
+		errorIf(err, "Unable to generate a data key for %s/%s.", bucket, object)
+		return nil, toAPIErrorCode(err)
+	}
+	objectKey, err := newObjectKey(dataKey, bucket, object, metadata)
+	if err != nil {
+		return nil, toAPIErrorCode(err)
+	}
+	metadata[sseAlgorithmMetaKey] = algorithm
+	metadata[sseKMSKeyIDMetaKey] = keyID
+	metadata[sseKMSSealedKeyMetaKey] = base64.StdEncoding.EncodeToString(sealedKey)
+	return objectKey, ErrNone
+}
+
+// newSSEObjectKey - generates the key of an object about to be written
+// if the request or the default encryption of the bucket asks for it,
+// returns nil for objects stored in plaintext.
+func newSSEObjectKey(header http.Header, bucket, object string, metadata map[string]string) ([]byte, APIErrorCode) {
+	_, sseRequested := header[amzServerSideEncryption]
+	if hasSSECustomerHeader(header, "") {
+		if sseRequested {
+			return nil, ErrInvalidEncryptionParameters
+		}
+		return newSSECustomerObjectKey(header, bucket, object, metadata)
+	}
+
+	algorithm, keyID := header.Get(amzServerSideEncryption), header.Get(amzServerSideEncryptionKMSKeyID)
+	if !sseRequested {
+		if keyID != "" {
+			return nil, ErrInvalidEncryptionParameters
+		}
+		if algorithm, keyID = getBucketEncryption(bucket); algorithm == "" {
+			return nil, ErrNone
+		}
+	}
+	return newSSEKMSObjectKey(algorithm, keyID, bucket, object, metadata)
+}
+
+// unsealSSEObjectKey - returns the key of an encrypted object or upload,
+// unsealed by the SSE-C headers of the request or by the KMS. prefix
+// selects the SSE-C headers of the copy source.
+func unsealSSEObjectKey(header http.Header, prefix, bucket, object string, metadata map[string]string) ([]byte, APIErrorCode) {
+	var sealingKey []byte
+	if isSSECustomerEncrypted(metadata) {
+		if !hasSSECustomerHeader(header, prefix) {
+			return nil, ErrSSEEncryptedObject
+		}
+		key, s3Error := parseSSECustomerKey(header, prefix)
+		if s3Error != ErrNone {
+			return nil, s3Error
+		}
+		sealingKey = key
+	} else {
+		if hasSSECustomerHeader(header, prefix) {
+			return nil, ErrInvalidEncryptionParameters
+		}
+		if globalKMS == nil {
+			return nil, ErrKMSNotConfigured
+		}
+		sealedKey, err := base64.StdEncoding.DecodeString(metadata[sseKMSSealedKeyMetaKey])
+		if err != nil {
+			return nil, ErrObjectTampered
+		}
+		dataKey, err := globalKMS.UnsealKey(metadata[sseKMSKeyIDMetaKey], sealedKey, []byte(path.Join(bucket, object)))
+		if err != nil {
+			errorIf(err, "Unable to unseal the data key of %s/%s.", bucket, object)
+			return nil, toAPIErrorCode(err)
+		}
+		sealingKey = dataKey
+	}
+
+	objectKey, err := unsealObjectKey(sealingKey, bucket, object, metadata)
+	if err != nil {
+		return nil, toAPIErrorCode(err)
+	}
+	return objectKey, ErrNone
+}
+
+// getSSEObjectKey - returns the key of an encrypted object, nil for
+// objects which are not encrypted. prefix selects the SSE-C headers of
+// the copy source. The size of objInfo is replaced by the size of the
+// plaintext.
+func getSSEObjectKey(header http.Header, prefix string, objInfo *ObjectInfo) ([]byte, APIErrorCode) {
+	if !isEncrypted(objInfo.UserDefined) {
+		if hasSSECustomerHeader(header, prefix) {
+			return nil, ErrInvalidEncryptionParameters
+		}
+		return nil, ErrNone
+	}
+	objectKey, s3Error := unsealSSEObjectKey(header, prefix, objInfo.Bucket, objInfo.Name, objInfo.UserDefined)
+	if s3Error != ErrNone {
+		return nil, s3Error
+	}
+
+	// Keep the encrypted part sizes, ranges are mapped onto them.
+	var err error
+	objInfo.Parts = sseObjectParts(*objInfo)
+	if objInfo.Size, err = sseObjectSize(*objInfo); err != nil {
+		return nil, toAPIErrorCode(err)
+	}
+	return objectKey, ErrNone
+}
+
+// getSSEUploadKey - returns the key of an encrypted multipart upload,
+// nil for uploads which are not encrypted, and the metadata the upload
+// was initiated with.
+func getSSEUploadKey(ctx context.Context, objAPI ObjectLayer, header http.Header, bucket, object, uploadID string) ([]byte, map[string]string, APIErrorCode) {
+	info, err := objAPI.ListObjectParts(ctx, bucket, object, uploadID, 0, 1)
+	if err != nil {
+		return nil, nil, toAPIErrorCode(err)
+	}
+	if !isEncrypted(info.UserDefined) {
+		if hasSSECustomerHeader(header, "") {
+			return nil, nil, ErrInvalidEncryptionParameters
+		}
+		return nil, info.UserDefined, ErrNone
+	}
+	objectKey, s3Error := unsealSSEObjectKey(header, "", bucket, object, info.UserDefined)
+	if s3Error != ErrNone {
+		return nil, nil, s3Error
+	}
+	return objectKey, info.UserDefined, ErrNone
+}
+
+// checkSSECopyInPlace - verifies the encryption headers of an object
+// copied onto itself. Sealed keys are bound to the name of an object,
+// objects copied in place keep the key they are encrypted with.
+func checkSSECopyInPlace(header http.Header, metadata map[string]string) APIErrorCode {
+	if isSSECustomerEncrypted(metadata) {
+		if !hasSSECustomerHeader(header, "") ||
+			header.Get(amzSSECustomerKey) != header.Get(amzSSECopySourcePrefix+amzSSECustomerKey) {
+			return ErrInvalidCopyDest
+		}
+		return ErrNone
+	}
+	if hasSSECustomerHeader(header, "") {
+		return ErrInvalidCopyDest
+	}
+	if _, ok := header[amzServerSideEncryption]; ok && !isEncrypted(metadata) {
+		return ErrInvalidCopyDest
+	}
+	return ErrNone
+}
+
+// getEncryptedObject - writes length bytes of plaintext at offset of an
+// encrypted object to writer, objInfo as returned by getSSEObjectKey.
+func getEncryptedObject(ctx context.Context, objAPI ObjectLayer, objInfo ObjectInfo, versionID string, objectKey []byte, offset, length int64, writer io.Writer) error {
+	encOffset, encLength, partIdx, seqNum, skip := sseEncryptedRange(objInfo.Parts, offset, length)
+	decrypter, err := newSSEDecryptWriter(writer, objectKey, objInfo.Parts, partIdx, seqNum, skip, length)
+	if err != nil {
+		return err
+	}
+	if err = objAPI.GetObjectVersion(ctx, objInfo.Bucket, objInfo.Name, versionID, encOffset, encLength, decrypter); err != nil {
+		return err
+	}
+	return decrypter.Close()
+}
+
+// copyEncryptedObject - copies an object when either side is encrypted,
+// srcInfo as returned by getSSEObjectKey. A nil key stands for a side
+// which is not encrypted.
+func copyEncryptedObject(ctx context.Context, objAPI ObjectLayer, srcInfo ObjectInfo, srcObjectKey []byte, dstBucket, dstObject string, dstObjectKey []byte, metadata map[string]string) (ObjectInfo, error) {
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		var err error
+		if srcObjectKey != nil {
+			err = getEncryptedObject(ctx, objAPI, srcInfo, "", srcObjectKey, 0, srcInfo.Size, pipeWriter)
+		} else {
+			err = objAPI.GetObject(ctx, srcInfo.Bucket, srcInfo.Name, 0, srcInfo.Size, pipeWriter)
+		}
+		pipeWriter.CloseWithError(err)
+	}()
+	defer pipeReader.Close()
+
+	var reader io.Reader = pipeReader
+	size := srcInfo.Size
+	if dstObjectKey != nil {
+		var err error
+		if reader, err = newSSEEncryptReader(pipeReader, size, dstObjectKey, 1, "", ""); err != nil {
+			return ObjectInfo{}, err
+		}
+		size = sseEncryptedSize(size)
+	}
+	return objAPI.PutObject(ctx, dstBucket, dstObject, size, reader, metadata, "")
+}
+
+// copyEncryptedObjectPart - copies a range of the plaintext of srcInfo
+// to a part of an upload, like copyEncryptedObject either side may be
+// encrypted.
+func copyEncryptedObjectPart(ctx context.Context, objAPI ObjectLayer, srcInfo ObjectInfo, srcObjectKey []byte, offset, length int64, dstBucket, dstObject, uploadID string, partID int, uploadKey []byte) (string, error) {
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		var err error
+		if srcObjectKey != nil {
+			err = getEncryptedObject(ctx, objAPI, srcInfo, "", srcObjectKey, offset, length, pipeWriter)
+		} else {
+			err = objAPI.GetObject(ctx, srcInfo.Bucket, srcInfo.Name, offset, length, pipeWriter)
+		}
+		pipeWriter.CloseWithError(err)
+	}()
+	defer pipeReader.Close()
+
+	var reader io.Reader = pipeReader
+	size := length
+	if uploadKey != nil {
+		var err error
+		if reader, err = newSSEEncryptReader(pipeReader, size, uploadKey, partID, "", ""); err != nil {
+			return "", err
+		}
+		size = sseEncryptedSize(size)
+	}
+	return objAPI.PutObjectPart(ctx, dstBucket, dstObject, uploadID, partID, size, reader, "", "")
+}
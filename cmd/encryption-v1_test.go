@@ -0,0 +1,346 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+// Returns SSE-C request headers for key, prefix selects the headers of
+// the copy source.
+func newSSECustomerHeader(key []byte, prefix string) http.Header {
+	keyMD5 := md5.Sum(key)
+	header := make(http.Header)
+	header.Set(prefix+amzSSECustomerAlgorithm, sseCustomerAlgorithmAES256)
+	header.Set(prefix+amzSSECustomerKey, base64.StdEncoding.EncodeToString(key))
+	header.Set(prefix+amzSSECustomerKeyMD5, base64.StdEncoding.EncodeToString(keyMD5[:]))
+	return header
+}
+
+// Tests validate parsing of SSE-C headers.
+func TestParseSSECustomerKey(t *testing.T) {
+	defer func(isSSL bool) { globalIsSSL = isSSL }(globalIsSSL)
+
+	key := bytes.Repeat([]byte{'k'}, 32)
+	valid := newSSECustomerHeader(key, "")
+
+	// Keys are never accepted in the clear.
+	globalIsSSL = false
+	if _, s3Error := parseSSECustomerKey(valid, ""); s3Error != ErrInsecureSSECustomerRequest {
+		t.Fatalf("Expected %d, got %d", ErrInsecureSSECustomerRequest, s3Error)
+	}
+	globalIsSSL = true
+
+	badAlgorithm := newSSECustomerHeader(key, "")
+	badAlgorithm.Set(amzSSECustomerAlgorithm, "AES128")
+	missingKey := newSSECustomerHeader(key, "")
+	missingKey.Del(amzSSECustomerKey)
+	missingKeyMD5 := newSSECustomerHeader(key, "")
+	missingKeyMD5.Del(amzSSECustomerKeyMD5)
+	shortKey := newSSECustomerHeader(key[:16], "")
+	wrongKeyMD5 := newSSECustomerHeader(key, "")
+	wrongKeyMD5.Set(amzSSECustomerKeyMD5, valid.Get(amzSSECustomerKey))
+
+	testCases := []struct {
+		header         http.Header
+		prefix         string
+		expectedErr    APIErrorCode
+		expectedHasKey bool
+	}{
+		{valid, "", ErrNone, true},
+		{newSSECustomerHeader(key, amzSSECopySourcePrefix), amzSSECopySourcePrefix, ErrNone, true},
+		{valid, amzSSECopySourcePrefix, ErrInvalidSSECustomerAlgorithm, false},
+		{badAlgorithm, "", ErrInvalidSSECustomerAlgorithm, true},
+		{missingKey, "", ErrMissingSSECustomerKey, true},
+		{missingKeyMD5, "", ErrMissingSSECustomerKeyMD5, true},
+		{shortKey, "", ErrInvalidSSECustomerKey, true},
+		{wrongKeyMD5, "", ErrSSECustomerKeyMD5Mismatch, true},
+	}
+	for i, testCase := range testCases {
+		if hasKey := hasSSECustomerHeader(testCase.header, testCase.prefix); hasKey != testCase.expectedHasKey {
+			t.Errorf("Test %d: Expected %t, got %t", i+1, testCase.expectedHasKey, hasKey)
+		}
+		parsedKey, s3Error := parseSSECustomerKey(testCase.header, testCase.prefix)
+		if s3Error != testCase.expectedErr {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.expectedErr, s3Error)
+			continue
+		}
+		if s3Error == ErrNone && !bytes.Equal(parsedKey, key) {
+			t.Errorf("Test %d: Unexpected key", i+1)
+		}
+	}
+}
+
+// Tests sealing and unsealing object keys.
+func TestSealObjectKey(t *testing.T) {
+	sealingKey := bytes.Repeat([]byte{'s'}, 32)
+	metadata := make(map[string]string)
+	objectKey, err := newObjectKey(sealingKey, "bucket", "object", metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncrypted(metadata) {
+		t.Fatal("Expected metadata of an encrypted object")
+	}
+
+	unsealed, err := unsealObjectKey(sealingKey, "bucket", "object", metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(unsealed, objectKey) {
+		t.Fatal("Unsealed key does not match the object key")
+	}
+
+	// A wrong sealing key and a different object must not unseal the key.
+	if _, err = unsealObjectKey(bytes.Repeat([]byte{'x'}, 32), "bucket", "object", metadata); err != errSSEKeyMismatch {
+		t.Errorf("Expected %v, got %v", errSSEKeyMismatch, err)
+	}
+	if _, err = unsealObjectKey(sealingKey, "bucket", "other-object", metadata); err != errSSEKeyMismatch {
+		t.Errorf("Expected %v, got %v", errSSEKeyMismatch, err)
+	}
+
+	removeEncryptionMetadata(metadata)
+	if len(metadata) != 0 {
+		t.Errorf("Expected no metadata, got %v", metadata)
+	}
+}
+
+// Tests size calculations of encrypted objects.
+func TestSSEEncryptedSize(t *testing.T) {
+	testCases := []struct {
+		size    int64
+		encSize int64
+	}{
+		{0, 0},
+		{1, 1 + ssePackageOverhead},
+		{ssePackagePayloadSize, ssePackageSize},
+		{ssePackagePayloadSize + 1, ssePackageSize + 1 + ssePackageOverhead},
+		{5 * ssePackagePayloadSize, 5 * ssePackageSize},
+	}
+	for i, testCase := range testCases {
+		if encSize := sseEncryptedSize(testCase.size); encSize != testCase.encSize {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.encSize, encSize)
+		}
+		size, err := sseDecryptedSize(testCase.encSize)
+		if err != nil || size != testCase.size {
+			t.Errorf("Test %d: Expected %d, got %d (%v)", i+1, testCase.size, size, err)
+		}
+	}
+	if _, err := sseDecryptedSize(ssePackageOverhead); err != errObjectTampered {
+		t.Errorf("Expected %v, got %v", errObjectTampered, err)
+	}
+}
+
+// Encrypts data with key as a single sequence of packages of part
+// partNumber.
+func sseEncrypt(t *testing.T, data, key []byte, partNumber int) []byte {
+	reader, err := newSSEEncryptReader(bytes.NewReader(data), int64(len(data)), key, partNumber, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(encrypted)) != sseEncryptedSize(int64(len(data))) {
+		t.Fatalf("Expected %d bytes, got %d", sseEncryptedSize(int64(len(data))), len(encrypted))
+	}
+	return encrypted
+}
+
+// Tests encrypting and decrypting ranges of single and multipart objects.
+func TestSSEEncryptDecrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{'k'}, 32)
+	part1 := make([]byte, 2*ssePackagePayloadSize+100)
+	part2 := make([]byte, ssePackagePayloadSize+7)
+	if _, err := io.ReadFull(rand.Reader, part1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(rand.Reader, part2); err != nil {
+		t.Fatal(err)
+	}
+	enc1, enc2 := sseEncrypt(t, part1, key, 1), sseEncrypt(t, part2, key, 2)
+
+	data := append(append([]byte{}, part1...), part2...)
+	encrypted := append(append([]byte{}, enc1...), enc2...)
+	parts := []objectPartInfo{
+		{Number: 1, Size: int64(len(enc1))},
+		{Number: 2, Size: int64(len(enc2))},
+	}
+
+	size := int64(len(data))
+	testCases := []struct {
+		offset, length int64
+	}{
+		{0, size},
+		{0, 1},
+		{1, 10},
+		{ssePackagePayloadSize - 1, 2},
+		{ssePackagePayloadSize, ssePackagePayloadSize},
+		{int64(len(part1)) - 5, 10},
+		{int64(len(part1)), int64(len(part2))},
+		{size - 1, 1},
+		{100, size - 200},
+	}
+	for i, testCase := range testCases {
+		encOffset, encLength, partIdx, seqNum, skip := sseEncryptedRange(parts, testCase.offset, testCase.length)
+		var buffer bytes.Buffer
+		writer, err := newSSEDecryptWriter(&buffer, key, parts, partIdx, seqNum, skip, testCase.length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = writer.Write(encrypted[encOffset : encOffset+encLength]); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if err = writer.Close(); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), data[testCase.offset:testCase.offset+testCase.length]) {
+			t.Errorf("Test %d: Decrypted range does not match", i+1)
+		}
+	}
+
+	// Modified, reordered, truncated or misplaced packages fail to
+	// decrypt.
+	tampered := append([]byte{}, enc1...)
+	tampered[ssePackageHeaderSize] ^= 0xff
+	reordered := append(append([]byte{}, enc1[ssePackageSize:2*ssePackageSize]...), enc1[:ssePackageSize]...)
+	swapped := append(append([]byte{}, enc2...), enc1...)
+	truncated := enc1[:2*ssePackageSize]
+	truncatedParts := []objectPartInfo{{Number: 1, Size: int64(len(truncated))}}
+	failCases := []struct {
+		data   []byte
+		parts  []objectPartInfo
+		length int64
+	}{
+		// Test case - 1.
+		// Modified payload.
+		{tampered, parts[:1], int64(len(part1))},
+		// Test case - 2.
+		// Packages out of order.
+		{reordered, parts[:1], 2 * ssePackagePayloadSize},
+		// Test case - 3.
+		// Parts out of order.
+		{swapped, []objectPartInfo{{Number: 1, Size: int64(len(enc2))}, {Number: 2, Size: int64(len(enc1))}}, size},
+		// Test case - 4.
+		// Last package of a part missing, truncated at a package boundary.
+		{truncated, truncatedParts, 2 * ssePackagePayloadSize},
+		// Test case - 5.
+		// Truncated within a package.
+		{enc1[:len(enc1)-1], parts[:1], int64(len(part1))},
+		// Test case - 6.
+		// Data missing at the end of the range.
+		{enc1, parts, size},
+	}
+	for i, testCase := range failCases {
+		writer, err := newSSEDecryptWriter(ioutil.Discard, key, testCase.parts, 0, 0, 0, testCase.length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = writer.Write(testCase.data); err == nil {
+			err = writer.Close()
+		}
+		if err != errObjectTampered {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, errObjectTampered, err)
+		}
+	}
+}
+
+// Tests verification of checksums while encrypting.
+func TestSSEEncryptReaderChecksums(t *testing.T) {
+	key := bytes.Repeat([]byte{'k'}, 32)
+	data := []byte("hello, world")
+	md5Sum := md5.Sum(data)
+	md5Hex := hex.EncodeToString(md5Sum[:])
+	sha256Hex := getSHA256Hash(data)
+
+	testCases := []struct {
+		data              []byte
+		md5Hex, sha256Hex string
+		expectedErr       error
+	}{
+		{data, md5Hex, sha256Hex, nil},
+		{data, "", "", nil},
+		{[]byte("hello, there"), md5Hex, "", BadDigest{}},
+		{[]byte("hello, there"), "", sha256Hex, SHA256Mismatch{}},
+		{data[:5], "", "", io.ErrUnexpectedEOF},
+	}
+	for i, testCase := range testCases {
+		reader, err := newSSEEncryptReader(bytes.NewReader(testCase.data), int64(len(data)), key, 1, testCase.md5Hex, testCase.sha256Hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = ioutil.ReadAll(reader)
+		switch testCase.expectedErr.(type) {
+		case nil:
+			if err != nil {
+				t.Errorf("Test %d: Unexpected error %v", i+1, err)
+			}
+		case BadDigest:
+			if _, ok := err.(BadDigest); !ok {
+				t.Errorf("Test %d: Expected BadDigest, got %v", i+1, err)
+			}
+		default:
+			if err != testCase.expectedErr {
+				t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedErr, err)
+			}
+		}
+	}
+}
+
+// Tests encrypting streams of unknown size.
+func TestSSEEncryptReaderUnknownSize(t *testing.T) {
+	key := bytes.Repeat([]byte{'k'}, 32)
+	for i, size := range []int{0, 1, ssePackagePayloadSize, 2*ssePackagePayloadSize + 1} {
+		data := bytes.Repeat([]byte{'d'}, size)
+		reader, err := newSSEEncryptReader(bytes.NewReader(data), -1, key, 1, "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		encrypted, err := ioutil.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if int64(len(encrypted)) != sseEncryptedSize(int64(size)) {
+			t.Fatalf("Test %d: Expected %d bytes, got %d", i+1, sseEncryptedSize(int64(size)), len(encrypted))
+		}
+
+		var buffer bytes.Buffer
+		parts := []objectPartInfo{{Number: 1, Size: int64(len(encrypted))}}
+		writer, err := newSSEDecryptWriter(&buffer, key, parts, 0, 0, 0, int64(size))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = writer.Write(encrypted); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if err = writer.Close(); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), data) {
+			t.Errorf("Test %d: Decrypted data does not match", i+1)
+		}
+	}
+}
@@ -82,10 +82,17 @@ func (m fsMetaV1) ToObjectInfo(bucket, object string, fi os.FileInfo) ObjectInfo
 	// need to remove it from m.Meta to avoid it from appearing as
 	// part of response headers. e.g, X-Minio-* or X-Amz-*.
 	delete(m.Meta, "md5Sum")
+	objInfo.VersionID, objInfo.DeleteMarker = extractVersionInfo(m.Meta)
+	objInfo.UserTags = extractObjectTags(m.Meta)
 
 	// Save all the other userdefined API.
 	objInfo.UserDefined = m.Meta
 
+	// Encrypted objects are decrypted part by part.
+	if isEncrypted(m.Meta) {
+		objInfo.Parts = m.Parts
+	}
+
 	// Success..
 	return objInfo
 }
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
@@ -237,7 +238,7 @@ func (fs fsObjects) listMultipartUploads(bucket, prefix, keyMarker, uploadIDMark
 // Implements S3 compatible ListMultipartUploads API. The resulting
 // ListMultipartsInfo structure is unmarshalled directly into XML and
 // replied back to the client.
-func (fs fsObjects) ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+func (fs fsObjects) ListMultipartUploads(ctx context.Context, bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
 	if err := checkListMultipartArgs(bucket, prefix, keyMarker, uploadIDMarker, delimiter, fs); err != nil {
 		return ListMultipartsInfo{}, err
 	}
@@ -301,7 +302,7 @@ func (fs fsObjects) newMultipartUpload(bucket string, object string, meta map[st
 // subsequent request each UUID is unique.
 //
 // Implements S3 compatible initiate multipart API.
-func (fs fsObjects) NewMultipartUpload(bucket, object string, meta map[string]string) (string, error) {
+func (fs fsObjects) NewMultipartUpload(ctx context.Context, bucket, object string, meta map[string]string) (string, error) {
 	if err := checkNewMultipartArgs(bucket, object, fs); err != nil {
 		return "", err
 	}
@@ -335,11 +336,42 @@ func partToAppend(fsMeta fsMetaV1, fsAppendMeta fsMetaV1) (part objectPartInfo,
 	return fsMeta.Parts[nextPartIndex], true
 }
 
+// CopyObjectPart - similar to PutObjectPart but reads data from an existing
+// object. Internally incoming data is written to '.minio.sys/tmp' location
+// and safely renamed to '.minio.sys/multipart' for reach parts.
+func (fs fsObjects) CopyObjectPart(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject, uploadID string, partID int, startOffset int64, length int64) (string, error) {
+	if err := checkGetObjArgs(srcBucket, srcObject); err != nil {
+		return "", err
+	}
+
+	// Initialize pipe.
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		if gerr := fs.GetObject(ctx, srcBucket, srcObject, startOffset, length, pipeWriter); gerr != nil {
+			errorIfCtx(ctx, gerr, "Unable to read %s/%s.", srcBucket, srcObject)
+			pipeWriter.CloseWithError(gerr)
+			return
+		}
+		pipeWriter.Close() // Close writer explicitly signalling we wrote all data.
+	}()
+
+	partMD5, err := fs.PutObjectPart(ctx, dstBucket, dstObject, uploadID, partID, length, pipeReader, "", "")
+	if err != nil {
+		return "", toObjectErr(err, dstBucket, dstObject)
+	}
+
+	// Explicitly close the reader.
+	pipeReader.Close()
+
+	return partMD5, nil
+}
+
 // PutObjectPart - reads incoming data until EOF for the part file on
 // an ongoing multipart transaction. Internally incoming data is
 // written to '.minio.sys/tmp' location and safely renamed to
 // '.minio.sys/multipart' for reach parts.
-func (fs fsObjects) PutObjectPart(bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (string, error) {
+func (fs fsObjects) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, size int64, data io.Reader, md5Hex string, sha256sum string) (string, error) {
 	if err := checkPutObjectPartArgs(bucket, object, fs); err != nil {
 		return "", err
 	}
@@ -543,6 +575,7 @@ func (fs fsObjects) listObjectParts(bucket, object, uploadID string, partNumberM
 	result.Object = object
 	result.UploadID = uploadID
 	result.MaxParts = maxParts
+	result.UserDefined = fsMeta.Meta
 
 	// Success.
 	return result, nil
@@ -555,7 +588,7 @@ func (fs fsObjects) listObjectParts(bucket, object, uploadID string, partNumberM
 // Implements S3 compatible ListObjectParts API. The resulting
 // ListPartsInfo structure is unmarshalled directly into XML and
 // replied back to the client.
-func (fs fsObjects) ListObjectParts(bucket, object, uploadID string, partNumberMarker, maxParts int) (ListPartsInfo, error) {
+func (fs fsObjects) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker, maxParts int) (ListPartsInfo, error) {
 	if err := checkListPartsArgs(bucket, object, fs); err != nil {
 		return ListPartsInfo{}, err
 	}
@@ -585,7 +618,7 @@ func (fs fsObjects) ListObjectParts(bucket, object, uploadID string, partNumberM
 // md5sums of all the parts.
 //
 // Implements S3 compatible Complete multipart API.
-func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, uploadID string, parts []completePart) (ObjectInfo, error) {
+func (fs fsObjects) CompleteMultipartUpload(ctx context.Context, bucket string, object string, uploadID string, parts []completePart) (objInfo ObjectInfo, err error) {
 	if err := checkCompleteMultipartArgs(bucket, object, fs); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -636,15 +669,11 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 		return ObjectInfo{}, toObjectErr(err, minioMetaMultipartBucket, fsMetaPathMultipart)
 	}
 
-	// Wait for any competing PutObject() operation on bucket/object, since same namespace
-	// would be acquired for `fs.json`.
-	fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
-	metaFile, err := fs.rwPool.Create(fsMetaPath)
-	if err != nil {
+	// Retained objects can not be replaced.
+	if err = checkObjectRetention(fs, bucket, object, "", false); err != nil {
 		fs.rwPool.Close(fsMetaPathMultipart)
-		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
-	defer metaFile.Close()
 
 	fsNSObjPath := pathJoin(fs.fsPath, bucket, object)
 
@@ -652,15 +681,12 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 	// location so that any competing GetObject/PutObject/DeleteObject do not race.
 	appendFallback := true // In case background-append did not append the required parts.
 
+	var fsTmpObjPath string
 	if isPartsSame(fsMeta.Parts, parts) {
 		err = fs.complete(bucket, object, uploadID, fsMeta)
 		if err == nil {
 			appendFallback = false
-			fsTmpObjPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, uploadID)
-			if err = fsRenameFile(fsTmpObjPath, fsNSObjPath); err != nil {
-				fs.rwPool.Close(fsMetaPathMultipart)
-				return ObjectInfo{}, toObjectErr(err, minioMetaTmpBucket, uploadID)
-			}
+			fsTmpObjPath = pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, uploadID)
 		}
 	}
 
@@ -668,11 +694,7 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 		// background append could not do append all the required parts, hence we do it here.
 		tempObj := uploadID + "-" + "part.1"
 
-		fsTmpObjPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, tempObj)
-		// Delete the temporary object in the case of a
-		// failure. If PutObject succeeds, then there would be
-		// nothing to delete.
-		defer fsRemoveFile(fsTmpObjPath)
+		fsTmpObjPath = pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, tempObj)
 
 		// Allocate staging buffer.
 		var buf = make([]byte, readSizeV1)
@@ -736,21 +758,54 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 			wfile.Close()
 			reader.Close()
 		}
+	}
 
-		if err = fsRenameFile(fsTmpObjPath, fsNSObjPath); err != nil {
-			fs.rwPool.Close(fsMetaPathMultipart)
-			return ObjectInfo{}, toObjectErr(err, minioMetaTmpBucket, uploadID)
+	// Delete the temporary object in the case of a
+	// failure. If CompleteMultipartUpload succeeds, then there would be
+	// nothing to delete.
+	defer fsRemoveFile(fsTmpObjPath)
+
+	// Save the current version of the object on versioned buckets once
+	// the new data is in place, brought back if this upload fails.
+	if err = archiveCurrentVersion(fs, bucket, object); err != nil {
+		fs.rwPool.Close(fsMetaPathMultipart)
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+	defer func() {
+		if err != nil && getBucketVersioningStatus(bucket) != "" {
+			errorIfCtx(ctx, promoteLatestVersion(fs, bucket, object), "Unable to restore %s/%s", bucket, object)
 		}
+	}()
+
+	// Wait for any competing PutObject() operation on bucket/object, since same namespace
+	// would be acquired for `fs.json`.
+	fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
+	metaFile, err := fs.rwPool.Create(fsMetaPath)
+	if err != nil {
+		fs.rwPool.Close(fsMetaPathMultipart)
+		return ObjectInfo{}, toObjectErr(traceError(err), bucket, object)
 	}
+	defer metaFile.Close()
 
-	// No need to save part info, since we have concatenated all parts.
-	fsMeta.Parts = nil
+	if err = fsRenameFile(fsTmpObjPath, fsNSObjPath); err != nil {
+		fs.rwPool.Close(fsMetaPathMultipart)
+		return ObjectInfo{}, toObjectErr(err, minioMetaTmpBucket, uploadID)
+	}
+
+	// Save info of the completed parts only, the parts have been
+	// concatenated but encrypted objects are decrypted part by part.
+	completedParts := make([]objectPartInfo, 0, len(parts))
+	for _, part := range parts {
+		completedParts = append(completedParts, fsMeta.Parts[fsMeta.ObjectPartIndex(part.PartNumber)])
+	}
+	fsMeta.Parts = completedParts
 
 	// Save additional metadata.
 	if len(fsMeta.Meta) == 0 {
 		fsMeta.Meta = make(map[string]string)
 	}
 	fsMeta.Meta["md5Sum"] = s3MD5
+	setObjectVersionID(bucket, fsMeta.Meta)
 
 	// Write all the set metadata.
 	if _, err = fsMeta.WriteTo(metaFile); err != nil {
@@ -796,7 +851,7 @@ func (fs fsObjects) CompleteMultipartUpload(bucket string, object string, upload
 // that this is an atomic idempotent operation. Subsequent calls have
 // no affect and further requests to the same uploadID would not be
 // honored.
-func (fs fsObjects) AbortMultipartUpload(bucket, object, uploadID string) error {
+func (fs fsObjects) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) error {
 	if err := checkAbortMultipartArgs(bucket, object, fs); err != nil {
 		return err
 	}
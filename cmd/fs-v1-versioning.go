@@ -0,0 +1,309 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	pathutil "path"
+	"time"
+)
+
+// Saved object versions keep their data next to `fs.json`.
+const fsVersionDataFile = "data"
+
+// GetObjectVersion - reads a version of an object, an empty versionID
+// reads the current version.
+func (fs fsObjects) GetObjectVersion(ctx context.Context, bucket, object, versionID string, startOffset int64, length int64, writer io.Writer) error {
+	if err := checkGetObjArgs(bucket, object); err != nil {
+		return err
+	}
+	if _, err := fs.statBucketDir(bucket); err != nil {
+		return toObjectErr(err, bucket)
+	}
+	return getObjectVersion(ctx, fs, bucket, object, versionID, startOffset, length, writer)
+}
+
+// GetObjectVersionInfo - reads metadata of a version of an object.
+func (fs fsObjects) GetObjectVersionInfo(ctx context.Context, bucket, object, versionID string) (ObjectInfo, error) {
+	if err := checkGetObjArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+	if _, err := fs.statBucketDir(bucket); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket)
+	}
+	return getObjectVersionInfo(fs, bucket, object, versionID)
+}
+
+// DeleteObjectVersion - removes a version of an object for good, an
+// empty versionID behaves like DeleteObject.
+func (fs fsObjects) DeleteObjectVersion(ctx context.Context, bucket, object, versionID string) (ObjectInfo, error) {
+	if err := checkDelObjArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+	if _, err := fs.statBucketDir(bucket); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket)
+	}
+	return deleteObjectVersion(ctx, fs, bucket, object, versionID)
+}
+
+// UpdateObjectMetadata - updates metadata entries of a version of an
+// object in place, an empty versionID updates the current version.
+func (fs fsObjects) UpdateObjectMetadata(ctx context.Context, bucket, object, versionID string, updates map[string]string) (ObjectInfo, error) {
+	if err := checkGetObjArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+	if _, err := fs.statBucketDir(bucket); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket)
+	}
+	return updateObjectVersionMetadata(fs, bucket, object, versionID, updates)
+}
+
+// ListObjectVersions - lists all versions of objects in a bucket.
+func (fs fsObjects) ListObjectVersions(ctx context.Context, bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int) (ListObjectVersionsInfo, error) {
+	return listObjectVersions(ctx, fs, bucket, prefix, keyMarker, versionIDMarker, delimiter, maxKeys)
+}
+
+// Returns the directory holding a saved version.
+func (fs fsObjects) versionDir(bucket, object, versionID string) string {
+	return pathJoin(fs.fsPath, minioMetaBucket, objectVersionPath(bucket, object, versionID))
+}
+
+// archiveObject - moves the current version of an object to the
+// versions area.
+func (fs fsObjects) archiveObject(bucket, object, versionID string) error {
+	minioMetaBucketDir := pathJoin(fs.fsPath, minioMetaBucket)
+	fsMetaPath := pathJoin(minioMetaBucketDir, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
+	fsObjPath := pathJoin(fs.fsPath, bucket, object)
+	versionDir := fs.versionDir(bucket, object, versionID)
+
+	// Clear any stale copy left at the destination.
+	if err := fsRemoveAll(versionDir); err != nil {
+		return toObjectErr(traceError(err), bucket, object)
+	}
+
+	// Objects written before `fs.json` was introduced get an empty one.
+	if _, err := fsStatFile(fsMetaPath); err != nil {
+		if errorCause(err) != errFileNotFound {
+			return toObjectErr(traceError(err), bucket, object)
+		}
+		wlk, cerr := fs.rwPool.Create(pathJoin(versionDir, fsMetaJSONFile))
+		if cerr != nil {
+			return toObjectErr(traceError(cerr), bucket, object)
+		}
+		fsMeta := newFSMetaV1()
+		_, cerr = fsMeta.WriteTo(wlk)
+		wlk.Close()
+		if cerr != nil {
+			return toObjectErr(cerr, bucket, object)
+		}
+	} else {
+		if err = fsRenameFile(fsMetaPath, pathJoin(versionDir, fsMetaJSONFile)); err != nil {
+			return toObjectErr(err, bucket, object)
+		}
+		// Remove the left over empty metadata directories.
+		fsDeleteFile(minioMetaBucketDir, pathutil.Dir(fsMetaPath))
+	}
+
+	if err := fsRenameFile(fsObjPath, pathJoin(versionDir, fsVersionDataFile)); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	// Remove the left over empty parent directories.
+	fsDeleteFile(pathJoin(fs.fsPath, bucket), pathutil.Dir(fsObjPath))
+	return nil
+}
+
+// restoreVersion - moves a saved version back as the current version
+// of an object.
+func (fs fsObjects) restoreVersion(bucket, object, versionID string) error {
+	minioMetaBucketDir := pathJoin(fs.fsPath, minioMetaBucket)
+	fsMetaPath := pathJoin(minioMetaBucketDir, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
+	versionDir := fs.versionDir(bucket, object, versionID)
+
+	if err := fsRenameFile(pathJoin(versionDir, fsVersionDataFile), pathJoin(fs.fsPath, bucket, object)); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	if err := fsRenameFile(pathJoin(versionDir, fsMetaJSONFile), fsMetaPath); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	// Remove the now empty version directory.
+	if err := fsDeleteFile(minioMetaBucketDir, versionDir); err != nil && err != errFileNotFound {
+		return toObjectErr(traceError(err), bucket, object)
+	}
+	return nil
+}
+
+// putDeleteMarker - saves a delete marker, delete markers only have
+// `fs.json` which carries the time of the deletion.
+func (fs fsObjects) putDeleteMarker(bucket, object, versionID string, modTime time.Time) error {
+	fsMetaPath := pathJoin(fs.versionDir(bucket, object, versionID), fsMetaJSONFile)
+	wlk, err := fs.rwPool.Create(fsMetaPath)
+	if err != nil {
+		return toObjectErr(traceError(err), bucket, object)
+	}
+
+	fsMeta := newFSMetaV1()
+	fsMeta.Meta = map[string]string{
+		versionIDMetaKey:    versionID,
+		deleteMarkerMetaKey: "true",
+	}
+	_, err = fsMeta.WriteTo(wlk)
+	wlk.Close()
+	if err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	if err = os.Chtimes(preparePath(fsMetaPath), modTime, modTime); err != nil {
+		return toObjectErr(traceError(err), bucket, object)
+	}
+	return nil
+}
+
+// getVersionInfo - reads metadata of a saved version.
+func (fs fsObjects) getVersionInfo(bucket, object, versionID string) (ObjectInfo, error) {
+	versionDir := fs.versionDir(bucket, object, versionID)
+	fsMetaPath := pathJoin(versionDir, fsMetaJSONFile)
+
+	rlk, err := fs.rwPool.Open(fsMetaPath)
+	if err != nil {
+		return ObjectInfo{}, toVersionErr(traceError(err), bucket, object, versionID)
+	}
+	defer fs.rwPool.Close(fsMetaPath)
+
+	fsMeta := fsMetaV1{}
+	if _, err = fsMeta.ReadFrom(io.NewSectionReader(rlk, 0, rlk.Size())); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	if fsMeta.Meta[deleteMarkerMetaKey] == "true" {
+		fi, serr := fsStatFile(fsMetaPath)
+		if serr != nil {
+			return ObjectInfo{}, toVersionErr(traceError(serr), bucket, object, versionID)
+		}
+		objInfo := fsMeta.ToObjectInfo(bucket, object, fi)
+		objInfo.Size = 0
+		objInfo.VersionID = versionID
+		return objInfo, nil
+	}
+
+	fi, err := fsStatFile(pathJoin(versionDir, fsVersionDataFile))
+	if err != nil {
+		return ObjectInfo{}, toVersionErr(traceError(err), bucket, object, versionID)
+	}
+	objInfo := fsMeta.ToObjectInfo(bucket, object, fi)
+	objInfo.VersionID = versionID
+	return objInfo, nil
+}
+
+// getVersion - reads data of a saved version.
+func (fs fsObjects) getVersion(ctx context.Context, bucket, object, versionID string, startOffset int64, length int64, writer io.Writer) error {
+	dataPath := pathJoin(objectVersionPath(bucket, object, versionID), fsVersionDataFile)
+	if err := fs.GetObject(ctx, minioMetaBucket, dataPath, startOffset, length, writer); err != nil {
+		return toVersionErr(err, bucket, object, versionID)
+	}
+	return nil
+}
+
+// deleteVersion - removes a saved version.
+func (fs fsObjects) deleteVersion(bucket, object, versionID string) error {
+	versionDir := fs.versionDir(bucket, object, versionID)
+	if _, err := fsStatDir(versionDir); err != nil {
+		if err == errVolumeNotFound {
+			return traceError(VersionNotFound{Bucket: bucket, Object: object, VersionID: versionID})
+		}
+		return toObjectErr(traceError(err), bucket, object)
+	}
+
+	if err := fsRemoveAll(versionDir); err != nil {
+		return toObjectErr(traceError(err), bucket, object)
+	}
+	// Remove the left over empty parent directories.
+	fsDeleteFile(pathJoin(fs.fsPath, minioMetaBucket), pathutil.Dir(versionDir))
+	return nil
+}
+
+// listVersions - lists saved versions of objects starting with prefix
+// and listed after marker, of at most maxObjects objects unless zero.
+func (fs fsObjects) listVersions(bucket, prefix, marker string, maxObjects int) (versions []ObjectInfo, isTruncated bool, err error) {
+	isLeaf := func(bucket, entry string) bool {
+		_, serr := fsStatFile(pathJoin(fs.fsPath, bucket, entry, fsMetaJSONFile))
+		return serr == nil
+	}
+	listDir := fs.listDirFactory(isLeaf)
+	isTruncated, err = walkVersions(bucket, prefix, marker, maxObjects, listDir, isLeaf, func(object, versionID string) error {
+		objInfo, vErr := fs.getVersionInfo(bucket, object, versionID)
+		if vErr != nil {
+			return vErr
+		}
+		versions = append(versions, objInfo)
+		return nil
+	})
+	return versions, isTruncated, err
+}
+
+// hasVersions - returns true if saved versions of any object of the
+// bucket exist.
+func (fs fsObjects) hasVersions(bucket string) bool {
+	entries, err := readDir(pathJoin(fs.fsPath, minioMetaBucket, versionsPrefix, bucket))
+	return err == nil && len(entries) > 0
+}
+
+// updateMetadata - rewrites `fs.json` with the updated metadata entries,
+// objects written before `fs.json` was introduced get a new one.
+func (fs fsObjects) updateMetadata(fsMetaPath string, updates map[string]string) error {
+	wlk, err := fs.rwPool.Create(fsMetaPath)
+	if err != nil {
+		return traceError(err)
+	}
+	// This close will allow for locks to be synchronized on `fs.json`.
+	defer wlk.Close()
+
+	fsMeta := newFSMetaV1()
+	if wlk.Size() > 0 {
+		if _, err = fsMeta.ReadFrom(io.NewSectionReader(wlk, 0, wlk.Size())); err != nil {
+			return err
+		}
+	}
+	if fsMeta.Meta == nil {
+		fsMeta.Meta = make(map[string]string)
+	}
+	applyMetadataUpdates(fsMeta.Meta, updates)
+
+	_, err = fsMeta.WriteTo(wlk)
+	return err
+}
+
+// updateObjectMetadata - updates metadata entries of the current
+// version of an object.
+func (fs fsObjects) updateObjectMetadata(bucket, object string, updates map[string]string) error {
+	fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
+	if err := fs.updateMetadata(fsMetaPath, updates); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	return nil
+}
+
+// updateVersionMetadata - updates metadata entries of a saved version.
+func (fs fsObjects) updateVersionMetadata(bucket, object, versionID string, updates map[string]string) error {
+	fsMetaPath := pathJoin(fs.versionDir(bucket, object, versionID), fsMetaJSONFile)
+	if err := fs.updateMetadata(fsMetaPath, updates); err != nil {
+		return toVersionErr(err, bucket, object, versionID)
+	}
+	return nil
+}
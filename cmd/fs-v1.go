@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"crypto/md5"
 	"crypto/sha256"
 	"encoding/hex"
@@ -160,6 +161,18 @@ func newFSObjectLayer(fsPath string) (ObjectLayer, error) {
 		return nil, fmt.Errorf("Unable to load all bucket policies. %s", err)
 	}
 
+	// Initialize and load bucket configurations.
+	err = initBucketConfigs(fs)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load all bucket configurations. %s", err)
+	}
+
+	// Initialize IAM users and policies.
+	err = initIAMSys(fs)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load IAM users and policies. %s", err)
+	}
+
 	// Initialize a new event notifier.
 	err = initEventNotifier(fs)
 	if err != nil {
@@ -252,7 +265,7 @@ func (fs fsObjects) statBucketDir(bucket string) (os.FileInfo, error) {
 
 // MakeBucket - create a new bucket, returns if it
 // already exists.
-func (fs fsObjects) MakeBucket(bucket string) error {
+func (fs fsObjects) MakeBucket(ctx context.Context, bucket string) error {
 	bucketDir, err := fs.getBucketDir(bucket)
 	if err != nil {
 		return toObjectErr(err, bucket)
@@ -266,7 +279,7 @@ func (fs fsObjects) MakeBucket(bucket string) error {
 }
 
 // GetBucketInfo - fetch bucket metadata info.
-func (fs fsObjects) GetBucketInfo(bucket string) (BucketInfo, error) {
+func (fs fsObjects) GetBucketInfo(ctx context.Context, bucket string) (BucketInfo, error) {
 	st, err := fs.statBucketDir(bucket)
 	if err != nil {
 		return BucketInfo{}, toObjectErr(err, bucket)
@@ -281,7 +294,7 @@ func (fs fsObjects) GetBucketInfo(bucket string) (BucketInfo, error) {
 }
 
 // ListBuckets - list all s3 compatible buckets (directories) at fsPath.
-func (fs fsObjects) ListBuckets() ([]BucketInfo, error) {
+func (fs fsObjects) ListBuckets(ctx context.Context) ([]BucketInfo, error) {
 	if err := checkPathLength(fs.fsPath); err != nil {
 		return nil, err
 	}
@@ -325,7 +338,7 @@ func (fs fsObjects) ListBuckets() ([]BucketInfo, error) {
 	// Print a user friendly message if we indeed skipped certain directories which are
 	// incompatible with S3's bucket name restrictions.
 	if len(invalidBucketNames) > 0 {
-		errorIf(errors.New("One or more invalid bucket names found"), "Skipping %s", invalidBucketNames)
+		errorIfCtx(ctx, errors.New("One or more invalid bucket names found"), "Skipping %s", invalidBucketNames)
 	}
 
 	// Sort bucket infos by bucket name.
@@ -337,12 +350,17 @@ func (fs fsObjects) ListBuckets() ([]BucketInfo, error) {
 
 // DeleteBucket - delete a bucket and all the metadata associated
 // with the bucket including pending multipart, object metadata.
-func (fs fsObjects) DeleteBucket(bucket string) error {
+func (fs fsObjects) DeleteBucket(ctx context.Context, bucket string) error {
 	bucketDir, err := fs.getBucketDir(bucket)
 	if err != nil {
 		return toObjectErr(err, bucket)
 	}
 
+	// Saved object versions keep the bucket from being deleted.
+	if fs.hasVersions(bucket) {
+		return toObjectErr(traceError(errVolumeNotEmpty), bucket)
+	}
+
 	// Attempt to delete regular bucket.
 	if err = fsRemoveDir(bucketDir); err != nil {
 		return toObjectErr(err, bucket)
@@ -368,7 +386,7 @@ func (fs fsObjects) DeleteBucket(bucket string) error {
 // CopyObject - copy object source object to destination object.
 // if source object and destination object are same we only
 // update metadata.
-func (fs fsObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string, metadata map[string]string) (ObjectInfo, error) {
+func (fs fsObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, metadata map[string]string) (ObjectInfo, error) {
 	if _, err := fs.statBucketDir(srcBucket); err != nil {
 		return ObjectInfo{}, toObjectErr(err, srcBucket)
 	}
@@ -380,8 +398,15 @@ func (fs fsObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 	}
 
 	// Check if this request is only metadata update.
+	// Versioned buckets always need a new version saved.
 	cpMetadataOnly := strings.EqualFold(pathJoin(srcBucket, srcObject), pathJoin(dstBucket, dstObject))
+	cpMetadataOnly = cpMetadataOnly && getBucketVersioningStatus(dstBucket) == ""
 	if cpMetadataOnly {
+		// Retained objects can not be replaced.
+		if err = checkObjectRetention(fs, srcBucket, srcObject, "", false); err != nil {
+			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
+		}
+
 		fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, srcBucket, srcObject, fsMetaJSONFile)
 		var wlk *lock.LockedFile
 		wlk, err = fs.rwPool.Write(fsMetaPath)
@@ -410,15 +435,15 @@ func (fs fsObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 
 	go func() {
 		startOffset := int64(0) // Read the whole file.
-		if gerr := fs.GetObject(srcBucket, srcObject, startOffset, length, pipeWriter); gerr != nil {
-			errorIf(gerr, "Unable to read %s/%s.", srcBucket, srcObject)
+		if gerr := fs.GetObject(ctx, srcBucket, srcObject, startOffset, length, pipeWriter); gerr != nil {
+			errorIfCtx(ctx, gerr, "Unable to read %s/%s.", srcBucket, srcObject)
 			pipeWriter.CloseWithError(gerr)
 			return
 		}
 		pipeWriter.Close() // Close writer explicitly signalling we wrote all data.
 	}()
 
-	objInfo, err := fs.PutObject(dstBucket, dstObject, length, pipeReader, metadata, "")
+	objInfo, err := fs.PutObject(ctx, dstBucket, dstObject, length, pipeReader, metadata, "")
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(err, dstBucket, dstObject)
 	}
@@ -435,7 +460,7 @@ func (fs fsObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 //
 // startOffset indicates the starting read location of the object.
 // length indicates the total length of the object.
-func (fs fsObjects) GetObject(bucket, object string, offset int64, length int64, writer io.Writer) (err error) {
+func (fs fsObjects) GetObject(ctx context.Context, bucket, object string, offset int64, length int64, writer io.Writer) (err error) {
 	if err = checkGetObjArgs(bucket, object); err != nil {
 		return err
 	}
@@ -525,7 +550,7 @@ func (fs fsObjects) getObjectInfo(bucket, object string) (ObjectInfo, error) {
 }
 
 // GetObjectInfo - reads object metadata and replies back ObjectInfo.
-func (fs fsObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
+func (fs fsObjects) GetObjectInfo(ctx context.Context, bucket, object string) (ObjectInfo, error) {
 	if err := checkGetObjArgs(bucket, object); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -541,7 +566,7 @@ func (fs fsObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
 // until EOF, writes data directly to configured filesystem path.
 // Additionally writes `fs.json` which carries the necessary metadata
 // for future object operations.
-func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
+func (fs fsObjects) PutObject(ctx context.Context, bucket string, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
 	// This is a special case with size as '0' and object ends with
 	// a slash separator, we treat it like a valid operation and
 	// return success.
@@ -560,19 +585,14 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 	if metadata == nil {
 		metadata = make(map[string]string)
 	}
+	setObjectVersionID(bucket, metadata)
 
 	fsMeta := newFSMetaV1()
 	fsMeta.Meta = metadata
 
-	var wlk *lock.LockedFile
-	if bucket != minioMetaBucket {
-		fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
-		wlk, err = fs.rwPool.Create(fsMetaPath)
-		if err != nil {
-			return ObjectInfo{}, toObjectErr(err, bucket, object)
-		}
-		// This close will allow for locks to be synchronized on `fs.json`.
-		defer wlk.Close()
+	// Retained objects can not be replaced.
+	if err = checkObjectRetention(fs, bucket, object, "", false); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
 
 	// Uploaded object will first be written to the temporary location which will eventually
@@ -613,7 +633,7 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 	bytesWritten, err := fsCreateFile(fsTmpObjPath, teeReader, buf, size)
 	if err != nil {
 		fsRemoveFile(fsTmpObjPath)
-		errorIf(err, "Failed to create object %s/%s", bucket, object)
+		errorIfCtx(ctx, err, "Failed to create object %s/%s", bucket, object)
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
 
@@ -651,6 +671,28 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 		}
 	}
 
+	// Save the current version of the object on versioned buckets once
+	// the new data is in place, brought back if this upload fails.
+	if err = archiveCurrentVersion(fs, bucket, object); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+	defer func() {
+		if err != nil && getBucketVersioningStatus(bucket) != "" {
+			errorIfCtx(ctx, promoteLatestVersion(fs, bucket, object), "Unable to restore %s/%s", bucket, object)
+		}
+	}()
+
+	var wlk *lock.LockedFile
+	if bucket != minioMetaBucket {
+		fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
+		wlk, err = fs.rwPool.Create(fsMetaPath)
+		if err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+		// This close will allow for locks to be synchronized on `fs.json`.
+		defer wlk.Close()
+	}
+
 	// Entire object was written to the temp location, now it's safe to rename it to the actual location.
 	fsNSObjPath := pathJoin(fs.fsPath, bucket, object)
 	if err = fsRenameFile(fsTmpObjPath, fsNSObjPath); err != nil {
@@ -675,8 +717,9 @@ func (fs fsObjects) PutObject(bucket string, object string, size int64, data io.
 }
 
 // DeleteObject - deletes an object from a bucket, this operation is destructive
-// and there are no rollbacks supported.
-func (fs fsObjects) DeleteObject(bucket, object string) error {
+// and there are no rollbacks supported. On versioned buckets a delete marker
+// is placed instead.
+func (fs fsObjects) DeleteObject(ctx context.Context, bucket, object string) error {
 	if err := checkDelObjArgs(bucket, object); err != nil {
 		return err
 	}
@@ -685,6 +728,12 @@ func (fs fsObjects) DeleteObject(bucket, object string) error {
 		return toObjectErr(err, bucket)
 	}
 
+	_, err := deleteObjectVersion(ctx, fs, bucket, object, "")
+	return err
+}
+
+// removeObject - removes the current version of an object.
+func (fs fsObjects) removeObject(bucket, object string) error {
 	minioMetaBucketDir := pathJoin(fs.fsPath, minioMetaBucket)
 	fsMetaPath := pathJoin(minioMetaBucketDir, bucketMetaPrefix, bucket, object, fsMetaJSONFile)
 	if bucket != minioMetaBucket {
@@ -765,7 +814,7 @@ func (fs fsObjects) listDirFactory(isLeaf isLeafFunc) listDirFunc {
 
 // ListObjects - list all objects at prefix upto maxKeys., optionally delimited by '/'. Maintains the list pool
 // state for future re-entrant list requests.
-func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+func (fs fsObjects) ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
 	if err := checkListObjsArgs(bucket, prefix, marker, delimiter, fs); err != nil {
 		return ListObjectsInfo{}, err
 	}
@@ -885,21 +934,21 @@ func (fs fsObjects) ListObjects(bucket, prefix, marker, delimiter string, maxKey
 }
 
 // HealObject - no-op for fs. Valid only for XL.
-func (fs fsObjects) HealObject(bucket, object string) error {
+func (fs fsObjects) HealObject(ctx context.Context, bucket, object string) error {
 	return traceError(NotImplemented{})
 }
 
 // HealBucket - no-op for fs, Valid only for XL.
-func (fs fsObjects) HealBucket(bucket string) error {
+func (fs fsObjects) HealBucket(ctx context.Context, bucket string) error {
 	return traceError(NotImplemented{})
 }
 
 // ListObjectsHeal - list all objects to be healed. Valid only for XL
-func (fs fsObjects) ListObjectsHeal(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+func (fs fsObjects) ListObjectsHeal(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
 	return ListObjectsInfo{}, traceError(NotImplemented{})
 }
 
 // ListBucketsHeal - list all buckets to be healed. Valid only for XL
-func (fs fsObjects) ListBucketsHeal() ([]BucketInfo, error) {
+func (fs fsObjects) ListBucketsHeal(ctx context.Context) ([]BucketInfo, error) {
 	return []BucketInfo{}, traceError(NotImplemented{})
 }
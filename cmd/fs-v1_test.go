@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -63,9 +64,9 @@ func TestFSShutdown(t *testing.T) {
 		obj := initFSObjects(disk, t)
 		fs := obj.(*fsObjects)
 		objectContent := "12345"
-		obj.MakeBucket(bucketName)
+		obj.MakeBucket(context.Background(), bucketName)
 		sha256sum := ""
-		obj.PutObject(bucketName, objectName, int64(len(objectContent)), bytes.NewReader([]byte(objectContent)), nil, sha256sum)
+		obj.PutObject(context.Background(), bucketName, objectName, int64(len(objectContent)), bytes.NewReader([]byte(objectContent)), nil, sha256sum)
 		return fs, disk
 	}
 
@@ -79,7 +80,7 @@ func TestFSShutdown(t *testing.T) {
 	// Test Shutdown with faulty disk
 	for i := 1; i <= 5; i++ {
 		fs, disk := prepareTest()
-		fs.DeleteObject(bucketName, objectName)
+		fs.DeleteObject(context.Background(), bucketName, objectName)
 		removeAll(disk)
 		if err := fs.Shutdown(); err != nil {
 			t.Fatal(i, ", Got unexpected fs shutdown error: ", err)
@@ -138,10 +139,10 @@ func TestFSGetBucketInfo(t *testing.T) {
 	fs := obj.(*fsObjects)
 	bucketName := "bucket"
 
-	obj.MakeBucket(bucketName)
+	obj.MakeBucket(context.Background(), bucketName)
 
 	// Test with valid parameters
-	info, err := fs.GetBucketInfo(bucketName)
+	info, err := fs.GetBucketInfo(context.Background(), bucketName)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -150,14 +151,14 @@ func TestFSGetBucketInfo(t *testing.T) {
 	}
 
 	// Test with inexistant bucket
-	_, err = fs.GetBucketInfo("a")
+	_, err = fs.GetBucketInfo(context.Background(), "a")
 	if !isSameType(errorCause(err), BucketNameInvalid{}) {
 		t.Fatal("BucketNameInvalid error not returned")
 	}
 
 	// Check for buckets and should get disk not found.
 	removeAll(disk)
-	_, err = fs.GetBucketInfo(bucketName)
+	_, err = fs.GetBucketInfo(context.Background(), bucketName)
 	if !isSameType(errorCause(err), BucketNotFound{}) {
 		t.Fatal("BucketNotFound error not returned")
 	}
@@ -174,34 +175,34 @@ func TestFSDeleteObject(t *testing.T) {
 	bucketName := "bucket"
 	objectName := "object"
 
-	obj.MakeBucket(bucketName)
+	obj.MakeBucket(context.Background(), bucketName)
 	sha256sum := ""
-	obj.PutObject(bucketName, objectName, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, sha256sum)
+	obj.PutObject(context.Background(), bucketName, objectName, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, sha256sum)
 
 	// Test with invalid bucket name
-	if err := fs.DeleteObject("fo", objectName); !isSameType(errorCause(err), BucketNameInvalid{}) {
+	if err := fs.DeleteObject(context.Background(), "fo", objectName); !isSameType(errorCause(err), BucketNameInvalid{}) {
 		t.Fatal("Unexpected error: ", err)
 	}
 	// Test with bucket does not exist
-	if err := fs.DeleteObject("foobucket", "fooobject"); !isSameType(errorCause(err), BucketNotFound{}) {
+	if err := fs.DeleteObject(context.Background(), "foobucket", "fooobject"); !isSameType(errorCause(err), BucketNotFound{}) {
 		t.Fatal("Unexpected error: ", err)
 	}
 	// Test with invalid object name
-	if err := fs.DeleteObject(bucketName, "\\"); !isSameType(errorCause(err), ObjectNameInvalid{}) {
+	if err := fs.DeleteObject(context.Background(), bucketName, "\\"); !isSameType(errorCause(err), ObjectNameInvalid{}) {
 		t.Fatal("Unexpected error: ", err)
 	}
 	// Test with object does not exist.
-	if err := fs.DeleteObject(bucketName, "foooobject"); !isSameType(errorCause(err), ObjectNotFound{}) {
+	if err := fs.DeleteObject(context.Background(), bucketName, "foooobject"); !isSameType(errorCause(err), ObjectNotFound{}) {
 		t.Fatal("Unexpected error: ", err)
 	}
 	// Test with valid condition
-	if err := fs.DeleteObject(bucketName, objectName); err != nil {
+	if err := fs.DeleteObject(context.Background(), bucketName, objectName); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
 	// Delete object should err disk not found.
 	removeAll(disk)
-	if err := fs.DeleteObject(bucketName, objectName); err != nil {
+	if err := fs.DeleteObject(context.Background(), bucketName, objectName); err != nil {
 		if !isSameType(errorCause(err), BucketNotFound{}) {
 			t.Fatal("Unexpected error: ", err)
 		}
@@ -219,29 +220,29 @@ func TestFSDeleteBucket(t *testing.T) {
 	fs := obj.(*fsObjects)
 	bucketName := "bucket"
 
-	err := obj.MakeBucket(bucketName)
+	err := obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
 	// Test with an invalid bucket name
-	if err = fs.DeleteBucket("fo"); !isSameType(errorCause(err), BucketNameInvalid{}) {
+	if err = fs.DeleteBucket(context.Background(), "fo"); !isSameType(errorCause(err), BucketNameInvalid{}) {
 		t.Fatal("Unexpected error: ", err)
 	}
 	// Test with an inexistant bucket
-	if err = fs.DeleteBucket("foobucket"); !isSameType(errorCause(err), BucketNotFound{}) {
+	if err = fs.DeleteBucket(context.Background(), "foobucket"); !isSameType(errorCause(err), BucketNotFound{}) {
 		t.Fatal("Unexpected error: ", err)
 	}
 	// Test with a valid case
-	if err = fs.DeleteBucket(bucketName); err != nil {
+	if err = fs.DeleteBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
-	obj.MakeBucket(bucketName)
+	obj.MakeBucket(context.Background(), bucketName)
 
 	// Delete bucker should get error disk not found.
 	removeAll(disk)
-	if err = fs.DeleteBucket(bucketName); err != nil {
+	if err = fs.DeleteBucket(context.Background(), bucketName); err != nil {
 		if !isSameType(errorCause(err), BucketNotFound{}) {
 			t.Fatal("Unexpected error: ", err)
 		}
@@ -258,7 +259,7 @@ func TestFSListBuckets(t *testing.T) {
 	fs := obj.(*fsObjects)
 
 	bucketName := "bucket"
-	if err := obj.MakeBucket(bucketName); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
@@ -273,7 +274,7 @@ func TestFSListBuckets(t *testing.T) {
 	f.Close()
 
 	// Test list buckets to have only one entry.
-	buckets, err := fs.ListBuckets()
+	buckets, err := fs.ListBuckets(context.Background())
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
@@ -284,7 +285,7 @@ func TestFSListBuckets(t *testing.T) {
 	// Test ListBuckets with disk not found.
 	removeAll(disk)
 
-	if _, err := fs.ListBuckets(); err != nil {
+	if _, err := fs.ListBuckets(context.Background()); err != nil {
 		if errorCause(err) != errDiskNotFound {
 			t.Fatal("Unexpected error: ", err)
 		}
@@ -292,7 +293,7 @@ func TestFSListBuckets(t *testing.T) {
 
 	longPath := fmt.Sprintf("%0256d", 1)
 	fs.fsPath = longPath
-	if _, err := fs.ListBuckets(); err != nil {
+	if _, err := fs.ListBuckets(context.Background()); err != nil {
 		if errorCause(err) != errFileNameTooLong {
 			t.Fatal("Unexpected error: ", err)
 		}
@@ -305,7 +306,7 @@ func TestFSHealObject(t *testing.T) {
 	defer removeAll(disk)
 
 	obj := initFSObjects(disk, t)
-	err := obj.HealObject("bucket", "object")
+	err := obj.HealObject(context.Background(), "bucket", "object")
 	if err == nil || !isSameType(errorCause(err), NotImplemented{}) {
 		t.Fatalf("Heal Object should return NotImplemented error ")
 	}
@@ -317,7 +318,7 @@ func TestFSListObjectsHeal(t *testing.T) {
 	defer removeAll(disk)
 
 	obj := initFSObjects(disk, t)
-	_, err := obj.ListObjectsHeal("bucket", "prefix", "marker", "delimiter", 1000)
+	_, err := obj.ListObjectsHeal(context.Background(), "bucket", "prefix", "marker", "delimiter", 1000)
 	if err == nil || !isSameType(errorCause(err), NotImplemented{}) {
 		t.Fatalf("Heal Object should return NotImplemented error ")
 	}
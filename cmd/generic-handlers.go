@@ -19,6 +19,7 @@ package cmd
 import (
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -39,6 +40,63 @@ func registerHandlers(mux *router.Router, handlerFns ...HandlerFunc) http.Handle
 	return f
 }
 
+// Types of requests served.
+const (
+	requestTypeS3        = "s3"
+	requestTypeAdmin     = "admin"
+	requestTypeBrowser   = "browser"
+	requestTypeInternode = "internode"
+)
+
+// Returns the type of an HTTP request, internode RPC calls are
+// typed by the RPC servers.
+func getRequestType(r *http.Request) string {
+	switch {
+	case r.Header.Get(minioAdminOpHeader) != "":
+		return requestTypeAdmin
+	case strings.HasPrefix(r.URL.Path, reservedBucket+"/"):
+		return requestTypeBrowser
+	}
+	return requestTypeS3
+}
+
+// requestIDHandler - assigns a unique ID to every request, returned in
+// the x-amz-request-id header. Log entries of errors hit while serving
+// the request carry its ID.
+type requestIDHandler struct {
+	handler http.Handler
+}
+
+func setRequestIDHandler(h http.Handler) http.Handler {
+	return requestIDHandler{h}
+}
+
+// Returns the request info of a request, S3 requests carry the
+// bucket, object and operation they address.
+func newReqInfo(r *http.Request, requestID string) *reqInfo {
+	info := &reqInfo{
+		RequestID:  requestID,
+		RemoteAddr: r.RemoteAddr,
+	}
+	switch getRequestType(r) {
+	case requestTypeAdmin:
+		info.API = r.Header.Get(minioAdminOpHeader)
+	case requestTypeBrowser:
+		info.API = strings.TrimPrefix(r.URL.Path, reservedBucket)
+	default:
+		info.Bucket, info.Object = urlPath2BucketObjectName(r.URL)
+		info.API = getAccessLogOperation(r, info.Object)
+	}
+	return info
+}
+
+func (h requestIDHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := mustGetRequestID(time.Now().UTC())
+	w.Header().Set(responseRequestIDKey, requestID)
+	ctx := contextWithReqInfo(r.Context(), newReqInfo(r, requestID))
+	h.handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // Adds limiting body size middleware
 
 // Maximum allowed form data field values. 64MiB is a guessed practical value
@@ -269,7 +327,9 @@ var defaultAllowableHTTPMethods = []string{
 	httpOPTIONS,
 }
 
-// setCorsHandler handler for CORS (Cross Origin Resource Sharing)
+// setCorsHandler handler for CORS (Cross Origin Resource Sharing),
+// requests to buckets with a CORS configuration are evaluated against
+// its rules, all other requests get a permissive default policy.
 func setCorsHandler(h http.Handler) http.Handler {
 	c := cors.New(cors.Options{
 		AllowedOrigins: []string{"*"},
@@ -277,7 +337,79 @@ func setCorsHandler(h http.Handler) http.Handler {
 		AllowedHeaders: []string{"*"},
 		ExposedHeaders: []string{"ETag"},
 	})
-	return c.Handler(h)
+	return corsHandler{handler: h, defaultHandler: c.Handler(h)}
+}
+
+// Evaluates per bucket CORS rules.
+type corsHandler struct {
+	handler        http.Handler
+	defaultHandler http.Handler
+}
+
+func (h corsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, _ := urlPath2BucketObjectName(r.URL)
+	cCfg := getBucketCors(bucket)
+	if cCfg == nil {
+		h.defaultHandler.ServeHTTP(w, r)
+		return
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		// Not a cross origin request.
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Add("Vary", "Origin")
+	preflightMethod := r.Header.Get("Access-Control-Request-Method")
+	if r.Method == httpOPTIONS && preflightMethod != "" {
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+		var reqHeaders []string
+		for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				reqHeaders = append(reqHeaders, header)
+			}
+		}
+
+		rule := cCfg.matchRule(origin, preflightMethod, reqHeaders)
+		if rule == nil {
+			writeErrorResponse(w, ErrCorsForbidden, r.URL)
+			return
+		}
+		setCorsResponseHeaders(w, rule, origin)
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(rule.AllowedMethods, ", "))
+		if len(reqHeaders) > 0 {
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(reqHeaders, ", "))
+		}
+		if rule.MaxAgeSeconds > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(rule.MaxAgeSeconds))
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Actual requests are served either way, browsers refuse to
+	// hand the response to scripts without the CORS headers.
+	if rule := cCfg.matchRule(origin, r.Method, nil); rule != nil {
+		setCorsResponseHeaders(w, rule, origin)
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// Sets the CORS response headers common to preflight and actual requests.
+func setCorsResponseHeaders(w http.ResponseWriter, rule *corsRule, origin string) {
+	if rule.allowsAnyOrigin() {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(rule.ExposeHeaders) > 0 {
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(rule.ExposeHeaders, ", "))
+	}
 }
 
 // setIgnoreResourcesHandler -
@@ -310,22 +442,12 @@ func ignoreNotImplementedObjectResources(req *http.Request) bool {
 
 // List of not implemented bucket queries
 var notimplementedBucketResourceNames = map[string]bool{
-	"acl":            true,
-	"cors":           true,
-	"lifecycle":      true,
-	"logging":        true,
-	"replication":    true,
-	"tagging":        true,
-	"versions":       true,
 	"requestPayment": true,
-	"versioning":     true,
-	"website":        true,
 }
 
 // List of not implemented object queries
 var notimplementedObjectResourceNames = map[string]bool{
 	"torrent": true,
-	"acl":     true,
 	"policy":  true,
 }
 
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -88,3 +89,137 @@ func TestGuessIsBrowser(t *testing.T) {
 		t.Fatal("Test shouldn't report as browser for a non browser request.")
 	}
 }
+
+// Tests CORS handling of buckets with and without a CORS configuration.
+func TestCorsHandler(t *testing.T) {
+	globalBucketCors.Set("private", &corsConfig{
+		CORSRules: []corsRule{
+			{
+				AllowedOrigins: []string{"https://*.example.com"},
+				AllowedMethods: []string{httpPUT, httpPOST},
+				AllowedHeaders: []string{"Content-*"},
+				ExposeHeaders:  []string{"ETag"},
+				MaxAgeSeconds:  3000,
+			},
+			{
+				AllowedOrigins: []string{"*"},
+				AllowedMethods: []string{httpGET},
+			},
+		},
+	})
+	defer globalBucketCors.Set("private", nil)
+
+	handler := setCorsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		method         string
+		path           string
+		origin         string
+		preflight      string
+		reqHeaders     string
+		expectedCode   int
+		expectedAllow  string
+		expectedMaxAge string
+	}{
+		// 1. Bucket without CORS configuration allows all origins.
+		{httpGET, "/public/object", "https://other.org", "", "", http.StatusOK, "https://other.org", ""},
+		// 2. Preflight matching the first rule.
+		{httpOPTIONS, "/private/object", "https://www.example.com", httpPUT, "content-type", http.StatusOK, "https://www.example.com", "3000"},
+		// 3. Preflight with a header not allowed.
+		{httpOPTIONS, "/private/object", "https://www.example.com", httpPUT, "x-amz-acl", http.StatusForbidden, "", ""},
+		// 4. Preflight from an origin not allowed.
+		{httpOPTIONS, "/private/object", "https://other.org", httpPUT, "", http.StatusForbidden, "", ""},
+		// 5. Actual request matching the wildcard rule.
+		{httpGET, "/private/object", "https://other.org", "", "", http.StatusOK, "*", ""},
+		// 6. Actual request not matching any rule is served without CORS headers.
+		{httpDELETE, "/private/object", "https://www.example.com", "", "", http.StatusOK, "", ""},
+		// 7. Request without origin.
+		{httpPUT, "/private/object", "", "", "", http.StatusOK, "", ""},
+	}
+
+	for i, testCase := range testCases {
+		r, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.path, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if testCase.origin != "" {
+			r.Header.Set("Origin", testCase.origin)
+		}
+		if testCase.preflight != "" {
+			r.Header.Set("Access-Control-Request-Method", testCase.preflight)
+		}
+		if testCase.reqHeaders != "" {
+			r.Header.Set("Access-Control-Request-Headers", testCase.reqHeaders)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != testCase.expectedCode {
+			t.Errorf("Test %d: Expected status %d, got %d", i+1, testCase.expectedCode, w.Code)
+		}
+		if allow := w.Header().Get("Access-Control-Allow-Origin"); allow != testCase.expectedAllow {
+			t.Errorf("Test %d: Expected allowed origin %q, got %q", i+1, testCase.expectedAllow, allow)
+		}
+		if maxAge := w.Header().Get("Access-Control-Max-Age"); maxAge != testCase.expectedMaxAge {
+			t.Errorf("Test %d: Expected max age %q, got %q", i+1, testCase.expectedMaxAge, maxAge)
+		}
+	}
+}
+
+// Tests the request ID handler.
+func TestRequestIDHandler(t *testing.T) {
+	var gotInfo *reqInfo
+	handler := setRequestIDHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotInfo = reqInfoFromContext(r.Context())
+		setCommonHeaders(w)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		method         string
+		path           string
+		expectedAPI    string
+		expectedBucket string
+		expectedObject string
+	}{
+		// Test case - 1.
+		// Object request.
+		{httpGET, "/bucket/photos/1.jpg", "REST.GET.OBJECT", "bucket", "photos/1.jpg"},
+		// Test case - 2.
+		// Bucket request.
+		{httpPUT, "/bucket", "REST.PUT.BUCKET", "bucket", ""},
+		// Test case - 3.
+		// Browser request.
+		{httpPOST, reservedBucket + "/webrpc", "/webrpc", "", ""},
+	}
+	requestIDs := make(map[string]bool)
+	for i, testCase := range testCases {
+		r, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.path, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		r.RemoteAddr = "127.0.0.1:12345"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		requestID := w.Header().Get(responseRequestIDKey)
+		if requestID == "" || requestIDs[requestID] {
+			t.Fatalf("Test %d: Unexpected request ID %q", i+1, requestID)
+		}
+		requestIDs[requestID] = true
+		if gotInfo == nil {
+			t.Fatalf("Test %d: Request info missing", i+1)
+		}
+		expectedInfo := reqInfo{
+			RequestID:  requestID,
+			RemoteAddr: "127.0.0.1:12345",
+			API:        testCase.expectedAPI,
+			Bucket:     testCase.expectedBucket,
+			Object:     testCase.expectedObject,
+		}
+		if *gotInfo != expectedInfo {
+			t.Errorf("Test %d: Expected %+v, got %+v", i+1, expectedInfo, *gotInfo)
+		}
+	}
+}
@@ -38,7 +38,7 @@ const (
 
 // minio configuration related constants.
 const (
-	globalMinioConfigVersion      = "13"
+	globalMinioConfigVersion      = "19"
 	globalMinioConfigDir          = ".minio"
 	globalMinioCertsDir           = "certs"
 	globalMinioCertsCADir         = "CAs"
@@ -116,6 +116,15 @@ var (
 	// Secret key passed from the environment
 	globalEnvSecretKey = os.Getenv("MINIO_SECRET_KEY")
 
+	// Domain buckets are served as static websites on, a bucket is
+	// reached at `<bucket>.<domain>`. Set by MINIO_WEBSITE_DOMAIN.
+	globalWebsiteDomain = strings.ToLower(os.Getenv("MINIO_WEBSITE_DOMAIN"))
+
+	// Metrics are served to anyone when MINIO_PROMETHEUS_AUTH_TYPE is
+	// set to 'public', otherwise requests need a JWT of the server
+	// credentials.
+	globalIsPrometheusPublic = strings.EqualFold(os.Getenv("MINIO_PROMETHEUS_AUTH_TYPE"), "public")
+
 	// url.URL endpoints of disks that belong to the object storage.
 	globalEndpoints = []*url.URL{}
 
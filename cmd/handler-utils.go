@@ -51,7 +51,7 @@ func isValidLocationConstraint(r *http.Request) (s3Error APIErrorCode) {
 		}
 		return s3Error
 	}
-	errorIf(err, "Unable to xml decode location constraint")
+	errorIfCtx(r.Context(), err, "Unable to xml decode location constraint")
 	// Treat all other failures as XML parsing errors.
 	return ErrMalformedXML
 }
@@ -0,0 +1,147 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+
+	router "github.com/gorilla/mux"
+)
+
+// Health checks are served to anyone, probes of orchestrators and load
+// balancers do not sign their requests.
+
+const (
+	// Paths of the health checks below the reserved bucket.
+	healthLivenessPath  = "/health/live"
+	healthReadinessPath = "/health/ready"
+	healthClusterPath   = "/health/cluster"
+)
+
+// registerHealthRouter - Add handler functions for the health check routes.
+func registerHealthRouter(mux *router.Router) {
+	// Health router
+	healthRouter := mux.NewRoute().PathPrefix(reservedBucket).Subrouter()
+
+	// Liveness
+	healthRouter.Methods("GET", "HEAD").Path(healthLivenessPath).HandlerFunc(livenessHandler)
+	// Readiness
+	healthRouter.Methods("GET", "HEAD").Path(healthReadinessPath).HandlerFunc(readinessHandler)
+	// Cluster quorum
+	healthRouter.Methods("GET", "HEAD").Path(healthClusterPath).HandlerFunc(clusterHealthHandler)
+}
+
+// livenessHandler - GET /minio/health/live
+// ----------
+// Succeeds as long as the server is able to serve requests.
+func livenessHandler(w http.ResponseWriter, r *http.Request) {
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// readinessHandler - GET /minio/health/ready
+// ----------
+// Succeeds once the object layer is initialized, which for XL means the
+// format of the disks is loaded.
+func readinessHandler(w http.ResponseWriter, r *http.Request) {
+	if newObjectLayerFn() == nil {
+		writeResponse(w, http.StatusServiceUnavailable, nil, mimeNone)
+		return
+	}
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// clusterHealth - quorum status returned by the cluster health check.
+type clusterHealth struct {
+	// Disks of the setup and their state as seen by this node.
+	Disks        int `json:"disks"`
+	OnlineDisks  int `json:"onlineDisks"`
+	OfflineDisks int `json:"offlineDisks"`
+
+	// Minimum online disks required for reads and writes.
+	ReadQuorum  int `json:"readQuorum"`
+	WriteQuorum int `json:"writeQuorum"`
+
+	// Names of the offline disks this node has a connection to, disks
+	// which were unreachable at startup are only counted.
+	Offline []string `json:"offline,omitempty"`
+}
+
+// Returns the quorum status of an XL object layer.
+func getClusterHealth(xl *xlObjects) clusterHealth {
+	storageInfo := xl.StorageInfo()
+	health := clusterHealth{
+		Disks:       len(globalEndpoints),
+		ReadQuorum:  storageInfo.Backend.ReadQuorum,
+		WriteQuorum: storageInfo.Backend.WriteQuorum,
+	}
+	for _, storageDisk := range xl.storageDisks {
+		if storageDisk == nil {
+			health.OfflineDisks++
+			continue
+		}
+		// Disks which fail to report their info can't serve requests.
+		if _, err := storageDisk.DiskInfo(); err != nil {
+			health.OfflineDisks++
+			health.Offline = append(health.Offline, storageDisk.String())
+			continue
+		}
+		health.OnlineDisks++
+	}
+	if health.Disks < len(xl.storageDisks) {
+		health.Disks = len(xl.storageDisks)
+	}
+	return health
+}
+
+// clusterHealthHandler - GET /minio/health/cluster
+// ----------
+// Succeeds if enough disks are online to achieve read and write
+// quorum. For XL the response body reports the disks which are
+// offline.
+func clusterHealthHandler(w http.ResponseWriter, r *http.Request) {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		writeResponse(w, http.StatusServiceUnavailable, nil, mimeNone)
+		return
+	}
+
+	xl, ok := objAPI.(*xlObjects)
+	if !ok {
+		// A single disk is always in quorum.
+		writeSuccessResponseHeadersOnly(w)
+		return
+	}
+
+	health := getClusterHealth(xl)
+	healthBytes, err := json.Marshal(health)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to marshal cluster health.")
+		writeResponse(w, http.StatusInternalServerError, nil, mimeNone)
+		return
+	}
+
+	// Write quorum is never below read quorum.
+	status := http.StatusOK
+	if health.OnlineDisks < health.ReadQuorum || health.OnlineDisks < health.WriteQuorum {
+		status = http.StatusServiceUnavailable
+	}
+	if r.Method == httpHEAD {
+		healthBytes = nil
+	}
+	writeResponse(w, status, healthBytes, mimeJSON)
+}
@@ -0,0 +1,118 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	router "github.com/gorilla/mux"
+)
+
+// Tests the health checks before and after the object layer is
+// initialized and with disks offline.
+func TestHealthHandlers(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	mux := router.NewRouter()
+	registerHealthRouter(mux)
+
+	// Sends an anonymous request and returns the recorded response.
+	sendRequest := func(method, path string) *httptest.ResponseRecorder {
+		req, err := newTestRequest(method, reservedBucket+path, 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The object layer is not initialized yet.
+	resetGlobalObjectAPI()
+	testCases := []struct {
+		path               string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// The server is alive.
+		{healthLivenessPath, http.StatusOK},
+		// Test case - 2.
+		// The server is not ready.
+		{healthReadinessPath, http.StatusServiceUnavailable},
+		// Test case - 3.
+		// Quorum is unknown.
+		{healthClusterPath, http.StatusServiceUnavailable},
+	}
+	for i, testCase := range testCases {
+		if rec := sendRequest("GET", testCase.path); rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: Expected the response status to be `%d`, but instead found `%d`", i+1, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	objLayer, xlDirs, err := initTestXLObjLayer()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for health check tests.")
+	}
+	defer removeRoots(xlDirs)
+	defer resetGlobalObjectAPI()
+
+	if rec := sendRequest("HEAD", healthReadinessPath); rec.Code != http.StatusOK {
+		t.Errorf("Expected the response status to be `%d`, but instead found `%d`", http.StatusOK, rec.Code)
+	}
+
+	xl := objLayer.(*xlObjects)
+	storageDisks := xl.storageDisks
+	defer func() { xl.storageDisks = storageDisks }()
+	for _, offlineDisks := range []int{0, len(storageDisks) - xl.writeQuorum, len(storageDisks) - xl.writeQuorum + 1} {
+		xl.storageDisks = make([]StorageAPI, len(storageDisks))
+		copy(xl.storageDisks, storageDisks)
+		for i := 0; i < offlineDisks; i++ {
+			// Any error reported by a disk takes it offline.
+			diskErr := errFaultyDisk
+			if i%2 == 1 {
+				diskErr = errDiskFull
+			}
+			xl.storageDisks[i] = newNaughtyDisk(storageDisks[i].(*retryStorage), nil, diskErr)
+		}
+
+		expectedRespStatus := http.StatusOK
+		if len(storageDisks)-offlineDisks < xl.writeQuorum {
+			expectedRespStatus = http.StatusServiceUnavailable
+		}
+		rec := sendRequest("GET", healthClusterPath)
+		if rec.Code != expectedRespStatus {
+			t.Errorf("%d offline disks: Expected the response status to be `%d`, but instead found `%d`", offlineDisks, expectedRespStatus, rec.Code)
+		}
+		health := clusterHealth{}
+		if err = json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+			t.Fatalf("%d offline disks: Unable to parse response %s", offlineDisks, err)
+		}
+		if health.OfflineDisks != offlineDisks || len(health.Offline) != offlineDisks {
+			t.Errorf("%d offline disks: Unexpected cluster health %+v", offlineDisks, health)
+		}
+		if health.ReadQuorum != xl.readQuorum || health.WriteQuorum != xl.writeQuorum {
+			t.Errorf("%d offline disks: Unexpected quorum %+v", offlineDisks, health)
+		}
+	}
+}
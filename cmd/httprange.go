@@ -132,3 +132,42 @@ func parseRequestRange(rangeString string, resourceSize int64) (hrange *httpRang
 
 	return &httpRange{offsetBegin, offsetEnd, resourceSize}, nil
 }
+
+// parseCopyPartRange - parses x-amz-copy-source-range of UploadPartCopy,
+// unlike Range only the form "bytes=first-last" is allowed. errInvalidRange
+// is returned if the range is not within the source object.
+func parseCopyPartRange(rangeString string, resourceSize int64) (hrange *httpRange, err error) {
+	byteRangeString := strings.TrimPrefix(rangeString, byteRangePrefix)
+	sepIndex := strings.Index(byteRangeString, "-")
+	if !strings.HasPrefix(rangeString, byteRangePrefix) || sepIndex == -1 {
+		return nil, fmt.Errorf("'%s' is not of the form bytes=first-last", rangeString)
+	}
+
+	offsetBeginString := byteRangeString[:sepIndex]
+	offsetEndString := byteRangeString[sepIndex+1:]
+	if !validBytePos.MatchString(offsetBeginString) || !validBytePos.MatchString(offsetEndString) {
+		return nil, fmt.Errorf("'%s' is not of the form bytes=first-last", rangeString)
+	}
+
+	offsetBegin, err := strconv.ParseInt(offsetBeginString, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' does not have a valid first byte position value", rangeString)
+	}
+	offsetEnd, err := strconv.ParseInt(offsetEndString, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' does not have a valid last byte position value", rangeString)
+	}
+
+	// Last byte position is not greater than first byte position. eg. "bytes=5-2"
+	if offsetBegin > offsetEnd {
+		return nil, fmt.Errorf("'%s' does not have valid range value", rangeString)
+	}
+
+	// Unlike Range the last byte position is not truncated, the whole
+	// range has to be within the source object.
+	if offsetEnd >= resourceSize {
+		return nil, errInvalidRange
+	}
+
+	return &httpRange{offsetBegin, offsetEnd, resourceSize}, nil
+}
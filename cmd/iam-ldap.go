@@ -0,0 +1,192 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/teamwork/minio/pkg/ldap"
+)
+
+const (
+	// Environment variables configuring LDAP user authentication.
+	envLDAPServerURL          = "MINIO_LDAP_SERVER_URL"
+	envLDAPUserDNFormat       = "MINIO_LDAP_USER_DN_FORMAT"
+	envLDAPGroupSearchBaseDN  = "MINIO_LDAP_GROUP_SEARCH_BASE_DN"
+	envLDAPGroupSearchFilter  = "MINIO_LDAP_GROUP_SEARCH_FILTER"
+	envLDAPGroupNameAttribute = "MINIO_LDAP_GROUP_NAME_ATTRIBUTE"
+	envLDAPStartTLS           = "MINIO_LDAP_STARTTLS"
+	envLDAPTLSSkipVerify      = "MINIO_LDAP_TLS_SKIP_VERIFY"
+
+	// Attribute of group entries holding the group name by default.
+	defaultLDAPGroupNameAttribute = "cn"
+)
+
+var (
+	errLDAPNotConfigured  = errors.New("LDAP authentication is not configured")
+	errLDAPAuthentication = errors.New("LDAP authentication failed, check your username and password")
+)
+
+// ldapConfig - LDAP directory users are authenticated against. Users
+// bind with the DN built from UserDNFormat, the groups they belong to
+// are searched with GroupSearchFilter under GroupSearchBaseDN.
+type ldapConfig struct {
+	ServerURL          string `json:"serverURL"`
+	UserDNFormat       string `json:"userDNFormat"`
+	GroupSearchBaseDN  string `json:"groupSearchBaseDN"`
+	GroupSearchFilter  string `json:"groupSearchFilter"`
+	GroupNameAttribute string `json:"groupNameAttribute"`
+	StartTLS           bool   `json:"startTLS"`
+	TLSSkipVerify      bool   `json:"tlsSkipVerify"`
+}
+
+// Global LDAP configuration, nil if LDAP authentication is disabled.
+var globalLDAPConfig *ldapConfig
+
+// newLDAPConfig - returns the LDAP configuration, nil if no server URL
+// is configured. The environment takes precedence over the `ldap`
+// section of the config file when `MINIO_LDAP_SERVER_URL` is set.
+func newLDAPConfig() (*ldapConfig, error) {
+	cfg := serverConfig.GetLDAP()
+	if serverURL := os.Getenv(envLDAPServerURL); serverURL != "" {
+		cfg = ldapConfig{
+			ServerURL:          serverURL,
+			UserDNFormat:       os.Getenv(envLDAPUserDNFormat),
+			GroupSearchBaseDN:  os.Getenv(envLDAPGroupSearchBaseDN),
+			GroupSearchFilter:  os.Getenv(envLDAPGroupSearchFilter),
+			GroupNameAttribute: os.Getenv(envLDAPGroupNameAttribute),
+			StartTLS:           strings.EqualFold(os.Getenv(envLDAPStartTLS), "on"),
+			TLSSkipVerify:      strings.EqualFold(os.Getenv(envLDAPTLSSkipVerify), "on"),
+		}
+	}
+	if cfg.ServerURL == "" {
+		return nil, nil
+	}
+	if cfg.GroupNameAttribute == "" {
+		cfg.GroupNameAttribute = defaultLDAPGroupNameAttribute
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// validate - checks the configuration is complete.
+func (cfg *ldapConfig) validate() error {
+	if !strings.HasPrefix(cfg.ServerURL, "ldap://") && !strings.HasPrefix(cfg.ServerURL, "ldaps://") {
+		return errors.New("LDAP server URL must be an ldap:// or ldaps:// URL")
+	}
+	if cfg.StartTLS && strings.HasPrefix(cfg.ServerURL, "ldaps://") {
+		return errors.New("LDAP StartTLS cannot be used with an ldaps:// URL")
+	}
+	if strings.Count(cfg.UserDNFormat, "%s") != 1 {
+		return errors.New("LDAP user DN format must contain %s exactly once")
+	}
+	if (cfg.GroupSearchBaseDN == "") != (cfg.GroupSearchFilter == "") {
+		return errors.New("LDAP group search base DN and filter must be set together")
+	}
+	if cfg.GroupSearchFilter != "" {
+		if _, err := ldap.CompileFilter(cfg.groupSearchFilter("user", "uid=user")); err != nil {
+			return fmt.Errorf("LDAP group search filter is invalid, %s", err)
+		}
+	}
+	return nil
+}
+
+// userDN - returns the DN a user binds with.
+func (cfg *ldapConfig) userDN(username string) string {
+	return strings.Replace(cfg.UserDNFormat, "%s", ldap.EscapeDN(username), 1)
+}
+
+// groupSearchFilter - returns the filter of the groups of a user, `%s`
+// is replaced by the username and `%d` by the DN of the user.
+func (cfg *ldapConfig) groupSearchFilter(username, userDN string) string {
+	filter := strings.Replace(cfg.GroupSearchFilter, "%s", ldap.EscapeFilter(username), -1)
+	return strings.Replace(filter, "%d", ldap.EscapeFilter(userDN), -1)
+}
+
+// dial - connects to the LDAP server, upgrading the connection with
+// StartTLS if configured.
+func (cfg *ldapConfig) dial() (*ldap.Conn, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.TLSSkipVerify}
+	addr := strings.TrimSuffix(cfg.ServerURL[strings.Index(cfg.ServerURL, "://")+3:], "/")
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	tlsConfig.ServerName = addr
+
+	conn, err := ldap.Dial(cfg.ServerURL, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.StartTLS {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// authenticate - binds as the user and returns the user DN along with
+// the names of the groups the user belongs to. Returns
+// errLDAPAuthentication if the credentials are rejected.
+func (cfg *ldapConfig) authenticate(username, password string) (string, []string, error) {
+	if username == "" || password == "" {
+		return "", nil, errLDAPAuthentication
+	}
+
+	conn, err := cfg.dial()
+	if err != nil {
+		return "", nil, err
+	}
+	defer conn.Close()
+
+	userDN := cfg.userDN(username)
+	if err = conn.Bind(userDN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.ResultInvalidCredentials) {
+			return "", nil, errLDAPAuthentication
+		}
+		return "", nil, err
+	}
+
+	if cfg.GroupSearchFilter == "" {
+		return userDN, nil, nil
+	}
+	entries, err := conn.Search(cfg.GroupSearchBaseDN, ldap.ScopeWholeSubtree,
+		cfg.groupSearchFilter(username, userDN), []string{cfg.GroupNameAttribute})
+	if err != nil {
+		return "", nil, err
+	}
+	var groups []string
+	for _, entry := range entries {
+		groups = append(groups, entry.GetAttributeValues(cfg.GroupNameAttribute)...)
+	}
+	return userDN, groups, nil
+}
+
+// initLDAP - initializes the global LDAP configuration.
+func initLDAP() {
+	cfg, err := newLDAPConfig()
+	fatalIf(err, "Unable to initialize LDAP authentication.")
+	globalLDAPConfig = cfg
+}
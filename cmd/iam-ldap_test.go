@@ -0,0 +1,212 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/teamwork/minio/pkg/ldap/ldaptest"
+)
+
+// Starts an LDAP server with two users, alice member of the readers
+// and writers groups and bob member of no group, and returns it along
+// with a configuration authenticating against it.
+func newTestLDAPServer() (*ldaptest.Server, *ldapConfig) {
+	server := ldaptest.NewServer([]ldaptest.Entry{
+		{
+			DN:         "uid=alice,ou=people,dc=example,dc=com",
+			Password:   "alice123",
+			Attributes: map[string][]string{"uid": {"alice"}},
+		},
+		{
+			DN:         "uid=bob,ou=people,dc=example,dc=com",
+			Password:   "bob123",
+			Attributes: map[string][]string{"uid": {"bob"}},
+		},
+		{
+			DN: "cn=readers,ou=groups,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"cn":     {"readers"},
+				"member": {"uid=alice,ou=people,dc=example,dc=com"},
+			},
+		},
+		{
+			DN: "cn=writers,ou=groups,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"cn":     {"writers"},
+				"member": {"uid=alice,ou=people,dc=example,dc=com"},
+			},
+		},
+	})
+	return server, &ldapConfig{
+		ServerURL:          server.URL,
+		UserDNFormat:       "uid=%s,ou=people,dc=example,dc=com",
+		GroupSearchBaseDN:  "ou=groups,dc=example,dc=com",
+		GroupSearchFilter:  "(&(cn=*)(member=%d))",
+		GroupNameAttribute: "cn",
+	}
+}
+
+func TestNewLDAPConfig(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+
+	envs := []string{
+		envLDAPServerURL, envLDAPUserDNFormat, envLDAPGroupSearchBaseDN,
+		envLDAPGroupSearchFilter, envLDAPGroupNameAttribute, envLDAPStartTLS,
+	}
+	defer func() {
+		for _, env := range envs {
+			os.Unsetenv(env)
+		}
+	}()
+
+	testCases := []struct {
+		env         map[string]string
+		expectedCfg *ldapConfig
+		shouldPass  bool
+	}{
+		// 1. LDAP is disabled without a server URL.
+		{map[string]string{}, nil, true},
+		// 2. Valid configuration without group search.
+		{
+			map[string]string{envLDAPServerURL: "ldap://localhost", envLDAPUserDNFormat: "uid=%s,dc=example,dc=com", envLDAPStartTLS: "on"},
+			&ldapConfig{ServerURL: "ldap://localhost", UserDNFormat: "uid=%s,dc=example,dc=com", GroupNameAttribute: "cn", StartTLS: true},
+			true,
+		},
+		// 3. Valid configuration with group search.
+		{
+			map[string]string{envLDAPServerURL: "ldaps://localhost", envLDAPUserDNFormat: "uid=%s,dc=example,dc=com",
+				envLDAPGroupSearchBaseDN: "dc=example,dc=com", envLDAPGroupSearchFilter: "(memberUid=%s)", envLDAPGroupNameAttribute: "ou"},
+			&ldapConfig{ServerURL: "ldaps://localhost", UserDNFormat: "uid=%s,dc=example,dc=com",
+				GroupSearchBaseDN: "dc=example,dc=com", GroupSearchFilter: "(memberUid=%s)", GroupNameAttribute: "ou"},
+			true,
+		},
+		// 4. Unsupported server URL.
+		{map[string]string{envLDAPServerURL: "http://localhost", envLDAPUserDNFormat: "uid=%s"}, nil, false},
+		// 5. StartTLS over an ldaps URL.
+		{map[string]string{envLDAPServerURL: "ldaps://localhost", envLDAPUserDNFormat: "uid=%s", envLDAPStartTLS: "on"}, nil, false},
+		// 6. User DN format without username.
+		{map[string]string{envLDAPServerURL: "ldap://localhost", envLDAPUserDNFormat: "uid=alice"}, nil, false},
+		// 7. Group search filter without base DN.
+		{map[string]string{envLDAPServerURL: "ldap://localhost", envLDAPUserDNFormat: "uid=%s", envLDAPGroupSearchFilter: "(memberUid=%s)"}, nil, false},
+		// 8. Malformed group search filter.
+		{map[string]string{envLDAPServerURL: "ldap://localhost", envLDAPUserDNFormat: "uid=%s",
+			envLDAPGroupSearchBaseDN: "dc=example,dc=com", envLDAPGroupSearchFilter: "memberUid=%s"}, nil, false},
+	}
+	for i, testCase := range testCases {
+		for _, env := range envs {
+			os.Unsetenv(env)
+		}
+		for env, value := range testCase.env {
+			os.Setenv(env, value)
+		}
+		cfg, err := newLDAPConfig()
+		if err != nil && testCase.shouldPass {
+			t.Errorf("Test %d: Expected to pass, but failed with: %v", i+1, err)
+		}
+		if err == nil && !testCase.shouldPass {
+			t.Errorf("Test %d: Expected to fail, but passed", i+1)
+		}
+		if err == nil && !reflect.DeepEqual(cfg, testCase.expectedCfg) {
+			t.Errorf("Test %d: Expected %+v, got %+v", i+1, testCase.expectedCfg, cfg)
+		}
+	}
+}
+
+func TestNewLDAPConfigFromFile(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+	defer os.Unsetenv(envLDAPServerURL)
+
+	// The config file is used without MINIO_LDAP_SERVER_URL.
+	serverConfig.SetLDAP(ldapConfig{ServerURL: "ldap://localhost", UserDNFormat: "uid=%s,dc=example,dc=com"})
+	expectedCfg := &ldapConfig{ServerURL: "ldap://localhost", UserDNFormat: "uid=%s,dc=example,dc=com", GroupNameAttribute: "cn"}
+	cfg, err := newLDAPConfig()
+	if err != nil {
+		t.Fatalf("Expected to pass, but failed with: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, expectedCfg) {
+		t.Errorf("Expected %+v, got %+v", expectedCfg, cfg)
+	}
+
+	// The environment overrides the config file.
+	os.Setenv(envLDAPServerURL, "ldap://localhost")
+	if _, err = newLDAPConfig(); err == nil {
+		t.Errorf("Expected the environment without user DN format to fail")
+	}
+	os.Unsetenv(envLDAPServerURL)
+
+	// An invalid config file is rejected.
+	serverConfig.SetLDAP(ldapConfig{ServerURL: "http://localhost", UserDNFormat: "uid=%s"})
+	if _, err = newLDAPConfig(); err == nil {
+		t.Errorf("Expected the invalid config file to fail")
+	}
+}
+
+func TestLDAPAuthenticate(t *testing.T) {
+	server, cfg := newTestLDAPServer()
+	defer server.Close()
+
+	testCases := []struct {
+		username       string
+		password       string
+		expectedDN     string
+		expectedGroups []string
+		expectedErr    error
+	}{
+		// 1. Member of two groups.
+		{"alice", "alice123", "uid=alice,ou=people,dc=example,dc=com", []string{"readers", "writers"}, nil},
+		// 2. Member of no group.
+		{"bob", "bob123", "uid=bob,ou=people,dc=example,dc=com", nil, nil},
+		// 3. Wrong password.
+		{"alice", "bob123", "", nil, errLDAPAuthentication},
+		// 4. Unknown user.
+		{"carol", "carol123", "", nil, errLDAPAuthentication},
+		// 5. Empty password, an unauthenticated bind.
+		{"alice", "", "", nil, errLDAPAuthentication},
+		// 6. Username injecting a DN.
+		{"alice,ou=people,dc=example,dc=com", "alice123", "", nil, errLDAPAuthentication},
+	}
+	for i, testCase := range testCases {
+		userDN, groups, err := cfg.authenticate(testCase.username, testCase.password)
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+			continue
+		}
+		if userDN != testCase.expectedDN {
+			t.Errorf("Test %d: Expected DN %q, got %q", i+1, testCase.expectedDN, userDN)
+		}
+		if !reflect.DeepEqual(groups, testCase.expectedGroups) {
+			t.Errorf("Test %d: Expected groups %v, got %v", i+1, testCase.expectedGroups, groups)
+		}
+	}
+
+	// Unreachable servers are reported.
+	server.Close()
+	if _, _, err := cfg.authenticate("alice", "alice123"); err == nil || err == errLDAPAuthentication {
+		t.Errorf("Expected a connection error, got %v", err)
+	}
+}
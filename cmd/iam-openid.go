@@ -0,0 +1,403 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// Environment variables configuring OpenID Connect authentication.
+	envOpenIDIssuer    = "MINIO_IDENTITY_OPENID_ISSUER"
+	envOpenIDJWKSURL   = "MINIO_IDENTITY_OPENID_JWKS_URL"
+	envOpenIDJWKSFile  = "MINIO_IDENTITY_OPENID_JWKS_FILE"
+	envOpenIDClientID  = "MINIO_IDENTITY_OPENID_CLIENT_ID"
+	envOpenIDClaimName = "MINIO_IDENTITY_OPENID_CLAIM_NAME"
+
+	// Claim naming the policies of the user by default.
+	defaultOpenIDClaimName = "policy"
+
+	// Minimum interval between two fetches of the key set, which is
+	// fetched again when a token is signed by an unknown key.
+	openIDJWKSRefreshInterval = 1 * time.Minute
+)
+
+var (
+	errOpenIDNotConfigured = errors.New("OpenID Connect authentication is not configured")
+	errOpenIDInvalidToken  = errors.New("The web identity token is not valid")
+	errOpenIDExpiredToken  = errors.New("The web identity token has expired")
+)
+
+// openIDConfig - OpenID Connect provider users are authenticated by.
+// ID tokens issued by Issuer are verified with the keys of the key set
+// at JWKSURL or in JWKSFile, ClaimName names the policies of the user.
+type openIDConfig struct {
+	Issuer    string `json:"issuer"`
+	JWKSURL   string `json:"jwksURL"`
+	JWKSFile  string `json:"jwksFile"`
+	ClientID  string `json:"clientID"`
+	ClaimName string `json:"claimName"`
+
+	client *http.Client
+
+	// Key set, indexed by key ID.
+	mutex       *sync.Mutex
+	keys        map[string]crypto.PublicKey
+	lastRefresh time.Time
+}
+
+// Global OpenID Connect configuration, nil if disabled.
+var globalOpenIDConfig *openIDConfig
+
+// newOpenIDConfig - returns the OpenID Connect configuration, nil if
+// no key set is configured. The environment takes precedence over the
+// `openid` section of the config file when a key set is set in it.
+func newOpenIDConfig() (*openIDConfig, error) {
+	cfg := serverConfig.GetOpenID()
+	if os.Getenv(envOpenIDJWKSURL) != "" || os.Getenv(envOpenIDJWKSFile) != "" {
+		cfg = openIDConfig{
+			Issuer:    os.Getenv(envOpenIDIssuer),
+			JWKSURL:   os.Getenv(envOpenIDJWKSURL),
+			JWKSFile:  os.Getenv(envOpenIDJWKSFile),
+			ClientID:  os.Getenv(envOpenIDClientID),
+			ClaimName: os.Getenv(envOpenIDClaimName),
+		}
+	}
+	cfg.client = &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: globalRootCAs}},
+	}
+	cfg.mutex = &sync.Mutex{}
+	if cfg.JWKSURL == "" && cfg.JWKSFile == "" {
+		return nil, nil
+	}
+	if cfg.ClaimName == "" {
+		cfg.ClaimName = defaultOpenIDClaimName
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	// A static key set must be valid, the provider may be temporarily
+	// unreachable and its key set is fetched again on first use.
+	if err := cfg.refreshKeys(); err != nil {
+		if cfg.JWKSFile != "" {
+			return nil, err
+		}
+		errorIf(err, "Unable to fetch the OpenID Connect key set from %s.", cfg.JWKSURL)
+	}
+	return &cfg, nil
+}
+
+// validate - checks the configuration is complete.
+func (cfg *openIDConfig) validate() error {
+	if cfg.JWKSURL != "" && cfg.JWKSFile != "" {
+		return errors.New("OpenID Connect key set URL and file cannot be set together")
+	}
+	if cfg.JWKSURL != "" {
+		u, err := url.Parse(cfg.JWKSURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.New("OpenID Connect key set URL must be an http:// or https:// URL")
+		}
+	}
+	if cfg.Issuer == "" {
+		return errors.New("OpenID Connect issuer must be set")
+	}
+	return nil
+}
+
+// refreshKeys - reads the key set again.
+func (cfg *openIDConfig) refreshKeys() error {
+	var keys map[string]crypto.PublicKey
+	var err error
+	if cfg.JWKSFile != "" {
+		keys, err = readJWKSFile(cfg.JWKSFile)
+	} else {
+		keys, err = cfg.fetchJWKS()
+	}
+
+	cfg.mutex.Lock()
+	defer cfg.mutex.Unlock()
+	cfg.lastRefresh = time.Now().UTC()
+	if err != nil {
+		return err
+	}
+	cfg.keys = keys
+	return nil
+}
+
+// readJWKSFile - reads a key set from a file.
+func readJWKSFile(filename string) (map[string]crypto.PublicKey, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseJWKS(f)
+}
+
+// fetchJWKS - fetches the key set from the provider.
+func (cfg *openIDConfig) fetchJWKS() (map[string]crypto.PublicKey, error) {
+	resp, err := cfg.client.Get(cfg.JWKSURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected response %s fetching %s", resp.Status, cfg.JWKSURL)
+	}
+	return parseJWKS(resp.Body)
+}
+
+// jsonWebKey - the members of a JSON Web Key (RFC 7517) describing RSA
+// and elliptic curve public keys.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS - parses a JSON Web Key Set, keys which are not signature
+// keys or of an unsupported type are skipped.
+func parseJWKS(r io.Reader) (map[string]crypto.PublicKey, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r, 1<<20)).Decode(&jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, err
+		}
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("The key set has no signature key")
+	}
+	return keys, nil
+}
+
+// publicKey - returns the public key, nil if its type is not supported.
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if e.BitLen() > 31 {
+			return nil, fmt.Errorf("Invalid RSA exponent of key %s", jwk.Kid)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, nil
+		}
+		x, err := decodeJWKInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("Invalid EC point of key %s", jwk.Kid)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, nil
+}
+
+// decodeJWKInt - decodes a base64url encoded big-endian integer.
+func decodeJWKInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("Missing key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// getKey - returns the key with the given ID, the key set is fetched
+// again if the key is unknown. Tokens without key ID are accepted if
+// the key set has a single key.
+func (cfg *openIDConfig) getKey(kid string) (crypto.PublicKey, bool) {
+	lookup := func() (crypto.PublicKey, bool, bool) {
+		cfg.mutex.Lock()
+		defer cfg.mutex.Unlock()
+		if kid == "" && len(cfg.keys) == 1 {
+			for _, key := range cfg.keys {
+				return key, true, false
+			}
+		}
+		key, ok := cfg.keys[kid]
+		canRefresh := cfg.JWKSURL != "" && time.Since(cfg.lastRefresh) >= openIDJWKSRefreshInterval
+		return key, ok, canRefresh
+	}
+
+	key, ok, canRefresh := lookup()
+	if ok || !canRefresh {
+		return key, ok
+	}
+	if err := cfg.refreshKeys(); err != nil {
+		errorIf(err, "Unable to fetch the OpenID Connect key set from %s.", cfg.JWKSURL)
+		return nil, false
+	}
+	key, ok, _ = lookup()
+	return key, ok
+}
+
+// keyFunc - returns the key verifying a token, only asymmetric
+// signatures are accepted.
+func (cfg *openIDConfig) keyFunc(token *jwtgo.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwtgo.SigningMethodRSA, *jwtgo.SigningMethodRSAPSS, *jwtgo.SigningMethodECDSA:
+	default:
+		return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := cfg.getKey(kid)
+	if !ok {
+		return nil, fmt.Errorf("Unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// validateToken - verifies an ID token and returns its subject along
+// with the names of the policies of the user. Returns
+// errOpenIDExpiredToken if the token expired and errOpenIDInvalidToken
+// for all other errors.
+func (cfg *openIDConfig) validateToken(tokenString string) (string, []string, error) {
+	token, err := jwtgo.Parse(tokenString, cfg.keyFunc)
+	if err != nil {
+		if vErr, ok := err.(*jwtgo.ValidationError); ok && vErr.Errors == jwtgo.ValidationErrorExpired {
+			return "", nil, errOpenIDExpiredToken
+		}
+		return "", nil, errOpenIDInvalidToken
+	}
+	claims, ok := token.Claims.(jwtgo.MapClaims)
+	if !ok || !token.Valid {
+		return "", nil, errOpenIDInvalidToken
+	}
+
+	// ID tokens always expire (OpenID Connect Core 2).
+	if !claims.VerifyExpiresAt(time.Now().UTC().Unix(), true) {
+		return "", nil, errOpenIDInvalidToken
+	}
+	if !claims.VerifyIssuer(cfg.Issuer, true) {
+		return "", nil, errOpenIDInvalidToken
+	}
+	if cfg.ClientID != "" && !isOpenIDAudience(claims["aud"], cfg.ClientID) {
+		return "", nil, errOpenIDInvalidToken
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return "", nil, errOpenIDInvalidToken
+	}
+	return subject, openIDClaimValues(claims[cfg.ClaimName]), nil
+}
+
+// isOpenIDAudience - checks if the aud claim, a string or an array of
+// strings, contains the client ID.
+func isOpenIDAudience(aud interface{}, clientID string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, v := range aud {
+			if v == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// openIDClaimValues - returns the values of a claim, either an array
+// of strings or a comma separated string.
+func openIDClaimValues(claim interface{}) []string {
+	var values []string
+	switch claim := claim.(type) {
+	case string:
+		for _, v := range strings.Split(claim, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	case []interface{}:
+		for _, v := range claim {
+			if s, ok := v.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
+
+// initOpenID - initializes the global OpenID Connect configuration.
+func initOpenID() {
+	cfg, err := newOpenIDConfig()
+	fatalIf(err, "Unable to initialize OpenID Connect authentication.")
+	globalOpenIDConfig = cfg
+}
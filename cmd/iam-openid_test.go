@@ -0,0 +1,383 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+// testOpenIDProvider - an OpenID Connect provider serving the key set
+// of its current signing key.
+type testOpenIDProvider struct {
+	*httptest.Server
+	issuer  string
+	mutex   sync.Mutex
+	kid     string
+	key     *rsa.PrivateKey
+	fetches int32
+}
+
+// Starts an OpenID Connect provider and returns it along with a
+// configuration verifying its ID tokens issued to the "minio" client.
+func newTestOpenIDProvider(t TestErrHandler) (*testOpenIDProvider, *openIDConfig) {
+	provider := &testOpenIDProvider{issuer: "https://openid.example.com"}
+	provider.rotateKey(t, "key1")
+	provider.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&provider.fetches, 1)
+		provider.mutex.Lock()
+		defer provider.mutex.Unlock()
+		w.Write(testJWKS(provider.kid, &provider.key.PublicKey))
+	}))
+	return provider, &openIDConfig{
+		Issuer:    provider.issuer,
+		JWKSURL:   provider.URL,
+		ClientID:  "minio",
+		ClaimName: "policy",
+		client:    &http.Client{},
+		mutex:     &sync.Mutex{},
+	}
+}
+
+// rotateKey - replaces the signing key.
+func (p *testOpenIDProvider) rotateKey(t TestErrHandler, kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unable to generate RSA key: %v", err)
+	}
+	p.mutex.Lock()
+	p.kid, p.key = kid, key
+	p.mutex.Unlock()
+}
+
+// signToken - returns an ID token with the claims, the issuer, the
+// audience and the expiration are set unless overridden.
+func (p *testOpenIDProvider) signToken(t TestErrHandler, claims jwtgo.MapClaims) string {
+	idClaims := jwtgo.MapClaims{
+		"iss": p.issuer,
+		"aud": "minio",
+		"exp": time.Now().UTC().Add(time.Hour).Unix(),
+	}
+	for name, value := range claims {
+		if value == nil {
+			delete(idClaims, name)
+			continue
+		}
+		idClaims[name] = value
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	token := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, idClaims)
+	token.Header["kid"] = p.kid
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		t.Fatalf("Unable to sign ID token: %v", err)
+	}
+	return signed
+}
+
+// Returns a key set holding a single RSA key.
+func testJWKS(kid string, key *rsa.PublicKey) []byte {
+	jwks, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	return jwks
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes())
+	x := base64.RawURLEncoding.EncodeToString(ecKey.X.Bytes())
+	y := base64.RawURLEncoding.EncodeToString(ecKey.Y.Bytes())
+
+	testCases := []struct {
+		jwks         string
+		expectedKids []string
+		shouldPass   bool
+	}{
+		// 1. RSA and EC keys.
+		{`{"keys": [{"kty": "RSA", "kid": "rsa", "n": "` + n + `", "e": "AQAB"},
+			{"kty": "EC", "kid": "ec", "use": "sig", "crv": "P-256", "x": "` + x + `", "y": "` + y + `"}]}`,
+			[]string{"ec", "rsa"}, true},
+		// 2. Encryption keys and unsupported key types are skipped.
+		{`{"keys": [{"kty": "RSA", "kid": "enc", "use": "enc", "n": "` + n + `", "e": "AQAB"},
+			{"kty": "oct", "kid": "oct", "k": "c2VjcmV0"},
+			{"kty": "RSA", "kid": "rsa", "n": "` + n + `", "e": "AQAB"}]}`,
+			[]string{"rsa"}, true},
+		// 3. No signature key.
+		{`{"keys": [{"kty": "oct", "kid": "oct", "k": "c2VjcmV0"}]}`, nil, false},
+		// 4. Malformed modulus.
+		{`{"keys": [{"kty": "RSA", "kid": "rsa", "n": "not base64!", "e": "AQAB"}]}`, nil, false},
+		// 5. EC point not on the curve.
+		{`{"keys": [{"kty": "EC", "kid": "ec", "crv": "P-256", "x": "` + x + `", "y": "` + x + `"}]}`, nil, false},
+		// 6. Malformed JSON.
+		{`{"keys": `, nil, false},
+	}
+	for i, testCase := range testCases {
+		keys, err := parseJWKS(strings.NewReader(testCase.jwks))
+		if err != nil && testCase.shouldPass {
+			t.Errorf("Test %d: Expected to pass, but failed with: %v", i+1, err)
+		}
+		if err == nil && !testCase.shouldPass {
+			t.Errorf("Test %d: Expected to fail, but passed", i+1)
+		}
+		if err != nil {
+			continue
+		}
+		var kids []string
+		for kid := range keys {
+			kids = append(kids, kid)
+		}
+		if len(kids) == 2 && kids[0] > kids[1] {
+			kids[0], kids[1] = kids[1], kids[0]
+		}
+		if !reflect.DeepEqual(kids, testCase.expectedKids) {
+			t.Errorf("Test %d: Expected keys %v, got %v", i+1, testCase.expectedKids, kids)
+		}
+	}
+}
+
+func TestNewOpenIDConfig(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+
+	envs := []string{envOpenIDIssuer, envOpenIDJWKSURL, envOpenIDJWKSFile, envOpenIDClientID, envOpenIDClaimName}
+	defer func() {
+		for _, env := range envs {
+			os.Unsetenv(env)
+		}
+	}()
+
+	dir, err := ioutil.TempDir("", "minio-openid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksFile := filepath.Join(dir, "jwks.json")
+	if err = ioutil.WriteFile(jwksFile, testJWKS("key1", &key.PublicKey), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		env        map[string]string
+		enabled    bool
+		shouldPass bool
+	}{
+		// 1. OpenID Connect is disabled without a key set.
+		{map[string]string{envOpenIDIssuer: "https://openid.example.com"}, false, true},
+		// 2. Static key set.
+		{map[string]string{envOpenIDIssuer: "https://openid.example.com", envOpenIDJWKSFile: jwksFile}, true, true},
+		// 3. Unreachable key set URL, fetched again on first use.
+		{map[string]string{envOpenIDIssuer: "https://openid.example.com", envOpenIDJWKSURL: "http://127.0.0.1:1/jwks"}, true, true},
+		// 4. Missing issuer.
+		{map[string]string{envOpenIDJWKSFile: jwksFile}, false, false},
+		// 5. Both a key set URL and file.
+		{map[string]string{envOpenIDIssuer: "https://openid.example.com", envOpenIDJWKSFile: jwksFile, envOpenIDJWKSURL: "https://openid.example.com/jwks"}, false, false},
+		// 6. Unsupported key set URL.
+		{map[string]string{envOpenIDIssuer: "https://openid.example.com", envOpenIDJWKSURL: "ftp://openid.example.com/jwks"}, false, false},
+		// 7. Missing static key set.
+		{map[string]string{envOpenIDIssuer: "https://openid.example.com", envOpenIDJWKSFile: filepath.Join(dir, "missing.json")}, false, false},
+	}
+	for i, testCase := range testCases {
+		for _, env := range envs {
+			os.Unsetenv(env)
+		}
+		for env, value := range testCase.env {
+			os.Setenv(env, value)
+		}
+		cfg, err := newOpenIDConfig()
+		if err != nil && testCase.shouldPass {
+			t.Errorf("Test %d: Expected to pass, but failed with: %v", i+1, err)
+		}
+		if err == nil && !testCase.shouldPass {
+			t.Errorf("Test %d: Expected to fail, but passed", i+1)
+		}
+		if (cfg != nil) != testCase.enabled {
+			t.Errorf("Test %d: Expected enabled to be %v, got %+v", i+1, testCase.enabled, cfg)
+		}
+		if cfg != nil && cfg.ClaimName != defaultOpenIDClaimName {
+			t.Errorf("Test %d: Expected claim name %s, got %s", i+1, defaultOpenIDClaimName, cfg.ClaimName)
+		}
+	}
+}
+
+func TestNewOpenIDConfigFromFile(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+	defer os.Unsetenv(envOpenIDJWKSURL)
+
+	dir, err := ioutil.TempDir("", "minio-openid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksFile := filepath.Join(dir, "jwks.json")
+	if err = ioutil.WriteFile(jwksFile, testJWKS("key1", &key.PublicKey), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The config file is used without a key set in the environment.
+	serverConfig.SetOpenID(openIDConfig{Issuer: "https://openid.example.com", JWKSFile: jwksFile, ClientID: "minio"})
+	cfg, err := newOpenIDConfig()
+	if err != nil {
+		t.Fatalf("Expected to pass, but failed with: %v", err)
+	}
+	if cfg == nil || cfg.Issuer != "https://openid.example.com" || cfg.ClientID != "minio" || cfg.ClaimName != defaultOpenIDClaimName {
+		t.Errorf("Expected the configuration of the config file, got %+v", cfg)
+	}
+
+	// The environment overrides the config file.
+	os.Setenv(envOpenIDJWKSURL, "https://openid.example.com/jwks")
+	if _, err = newOpenIDConfig(); err == nil {
+		t.Errorf("Expected the environment without issuer to fail")
+	}
+	os.Unsetenv(envOpenIDJWKSURL)
+
+	// An invalid config file is rejected.
+	serverConfig.SetOpenID(openIDConfig{JWKSFile: jwksFile})
+	if _, err = newOpenIDConfig(); err == nil {
+		t.Errorf("Expected the invalid config file to fail")
+	}
+}
+
+func TestOpenIDValidateToken(t *testing.T) {
+	provider, cfg := newTestOpenIDProvider(t)
+	defer provider.Close()
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hmacToken, err := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, jwtgo.MapClaims{
+		"iss": provider.issuer, "aud": "minio", "sub": "alice", "exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherToken, err := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, jwtgo.MapClaims{
+		"iss": provider.issuer, "aud": "minio", "sub": "alice", "exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		token            string
+		expectedSubject  string
+		expectedPolicies []string
+		expectedErr      error
+	}{
+		// 1. Comma separated policies.
+		{provider.signToken(t, jwtgo.MapClaims{"sub": "alice", "policy": "readers, writers"}), "alice", []string{"readers", "writers"}, nil},
+		// 2. Array of policies and audiences.
+		{provider.signToken(t, jwtgo.MapClaims{"sub": "alice", "policy": []string{"readers"}, "aud": []string{"other", "minio"}}), "alice", []string{"readers"}, nil},
+		// 3. No policy.
+		{provider.signToken(t, jwtgo.MapClaims{"sub": "bob"}), "bob", nil, nil},
+		// 4. Expired.
+		{provider.signToken(t, jwtgo.MapClaims{"sub": "alice", "exp": time.Now().Add(-time.Minute).Unix()}), "", nil, errOpenIDExpiredToken},
+		// 5. No expiration.
+		{provider.signToken(t, jwtgo.MapClaims{"sub": "alice", "exp": nil}), "", nil, errOpenIDInvalidToken},
+		// 6. Other issuer.
+		{provider.signToken(t, jwtgo.MapClaims{"sub": "alice", "iss": "https://other.example.com"}), "", nil, errOpenIDInvalidToken},
+		// 7. Other audience.
+		{provider.signToken(t, jwtgo.MapClaims{"sub": "alice", "aud": "other"}), "", nil, errOpenIDInvalidToken},
+		// 8. No subject.
+		{provider.signToken(t, jwtgo.MapClaims{}), "", nil, errOpenIDInvalidToken},
+		// 9. Symmetric signature.
+		{hmacToken, "", nil, errOpenIDInvalidToken},
+		// 10. Signed by an unknown key.
+		{otherToken, "", nil, errOpenIDInvalidToken},
+		// 11. Malformed token.
+		{"not-a-token", "", nil, errOpenIDInvalidToken},
+	}
+	for i, testCase := range testCases {
+		subject, policies, err := cfg.validateToken(testCase.token)
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+			continue
+		}
+		if subject != testCase.expectedSubject {
+			t.Errorf("Test %d: Expected subject %q, got %q", i+1, testCase.expectedSubject, subject)
+		}
+		if !reflect.DeepEqual(policies, testCase.expectedPolicies) {
+			t.Errorf("Test %d: Expected policies %v, got %v", i+1, testCase.expectedPolicies, policies)
+		}
+	}
+
+	// The key set is fetched once, and again when the provider rotates
+	// its key but no more than once per refresh interval.
+	if fetches := atomic.LoadInt32(&provider.fetches); fetches != 1 {
+		t.Errorf("Expected the key set to be fetched once, got %d", fetches)
+	}
+	provider.rotateKey(t, "key2")
+	token := provider.signToken(t, jwtgo.MapClaims{"sub": "alice"})
+	if _, _, err = cfg.validateToken(token); err != errOpenIDInvalidToken {
+		t.Errorf("Expected the rotated key to be unknown until the next refresh, got %v", err)
+	}
+	cfg.lastRefresh = time.Now().UTC().Add(-openIDJWKSRefreshInterval)
+	if _, _, err = cfg.validateToken(token); err != nil {
+		t.Errorf("Expected the rotated key to be fetched, got %v", err)
+	}
+	if fetches := atomic.LoadInt32(&provider.fetches); fetches != 2 {
+		t.Errorf("Expected the key set to be fetched twice, got %d", fetches)
+	}
+}
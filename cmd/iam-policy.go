@@ -0,0 +1,162 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// parseIAMPolicy - parses and validates an IAM user policy. It uses
+// the bucket policy grammar, except that the principal is optional
+// since the policy applies to the users it is attached to.
+func parseIAMPolicy(policyReader io.Reader, policy *bucketPolicy) (err error) {
+	decoder := json.NewDecoder(policyReader)
+	if err = decoder.Decode(&policy); err != nil {
+		return err
+	}
+
+	// Policy version cannot be empty.
+	if len(policy.Version) == 0 {
+		err = errors.New("Policy version cannot be empty")
+		return err
+	}
+
+	// Policy statements cannot be empty.
+	if len(policy.Statements) == 0 {
+		err = errors.New("Policy statement cannot be empty")
+		return err
+	}
+
+	// Loop through all policy statements and validate entries.
+	for _, statement := range policy.Statements {
+		if err := isValidEffect(statement.Effect); err != nil {
+			return err
+		}
+		if statement.Principal != nil {
+			if err := isValidPrincipals(statement.Principal); err != nil {
+				return err
+			}
+		}
+		if err := isValidActions(statement.Actions); err != nil {
+			return err
+		}
+		if err := isValidResources(statement.Resources); err != nil {
+			return err
+		}
+		if err := isValidConditions(statement.Conditions); err != nil {
+			return err
+		}
+	}
+
+	// Deny statements are enforced first once matched.
+	var denyStatements []policyStatement
+	var allowStatements []policyStatement
+	for _, statement := range policy.Statements {
+		if statement.Effect == "Deny" {
+			denyStatements = append(denyStatements, statement)
+			continue
+		}
+		allowStatements = append(allowStatements, statement)
+	}
+	policy.Statements = append(denyStatements, allowStatements...)
+
+	return nil
+}
+
+// enforceUserPolicy - checks if the owner of the access key a request
+// is signed with may perform the action. The server credentials are
+// allowed everything, requests without an action (e.g. admin requests)
+// are reserved for them. Other users are denied if the bucket policy or
+// one of their policies denies the action, otherwise they are allowed
+// if the bucket policy or one of their policies allows it. Temporary
+// credentials are allowed what their parent user (or their named
+// policies for LDAP and OpenID Connect users) is allowed, further
+// limited by their session policy.
+func enforceUserPolicy(accessKey, bucket, action string, reqURL *url.URL, r *http.Request) APIErrorCode {
+	if accessKey == serverConfig.GetCredential().AccessKey {
+		return ErrNone
+	}
+	if action == "" {
+		return ErrAccessDenied
+	}
+
+	bucketDecision := policyImplicitDeny
+	if bucket != "" && globalBucketPolicies != nil {
+		if policy := globalBucketPolicies.GetBucketPolicy(bucket); policy != nil {
+			bucketDecision = evalBucketPolicy(policy, bucket, action, reqURL, r)
+		}
+	}
+	if bucketDecision == policyExplicitDeny {
+		return ErrAccessDenied
+	}
+
+	switch evalUserPolicy(accessKey, bucket, action, reqURL, r) {
+	case policyAllow:
+		return ErrNone
+	case policyImplicitDeny:
+		if bucketDecision == policyAllow {
+			return ErrNone
+		}
+	}
+	return ErrAccessDenied
+}
+
+// evalUserPolicy - evaluates the policies of the owner of an access key,
+// other than the server credentials, for the action.
+func evalUserPolicy(accessKey, bucket, action string, reqURL *url.URL, r *http.Request) policyDecision {
+	tempUser, ok := globalIAMSys.GetTempUser(accessKey)
+	if !ok {
+		policy := globalIAMSys.GetUserPolicy(accessKey)
+		if policy == nil {
+			return policyImplicitDeny
+		}
+		return evalIAMPolicy(policy, bucket, action, reqURL, r)
+	}
+
+	var decision policyDecision
+	if tempUser.ParentUser == "" {
+		decision = policyImplicitDeny
+		if policy := globalIAMSys.GetPolicy(tempUser.Policies); policy != nil {
+			decision = evalIAMPolicy(policy, bucket, action, reqURL, r)
+		}
+	} else if tempUser.ParentUser == serverConfig.GetCredential().AccessKey {
+		decision = policyAllow
+	} else {
+		decision = evalUserPolicy(tempUser.ParentUser, bucket, action, reqURL, r)
+	}
+	if decision != policyAllow || tempUser.Policy == nil {
+		return decision
+	}
+	return evalIAMPolicy(tempUser.Policy, bucket, action, reqURL, r)
+}
+
+// evalIAMPolicy - evaluates an IAM policy for the action on the
+// resource of the request.
+func evalIAMPolicy(policy *bucketPolicy, bucket, action string, reqURL *url.URL, r *http.Request) policyDecision {
+	// Construct resource in 'arn:aws:s3:::examplebucket/object' format.
+	resource := bucketARNPrefix + strings.TrimSuffix(strings.TrimPrefix(reqURL.Path, "/"), "/")
+
+	// Get conditions for policy verification.
+	conditionKeyMap := getPolicyConditionValues(policy, bucket, reqURL, r)
+
+	return bucketPolicyEval(action, resource, conditionKeyMap, policy.Statements)
+}
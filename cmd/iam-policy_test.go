@@ -0,0 +1,173 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Tests validating IAM user policies.
+func TestParseIAMPolicy(t *testing.T) {
+	testCases := []struct {
+		policy      string
+		shouldParse bool
+	}{
+		// Bucket configuration actions without a principal.
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:ListAllMyBuckets", "s3:CreateBucket", "s3:PutBucketPolicy"], "Resource": ["arn:aws:s3:::*"]}]}`, true},
+		// Bucket policy grammar with a principal.
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Deny", "Principal": {"AWS": ["*"]}, "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/*"]}]}`, true},
+		// Conditions.
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:ListBucket"], "Resource": ["arn:aws:s3:::mybucket"], "Condition": {"StringEquals": {"s3:prefix": ["docs/"]}}}]}`, true},
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Deny", "Action": ["s3:*"], "Resource": ["arn:aws:s3:::mybucket/*"], "Condition": {"NotIpAddress": {"aws:SourceIp": ["10.0.0.0/8"]}, "Bool": {"aws:SecureTransport": ["false"]}}}]}`, true},
+		// Unsupported condition.
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/*"], "Condition": {"IpAddress": {"aws:SourceIp": ["somewhere"]}}}]}`, false},
+		// Missing version.
+		{`{"Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/*"]}]}`, false},
+		// No statements.
+		{`{"Version": "2012-10-17", "Statement": []}`, false},
+		// Unsupported action.
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["iam:CreateUser"], "Resource": ["arn:aws:s3:::*"]}]}`, false},
+		// Unsupported effect.
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Maybe", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/*"]}]}`, false},
+		// Unsupported resource.
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["mybucket/*"]}]}`, false},
+		// Unsupported principal.
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "someone", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/*"]}]}`, false},
+		// Malformed JSON.
+		{`{"Version": "2012-10-17", "Statement": [`, false},
+	}
+	for i, testCase := range testCases {
+		policy := &bucketPolicy{}
+		err := parseIAMPolicy(strings.NewReader(testCase.policy), policy)
+		if testCase.shouldParse && err != nil {
+			t.Errorf("Test %d: Expected policy to parse, got %v", i+1, err)
+		}
+		if !testCase.shouldParse && err == nil {
+			t.Errorf("Test %d: Expected policy not to parse", i+1)
+		}
+	}
+}
+
+// Tests evaluating the policies of the user signing a request.
+func TestEnforceUserPolicy(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+	defer resetGlobalIAMSys()
+
+	policy := mustParseIAMPolicy(t, `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": ["s3:GetObject", "s3:PutBucketPolicy"], "Resource": ["arn:aws:s3:::mybucket", "arn:aws:s3:::mybucket/*"]},
+		{"Effect": "Allow", "Action": ["s3:ListAllMyBuckets"], "Resource": ["arn:aws:s3:::*"]},
+		{"Effect": "Deny", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/secret/*"]}]}`)
+	globalIAMSys.Set(map[string]iamUser{
+		"newuser": {Credential: credential{AccessKey: "newuser", SecretKey: "newuser123"}, Status: iamUserEnabled, Policies: []string{"policy"}},
+	}, map[string]*bucketPolicy{"policy": policy})
+
+	serverCred := serverConfig.GetCredential()
+	testCases := []struct {
+		accessKey string
+		bucket    string
+		action    string
+		path      string
+		expected  APIErrorCode
+	}{
+		// The server credentials are allowed everything.
+		{serverCred.AccessKey, "mybucket", "s3:PutObject", "/mybucket/object", ErrNone},
+		{serverCred.AccessKey, "", "", "/", ErrNone},
+		// Users are allowed what their policies allow.
+		{"newuser", "mybucket", "s3:GetObject", "/mybucket/object", ErrNone},
+		{"newuser", "mybucket", "s3:PutBucketPolicy", "/mybucket", ErrNone},
+		{"newuser", "", "s3:ListAllMyBuckets", "/", ErrNone},
+		// Deny statements win.
+		{"newuser", "mybucket", "s3:GetObject", "/mybucket/secret/object", ErrAccessDenied},
+		// Everything else is denied.
+		{"newuser", "mybucket", "s3:PutObject", "/mybucket/object", ErrAccessDenied},
+		{"newuser", "otherbucket", "s3:GetObject", "/otherbucket/object", ErrAccessDenied},
+		{"newuser", "", "", "/", ErrAccessDenied},
+		{"unknown", "mybucket", "s3:GetObject", "/mybucket/object", ErrAccessDenied},
+	}
+	for i, testCase := range testCases {
+		reqURL := &url.URL{Path: testCase.path}
+		s3Error := enforceUserPolicy(testCase.accessKey, testCase.bucket, testCase.action, reqURL, nil)
+		if s3Error != testCase.expected {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, s3Error)
+		}
+	}
+}
+
+// Tests bucket policies are enforced on the requests of IAM users.
+func TestEnforceUserPolicyWithBucketPolicy(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+	defer resetGlobalIAMSys()
+
+	policy := mustParseIAMPolicy(t, `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/*"]}]}`)
+	globalIAMSys.Set(map[string]iamUser{
+		"newuser": {Credential: credential{AccessKey: "newuser", SecretKey: "newuser123"}, Status: iamUserEnabled, Policies: []string{"policy"}},
+	}, map[string]*bucketPolicy{"policy": policy})
+
+	policy = &bucketPolicy{}
+	err = parseBucketPolicy(strings.NewReader(`{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Deny", "Principal": "*", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/private/*"], "Condition": {"NotIpAddress": {"aws:SourceIp": ["10.0.0.0/8"]}}},
+		{"Effect": "Allow", "Principal": "*", "Action": ["s3:PutObject"], "Resource": ["arn:aws:s3:::mybucket/uploads/*"]}]}`), policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldBucketPolicies := globalBucketPolicies
+	defer func() { globalBucketPolicies = oldBucketPolicies }()
+	globalBucketPolicies = &bucketPolicies{
+		rwMutex:             &sync.RWMutex{},
+		bucketPolicyConfigs: map[string]*bucketPolicy{"mybucket": policy},
+	}
+
+	testCases := []struct {
+		accessKey  string
+		action     string
+		path       string
+		remoteAddr string
+		expected   APIErrorCode
+	}{
+		// Allowed by the user policy.
+		{"newuser", "s3:GetObject", "/mybucket/object", "192.168.1.1:9000", ErrNone},
+		// Denied by the bucket policy whatever the user policy allows.
+		{"newuser", "s3:GetObject", "/mybucket/private/object", "192.168.1.1:9000", ErrAccessDenied},
+		{"newuser", "s3:GetObject", "/mybucket/private/object", "10.1.2.3:9000", ErrNone},
+		// Allowed by the bucket policy.
+		{"newuser", "s3:PutObject", "/mybucket/uploads/object", "192.168.1.1:9000", ErrNone},
+		{"newuser", "s3:PutObject", "/mybucket/object", "192.168.1.1:9000", ErrAccessDenied},
+		// The server credentials are not subject to bucket policies.
+		{serverConfig.GetCredential().AccessKey, "s3:GetObject", "/mybucket/private/object", "192.168.1.1:9000", ErrNone},
+	}
+	for i, testCase := range testCases {
+		reqURL := &url.URL{Path: testCase.path}
+		r := &http.Request{URL: reqURL, Header: http.Header{}, RemoteAddr: testCase.remoteAddr}
+		s3Error := enforceUserPolicy(testCase.accessKey, "mybucket", testCase.action, reqURL, r)
+		if s3Error != testCase.expected {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, s3Error)
+		}
+	}
+}
@@ -0,0 +1,462 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"sync"
+)
+
+const (
+	// IAM configuration prefix inside minioMetaBucket.
+	iamConfigPrefix = "config/iam"
+
+	// IAM users and policies file names.
+	iamUsersFile    = "users.json"
+	iamPoliciesFile = "policies.json"
+
+	// Current version of the IAM files.
+	iamConfigVersion = "1"
+
+	// Name peers are notified with to reload IAM users and policies.
+	iamConfigName = "iam"
+
+	// IAM user states.
+	iamUserEnabled  = "enabled"
+	iamUserDisabled = "disabled"
+)
+
+var (
+	errNoSuchUser      = errors.New("The specified user does not exist")
+	errNoSuchIAMPolicy = errors.New("The specified policy does not exist")
+)
+
+// iamUser - user with its own credentials and the names of the
+// policies attached to it.
+type iamUser struct {
+	Credential credential `json:"credential"`
+	Status     string     `json:"status"`
+	Policies   []string   `json:"policies,omitempty"`
+}
+
+// iamUsersConfig - format of the IAM users file.
+type iamUsersConfig struct {
+	Version string             `json:"version"`
+	Users   map[string]iamUser `json:"users"`
+}
+
+// iamPoliciesConfig - format of the IAM policies file.
+type iamPoliciesConfig struct {
+	Version  string                   `json:"version"`
+	Policies map[string]*bucketPolicy `json:"policies"`
+}
+
+// Global IAM users and policies.
+var globalIAMSys = newIAMSys()
+
+// iamSys - in-memory collection of IAM users, policies and
+// temporary credentials.
+type iamSys struct {
+	rwMutex   *sync.RWMutex
+	users     map[string]iamUser
+	policies  map[string]*bucketPolicy
+	tempUsers map[string]iamTempUser
+}
+
+func newIAMSys() *iamSys {
+	return &iamSys{
+		rwMutex:   &sync.RWMutex{},
+		users:     make(map[string]iamUser),
+		policies:  make(map[string]*bucketPolicy),
+		tempUsers: make(map[string]iamTempUser),
+	}
+}
+
+// Set - replaces all the users and policies.
+func (sys *iamSys) Set(users map[string]iamUser, policies map[string]*bucketPolicy) {
+	sys.rwMutex.Lock()
+	defer sys.rwMutex.Unlock()
+
+	sys.users = users
+	sys.policies = policies
+}
+
+// GetUser - returns the user with the given access key.
+func (sys *iamSys) GetUser(accessKey string) (iamUser, bool) {
+	sys.rwMutex.RLock()
+	defer sys.rwMutex.RUnlock()
+
+	user, ok := sys.users[accessKey]
+	return user, ok
+}
+
+// ListUsers - returns all the users.
+func (sys *iamSys) ListUsers() map[string]iamUser {
+	sys.rwMutex.RLock()
+	defer sys.rwMutex.RUnlock()
+
+	users := make(map[string]iamUser, len(sys.users))
+	for accessKey, user := range sys.users {
+		users[accessKey] = user
+	}
+	return users
+}
+
+// ListPolicies - returns all the named policies.
+func (sys *iamSys) ListPolicies() map[string]*bucketPolicy {
+	sys.rwMutex.RLock()
+	defer sys.rwMutex.RUnlock()
+
+	policies := make(map[string]*bucketPolicy, len(sys.policies))
+	for name, policy := range sys.policies {
+		policies[name] = policy
+	}
+	return policies
+}
+
+// GetUserPolicy - returns a single policy combining the statements
+// of all the policies attached to an enabled user, nil if there are
+// none. Deny statements of every policy are placed first.
+func (sys *iamSys) GetUserPolicy(accessKey string) *bucketPolicy {
+	sys.rwMutex.RLock()
+	defer sys.rwMutex.RUnlock()
+
+	user, ok := sys.users[accessKey]
+	if !ok || user.Status != iamUserEnabled {
+		return nil
+	}
+	return sys.combinePolicies(user.Policies)
+}
+
+// GetPolicy - returns a single policy combining the statements of the
+// named policies, nil if there are none.
+func (sys *iamSys) GetPolicy(names []string) *bucketPolicy {
+	sys.rwMutex.RLock()
+	defer sys.rwMutex.RUnlock()
+
+	return sys.combinePolicies(names)
+}
+
+// combinePolicies - combines the statements of the named policies,
+// deny statements first. The caller is expected to hold a read lock.
+func (sys *iamSys) combinePolicies(names []string) *bucketPolicy {
+	var denyStatements []policyStatement
+	var allowStatements []policyStatement
+	for _, name := range names {
+		// Policies removed after being attached are ignored.
+		policy, ok := sys.policies[name]
+		if !ok {
+			continue
+		}
+		for _, statement := range policy.Statements {
+			if statement.Effect == "Deny" {
+				denyStatements = append(denyStatements, statement)
+				continue
+			}
+			allowStatements = append(allowStatements, statement)
+		}
+	}
+	if len(denyStatements)+len(allowStatements) == 0 {
+		return nil
+	}
+	return &bucketPolicy{
+		Version:    iamConfigVersion,
+		Statements: append(denyStatements, allowStatements...),
+	}
+}
+
+// getCredentialForAccessKey - returns the credential requests signed
+// with the given access key are verified against. These are either the
+// server credentials, the credentials of an enabled IAM user or
+// temporary credentials whose parent user is still valid, temporary
+// credentials of LDAP and OpenID Connect users have no parent user.
+func getCredentialForAccessKey(accessKey string) (credential, bool) {
+	cred := serverConfig.GetCredential()
+	if accessKey == cred.AccessKey {
+		return cred, true
+	}
+	if tempUser, ok := globalIAMSys.GetTempUser(accessKey); ok {
+		if tempUser.ParentUser != "" {
+			if _, ok = getCredentialForAccessKey(tempUser.ParentUser); !ok {
+				return credential{}, false
+			}
+		}
+		return tempUser.Credential, true
+	}
+	user, ok := globalIAMSys.GetUser(accessKey)
+	if !ok || user.Status != iamUserEnabled {
+		return credential{}, false
+	}
+	return user.Credential, true
+}
+
+// isValidIAMUserStatus - checks if status is a known IAM user state.
+func isValidIAMUserStatus(status string) bool {
+	return status == iamUserEnabled || status == iamUserDisabled
+}
+
+// readIAMConfig - reads and decodes an IAM file, the caller is
+// expected to hold a lock on the file.
+func readIAMConfig(file string, v interface{}, objAPI ObjectLayer) error {
+	iamPath := path.Join(iamConfigPrefix, file)
+
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, iamPath, 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			// Nothing configured yet.
+			return nil
+		}
+		errorIf(err, "Unable to load IAM configuration %s.", file)
+		return errorCause(err)
+	}
+	return json.Unmarshal(buffer.Bytes(), v)
+}
+
+// writeIAMConfig - encodes and writes an IAM file, the caller is
+// expected to hold a write lock on the file.
+func writeIAMConfig(file string, v interface{}, objAPI ObjectLayer) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		errorIf(err, "Unable to marshal IAM configuration %s into JSON.", file)
+		return err
+	}
+
+	iamPath := path.Join(iamConfigPrefix, file)
+	sha256Sum := getSHA256Hash(buf)
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, iamPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum); err != nil {
+		errorIf(err, "Unable to write IAM configuration %s.", file)
+		return errorCause(err)
+	}
+	return nil
+}
+
+// readIAMUsers - reads all the IAM users, the caller is expected to
+// hold a lock on the users file.
+func readIAMUsers(objAPI ObjectLayer) (map[string]iamUser, error) {
+	usersCfg := iamUsersConfig{}
+	if err := readIAMConfig(iamUsersFile, &usersCfg, objAPI); err != nil {
+		return nil, err
+	}
+	if usersCfg.Users == nil {
+		usersCfg.Users = make(map[string]iamUser)
+	}
+	return usersCfg.Users, nil
+}
+
+// readIAMPolicies - reads all the named IAM policies, the caller is
+// expected to hold a lock on the policies file.
+func readIAMPolicies(objAPI ObjectLayer) (map[string]*bucketPolicy, error) {
+	policiesCfg := iamPoliciesConfig{}
+	if err := readIAMConfig(iamPoliciesFile, &policiesCfg, objAPI); err != nil {
+		return nil, err
+	}
+	if policiesCfg.Policies == nil {
+		policiesCfg.Policies = make(map[string]*bucketPolicy)
+	}
+	return policiesCfg.Policies, nil
+}
+
+// readIAM - reads all the IAM users, policies and temporary
+// credentials into globalIAMSys.
+func readIAM(objAPI ObjectLayer) error {
+	users, err := readIAMUsers(objAPI)
+	if err != nil {
+		return err
+	}
+	policies, err := readIAMPolicies(objAPI)
+	if err != nil {
+		return err
+	}
+	tempUsers, err := readIAMTempUsers(objAPI)
+	if err != nil {
+		return err
+	}
+
+	globalIAMSys.Set(users, policies)
+	globalIAMSys.SetTempUsers(tempUsers)
+	return nil
+}
+
+// loadIAM - reloads all the IAM users, policies and temporary
+// credentials after a change.
+func loadIAM(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	usersLock := globalNSMutex.NewNSLock(minioMetaBucket, path.Join(iamConfigPrefix, iamUsersFile))
+	usersLock.RLock()
+	defer usersLock.RUnlock()
+	policiesLock := globalNSMutex.NewNSLock(minioMetaBucket, path.Join(iamConfigPrefix, iamPoliciesFile))
+	policiesLock.RLock()
+	defer policiesLock.RUnlock()
+	tempUsersLock := globalNSMutex.NewNSLock(minioMetaBucket, path.Join(iamConfigPrefix, iamTempUsersFile))
+	tempUsersLock.RLock()
+	defer tempUsersLock.RUnlock()
+
+	return readIAM(objAPI)
+}
+
+// initIAMSys - loads the IAM users and policies on startup, this
+// happens before the object layer serves any request so no locks
+// are taken.
+func initIAMSys(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	return readIAM(objAPI)
+}
+
+// updateIAMUsers - applies fn to the persisted IAM users while holding
+// a write lock and notifies all peers (including self) on success.
+func updateIAMUsers(objAPI ObjectLayer, fn func(users map[string]iamUser) error) error {
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, path.Join(iamConfigPrefix, iamUsersFile))
+	objLock.Lock()
+	usersCfg := iamUsersConfig{}
+	err := readIAMConfig(iamUsersFile, &usersCfg, objAPI)
+	if err == nil {
+		if usersCfg.Users == nil {
+			usersCfg.Users = make(map[string]iamUser)
+		}
+		if err = fn(usersCfg.Users); err == nil {
+			usersCfg.Version = iamConfigVersion
+			err = writeIAMConfig(iamUsersFile, &usersCfg, objAPI)
+		}
+	}
+	objLock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	S3PeersLoadConfig(iamConfigName, "")
+	return nil
+}
+
+// updateIAMPolicies - applies fn to the persisted IAM policies while
+// holding a write lock and notifies all peers (including self) on
+// success.
+func updateIAMPolicies(objAPI ObjectLayer, fn func(policies map[string]*bucketPolicy) error) error {
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, path.Join(iamConfigPrefix, iamPoliciesFile))
+	objLock.Lock()
+	policiesCfg := iamPoliciesConfig{}
+	err := readIAMConfig(iamPoliciesFile, &policiesCfg, objAPI)
+	if err == nil {
+		if policiesCfg.Policies == nil {
+			policiesCfg.Policies = make(map[string]*bucketPolicy)
+		}
+		if err = fn(policiesCfg.Policies); err == nil {
+			policiesCfg.Version = iamConfigVersion
+			err = writeIAMConfig(iamPoliciesFile, &policiesCfg, objAPI)
+		}
+	}
+	objLock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	S3PeersLoadConfig(iamConfigName, "")
+	return nil
+}
+
+// setIAMUser - creates a user or updates the credentials and status of
+// an existing one, attached policies are kept.
+func setIAMUser(cred credential, status string, objAPI ObjectLayer) error {
+	return updateIAMUsers(objAPI, func(users map[string]iamUser) error {
+		user := users[cred.AccessKey]
+		user.Credential = cred
+		user.Status = status
+		users[cred.AccessKey] = user
+		return nil
+	})
+}
+
+// removeIAMUser - removes a user.
+func removeIAMUser(accessKey string, objAPI ObjectLayer) error {
+	return updateIAMUsers(objAPI, func(users map[string]iamUser) error {
+		if _, ok := users[accessKey]; !ok {
+			return errNoSuchUser
+		}
+		delete(users, accessKey)
+		return nil
+	})
+}
+
+// setIAMUserStatus - enables or disables a user.
+func setIAMUserStatus(accessKey, status string, objAPI ObjectLayer) error {
+	return updateIAMUsers(objAPI, func(users map[string]iamUser) error {
+		user, ok := users[accessKey]
+		if !ok {
+			return errNoSuchUser
+		}
+		user.Status = status
+		users[accessKey] = user
+		return nil
+	})
+}
+
+// setIAMUserPolicies - replaces the policies attached to a user, all
+// of them must exist.
+func setIAMUserPolicies(accessKey string, policyNames []string, objAPI ObjectLayer) error {
+	policiesLock := globalNSMutex.NewNSLock(minioMetaBucket, path.Join(iamConfigPrefix, iamPoliciesFile))
+	policiesLock.RLock()
+	policies, err := readIAMPolicies(objAPI)
+	policiesLock.RUnlock()
+	if err != nil {
+		return err
+	}
+	for _, name := range policyNames {
+		if _, ok := policies[name]; !ok {
+			return errNoSuchIAMPolicy
+		}
+	}
+
+	return updateIAMUsers(objAPI, func(users map[string]iamUser) error {
+		user, ok := users[accessKey]
+		if !ok {
+			return errNoSuchUser
+		}
+		user.Policies = policyNames
+		users[accessKey] = user
+		return nil
+	})
+}
+
+// setIAMPolicy - creates or replaces a named policy.
+func setIAMPolicy(name string, policy *bucketPolicy, objAPI ObjectLayer) error {
+	return updateIAMPolicies(objAPI, func(policies map[string]*bucketPolicy) error {
+		policies[name] = policy
+		return nil
+	})
+}
+
+// removeIAMPolicy - removes a named policy, users it is attached to
+// lose the permissions it granted.
+func removeIAMPolicy(name string, objAPI ObjectLayer) error {
+	return updateIAMPolicies(objAPI, func(policies map[string]*bucketPolicy) error {
+		if _, ok := policies[name]; !ok {
+			return errNoSuchIAMPolicy
+		}
+		delete(policies, name)
+		return nil
+	})
+}
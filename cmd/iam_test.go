@@ -0,0 +1,200 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+// Returns a parsed IAM policy, fails the test on errors.
+func mustParseIAMPolicy(t TestErrHandler, policyStr string) *bucketPolicy {
+	policy := &bucketPolicy{}
+	if err := parseIAMPolicy(strings.NewReader(policyStr), policy); err != nil {
+		t.Fatalf("Unable to parse IAM policy %s: %v", policyStr, err)
+	}
+	return policy
+}
+
+// Tests combining the policies attached to a user.
+func TestIAMSysGetUserPolicy(t *testing.T) {
+	readOnly := mustParseIAMPolicy(t, `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/*"]}]}`)
+	denySecret := mustParseIAMPolicy(t, `{"Version": "2012-10-17", "Statement": [{"Effect": "Deny", "Action": ["s3:*"], "Resource": ["arn:aws:s3:::mybucket/secret/*"]}]}`)
+
+	sys := newIAMSys()
+	sys.Set(map[string]iamUser{
+		"enabled-user":  {Credential: credential{AccessKey: "enabled-user", SecretKey: "secret123"}, Status: iamUserEnabled, Policies: []string{"readonly", "deny-secret", "removed"}},
+		"disabled-user": {Credential: credential{AccessKey: "disabled-user", SecretKey: "secret123"}, Status: iamUserDisabled, Policies: []string{"readonly"}},
+		"no-policy":     {Credential: credential{AccessKey: "no-policy", SecretKey: "secret123"}, Status: iamUserEnabled},
+	}, map[string]*bucketPolicy{
+		"readonly":    readOnly,
+		"deny-secret": denySecret,
+	})
+
+	policy := sys.GetUserPolicy("enabled-user")
+	if policy == nil {
+		t.Fatal("Expected a policy for an enabled user with policies")
+	}
+	if len(policy.Statements) != 2 {
+		t.Fatalf("Expected 2 statements, got %d", len(policy.Statements))
+	}
+	if policy.Statements[0].Effect != "Deny" {
+		t.Errorf("Expected deny statements first, got %s", policy.Statements[0].Effect)
+	}
+	if !bucketPolicyEvalStatements("s3:GetObject", "arn:aws:s3:::mybucket/public/object", nil, policy.Statements) {
+		t.Error("Expected s3:GetObject to be allowed")
+	}
+	if bucketPolicyEvalStatements("s3:GetObject", "arn:aws:s3:::mybucket/secret/object", nil, policy.Statements) {
+		t.Error("Expected s3:GetObject to be denied by the deny statement")
+	}
+	if bucketPolicyEvalStatements("s3:PutObject", "arn:aws:s3:::mybucket/public/object", nil, policy.Statements) {
+		t.Error("Expected s3:PutObject not to be allowed")
+	}
+
+	for _, accessKey := range []string{"disabled-user", "no-policy", "unknown-user"} {
+		if policy = sys.GetUserPolicy(accessKey); policy != nil {
+			t.Errorf("Expected no policy for %s, got %v", accessKey, policy)
+		}
+	}
+}
+
+// Tests looking up the credentials requests are verified against.
+func TestGetCredentialForAccessKey(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+	defer resetGlobalIAMSys()
+
+	globalIAMSys.Set(map[string]iamUser{
+		"enabled-user":  {Credential: credential{AccessKey: "enabled-user", SecretKey: "secret123"}, Status: iamUserEnabled},
+		"disabled-user": {Credential: credential{AccessKey: "disabled-user", SecretKey: "secret123"}, Status: iamUserDisabled},
+	}, nil)
+
+	serverCred := serverConfig.GetCredential()
+	testCases := []struct {
+		accessKey    string
+		expectedCred credential
+		expectedOk   bool
+	}{
+		{serverCred.AccessKey, serverCred, true},
+		{"enabled-user", credential{AccessKey: "enabled-user", SecretKey: "secret123"}, true},
+		{"disabled-user", credential{}, false},
+		{"unknown-user", credential{}, false},
+	}
+	for i, testCase := range testCases {
+		cred, ok := getCredentialForAccessKey(testCase.accessKey)
+		if ok != testCase.expectedOk {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedOk, ok)
+		}
+		if cred != testCase.expectedCred {
+			t.Errorf("Test %d: Expected credential %v, got %v", i+1, testCase.expectedCred, cred)
+		}
+	}
+}
+
+// Wrapper for calling IAM persistence tests for both XL and FS.
+func TestIAMPersistence(t *testing.T) {
+	initNSLock(false)
+	ExecObjectLayerTest(t, testIAMPersistence)
+}
+
+// Tests IAM users and policies are persisted and loaded.
+func testIAMPersistence(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+	initGlobalS3Peers(nil)
+	defer resetGlobalIAMSys()
+
+	policy := mustParseIAMPolicy(t, `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/*"]}]}`)
+	if err := setIAMPolicy("readonly", policy, obj); err != nil {
+		t.Fatalf("%s: Unable to set policy: %v", instanceType, err)
+	}
+	if err := setIAMUser(credential{AccessKey: "newuser", SecretKey: "newuser123"}, iamUserEnabled, obj); err != nil {
+		t.Fatalf("%s: Unable to set user: %v", instanceType, err)
+	}
+	if err := setIAMUserPolicies("newuser", []string{"readonly"}, obj); err != nil {
+		t.Fatalf("%s: Unable to set user policies: %v", instanceType, err)
+	}
+	if err := setIAMUserPolicies("newuser", []string{"missing"}, obj); err != errNoSuchIAMPolicy {
+		t.Errorf("%s: Expected %v, got %v", instanceType, errNoSuchIAMPolicy, err)
+	}
+	if err := setIAMUserPolicies("unknown", nil, obj); err != errNoSuchUser {
+		t.Errorf("%s: Expected %v, got %v", instanceType, errNoSuchUser, err)
+	}
+
+	// Updating the secret key keeps the attached policies.
+	if err := setIAMUser(credential{AccessKey: "newuser", SecretKey: "newsecret123"}, iamUserEnabled, obj); err != nil {
+		t.Fatalf("%s: Unable to update user: %v", instanceType, err)
+	}
+
+	// Peers (including self) are notified of the changes, reload
+	// from scratch to verify what was persisted.
+	resetGlobalIAMSys()
+	if err := loadIAM(obj); err != nil {
+		t.Fatalf("%s: Unable to load IAM: %v", instanceType, err)
+	}
+	user, ok := globalIAMSys.GetUser("newuser")
+	if !ok {
+		t.Fatalf("%s: Expected user to be loaded", instanceType)
+	}
+	if user.Credential.SecretKey != "newsecret123" || user.Status != iamUserEnabled {
+		t.Errorf("%s: Unexpected user %v", instanceType, user)
+	}
+	if len(user.Policies) != 1 || user.Policies[0] != "readonly" {
+		t.Errorf("%s: Unexpected user policies %v", instanceType, user.Policies)
+	}
+	loadedPolicy, ok := globalIAMSys.ListPolicies()["readonly"]
+	if !ok || loadedPolicy.String() != policy.String() {
+		t.Errorf("%s: Expected policy %s, got %v", instanceType, policy, loadedPolicy)
+	}
+
+	if err := setIAMUserStatus("newuser", iamUserDisabled, obj); err != nil {
+		t.Fatalf("%s: Unable to disable user: %v", instanceType, err)
+	}
+	if _, ok = getCredentialForAccessKey("newuser"); ok {
+		t.Errorf("%s: Expected disabled user to have no credentials", instanceType)
+	}
+
+	if err := removeIAMPolicy("readonly", obj); err != nil {
+		t.Fatalf("%s: Unable to remove policy: %v", instanceType, err)
+	}
+	if err := removeIAMPolicy("readonly", obj); err != errNoSuchIAMPolicy {
+		t.Errorf("%s: Expected %v, got %v", instanceType, errNoSuchIAMPolicy, err)
+	}
+	if err := removeIAMUser("newuser", obj); err != nil {
+		t.Fatalf("%s: Unable to remove user: %v", instanceType, err)
+	}
+	if err := removeIAMUser("newuser", obj); err != errNoSuchUser {
+		t.Errorf("%s: Expected %v, got %v", instanceType, errNoSuchUser, err)
+	}
+	if users := globalIAMSys.ListUsers(); len(users) != 0 {
+		t.Errorf("%s: Expected no users, got %v", instanceType, users)
+	}
+
+	var buffer bytes.Buffer
+	if err := obj.GetObject(context.Background(), minioMetaBucket, iamConfigPrefix+"/"+iamUsersFile, 0, -1, &buffer); err != nil {
+		t.Fatalf("%s: Unable to read users file: %v", instanceType, err)
+	}
+	if strings.Contains(buffer.String(), "newuser") {
+		t.Errorf("%s: Expected removed user not to be persisted, got %s", instanceType, buffer.String())
+	}
+}
@@ -36,6 +36,11 @@ const (
 
 	// Inter-node JWT token expiry is 100 years approx.
 	defaultInterNodeJWTExpiry = 100 * 365 * 24 * time.Hour
+
+	// Audiences of JWT tokens, tokens issued for web handlers are
+	// never accepted by inter-node RPC and vice versa.
+	jwtAudienceWeb  = "web"
+	jwtAudienceNode = "node"
 )
 
 var errInvalidAccessKeyLength = errors.New("Invalid access key, access key should be 5 to 20 characters in length")
@@ -44,8 +49,9 @@ var errInvalidSecretKeyLength = errors.New("Invalid secret key, secret key shoul
 var errInvalidAccessKeyID = errors.New("The access key ID you provided does not exist in our records")
 var errAuthentication = errors.New("Authentication failed, check your access credentials")
 var errNoAuthToken = errors.New("JWT token missing")
+var errAccessDenied = errors.New("Access denied, you are not allowed to perform this operation")
 
-func authenticateJWT(accessKey, secretKey string, expiry time.Duration) (string, error) {
+func authenticateJWT(accessKey, secretKey, audience string, expiry time.Duration) (string, error) {
 	// Trim spaces.
 	accessKey = strings.TrimSpace(accessKey)
 
@@ -70,22 +76,29 @@ func authenticateJWT(accessKey, secretKey string, expiry time.Duration) (string,
 		return "", errAuthentication
 	}
 
+	return newAuthToken(accessKey, audience, expiry)
+}
+
+// newAuthToken - signs an auth token issued to the given access key
+// for the given audience.
+func newAuthToken(accessKey, audience string, expiry time.Duration) (string, error) {
 	utcNow := time.Now().UTC()
 	token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, jwtgo.MapClaims{
 		"exp": utcNow.Add(expiry).Unix(),
 		"iat": utcNow.Unix(),
 		"sub": accessKey,
+		"aud": audience,
 	})
 
-	return token.SignedString([]byte(serverCred.SecretKey))
+	return token.SignedString([]byte(serverConfig.GetCredential().SecretKey))
 }
 
 func authenticateNode(accessKey, secretKey string) (string, error) {
-	return authenticateJWT(accessKey, secretKey, defaultInterNodeJWTExpiry)
+	return authenticateJWT(accessKey, secretKey, jwtAudienceNode, defaultInterNodeJWTExpiry)
 }
 
 func authenticateWeb(accessKey, secretKey string) (string, error) {
-	return authenticateJWT(accessKey, secretKey, defaultJWTExpiry)
+	return authenticateJWT(accessKey, secretKey, jwtAudienceWeb, defaultJWTExpiry)
 }
 
 func keyFuncCallback(jwtToken *jwtgo.Token) (interface{}, error) {
@@ -96,14 +109,53 @@ func keyFuncCallback(jwtToken *jwtgo.Token) (interface{}, error) {
 	return []byte(serverConfig.GetCredential().SecretKey), nil
 }
 
-func isAuthTokenValid(tokenString string) bool {
+// parseAuthToken - parses an auth token issued for the given audience
+// and returns its claims. Session tokens of temporary credentials are
+// signed with the same key, they carry an access key claim and are
+// rejected.
+func parseAuthToken(tokenString, audience string) (jwtgo.MapClaims, error) {
 	jwtToken, err := jwtgo.Parse(tokenString, keyFuncCallback)
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := jwtToken.Claims.(jwtgo.MapClaims)
+	if !ok || !jwtToken.Valid {
+		return nil, errAuthentication
+	}
+	if _, ok = claims["accessKey"]; ok {
+		return nil, errAuthentication
+	}
+	if !claims.VerifyAudience(audience, true) {
+		return nil, errAuthentication
+	}
+	return claims, nil
+}
+
+// isAuthTokenValid - validates an inter-node auth token, only tokens
+// issued to the server credentials for inter-node use are accepted.
+func isAuthTokenValid(tokenString string) bool {
+	claims, err := parseAuthToken(tokenString, jwtAudienceNode)
 	if err != nil {
 		errorIf(err, "Unable to parse JWT token string")
 		return false
 	}
 
-	return jwtToken.Valid
+	accessKey, _ := claims["sub"].(string)
+	return accessKey == serverConfig.GetCredential().AccessKey
+}
+
+// authTokenSubject - returns the access key an auth token was issued
+// to, errAuthentication if the token or the access key is not valid.
+func authTokenSubject(tokenString string) (string, error) {
+	claims, err := parseAuthToken(tokenString, jwtAudienceWeb)
+	if err != nil {
+		return "", errAuthentication
+	}
+	accessKey, _ := claims["sub"].(string)
+	if _, ok := getCredentialForAccessKey(accessKey); !ok {
+		return "", errAuthentication
+	}
+	return accessKey, nil
 }
 
 func isHTTPRequestValid(req *http.Request) bool {
@@ -114,16 +166,19 @@ func isHTTPRequestValid(req *http.Request) bool {
 // Returns nil if the request is authenticated. errNoAuthToken if token missing.
 // Returns errAuthentication for all other errors.
 func webReqestAuthenticate(req *http.Request) error {
-	jwtToken, err := jwtreq.ParseFromRequest(req, jwtreq.AuthorizationHeaderExtractor, keyFuncCallback)
+	_, err := webRequestSubject(req)
+	return err
+}
+
+// webRequestSubject - returns the access key the auth token of the
+// request was issued to, errors are the same as webReqestAuthenticate.
+func webRequestSubject(req *http.Request) (string, error) {
+	tokenString, err := jwtreq.AuthorizationHeaderExtractor.ExtractToken(req)
 	if err != nil {
 		if err == jwtreq.ErrNoTokenInRequest {
-			return errNoAuthToken
+			return "", errNoAuthToken
 		}
-		return errAuthentication
-	}
-
-	if !jwtToken.Valid {
-		return errAuthentication
+		return "", errAuthentication
 	}
-	return nil
+	return authTokenSubject(tokenString)
 }
@@ -82,3 +82,54 @@ func TestNodeAuthenticate(t *testing.T) {
 func TestWebAuthenticate(t *testing.T) {
 	testAuthenticate("web", t)
 }
+
+// Tests that inter-node RPC only accepts node tokens of the server
+// credentials, web tokens are rejected.
+func TestIsAuthTokenValid(t *testing.T) {
+	testPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("unable initialize config file, %s", err)
+	}
+	defer removeAll(testPath)
+
+	serverCred := serverConfig.GetCredential()
+	nodeToken, err := authenticateNode(serverCred.AccessKey, serverCred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	webToken, err := authenticateWeb(serverCred.AccessKey, serverCred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherNodeToken, err := newAuthToken("otheruser", jwtAudienceNode, defaultJWTExpiry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		token         string
+		expectedValid bool
+	}{
+		// Test case - 1.
+		// Node token of the server credentials.
+		{nodeToken, true},
+		// Test case - 2.
+		// Web token of the server credentials.
+		{webToken, false},
+		// Test case - 3.
+		// Node token of another access key.
+		{otherNodeToken, false},
+		// Test case - 4.
+		// Garbage token.
+		{"garbage", false},
+	}
+	for i, testCase := range testCases {
+		if valid := isAuthTokenValid(testCase.token); valid != testCase.expectedValid {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedValid, valid)
+		}
+		// Web handlers never accept node tokens.
+		if _, err = authTokenSubject(testCase.token); err == nil && testCase.token != webToken {
+			t.Errorf("Test %d: Expected web authentication to fail", i+1)
+		}
+	}
+}
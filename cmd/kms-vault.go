@@ -0,0 +1,143 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// vaultKMS - KMS backed by the transit secrets engine of a Vault server,
+// master keys are the named keys of the transit engine.
+type vaultKMS struct {
+	endpoint     string
+	token        string
+	defaultKeyID string
+	client       *http.Client
+}
+
+// newVaultKMS - returns a KMS talking to the Vault server at endpoint,
+// authenticated by token.
+func newVaultKMS(endpoint, token, defaultKeyID string) (KMS, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("Invalid Vault endpoint %s", endpoint)
+	}
+	if token == "" || defaultKeyID == "" {
+		return nil, fmt.Errorf("Vault requires %s and %s to be set", envSSEVaultToken, envSSEVaultKeyName)
+	}
+	return &vaultKMS{
+		endpoint:     strings.TrimSuffix(endpoint, "/"),
+		token:        token,
+		defaultKeyID: defaultKeyID,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: globalRootCAs}},
+		},
+	}, nil
+}
+
+// vaultResponse - the parts of a transit response used by vaultKMS.
+type vaultResponse struct {
+	Data struct {
+		Plaintext  string `json:"plaintext"`
+		Ciphertext string `json:"ciphertext"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// Sends a transit request for keyID, operation is one of `datakey/plaintext`
+// and `decrypt`.
+func (kms *vaultKMS) call(operation, keyID string, request map[string]interface{}) (*vaultResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	reqURL := kms.endpoint + "/v1/transit/" + operation + "/" + url.QueryEscape(keyID)
+	req, err := http.NewRequest("POST", reqURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", kms.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := kms.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Error responses may come without a body.
+	response := &vaultResponse{}
+	err = json.NewDecoder(resp.Body).Decode(response)
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errKMSKeyNotFound
+	case len(response.Errors) > 0:
+		return nil, fmt.Errorf("Vault: %s", strings.Join(response.Errors, ", "))
+	}
+	return nil, fmt.Errorf("Vault: unexpected response %s", resp.Status)
+}
+
+func (kms *vaultKMS) DefaultKeyID() string {
+	return kms.defaultKeyID
+}
+
+func (kms *vaultKMS) GenerateKey(keyID string, context []byte) ([]byte, []byte, error) {
+	response, err := kms.call("datakey/plaintext", keyID, map[string]interface{}{
+		"context": base64.StdEncoding.EncodeToString(context),
+		"bits":    256,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(response.Data.Plaintext)
+	if err != nil || len(key) != 32 || response.Data.Ciphertext == "" {
+		return nil, nil, fmt.Errorf("Vault: invalid data key")
+	}
+	return key, []byte(response.Data.Ciphertext), nil
+}
+
+func (kms *vaultKMS) UnsealKey(keyID string, sealedKey, context []byte) ([]byte, error) {
+	response, err := kms.call("decrypt", keyID, map[string]interface{}{
+		"ciphertext": string(sealedKey),
+		"context":    base64.StdEncoding.EncodeToString(context),
+	})
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(response.Data.Plaintext)
+	if err != nil || len(key) != 32 {
+		return nil, errObjectTampered
+	}
+	return key, nil
+}
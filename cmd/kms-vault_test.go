@@ -0,0 +1,158 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// vaultStub - minimal transit engine of a Vault server, data keys are
+// kept in memory and ciphertexts are indices into them.
+type vaultStub struct {
+	mutex sync.Mutex
+	token string
+	keys  map[string]bool
+	// Data keys and their contexts indexed by ciphertext.
+	dataKeys map[string][2]string
+}
+
+func (stub *vaultStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	stub.mutex.Lock()
+	defer stub.mutex.Unlock()
+
+	writeResponse := func(status int, data map[string]string, errs ...string) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "errors": errs})
+	}
+	if r.Header.Get("X-Vault-Token") != stub.token {
+		writeResponse(http.StatusForbidden, nil, "permission denied")
+		return
+	}
+
+	var request map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+		writeResponse(http.StatusBadRequest, nil, err.Error())
+		return
+	}
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/transit/datakey/plaintext/"):
+		if !stub.keys[strings.TrimPrefix(r.URL.Path, "/v1/transit/datakey/plaintext/")] {
+			writeResponse(http.StatusNotFound, nil)
+			return
+		}
+		key := make([]byte, 32)
+		rand.Read(key)
+		plaintext := base64.StdEncoding.EncodeToString(key)
+		ciphertext := "vault:v1:" + base64.StdEncoding.EncodeToString(key[:8])
+		stub.dataKeys[ciphertext] = [2]string{plaintext, fmt.Sprint(request["context"])}
+		writeResponse(http.StatusOK, map[string]string{"plaintext": plaintext, "ciphertext": ciphertext})
+	case strings.HasPrefix(r.URL.Path, "/v1/transit/decrypt/"):
+		if !stub.keys[strings.TrimPrefix(r.URL.Path, "/v1/transit/decrypt/")] {
+			writeResponse(http.StatusNotFound, nil)
+			return
+		}
+		dataKey, ok := stub.dataKeys[fmt.Sprint(request["ciphertext"])]
+		if !ok || dataKey[1] != fmt.Sprint(request["context"]) {
+			writeResponse(http.StatusBadRequest, nil, "cipher: message authentication failed")
+			return
+		}
+		writeResponse(http.StatusOK, map[string]string{"plaintext": dataKey[0]})
+	default:
+		writeResponse(http.StatusNotFound, nil)
+	}
+}
+
+// Tests the Vault KMS against a local transit stub.
+func TestVaultKMS(t *testing.T) {
+	stub := &vaultStub{
+		token:    "vault-token",
+		keys:     map[string]bool{"my-key": true},
+		dataKeys: make(map[string][2]string),
+	}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	kms, err := newVaultKMS(server.URL, stub.token, "my-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kms.DefaultKeyID() != "my-key" {
+		t.Fatalf("Expected key id my-key, got %s", kms.DefaultKeyID())
+	}
+
+	context := []byte("bucket/object")
+	key, sealedKey, err := kms.GenerateKey("my-key", context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsealed, err := kms.UnsealKey("my-key", sealedKey, context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(unsealed, key) {
+		t.Fatal("Unsealed key does not match the data key")
+	}
+
+	if _, _, err = kms.GenerateKey("other-key", context); err != errKMSKeyNotFound {
+		t.Errorf("Expected %v, got %v", errKMSKeyNotFound, err)
+	}
+	if _, err = kms.UnsealKey("my-key", sealedKey, []byte("bucket/other-object")); err == nil {
+		t.Error("Expected an error for a different context")
+	}
+
+	// Requests with a wrong token are rejected.
+	kms, err = newVaultKMS(server.URL, "wrong-token", "my-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = kms.GenerateKey("my-key", context); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Expected permission denied, got %v", err)
+	}
+}
+
+// Tests validation of the Vault configuration.
+func TestNewVaultKMS(t *testing.T) {
+	testCases := []struct {
+		endpoint, token, keyName string
+		shouldPass               bool
+	}{
+		{"https://vault.example.com:8200", "token", "my-key", true},
+		{"http://127.0.0.1:8200/", "token", "my-key", true},
+		{"vault.example.com:8200", "token", "my-key", false},
+		{"https://vault.example.com:8200", "", "my-key", false},
+		{"https://vault.example.com:8200", "token", "", false},
+	}
+	for i, testCase := range testCases {
+		_, err := newVaultKMS(testCase.endpoint, testCase.token, testCase.keyName)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Unexpected error %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected an error", i+1)
+		}
+	}
+}
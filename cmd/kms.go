@@ -0,0 +1,169 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// KMS - key management service handing out the data keys of SSE-S3 and
+// SSE-KMS encrypted objects. Data keys are returned in plaintext and
+// sealed by a master key which never leaves the KMS, only the sealed
+// key is stored with the object.
+type KMS interface {
+	// DefaultKeyID - the master key used if a request does not name one.
+	DefaultKeyID() string
+
+	// GenerateKey - returns a new data key and the data key sealed by
+	// the master key keyID. The context is bound to the sealed key and
+	// has to be presented again to unseal it.
+	GenerateKey(keyID string, context []byte) (key, sealedKey []byte, err error)
+
+	// UnsealKey - returns the data key of a sealed key.
+	UnsealKey(keyID string, sealedKey, context []byte) ([]byte, error)
+}
+
+var (
+	// errKMSNotConfigured - SSE-S3 or SSE-KMS is used without a KMS.
+	errKMSNotConfigured = errors.New("Server side encryption requires a key management service")
+
+	// errKMSKeyNotFound - the master key is not known to the KMS.
+	errKMSKeyNotFound = errors.New("The specified master key does not exist")
+)
+
+// Global KMS, nil if none is configured.
+var globalKMS KMS
+
+const (
+	// File in the config dir holding the master key of the built-in
+	// KMS, a single line `<key-id>:<64 hex characters>`.
+	globalMinioKMSMasterKeyFile = "master.key"
+
+	// Environment variables selecting a Vault transit backend.
+	envSSEVaultEndpoint = "MINIO_SSE_VAULT_ENDPOINT"
+	envSSEVaultToken    = "MINIO_SSE_VAULT_TOKEN"
+	envSSEVaultKeyName  = "MINIO_SSE_VAULT_KEY_NAME"
+)
+
+// masterKeyKMS - built-in KMS sealing data keys with a single master
+// key loaded from the config dir.
+type masterKeyKMS struct {
+	keyID     string
+	masterKey []byte
+}
+
+// newMasterKeyKMS - returns a KMS for a 32 byte master key.
+func newMasterKeyKMS(keyID string, masterKey []byte) (KMS, error) {
+	if keyID == "" || len(masterKey) != 32 {
+		return nil, errInvalidArgument
+	}
+	return &masterKeyKMS{keyID: keyID, masterKey: masterKey}, nil
+}
+
+// parseMasterKey - parses a master key of the form `<key-id>:<hex key>`.
+func parseMasterKey(s string) (KMS, error) {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return nil, fmt.Errorf("Invalid master key, expected <key-id>:<hex key>")
+	}
+	masterKey, err := hex.DecodeString(s[i+1:])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid master key, %s", err)
+	}
+	return newMasterKeyKMS(s[:i], masterKey)
+}
+
+func (kms *masterKeyKMS) DefaultKeyID() string {
+	return kms.keyID
+}
+
+func (kms *masterKeyKMS) GenerateKey(keyID string, context []byte) ([]byte, []byte, error) {
+	if keyID != kms.keyID {
+		return nil, nil, errKMSKeyNotFound
+	}
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, nil, err
+	}
+	aead, err := newSSECipher(kms.masterKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, err
+	}
+	return key, aead.Seal(nonce, nonce, key, context), nil
+}
+
+func (kms *masterKeyKMS) UnsealKey(keyID string, sealedKey, context []byte) ([]byte, error) {
+	if keyID != kms.keyID {
+		return nil, errKMSKeyNotFound
+	}
+	aead, err := newSSECipher(kms.masterKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealedKey) < aead.NonceSize() {
+		return nil, errObjectTampered
+	}
+	nonce, sealedKey := sealedKey[:aead.NonceSize()], sealedKey[aead.NonceSize():]
+	key, err := aead.Open(nil, nonce, sealedKey, context)
+	if err != nil {
+		return nil, errObjectTampered
+	}
+	return key, nil
+}
+
+// newKMS - returns the KMS configured for the server, a Vault transit
+// backend if `MINIO_SSE_VAULT_ENDPOINT` is set, otherwise the built-in
+// KMS if a master key is present in the config dir. Returns nil if
+// neither is configured.
+func newKMS() (KMS, error) {
+	if endpoint := os.Getenv(envSSEVaultEndpoint); endpoint != "" {
+		return newVaultKMS(endpoint, os.Getenv(envSSEVaultToken), os.Getenv(envSSEVaultKeyName))
+	}
+
+	configDir, err := getConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(configDir, globalMinioKMSMasterKeyFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseMasterKey(string(data))
+}
+
+// initKMS - initializes the global KMS.
+func initKMS() {
+	kms, err := newKMS()
+	fatalIf(err, "Unable to initialize the key management service.")
+	globalKMS = kms
+}
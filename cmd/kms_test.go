@@ -0,0 +1,96 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// Tests parsing of the master key of the built-in KMS.
+func TestParseMasterKey(t *testing.T) {
+	key := hex.EncodeToString(bytes.Repeat([]byte{'m'}, 32))
+	testCases := []struct {
+		masterKey     string
+		expectedKeyID string
+		shouldPass    bool
+	}{
+		{"my-key:" + key, "my-key", true},
+		{"arn:minio:my-key:" + key + "\n", "arn:minio:my-key", true},
+		{key, "", false},
+		{":" + key, "", false},
+		{"my-key:" + key[:62], "", false},
+		{"my-key:" + key[:63] + "x", "", false},
+	}
+	for i, testCase := range testCases {
+		kms, err := parseMasterKey(testCase.masterKey)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Unexpected error %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected an error", i+1)
+		}
+		if err == nil && kms.DefaultKeyID() != testCase.expectedKeyID {
+			t.Errorf("Test %d: Expected key id %s, got %s", i+1, testCase.expectedKeyID, kms.DefaultKeyID())
+		}
+	}
+}
+
+// Tests sealing and unsealing data keys with the built-in KMS.
+func TestMasterKeyKMS(t *testing.T) {
+	kms, err := newMasterKeyKMS("my-key", bytes.Repeat([]byte{'m'}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	context := []byte("bucket/object")
+	key, sealedKey, err := kms.GenerateKey("my-key", context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 32 || bytes.Contains(sealedKey, key) {
+		t.Fatal("Unexpected data key")
+	}
+
+	unsealed, err := kms.UnsealKey("my-key", sealedKey, context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(unsealed, key) {
+		t.Fatal("Unsealed key does not match the data key")
+	}
+
+	// Unknown master keys, other contexts and modified keys fail.
+	if _, _, err = kms.GenerateKey("other-key", context); err != errKMSKeyNotFound {
+		t.Errorf("Expected %v, got %v", errKMSKeyNotFound, err)
+	}
+	if _, err = kms.UnsealKey("other-key", sealedKey, context); err != errKMSKeyNotFound {
+		t.Errorf("Expected %v, got %v", errKMSKeyNotFound, err)
+	}
+	if _, err = kms.UnsealKey("my-key", sealedKey, []byte("bucket/other-object")); err != errObjectTampered {
+		t.Errorf("Expected %v, got %v", errObjectTampered, err)
+	}
+	sealedKey[len(sealedKey)-1] ^= 0xff
+	if _, err = kms.UnsealKey("my-key", sealedKey, context); err != errObjectTampered {
+		t.Errorf("Expected %v, got %v", errObjectTampered, err)
+	}
+
+	// Master keys have to be 256 bits.
+	if _, err = newMasterKeyKMS("my-key", bytes.Repeat([]byte{'m'}, 16)); err != errInvalidArgument {
+		t.Errorf("Expected %v, got %v", errInvalidArgument, err)
+	}
+}
@@ -123,7 +123,7 @@ func registerStorageLockers(mux *router.Router, lockServers []*lockServer) error
 			return traceError(err)
 		}
 		lockRouter := mux.PathPrefix(reservedBucket).Subrouter()
-		lockRouter.Path(path.Join("/lock", lockServer.rpcPath)).Handler(lockRPCServer)
+		lockRouter.Path(path.Join("/lock", lockServer.rpcPath)).Handler(newRPCHandler(lockRPCServer))
 	}
 	return nil
 }
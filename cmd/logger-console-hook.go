@@ -18,29 +18,28 @@ package cmd
 
 import "github.com/Sirupsen/logrus"
 
-// consoleLogger - default logger if not other logging is enabled.
-type consoleLogger struct {
+// loggerConsole - default logger if not other logging is enabled.
+type loggerConsole struct {
 	Enable bool   `json:"enable"`
 	Level  string `json:"level"`
+	Format string `json:"format,omitempty"`
 }
 
-// enable console logger.
-func enableConsoleLogger() {
-	clogger := serverConfig.GetConsoleLogger()
-	if !clogger.Enable {
-		return
-	}
-
+// Returns a logger of a console logger config.
+func newConsoleLogger(clogger loggerConsole) (*logrus.Logger, error) {
 	consoleLogger := logrus.New()
 
-	// log.Out and log.Formatter use the default versions.
-	// Only set specific log level.
+	// log.Out uses the default version.
+	// Only set specific log level and format.
 	lvl, err := logrus.ParseLevel(clogger.Level)
-	fatalIf(err, "Unknown log level found in the config file.")
+	if err != nil {
+		return nil, err
+	}
 
 	consoleLogger.Level = lvl
-	consoleLogger.Formatter = new(logrus.TextFormatter)
-	log.mu.Lock()
-	log.loggers = append(log.loggers, consoleLogger)
-	log.mu.Unlock()
+	consoleLogger.Formatter, err = newLogFormatter(clogger.Format, logFormatText)
+	if err != nil {
+		return nil, err
+	}
+	return consoleLogger, nil
 }
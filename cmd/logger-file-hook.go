@@ -19,47 +19,65 @@ package cmd
 import (
 	"fmt"
 	"io/ioutil"
-	"os"
+	"time"
 
 	"github.com/Sirupsen/logrus"
+	humanize "github.com/dustin/go-humanize"
 )
 
-type fileLogger struct {
-	Enable   bool   `json:"enable"`
-	Filename string `json:"fileName"`
-	Level    string `json:"level"`
+// loggerFile - logs to a file, which is rotated once it grows past
+// MaxSize megabytes. Rotated files older than MaxAge days and beyond
+// the newest MaxBackups are removed, files are never rotated nor
+// removed by default.
+type loggerFile struct {
+	Enable     bool   `json:"enable"`
+	Filename   string `json:"fileName"`
+	Level      string `json:"level"`
+	Format     string `json:"format,omitempty"`
+	MaxSize    int    `json:"maxSize,omitempty"`
+	MaxAge     int    `json:"maxAge,omitempty"`
+	MaxBackups int    `json:"maxBackups,omitempty"`
+	Compress   bool   `json:"compress,omitempty"`
 }
 
 type localFile struct {
-	*os.File
+	*rotatingFile
 }
 
-func enableFileLogger() {
-	flogger := serverConfig.GetFileLogger()
-	if !flogger.Enable || flogger.Filename == "" {
-		return
+// Returns a logger of a file logger config along with its file.
+func newFileLogger(flogger loggerFile) (*logrus.Logger, *localFile, error) {
+	lvl, err := logrus.ParseLevel(flogger.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Log files are JSON unless configured otherwise.
+	formatter, err := newLogFormatter(flogger.Format, logFormatJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+	if flogger.MaxSize < 0 || flogger.MaxAge < 0 || flogger.MaxBackups < 0 {
+		return nil, nil, errInvalidArgument
 	}
 
-	// Creates the named file with mode 0666, honors system umask.
-	file, err := os.OpenFile(flogger.Filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
-	fatalIf(err, "Unable to open log file.")
+	file, err := newRotatingFile(flogger.Filename,
+		int64(flogger.MaxSize)*humanize.MiByte,
+		time.Duration(flogger.MaxAge)*24*time.Hour,
+		flogger.MaxBackups, flogger.Compress)
+	if err != nil {
+		return nil, nil, err
+	}
+	hook := &localFile{file}
 
 	fileLogger := logrus.New()
 
 	// Add a local file hook.
-	fileLogger.Hooks.Add(&localFile{file})
-
-	lvl, err := logrus.ParseLevel(flogger.Level)
-	fatalIf(err, "Unknown log level found in the config file.")
+	fileLogger.Hooks.Add(hook)
 
-	// Set default JSON formatter.
 	fileLogger.Out = ioutil.Discard
-	fileLogger.Formatter = new(logrus.JSONFormatter)
+	fileLogger.Formatter = formatter
 	fileLogger.Level = lvl // Minimum log level.
 
-	log.mu.Lock()
-	log.loggers = append(log.loggers, fileLogger)
-	log.mu.Unlock()
+	return fileLogger, hook, nil
 }
 
 // Fire fires the file logger hook and logs to the file.
@@ -68,8 +86,8 @@ func (l *localFile) Fire(entry *logrus.Entry) error {
 	if err != nil {
 		return fmt.Errorf("Unable to read entry, %v", err)
 	}
-	l.File.Write([]byte(line))
-	l.File.Sync()
+	l.rotatingFile.Write([]byte(line))
+	l.rotatingFile.Sync()
 	return nil
 }
 
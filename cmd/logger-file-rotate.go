@@ -0,0 +1,295 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotated log files are named after the log file and the time they were
+// rotated, e.g. minio.log.20170305T103000.000, and get a .gz suffix
+// once compressed.
+const (
+	rotatedLogTimeFormat = "20060102T150405.000"
+	compressedLogSuffix  = ".gz"
+)
+
+// rotatingFile - a log file which is rotated once it grows past
+// maxSize. Rotated files older than maxAge and beyond the newest
+// maxBackups are removed, zero values keep them. Rotated files are
+// compressed in the background when asked to.
+type rotatingFile struct {
+	mutex      *sync.Mutex
+	filename   string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+
+	file   *os.File
+	size   int64
+	closed bool
+
+	// Wakes up the goroutine compressing and removing rotated files.
+	millCh chan struct{}
+	doneCh chan struct{}
+	wg     *sync.WaitGroup
+}
+
+func newRotatingFile(filename string, maxSize int64, maxAge time.Duration, maxBackups int, compress bool) (*rotatingFile, error) {
+	f := &rotatingFile{
+		mutex:      &sync.Mutex{},
+		filename:   filename,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+		compress:   compress,
+		millCh:     make(chan struct{}, 1),
+		doneCh:     make(chan struct{}),
+		wg:         &sync.WaitGroup{},
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	f.wg.Add(1)
+	go f.mill()
+
+	// Clean up files rotated before a restart.
+	f.millCh <- struct{}{}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	// Creates the named file with mode 0666, honors system umask.
+	file, err := os.OpenFile(f.filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = fi.Size()
+	return nil
+}
+
+// Write appends p to the log file, the file is rotated first if p does
+// not fit anymore.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.closed {
+		return 0, errLoggerClosed
+	}
+	// Reopen the log file if a rotation failed half way.
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Sync commits the log file to stable storage.
+func (f *rotatingFile) Sync() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return errLoggerClosed
+	}
+	return f.file.Sync()
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+	rotated := f.filename + "." + time.Now().UTC().Format(rotatedLogTimeFormat)
+	if err := os.Rename(f.filename, rotated); err != nil {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	select {
+	case f.millCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Close closes the log file, rotated files left to compress are
+// compressed on the next start.
+func (f *rotatingFile) Close() error {
+	f.mutex.Lock()
+	if f.closed {
+		f.mutex.Unlock()
+		return nil
+	}
+	f.closed = true
+	file := f.file
+	f.file = nil
+	f.mutex.Unlock()
+
+	// Cleaning up rotated files may log errors to this very file,
+	// wait for it without holding the mutex.
+	close(f.doneCh)
+	f.wg.Wait()
+	if file == nil {
+		return nil
+	}
+	return file.Close()
+}
+
+func (f *rotatingFile) mill() {
+	defer f.wg.Done()
+	for {
+		select {
+		case <-f.millCh:
+			errorIf(f.millRotated(), "Unable to clean up rotated log files of %s.", f.filename)
+		case <-f.doneCh:
+			return
+		}
+	}
+}
+
+// Returns the rotated files of the log file, oldest first.
+func (f *rotatingFile) rotatedFiles() ([]string, error) {
+	dir := filepath.Dir(f.filename)
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix := filepath.Base(f.filename) + "."
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), compressedLogSuffix)
+		if _, err = time.Parse(rotatedLogTimeFormat, stamp); err != nil {
+			continue
+		}
+		names = append(names, name)
+	}
+	// Time stamps sort in the order the files were rotated.
+	sort.Strings(names)
+	var rotated []string
+	for i, name := range names {
+		// Skip compressed copies of files not done compressing.
+		if i > 0 && strings.TrimSuffix(name, compressedLogSuffix) == names[i-1] {
+			continue
+		}
+		rotated = append(rotated, filepath.Join(dir, name))
+	}
+	return rotated, nil
+}
+
+// Removes the rotated files which are too old or too many and
+// compresses the rest when asked to.
+func (f *rotatingFile) millRotated() error {
+	names, err := f.rotatedFiles()
+	if err != nil {
+		return err
+	}
+	prefix := f.filename + "."
+	var kept []string
+	for _, name := range names {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), compressedLogSuffix)
+		rotatedAt, _ := time.Parse(rotatedLogTimeFormat, stamp)
+		if f.maxAge > 0 && time.Since(rotatedAt) > f.maxAge {
+			if err = os.Remove(name); err != nil {
+				return err
+			}
+			continue
+		}
+		kept = append(kept, name)
+	}
+	if f.maxBackups > 0 && len(kept) > f.maxBackups {
+		for _, name := range kept[:len(kept)-f.maxBackups] {
+			if err = os.Remove(name); err != nil {
+				return err
+			}
+		}
+		kept = kept[len(kept)-f.maxBackups:]
+	}
+	if !f.compress {
+		return nil
+	}
+	for _, name := range kept {
+		if strings.HasSuffix(name, compressedLogSuffix) {
+			continue
+		}
+		if err = compressLogFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replaces a file with its gzip compressed copy.
+func compressLogFile(name string) (err error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+compressedLogSuffix, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			dst.Close()
+			os.Remove(name + compressedLogSuffix)
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
@@ -0,0 +1,245 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Messages are sent in the RFC5424 format, framed by their length on
+// stream transports as in RFC6587.
+
+// Defaults of the syslog logger config.
+const (
+	defaultSyslogFacility = "daemon"
+	defaultSyslogTag      = "minio"
+)
+
+// Sockets of the local syslog daemon, tried in order.
+var localSyslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// Syslog facility codes by name.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// loggerSyslog - logs to the local syslog daemon, or to a remote one
+// when Network is udp or tcp.
+type loggerSyslog struct {
+	Enable   bool   `json:"enable"`
+	Network  string `json:"network,omitempty"`
+	Address  string `json:"address,omitempty"`
+	Facility string `json:"facility,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+	Level    string `json:"level"`
+	Format   string `json:"format,omitempty"`
+}
+
+// syslogWriter - sends log entries to a syslog daemon, the connection
+// is dialed again once it fails.
+type syslogWriter struct {
+	mutex    *sync.Mutex
+	network  string
+	address  string
+	conn     net.Conn
+	closed   bool
+	facility int
+	hostname string
+	tag      string
+	pid      int
+}
+
+// Returns a logger of a syslog logger config along with its writer.
+func newSyslogLogger(slogger loggerSyslog) (*logrus.Logger, *syslogWriter, error) {
+	lvl, err := logrus.ParseLevel(slogger.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+	formatter, err := newLogFormatter(slogger.Format, logFormatText)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	facilityName := slogger.Facility
+	if facilityName == "" {
+		facilityName = defaultSyslogFacility
+	}
+	facility, ok := syslogFacilities[strings.ToLower(facilityName)]
+	if !ok {
+		return nil, nil, fmt.Errorf("Unknown syslog facility `%s`", facilityName)
+	}
+
+	w := &syslogWriter{
+		mutex:    &sync.Mutex{},
+		facility: facility,
+		tag:      slogger.Tag,
+		pid:      os.Getpid(),
+	}
+	if w.tag == "" {
+		w.tag = defaultSyslogTag
+	}
+	if w.hostname, err = os.Hostname(); err != nil || w.hostname == "" {
+		w.hostname = "-"
+	}
+	switch slogger.Network {
+	case "":
+		// Local syslog daemon, the address is a socket path if set.
+	case "udp", "tcp", "unix", "unixgram":
+		if slogger.Address == "" {
+			return nil, nil, errInvalidArgument
+		}
+	default:
+		return nil, nil, fmt.Errorf("Unknown syslog network `%s`", slogger.Network)
+	}
+	w.network = slogger.Network
+	w.address = slogger.Address
+	if err = w.connect(); err != nil {
+		return nil, nil, err
+	}
+
+	syslogLogger := logrus.New()
+	syslogLogger.Hooks.Add(w)
+	syslogLogger.Out = ioutil.Discard
+	syslogLogger.Formatter = formatter
+	syslogLogger.Level = lvl
+
+	return syslogLogger, w, nil
+}
+
+// Dials the syslog daemon, the local one is looked up on the usual
+// sockets.
+func (w *syslogWriter) connect() (err error) {
+	if w.network != "" {
+		w.conn, err = net.Dial(w.network, w.address)
+		return err
+	}
+	sockets := localSyslogSockets
+	if w.address != "" {
+		sockets = []string{w.address}
+	}
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, socket := range sockets {
+			if w.conn, err = net.Dial(network, socket); err == nil {
+				w.network = network
+				w.address = socket
+				return nil
+			}
+		}
+	}
+	return err
+}
+
+// Returns the syslog severity of a log level.
+func syslogSeverity(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel:
+		return 1 // alert
+	case logrus.FatalLevel:
+		return 2 // crit
+	case logrus.ErrorLevel:
+		return 3 // err
+	case logrus.WarnLevel:
+		return 4 // warning
+	case logrus.InfoLevel:
+		return 6 // info
+	}
+	return 7 // debug
+}
+
+// Returns the RFC5424 message of a log entry, the message is the
+// formatted entry.
+func (w *syslogWriter) format(entry *logrus.Entry, msg string) string {
+	return fmt.Sprintf("<%d>1 %s %s %s %d - - %s",
+		w.facility*8+syslogSeverity(entry.Level),
+		entry.Time.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		w.hostname, w.tag, w.pid, strings.TrimRight(msg, "\n"))
+}
+
+// Fire sends a log entry to the syslog daemon, a failed connection is
+// dialed again once.
+func (w *syslogWriter) Fire(entry *logrus.Entry) error {
+	line, err := entry.String()
+	if err != nil {
+		return fmt.Errorf("Unable to read entry, %v", err)
+	}
+	msg := w.format(entry, line)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return errLoggerClosed
+	}
+	if w.network == "tcp" || w.network == "unix" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	if w.conn != nil {
+		if _, err = w.conn.Write([]byte(msg)); err == nil {
+			return nil
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+	if err = w.connect(); err != nil {
+		return err
+	}
+	_, err = w.conn.Write([]byte(msg))
+	return err
+}
+
+// Levels - indicate log levels supported.
+func (w *syslogWriter) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Close closes the connection to the syslog daemon.
+func (w *syslogWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
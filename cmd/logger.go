@@ -17,7 +17,10 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"path"
 	"runtime"
 	"strings"
@@ -30,20 +33,132 @@ type fields map[string]interface{}
 
 var log = struct {
 	loggers []*logrus.Logger // All registered loggers.
-	mu      sync.Mutex
+	closers []io.Closer      // Files and connections of the registered loggers.
+	// Held for reading while logging, loggers are replaced and their
+	// files and connections closed only once no entry is being logged.
+	mu sync.RWMutex
 }{}
 
-// logger carries logging configuration for various supported loggers.
-// Currently supported loggers are
+var errLoggerClosed = errors.New("logger closed")
+
+// loggerConfig carries logging configuration for various supported
+// loggers. Currently supported loggers are
 //
 //   - console [default]
 //   - file
-type logger struct {
-	Console consoleLogger `json:"console"`
-	File    fileLogger    `json:"file"`
+//   - syslog
+type loggerConfig struct {
+	Console loggerConsole `json:"console"`
+	File    loggerFile    `json:"file"`
+	Syslog  loggerSyslog  `json:"syslog"`
 	// Add new loggers here.
 }
 
+// Returns the registered loggers.
+func getLoggers() []*logrus.Logger {
+	log.mu.RLock()
+	defer log.mu.RUnlock()
+	return log.loggers
+}
+
+// Calls fn with every registered logger, the loggers are not replaced
+// until fn returns.
+func forEachLogger(fn func(*logrus.Logger)) {
+	log.mu.RLock()
+	defer log.mu.RUnlock()
+	for _, l := range log.loggers {
+		fn(l)
+	}
+}
+
+// loadLoggers - replaces the registered loggers with the enabled
+// loggers of a logger config and closes the files and connections of
+// the replaced ones. The loggers are swapped under the write lock, so
+// entries being logged are written before the replaced files and
+// connections are closed. The registered loggers are kept if any
+// logger fails to be set up.
+func loadLoggers(cfg loggerConfig) (err error) {
+	var loggers []*logrus.Logger
+	var closers []io.Closer
+	defer func() {
+		if err != nil {
+			for _, closer := range closers {
+				closer.Close()
+			}
+		}
+	}()
+
+	if cfg.Console.Enable {
+		consoleLogger, err := newConsoleLogger(cfg.Console)
+		if err != nil {
+			return fmt.Errorf("Unable to set up the console logger. %v", err)
+		}
+		loggers = append(loggers, consoleLogger)
+	}
+	if cfg.File.Enable && cfg.File.Filename != "" {
+		fileLogger, file, err := newFileLogger(cfg.File)
+		if err != nil {
+			return fmt.Errorf("Unable to set up the file logger. %v", err)
+		}
+		loggers = append(loggers, fileLogger)
+		closers = append(closers, file)
+	}
+	if cfg.Syslog.Enable {
+		syslogLogger, conn, err := newSyslogLogger(cfg.Syslog)
+		if err != nil {
+			return fmt.Errorf("Unable to set up the syslog logger. %v", err)
+		}
+		loggers = append(loggers, syslogLogger)
+		closers = append(closers, conn)
+	}
+
+	log.mu.Lock()
+	replaced := log.closers
+	log.loggers, log.closers = loggers, closers
+	log.mu.Unlock()
+
+	// No entry can reach the replaced loggers anymore.
+	for _, closer := range replaced {
+		closer.Close()
+	}
+	return nil
+}
+
+// reloadLoggers - replaces the registered loggers with the loggers of
+// the config file.
+func reloadLoggers() error {
+	cfg, err := loadLoggerConfig()
+	if err != nil {
+		return err
+	}
+	if err = loadLoggers(cfg); err != nil {
+		return err
+	}
+	serverConfig.SetLogger(cfg)
+	return nil
+}
+
+// Output formats of the loggers.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// Returns the formatter of a log format, loggers without a configured
+// format use defaultFormat.
+func newLogFormatter(format, defaultFormat string) (logrus.Formatter, error) {
+	if format == "" {
+		format = defaultFormat
+	}
+	switch strings.ToLower(format) {
+	case logFormatText:
+		return new(logrus.TextFormatter), nil
+	case logFormatJSON:
+		return new(logrus.JSONFormatter), nil
+	}
+	return nil, fmt.Errorf("Unknown log format `%s`", format)
+}
+
 // Get file, line, function name of the caller.
 func callerSource() string {
 	pc, file, line, success := runtime.Caller(2)
@@ -57,12 +172,32 @@ func callerSource() string {
 	return fmt.Sprintf("[%s:%d:%s()]", file, line, name)
 }
 
-// errorIf synonymous with fatalIf but doesn't exit on error != nil
-func errorIf(err error, msg string, data ...interface{}) {
-	if err == nil || !isErrLogged(err) {
-		return
-	}
-	source := callerSource()
+// reqInfo - the request being served, log entries of errors hit while
+// serving it carry these fields.
+type reqInfo struct {
+	RequestID  string
+	RemoteAddr string
+	API        string
+	Bucket     string
+	Object     string
+}
+
+// reqInfoKey - context key of the request info.
+type reqInfoKey struct{}
+
+// Returns a context carrying the request info.
+func contextWithReqInfo(ctx context.Context, info *reqInfo) context.Context {
+	return context.WithValue(ctx, reqInfoKey{}, info)
+}
+
+// Returns the request info carried by a context, nil if there is none.
+func reqInfoFromContext(ctx context.Context) *reqInfo {
+	info, _ := ctx.Value(reqInfoKey{}).(*reqInfo)
+	return info
+}
+
+// Returns the fields of a log entry for an error.
+func errorFields(ctx context.Context, err error, source string) logrus.Fields {
 	fields := logrus.Fields{
 		"source": source,
 		"cause":  err.Error(),
@@ -70,10 +205,43 @@ func errorIf(err error, msg string, data ...interface{}) {
 	if e, ok := err.(*Error); ok {
 		fields["stack"] = strings.Join(e.Trace(), " ")
 	}
+	if info := reqInfoFromContext(ctx); info != nil {
+		fields["requestID"] = info.RequestID
+		fields["remoteAddr"] = info.RemoteAddr
+		if info.API != "" {
+			fields["api"] = info.API
+		}
+		if info.Bucket != "" {
+			fields["bucket"] = info.Bucket
+		}
+		if info.Object != "" {
+			fields["object"] = info.Object
+		}
+	}
+	return fields
+}
 
-	for _, log := range log.loggers {
+// errorIf synonymous with fatalIf but doesn't exit on error != nil
+func errorIf(err error, msg string, data ...interface{}) {
+	if err == nil || !isErrLogged(err) {
+		return
+	}
+	fields := errorFields(context.Background(), err, callerSource())
+	forEachLogger(func(log *logrus.Logger) {
 		log.WithFields(fields).Errorf(msg, data...)
+	})
+}
+
+// errorIfCtx is errorIf for errors hit while serving a request, the log
+// entries carry the request info of ctx.
+func errorIfCtx(ctx context.Context, err error, msg string, data ...interface{}) {
+	if err == nil || !isErrLogged(err) {
+		return
 	}
+	fields := errorFields(ctx, err, callerSource())
+	forEachLogger(func(log *logrus.Logger) {
+		log.WithFields(fields).Errorf(msg, data...)
+	})
 }
 
 // fatalIf wrapper function which takes error and prints jsonic error messages.
@@ -81,17 +249,10 @@ func fatalIf(err error, msg string, data ...interface{}) {
 	if err == nil || !isErrLogged(err) {
 		return
 	}
-	source := callerSource()
-	fields := logrus.Fields{
-		"source": source,
-		"cause":  err.Error(),
-	}
-	if e, ok := err.(*Error); ok {
-		fields["stack"] = strings.Join(e.Trace(), " ")
-	}
-	for _, log := range log.loggers {
+	fields := errorFields(context.Background(), err, callerSource())
+	forEachLogger(func(log *logrus.Logger) {
 		log.WithFields(fields).Fatalf(msg, data...)
-	}
+	})
 }
 
 // returns false if error is not supposed to be logged.
@@ -17,10 +17,22 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 )
@@ -29,7 +41,7 @@ import (
 func TestCallerSource(t *testing.T) {
 	currentSource := func() string { return callerSource() }
 	gotSource := currentSource()
-	expectedSource := "[logger_test.go:31:TestCallerSource()]"
+	expectedSource := "[logger_test.go:43:TestCallerSource()]"
 	if gotSource != expectedSource {
 		t.Errorf("expected : %s, got : %s", expectedSource, gotSource)
 	}
@@ -62,3 +74,374 @@ func TestLogger(t *testing.T) {
 		t.Fatal("Cause field has unexpected message", msg)
 	}
 }
+
+// Tests error logger for requests.
+func TestLoggerRequestInfo(t *testing.T) {
+	var buffer bytes.Buffer
+	var fields logrus.Fields
+	testLog := logrus.New()
+	testLog.Out = &buffer
+	testLog.Formatter = new(logrus.JSONFormatter)
+	log.mu.Lock()
+	savedLoggers := log.loggers
+	log.loggers = []*logrus.Logger{testLog}
+	log.mu.Unlock()
+	defer func() {
+		log.mu.Lock()
+		log.loggers = savedLoggers
+		log.mu.Unlock()
+	}()
+
+	ctx := contextWithReqInfo(context.Background(), &reqInfo{
+		RequestID:  "14D1F3C0A3B2E5A7",
+		RemoteAddr: "127.0.0.1:12345",
+		API:        "REST.GET.OBJECT",
+		Bucket:     "bucket",
+		Object:     "object",
+	})
+	errorIfCtx(ctx, errors.New("Fake error"), "Failed with error.")
+	if err := json.Unmarshal(buffer.Bytes(), &fields); err != nil {
+		t.Fatal(err)
+	}
+	expectedFields := map[string]string{
+		"requestID":  "14D1F3C0A3B2E5A7",
+		"remoteAddr": "127.0.0.1:12345",
+		"api":        "REST.GET.OBJECT",
+		"bucket":     "bucket",
+		"object":     "object",
+		"cause":      "Fake error",
+	}
+	for k, v := range expectedFields {
+		if fields[k] != v {
+			t.Errorf("Expected %s to be %s, got %v", k, v, fields[k])
+		}
+	}
+}
+
+// Tests log formats of the loggers.
+func TestNewLogFormatter(t *testing.T) {
+	testCases := []struct {
+		format        string
+		defaultFormat string
+		expectedJSON  bool
+		expectedErr   bool
+	}{
+		// Test case - 1.
+		// Default format.
+		{"", logFormatText, false, false},
+		// Test case - 2.
+		// Default JSON format.
+		{"", logFormatJSON, true, false},
+		// Test case - 3.
+		// JSON format.
+		{"JSON", logFormatText, true, false},
+		// Test case - 4.
+		// Text format.
+		{"text", logFormatJSON, false, false},
+		// Test case - 5.
+		// Unknown format.
+		{"xml", logFormatText, false, true},
+	}
+	for i, testCase := range testCases {
+		formatter, err := newLogFormatter(testCase.format, testCase.defaultFormat)
+		if (err != nil) != testCase.expectedErr {
+			t.Fatalf("Test %d: Unexpected error %v", i+1, err)
+		}
+		if err != nil {
+			continue
+		}
+		if _, isJSON := formatter.(*logrus.JSONFormatter); isJSON != testCase.expectedJSON {
+			t.Errorf("Test %d: Expected JSON %v, got %T", i+1, testCase.expectedJSON, formatter)
+		}
+	}
+}
+
+// Tests that loggers are replaced only if all loggers are set up.
+func TestLoadLoggers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minio-logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+
+	log.mu.Lock()
+	savedLoggers, savedClosers := log.loggers, log.closers
+	log.loggers, log.closers = nil, nil
+	log.mu.Unlock()
+	defer func() {
+		log.mu.Lock()
+		for _, closer := range log.closers {
+			closer.Close()
+		}
+		log.loggers, log.closers = savedLoggers, savedClosers
+		log.mu.Unlock()
+	}()
+
+	fileCfg := loggerFile{Enable: true, Filename: filepath.Join(dir, "minio.log"), Level: "error"}
+	testCases := []struct {
+		cfg             loggerConfig
+		expectedErr     bool
+		expectedLoggers int
+	}{
+		// Test case - 1.
+		// Console and file loggers.
+		{loggerConfig{Console: loggerConsole{Enable: true, Level: "error"}, File: fileCfg}, false, 2},
+		// Test case - 2.
+		// Invalid console level, the loggers are kept.
+		{loggerConfig{Console: loggerConsole{Enable: true, Level: "loud"}, File: fileCfg}, true, 2},
+		// Test case - 3.
+		// Invalid rotation of the file logger.
+		{loggerConfig{File: loggerFile{Enable: true, Filename: fileCfg.Filename, Level: "error", MaxSize: -1}}, true, 2},
+		// Test case - 4.
+		// Invalid syslog network.
+		{loggerConfig{Syslog: loggerSyslog{Enable: true, Network: "ftp", Level: "error"}}, true, 2},
+		// Test case - 5.
+		// File logger only.
+		{loggerConfig{File: fileCfg}, false, 1},
+		// Test case - 6.
+		// No loggers.
+		{loggerConfig{}, false, 0},
+	}
+	for i, testCase := range testCases {
+		err := loadLoggers(testCase.cfg)
+		if (err != nil) != testCase.expectedErr {
+			t.Fatalf("Test %d: Unexpected error %v", i+1, err)
+		}
+		if loggers := getLoggers(); len(loggers) != testCase.expectedLoggers {
+			t.Errorf("Test %d: Expected %d loggers, got %d", i+1, testCase.expectedLoggers, len(loggers))
+		}
+	}
+}
+
+// slowHook - a logger hook taking a while to fire, records entries
+// fired after it was closed.
+type slowHook struct {
+	firing  chan struct{}
+	mu      sync.Mutex
+	closed  bool
+	lateErr error
+}
+
+func (h *slowHook) Fire(entry *logrus.Entry) error {
+	h.firing <- struct{}{}
+	time.Sleep(50 * time.Millisecond)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		h.lateErr = errLoggerClosed
+	}
+	return nil
+}
+
+func (h *slowHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *slowHook) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	return nil
+}
+
+// Tests that replaced loggers are closed only once the entries being
+// logged are written.
+func TestLoadLoggersWaitsForEntries(t *testing.T) {
+	hook := &slowHook{firing: make(chan struct{}, 1)}
+	testLog := logrus.New()
+	testLog.Out = ioutil.Discard
+	testLog.Hooks.Add(hook)
+
+	log.mu.Lock()
+	savedLoggers, savedClosers := log.loggers, log.closers
+	log.loggers, log.closers = []*logrus.Logger{testLog}, []io.Closer{hook}
+	log.mu.Unlock()
+	defer func() {
+		log.mu.Lock()
+		log.loggers, log.closers = savedLoggers, savedClosers
+		log.mu.Unlock()
+	}()
+
+	doneCh := make(chan struct{})
+	go func() {
+		errorIf(errors.New("Fake error"), "Failed with error.")
+		close(doneCh)
+	}()
+	<-hook.firing
+
+	if err := loadLoggers(loggerConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	<-doneCh
+	if !hook.closed {
+		t.Fatal("Expected the replaced logger to be closed")
+	}
+	if hook.lateErr != nil {
+		t.Errorf("Expected the entry to be written before the logger was closed, got %v", hook.lateErr)
+	}
+}
+
+// Tests that log files are rotated and rotated files are removed and
+// compressed.
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minio-logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeAll(dir)
+
+	filename := filepath.Join(dir, "minio.log")
+
+	// A file rotated long ago is removed.
+	expired := filename + "." + time.Now().UTC().Add(-48*time.Hour).Format(rotatedLogTimeFormat)
+	if err = ioutil.WriteFile(expired, []byte("expired\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := newRotatingFile(filename, 10, 24*time.Hour, 2, true)
+	if err != nil {
+		t.Fatalf("Unable to open log file. %s", err)
+	}
+	for i := 0; i < 4; i++ {
+		// Rotated files are named after the millisecond they were
+		// rotated in.
+		time.Sleep(2 * time.Millisecond)
+		if _, err = f.Write([]byte(fmt.Sprintf("line %d\n", i))); err != nil {
+			t.Fatalf("Unable to write line %d. %s", i, err)
+		}
+	}
+	if err = f.Close(); err != nil {
+		t.Fatalf("Unable to close log file. %s", err)
+	}
+	if _, err = f.Write([]byte("closed\n")); err != errLoggerClosed {
+		t.Errorf("Expected %s writing to a closed file, got %v", errLoggerClosed, err)
+	}
+	// Clean up the files rotated after the last clean up.
+	if err = f.millRotated(); err != nil {
+		t.Fatalf("Unable to clean up rotated files. %s", err)
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "line 3\n" {
+		t.Errorf("Expected the last line in the log file, found %q", string(data))
+	}
+	rotated, err := f.rotatedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated) != 2 {
+		t.Fatalf("Expected 2 rotated files, found %v", rotated)
+	}
+	for i, name := range rotated {
+		if !strings.HasSuffix(name, compressedLogSuffix) {
+			t.Fatalf("Expected %s to be compressed", name)
+		}
+		file, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err = ioutil.ReadAll(gz)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := fmt.Sprintf("line %d\n", i+1); string(data) != expected {
+			t.Errorf("Expected %q in %s, found %q", expected, name, string(data))
+		}
+	}
+}
+
+// Tests that log entries are sent to syslog as RFC5424 messages.
+func TestSyslogLogger(t *testing.T) {
+	if _, _, err := newSyslogLogger(loggerSyslog{Enable: true, Network: "udp", Address: "127.0.0.1:514", Level: "error", Facility: "local9"}); err == nil {
+		t.Fatal("Expected an unknown facility to fail")
+	}
+
+	udpConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer udpConn.Close()
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tcpListener.Close()
+
+	hostname, _ := os.Hostname()
+	// Returns the message of a syslog message after checking its header.
+	checkHeader := func(msg string) string {
+		parts := strings.SplitN(msg, " ", 8)
+		if len(parts) != 8 {
+			t.Fatalf("Unexpected message %q", msg)
+		}
+		// local0.err
+		if parts[0] != "<131>1" {
+			t.Errorf("Expected priority and version <131>1, got %s", parts[0])
+		}
+		if _, err := time.Parse(time.RFC3339Nano, parts[1]); err != nil {
+			t.Errorf("Unable to parse time stamp %s. %s", parts[1], err)
+		}
+		expected := []string{hostname, "minio", fmt.Sprint(os.Getpid()), "-", "-"}
+		if strings.Join(parts[2:7], " ") != strings.Join(expected, " ") {
+			t.Errorf("Expected header %v, got %v", expected, parts[2:7])
+		}
+		return parts[7]
+	}
+
+	// UDP messages are sent one per datagram.
+	udpLogger, udpWriter, err := newSyslogLogger(loggerSyslog{Enable: true, Network: "udp", Address: udpConn.LocalAddr().String(), Level: "error", Facility: "local0", Format: logFormatJSON})
+	if err != nil {
+		t.Fatalf("Unable to set up the syslog logger. %s", err)
+	}
+	defer udpWriter.Close()
+	udpLogger.WithField("cause", "Fake error").Error("Failed with error.")
+
+	buf := make([]byte, 4096)
+	udpConn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := udpConn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Unable to receive syslog message. %s", err)
+	}
+	var fields logrus.Fields
+	if err = json.Unmarshal([]byte(checkHeader(string(buf[:n]))), &fields); err != nil {
+		t.Fatalf("Unable to unmarshal message. %s", err)
+	}
+	if fields["cause"] != "Fake error" || fields["msg"] != "Failed with error." {
+		t.Errorf("Unexpected message fields %v", fields)
+	}
+
+	// TCP messages are framed by their length.
+	tcpLogger, tcpWriter, err := newSyslogLogger(loggerSyslog{Enable: true, Network: "tcp", Address: tcpListener.Addr().String(), Level: "error", Facility: "local0"})
+	if err != nil {
+		t.Fatalf("Unable to set up the syslog logger. %s", err)
+	}
+	defer tcpWriter.Close()
+	conn, err := tcpListener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	tcpLogger.Error("Failed with error.")
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(conn)
+	var length int
+	if _, err = fmt.Fscanf(reader, "%d ", &length); err != nil {
+		t.Fatalf("Unable to read message length. %s", err)
+	}
+	framed := make([]byte, length)
+	if _, err = io.ReadFull(reader, framed); err != nil {
+		t.Fatalf("Unable to read message. %s", err)
+	}
+	if msg := checkHeader(string(framed)); !strings.Contains(msg, "Failed with error.") {
+		t.Errorf("Unexpected message %q", msg)
+	}
+}
@@ -80,10 +80,9 @@ func migrate() {
 }
 
 func enableLoggers() {
-	// Enable all loggers here.
-	enableConsoleLogger()
-	enableFileLogger()
-	// Add your logger here.
+	if err := loadLoggers(serverConfig.GetLogger()); err != nil {
+		console.Fatalf("Unable to initialize minio loggers. Err: %s.\n", err)
+	}
 }
 
 func findClosestCommands(command string) []string {
@@ -0,0 +1,76 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+
+	router "github.com/gorilla/mux"
+)
+
+const (
+	// Path of the Prometheus metrics below the reserved bucket.
+	prometheusMetricsPath = "/prometheus/metrics"
+
+	// Content type of the Prometheus text exposition format.
+	prometheusContentType = "text/plain; version=0.0.4"
+)
+
+// registerMetricsRouter - Add handler functions for the metrics routes.
+func registerMetricsRouter(mux *router.Router) {
+	// Metrics router
+	metricsRouter := mux.NewRoute().PathPrefix(reservedBucket).Subrouter()
+
+	// Prometheus metrics
+	metricsRouter.Methods("GET").Path(prometheusMetricsPath).HandlerFunc(metricsHandler)
+}
+
+// Checks if a request may read the metrics, unless metrics are public
+// the request carries a JWT issued to the server credentials.
+func isMetricsRequestAllowed(r *http.Request) bool {
+	if globalIsPrometheusPublic {
+		return true
+	}
+	accessKey, err := webRequestSubject(r)
+	if err != nil {
+		return false
+	}
+	return accessKey == serverConfig.GetCredential().AccessKey
+}
+
+// metricsHandler - GET /minio/prometheus/metrics
+// ----------
+// Returns the metrics of this node in the Prometheus text exposition
+// format.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if !isMetricsRequestAllowed(r) {
+		writeErrorResponse(w, ErrAccessDenied, r.URL)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := writeMetrics(&buf, newObjectLayerFn()); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to write metrics.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	w.Header().Set("Content-Type", prometheusContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,408 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Server metrics are collected in memory and exported in the Prometheus
+// text exposition format, every node exports the metrics of the
+// requests it served and of its view of the storage.
+
+// Upper bounds of the request duration histogram buckets, in seconds.
+var httpRequestDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// httpAPIStatus - S3 API and response status requests are counted by.
+type httpAPIStatus struct {
+	api    string
+	status int
+}
+
+// byAPIStatus - sorts by S3 API, then by response status.
+type byAPIStatus []httpAPIStatus
+
+func (s byAPIStatus) Len() int      { return len(s) }
+func (s byAPIStatus) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byAPIStatus) Less(i, j int) bool {
+	if s[i].api != s[j].api {
+		return s[i].api < s[j].api
+	}
+	return s[i].status < s[j].status
+}
+
+// httpAPIStats - count and duration histogram of requests.
+type httpAPIStats struct {
+	count uint64
+	// Cumulative counts of the histogram buckets.
+	buckets     []uint64
+	durationSum float64
+}
+
+// httpMetrics - counts of the requests served per S3 API and response
+// status and the bytes received and sent.
+type httpMetrics struct {
+	mutex *sync.Mutex
+
+	apis map[httpAPIStatus]*httpAPIStats
+
+	bytesReceived uint64
+	bytesSent     uint64
+}
+
+// Variable represents the metrics of the requests served.
+var globalHTTPMetrics = newHTTPMetrics()
+
+func newHTTPMetrics() *httpMetrics {
+	return &httpMetrics{
+		mutex: &sync.Mutex{},
+		apis:  make(map[httpAPIStatus]*httpAPIStats),
+	}
+}
+
+// Record accounts a served request.
+func (m *httpMetrics) Record(api string, status int, duration time.Duration, bytesReceived, bytesSent int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := httpAPIStatus{api, status}
+	stats, ok := m.apis[key]
+	if !ok {
+		stats = &httpAPIStats{buckets: make([]uint64, len(httpRequestDurationBuckets))}
+		m.apis[key] = stats
+	}
+	seconds := duration.Seconds()
+	stats.count++
+	stats.durationSum += seconds
+	for i, le := range httpRequestDurationBuckets {
+		if seconds <= le {
+			stats.buckets[i]++
+		}
+	}
+	m.bytesReceived += uint64(bytesReceived)
+	m.bytesSent += uint64(bytesSent)
+}
+
+// rpcMetrics - counts of the network errors of RPC calls per peer.
+type rpcMetrics struct {
+	mutex  *sync.Mutex
+	errors map[string]uint64
+}
+
+// Variable represents the network errors of RPC calls.
+var globalRPCMetrics = &rpcMetrics{
+	mutex:  &sync.Mutex{},
+	errors: make(map[string]uint64),
+}
+
+// RecordError accounts a network error of an RPC call to a peer.
+func (m *rpcMetrics) RecordError(peer string) {
+	m.mutex.Lock()
+	m.errors[peer]++
+	m.mutex.Unlock()
+}
+
+// healMetrics - counts of the buckets and objects healed.
+type healMetrics struct {
+	buckets  uint64
+	objects  uint64
+	failures uint64
+}
+
+// Variable represents the counts of heal operations.
+var globalHealMetrics = &healMetrics{}
+
+// RecordBucket accounts the heal of a bucket.
+func (m *healMetrics) RecordBucket(err error) {
+	if err != nil {
+		atomic.AddUint64(&m.failures, 1)
+		return
+	}
+	atomic.AddUint64(&m.buckets, 1)
+}
+
+// RecordObject accounts the heal of an object.
+func (m *healMetrics) RecordObject(err error) {
+	if err != nil {
+		atomic.AddUint64(&m.failures, 1)
+		return
+	}
+	atomic.AddUint64(&m.objects, 1)
+}
+
+// metricsResponseWriter - records the status and the size of a response.
+type metricsResponseWriter struct {
+	http.ResponseWriter
+
+	status    int
+	bytesSent int64
+}
+
+func (mw *metricsResponseWriter) WriteHeader(status int) {
+	if mw.status == 0 {
+		mw.status = status
+	}
+	mw.ResponseWriter.WriteHeader(status)
+}
+
+func (mw *metricsResponseWriter) Write(p []byte) (int, error) {
+	if mw.status == 0 {
+		mw.status = http.StatusOK
+	}
+	n, err := mw.ResponseWriter.Write(p)
+	mw.bytesSent += int64(n)
+	return n, err
+}
+
+// Flush - handlers flush responses while writing them.
+func (mw *metricsResponseWriter) Flush() {
+	if f, ok := mw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// metricsReadCloser - counts the bytes read of a request body.
+type metricsReadCloser struct {
+	io.ReadCloser
+
+	bytesReceived int64
+}
+
+func (mr *metricsReadCloser) Read(p []byte) (int, error) {
+	n, err := mr.ReadCloser.Read(p)
+	mr.bytesReceived += int64(n)
+	return n, err
+}
+
+// httpMetricsHandler - records the metrics of S3 requests.
+type httpMetricsHandler struct {
+	handler http.Handler
+}
+
+func setHTTPMetricsHandler(h http.Handler) http.Handler {
+	return httpMetricsHandler{handler: h}
+}
+
+func (h httpMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Browser, RPC and admin requests are not S3 requests.
+	if strings.HasPrefix(r.URL.Path, reservedBucket+"/") || r.Header.Get(minioAdminOpHeader) != "" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	_, object := urlPath2BucketObjectName(r.URL)
+	api := getAccessLogOperation(r, object)
+
+	startTime := time.Now().UTC()
+	mw := &metricsResponseWriter{ResponseWriter: w}
+	var mr *metricsReadCloser
+	if r.Body != nil {
+		mr = &metricsReadCloser{ReadCloser: r.Body}
+		r.Body = mr
+	}
+	h.handler.ServeHTTP(mw, r)
+
+	status := mw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	var bytesReceived int64
+	if mr != nil {
+		bytesReceived = mr.bytesReceived
+	}
+	globalHTTPMetrics.Record(api, status, time.Now().UTC().Sub(startTime), bytesReceived, mw.bytesSent)
+}
+
+// Escapes label values of the text exposition format.
+var metricsLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metricsWriter - writes metrics in the Prometheus text exposition
+// format, the first error is kept and stops further writes.
+type metricsWriter struct {
+	w   io.Writer
+	err error
+}
+
+// Describes the metric the following samples belong to.
+func (mw *metricsWriter) describe(name, metricType, help string) {
+	mw.printf("# HELP minio_%s %s\n# TYPE minio_%s %s\n", name, help, name, metricType)
+}
+
+// Writes a sample, labels are pairs of label names and values.
+func (mw *metricsWriter) sample(name string, value float64, labels ...string) {
+	var pairs []string
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, labels[i]+`="`+metricsLabelReplacer.Replace(labels[i+1])+`"`)
+	}
+	labelStr := ""
+	if len(pairs) > 0 {
+		labelStr = "{" + strings.Join(pairs, ",") + "}"
+	}
+	mw.printf("minio_%s%s %s\n", name, labelStr, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func (mw *metricsWriter) printf(format string, a ...interface{}) {
+	if mw.err != nil {
+		return
+	}
+	_, mw.err = fmt.Fprintf(mw.w, format, a...)
+}
+
+// Writes the request metrics.
+func (m *httpMetrics) writeTo(mw *metricsWriter) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	keys := make([]httpAPIStatus, 0, len(m.apis))
+	for key := range m.apis {
+		keys = append(keys, key)
+	}
+	sort.Sort(byAPIStatus(keys))
+
+	mw.describe("http_requests_total", "counter", "Total number of S3 requests served per API and response status.")
+	for _, key := range keys {
+		mw.sample("http_requests_total", float64(m.apis[key].count), "api", key.api, "status", strconv.Itoa(key.status))
+	}
+
+	mw.describe("http_request_duration_seconds", "histogram", "Time taken to serve S3 requests per API and response status.")
+	for _, key := range keys {
+		stats := m.apis[key]
+		status := strconv.Itoa(key.status)
+		for i, le := range httpRequestDurationBuckets {
+			mw.sample("http_request_duration_seconds_bucket", float64(stats.buckets[i]),
+				"api", key.api, "status", status, "le", strconv.FormatFloat(le, 'g', -1, 64))
+		}
+		mw.sample("http_request_duration_seconds_bucket", float64(stats.count), "api", key.api, "status", status, "le", "+Inf")
+		mw.sample("http_request_duration_seconds_sum", stats.durationSum, "api", key.api, "status", status)
+		mw.sample("http_request_duration_seconds_count", float64(stats.count), "api", key.api, "status", status)
+	}
+
+	mw.describe("http_received_bytes_total", "counter", "Total number of bytes received in S3 request bodies.")
+	mw.sample("http_received_bytes_total", float64(m.bytesReceived))
+	mw.describe("http_sent_bytes_total", "counter", "Total number of bytes sent in S3 response bodies.")
+	mw.sample("http_sent_bytes_total", float64(m.bytesSent))
+}
+
+// Writes the RPC error metrics.
+func (m *rpcMetrics) writeTo(mw *metricsWriter) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	peers := make([]string, 0, len(m.errors))
+	for peer := range m.errors {
+		peers = append(peers, peer)
+	}
+	sort.Strings(peers)
+
+	mw.describe("rpc_errors_total", "counter", "Total number of network errors of RPC calls per peer.")
+	for _, peer := range peers {
+		mw.sample("rpc_errors_total", float64(m.errors[peer]), "peer", peer)
+	}
+}
+
+// Writes the heal metrics.
+func (m *healMetrics) writeTo(mw *metricsWriter) {
+	mw.describe("heal_buckets_total", "counter", "Total number of buckets healed.")
+	mw.sample("heal_buckets_total", float64(atomic.LoadUint64(&m.buckets)))
+	mw.describe("heal_objects_total", "counter", "Total number of objects healed.")
+	mw.sample("heal_objects_total", float64(atomic.LoadUint64(&m.objects)))
+	mw.describe("heal_failures_total", "counter", "Total number of failed bucket and object heals.")
+	mw.sample("heal_failures_total", float64(atomic.LoadUint64(&m.failures)))
+}
+
+// Writes the namespace lock metrics of this node.
+func writeLockMetrics(mw *metricsWriter) {
+	globalNSMutex.lockMapMutex.Lock()
+	counters := *globalNSMutex.counters
+	globalNSMutex.lockMapMutex.Unlock()
+
+	mw.describe("locks_total", "gauge", "Number of namespace locks held or waited for.")
+	mw.sample("locks_total", float64(counters.total))
+	mw.describe("locks_blocked", "gauge", "Number of namespace locks waited for.")
+	mw.sample("locks_blocked", float64(counters.blocked))
+	mw.describe("locks_granted", "gauge", "Number of namespace locks held.")
+	mw.sample("locks_granted", float64(counters.granted))
+}
+
+// Writes the storage and object cache metrics of an object layer.
+func writeStorageMetrics(mw *metricsWriter, objAPI ObjectLayer) {
+	xl, ok := objAPI.(*xlObjects)
+	if !ok {
+		storageInfo := objAPI.StorageInfo()
+		mw.describe("disk_storage_total_bytes", "gauge", "Total space of a disk.")
+		mw.sample("disk_storage_total_bytes", float64(storageInfo.Total), "disk", "fs")
+		mw.describe("disk_storage_free_bytes", "gauge", "Free space of a disk.")
+		mw.sample("disk_storage_free_bytes", float64(storageInfo.Free), "disk", "fs")
+		return
+	}
+
+	disksInfo, onlineDisks, offlineDisks := getDisksInfo(xl.storageDisks)
+	mw.describe("disks_online", "gauge", "Number of online disks.")
+	mw.sample("disks_online", float64(onlineDisks))
+	mw.describe("disks_offline", "gauge", "Number of offline disks.")
+	mw.sample("disks_offline", float64(offlineDisks))
+
+	mw.describe("disk_storage_total_bytes", "gauge", "Total space of a disk.")
+	for i, storageDisk := range xl.storageDisks {
+		if storageDisk != nil {
+			mw.sample("disk_storage_total_bytes", float64(disksInfo[i].Total), "disk", storageDisk.String())
+		}
+	}
+	mw.describe("disk_storage_free_bytes", "gauge", "Free space of a disk.")
+	for i, storageDisk := range xl.storageDisks {
+		if storageDisk != nil {
+			mw.sample("disk_storage_free_bytes", float64(disksInfo[i].Free), "disk", storageDisk.String())
+		}
+	}
+
+	if !xl.objCacheEnabled {
+		return
+	}
+	stats := xl.objCache.Stats()
+	mw.describe("cache_hits_total", "counter", "Total number of object cache hits.")
+	mw.sample("cache_hits_total", float64(stats.Hits))
+	mw.describe("cache_misses_total", "counter", "Total number of object cache misses.")
+	mw.sample("cache_misses_total", float64(stats.Misses))
+	mw.describe("cache_entries", "gauge", "Number of objects in the object cache.")
+	mw.sample("cache_entries", float64(stats.Entries))
+	mw.describe("cache_size_bytes", "gauge", "Size of the objects in the object cache.")
+	mw.sample("cache_size_bytes", float64(stats.Size))
+	mw.describe("cache_max_size_bytes", "gauge", "Maximum size of the object cache.")
+	mw.sample("cache_max_size_bytes", float64(stats.MaxSize))
+}
+
+// writeMetrics - writes all metrics of this node, storage metrics are
+// left out until the object layer is initialized.
+func writeMetrics(w io.Writer, objAPI ObjectLayer) error {
+	mw := &metricsWriter{w: w}
+	globalHTTPMetrics.writeTo(mw)
+	if objAPI != nil {
+		writeStorageMetrics(mw, objAPI)
+	}
+	writeLockMetrics(mw)
+	globalRPCMetrics.writeTo(mw)
+	globalHealMetrics.writeTo(mw)
+	return mw.err
+}
@@ -0,0 +1,191 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+// Tests the request metrics recorded by the metrics handler.
+func TestHTTPMetricsHandler(t *testing.T) {
+	savedMetrics := globalHTTPMetrics
+	defer func() { globalHTTPMetrics = savedMetrics }()
+	globalHTTPMetrics = newHTTPMetrics()
+
+	handler := setHTTPMetricsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		if r.Method == httpPUT {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	}))
+
+	testCases := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		// Test case - 1.
+		// Object upload.
+		{"PUT", "/bucket/object", "hello"},
+		// Test case - 2.
+		// Missing object.
+		{"GET", "/bucket/missing", ""},
+		// Test case - 3.
+		// Browser requests are not recorded.
+		{"GET", reservedBucket + "/index.html", ""},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest(testCase.method, testCase.path, int64(len(testCase.body)), bytes.NewReader([]byte(testCase.body)))
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	var buf bytes.Buffer
+	mw := &metricsWriter{w: &buf}
+	globalHTTPMetrics.writeTo(mw)
+	if mw.err != nil {
+		t.Fatal(mw.err)
+	}
+	for _, line := range []string{
+		`minio_http_requests_total{api="REST.GET.OBJECT",status="404"} 1`,
+		`minio_http_requests_total{api="REST.PUT.OBJECT",status="200"} 1`,
+		`minio_http_request_duration_seconds_bucket{api="REST.PUT.OBJECT",status="200",le="+Inf"} 1`,
+		`minio_http_request_duration_seconds_count{api="REST.GET.OBJECT",status="404"} 1`,
+		`minio_http_received_bytes_total 5`,
+		`minio_http_sent_bytes_total 9`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("Expected %q in the metrics:\n%s", line, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "REST.GET.BUCKET") {
+		t.Errorf("Unexpected browser request in the metrics:\n%s", buf.String())
+	}
+}
+
+// Tests the histogram buckets requests are counted in.
+func TestHTTPMetricsRecord(t *testing.T) {
+	m := newHTTPMetrics()
+	m.Record("REST.GET.OBJECT", http.StatusOK, 20*time.Millisecond, 0, 0)
+	m.Record("REST.GET.OBJECT", http.StatusOK, 2*time.Second, 0, 0)
+
+	stats := m.apis[httpAPIStatus{"REST.GET.OBJECT", http.StatusOK}]
+	for i, le := range httpRequestDurationBuckets {
+		expected := uint64(0)
+		if le >= 0.02 {
+			expected++
+		}
+		if le >= 2 {
+			expected++
+		}
+		if stats.buckets[i] != expected {
+			t.Errorf("Expected %d requests in bucket %v, got %d", expected, le, stats.buckets[i])
+		}
+	}
+	if stats.count != 2 {
+		t.Errorf("Expected 2 requests, got %d", stats.count)
+	}
+}
+
+// Tests access to the metrics endpoint.
+func TestMetricsHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	_, xlDirs, err := initTestXLObjLayer()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for metrics handler tests.")
+	}
+	defer removeRoots(xlDirs)
+	defer resetGlobalObjectAPI()
+	defer func() { globalIsPrometheusPublic = false }()
+	initNSLock(false)
+
+	mux := router.NewRouter()
+	registerMetricsRouter(mux)
+
+	token, err := newAuthToken(serverConfig.GetCredential().AccessKey, jwtAudienceWeb, defaultJWTExpiry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherToken, err := newAuthToken("otheruser", jwtAudienceWeb, defaultJWTExpiry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		public             bool
+		token              string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Token of the server credentials.
+		{false, token, http.StatusOK},
+		// Test case - 2.
+		// No token.
+		{false, "", http.StatusForbidden},
+		// Test case - 3.
+		// Token of another user.
+		{false, otherToken, http.StatusForbidden},
+		// Test case - 4.
+		// Public metrics.
+		{true, "", http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		globalIsPrometheusPublic = testCase.public
+		req, err := newTestRequest("GET", reservedBucket+prometheusMetricsPath, 0, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request: <ERROR> %v", i+1, err)
+		}
+		if testCase.token != "" {
+			req.Header.Set("Authorization", jwtAlgorithm+" "+testCase.token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: Expected the response status to be `%d`, but instead found `%d`", i+1, testCase.expectedRespStatus, rec.Code)
+			continue
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+		if rec.Header().Get("Content-Type") != prometheusContentType {
+			t.Errorf("Test %d: Unexpected content type %s", i+1, rec.Header().Get("Content-Type"))
+		}
+		for _, line := range []string{"minio_disks_offline 0\n", "minio_locks_total ", "minio_heal_objects_total "} {
+			if !strings.Contains(rec.Body.String(), line) {
+				t.Errorf("Test %d: Expected %q in the metrics:\n%s", i+1, line, rec.Body.String())
+			}
+		}
+	}
+}
@@ -139,10 +139,16 @@ func (rpcClient *RPCClient) Call(serviceMethod string, args interface{}, reply i
 	// Get a new or existing rpc.Client.
 	netRPCClient, err := rpcClient.dial()
 	if err != nil {
+		globalRPCMetrics.RecordError(rpcClient.serverAddr)
 		return err
 	}
 
-	return netRPCClient.Call(serviceMethod, args, reply)
+	err = netRPCClient.Call(serviceMethod, args, reply)
+	// Errors returned by the remote service are not network errors.
+	if _, ok := err.(rpc.ServerError); err != nil && !ok {
+		globalRPCMetrics.RecordError(rpcClient.serverAddr)
+	}
+	return err
 }
 
 // Close closes underlying rpc.Client.
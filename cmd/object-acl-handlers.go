@@ -0,0 +1,167 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// An access control policy of a canned ACL fits in 64KiB.
+const maxACLSize = 64 * 1024
+
+// Reads the canned ACL sent with PutObjectAcl and PutBucketAcl, either
+// with the `x-amz-acl` header or as an access control policy whose
+// grants amount to a canned ACL.
+func readACL(r *http.Request) (string, APIErrorCode) {
+	acl, s3Error := getACLFromHeader(r.Header)
+	if s3Error != ErrNone || acl != "" {
+		return acl, s3Error
+	}
+
+	// If Content-Length is unknown or zero, deny the request.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		return "", ErrMissingContentLength
+	}
+	if r.ContentLength > maxACLSize {
+		return "", ErrEntityTooLarge
+	}
+
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to read incoming body.")
+		return "", toAPIErrorCode(err)
+	}
+
+	acp := accessControlPolicy{}
+	if err := xml.Unmarshal(buffer.Bytes(), &acp); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to parse access control policy XML.")
+		return "", ErrMalformedACLError
+	}
+	acl, ok := getCannedACL(acp)
+	if !ok {
+		return "", ErrUnsupportedACL
+	}
+	return acl, ErrNone
+}
+
+// PutObjectACLHandler - PUT Object ACL
+// -----------------
+// This implementation of the PUT operation uses the acl subresource
+// to set the canned ACL of an object version.
+func (api objectAPIHandlers) PutObjectACLHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObjectAcl", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	acl, s3Error := readACL(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Private objects carry no ACL.
+	if acl == cannedACLPrivate {
+		acl = ""
+	}
+
+	// Lock the object before updating its ACL.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.UpdateObjectMetadata(r.Context(), bucket, object, versionID, map[string]string{
+		objectACLMetaKey: acl,
+	})
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to update object ACL.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	setVersionHeaders(w, objInfo)
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetObjectACLHandler - GET Object ACL
+// -----------------
+// This implementation of the GET operation uses the acl subresource
+// to return the grants of the canned ACL of an object version.
+func (api objectAPIHandlers) GetObjectACLHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObjectAcl", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Lock the object before reading its ACL.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.GetObjectVersionInfo(r.Context(), bucket, object, versionID)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Delete markers have no ACL.
+	if objInfo.DeleteMarker {
+		setVersionHeaders(w, objInfo)
+		writeErrorResponse(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	aclBytes, err := xml.Marshal(newAccessControlPolicy(getObjectACL(objInfo)))
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to marshal object ACL into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	setVersionHeaders(w, objInfo)
+	writeSuccessResponseXML(w, aclBytes)
+}
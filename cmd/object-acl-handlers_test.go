@@ -0,0 +1,170 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Wrapper for calling Put/GetObjectACL handler tests for both XL multiple disks and single node setup.
+func TestObjectACLHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testObjectACLHandlers, []string{
+		"PutObjectACL",
+		"GetObjectACL",
+		"HeadObject",
+		"GetObject",
+		"CopyObject",
+		"PutObject",
+	})
+}
+
+func testObjectACLHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Sends a request and returns the recorded response.
+	sendRequest := func(method, urlStr, body string, header http.Header) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(body)), bytes.NewReader([]byte(body)),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, urlStr, err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	// Sends an anonymous GET of an object and returns the response status.
+	anonGet := func(object string) int {
+		rec := httptest.NewRecorder()
+		req, err := newTestRequest("GET", getGetObjectURL("", bucketName, object), 0, nil)
+		if err != nil {
+			t.Fatalf("%s: Failed to create an anonymous request: <ERROR> %v", instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	// Returns the canned ACL of an object as seen by GetObjectAcl.
+	getACL := func(object string) string {
+		rec := sendRequest("GET", getObjectACLURL("", bucketName, object, ""), "", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+		}
+		acp := accessControlPolicy{}
+		if err := xml.Unmarshal(rec.Body.Bytes(), &acp); err != nil {
+			t.Fatalf("%s: Unable to parse response %s", instanceType, err)
+		}
+		acl, ok := getCannedACL(acp)
+		if !ok {
+			t.Fatalf("%s: Unexpected grants %v", instanceType, acp.AccessControlList)
+		}
+		return acl
+	}
+
+	// Objects are private by default.
+	if rec := sendRequest("PUT", getPutObjectURL("", bucketName, "private"), "hello", nil); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if acl := getACL("private"); acl != cannedACLPrivate {
+		t.Errorf("%s: Expected ACL %s, got %s", instanceType, cannedACLPrivate, acl)
+	}
+	if code := anonGet("private"); code != http.StatusForbidden {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, code)
+	}
+
+	// Object uploaded with a public ACL.
+	header := http.Header{}
+	header.Set(amzACLHeader, cannedACLPublicRead)
+	if rec := sendRequest("PUT", getPutObjectURL("", bucketName, "object"), "hello", header); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if acl := getACL("object"); acl != cannedACLPublicRead {
+		t.Errorf("%s: Expected ACL %s, got %s", instanceType, cannedACLPublicRead, acl)
+	}
+	if code := anonGet("object"); code != http.StatusOK {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+	rec := sendRequest("HEAD", getHeadObjectURL("", bucketName, "object"), "", nil)
+	if rec.Header().Get(objectACLMetaKey) != "" {
+		t.Errorf("%s: ACL leaked into the response headers", instanceType)
+	}
+	// Missing objects are not revealed to anonymous requests.
+	if code := anonGet("missing"); code != http.StatusForbidden {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, code)
+	}
+
+	// The ACL is never copied.
+	header = http.Header{}
+	header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/object"))
+	if rec = sendRequest("PUT", getCopyObjectURL("", bucketName, "copy"), "", header); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if acl := getACL("copy"); acl != cannedACLPrivate {
+		t.Errorf("%s: Expected ACL %s, got %s", instanceType, cannedACLPrivate, acl)
+	}
+
+	testCases := []struct {
+		objectName         string
+		acl                string
+		body               string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Canned ACL header.
+		{"object", cannedACLPrivate, "", http.StatusOK},
+		// Test case - 2.
+		// Grants of a canned ACL.
+		{"copy", "", `<AccessControlPolicy><AccessControlList><Grant><Grantee><URI>` + aclAllUsersGroup + `</URI></Grantee><Permission>READ</Permission></Grant></AccessControlList></AccessControlPolicy>`, http.StatusOK},
+		// Test case - 3.
+		// Unknown canned ACL.
+		{"object", "bucket-owner-read", "", http.StatusBadRequest},
+		// Test case - 4.
+		// Grants without a canned ACL.
+		{"object", "", `<AccessControlPolicy><AccessControlList><Grant><Grantee><ID>other</ID></Grantee><Permission>READ</Permission></Grant></AccessControlList></AccessControlPolicy>`, http.StatusNotImplemented},
+		// Test case - 5.
+		// Malformed grants.
+		{"object", "", `<AccessControlPolicy>`, http.StatusBadRequest},
+		// Test case - 6.
+		// Non-existent object.
+		{"missing", cannedACLPublicRead, "", http.StatusNotFound},
+	}
+	for i, testCase := range testCases {
+		header = http.Header{}
+		if testCase.acl != "" {
+			header.Set(amzACLHeader, testCase.acl)
+		}
+		rec = sendRequest("PUT", getObjectACLURL("", bucketName, testCase.objectName, ""), testCase.body, header)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+	if acl := getACL("object"); acl != cannedACLPrivate {
+		t.Errorf("%s: Expected ACL %s, got %s", instanceType, cannedACLPrivate, acl)
+	}
+	if code := anonGet("object"); code != http.StatusForbidden {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, code)
+	}
+	if code := anonGet("copy"); code != http.StatusOK {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+const (
+	// Reserved metadata entry carrying the canned ACL of an object,
+	// objects without it are private.
+	objectACLMetaKey = "X-Minio-Internal-Acl"
+
+	// Canned ACL header of PutObject, CopyObject, PutBucket and of the
+	// acl subresource.
+	amzACLHeader = "X-Amz-Acl"
+
+	// Supported canned ACLs.
+	cannedACLPrivate           = "private"
+	cannedACLPublicRead        = "public-read"
+	cannedACLPublicReadWrite   = "public-read-write"
+	cannedACLAuthenticatedRead = "authenticated-read"
+
+	// Grantee groups of the canned ACLs.
+	aclAllUsersGroup           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	aclAuthenticatedUsersGroup = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+
+	// Grant permissions used by the canned ACLs.
+	aclPermissionFullControl = "FULL_CONTROL"
+	aclPermissionRead        = "READ"
+	aclPermissionWrite       = "WRITE"
+)
+
+// Explicit grant headers, only canned ACLs are supported.
+var amzGrantHeaders = []string{
+	"X-Amz-Grant-Read",
+	"X-Amz-Grant-Write",
+	"X-Amz-Grant-Read-Acp",
+	"X-Amz-Grant-Write-Acp",
+	"X-Amz-Grant-Full-Control",
+}
+
+// aclGrantee - the grantee of a grant, either the owner or a group.
+type aclGrantee struct {
+	XMLNS       string `xml:"xmlns:xsi,attr"`
+	Type        string `xml:"xsi:type,attr"`
+	ID          string `xml:"ID,omitempty"`
+	DisplayName string `xml:"DisplayName,omitempty"`
+	URI         string `xml:"URI,omitempty"`
+}
+
+// aclGrant - a permission granted to a grantee.
+type aclGrant struct {
+	Grantee    aclGrantee `xml:"Grantee"`
+	Permission string     `xml:"Permission"`
+}
+
+// accessControlPolicy - represents the ACL of an object or a bucket as
+// returned by GetObjectAcl and GetBucketAcl and as sent to
+// PutObjectAcl and PutBucketAcl.
+type accessControlPolicy struct {
+	XMLName           xml.Name   `xml:"AccessControlPolicy"`
+	Owner             Owner      `xml:"Owner"`
+	AccessControlList []aclGrant `xml:"AccessControlList>Grant"`
+}
+
+// Checks if a canned ACL is supported.
+func isValidCannedACL(acl string) bool {
+	switch acl {
+	case cannedACLPrivate, cannedACLPublicRead, cannedACLPublicReadWrite, cannedACLAuthenticatedRead:
+		return true
+	}
+	return false
+}
+
+// Returns a grant of a permission to a group.
+func newGroupGrant(uri, permission string) aclGrant {
+	return aclGrant{
+		Grantee: aclGrantee{
+			XMLNS: "http://www.w3.org/2001/XMLSchema-instance",
+			Type:  "Group",
+			URI:   uri,
+		},
+		Permission: permission,
+	}
+}
+
+// newAccessControlPolicy - returns the grants of a canned ACL, the owner
+// always has full control.
+func newAccessControlPolicy(acl string) accessControlPolicy {
+	owner := Owner{
+		ID:          globalMinioDefaultOwnerID,
+		DisplayName: globalMinioDefaultOwnerID,
+	}
+	grants := []aclGrant{{
+		Grantee: aclGrantee{
+			XMLNS:       "http://www.w3.org/2001/XMLSchema-instance",
+			Type:        "CanonicalUser",
+			ID:          owner.ID,
+			DisplayName: owner.DisplayName,
+		},
+		Permission: aclPermissionFullControl,
+	}}
+	switch acl {
+	case cannedACLPublicRead:
+		grants = append(grants, newGroupGrant(aclAllUsersGroup, aclPermissionRead))
+	case cannedACLPublicReadWrite:
+		grants = append(grants, newGroupGrant(aclAllUsersGroup, aclPermissionRead),
+			newGroupGrant(aclAllUsersGroup, aclPermissionWrite))
+	case cannedACLAuthenticatedRead:
+		grants = append(grants, newGroupGrant(aclAuthenticatedUsersGroup, aclPermissionRead))
+	}
+	return accessControlPolicy{Owner: owner, AccessControlList: grants}
+}
+
+// getCannedACL - returns the canned ACL the grants of an access control
+// policy amount to. Grants to users other than full control, as given
+// to the owner by every canned ACL, have no canned ACL.
+func getCannedACL(acp accessControlPolicy) (string, bool) {
+	groups := make(map[string]map[string]bool)
+	for _, grant := range acp.AccessControlList {
+		if grant.Grantee.URI == "" {
+			if grant.Permission != aclPermissionFullControl {
+				return "", false
+			}
+			continue
+		}
+		if groups[grant.Grantee.URI] == nil {
+			groups[grant.Grantee.URI] = make(map[string]bool)
+		}
+		groups[grant.Grantee.URI][grant.Permission] = true
+	}
+
+	allUsers, authUsers := groups[aclAllUsersGroup], groups[aclAuthenticatedUsersGroup]
+	switch {
+	case len(groups) == 0:
+		return cannedACLPrivate, true
+	case len(groups) > 1:
+		return "", false
+	case len(allUsers) == 1 && allUsers[aclPermissionRead]:
+		return cannedACLPublicRead, true
+	case len(allUsers) == 2 && allUsers[aclPermissionRead] && allUsers[aclPermissionWrite]:
+		return cannedACLPublicReadWrite, true
+	case len(authUsers) == 1 && authUsers[aclPermissionRead]:
+		return cannedACLAuthenticatedRead, true
+	}
+	return "", false
+}
+
+// getACLFromHeader - returns the canned ACL of the `x-amz-acl` header,
+// empty if the header is not set.
+func getACLFromHeader(header http.Header) (string, APIErrorCode) {
+	for _, grantHeader := range amzGrantHeaders {
+		if _, ok := header[grantHeader]; ok {
+			return "", ErrUnsupportedACL
+		}
+	}
+	acl := header.Get(amzACLHeader)
+	if acl != "" && !isValidCannedACL(acl) {
+		return "", ErrInvalidCannedACL
+	}
+	return acl, ErrNone
+}
+
+// setObjectACLFromHeader - saves the canned ACL of the `x-amz-acl`
+// header into the metadata of an object about to be written, private
+// objects carry no ACL.
+func setObjectACLFromHeader(header http.Header, metadata map[string]string) APIErrorCode {
+	acl, s3Error := getACLFromHeader(header)
+	if s3Error != ErrNone {
+		return s3Error
+	}
+	if acl != "" && acl != cannedACLPrivate {
+		metadata[objectACLMetaKey] = acl
+	}
+	return ErrNone
+}
+
+// Returns the canned ACL of an object version.
+func getObjectACL(objInfo ObjectInfo) string {
+	if acl, ok := objInfo.UserDefined[objectACLMetaKey]; ok {
+		return acl
+	}
+	return cannedACLPrivate
+}
+
+// isObjectACLReadAllowed - verifies if an anonymous request, which the
+// bucket policy does not allow, may read an object version through its
+// ACL. A Deny of the bucket policy overrides the ACL.
+func isObjectACLReadAllowed(r *http.Request, bucket string, objInfo ObjectInfo) bool {
+	if getRequestAuthType(r) != authTypeAnonymous {
+		return false
+	}
+	if acl := getObjectACL(objInfo); acl != cannedACLPublicRead && acl != cannedACLPublicReadWrite {
+		return false
+	}
+	if policy := globalBucketPolicies.GetBucketPolicy(bucket); policy != nil {
+		return evalBucketPolicy(policy, bucket, "s3:GetObject", r.URL, r) != policyExplicitDeny
+	}
+	return true
+}
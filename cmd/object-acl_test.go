@@ -0,0 +1,104 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"testing"
+)
+
+// Tests the canned ACLs the grants of access control policies amount to.
+func TestGetCannedACL(t *testing.T) {
+	ownerGrant := aclGrant{Grantee: aclGrantee{ID: globalMinioDefaultOwnerID}, Permission: aclPermissionFullControl}
+	testCases := []struct {
+		grants      []aclGrant
+		expectedACL string
+		expectedOk  bool
+	}{
+		// Test case - 1.
+		// Only the owner has access.
+		{[]aclGrant{ownerGrant}, cannedACLPrivate, true},
+		// Test case - 2.
+		// Everyone may read.
+		{[]aclGrant{ownerGrant, newGroupGrant(aclAllUsersGroup, aclPermissionRead)}, cannedACLPublicRead, true},
+		// Test case - 3.
+		// Everyone may read and write.
+		{[]aclGrant{newGroupGrant(aclAllUsersGroup, aclPermissionWrite), newGroupGrant(aclAllUsersGroup, aclPermissionRead)}, cannedACLPublicReadWrite, true},
+		// Test case - 4.
+		// Authenticated users may read.
+		{[]aclGrant{ownerGrant, newGroupGrant(aclAuthenticatedUsersGroup, aclPermissionRead)}, cannedACLAuthenticatedRead, true},
+		// Test case - 5.
+		// Write only access has no canned ACL.
+		{[]aclGrant{newGroupGrant(aclAllUsersGroup, aclPermissionWrite)}, "", false},
+		// Test case - 6.
+		// Grants to other users have no canned ACL.
+		{[]aclGrant{{Grantee: aclGrantee{ID: "other"}, Permission: aclPermissionRead}}, "", false},
+		// Test case - 7.
+		// Grants to several groups have no canned ACL.
+		{[]aclGrant{newGroupGrant(aclAllUsersGroup, aclPermissionRead), newGroupGrant(aclAuthenticatedUsersGroup, aclPermissionRead)}, "", false},
+	}
+	for i, testCase := range testCases {
+		acl, ok := getCannedACL(accessControlPolicy{AccessControlList: testCase.grants})
+		if acl != testCase.expectedACL || ok != testCase.expectedOk {
+			t.Errorf("Test %d: Expected %q %v, got %q %v", i+1, testCase.expectedACL, testCase.expectedOk, acl, ok)
+		}
+	}
+
+	// Every canned ACL survives a round trip through XML.
+	for _, acl := range []string{cannedACLPrivate, cannedACLPublicRead, cannedACLPublicReadWrite, cannedACLAuthenticatedRead} {
+		data, err := xml.Marshal(newAccessControlPolicy(acl))
+		if err != nil {
+			t.Fatalf("Unable to marshal %s: %v", acl, err)
+		}
+		acp := accessControlPolicy{}
+		if err = xml.Unmarshal(data, &acp); err != nil {
+			t.Fatalf("Unable to unmarshal %s: %v", acl, err)
+		}
+		if got, ok := getCannedACL(acp); !ok || got != acl {
+			t.Errorf("Expected %q, got %q", acl, got)
+		}
+	}
+}
+
+// Tests the canned ACL of the `x-amz-acl` header.
+func TestGetACLFromHeader(t *testing.T) {
+	testCases := []struct {
+		header        http.Header
+		expectedACL   string
+		expectedError APIErrorCode
+	}{
+		// Test case - 1.
+		// No ACL.
+		{http.Header{}, "", ErrNone},
+		// Test case - 2.
+		// Canned ACL.
+		{http.Header{amzACLHeader: []string{"public-read"}}, cannedACLPublicRead, ErrNone},
+		// Test case - 3.
+		// Unknown canned ACL.
+		{http.Header{amzACLHeader: []string{"bucket-owner-read"}}, "", ErrInvalidCannedACL},
+		// Test case - 4.
+		// Explicit grants are not supported.
+		{http.Header{"X-Amz-Grant-Read": []string{"uri=" + aclAllUsersGroup}}, "", ErrUnsupportedACL},
+	}
+	for i, testCase := range testCases {
+		acl, s3Error := getACLFromHeader(testCase.header)
+		if acl != testCase.expectedACL || s3Error != testCase.expectedError {
+			t.Errorf("Test %d: Expected %q %d, got %q %d", i+1, testCase.expectedACL, testCase.expectedError, acl, s3Error)
+		}
+	}
+}
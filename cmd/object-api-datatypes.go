@@ -114,6 +114,23 @@ type ObjectInfo struct {
 	// User-Defined metadata
 	UserDefined    map[string]string
 	HealObjectInfo *HealObjectInfo `xml:"HealObjectInfo,omitempty"`
+
+	// Version id of the object, empty for objects written
+	// while versioning was never enabled on the bucket.
+	VersionID string
+
+	// IsLatest indicates if this is the current version of the object.
+	IsLatest bool
+
+	// DeleteMarker indicates if this version is a delete marker.
+	DeleteMarker bool
+
+	// URL encoded tags of the object, empty if untagged.
+	UserTags string
+
+	// Parts of an encrypted object, only filled in for encrypted
+	// objects as their parts are decrypted separately.
+	Parts []objectPartInfo `xml:"-"`
 }
 
 // ListPartsInfo - represents list of all parts.
@@ -147,6 +164,9 @@ type ListPartsInfo struct {
 	// List of all parts.
 	Parts []partInfo
 
+	// Metadata the upload was initiated with.
+	UserDefined map[string]string `xml:"-"`
+
 	EncodingType string // Not supported yet.
 }
 
@@ -220,6 +240,24 @@ type ListObjectsInfo struct {
 	Prefixes []string
 }
 
+// ListObjectVersionsInfo - container for list object versions.
+type ListObjectVersionsInfo struct {
+	// Indicates whether the returned list of versions is truncated.
+	IsTruncated bool
+
+	// When response is truncated, the key and version id to be used
+	// as key-marker and version-id-marker in the subsequent request.
+	NextKeyMarker       string
+	NextVersionIDMarker string
+
+	// List of object versions, including delete markers, sorted by
+	// object name and then from the newest to the oldest version.
+	Objects []ObjectInfo
+
+	// List of prefixes for this request.
+	Prefixes []string
+}
+
 // partInfo - represents individual part metadata.
 type partInfo struct {
 	// Part number that identifies the part. This is a positive integer between
@@ -159,6 +159,28 @@ func (e ObjectNotFound) Error() string {
 	return "Object not found: " + e.Bucket + "#" + e.Object
 }
 
+// VersionNotFound object version does not exist.
+type VersionNotFound struct {
+	Bucket    string
+	Object    string
+	VersionID string
+}
+
+func (e VersionNotFound) Error() string {
+	return "Version not found: " + e.Bucket + "#" + e.Object + "#" + e.VersionID
+}
+
+// ObjectLocked object version is protected by object lock.
+type ObjectLocked struct {
+	Bucket    string
+	Object    string
+	VersionID string
+}
+
+func (e ObjectLocked) Error() string {
+	return "Object is protected by object lock: " + e.Bucket + "#" + e.Object
+}
+
 // ObjectExistsAsDirectory object already exists as a directory.
 type ObjectExistsAsDirectory GenericError
 
@@ -352,6 +374,16 @@ func isErrBucketPolicyNotFound(err error) bool {
 	return false
 }
 
+// Check if error type is VersionNotFound.
+func isErrVersionNotFound(err error) bool {
+	err = errorCause(err)
+	switch err.(type) {
+	case VersionNotFound:
+		return true
+	}
+	return false
+}
+
 // Check if error type is ObjectNotFound.
 func isErrObjectNotFound(err error) bool {
 	err = errorCause(err)
@@ -361,3 +393,23 @@ func isErrObjectNotFound(err error) bool {
 	}
 	return false
 }
+
+// Check if error type is ObjectLocked.
+func isErrObjectLocked(err error) bool {
+	err = errorCause(err)
+	switch err.(type) {
+	case ObjectLocked:
+		return true
+	}
+	return false
+}
+
+// Check if error type is InvalidUploadID.
+func isErrInvalidUploadID(err error) bool {
+	err = errorCause(err)
+	switch err.(type) {
+	case InvalidUploadID:
+		return true
+	}
+	return false
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"strings"
 
 	"github.com/skyrings/skyring-common/tools/uuid"
@@ -153,7 +154,7 @@ func checkBucketExist(bucket string, obj ObjectLayer) error {
 	if !IsValidBucketName(bucket) {
 		return BucketNameInvalid{Bucket: bucket}
 	}
-	_, err := obj.GetBucketInfo(bucket)
+	_, err := obj.GetBucketInfo(context.Background(), bucket)
 	if err != nil {
 		return BucketNotFound{Bucket: bucket}
 	}
@@ -0,0 +1,574 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/skyrings/skyring-common/tools/uuid"
+)
+
+const (
+	// Versions prefix, non current versions of objects are saved
+	// under `.minio.sys/versions/<bucket>/<object>/<version-id>`.
+	versionsPrefix = "versions"
+
+	// Version id of objects written while versioning is not enabled.
+	nullVersionID = "null"
+
+	// Reserved metadata entries carrying the versioning state of an
+	// object, these are never returned as user defined metadata.
+	versionIDMetaKey    = "X-Minio-Internal-Version-Id"
+	deleteMarkerMetaKey = "X-Minio-Internal-Delete-Marker"
+)
+
+// versionedObjects - backend specific primitives the bucket versioning
+// logic in this file is built upon, implemented by both FS and XL.
+//
+// The current version of an object always lives at its regular
+// location, all the other versions and delete markers are saved
+// in the versions area of minioMetaBucket.
+type versionedObjects interface {
+	ObjectLayer
+
+	// Returns object info of the current version of an object.
+	getObjectInfo(bucket, object string) (ObjectInfo, error)
+	// Removes the current version of an object for good.
+	removeObject(bucket, object string) error
+	// Moves the current version of an object to the versions area.
+	archiveObject(bucket, object, versionID string) error
+	// Moves a saved version back as the current version of an object.
+	restoreVersion(bucket, object, versionID string) error
+	// Saves a new delete marker in the versions area.
+	putDeleteMarker(bucket, object, versionID string, modTime time.Time) error
+	// Reads a saved version.
+	getVersionInfo(bucket, object, versionID string) (ObjectInfo, error)
+	getVersion(ctx context.Context, bucket, object, versionID string, startOffset int64, length int64, writer io.Writer) error
+	// Removes a saved version for good.
+	deleteVersion(bucket, object, versionID string) error
+	// Lists saved versions of objects starting with prefix and listed
+	// after marker, of at most maxObjects objects unless zero.
+	listVersions(bucket, prefix, marker string, maxObjects int) ([]ObjectInfo, bool, error)
+	// Updates metadata entries of the current version of an object.
+	updateObjectMetadata(bucket, object string, updates map[string]string) error
+	// Updates metadata entries of a saved version.
+	updateVersionMetadata(bucket, object, versionID string, updates map[string]string) error
+}
+
+// Returns the path of a saved object version inside minioMetaBucket.
+func objectVersionPath(bucket, object, versionID string) string {
+	return pathJoin(versionsPrefix, bucket, object, versionID)
+}
+
+// toVersionErr - converts errors of reading a saved version, a missing
+// object in the versions area is a missing version.
+func toVersionErr(err error, bucket, object, versionID string) error {
+	err = toObjectErr(err, bucket, object)
+	if isErrObjectNotFound(err) {
+		return traceError(VersionNotFound{Bucket: bucket, Object: object, VersionID: versionID})
+	}
+	return err
+}
+
+// Version ids are either the null version id or uuids generated when
+// the object was written, anything else can never be found.
+func isValidVersionID(versionID string) bool {
+	if versionID == nullVersionID {
+		return true
+	}
+	id, err := uuid.Parse(versionID)
+	return err == nil && !id.IsZero()
+}
+
+// Objects written while versioning was never enabled on the bucket do
+// not carry any version id, they are known as the null version.
+func versionIDOrNull(versionID string) string {
+	if versionID == "" {
+		return nullVersionID
+	}
+	return versionID
+}
+
+// extractVersionInfo - removes the reserved versioning entries from the
+// metadata of an object and returns their values.
+func extractVersionInfo(meta map[string]string) (versionID string, deleteMarker bool) {
+	versionID = meta[versionIDMetaKey]
+	deleteMarker = meta[deleteMarkerMetaKey] == "true"
+	delete(meta, versionIDMetaKey)
+	delete(meta, deleteMarkerMetaKey)
+	return versionID, deleteMarker
+}
+
+// setObjectVersionID - saves the version id of an object about to be
+// written into its metadata, depending on the versioning state of the
+// bucket. Nothing is saved for buckets which never had versioning.
+func setObjectVersionID(bucket string, metadata map[string]string) {
+	switch getBucketVersioningStatus(bucket) {
+	case versioningEnabled:
+		metadata[versionIDMetaKey] = mustGetUUID()
+	case versioningSuspended:
+		metadata[versionIDMetaKey] = nullVersionID
+	}
+}
+
+// Returns the current version of an object, ok is false if the
+// object does not exist.
+func getCurrentVersion(obj versionedObjects, bucket, object string) (objInfo ObjectInfo, ok bool, err error) {
+	objInfo, err = obj.getObjectInfo(bucket, object)
+	if err != nil {
+		err = toObjectErr(err, bucket, object)
+		if isErrObjectNotFound(err) {
+			return ObjectInfo{}, false, nil
+		}
+		return ObjectInfo{}, false, err
+	}
+	objInfo.IsLatest = true
+	return objInfo, true, nil
+}
+
+// archiveCurrentVersion - makes room for a new current version of an
+// object. On buckets with versioning enabled the current version is
+// preserved in the versions area, with versioning suspended the null
+// version is replaced, only one null version may exist at a time.
+func archiveCurrentVersion(obj versionedObjects, bucket, object string) error {
+	status := getBucketVersioningStatus(bucket)
+	if status == "" {
+		return nil
+	}
+
+	if status == versioningSuspended {
+		err := obj.deleteVersion(bucket, object, nullVersionID)
+		if err != nil && !isErrVersionNotFound(err) {
+			return err
+		}
+	}
+
+	objInfo, ok, err := getCurrentVersion(obj, bucket, object)
+	if err != nil || !ok {
+		return err
+	}
+
+	versionID := versionIDOrNull(objInfo.VersionID)
+	if status == versioningSuspended && versionID == nullVersionID {
+		return obj.removeObject(bucket, object)
+	}
+	return obj.archiveObject(bucket, object, versionID)
+}
+
+// promoteLatestVersion - restores the newest saved version of an object
+// as its current version, unless it is a delete marker. Called once the
+// current version was removed.
+func promoteLatestVersion(obj versionedObjects, bucket, object string) error {
+	versions, err := listSavedVersions(obj, bucket, object)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 || versions[0].DeleteMarker {
+		return nil
+	}
+	return obj.restoreVersion(bucket, object, versions[0].VersionID)
+}
+
+// listSavedVersions - returns the saved versions of an object from the
+// newest to the oldest version.
+func listSavedVersions(obj versionedObjects, bucket, object string) ([]ObjectInfo, error) {
+	// Versions of an object are saved in a directory named after it,
+	// objects nested under its name are skipped.
+	saved, _, err := obj.listVersions(bucket, object+slashSeparator, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	var versions []ObjectInfo
+	for _, version := range saved {
+		if version.Name == object {
+			versions = append(versions, version)
+		}
+	}
+	sort.Sort(byObjectVersion(versions))
+	return versions, nil
+}
+
+// getObjectVersionInfo - implements GetObjectVersionInfo, an empty
+// versionID refers to the current version.
+func getObjectVersionInfo(obj versionedObjects, bucket, object, versionID string) (ObjectInfo, error) {
+	current, ok, err := getCurrentVersion(obj, bucket, object)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if ok && (versionID == "" || versionIDOrNull(current.VersionID) == versionID) {
+		return current, nil
+	}
+	if versionID == "" {
+		return ObjectInfo{}, traceError(ObjectNotFound{Bucket: bucket, Object: object})
+	}
+	if !isValidVersionID(versionID) {
+		return ObjectInfo{}, traceError(VersionNotFound{Bucket: bucket, Object: object, VersionID: versionID})
+	}
+	return obj.getVersionInfo(bucket, object, versionID)
+}
+
+// getObjectVersion - implements GetObjectVersion, delete markers have
+// no content and are reported as not found.
+func getObjectVersion(ctx context.Context, obj versionedObjects, bucket, object, versionID string, startOffset int64, length int64, writer io.Writer) error {
+	objInfo, err := getObjectVersionInfo(obj, bucket, object, versionID)
+	if err != nil {
+		return err
+	}
+	if objInfo.DeleteMarker {
+		return traceError(VersionNotFound{Bucket: bucket, Object: object, VersionID: versionID})
+	}
+	if objInfo.IsLatest {
+		return obj.GetObject(ctx, bucket, object, startOffset, length, writer)
+	}
+	return obj.getVersion(ctx, bucket, object, objInfo.VersionID, startOffset, length, writer)
+}
+
+// deleteObjectVersion - implements DeleteObjectVersion. Without a
+// versionID the object is removed on buckets without versioning,
+// otherwise a new delete marker is placed on top of its versions.
+// Removing the current version by its versionID promotes the newest
+// saved version as the current one. Versions retained by object lock
+// are never removed, unless their GOVERNANCE retention is bypassed by
+// the request of ctx.
+func deleteObjectVersion(ctx context.Context, obj versionedObjects, bucket, object, versionID string) (ObjectInfo, error) {
+	// Retained versions can not be removed, on buckets with versioning
+	// enabled a delete marker may still hide them.
+	if err := checkObjectRetention(obj, bucket, object, versionID, isGovernanceBypassed(ctx)); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	if versionID == "" {
+		status := getBucketVersioningStatus(bucket)
+		if status == "" {
+			return ObjectInfo{Bucket: bucket, Name: object}, obj.removeObject(bucket, object)
+		}
+		if err := archiveCurrentVersion(obj, bucket, object); err != nil {
+			return ObjectInfo{}, err
+		}
+		markerID := nullVersionID
+		if status == versioningEnabled {
+			markerID = mustGetUUID()
+		}
+		modTime := time.Now().UTC()
+		if err := obj.putDeleteMarker(bucket, object, markerID, modTime); err != nil {
+			return ObjectInfo{}, err
+		}
+		return ObjectInfo{
+			Bucket:       bucket,
+			Name:         object,
+			ModTime:      modTime,
+			VersionID:    markerID,
+			IsLatest:     true,
+			DeleteMarker: true,
+		}, nil
+	}
+
+	if !isValidVersionID(versionID) {
+		return ObjectInfo{}, traceError(VersionNotFound{Bucket: bucket, Object: object, VersionID: versionID})
+	}
+
+	current, ok, err := getCurrentVersion(obj, bucket, object)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	if ok && versionIDOrNull(current.VersionID) == versionID {
+		if err = obj.removeObject(bucket, object); err != nil {
+			return ObjectInfo{}, err
+		}
+		return current, promoteLatestVersion(obj, bucket, object)
+	}
+
+	objInfo, err := obj.getVersionInfo(bucket, object, versionID)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if err = obj.deleteVersion(bucket, object, versionID); err != nil {
+		return ObjectInfo{}, err
+	}
+	if !ok {
+		// Removing the newest delete marker brings back the
+		// version underneath it.
+		if err = promoteLatestVersion(obj, bucket, object); err != nil {
+			return ObjectInfo{}, err
+		}
+	}
+	return objInfo, nil
+}
+
+// applyMetadataUpdates - sets the updated metadata entries, entries
+// updated with an empty value are removed.
+func applyMetadataUpdates(meta map[string]string, updates map[string]string) {
+	for k, v := range updates {
+		if v == "" {
+			delete(meta, k)
+			continue
+		}
+		meta[k] = v
+	}
+}
+
+// updateObjectVersionMetadata - implements UpdateObjectMetadata, the
+// metadata of an object version is updated in place, no new version
+// is created. Delete markers have no metadata to update.
+func updateObjectVersionMetadata(obj versionedObjects, bucket, object, versionID string, updates map[string]string) (ObjectInfo, error) {
+	objInfo, err := getObjectVersionInfo(obj, bucket, object, versionID)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if objInfo.DeleteMarker {
+		return ObjectInfo{}, traceError(VersionNotFound{Bucket: bucket, Object: object, VersionID: versionID})
+	}
+	if objInfo.IsLatest {
+		err = obj.updateObjectMetadata(bucket, object, updates)
+	} else {
+		err = obj.updateVersionMetadata(bucket, object, objInfo.VersionID, updates)
+	}
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return getObjectVersionInfo(obj, bucket, object, objInfo.VersionID)
+}
+
+// Sorts object versions by object name and then from the newest
+// to the oldest version.
+type byObjectVersion []ObjectInfo
+
+func (v byObjectVersion) Len() int      { return len(v) }
+func (v byObjectVersion) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v byObjectVersion) Less(i, j int) bool {
+	if v[i].Name != v[j].Name {
+		return v[i].Name < v[j].Name
+	}
+	if v[i].IsLatest != v[j].IsLatest {
+		return v[i].IsLatest
+	}
+	return v[i].ModTime.After(v[j].ModTime)
+}
+
+// listVersionsBatch - returns the versions of the next batch of at most
+// maxObjects objects under prefix listed after marker, sorted by object
+// name and from the newest to the oldest version. The returned marker
+// continues the listing, it is empty once all objects are listed.
+func listVersionsBatch(ctx context.Context, obj versionedObjects, bucket, prefix, marker string, maxObjects int) (versions []ObjectInfo, nextMarker string, err error) {
+	result, err := obj.ListObjects(ctx, bucket, prefix, marker, "", maxObjects)
+	if err != nil {
+		return nil, "", err
+	}
+	saved, isTruncated, err := obj.listVersions(bucket, prefix, marker, maxObjects)
+	if err != nil {
+		return nil, "", toObjectErr(err, bucket, prefix)
+	}
+
+	// Only objects up to the end of the shorter listing are known to
+	// have all their versions in this batch.
+	if result.IsTruncated {
+		nextMarker = result.NextMarker
+	}
+	if isTruncated && len(saved) > 0 {
+		if last := saved[len(saved)-1].Name; nextMarker == "" || last < nextMarker {
+			nextMarker = last
+		}
+	}
+
+	for _, objInfo := range result.Objects {
+		if nextMarker != "" && objInfo.Name > nextMarker {
+			break
+		}
+		// Listing does not carry versioning state on all backends.
+		current, ok, cerr := getCurrentVersion(obj, bucket, objInfo.Name)
+		if cerr != nil {
+			return nil, "", cerr
+		}
+		if ok {
+			versions = append(versions, current)
+		}
+	}
+	for _, version := range saved {
+		if nextMarker != "" && version.Name > nextMarker {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	sort.Sort(byObjectVersion(versions))
+
+	// Objects without a current version have a delete marker on top.
+	for i := range versions {
+		if i == 0 || versions[i-1].Name != versions[i].Name {
+			versions[i].IsLatest = true
+		}
+	}
+	return versions, nextMarker, nil
+}
+
+// listObjectVersions - implements ListObjectVersions. Objects under
+// prefix are scanned in batches starting after the key marker, until
+// a page of maxKeys versions is filled.
+func listObjectVersions(ctx context.Context, obj versionedObjects, bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int) (ListObjectVersionsInfo, error) {
+	if err := checkListObjsArgs(bucket, prefix, keyMarker, delimiter, obj); err != nil {
+		return ListObjectVersionsInfo{}, err
+	}
+
+	// With max keys of zero we have reached eof, return right here.
+	if maxKeys == 0 {
+		return ListObjectVersionsInfo{}, nil
+	}
+
+	// Over flowing count - reset to maxObjectList.
+	if maxKeys < 0 || maxKeys > maxObjectList {
+		maxKeys = maxObjectList
+	}
+
+	// Versions of the key marker newer than the version id marker
+	// were listed already, the older ones are listed first.
+	var versions []ObjectInfo
+	if keyMarker != "" && versionIDMarker != "" && strings.HasPrefix(keyMarker, prefix) {
+		current, ok, err := getCurrentVersion(obj, bucket, keyMarker)
+		if err != nil {
+			return ListObjectVersionsInfo{}, err
+		}
+		saved, err := listSavedVersions(obj, bucket, keyMarker)
+		if err != nil {
+			return ListObjectVersionsInfo{}, toObjectErr(err, bucket, keyMarker)
+		}
+		if ok {
+			versions = append(versions, current)
+		}
+		versions = append(versions, saved...)
+		if len(versions) > 0 {
+			versions[0].IsLatest = true
+		}
+	}
+	skipVersions := true
+
+	result := ListObjectVersionsInfo{}
+	count := 0
+	marker := keyMarker
+	for {
+		batch, nextMarker, err := listVersionsBatch(ctx, obj, bucket, prefix, marker, maxKeys)
+		if err != nil {
+			return ListObjectVersionsInfo{}, err
+		}
+		versions = append(versions, batch...)
+
+		for _, version := range versions {
+			if version.Name == keyMarker && skipVersions {
+				if versionIDOrNull(version.VersionID) == versionIDMarker {
+					skipVersions = false
+				}
+				continue
+			}
+			// A common prefix as marker skips all the keys under it.
+			if delimiter != "" && strings.HasSuffix(keyMarker, delimiter) && strings.HasPrefix(version.Name, keyMarker) {
+				continue
+			}
+			if delimiter != "" {
+				suffix := strings.TrimPrefix(version.Name, prefix)
+				if idx := strings.Index(suffix, delimiter); idx != -1 {
+					commonPrefix := prefix + suffix[:idx+len(delimiter)]
+					if count > 0 && result.NextKeyMarker == commonPrefix {
+						// Common prefix already listed.
+						continue
+					}
+					if count == maxKeys {
+						result.IsTruncated = true
+						break
+					}
+					result.Prefixes = append(result.Prefixes, commonPrefix)
+					result.NextKeyMarker = commonPrefix
+					result.NextVersionIDMarker = ""
+					count++
+					continue
+				}
+			}
+			if count == maxKeys {
+				result.IsTruncated = true
+				break
+			}
+			result.Objects = append(result.Objects, version)
+			result.NextKeyMarker = version.Name
+			result.NextVersionIDMarker = versionIDOrNull(version.VersionID)
+			count++
+		}
+		if result.IsTruncated || nextMarker == "" {
+			break
+		}
+		versions = nil
+		marker = nextMarker
+	}
+	if !result.IsTruncated {
+		result.NextKeyMarker = ""
+		result.NextVersionIDMarker = ""
+	}
+	return result, nil
+}
+
+// walkVersions - walks the versions area of a bucket, calling walkFn
+// with the object name and version id of every saved version of the
+// objects starting with prefix and listed after marker. The walk stops
+// short of the versions of object number maxObjects+1 unless maxObjects
+// is zero, isTruncated is true then.
+func walkVersions(bucket, prefix, marker string, maxObjects int, listDir listDirFunc, isLeaf isLeafFunc, walkFn func(object, versionID string) error) (isTruncated bool, err error) {
+	versionsDir := path.Join(versionsPrefix, bucket) + slashSeparator
+
+	endWalkCh := make(chan struct{})
+	defer close(endWalkCh)
+
+	// Versions of the marker itself are walked too and skipped below.
+	walkMarker := ""
+	if marker != "" {
+		walkMarker = versionsDir + marker
+	}
+
+	recursive := true
+	objects := 0
+	lastObject := ""
+	walkResultCh := startTreeWalk(minioMetaBucket, versionsDir+prefix, walkMarker, recursive, listDir, isLeaf, endWalkCh)
+	for walkResult := range walkResultCh {
+		if walkResult.err != nil {
+			// No versions saved is a valid case.
+			if isErrIgnored(walkResult.err, errFileNotFound, errVolumeNotFound) {
+				return false, nil
+			}
+			return false, walkResult.err
+		}
+		entry := strings.TrimPrefix(walkResult.entry, versionsDir)
+		object, versionID := path.Dir(entry), path.Base(entry)
+		if object == "." || strings.HasSuffix(entry, slashSeparator) {
+			continue
+		}
+		if marker != "" && object <= marker {
+			continue
+		}
+		if object != lastObject {
+			if maxObjects > 0 && objects == maxObjects {
+				return true, nil
+			}
+			objects++
+			lastObject = object
+		}
+		if err = walkFn(object, versionID); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,342 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+// Wrapper for calling object versioning tests for both XL multiple disks and single node setup.
+func TestObjectVersioning(t *testing.T) {
+	ExecObjectLayerTest(t, testObjectVersioning)
+}
+
+// Tests versions and delete markers of objects on a versioned bucket.
+func testObjectVersioning(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "versioned-bucket"
+	object := "dir/object"
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	globalBucketVersioning.Set(bucket, &versioningConfig{Status: versioningEnabled})
+	defer globalBucketVersioning.Set(bucket, nil)
+
+	var versionIDs []string
+	for _, content := range []string{"first", "second"} {
+		objInfo, err := obj.PutObject(context.Background(), bucket, object, int64(len(content)), bytes.NewBufferString(content), nil, "")
+		if err != nil {
+			t.Fatalf("%s : %s", instanceType, err)
+		}
+		if objInfo.VersionID == "" || objInfo.VersionID == nullVersionID {
+			t.Fatalf("%s: Expected a version id, got %q", instanceType, objInfo.VersionID)
+		}
+		versionIDs = append(versionIDs, objInfo.VersionID)
+	}
+
+	// Reading the older version.
+	var buffer bytes.Buffer
+	if err := obj.GetObjectVersion(context.Background(), bucket, object, versionIDs[0], 0, -1, &buffer); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if buffer.String() != "first" {
+		t.Errorf("%s: Expected %q, got %q", instanceType, "first", buffer.String())
+	}
+
+	// Current version reports its version id.
+	objInfo, err := obj.GetObjectInfo(context.Background(), bucket, object)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if objInfo.VersionID != versionIDs[1] {
+		t.Errorf("%s: Expected version %s, got %s", instanceType, versionIDs[1], objInfo.VersionID)
+	}
+	if _, ok := objInfo.UserDefined[versionIDMetaKey]; ok {
+		t.Errorf("%s: Version id leaked into user defined metadata", instanceType)
+	}
+
+	// Deleting places a delete marker on top.
+	if err = obj.DeleteObject(context.Background(), bucket, object); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if _, err = obj.GetObjectInfo(context.Background(), bucket, object); !isErrObjectNotFound(errorCause(err)) {
+		t.Fatalf("%s: Expected object not found, got %v", instanceType, err)
+	}
+
+	result, err := obj.ListObjectVersions(context.Background(), bucket, "", "", "", "", 1000)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if len(result.Objects) != 3 {
+		t.Fatalf("%s: Expected 3 versions, got %d", instanceType, len(result.Objects))
+	}
+	marker := result.Objects[0]
+	if !marker.DeleteMarker || !marker.IsLatest {
+		t.Fatalf("%s: Expected a latest delete marker, got %#v", instanceType, marker)
+	}
+	if result.Objects[1].VersionID != versionIDs[1] || result.Objects[2].VersionID != versionIDs[0] {
+		t.Errorf("%s: Versions listed out of order", instanceType)
+	}
+	if result.Objects[1].IsLatest || result.Objects[2].IsLatest {
+		t.Errorf("%s: Only the delete marker should be latest", instanceType)
+	}
+
+	// Reading the delete marker is not possible.
+	if err = obj.GetObjectVersion(context.Background(), bucket, object, marker.VersionID, 0, -1, &buffer); !isErrVersionNotFound(err) {
+		t.Errorf("%s: Expected version not found, got %v", instanceType, err)
+	}
+
+	// Versions keep the bucket from being deleted.
+	if err = obj.DeleteBucket(context.Background(), bucket); err == nil {
+		t.Fatalf("%s: Expected bucket deletion to fail", instanceType)
+	}
+	if _, ok := errorCause(err).(BucketNotEmpty); !ok {
+		t.Fatalf("%s: Expected BucketNotEmpty, got %v", instanceType, err)
+	}
+
+	// Removing the delete marker brings back the previous version.
+	if _, err = obj.DeleteObjectVersion(context.Background(), bucket, object, marker.VersionID); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	buffer.Reset()
+	if err = obj.GetObject(context.Background(), bucket, object, 0, -1, &buffer); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if buffer.String() != "second" {
+		t.Errorf("%s: Expected %q, got %q", instanceType, "second", buffer.String())
+	}
+
+	// Removing the current version promotes the older one.
+	if _, err = obj.DeleteObjectVersion(context.Background(), bucket, object, versionIDs[1]); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	objInfo, err = obj.GetObjectVersionInfo(context.Background(), bucket, object, "")
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if objInfo.VersionID != versionIDs[0] || !objInfo.IsLatest {
+		t.Errorf("%s: Expected version %s to be latest, got %#v", instanceType, versionIDs[0], objInfo)
+	}
+
+	// Unknown versions.
+	for _, versionID := range []string{versionIDs[1], "../../object", mustGetUUID()} {
+		if _, err = obj.GetObjectVersionInfo(context.Background(), bucket, object, versionID); !isErrVersionNotFound(err) {
+			t.Errorf("%s: Expected version not found for %s, got %v", instanceType, versionID, err)
+		}
+	}
+
+	// Removing the last version frees the bucket.
+	if _, err = obj.DeleteObjectVersion(context.Background(), bucket, object, versionIDs[0]); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if err = obj.DeleteBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+}
+
+// Wrapper for calling suspended versioning tests for both XL multiple disks and single node setup.
+func TestObjectVersioningSuspended(t *testing.T) {
+	ExecObjectLayerTest(t, testObjectVersioningSuspended)
+}
+
+// Tests the null version of objects on buckets with versioning suspended.
+func testObjectVersioningSuspended(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "suspended-bucket"
+	object := "object"
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	// Written before versioning was ever enabled.
+	if _, err := obj.PutObject(context.Background(), bucket, object, int64(len("zero")), bytes.NewBufferString("zero"), nil, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	globalBucketVersioning.Set(bucket, &versioningConfig{Status: versioningEnabled})
+	defer globalBucketVersioning.Set(bucket, nil)
+	enabledInfo, err := obj.PutObject(context.Background(), bucket, object, int64(len("one")), bytes.NewBufferString("one"), nil, "")
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	globalBucketVersioning.Set(bucket, &versioningConfig{Status: versioningSuspended})
+	for _, content := range []string{"two", "three"} {
+		objInfo, err := obj.PutObject(context.Background(), bucket, object, int64(len(content)), bytes.NewBufferString(content), nil, "")
+		if err != nil {
+			t.Fatalf("%s : %s", instanceType, err)
+		}
+		if objInfo.VersionID != nullVersionID {
+			t.Errorf("%s: Expected null version, got %s", instanceType, objInfo.VersionID)
+		}
+	}
+
+	// The null version written before versioning was replaced.
+	result, err := obj.ListObjectVersions(context.Background(), bucket, "", "", "", "", 1000)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if len(result.Objects) != 2 {
+		t.Fatalf("%s: Expected 2 versions, got %d", instanceType, len(result.Objects))
+	}
+	if result.Objects[0].VersionID != nullVersionID || result.Objects[1].VersionID != enabledInfo.VersionID {
+		t.Errorf("%s: Unexpected versions %#v", instanceType, result.Objects)
+	}
+
+	var buffer bytes.Buffer
+	if err = obj.GetObjectVersion(context.Background(), bucket, object, nullVersionID, 0, -1, &buffer); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if buffer.String() != "three" {
+		t.Errorf("%s: Expected %q, got %q", instanceType, "three", buffer.String())
+	}
+
+	// A failed upload leaves the null version in place.
+	metadata := map[string]string{"md5Sum": "a8f5f167f44f4964e6c998dee827110c"}
+	if _, err = obj.PutObject(context.Background(), bucket, object, int64(len("four")), bytes.NewBufferString("four"), metadata, ""); err == nil {
+		t.Fatalf("%s: Expected upload with a bad digest to fail", instanceType)
+	}
+	buffer.Reset()
+	if err = obj.GetObjectVersion(context.Background(), bucket, object, nullVersionID, 0, -1, &buffer); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if buffer.String() != "three" {
+		t.Errorf("%s: Expected %q, got %q", instanceType, "three", buffer.String())
+	}
+}
+
+// Wrapper for calling ListObjectVersions tests for both XL multiple disks and single node setup.
+func TestListObjectVersions(t *testing.T) {
+	ExecObjectLayerTest(t, testListObjectVersions)
+}
+
+// Tests paging through object versions.
+func testListObjectVersions(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "list-versions"
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	globalBucketVersioning.Set(bucket, &versioningConfig{Status: versioningEnabled})
+	defer globalBucketVersioning.Set(bucket, nil)
+
+	for _, object := range []string{"a", "b", "b", "c/d", "c/e"} {
+		if _, err := obj.PutObject(context.Background(), bucket, object, int64(len(object)), bytes.NewBufferString(object), nil, ""); err != nil {
+			t.Fatalf("%s : %s", instanceType, err)
+		}
+	}
+
+	testCases := []struct {
+		prefix          string
+		keyMarker       string
+		versionIDMarker string
+		delimiter       string
+		maxKeys         int
+
+		// Expected output of ListObjectVersions.
+		keys        []string
+		prefixes    []string
+		isTruncated bool
+	}{
+		// Test case - 1.
+		// Listing all versions.
+		{"", "", "", "", 1000, []string{"a", "b", "b", "c/d", "c/e"}, nil, false},
+		// Test case - 2.
+		// Listing with a delimiter.
+		{"", "", "", "/", 1000, []string{"a", "b", "b"}, []string{"c/"}, false},
+		// Test case - 3.
+		// Listing with a prefix.
+		{"c/", "", "", "/", 1000, []string{"c/d", "c/e"}, nil, false},
+		// Test case - 4.
+		// Truncated listing.
+		{"", "", "", "", 2, []string{"a", "b"}, nil, true},
+		// Test case - 5.
+		// Listing after a key marker.
+		{"", "b", "", "", 1000, []string{"c/d", "c/e"}, nil, false},
+		// Test case - 6.
+		// Listing after a common prefix.
+		{"", "c/", "", "/", 1000, nil, nil, false},
+		// Test case - 7.
+		// Zero max keys.
+		{"", "", "", "", 0, nil, nil, false},
+	}
+
+	for i, testCase := range testCases {
+		result, err := obj.ListObjectVersions(context.Background(), bucket, testCase.prefix, testCase.keyMarker, testCase.versionIDMarker, testCase.delimiter, testCase.maxKeys)
+		if err != nil {
+			t.Fatalf("Test %d: %s: %s", i+1, instanceType, err)
+		}
+		var keys []string
+		for _, objInfo := range result.Objects {
+			keys = append(keys, objInfo.Name)
+		}
+		if !stringSlicesEqual(keys, testCase.keys) {
+			t.Errorf("Test %d: %s: Expected keys %v, got %v", i+1, instanceType, testCase.keys, keys)
+		}
+		if !stringSlicesEqual(result.Prefixes, testCase.prefixes) {
+			t.Errorf("Test %d: %s: Expected prefixes %v, got %v", i+1, instanceType, testCase.prefixes, result.Prefixes)
+		}
+		if result.IsTruncated != testCase.isTruncated {
+			t.Errorf("Test %d: %s: Expected truncated %v, got %v", i+1, instanceType, testCase.isTruncated, result.IsTruncated)
+		}
+	}
+
+	// Continuing from the markers of a truncated listing.
+	result, err := obj.ListObjectVersions(context.Background(), bucket, "", "", "", "", 2)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	result, err = obj.ListObjectVersions(context.Background(), bucket, "", result.NextKeyMarker, result.NextVersionIDMarker, "", 2)
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if len(result.Objects) != 2 || result.Objects[0].Name != "b" || result.Objects[0].IsLatest || result.Objects[1].Name != "c/d" {
+		t.Errorf("%s: Unexpected second page %#v", instanceType, result.Objects)
+	}
+
+	// Paging one version at a time lists every version once.
+	var keys []string
+	keyMarker, versionIDMarker := "", ""
+	for {
+		result, err = obj.ListObjectVersions(context.Background(), bucket, "", keyMarker, versionIDMarker, "", 1)
+		if err != nil {
+			t.Fatalf("%s : %s", instanceType, err)
+		}
+		for _, objInfo := range result.Objects {
+			keys = append(keys, objInfo.Name)
+		}
+		if !result.IsTruncated {
+			break
+		}
+		keyMarker, versionIDMarker = result.NextKeyMarker, result.NextVersionIDMarker
+	}
+	if expected := []string{"a", "b", "b", "c/d", "c/e"}; !stringSlicesEqual(keys, expected) {
+		t.Errorf("%s: Expected keys %v, got %v", instanceType, expected, keys)
+	}
+}
+
+// Compares two string slices, nil and empty are equal.
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
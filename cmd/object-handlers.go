@@ -19,6 +19,7 @@ package cmd
 import (
 	"encoding/hex"
 	"encoding/xml"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -26,6 +27,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	mux "github.com/gorilla/mux"
 )
@@ -59,7 +61,7 @@ func errAllowableObjectNotFound(bucket string, r *http.Request) APIErrorCode {
 		url := *r.URL
 		url.Path = "/" + bucket
 
-		if s3Error := enforceBucketPolicy(bucket, "s3:ListBucket", &url); s3Error != ErrNone {
+		if s3Error := enforceBucketPolicy(bucket, "s3:ListBucket", &url, r); s3Error != ErrNone {
 			return ErrAccessDenied
 		}
 	}
@@ -90,7 +92,11 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion()); s3Error != ErrNone {
+	// Anonymous requests the bucket policy does not allow may still
+	// read objects with a public ACL.
+	s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion())
+	aclRead := s3Error == ErrAccessDenied && getRequestAuthType(r) == authTypeAnonymous
+	if s3Error != ErrNone && !aclRead {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -100,16 +106,38 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 	objectLock.RLock()
 	defer objectLock.RUnlock()
 
-	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.GetObjectVersionInfo(r.Context(), bucket, object, versionID)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		errorIfCtx(r.Context(), err, "Unable to fetch object info.")
 		apiErr := toAPIErrorCode(err)
-		if apiErr == ErrNoSuchKey {
+		if aclRead {
+			apiErr = ErrAccessDenied
+		} else if apiErr == ErrNoSuchKey {
 			apiErr = errAllowableObjectNotFound(bucket, r)
 		}
 		writeErrorResponse(w, apiErr, r.URL)
 		return
 	}
+	if aclRead && !isObjectACLReadAllowed(r, bucket, objInfo) {
+		writeErrorResponse(w, ErrAccessDenied, r.URL)
+		return
+	}
+
+	// Delete markers have no content.
+	if objInfo.DeleteMarker {
+		setVersionHeaders(w, objInfo)
+		writeErrorResponse(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	// Encrypted objects need the key they were written with, the size
+	// and ranges refer to the plaintext from here on.
+	objectKey, s3Error := getSSEObjectKey(r.Header, "", &objInfo)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
 
 	// Get request range.
 	var hrange *httpRange
@@ -124,7 +152,7 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 			}
 
 			// log the error.
-			errorIf(err, "Invalid request range")
+			errorIfCtx(r.Context(), err, "Invalid request range")
 		}
 	}
 
@@ -146,6 +174,8 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 	writer := funcToWriter(func(p []byte) (int, error) {
 		if !dataWritten {
 			// Set headers on the first write.
+			setSSEHeaders(w, objInfo.UserDefined, r.Header)
+
 			// Set standard object headers.
 			setObjectHeaders(w, objInfo, hrange)
 
@@ -158,8 +188,13 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 	})
 
 	// Reads the object at startOffset and writes to mw.
-	if err := objectAPI.GetObject(bucket, object, startOffset, length, writer); err != nil {
-		errorIf(err, "Unable to write to client.")
+	if objectKey != nil {
+		err = getEncryptedObject(r.Context(), objectAPI, objInfo, versionID, objectKey, startOffset, length, writer)
+	} else {
+		err = objectAPI.GetObjectVersion(r.Context(), bucket, object, versionID, startOffset, length, writer)
+	}
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to write to client.")
 		if !dataWritten {
 			// Error response only if no data has been written to client yet. i.e if
 			// partial data has already been written before an error
@@ -192,7 +227,11 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion()); s3Error != ErrNone {
+	// Anonymous requests the bucket policy does not allow may still
+	// read objects with a public ACL.
+	s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion())
+	aclRead := s3Error == ErrAccessDenied && getRequestAuthType(r) == authTypeAnonymous
+	if s3Error != ErrNone && !aclRead {
 		writeErrorResponseHeadersOnly(w, s3Error)
 		return
 	}
@@ -202,22 +241,44 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 	objectLock.RLock()
 	defer objectLock.RUnlock()
 
-	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.GetObjectVersionInfo(r.Context(), bucket, object, versionID)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		errorIfCtx(r.Context(), err, "Unable to fetch object info.")
 		apiErr := toAPIErrorCode(err)
-		if apiErr == ErrNoSuchKey {
+		if aclRead {
+			apiErr = ErrAccessDenied
+		} else if apiErr == ErrNoSuchKey {
 			apiErr = errAllowableObjectNotFound(bucket, r)
 		}
 		writeErrorResponseHeadersOnly(w, apiErr)
 		return
 	}
+	if aclRead && !isObjectACLReadAllowed(r, bucket, objInfo) {
+		writeErrorResponseHeadersOnly(w, ErrAccessDenied)
+		return
+	}
+
+	// Delete markers have no content.
+	if objInfo.DeleteMarker {
+		setVersionHeaders(w, objInfo)
+		writeErrorResponseHeadersOnly(w, ErrMethodNotAllowed)
+		return
+	}
+
+	// Encrypted objects need the key they were written with.
+	if _, s3Error := getSSEObjectKey(r.Header, "", &objInfo); s3Error != ErrNone {
+		writeErrorResponseHeadersOnly(w, s3Error)
+		return
+	}
 
 	// Validate pre-conditions if any.
 	if checkPreconditions(w, r, objInfo) {
 		return
 	}
 
+	setSSEHeaders(w, objInfo.UserDefined, r.Header)
+
 	// Set standard object headers.
 	setObjectHeaders(w, objInfo, nil)
 
@@ -281,12 +342,24 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// The caller must be allowed to read the source object.
+	if s3Error := checkCopySourceAuth(r, srcBucket, srcObject); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	// Check if metadata directive is valid.
 	if !isMetadataDirectiveValid(r.Header) {
 		writeErrorResponse(w, ErrInvalidMetadataDirective, r.URL)
 		return
 	}
 
+	// Check if tagging directive is valid.
+	if !isTaggingDirectiveValid(r.Header) {
+		writeErrorResponse(w, ErrInvalidTaggingDirective, r.URL)
+		return
+	}
+
 	cpSrcDstSame := cpSrcPath == cpDestPath
 	// Hold write lock on destination since in both cases
 	// - if source and destination are same
@@ -308,9 +381,9 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 
 	}
 
-	objInfo, err := objectAPI.GetObjectInfo(srcBucket, srcObject)
+	objInfo, err := objectAPI.GetObjectInfo(r.Context(), srcBucket, srcObject)
 	if err != nil {
-		errorIf(err, "Unable to fetch object info.")
+		errorIfCtx(r.Context(), err, "Unable to fetch object info.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -320,6 +393,22 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Encrypted sources are unsealed by the x-amz-copy-source SSE-C
+	// headers or the KMS, the size refers to the plaintext from here on.
+	srcObjectKey, s3Error := getSSEObjectKey(r.Header, amzSSECopySourcePrefix, &objInfo)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Objects copied in place keep the key they were encrypted with.
+	if cpSrcDstSame {
+		if s3Error = checkSSECopyInPlace(r.Header, objInfo.UserDefined); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	}
+
 	/// maximum Upload size for object in a single CopyObject operation.
 	if isMaxObjectSize(objInfo.Size) {
 		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
@@ -334,18 +423,82 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	delete(defaultMeta, "md5Sum")
 
 	newMetadata := getCpObjMetadataFromHeader(r.Header, defaultMeta)
-	// Check if x-amz-metadata-directive was not set to REPLACE and source,
+
+	var dstObjectKey []byte
+	if cpSrcDstSame {
+		// Sealed keys are bound to the name of the object, in place
+		// copies only update metadata and keep them.
+		for _, key := range sseMetadataKeys {
+			if value, ok := objInfo.UserDefined[key]; ok {
+				newMetadata[key] = value
+			}
+		}
+	} else {
+		// The key of the source object is never copied, the destination
+		// gets a key of its own if it is encrypted.
+		removeEncryptionMetadata(newMetadata)
+		if dstObjectKey, s3Error = newSSEObjectKey(r.Header, dstBucket, dstObject, newMetadata); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	}
+
+	// Check if neither x-amz-metadata-directive nor x-amz-tagging-directive
+	// was set to REPLACE nor a storage class was set and source,
 	// desination are same objects.
-	if !isMetadataReplace(r.Header) && cpSrcDstSame {
-		// If x-amz-metadata-directive is not set to REPLACE then we need
-		// to error out if source and destination are same.
+	if !isMetadataReplace(r.Header) && !isTaggingReplace(r.Header) &&
+		r.Header.Get(amzStorageClassHeader) == "" && cpSrcDstSame {
+		// If no directive is set to REPLACE then we need to error out
+		// if source and destination are same.
 		writeErrorResponse(w, ErrInvalidCopyDest, r.URL)
 		return
 	}
 
-	// Copy source object to destination, if source and destination
-	// object is same then only metadata is updated.
-	objInfo, err = objectAPI.CopyObject(srcBucket, srcObject, dstBucket, dstObject, newMetadata)
+	// Tags of the source object are copied unless x-amz-tagging-directive
+	// says REPLACE.
+	if isTaggingReplace(r.Header) {
+		if s3Error := setObjectTagsFromHeader(r.Header, newMetadata); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	} else if objInfo.UserTags != "" {
+		newMetadata[objectTagsMetaKey] = objInfo.UserTags
+	}
+
+	// The ACL of the source is never copied, the copy is private unless
+	// x-amz-acl says otherwise.
+	delete(newMetadata, objectACLMetaKey)
+	if s3Error := setObjectACLFromHeader(r.Header, newMetadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// The storage class of the source is never copied either, the copy
+	// is STANDARD unless x-amz-storage-class says otherwise.
+	delete(newMetadata, amzStorageClassHeader)
+	if s3Error := setStorageClassFromHeader(r.Header, newMetadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// The object lock state of the source is never copied, the copy is
+	// retained as requested or by the default retention of the bucket.
+	removeObjectLockMetadata(newMetadata)
+	if s3Error := setObjectLockMetadata(r.Header, dstBucket, newMetadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	setReplicationMetadata(r.Header, dstBucket, dstObject, newMetadata)
+
+	if !cpSrcDstSame && (srcObjectKey != nil || dstObjectKey != nil) {
+		// Encrypted objects are decrypted and encrypted again on the
+		// fly, the object layer never sees any plaintext.
+		objInfo, err = copyEncryptedObject(r.Context(), objectAPI, objInfo, srcObjectKey, dstBucket, dstObject, dstObjectKey, newMetadata)
+	} else {
+		// Copy source object to destination, if source and destination
+		// object is same then only metadata is updated.
+		objInfo, err = objectAPI.CopyObject(r.Context(), srcBucket, srcObject, dstBucket, dstObject, newMetadata)
+	}
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -354,6 +507,8 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	md5Sum := objInfo.MD5Sum
 	response := generateCopyObjectResponse(md5Sum, objInfo.ModTime)
 	encodedSuccessResponse := encodeResponse(response)
+	setVersionHeaders(w, objInfo)
+	setSSEHeaders(w, newMetadata, r.Header)
 
 	// Write success response.
 	writeSuccessResponseXML(w, encodedSuccessResponse)
@@ -392,7 +547,7 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 	// Get Content-Md5 sent by client and verify if valid
 	md5Bytes, err := checkValidMD5(r.Header.Get("Content-Md5"))
 	if err != nil {
-		errorIf(err, "Unable to validate content-md5 format.")
+		errorIfCtx(r.Context(), err, "Unable to validate content-md5 format.")
 		writeErrorResponse(w, ErrInvalidDigest, r.URL)
 		return
 	}
@@ -404,7 +559,7 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		sizeStr := r.Header.Get("x-amz-decoded-content-length")
 		size, err = strconv.ParseInt(sizeStr, 10, 64)
 		if err != nil {
-			errorIf(err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
+			errorIfCtx(r.Context(), err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
 			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 			return
 		}
@@ -425,6 +580,42 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 	// Make sure we hex encode md5sum here.
 	metadata["md5Sum"] = hex.EncodeToString(md5Bytes)
 
+	// Save tags sent along with the object.
+	if s3Error := setObjectTagsFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Save the canned ACL sent along with the object.
+	if s3Error := setObjectACLFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Save the storage class the object is erasure coded with.
+	if s3Error := setStorageClassFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Save the requested retention and legal hold along with the object.
+	if s3Error := setObjectLockMetadata(r.Header, bucket, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Objects matching a replication rule wait to be replicated.
+	setReplicationMetadata(r.Header, bucket, object, metadata)
+
+	// Objects sent with encryption headers or written to a bucket with
+	// default encryption are encrypted with a key of their own, sealed
+	// by the client key or a data key of the KMS.
+	objectKey, s3Error := newSSEObjectKey(r.Header, bucket, object, metadata)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	sha256sum := ""
 
 	// Lock the object.
@@ -432,7 +623,7 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
-	var objInfo ObjectInfo
+	var reader io.Reader = r.Body
 	switch rAuthType {
 	default:
 		// For all unknown auth types return error.
@@ -440,47 +631,68 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	case authTypeAnonymous:
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/using-with-s3-actions.html
-		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL); s3Error != ErrNone {
+		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL, r); s3Error != ErrNone {
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
-		// Create anonymous object.
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
+		// No need to verify signature, anonymous request access is already allowed.
 	case authTypeStreamingSigned:
 		// Initialize stream signature verifier.
-		reader, s3Error := newSignV4ChunkedReader(r)
+		var s3Error APIErrorCode
+		reader, s3Error = newSignV4ChunkedReader(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			errorIfCtx(r.Context(), errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
-		objInfo, err = objectAPI.PutObject(bucket, object, size, reader, metadata, sha256sum)
 	case authTypeSignedV2, authTypePresignedV2:
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			errorIfCtx(r.Context(), errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
 	case authTypePresigned, authTypeSigned:
 		if s3Error := reqSignatureV4Verify(r); s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			errorIfCtx(r.Context(), errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
 		if !skipContentSha256Cksum(r) {
 			sha256sum = r.Header.Get("X-Amz-Content-Sha256")
 		}
-		// Create object.
-		objInfo, err = objectAPI.PutObject(bucket, object, size, r.Body, metadata, sha256sum)
 	}
+
+	// Signed requests of IAM users are subject to their policies.
+	if rAuthType != authTypeAnonymous {
+		if s3Error := enforceUserPolicy(getRequestAccessKey(r), bucket, "s3:PutObject", r.URL, r); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	}
+
+	// Only ciphertext reaches the object layer, checksums of the
+	// plaintext are verified while encrypting.
+	if objectKey != nil {
+		if reader, err = newSSEEncryptReader(reader, size, objectKey, 1, metadata["md5Sum"], sha256sum); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		delete(metadata, "md5Sum")
+		sha256sum = ""
+		size = sseEncryptedSize(size)
+	}
+
+	// Create object.
+	objInfo, err := objectAPI.PutObject(r.Context(), bucket, object, size, reader, metadata, sha256sum)
 	if err != nil {
-		errorIf(err, "Unable to create an object.")
+		errorIfCtx(r.Context(), err, "Unable to create an object.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 	w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+	setVersionHeaders(w, objInfo)
+	setSSEHeaders(w, metadata, r.Header)
 	writeSuccessResponseHeadersOnly(w)
 
 	// Notify object created event.
@@ -517,15 +729,182 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 	// Extract metadata that needs to be saved.
 	metadata := extractMetadataFromHeader(r.Header)
 
-	uploadID, err := objectAPI.NewMultipartUpload(bucket, object, metadata)
+	// Save tags sent along with the object.
+	if s3Error := setObjectTagsFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Save the canned ACL sent along with the object.
+	if s3Error := setObjectACLFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Save the storage class the object is erasure coded with.
+	if s3Error := setStorageClassFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Save the requested retention and legal hold along with the object.
+	if s3Error := setObjectLockMetadata(r.Header, bucket, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Objects matching a replication rule wait to be replicated.
+	setReplicationMetadata(r.Header, bucket, object, metadata)
+
+	// Encrypted uploads get a key of their own, parts of SSE-C uploads
+	// have to be sent with the same client key.
+	if _, s3Error := newSSEObjectKey(r.Header, bucket, object, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	uploadID, err := objectAPI.NewMultipartUpload(r.Context(), bucket, object, metadata)
 	if err != nil {
-		errorIf(err, "Unable to initiate new multipart upload id.")
+		errorIfCtx(r.Context(), err, "Unable to initiate new multipart upload id.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
 	response := generateInitiateMultipartUploadResponse(bucket, object, uploadID)
 	encodedSuccessResponse := encodeResponse(response)
+	setSSEHeaders(w, metadata, r.Header)
+
+	// Write success response.
+	writeSuccessResponseXML(w, encodedSuccessResponse)
+}
+
+// CopyObjectPartHandler - uploads a part by copying data from an existing object as data source.
+func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	dstBucket := vars["bucket"]
+	dstObject := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, dstBucket, "s3:PutObject", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Copy source path.
+	cpSrcPath, err := url.QueryUnescape(r.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		// Save unescaped string as is.
+		cpSrcPath = r.Header.Get("X-Amz-Copy-Source")
+	}
+
+	srcBucket, srcObject := path2BucketAndObject(cpSrcPath)
+	// If source object is empty or bucket is empty, reply back invalid copy source.
+	if srcObject == "" || srcBucket == "" {
+		writeErrorResponse(w, ErrInvalidCopySource, r.URL)
+		return
+	}
+
+	// The caller must be allowed to read the source object.
+	if s3Error := checkCopySourceAuth(r, srcBucket, srcObject); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	uploadID := r.URL.Query().Get("uploadId")
+	partIDString := r.URL.Query().Get("partNumber")
+
+	partID, err := strconv.Atoi(partIDString)
+	if err != nil {
+		writeErrorResponse(w, ErrInvalidPart, r.URL)
+		return
+	}
+
+	// check partID with maximum part ID for multipart objects
+	if isMaxPartID(partID) {
+		writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
+		return
+	}
+
+	// Hold read locks on source object only if we are
+	// going to read data from source object.
+	objectSRLock := globalNSMutex.NewNSLock(srcBucket, srcObject)
+	objectSRLock.RLock()
+	defer objectSRLock.RUnlock()
+
+	objInfo, err := objectAPI.GetObjectInfo(r.Context(), srcBucket, srcObject)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Verify before x-amz-copy-source preconditions before continuing with CopyObjectPart.
+	if checkCopyObjectPreconditions(w, r, objInfo) {
+		return
+	}
+
+	// Encrypted sources are unsealed by the x-amz-copy-source SSE-C
+	// headers or the KMS, the size refers to the plaintext from here on.
+	srcObjectKey, s3Error := getSSEObjectKey(r.Header, amzSSECopySourcePrefix, &objInfo)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Get the range to copy, the whole object by default.
+	startOffset := int64(0)
+	length := objInfo.Size
+	if rangeHeader := r.Header.Get("x-amz-copy-source-range"); rangeHeader != "" {
+		hrange, rangeErr := parseCopyPartRange(rangeHeader, objInfo.Size)
+		if rangeErr != nil {
+			errorIfCtx(r.Context(), rangeErr, "Unable to extract range %s", rangeHeader)
+			if rangeErr == errInvalidRange {
+				writeErrorResponse(w, ErrInvalidCopyPartRangeSource, r.URL)
+				return
+			}
+			writeErrorResponse(w, ErrInvalidCopyPartRange, r.URL)
+			return
+		}
+		startOffset = hrange.offsetBegin
+		length = hrange.getLength()
+	}
+
+	/// maximum copy size for multipart objects in a single operation
+	if isMaxObjectSize(length) {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+
+	// Parts of encrypted uploads are encrypted with the key of the upload.
+	uploadKey, uploadMetadata, s3Error := getSSEUploadKey(r.Context(), objectAPI, r.Header, dstBucket, dstObject, uploadID)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	var partMD5 string
+	if srcObjectKey != nil || uploadKey != nil {
+		// Encrypted data is decrypted and encrypted again on the fly.
+		partMD5, err = copyEncryptedObjectPart(r.Context(), objectAPI, objInfo, srcObjectKey, startOffset, length, dstBucket, dstObject, uploadID, partID, uploadKey)
+	} else {
+		// Copy source object to destination, the part is read from
+		// the source object directly.
+		partMD5, err = objectAPI.CopyObjectPart(r.Context(), srcBucket, srcObject, dstBucket, dstObject, uploadID, partID, startOffset, length)
+	}
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to copy object part.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	response := generateCopyObjectPartResponse(partMD5, time.Now().UTC())
+	encodedSuccessResponse := encodeResponse(response)
+	setSSEHeaders(w, uploadMetadata, r.Header)
 
 	// Write success response.
 	writeSuccessResponseXML(w, encodedSuccessResponse)
@@ -559,7 +938,7 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		sizeStr := r.Header.Get("x-amz-decoded-content-length")
 		size, err = strconv.ParseInt(sizeStr, 10, 64)
 		if err != nil {
-			errorIf(err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
+			errorIfCtx(r.Context(), err, "Unable to parse `x-amz-decoded-content-length` into its integer value", sizeStr)
 			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 			return
 		}
@@ -590,9 +969,9 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	var partMD5 string
 	incomingMD5 := hex.EncodeToString(md5Bytes)
 	sha256sum := ""
+	var reader io.Reader = r.Body
 	switch rAuthType {
 	default:
 		// For all unknown auth types return error.
@@ -600,32 +979,30 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		return
 	case authTypeAnonymous:
 		// http://docs.aws.amazon.com/AmazonS3/latest/dev/mpuAndPermissions.html
-		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL); s3Error != ErrNone {
+		if s3Error := enforceBucketPolicy(bucket, "s3:PutObject", r.URL, r); s3Error != ErrNone {
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
 		// No need to verify signature, anonymous request access is already allowed.
-		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	case authTypeStreamingSigned:
 		// Initialize stream signature verifier.
-		reader, s3Error := newSignV4ChunkedReader(r)
+		var s3Error APIErrorCode
+		reader, s3Error = newSignV4ChunkedReader(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			errorIfCtx(r.Context(), errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
-		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, reader, incomingMD5, sha256sum)
 	case authTypeSignedV2, authTypePresignedV2:
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			errorIfCtx(r.Context(), errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
-		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	case authTypePresigned, authTypeSigned:
 		if s3Error := reqSignatureV4Verify(r); s3Error != ErrNone {
-			errorIf(errSignatureMismatch, dumpRequest(r))
+			errorIfCtx(r.Context(), errSignatureMismatch, dumpRequest(r))
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
@@ -633,10 +1010,35 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		if !skipContentSha256Cksum(r) {
 			sha256sum = r.Header.Get("X-Amz-Content-Sha256")
 		}
-		partMD5, err = objectAPI.PutObjectPart(bucket, object, uploadID, partID, size, r.Body, incomingMD5, sha256sum)
 	}
+
+	// Signed requests of IAM users are subject to their policies.
+	if rAuthType != authTypeAnonymous {
+		if s3Error := enforceUserPolicy(getRequestAccessKey(r), bucket, "s3:PutObject", r.URL, r); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	}
+
+	// Parts of encrypted uploads are encrypted with the key of the
+	// upload, checksums of the plaintext are verified while encrypting.
+	objectKey, uploadMetadata, s3Error := getSSEUploadKey(r.Context(), objectAPI, r.Header, bucket, object, uploadID)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	if objectKey != nil {
+		if reader, err = newSSEEncryptReader(reader, size, objectKey, partID, incomingMD5, sha256sum); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		incomingMD5, sha256sum = "", ""
+		size = sseEncryptedSize(size)
+	}
+
+	partMD5, err := objectAPI.PutObjectPart(r.Context(), bucket, object, uploadID, partID, size, reader, incomingMD5, sha256sum)
 	if err != nil {
-		errorIf(err, "Unable to create object part.")
+		errorIfCtx(r.Context(), err, "Unable to create object part.")
 		// Verify if the underlying error is signature mismatch.
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
@@ -644,6 +1046,7 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 	if partMD5 != "" {
 		w.Header().Set("ETag", "\""+partMD5+"\"")
 	}
+	setSSEHeaders(w, uploadMetadata, r.Header)
 
 	writeSuccessResponseHeadersOnly(w)
 }
@@ -666,8 +1069,8 @@ func (api objectAPIHandlers) AbortMultipartUploadHandler(w http.ResponseWriter,
 	}
 
 	uploadID, _, _, _ := getObjectResources(r.URL.Query())
-	if err := objectAPI.AbortMultipartUpload(bucket, object, uploadID); err != nil {
-		errorIf(err, "Unable to abort multipart upload.")
+	if err := objectAPI.AbortMultipartUpload(r.Context(), bucket, object, uploadID); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to abort multipart upload.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -700,9 +1103,9 @@ func (api objectAPIHandlers) ListObjectPartsHandler(w http.ResponseWriter, r *ht
 		writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
 		return
 	}
-	listPartsInfo, err := objectAPI.ListObjectParts(bucket, object, uploadID, partNumberMarker, maxParts)
+	listPartsInfo, err := objectAPI.ListObjectParts(r.Context(), bucket, object, uploadID, partNumberMarker, maxParts)
 	if err != nil {
-		errorIf(err, "Unable to list uploaded parts.")
+		errorIfCtx(r.Context(), err, "Unable to list uploaded parts.")
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
@@ -735,13 +1138,13 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 
 	completeMultipartBytes, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		errorIf(err, "Unable to complete multipart upload.")
+		errorIfCtx(r.Context(), err, "Unable to complete multipart upload.")
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
 	complMultipartUpload := &completeMultipartUpload{}
 	if err = xml.Unmarshal(completeMultipartBytes, complMultipartUpload); err != nil {
-		errorIf(err, "Unable to parse complete multipart upload XML.")
+		errorIfCtx(r.Context(), err, "Unable to parse complete multipart upload XML.")
 		writeErrorResponse(w, ErrMalformedXML, r.URL)
 		return
 	}
@@ -767,9 +1170,9 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	destLock.Lock()
 	defer destLock.Unlock()
 
-	objInfo, err := objectAPI.CompleteMultipartUpload(bucket, object, uploadID, completeParts)
+	objInfo, err := objectAPI.CompleteMultipartUpload(r.Context(), bucket, object, uploadID, completeParts)
 	if err != nil {
-		errorIf(err, "Unable to complete multipart upload.")
+		errorIfCtx(r.Context(), err, "Unable to complete multipart upload.")
 		err = errorCause(err)
 		switch oErr := err.(type) {
 		case PartTooSmall:
@@ -788,13 +1191,14 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 	response := generateCompleteMultpartUploadResponse(bucket, object, location, objInfo.MD5Sum)
 	encodedSuccessResponse := encodeResponse(response)
 	if err != nil {
-		errorIf(err, "Unable to parse CompleteMultipartUpload response")
+		errorIfCtx(r.Context(), err, "Unable to parse CompleteMultipartUpload response")
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
 
 	// Set etag.
 	w.Header().Set("ETag", "\""+objInfo.MD5Sum+"\"")
+	setVersionHeaders(w, objInfo)
 
 	// Write success response.
 	writeSuccessResponseXML(w, encodedSuccessResponse)
@@ -829,17 +1233,37 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Removing versions under GOVERNANCE retention needs the permission
+	// to bypass it.
+	ctx := r.Context()
+	if isBypassGovernanceRetention(r.Header) {
+		if s3Error := checkRequestAuthType(r, bucket, "s3:BypassGovernanceRetention", serverConfig.GetRegion()); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+		ctx = contextWithBypassGovernance(ctx)
+	}
+
 	objectLock := globalNSMutex.NewNSLock(bucket, object)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
 	/// http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectDELETE.html
 	/// Ignore delete object errors, since we are suppposed to reply
-	/// only 204.
-	if err := objectAPI.DeleteObject(bucket, object); err != nil {
+	/// only 204. Deleting a specific version or a retained object
+	/// reports errors though.
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.DeleteObjectVersion(ctx, bucket, object, versionID)
+	if err != nil {
+		if versionID != "" || isErrObjectLocked(err) {
+			errorIfCtx(r.Context(), err, "Unable to delete object version.")
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
 		writeSuccessNoContent(w)
 		return
 	}
+	setVersionHeaders(w, objInfo)
 	writeSuccessNoContent(w)
 
 	// Notify object deleted event.
@@ -847,7 +1271,8 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 		Type:   ObjectRemovedDelete,
 		Bucket: bucket,
 		ObjInfo: ObjectInfo{
-			Name: object,
+			Name:      object,
+			VersionID: versionID,
 		},
 		ReqParams: map[string]string{
 			"sourceIPAddress": r.RemoteAddr,
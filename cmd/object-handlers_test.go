@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -26,8 +27,10 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 )
@@ -76,7 +79,7 @@ func testAPIHeadObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 	// iterate through the above set of inputs and upload the object.
 	for i, input := range putObjectInputs {
 		// uploading the object.
-		_, err := obj.PutObject(input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, sha256sum)
+		_, err := obj.PutObject(context.Background(), input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, sha256sum)
 		// if object upload fails stop the test.
 		if err != nil {
 			t.Fatalf("Put Object case %d:  Error uploading object: <ERROR> %v", i+1, err)
@@ -224,7 +227,7 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 	// iterate through the above set of inputs and upload the object.
 	for i, input := range putObjectInputs {
 		// uploading the object.
-		_, err := obj.PutObject(input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, sha256sum)
+		_, err := obj.PutObject(context.Background(), input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, sha256sum)
 		// if object upload fails stop the test.
 		if err != nil {
 			t.Fatalf("Put Object case %d:  Error uploading object: <ERROR> %v", i+1, err)
@@ -669,7 +672,7 @@ func testAPIPutObjectStreamSigV4Handler(obj ObjectLayer, instanceType, bucketNam
 			}
 
 			buffer := new(bytes.Buffer)
-			err = obj.GetObject(testCase.bucketName, testCase.objectName, 0, int64(bytesDataLen), buffer)
+			err = obj.GetObject(context.Background(), testCase.bucketName, testCase.objectName, 0, int64(bytesDataLen), buffer)
 			if err != nil {
 				t.Fatalf("Test %d: %s: Failed to fetch the copied object: <ERROR> %s", i+1, instanceType, err)
 			}
@@ -832,7 +835,7 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 			buffer := new(bytes.Buffer)
 
 			// Fetch the object to check whether the content is same as the one uploaded via PutObject.
-			err = obj.GetObject(testCase.bucketName, testCase.objectName, 0, int64(len(bytesData)), buffer)
+			err = obj.GetObject(context.Background(), testCase.bucketName, testCase.objectName, 0, int64(len(bytesData)), buffer)
 			if err != nil {
 				t.Fatalf("Test %d: %s: Failed to fetch the copied object: <ERROR> %s", i+1, instanceType, err)
 			}
@@ -875,7 +878,7 @@ func testAPIPutObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 		if testCase.expectedRespStatus == http.StatusOK {
 			buffer := new(bytes.Buffer)
 			// Fetch the object to check whether the content is same as the one uploaded via PutObject.
-			err = obj.GetObject(testCase.bucketName, testCase.objectName, 0, int64(len(bytesData)), buffer)
+			err = obj.GetObject(context.Background(), testCase.bucketName, testCase.objectName, 0, int64(len(bytesData)), buffer)
 			if err != nil {
 				t.Fatalf("Test %d: %s: Failed to fetch the copied object: <ERROR> %s", i+1, instanceType, err)
 			}
@@ -969,7 +972,7 @@ func testAPICopyObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 	// iterate through the above set of inputs and upload the object.
 	for i, input := range putObjectInputs {
 		// uploading the object.
-		_, err = obj.PutObject(input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, sha256sum)
+		_, err = obj.PutObject(context.Background(), input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, sha256sum)
 		// if object upload fails stop the test.
 		if err != nil {
 			t.Fatalf("Put Object case %d:  Error uploading object: <ERROR> %v", i+1, err)
@@ -1157,7 +1160,7 @@ func testAPICopyObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 		if rec.Code == http.StatusOK {
 			// See if the new object is formed.
 			// testing whether the copy was successful.
-			err = obj.GetObject(testCase.bucketName, testCase.newObjectName, 0, int64(len(bytesData[0].byteData)), buffers[0])
+			err = obj.GetObject(context.Background(), testCase.bucketName, testCase.newObjectName, 0, int64(len(bytesData[0].byteData)), buffers[0])
 			if err != nil {
 				t.Fatalf("Test %d: %s: Failed to fetch the copied object: <ERROR> %s", i+1, instanceType, err)
 			}
@@ -1220,9 +1223,9 @@ func testAPICopyObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 	// Its necessary to set the "X-Amz-Copy-Source" header for the request to be accepted by the handler.
 	anonReq.Header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+anonObject))
 	// ExecObjectLayerAPIAnonTest - Calls the HTTP API handler using the anonymous request, validates the ErrAccessDeniedResponse,
-	// sets the bucket policy using the policy statement generated from `getWriteOnlyObjectStatement` so that the
-	// unsigned request goes through and its validated again.
-	ExecObjectLayerAPIAnonTest(t, "TestAPICopyObjectHandler", bucketName, newCopyAnonObject, instanceType, apiRouter, anonReq, getWriteOnlyObjectStatement)
+	// sets the bucket policy using the policy statement generated from `getReadWriteObjectStatement` so that the
+	// unsigned request, which reads the copy source, goes through and its validated again.
+	ExecObjectLayerAPIAnonTest(t, "TestAPICopyObjectHandler", bucketName, newCopyAnonObject, instanceType, apiRouter, anonReq, getReadWriteObjectStatement)
 
 	// HTTP request to test the case of `objectLayer` being set to `nil`.
 	// There is no need to use an existing bucket or valid input for creating the request,
@@ -1248,6 +1251,310 @@ func testAPICopyObjectHandler(obj ObjectLayer, instanceType, bucketName string,
 
 }
 
+// Wrapper for calling Copy Object Part API handler tests for both XL multiple disks and single node setup.
+func TestAPICopyObjectPartHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPICopyObjectPartHandler, []string{"CopyObjectPart"})
+}
+
+func testAPICopyObjectPartHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	objectName := "test-object"
+	srcObject := "source-object"
+
+	srcData := generateBytesData(6 * humanize.KiByte)
+	_, err := obj.PutObject(context.Background(), bucketName, srcObject, int64(len(srcData)), bytes.NewReader(srcData), nil, "")
+	if err != nil {
+		t.Fatalf("%s: Error uploading object: <ERROR> %v", instanceType, err)
+	}
+
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucketName, objectName, nil)
+	if err != nil {
+		t.Fatalf("%s: Failed to create NewMultipartUpload: <ERROR> %v", instanceType, err)
+	}
+
+	// test cases with inputs and expected result for Copy Object Part.
+	testCases := []struct {
+		uploadID         string
+		partNumber       string
+		copySourceHeader string // data for "X-Amz-Copy-Source" header.
+		copySourceRange  string // data for "x-amz-copy-source-range" header.
+		accessKey        string
+		secretKey        string
+		// expected output.
+		expectedRespStatus int
+		expectedData       []byte
+	}{
+		// Test case - 1.
+		// Copy the whole source object.
+		{
+			uploadID:         uploadID,
+			partNumber:       "1",
+			copySourceHeader: url.QueryEscape("/" + bucketName + "/" + srcObject),
+			accessKey:        credentials.AccessKey,
+			secretKey:        credentials.SecretKey,
+
+			expectedRespStatus: http.StatusOK,
+			expectedData:       srcData,
+		},
+		// Test case - 2.
+		// Copy a range of the source object.
+		{
+			uploadID:         uploadID,
+			partNumber:       "2",
+			copySourceHeader: url.QueryEscape("/" + bucketName + "/" + srcObject),
+			copySourceRange:  "bytes=500-4095",
+			accessKey:        credentials.AccessKey,
+			secretKey:        credentials.SecretKey,
+
+			expectedRespStatus: http.StatusOK,
+			expectedData:       srcData[500:4096],
+		},
+		// Test case - 3.
+		// Copy source range not of the form bytes=first-last.
+		{
+			uploadID:         uploadID,
+			partNumber:       "3",
+			copySourceHeader: url.QueryEscape("/" + bucketName + "/" + srcObject),
+			copySourceRange:  "bytes=-100",
+			accessKey:        credentials.AccessKey,
+			secretKey:        credentials.SecretKey,
+
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 4.
+		// Copy source range beyond the end of the source object.
+		{
+			uploadID:         uploadID,
+			partNumber:       "3",
+			copySourceHeader: url.QueryEscape("/" + bucketName + "/" + srcObject),
+			copySourceRange:  "bytes=0-6144",
+			accessKey:        credentials.AccessKey,
+			secretKey:        credentials.SecretKey,
+
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 5.
+		// Copy source without an object name.
+		{
+			uploadID:         uploadID,
+			partNumber:       "3",
+			copySourceHeader: url.QueryEscape("/" + bucketName + "/"),
+			accessKey:        credentials.AccessKey,
+			secretKey:        credentials.SecretKey,
+
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 6.
+		// Non-existent source object.
+		{
+			uploadID:         uploadID,
+			partNumber:       "3",
+			copySourceHeader: url.QueryEscape("/" + bucketName + "/non-existent-object"),
+			accessKey:        credentials.AccessKey,
+			secretKey:        credentials.SecretKey,
+
+			expectedRespStatus: http.StatusNotFound,
+		},
+		// Test case - 7.
+		// Invalid upload id.
+		{
+			uploadID:         "xyz",
+			partNumber:       "3",
+			copySourceHeader: url.QueryEscape("/" + bucketName + "/" + srcObject),
+			accessKey:        credentials.AccessKey,
+			secretKey:        credentials.SecretKey,
+
+			expectedRespStatus: http.StatusNotFound,
+		},
+		// Test case - 8.
+		// Part number beyond the maximum number of parts.
+		{
+			uploadID:         uploadID,
+			partNumber:       "99999",
+			copySourceHeader: url.QueryEscape("/" + bucketName + "/" + srcObject),
+			accessKey:        credentials.AccessKey,
+			secretKey:        credentials.SecretKey,
+
+			expectedRespStatus: http.StatusBadRequest,
+		},
+		// Test case - 9.
+		// Case with invalid AccessKey.
+		{
+			uploadID:         uploadID,
+			partNumber:       "3",
+			copySourceHeader: url.QueryEscape("/" + bucketName + "/" + srcObject),
+			accessKey:        "Invalid-AccessID",
+			secretKey:        credentials.SecretKey,
+
+			expectedRespStatus: http.StatusForbidden,
+		},
+	}
+
+	for i, testCase := range testCases {
+		// initialize HTTP NewRecorder, this records any mutations to response writer inside the handler.
+		rec := httptest.NewRecorder()
+		// construct HTTP request for copy object part.
+		req, err := newTestSignedRequestV4("PUT", getPutObjectPartURL("", bucketName, objectName, testCase.uploadID, testCase.partNumber),
+			0, nil, testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to create HTTP request for copy object part: <ERROR> %v", i+1, err)
+		}
+		req.Header.Set("X-Amz-Copy-Source", testCase.copySourceHeader)
+		if testCase.copySourceRange != "" {
+			req.Header.Set("X-Amz-Copy-Source-Range", testCase.copySourceRange)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		var resp CopyObjectPartResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Test %d: %s: Failed to parse the response: <ERROR> %v", i+1, instanceType, err)
+		}
+		if expected := "\"" + getMD5Hash(testCase.expectedData) + "\""; resp.ETag != expected {
+			t.Errorf("Test %d: %s: Expected ETag %s, got %s", i+1, instanceType, expected, resp.ETag)
+		}
+	}
+
+	// The copied parts are listed with the sizes of the copied ranges.
+	partsInfo, err := obj.ListObjectParts(context.Background(), bucketName, objectName, uploadID, 0, 10)
+	if err != nil {
+		t.Fatalf("%s: Failed to list parts: <ERROR> %v", instanceType, err)
+	}
+	if len(partsInfo.Parts) != 2 || partsInfo.Parts[0].Size != int64(len(srcData)) || partsInfo.Parts[1].Size != 3596 {
+		t.Errorf("%s: Unexpected parts %v", instanceType, partsInfo.Parts)
+	}
+
+	// Anonymous requests need a bucket policy which allows to read the
+	// copy source, writing the part is not enough.
+	copyPartAnon := func() int {
+		anonReq, err := newTestRequest("PUT", getPutObjectPartURL("", bucketName, objectName, uploadID, "3"), 0, nil)
+		if err != nil {
+			t.Fatalf("%s: Failed to create an anonymous request for copy object part: <ERROR> %v", instanceType, err)
+		}
+		anonReq.Header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+srcObject))
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, anonReq)
+		return rec.Code
+	}
+	defer globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{true, nil})
+
+	policy := bucketPolicy{
+		Version:    "1.0",
+		Statements: []policyStatement{getWriteOnlyObjectStatement(bucketName, "")},
+	}
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, &policy})
+	if code := copyPartAnon(); code != http.StatusForbidden {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, code)
+	}
+
+	policy.Statements = []policyStatement{getReadWriteObjectStatement(bucketName, "")}
+	globalBucketPolicies.SetBucketPolicy(bucketName, policyChange{false, &policy})
+	if code := copyPartAnon(); code != http.StatusOK {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, code)
+	}
+}
+
+// Wrapper for calling the copy source tests of IAM users and temporary
+// credentials for both XL multiple disks and single node setup.
+func TestAPICopyObjectSourceUserPolicy(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPICopyObjectSourceUserPolicy, []string{"CopyObject", "CopyObjectPart"})
+}
+
+func testAPICopyObjectSourceUserPolicy(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	defer resetGlobalIAMSys()
+
+	// The same source object is stored in the bucket of the test and
+	// in a bucket the users may not read.
+	otherBucket := getRandomBucketName()
+	if err := obj.MakeBucket(context.Background(), otherBucket); err != nil {
+		t.Fatalf("%s: Failed to make bucket: <ERROR> %v", instanceType, err)
+	}
+	srcObject := "source-object"
+	srcData := []byte("source data")
+	for _, bucket := range []string{bucketName, otherBucket} {
+		_, err := obj.PutObject(context.Background(), bucket, srcObject, int64(len(srcData)), bytes.NewReader(srcData), nil, "")
+		if err != nil {
+			t.Fatalf("%s: Error uploading object: <ERROR> %v", instanceType, err)
+		}
+	}
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucketName, "test-object", nil)
+	if err != nil {
+		t.Fatalf("%s: Failed to create NewMultipartUpload: <ERROR> %v", instanceType, err)
+	}
+
+	// An IAM user, temporary credentials of the user issued by
+	// AssumeRole and temporary credentials of an LDAP or OpenID Connect
+	// login may only read and write objects in the bucket of the test.
+	policy := mustParseIAMPolicy(t, `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject", "s3:PutObject"], "Resource": ["arn:aws:s3:::`+bucketName+`/*"]}]}`)
+	userCred := credential{AccessKey: "newuser", SecretKey: "newuser123"}
+	globalIAMSys.Set(map[string]iamUser{
+		userCred.AccessKey: {Credential: userCred, Status: iamUserEnabled, Policies: []string{"readwrite"}},
+	}, map[string]*bucketPolicy{"readwrite": policy})
+
+	expiration := time.Now().UTC().Add(time.Hour)
+	stsCred := credential{AccessKey: "sts-temp", SecretKey: "tempsecret123"}
+	if stsCred.SessionToken, err = newSessionToken(stsCred.AccessKey, userCred.AccessKey, expiration); err != nil {
+		t.Fatalf("Failed to sign session token - %v", err)
+	}
+	ldapCred := credential{AccessKey: "ldap-temp", SecretKey: "tempsecret123"}
+	if ldapCred.SessionToken, err = newSessionToken(ldapCred.AccessKey, "", expiration); err != nil {
+		t.Fatalf("Failed to sign session token - %v", err)
+	}
+	globalIAMSys.SetTempUsers(map[string]iamTempUser{
+		stsCred.AccessKey:  {Credential: stsCred, ParentUser: userCred.AccessKey, Expiration: expiration},
+		ldapCred.AccessKey: {Credential: ldapCred, LDAPUser: "uid=dillon,ou=people,dc=example,dc=com", Policies: []string{"readwrite"}, Expiration: expiration},
+	})
+
+	testCases := []struct {
+		urlStr     string
+		copySource string
+		// expected output.
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Copy within the bucket the users may read.
+		{getCopyObjectURL("", bucketName, "copied-object"), bucketName, http.StatusOK},
+		// Test case - 2.
+		// Copy from a bucket the users may not read.
+		{getCopyObjectURL("", bucketName, "copied-object"), otherBucket, http.StatusForbidden},
+		// Test case - 3.
+		// Copy a part within the bucket the users may read.
+		{getPutObjectPartURL("", bucketName, "test-object", uploadID, "1"), bucketName, http.StatusOK},
+		// Test case - 4.
+		// Copy a part from a bucket the users may not read.
+		{getPutObjectPartURL("", bucketName, "test-object", uploadID, "1"), otherBucket, http.StatusForbidden},
+	}
+	for _, cred := range []credential{userCred, stsCred, ldapCred} {
+		for i, testCase := range testCases {
+			var req *http.Request
+			if cred.SessionToken != "" {
+				req, err = newTestSignedRequestV4WithToken("PUT", testCase.urlStr, cred)
+			} else {
+				req, err = newTestSignedRequestV4("PUT", testCase.urlStr, 0, nil, cred.AccessKey, cred.SecretKey)
+			}
+			if err != nil {
+				t.Fatalf("Test %d: Failed to create HTTP request for copy: <ERROR> %v", i+1, err)
+			}
+			req.Header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+testCase.copySource+"/"+srcObject))
+			rec := httptest.NewRecorder()
+			apiRouter.ServeHTTP(rec, req)
+			if rec.Code != testCase.expectedRespStatus {
+				t.Errorf("Test %d: %s: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, cred.AccessKey, testCase.expectedRespStatus, rec.Code)
+			}
+		}
+	}
+}
+
 // Wrapper for calling NewMultipartUpload tests for both XL multiple disks and single node setup.
 // First register the HTTP handler for NewMutlipartUpload, then a HTTP request for NewMultipart upload is made.
 // The UploadID from the response body is parsed and its existence is asserted with an attempt to ListParts using it.
@@ -1285,7 +1592,7 @@ func testAPINewMultipartHandler(obj ObjectLayer, instanceType, bucketName string
 		t.Fatalf("Error decoding the recorded response Body")
 	}
 	// verify the uploadID my making an attempt to list parts.
-	_, err = obj.ListObjectParts(bucketName, objectName, multipartResponse.UploadID, 0, 1)
+	_, err = obj.ListObjectParts(context.Background(), bucketName, objectName, multipartResponse.UploadID, 0, 1)
 	if err != nil {
 		t.Fatalf("Invalid UploadID: <ERROR> %s", err)
 	}
@@ -1337,7 +1644,7 @@ func testAPINewMultipartHandler(obj ObjectLayer, instanceType, bucketName string
 		t.Fatalf("Error decoding the recorded response Body")
 	}
 	// verify the uploadID my making an attempt to list parts.
-	_, err = obj.ListObjectParts(bucketName, objectName, multipartResponse.UploadID, 0, 1)
+	_, err = obj.ListObjectParts(context.Background(), bucketName, objectName, multipartResponse.UploadID, 0, 1)
 	if err != nil {
 		t.Fatalf("Invalid UploadID: <ERROR> %s", err)
 	}
@@ -1449,7 +1756,7 @@ func testAPINewMultipartHandlerParallel(obj ObjectLayer, instanceType, bucketNam
 	wg.Wait()
 	// Validate the upload ID by an attempt to list parts using it.
 	for _, uploadID := range testUploads.uploads {
-		_, err := obj.ListObjectParts(bucketName, objectName, uploadID, 0, 1)
+		_, err := obj.ListObjectParts(context.Background(), bucketName, objectName, uploadID, 0, 1)
 		if err != nil {
 			t.Fatalf("Invalid UploadID: <ERROR> %s", err)
 		}
@@ -1483,7 +1790,7 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 
 	for i := 0; i < 2; i++ {
 		// initiate new multipart uploadID.
-		uploadID, err = obj.NewMultipartUpload(bucketName, objectName, nil)
+		uploadID, err = obj.NewMultipartUpload(context.Background(), bucketName, objectName, nil)
 		if err != nil {
 			// Failed to create NewMultipartUpload, abort.
 			t.Fatalf("Minio %s : <ERROR>  %s", instanceType, err)
@@ -1524,7 +1831,7 @@ func testAPICompleteMultipartHandler(obj ObjectLayer, instanceType, bucketName s
 	}
 	// Iterating over creatPartCases to generate multipart chunks.
 	for _, part := range parts {
-		_, err = obj.PutObjectPart(part.bucketName, part.objName, part.uploadID, part.PartID, part.intputDataSize,
+		_, err = obj.PutObjectPart(context.Background(), part.bucketName, part.objName, part.uploadID, part.PartID, part.intputDataSize,
 			bytes.NewBufferString(part.inputReaderData), part.inputMd5, "")
 		if err != nil {
 			t.Fatalf("%s : %s", instanceType, err)
@@ -1838,7 +2145,7 @@ func testAPIAbortMultipartHandler(obj ObjectLayer, instanceType, bucketName stri
 
 	for i := 0; i < 2; i++ {
 		// initiate new multipart uploadID.
-		uploadID, err = obj.NewMultipartUpload(bucketName, objectName, nil)
+		uploadID, err = obj.NewMultipartUpload(context.Background(), bucketName, objectName, nil)
 		if err != nil {
 			// Failed to create NewMultipartUpload, abort.
 			t.Fatalf("Minio %s : <ERROR>  %s", instanceType, err)
@@ -1879,7 +2186,7 @@ func testAPIAbortMultipartHandler(obj ObjectLayer, instanceType, bucketName stri
 	}
 	// Iterating over createPartCases to generate multipart chunks.
 	for _, part := range parts {
-		_, err = obj.PutObjectPart(part.bucketName, part.objName, part.uploadID, part.PartID, part.intputDataSize,
+		_, err = obj.PutObjectPart(context.Background(), part.bucketName, part.objName, part.uploadID, part.PartID, part.intputDataSize,
 			bytes.NewBufferString(part.inputReaderData), part.inputMd5, "")
 		if err != nil {
 			t.Fatalf("%s : %s", instanceType, err)
@@ -2023,7 +2330,7 @@ func testAPIDeleteObjectHandler(obj ObjectLayer, instanceType, bucketName string
 	// iterate through the above set of inputs and upload the object.
 	for i, input := range putObjectInputs {
 		// uploading the object.
-		_, err = obj.PutObject(input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, "")
+		_, err = obj.PutObject(context.Background(), input.bucketName, input.objectName, input.contentLength, bytes.NewBuffer(input.textData), input.metaData, "")
 		// if object upload fails stop the test.
 		if err != nil {
 			t.Fatalf("Put Object case %d:  Error uploading object: <ERROR> %v", i+1, err)
@@ -2316,7 +2623,7 @@ func testAPIPutObjectPartHandler(obj ObjectLayer, instanceType, bucketName strin
 	// PutObjectPart API HTTP Handler has to be tested in isolation,
 	// that is without any other handler being registered,
 	// That's why NewMultipartUpload is initiated using ObjectLayer.
-	uploadID, err := obj.NewMultipartUpload(bucketName, testObject, nil)
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucketName, testObject, nil)
 	if err != nil {
 		// Failed to create NewMultipartUpload, abort.
 		t.Fatalf("Minio %s : <ERROR>  %s", instanceType, err)
@@ -2718,7 +3025,7 @@ func testAPIListObjectPartsHandler(obj ObjectLayer, instanceType, bucketName str
 	// PutObjectPart API HTTP Handler has to be tested in isolation,
 	// that is without any other handler being registered,
 	// That's why NewMultipartUpload is initiated using ObjectLayer.
-	uploadID, err := obj.NewMultipartUpload(bucketName, testObject, nil)
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucketName, testObject, nil)
 	if err != nil {
 		// Failed to create NewMultipartUpload, abort.
 		t.Fatalf("Minio %s : <ERROR>  %s", instanceType, err)
@@ -2727,7 +3034,7 @@ func testAPIListObjectPartsHandler(obj ObjectLayer, instanceType, bucketName str
 	uploadIDCopy := uploadID
 
 	// create an object Part, will be used to test list object parts.
-	_, err = obj.PutObjectPart(bucketName, testObject, uploadID, 1, int64(len("hello")), bytes.NewReader([]byte("hello")),
+	_, err = obj.PutObjectPart(context.Background(), bucketName, testObject, uploadID, 1, int64(len("hello")), bytes.NewReader([]byte("hello")),
 		"5d41402abc4b2a76b9719d911017c592", "")
 	if err != nil {
 		t.Fatalf("Minio %s : %s.", instanceType, err)
@@ -2918,3 +3225,429 @@ func testAPIListObjectPartsHandler(obj ObjectLayer, instanceType, bucketName str
 	// `ExecObjectLayerAPINilTest` sets the Object Layer to `nil` and calls the handler.
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
+
+// Wrapper for calling object handler tests with SSE-C headers for both XL multiple disks and single node setup.
+func TestAPIObjectHandlersSSECustomer(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIObjectHandlersSSECustomer, []string{
+		"NewMultipart", "CopyObjectPart", "PutObjectPart", "CompleteMultipart",
+		"CopyObject", "HeadObject", "GetObject", "PutObject",
+	})
+}
+
+func testAPIObjectHandlersSSECustomer(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// SSE-C is only accepted over TLS.
+	defer func(isSSL bool) { globalIsSSL = isSSL }(globalIsSSL)
+	globalIsSSL = true
+
+	key := bytes.Repeat([]byte{'a'}, 32)
+	wrongKey := bytes.Repeat([]byte{'b'}, 32)
+
+	// Sends a request with the given headers and returns the recorded response.
+	sendRequest := func(method, urlStr string, body []byte, header http.Header) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(body)), bytes.NewReader(body),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s: <ERROR> %v", instanceType, method, err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 10000)
+	objectName := "encrypted-object"
+	rec := sendRequest("PUT", getPutObjectURL("", bucketName, objectName), data, newSSECustomerHeader(key, ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if rec.Header().Get(amzSSECustomerAlgorithm) != sseCustomerAlgorithmAES256 {
+		t.Errorf("%s: Missing SSE-C response headers", instanceType)
+	}
+
+	// Only ciphertext is stored.
+	var stored bytes.Buffer
+	if err := obj.GetObject(context.Background(), bucketName, objectName, 0, sseEncryptedSize(int64(len(data))), &stored); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if bytes.Contains(stored.Bytes(), data[:64]) {
+		t.Errorf("%s: Object is stored in plaintext", instanceType)
+	}
+
+	testCases := []struct {
+		header             http.Header
+		rangeHeader        string
+		expectedRespStatus int
+		expectedContent    []byte
+	}{
+		// Test case - 1.
+		// Key is missing.
+		{nil, "", http.StatusBadRequest, nil},
+		// Test case - 2.
+		// Wrong key.
+		{newSSECustomerHeader(wrongKey, ""), "", http.StatusForbidden, nil},
+		// Test case - 3.
+		// Full object.
+		{newSSECustomerHeader(key, ""), "", http.StatusOK, data},
+		// Test case - 4.
+		// Range across packages.
+		{newSSECustomerHeader(key, ""), "bytes=65530-65545", http.StatusPartialContent, data[65530:65546]},
+		// Test case - 5.
+		// Suffix range.
+		{newSSECustomerHeader(key, ""), "bytes=-10", http.StatusPartialContent, data[len(data)-10:]},
+	}
+	for i, testCase := range testCases {
+		header := testCase.header
+		if testCase.rangeHeader != "" {
+			header.Set("Range", testCase.rangeHeader)
+		}
+		rec = sendRequest("GET", getGetObjectURL("", bucketName, objectName), nil, header)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+			continue
+		}
+		if testCase.expectedContent != nil && !bytes.Equal(rec.Body.Bytes(), testCase.expectedContent) {
+			t.Errorf("Test %d: %s: Unexpected content", i+1, instanceType)
+		}
+	}
+
+	// HEAD reports the size of the plaintext.
+	rec = sendRequest("HEAD", getHeadObjectURL("", bucketName, objectName), nil, newSSECustomerHeader(key, ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if rec.Header().Get("Content-Length") != strconv.Itoa(len(data)) {
+		t.Errorf("%s: Expected Content-Length %d, got %s", instanceType, len(data), rec.Header().Get("Content-Length"))
+	}
+	for k := range rec.Header() {
+		if strings.HasPrefix(k, reservedMetadataPrefix) {
+			t.Errorf("%s: Reserved metadata %s returned", instanceType, k)
+		}
+	}
+
+	// Copy into a plaintext object and encrypt it again with another key.
+	copyName := "decrypted-copy"
+	header := newSSECustomerHeader(key, amzSSECopySourcePrefix)
+	header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+objectName))
+	if rec = sendRequest("PUT", getCopyObjectURL("", bucketName, copyName), nil, header); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if rec = sendRequest("GET", getGetObjectURL("", bucketName, copyName), nil, nil); !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Errorf("%s: Unexpected content of the decrypted copy", instanceType)
+	}
+	encryptedName := "encrypted-copy"
+	header = newSSECustomerHeader(wrongKey, "")
+	header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+copyName))
+	if rec = sendRequest("PUT", getCopyObjectURL("", bucketName, encryptedName), nil, header); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if rec = sendRequest("GET", getGetObjectURL("", bucketName, encryptedName), nil, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	if rec = sendRequest("GET", getGetObjectURL("", bucketName, encryptedName), nil, newSSECustomerHeader(wrongKey, "")); !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Errorf("%s: Unexpected content of the encrypted copy", instanceType)
+	}
+
+	// In place copies keep the key of the object.
+	header = newSSECustomerHeader(key, "")
+	for k, v := range newSSECustomerHeader(wrongKey, amzSSECopySourcePrefix) {
+		header[k] = v
+	}
+	header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+encryptedName))
+	header.Set("X-Amz-Metadata-Directive", "REPLACE")
+	if rec = sendRequest("PUT", getCopyObjectURL("", bucketName, encryptedName), nil, header); rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	header = newSSECustomerHeader(wrongKey, "")
+	for k, v := range newSSECustomerHeader(wrongKey, amzSSECopySourcePrefix) {
+		header[k] = v
+	}
+	header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+encryptedName))
+	header.Set("X-Amz-Metadata-Directive", "REPLACE")
+	header.Set("X-Amz-Meta-Copied", "true")
+	if rec = sendRequest("PUT", getCopyObjectURL("", bucketName, encryptedName), nil, header); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	rec = sendRequest("GET", getGetObjectURL("", bucketName, encryptedName), nil, newSSECustomerHeader(wrongKey, ""))
+	if !bytes.Equal(rec.Body.Bytes(), data) || rec.Header().Get("X-Amz-Meta-Copied") != "true" {
+		t.Errorf("%s: Unexpected content of the object copied in place", instanceType)
+	}
+
+	// Multipart uploads, every part is encrypted separately.
+	multipartName := "encrypted-multipart"
+	rec = sendRequest("POST", getNewMultipartURL("", bucketName, multipartName), nil, newSSECustomerHeader(key, ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	initResponse := &InitiateMultipartUploadResponse{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), initResponse); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	uploadID := initResponse.UploadID
+
+	// Parts need the key of the upload.
+	part1 := bytes.Repeat([]byte{'x'}, 5*humanize.MiByte)
+	partURL := getPutObjectPartURL("", bucketName, multipartName, uploadID, "1")
+	if rec = sendRequest("PUT", partURL, part1, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+	if rec = sendRequest("PUT", partURL, part1, newSSECustomerHeader(wrongKey, "")); rec.Code != http.StatusForbidden {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, rec.Code)
+	}
+
+	var completeParts completeMultipartUpload
+	for i, part := range [][]byte{part1, data} {
+		partURL = getPutObjectPartURL("", bucketName, multipartName, uploadID, strconv.Itoa(i+1))
+		if rec = sendRequest("PUT", partURL, part, newSSECustomerHeader(key, "")); rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+		completeParts.Parts = append(completeParts.Parts, completePart{PartNumber: i + 1, ETag: rec.Header().Get("ETag")})
+	}
+	completeBytes, err := xml.Marshal(completeParts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = sendRequest("POST", getCompleteMultipartUploadURL("", bucketName, multipartName, uploadID), completeBytes, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+
+	// Range across the boundary of the parts.
+	header = newSSECustomerHeader(key, "")
+	header.Set("Range", fmt.Sprintf("bytes=%d-%d", len(part1)-10, len(part1)+9))
+	rec = sendRequest("GET", getGetObjectURL("", bucketName, multipartName), nil, header)
+	if rec.Code != http.StatusPartialContent {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusPartialContent, rec.Code)
+	}
+	expected := append(append([]byte{}, part1[len(part1)-10:]...), data[:10]...)
+	if !bytes.Equal(rec.Body.Bytes(), expected) {
+		t.Errorf("%s: Unexpected content of the multipart object", instanceType)
+	}
+
+	// Parts copied from an encrypted object are encrypted with the key
+	// of the upload.
+	copyMultipartName := "encrypted-copy-multipart"
+	rec = sendRequest("POST", getNewMultipartURL("", bucketName, copyMultipartName), nil, newSSECustomerHeader(wrongKey, ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	initResponse = &InitiateMultipartUploadResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), initResponse); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	header = newSSECustomerHeader(wrongKey, "")
+	for k, v := range newSSECustomerHeader(key, amzSSECopySourcePrefix) {
+		header[k] = v
+	}
+	header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+objectName))
+	header.Set("X-Amz-Copy-Source-Range", "bytes=65530-99999")
+	partURL = getPutObjectPartURL("", bucketName, copyMultipartName, initResponse.UploadID, "1")
+	if rec = sendRequest("PUT", partURL, nil, header); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	copyPartResponse := &CopyObjectPartResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), copyPartResponse); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	completeParts = completeMultipartUpload{Parts: []completePart{{PartNumber: 1, ETag: copyPartResponse.ETag}}}
+	if completeBytes, err = xml.Marshal(completeParts); err != nil {
+		t.Fatal(err)
+	}
+	rec = sendRequest("POST", getCompleteMultipartUploadURL("", bucketName, copyMultipartName, initResponse.UploadID), completeBytes, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	rec = sendRequest("GET", getGetObjectURL("", bucketName, copyMultipartName), nil, newSSECustomerHeader(wrongKey, ""))
+	if !bytes.Equal(rec.Body.Bytes(), data[65530:100000]) {
+		t.Errorf("%s: Unexpected content of the copied multipart object", instanceType)
+	}
+}
+
+// Wrapper for calling SSE-S3 and SSE-KMS object API handler tests for both XL multiple disks and FS single drive setup.
+func TestAPIObjectHandlersSSEKMS(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIObjectHandlersSSEKMS, []string{
+		"NewMultipart", "CopyObjectPart", "PutObjectPart", "CompleteMultipart",
+		"CopyObject", "HeadObject", "GetObject", "PutObject",
+	})
+}
+
+func testAPIObjectHandlersSSEKMS(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	defer func(kms KMS) {
+		globalKMS = kms
+	}(globalKMS)
+
+	// Sends a request with the given headers and returns the recorded response.
+	sendRequest := func(method, urlStr string, body []byte, header http.Header) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(body)), bytes.NewReader(body),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s: <ERROR> %v", instanceType, method, err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	sseHeader := func(algorithm, keyID string) http.Header {
+		header := make(http.Header)
+		header.Set(amzServerSideEncryption, algorithm)
+		if keyID != "" {
+			header.Set(amzServerSideEncryptionKMSKeyID, keyID)
+		}
+		return header
+	}
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 10000)
+	objectName := "sse-s3-object"
+
+	// Encryption needs a KMS.
+	globalKMS = nil
+	rec := sendRequest("PUT", getPutObjectURL("", bucketName, objectName), data, sseHeader(sseAlgorithmAES256, ""))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotImplemented, rec.Code)
+	}
+	kms, err := newMasterKeyKMS("my-key", bytes.Repeat([]byte{'m'}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalKMS = kms
+
+	putTestCases := []struct {
+		header             http.Header
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Unsupported algorithm.
+		{sseHeader("DES", ""), http.StatusBadRequest},
+		// Test case - 2.
+		// SSE-S3 does not take a master key.
+		{sseHeader(sseAlgorithmAES256, "my-key"), http.StatusBadRequest},
+		// Test case - 3.
+		// Unknown master key.
+		{sseHeader(sseAlgorithmKMS, "other-key"), http.StatusBadRequest},
+		// Test case - 4.
+		// SSE-S3.
+		{sseHeader(sseAlgorithmAES256, ""), http.StatusOK},
+	}
+	for i, testCase := range putTestCases {
+		rec = sendRequest("PUT", getPutObjectURL("", bucketName, objectName), data, testCase.header)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+	if rec.Header().Get(amzServerSideEncryption) != sseAlgorithmAES256 {
+		t.Errorf("%s: Missing SSE-S3 response headers", instanceType)
+	}
+
+	// Only ciphertext is stored, the master key and sealed key are kept
+	// for rotation.
+	objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, objectName)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if objInfo.UserDefined[sseKMSKeyIDMetaKey] != "my-key" || objInfo.UserDefined[sseKMSSealedKeyMetaKey] == "" {
+		t.Errorf("%s: Unexpected metadata %v", instanceType, objInfo.UserDefined)
+	}
+	var stored bytes.Buffer
+	if err = obj.GetObject(context.Background(), bucketName, objectName, 0, objInfo.Size, &stored); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if bytes.Contains(stored.Bytes(), data[:64]) {
+		t.Errorf("%s: Object is stored in plaintext", instanceType)
+	}
+
+	// Objects are decrypted transparently, SSE-C keys are rejected.
+	rec = sendRequest("GET", getGetObjectURL("", bucketName, objectName), nil, nil)
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Errorf("%s: Unexpected response %d", instanceType, rec.Code)
+	}
+	if rec.Header().Get(amzServerSideEncryption) != sseAlgorithmAES256 {
+		t.Errorf("%s: Missing SSE-S3 response headers", instanceType)
+	}
+	header := make(http.Header)
+	header.Set("Range", "bytes=65530-65545")
+	if rec = sendRequest("GET", getGetObjectURL("", bucketName, objectName), nil, header); !bytes.Equal(rec.Body.Bytes(), data[65530:65546]) {
+		t.Errorf("%s: Unexpected content of the range", instanceType)
+	}
+	rec = sendRequest("GET", getGetObjectURL("", bucketName, objectName), nil, newSSECustomerHeader(bytes.Repeat([]byte{'a'}, 32), ""))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	// Default encryption of the bucket applies to objects sent without
+	// encryption headers, copies included.
+	eCfg := &encryptionConfig{Rules: make([]encryptionRule, 1)}
+	eCfg.Rules[0].Default.SSEAlgorithm = sseAlgorithmKMS
+	globalBucketEncryption.Set(bucketName, eCfg)
+	defer globalBucketEncryption.Set(bucketName, nil)
+	plainName := "default-encrypted-object"
+	rec = sendRequest("PUT", getPutObjectURL("", bucketName, plainName), data, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if rec.Header().Get(amzServerSideEncryption) != sseAlgorithmKMS || rec.Header().Get(amzServerSideEncryptionKMSKeyID) != "my-key" {
+		t.Errorf("%s: Missing SSE-KMS response headers", instanceType)
+	}
+	copyName := "default-encrypted-copy"
+	header = make(http.Header)
+	header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+objectName))
+	if rec = sendRequest("PUT", getCopyObjectURL("", bucketName, copyName), nil, header); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	if objInfo, err = obj.GetObjectInfo(context.Background(), bucketName, copyName); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if objInfo.UserDefined[sseAlgorithmMetaKey] != sseAlgorithmKMS {
+		t.Errorf("%s: Copy is not encrypted by default", instanceType)
+	}
+	for _, name := range []string{plainName, copyName} {
+		if rec = sendRequest("GET", getGetObjectURL("", bucketName, name), nil, nil); !bytes.Equal(rec.Body.Bytes(), data) {
+			t.Errorf("%s: Unexpected content of %s", instanceType, name)
+		}
+	}
+
+	// Multipart uploads are encrypted with the key of the upload.
+	multipartName := "sse-kms-multipart"
+	rec = sendRequest("POST", getNewMultipartURL("", bucketName, multipartName), nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	initResponse := &InitiateMultipartUploadResponse{}
+	if err = xml.Unmarshal(rec.Body.Bytes(), initResponse); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	part1 := bytes.Repeat([]byte{'x'}, 5*humanize.MiByte)
+	var completeParts completeMultipartUpload
+	for i, part := range [][]byte{part1, data} {
+		partURL := getPutObjectPartURL("", bucketName, multipartName, initResponse.UploadID, strconv.Itoa(i+1))
+		if rec = sendRequest("PUT", partURL, part, nil); rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+		}
+		if rec.Header().Get(amzServerSideEncryption) != sseAlgorithmKMS {
+			t.Errorf("%s: Missing SSE-KMS response headers", instanceType)
+		}
+		completeParts.Parts = append(completeParts.Parts, completePart{PartNumber: i + 1, ETag: rec.Header().Get("ETag")})
+	}
+	completeBytes, err := xml.Marshal(completeParts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = sendRequest("POST", getCompleteMultipartUploadURL("", bucketName, multipartName, initResponse.UploadID), completeBytes, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusOK, rec.Code)
+	}
+	expected := append(append([]byte{}, part1...), data...)
+	if rec = sendRequest("GET", getGetObjectURL("", bucketName, multipartName), nil, nil); !bytes.Equal(rec.Body.Bytes(), expected) {
+		t.Errorf("%s: Unexpected content of the multipart object", instanceType)
+	}
+}
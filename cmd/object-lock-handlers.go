@@ -0,0 +1,459 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Object lock configurations, retentions and legal holds are tiny,
+// 64KiB is plenty.
+const maxObjectLockConfigSize = 64 * 1024
+
+// Reads the XML body of the object lock requests into v.
+func readObjectLockXML(r *http.Request, v interface{}) APIErrorCode {
+	// If Content-Length is unknown or zero, deny the request.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		return ErrMissingContentLength
+	}
+	if r.ContentLength > maxObjectLockConfigSize {
+		return ErrEntityTooLarge
+	}
+
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to read incoming body.")
+		return toAPIErrorCode(err)
+	}
+	if err := xml.Unmarshal(buffer.Bytes(), v); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to parse object lock XML.")
+		return ErrMalformedXML
+	}
+	return ErrNone
+}
+
+// PutBucketObjectLockConfigHandler - PUT Bucket object lock configuration
+// -----------------
+// This implementation of the PUT operation uses the object-lock
+// subresource to set the default retention of a bucket. Object lock
+// itself can only be enabled when the bucket is created.
+func (api objectAPIHandlers) PutBucketObjectLockConfigHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutBucketObjectLockConfiguration", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	if getBucketObjectLock(bucket) == nil {
+		writeErrorResponse(w, ErrObjectLockCannotBeEnabled, r.URL)
+		return
+	}
+
+	var lCfg objectLockConfig
+	if s3Error := readObjectLockXML(r, &lCfg); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	if s3Error := validateObjectLockConfig(lCfg); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err = globalBucketObjectLock.persistAndNotify(bucket, &lCfg, objectAPI); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to save object lock configuration.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketObjectLockConfigHandler - GET Bucket object lock configuration
+// -----------------
+// This implementation of the GET operation uses the object-lock
+// subresource to return the object lock configuration of a bucket.
+func (api objectAPIHandlers) GetBucketObjectLockConfigHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetBucketObjectLockConfiguration", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	_, err := objectAPI.GetBucketInfo(r.Context(), bucket)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to find bucket info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	lCfg, err := globalBucketObjectLock.read(bucket, objectAPI)
+	if err != nil {
+		if err != errNoSuchObjectLockConfig {
+			errorIfCtx(r.Context(), err, "Unable to read object lock configuration.")
+		}
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	configBytes, err := xml.Marshal(lCfg)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to marshal object lock configuration into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseXML(w, configBytes)
+}
+
+// Checks if a retention may replace the active retention of an object
+// version. Retention can always be extended, COMPLIANCE retention can
+// never be shortened and GOVERNANCE retention only by those who may
+// bypass it.
+func checkRetentionUpdate(meta map[string]string, retention objectRetention, retainUntil time.Time, bypassGovernance bool) APIErrorCode {
+	now := time.Now().UTC()
+	if meta[amzObjectLockMode] == "" {
+		return ErrNone
+	}
+	current, err := parseRetainUntilDate(meta[amzObjectLockRetainUntilDate])
+	if err == nil && !current.After(now) {
+		// Expired retention.
+		return ErrNone
+	}
+
+	extended := retention.Mode != "" && err == nil && !retainUntil.Before(current)
+	switch meta[amzObjectLockMode] {
+	case retentionCompliance:
+		if extended && retention.Mode == retentionCompliance {
+			return ErrNone
+		}
+	case retentionGovernance:
+		if extended {
+			return ErrNone
+		}
+		if bypassGovernance {
+			return ErrNone
+		}
+	}
+	return ErrObjectLocked
+}
+
+// PutObjectRetentionHandler - PUT Object retention
+// -----------------
+// This implementation of the PUT operation uses the retention
+// subresource to set the retention of an object version.
+func (api objectAPIHandlers) PutObjectRetentionHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObjectRetention", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Shortening or removing GOVERNANCE retention needs the permission
+	// to bypass it.
+	bypassGovernance := isBypassGovernanceRetention(r.Header)
+	if bypassGovernance {
+		if s3Error := checkRequestAuthType(r, bucket, "s3:BypassGovernanceRetention", serverConfig.GetRegion()); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	}
+
+	if getBucketObjectLock(bucket) == nil {
+		writeErrorResponse(w, ErrInvalidBucketObjectLockConfiguration, r.URL)
+		return
+	}
+
+	var retention objectRetention
+	if s3Error := readObjectLockXML(r, &retention); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Without mode and date the retention is removed.
+	var retainUntil time.Time
+	if retention.Mode != "" || retention.RetainUntilDate != "" {
+		if !isValidRetentionMode(retention.Mode) {
+			writeErrorResponse(w, ErrInvalidObjectLockMode, r.URL)
+			return
+		}
+		var err error
+		if retainUntil, err = parseRetainUntilDate(retention.RetainUntilDate); err != nil {
+			writeErrorResponse(w, ErrInvalidRetainUntilDate, r.URL)
+			return
+		}
+		if !retainUntil.After(time.Now().UTC()) {
+			writeErrorResponse(w, ErrPastObjectLockRetainDate, r.URL)
+			return
+		}
+	}
+
+	// Lock the object before updating its retention.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.GetObjectVersionInfo(r.Context(), bucket, object, versionID)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Delete markers can not be retained.
+	if objInfo.DeleteMarker {
+		setVersionHeaders(w, objInfo)
+		writeErrorResponse(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	if s3Error := checkRetentionUpdate(objInfo.UserDefined, retention, retainUntil, bypassGovernance); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	updates := map[string]string{
+		amzObjectLockMode:            "",
+		amzObjectLockRetainUntilDate: "",
+	}
+	if retention.Mode != "" {
+		updates[amzObjectLockMode] = retention.Mode
+		updates[amzObjectLockRetainUntilDate] = retainUntil.UTC().Format(timeFormatAMZLong)
+	}
+	objInfo, err = objectAPI.UpdateObjectMetadata(r.Context(), bucket, object, versionID, updates)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to update object retention.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	setVersionHeaders(w, objInfo)
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetObjectRetentionHandler - GET Object retention
+// -----------------
+// This implementation of the GET operation uses the retention
+// subresource to return the retention of an object version.
+func (api objectAPIHandlers) GetObjectRetentionHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObjectRetention", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if getBucketObjectLock(bucket) == nil {
+		writeErrorResponse(w, ErrInvalidBucketObjectLockConfiguration, r.URL)
+		return
+	}
+
+	// Lock the object before reading its retention.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.GetObjectVersionInfo(r.Context(), bucket, object, versionID)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	if objInfo.UserDefined[amzObjectLockMode] == "" {
+		setVersionHeaders(w, objInfo)
+		writeErrorResponse(w, ErrNoSuchObjectLockConfiguration, r.URL)
+		return
+	}
+
+	retentionBytes, err := xml.Marshal(objectRetention{
+		Mode:            objInfo.UserDefined[amzObjectLockMode],
+		RetainUntilDate: objInfo.UserDefined[amzObjectLockRetainUntilDate],
+	})
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to marshal object retention into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	setVersionHeaders(w, objInfo)
+	writeSuccessResponseXML(w, retentionBytes)
+}
+
+// PutObjectLegalHoldHandler - PUT Object legal hold
+// -----------------
+// This implementation of the PUT operation uses the legal-hold
+// subresource to place or release a legal hold on an object version.
+func (api objectAPIHandlers) PutObjectLegalHoldHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObjectLegalHold", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if getBucketObjectLock(bucket) == nil {
+		writeErrorResponse(w, ErrInvalidBucketObjectLockConfiguration, r.URL)
+		return
+	}
+
+	var legalHold objectLegalHold
+	if s3Error := readObjectLockXML(r, &legalHold); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	if legalHold.Status != legalHoldOn && legalHold.Status != legalHoldOff {
+		writeErrorResponse(w, ErrInvalidObjectLockLegalHold, r.URL)
+		return
+	}
+
+	// Lock the object before updating its legal hold.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.UpdateObjectMetadata(r.Context(), bucket, object, versionID, map[string]string{
+		amzObjectLockLegalHold: legalHold.Status,
+	})
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to update object legal hold.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	setVersionHeaders(w, objInfo)
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetObjectLegalHoldHandler - GET Object legal hold
+// -----------------
+// This implementation of the GET operation uses the legal-hold
+// subresource to return the legal hold state of an object version.
+func (api objectAPIHandlers) GetObjectLegalHoldHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObjectLegalHold", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if getBucketObjectLock(bucket) == nil {
+		writeErrorResponse(w, ErrInvalidBucketObjectLockConfiguration, r.URL)
+		return
+	}
+
+	// Lock the object before reading its legal hold.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.GetObjectVersionInfo(r.Context(), bucket, object, versionID)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	if objInfo.UserDefined[amzObjectLockLegalHold] == "" {
+		setVersionHeaders(w, objInfo)
+		writeErrorResponse(w, ErrNoSuchObjectLockConfiguration, r.URL)
+		return
+	}
+
+	legalHoldBytes, err := xml.Marshal(objectLegalHold{
+		Status: objInfo.UserDefined[amzObjectLockLegalHold],
+	})
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to marshal object legal hold into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	setVersionHeaders(w, objInfo)
+	writeSuccessResponseXML(w, legalHoldBytes)
+}
@@ -0,0 +1,183 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Wrapper for calling object lock handler tests for both XL multiple disks and single node setup.
+func TestObjectLockHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testObjectLockHandlers, []string{
+		"PutBucketObjectLockConfig",
+		"GetBucketObjectLockConfig",
+		"PutObjectRetention",
+		"GetObjectRetention",
+		"PutObjectLegalHold",
+		"GetObjectLegalHold",
+		"PutObject",
+		"DeleteObject",
+		"HeadObject",
+		"PutBucket",
+	})
+}
+
+func testObjectLockHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Sends a request and returns the recorded response.
+	sendRequest := func(method, urlStr, body string, header http.Header) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(body)), bytes.NewReader([]byte(body)),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, urlStr, err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	expectStatus := func(rec *httptest.ResponseRecorder, status int) {
+		if rec.Code != status {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`: %s", instanceType, status, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Object lock configurations reach the in-memory state through the
+	// peers, the local node included.
+	initGlobalS3Peers(nil)
+
+	// Object lock can not be enabled on existing buckets.
+	lockConfig := `<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled></ObjectLockConfiguration>`
+	expectStatus(sendRequest("PUT", getBucketConfigURL("", bucketName, "object-lock"), lockConfig, nil), http.StatusConflict)
+	expectStatus(sendRequest("GET", getBucketConfigURL("", bucketName, "object-lock"), "", nil), http.StatusNotFound)
+
+	// Retention headers are refused on buckets without object lock.
+	future := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+	header := http.Header{}
+	header.Set(amzObjectLockMode, retentionGovernance)
+	header.Set(amzObjectLockRetainUntilDate, future)
+	expectStatus(sendRequest("PUT", getPutObjectURL("", bucketName, "object"), "hello", header), http.StatusBadRequest)
+
+	// Bucket created with object lock.
+	lockedBucket := getRandomBucketName()
+	lockHeader := http.Header{}
+	lockHeader.Set(amzBucketObjectLockEnabled, "true")
+	expectStatus(sendRequest("PUT", getMakeBucketURL("", lockedBucket), "", lockHeader), http.StatusOK)
+	defer globalBucketObjectLock.Set(lockedBucket, nil)
+	rec := sendRequest("GET", getBucketConfigURL("", lockedBucket, "object-lock"), "", nil)
+	expectStatus(rec, http.StatusOK)
+	lCfg := objectLockConfig{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &lCfg); err != nil || lCfg.ObjectLockEnabled != objectLockEnabled || lCfg.Rule != nil {
+		t.Errorf("%s: Unexpected object lock configuration %s", instanceType, rec.Body.String())
+	}
+
+	// Object uploaded with retention.
+	expectStatus(sendRequest("PUT", getPutObjectURL("", lockedBucket, "object"), "hello", header), http.StatusOK)
+	rec = sendRequest("HEAD", getHeadObjectURL("", lockedBucket, "object"), "", nil)
+	expectStatus(rec, http.StatusOK)
+	if rec.Header().Get(amzObjectLockMode) != retentionGovernance {
+		t.Errorf("%s: Expected the object lock mode to be returned, got %v", instanceType, rec.Header())
+	}
+	rec = sendRequest("GET", getObjectRetentionURL("", lockedBucket, "object", ""), "", nil)
+	expectStatus(rec, http.StatusOK)
+	retention := objectRetention{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &retention); err != nil || retention.Mode != retentionGovernance {
+		t.Errorf("%s: Unexpected retention %s", instanceType, rec.Body.String())
+	}
+
+	// Retained objects can not be removed or replaced.
+	expectStatus(sendRequest("DELETE", getDeleteObjectURL("", lockedBucket, "object"), "", nil), http.StatusForbidden)
+	expectStatus(sendRequest("PUT", getPutObjectURL("", lockedBucket, "object"), "world", nil), http.StatusForbidden)
+
+	testCases := []struct {
+		body               string
+		bypass             bool
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Extending GOVERNANCE retention.
+		{`<Retention><Mode>GOVERNANCE</Mode><RetainUntilDate>` + time.Now().UTC().Add(2*time.Hour).Format(time.RFC3339) + `</RetainUntilDate></Retention>`, false, http.StatusOK},
+		// Test case - 2.
+		// Shortening GOVERNANCE retention.
+		{`<Retention><Mode>GOVERNANCE</Mode><RetainUntilDate>` + future + `</RetainUntilDate></Retention>`, false, http.StatusForbidden},
+		// Test case - 3.
+		// Shortening GOVERNANCE retention with bypass.
+		{`<Retention><Mode>GOVERNANCE</Mode><RetainUntilDate>` + future + `</RetainUntilDate></Retention>`, true, http.StatusOK},
+		// Test case - 4.
+		// Retain until date in the past.
+		{`<Retention><Mode>GOVERNANCE</Mode><RetainUntilDate>2001-01-01T00:00:00Z</RetainUntilDate></Retention>`, false, http.StatusBadRequest},
+		// Test case - 5.
+		// Unknown mode.
+		{`<Retention><Mode>FOREVER</Mode><RetainUntilDate>` + future + `</RetainUntilDate></Retention>`, false, http.StatusBadRequest},
+		// Test case - 6.
+		// Malformed retention.
+		{`<Retention>`, false, http.StatusBadRequest},
+		// Test case - 7.
+		// Switching to COMPLIANCE.
+		{`<Retention><Mode>COMPLIANCE</Mode><RetainUntilDate>` + future + `</RetainUntilDate></Retention>`, false, http.StatusOK},
+		// Test case - 8.
+		// COMPLIANCE retention can not be removed, not even with bypass.
+		{`<Retention></Retention>`, true, http.StatusForbidden},
+	}
+	for i, testCase := range testCases {
+		header = http.Header{}
+		if testCase.bypass {
+			header.Set(amzBypassGovernanceRetention, "true")
+		}
+		rec = sendRequest("PUT", getObjectRetentionURL("", lockedBucket, "object", ""), testCase.body, header)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Legal hold on an object without retention.
+	expectStatus(sendRequest("PUT", getPutObjectURL("", lockedBucket, "held"), "hello", nil), http.StatusOK)
+	expectStatus(sendRequest("GET", getObjectLegalHoldURL("", lockedBucket, "held", ""), "", nil), http.StatusNotFound)
+	expectStatus(sendRequest("PUT", getObjectLegalHoldURL("", lockedBucket, "held", ""), `<LegalHold><Status>MAYBE</Status></LegalHold>`, nil), http.StatusBadRequest)
+	expectStatus(sendRequest("PUT", getObjectLegalHoldURL("", lockedBucket, "held", ""), `<LegalHold><Status>ON</Status></LegalHold>`, nil), http.StatusOK)
+	rec = sendRequest("GET", getObjectLegalHoldURL("", lockedBucket, "held", ""), "", nil)
+	expectStatus(rec, http.StatusOK)
+	legalHold := objectLegalHold{}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &legalHold); err != nil || legalHold.Status != legalHoldOn {
+		t.Errorf("%s: Unexpected legal hold %s", instanceType, rec.Body.String())
+	}
+	expectStatus(sendRequest("DELETE", getDeleteObjectURL("", lockedBucket, "held"), "", nil), http.StatusForbidden)
+	expectStatus(sendRequest("PUT", getObjectLegalHoldURL("", lockedBucket, "held", ""), `<LegalHold><Status>OFF</Status></LegalHold>`, nil), http.StatusOK)
+	expectStatus(sendRequest("DELETE", getDeleteObjectURL("", lockedBucket, "held"), "", nil), http.StatusNoContent)
+
+	// Default retention applies to new objects.
+	defaultConfig := `<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled><Rule><DefaultRetention><Mode>GOVERNANCE</Mode><Days>1</Days></DefaultRetention></Rule></ObjectLockConfiguration>`
+	expectStatus(sendRequest("PUT", getBucketConfigURL("", lockedBucket, "object-lock"), `<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled><Rule><DefaultRetention><Mode>GOVERNANCE</Mode><Days>1</Days><Years>1</Years></DefaultRetention></Rule></ObjectLockConfiguration>`, nil), http.StatusBadRequest)
+	expectStatus(sendRequest("PUT", getBucketConfigURL("", lockedBucket, "object-lock"), defaultConfig, nil), http.StatusOK)
+	expectStatus(sendRequest("PUT", getPutObjectURL("", lockedBucket, "defaulted"), "hello", nil), http.StatusOK)
+	expectStatus(sendRequest("DELETE", getDeleteObjectURL("", lockedBucket, "defaulted"), "", nil), http.StatusForbidden)
+	expectStatus(sendRequest("GET", getObjectRetentionURL("", lockedBucket, "defaulted", ""), "", nil), http.StatusOK)
+
+	// GOVERNANCE retention is bypassed on removal by those allowed to.
+	header = http.Header{}
+	header.Set(amzBypassGovernanceRetention, "true")
+	expectStatus(sendRequest("DELETE", getDeleteObjectURL("", lockedBucket, "defaulted"), "", header), http.StatusNoContent)
+	expectStatus(sendRequest("HEAD", getHeadObjectURL("", lockedBucket, "defaulted"), "", nil), http.StatusNotFound)
+}
@@ -0,0 +1,264 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// Bucket object lock config name.
+	bucketObjectLockConfig = "object-lock.xml"
+
+	// Object lock is enabled on a bucket when it is created, it can
+	// never be disabled again.
+	objectLockEnabled = "Enabled"
+
+	// Retention modes, objects under COMPLIANCE retention can not be
+	// removed by anyone until their retention expires. GOVERNANCE
+	// retention can be shortened or removed by users who may bypass it.
+	retentionGovernance = "GOVERNANCE"
+	retentionCompliance = "COMPLIANCE"
+
+	// Legal hold states, a legal hold has no expiry.
+	legalHoldOn  = "ON"
+	legalHoldOff = "OFF"
+)
+
+// Object lock request headers, the object lock state of an object is
+// saved with its metadata under the same names and returned as is on
+// GET and HEAD.
+const (
+	amzBucketObjectLockEnabled   = "X-Amz-Bucket-Object-Lock-Enabled"
+	amzObjectLockMode            = "X-Amz-Object-Lock-Mode"
+	amzObjectLockRetainUntilDate = "X-Amz-Object-Lock-Retain-Until-Date"
+	amzObjectLockLegalHold       = "X-Amz-Object-Lock-Legal-Hold"
+	amzBypassGovernanceRetention = "X-Amz-Bypass-Governance-Retention"
+)
+
+// errNoSuchObjectLockConfig - object lock is not enabled on the bucket.
+var errNoSuchObjectLockConfig = errors.New("Object Lock configuration does not exist for this bucket")
+
+// objectLockConfig - represents the object lock configuration of a
+// bucket, optionally with a default retention for new objects.
+type objectLockConfig struct {
+	XMLName           xml.Name        `xml:"ObjectLockConfiguration"`
+	ObjectLockEnabled string          `xml:"ObjectLockEnabled"`
+	Rule              *objectLockRule `xml:"Rule,omitempty"`
+}
+
+// objectLockRule - default retention applied to new objects.
+type objectLockRule struct {
+	DefaultRetention struct {
+		Mode  string `xml:"Mode"`
+		Days  int    `xml:"Days,omitempty"`
+		Years int    `xml:"Years,omitempty"`
+	} `xml:"DefaultRetention"`
+}
+
+// objectRetention - retention of an object version as set by
+// PutObjectRetention, an empty retention removes it.
+type objectRetention struct {
+	XMLName         xml.Name `xml:"Retention"`
+	Mode            string   `xml:"Mode,omitempty"`
+	RetainUntilDate string   `xml:"RetainUntilDate,omitempty"`
+}
+
+// objectLegalHold - legal hold of an object version.
+type objectLegalHold struct {
+	XMLName xml.Name `xml:"LegalHold"`
+	Status  string   `xml:"Status"`
+}
+
+// Checks if a retention mode is known.
+func isValidRetentionMode(mode string) bool {
+	return mode == retentionGovernance || mode == retentionCompliance
+}
+
+// Validates bucket object lock configuration.
+func validateObjectLockConfig(lCfg objectLockConfig) APIErrorCode {
+	if lCfg.ObjectLockEnabled != objectLockEnabled {
+		return ErrMalformedXML
+	}
+	if lCfg.Rule == nil {
+		return ErrNone
+	}
+	retention := lCfg.Rule.DefaultRetention
+	if !isValidRetentionMode(retention.Mode) {
+		return ErrInvalidObjectLockMode
+	}
+	// Exactly one of days and years is set.
+	if retention.Days < 0 || retention.Years < 0 || (retention.Days == 0) == (retention.Years == 0) {
+		return ErrMalformedXML
+	}
+	return ErrNone
+}
+
+// Parses a retain until date, as sent by clients and as saved.
+func parseRetainUntilDate(date string) (time.Time, error) {
+	return time.Parse(time.RFC3339, date)
+}
+
+// isObjectRetained - checks if the object lock state saved in the
+// metadata of an object version keeps it from being removed or
+// replaced at the given time. GOVERNANCE retention does not keep
+// requests allowed to bypass it.
+func isObjectRetained(meta map[string]string, now time.Time, bypassGovernance bool) bool {
+	if meta[amzObjectLockLegalHold] == legalHoldOn {
+		return true
+	}
+	if meta[amzObjectLockMode] == "" {
+		return false
+	}
+	if meta[amzObjectLockMode] == retentionGovernance && bypassGovernance {
+		return false
+	}
+	retainUntil, err := parseRetainUntilDate(meta[amzObjectLockRetainUntilDate])
+	if err != nil {
+		// Broken dates never release an object.
+		return true
+	}
+	return retainUntil.After(now)
+}
+
+// checkObjectRetention - refuses to remove or replace a version of an
+// object while it is retained. An empty versionID refers to the version
+// an upload or a delete without versionID replaces: the current version
+// on buckets without versioning, the null version on suspended buckets
+// and none on enabled buckets, where a new version is added instead.
+// Only buckets with object lock are looked at.
+func checkObjectRetention(obj versionedObjects, bucket, object, versionID string, bypassGovernance bool) error {
+	if getBucketObjectLock(bucket) == nil {
+		return nil
+	}
+	if versionID == "" {
+		switch getBucketVersioningStatus(bucket) {
+		case versioningEnabled:
+			return nil
+		case versioningSuspended:
+			versionID = nullVersionID
+		}
+	}
+	objInfo, err := getObjectVersionInfo(obj, bucket, object, versionID)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrVersionNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if isObjectRetained(objInfo.UserDefined, time.Now().UTC(), bypassGovernance) {
+		return traceError(ObjectLocked{Bucket: bucket, Object: object, VersionID: objInfo.VersionID})
+	}
+	return nil
+}
+
+// removeObjectLockMetadata - removes the object lock state of another
+// object from metadata, it is never copied along.
+func removeObjectLockMetadata(metadata map[string]string) {
+	delete(metadata, amzObjectLockMode)
+	delete(metadata, amzObjectLockRetainUntilDate)
+	delete(metadata, amzObjectLockLegalHold)
+}
+
+// setObjectLockMetadata - saves the object lock state requested by the
+// object lock headers of an upload into its metadata. Without explicit
+// retention the default retention of the bucket applies.
+func setObjectLockMetadata(header http.Header, bucket string, metadata map[string]string) APIErrorCode {
+	mode := header.Get(amzObjectLockMode)
+	retainUntilDate := header.Get(amzObjectLockRetainUntilDate)
+	legalHold := header.Get(amzObjectLockLegalHold)
+
+	lCfg := getBucketObjectLock(bucket)
+	if lCfg == nil {
+		if mode != "" || retainUntilDate != "" || legalHold != "" {
+			return ErrInvalidBucketObjectLockConfiguration
+		}
+		return ErrNone
+	}
+
+	if (mode == "") != (retainUntilDate == "") {
+		return ErrObjectLockInvalidHeaders
+	}
+
+	now := time.Now().UTC()
+	if mode != "" {
+		if !isValidRetentionMode(mode) {
+			return ErrInvalidObjectLockMode
+		}
+		retainUntil, err := parseRetainUntilDate(retainUntilDate)
+		if err != nil {
+			return ErrInvalidRetainUntilDate
+		}
+		if !retainUntil.After(now) {
+			return ErrPastObjectLockRetainDate
+		}
+		metadata[amzObjectLockMode] = mode
+		metadata[amzObjectLockRetainUntilDate] = retainUntil.UTC().Format(timeFormatAMZLong)
+	} else if lCfg.Rule != nil {
+		retention := lCfg.Rule.DefaultRetention
+		metadata[amzObjectLockMode] = retention.Mode
+		metadata[amzObjectLockRetainUntilDate] = now.AddDate(retention.Years, 0, retention.Days).Format(timeFormatAMZLong)
+	}
+
+	if legalHold != "" {
+		if legalHold != legalHoldOn && legalHold != legalHoldOff {
+			return ErrInvalidObjectLockLegalHold
+		}
+		metadata[amzObjectLockLegalHold] = legalHold
+	}
+	return ErrNone
+}
+
+// bypassGovernanceKey - context key of requests allowed to bypass
+// GOVERNANCE retention.
+type bypassGovernanceKey struct{}
+
+// Returns a context of a request allowed to bypass GOVERNANCE retention.
+func contextWithBypassGovernance(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassGovernanceKey{}, true)
+}
+
+// Checks if the request of a context may bypass GOVERNANCE retention.
+func isGovernanceBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassGovernanceKey{}).(bool)
+	return bypass
+}
+
+// isBypassGovernanceRetention - checks if a request asks to bypass
+// GOVERNANCE retention.
+func isBypassGovernanceRetention(header http.Header) bool {
+	return strings.EqualFold(header.Get(amzBypassGovernanceRetention), "true")
+}
+
+// Variable represents bucket object lock configurations in memory,
+// looked up by the object layer on every operation which replaces or
+// removes an object.
+var globalBucketObjectLock = newBucketConfig(bucketObjectLockConfig, "object lock", errNoSuchObjectLockConfig, func() interface{} {
+	return &objectLockConfig{}
+})
+
+// getBucketObjectLock - returns the object lock configuration of a
+// bucket, nil if object lock is not enabled on it.
+func getBucketObjectLock(bucket string) *objectLockConfig {
+	lCfg, _ := globalBucketObjectLock.Get(bucket).(*objectLockConfig)
+	return lCfg
+}
@@ -0,0 +1,335 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Tests validating bucket object lock configurations.
+func TestValidateObjectLockConfig(t *testing.T) {
+	rule := func(mode string, days, years int) *objectLockRule {
+		r := &objectLockRule{}
+		r.DefaultRetention.Mode = mode
+		r.DefaultRetention.Days = days
+		r.DefaultRetention.Years = years
+		return r
+	}
+	testCases := []struct {
+		lCfg        objectLockConfig
+		expectedErr APIErrorCode
+	}{
+		// Test case - 1.
+		// Object lock without default retention.
+		{objectLockConfig{ObjectLockEnabled: objectLockEnabled}, ErrNone},
+		// Test case - 2.
+		// Default retention in days.
+		{objectLockConfig{ObjectLockEnabled: objectLockEnabled, Rule: rule(retentionGovernance, 1, 0)}, ErrNone},
+		// Test case - 3.
+		// Default retention in years.
+		{objectLockConfig{ObjectLockEnabled: objectLockEnabled, Rule: rule(retentionCompliance, 0, 1)}, ErrNone},
+		// Test case - 4.
+		// Object lock can not be disabled.
+		{objectLockConfig{ObjectLockEnabled: "Disabled"}, ErrMalformedXML},
+		// Test case - 5.
+		// Unknown mode.
+		{objectLockConfig{ObjectLockEnabled: objectLockEnabled, Rule: rule("LEGAL", 1, 0)}, ErrInvalidObjectLockMode},
+		// Test case - 6.
+		// Both days and years.
+		{objectLockConfig{ObjectLockEnabled: objectLockEnabled, Rule: rule(retentionGovernance, 1, 1)}, ErrMalformedXML},
+		// Test case - 7.
+		// Neither days nor years.
+		{objectLockConfig{ObjectLockEnabled: objectLockEnabled, Rule: rule(retentionGovernance, 0, 0)}, ErrMalformedXML},
+		// Test case - 8.
+		// Negative period.
+		{objectLockConfig{ObjectLockEnabled: objectLockEnabled, Rule: rule(retentionGovernance, -1, 0)}, ErrMalformedXML},
+	}
+	for i, testCase := range testCases {
+		if err := validateObjectLockConfig(testCase.lCfg); err != testCase.expectedErr {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+	}
+}
+
+// Tests deciding whether an object version is retained.
+func TestIsObjectRetained(t *testing.T) {
+	now := time.Now().UTC()
+	future := now.Add(time.Hour).Format(timeFormatAMZLong)
+	past := now.Add(-time.Hour).Format(timeFormatAMZLong)
+	testCases := []struct {
+		meta             map[string]string
+		bypassGovernance bool
+		retained         bool
+	}{
+		// Test case - 1.
+		// No object lock state.
+		{map[string]string{}, false, false},
+		// Test case - 2.
+		// Active retention.
+		{map[string]string{amzObjectLockMode: retentionGovernance, amzObjectLockRetainUntilDate: future}, false, true},
+		// Test case - 3.
+		// Expired retention.
+		{map[string]string{amzObjectLockMode: retentionCompliance, amzObjectLockRetainUntilDate: past}, false, false},
+		// Test case - 4.
+		// Legal hold.
+		{map[string]string{amzObjectLockLegalHold: legalHoldOn}, false, true},
+		// Test case - 5.
+		// Released legal hold.
+		{map[string]string{amzObjectLockLegalHold: legalHoldOff}, false, false},
+		// Test case - 6.
+		// Legal hold outlasts an expired retention.
+		{map[string]string{amzObjectLockMode: retentionGovernance, amzObjectLockRetainUntilDate: past, amzObjectLockLegalHold: legalHoldOn}, false, true},
+		// Test case - 7.
+		// Broken date.
+		{map[string]string{amzObjectLockMode: retentionGovernance, amzObjectLockRetainUntilDate: "tomorrow"}, false, true},
+		// Test case - 8.
+		// Bypassed GOVERNANCE retention.
+		{map[string]string{amzObjectLockMode: retentionGovernance, amzObjectLockRetainUntilDate: future}, true, false},
+		// Test case - 9.
+		// COMPLIANCE retention can't be bypassed.
+		{map[string]string{amzObjectLockMode: retentionCompliance, amzObjectLockRetainUntilDate: future}, true, true},
+		// Test case - 10.
+		// Legal hold can't be bypassed.
+		{map[string]string{amzObjectLockMode: retentionGovernance, amzObjectLockRetainUntilDate: future, amzObjectLockLegalHold: legalHoldOn}, true, true},
+	}
+	for i, testCase := range testCases {
+		if retained := isObjectRetained(testCase.meta, now, testCase.bypassGovernance); retained != testCase.retained {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.retained, retained)
+		}
+	}
+}
+
+// Tests saving the object lock headers of uploads into metadata.
+func TestSetObjectLockMetadata(t *testing.T) {
+	defaultRule := &objectLockRule{}
+	defaultRule.DefaultRetention.Mode = retentionCompliance
+	defaultRule.DefaultRetention.Days = 2
+	globalBucketObjectLock.Set("locked", &objectLockConfig{ObjectLockEnabled: objectLockEnabled})
+	defer globalBucketObjectLock.Set("locked", nil)
+	globalBucketObjectLock.Set("default", &objectLockConfig{ObjectLockEnabled: objectLockEnabled, Rule: defaultRule})
+	defer globalBucketObjectLock.Set("default", nil)
+
+	future := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+	past := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+	testCases := []struct {
+		bucket       string
+		headers      map[string]string
+		expectedErr  APIErrorCode
+		expectedMode string
+	}{
+		// Test case - 1.
+		// No headers on a bucket without object lock.
+		{"plain", nil, ErrNone, ""},
+		// Test case - 2.
+		// Headers on a bucket without object lock.
+		{"plain", map[string]string{amzObjectLockLegalHold: legalHoldOn}, ErrInvalidBucketObjectLockConfiguration, ""},
+		// Test case - 3.
+		// Explicit retention.
+		{"locked", map[string]string{amzObjectLockMode: retentionGovernance, amzObjectLockRetainUntilDate: future}, ErrNone, retentionGovernance},
+		// Test case - 4.
+		// Mode without date.
+		{"locked", map[string]string{amzObjectLockMode: retentionGovernance}, ErrObjectLockInvalidHeaders, ""},
+		// Test case - 5.
+		// Date in the past.
+		{"locked", map[string]string{amzObjectLockMode: retentionGovernance, amzObjectLockRetainUntilDate: past}, ErrPastObjectLockRetainDate, ""},
+		// Test case - 6.
+		// Unparsable date.
+		{"locked", map[string]string{amzObjectLockMode: retentionGovernance, amzObjectLockRetainUntilDate: "tomorrow"}, ErrInvalidRetainUntilDate, ""},
+		// Test case - 7.
+		// Unknown mode.
+		{"locked", map[string]string{amzObjectLockMode: "FOREVER", amzObjectLockRetainUntilDate: future}, ErrInvalidObjectLockMode, ""},
+		// Test case - 8.
+		// Invalid legal hold.
+		{"locked", map[string]string{amzObjectLockLegalHold: "MAYBE"}, ErrInvalidObjectLockLegalHold, ""},
+		// Test case - 9.
+		// Default retention of the bucket.
+		{"default", nil, ErrNone, retentionCompliance},
+		// Test case - 10.
+		// Explicit retention wins over the default.
+		{"default", map[string]string{amzObjectLockMode: retentionGovernance, amzObjectLockRetainUntilDate: future}, ErrNone, retentionGovernance},
+	}
+	for i, testCase := range testCases {
+		header := http.Header{}
+		for k, v := range testCase.headers {
+			header.Set(k, v)
+		}
+		metadata := make(map[string]string)
+		if err := setObjectLockMetadata(header, testCase.bucket, metadata); err != testCase.expectedErr {
+			t.Fatalf("Test %d: Expected %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if metadata[amzObjectLockMode] != testCase.expectedMode {
+			t.Errorf("Test %d: Expected mode %q, got %q", i+1, testCase.expectedMode, metadata[amzObjectLockMode])
+		}
+		if testCase.expectedMode != "" && !isObjectRetained(metadata, time.Now().UTC(), false) {
+			t.Errorf("Test %d: Expected the object to be retained", i+1)
+		}
+	}
+}
+
+// Wrapper for calling object lock tests for both XL multiple disks and single node setup.
+func TestObjectLockRetention(t *testing.T) {
+	ExecObjectLayerTest(t, testObjectLockRetention)
+}
+
+// Tests retained objects are neither removed nor replaced.
+func testObjectLockRetention(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "locked-bucket"
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	globalBucketObjectLock.Set(bucket, &objectLockConfig{ObjectLockEnabled: objectLockEnabled})
+	defer globalBucketObjectLock.Set(bucket, nil)
+
+	retainUntil := time.Now().UTC().Add(time.Hour).Format(timeFormatAMZLong)
+	metadata := map[string]string{
+		amzObjectLockMode:            retentionGovernance,
+		amzObjectLockRetainUntilDate: retainUntil,
+	}
+	if _, err := obj.PutObject(context.Background(), bucket, "retained", 5, bytes.NewBufferString("hello"), metadata, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	// The object lock state is returned with the object.
+	objInfo, err := obj.GetObjectInfo(context.Background(), bucket, "retained")
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if objInfo.UserDefined[amzObjectLockMode] != retentionGovernance || objInfo.UserDefined[amzObjectLockRetainUntilDate] != retainUntil {
+		t.Errorf("%s: Unexpected object lock state %v", instanceType, objInfo.UserDefined)
+	}
+
+	// Retained objects are neither removed nor replaced.
+	if err = obj.DeleteObject(context.Background(), bucket, "retained"); !isErrObjectLocked(err) {
+		t.Errorf("%s: Expected object locked, got %v", instanceType, err)
+	}
+	if _, err = obj.PutObject(context.Background(), bucket, "retained", 5, bytes.NewBufferString("world"), nil, ""); !isErrObjectLocked(err) {
+		t.Errorf("%s: Expected object locked, got %v", instanceType, err)
+	}
+	var buffer bytes.Buffer
+	if err = obj.GetObject(context.Background(), bucket, "retained", 0, -1, &buffer); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if buffer.String() != "hello" {
+		t.Errorf("%s: Expected %q, got %q", instanceType, "hello", buffer.String())
+	}
+
+	// Expired retention releases the object, a legal hold keeps it.
+	expired := time.Now().UTC().Add(-time.Hour).Format(timeFormatAMZLong)
+	if _, err = obj.UpdateObjectMetadata(context.Background(), bucket, "retained", "", map[string]string{
+		amzObjectLockRetainUntilDate: expired,
+		amzObjectLockLegalHold:       legalHoldOn,
+	}); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if err = obj.DeleteObject(context.Background(), bucket, "retained"); !isErrObjectLocked(err) {
+		t.Errorf("%s: Expected object locked, got %v", instanceType, err)
+	}
+	if _, err = obj.UpdateObjectMetadata(context.Background(), bucket, "retained", "", map[string]string{
+		amzObjectLockLegalHold: legalHoldOff,
+	}); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if err = obj.DeleteObject(context.Background(), bucket, "retained"); err != nil {
+		t.Errorf("%s: Expected the object to be removed, got %v", instanceType, err)
+	}
+
+	// Objects without retention are not affected.
+	if _, err = obj.PutObject(context.Background(), bucket, "plain", 5, bytes.NewBufferString("hello"), nil, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if err = obj.DeleteObject(context.Background(), bucket, "plain"); err != nil {
+		t.Errorf("%s: Expected the object to be removed, got %v", instanceType, err)
+	}
+}
+
+// Wrapper for calling versioned object lock tests for both XL multiple disks and single node setup.
+func TestObjectLockVersions(t *testing.T) {
+	ExecObjectLayerTest(t, testObjectLockVersions)
+}
+
+// Tests retained versions on a versioned bucket.
+func testObjectLockVersions(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "locked-versioned-bucket"
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	globalBucketVersioning.Set(bucket, &versioningConfig{Status: versioningEnabled})
+	defer globalBucketVersioning.Set(bucket, nil)
+	globalBucketObjectLock.Set(bucket, &objectLockConfig{ObjectLockEnabled: objectLockEnabled})
+	defer globalBucketObjectLock.Set(bucket, nil)
+
+	objInfo, err := obj.PutObject(context.Background(), bucket, "object", 5, bytes.NewBufferString("first"), map[string]string{
+		amzObjectLockLegalHold: legalHoldOn,
+	}, "")
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	retainedID := objInfo.VersionID
+	if _, err = obj.UpdateObjectMetadata(context.Background(), bucket, "object", "", map[string]string{amzObjectLockLegalHold: legalHoldOff}); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	// Released versions can be replaced, the old version is kept.
+	if _, err = obj.PutObject(context.Background(), bucket, "object", 6, bytes.NewBufferString("second"), nil, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if _, err = obj.UpdateObjectMetadata(context.Background(), bucket, "object", retainedID, map[string]string{amzObjectLockLegalHold: legalHoldOn}); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	// Noncurrent retained versions are kept.
+	if _, err = obj.DeleteObjectVersion(context.Background(), bucket, "object", retainedID); !isErrObjectLocked(err) {
+		t.Errorf("%s: Expected object locked, got %v", instanceType, err)
+	}
+	if _, err = obj.GetObjectVersionInfo(context.Background(), bucket, "object", retainedID); err != nil {
+		t.Errorf("%s: Expected the version to be kept, got %v", instanceType, err)
+	}
+
+	// Retained current versions are replaced by new versions and
+	// hidden by delete markers, they are kept either way.
+	if _, err = obj.PutObject(context.Background(), bucket, "object", 5, bytes.NewBufferString("third"), map[string]string{
+		amzObjectLockLegalHold: legalHoldOn,
+	}, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if _, err = obj.PutObject(context.Background(), bucket, "object", 6, bytes.NewBufferString("fourth"), map[string]string{
+		amzObjectLockLegalHold: legalHoldOn,
+	}, ""); err != nil {
+		t.Errorf("%s: Expected a new version to be added, got %v", instanceType, err)
+	}
+	if objInfo, err = obj.DeleteObjectVersion(context.Background(), bucket, "object", ""); err != nil || !objInfo.DeleteMarker {
+		t.Errorf("%s: Expected a delete marker to be placed, got %v", instanceType, err)
+	}
+
+	// On suspended buckets the retained null version can't be replaced.
+	globalBucketVersioning.Set(bucket, &versioningConfig{Status: versioningSuspended})
+	if _, err = obj.PutObject(context.Background(), bucket, "null-object", 5, bytes.NewBufferString("first"), map[string]string{
+		amzObjectLockLegalHold: legalHoldOn,
+	}, ""); err != nil {
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+	if _, err = obj.PutObject(context.Background(), bucket, "null-object", 6, bytes.NewBufferString("second"), nil, ""); !isErrObjectLocked(err) {
+		t.Errorf("%s: Expected object locked, got %v", instanceType, err)
+	}
+	if _, err = obj.DeleteObjectVersion(context.Background(), bucket, "null-object", ""); !isErrObjectLocked(err) {
+		t.Errorf("%s: Expected object locked, got %v", instanceType, err)
+	}
+}
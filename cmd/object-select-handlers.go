@@ -0,0 +1,148 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/teamwork/minio/pkg/s3select"
+)
+
+// Select requests can be at most 256KiB.
+const maxSelectRequestSize = 256 * 1024
+
+// Converts errors of invalid Select requests into API error codes.
+func toSelectAPIErrorCode(err error) APIErrorCode {
+	e, ok := err.(*s3select.Error)
+	if !ok {
+		return toAPIErrorCode(err)
+	}
+	switch e.Code {
+	case s3select.ErrCodeInvalidExpressionType:
+		return ErrInvalidExpressionType
+	case s3select.ErrCodeInvalidCompressionFormat:
+		return ErrInvalidCompressionFormat
+	case s3select.ErrCodeMissingRequiredParameter:
+		return ErrMissingRequiredParameter
+	case s3select.ErrCodeParseUnexpectedToken:
+		return ErrParseUnexpectedToken
+	case s3select.ErrCodeParseUnsupportedSyntax:
+		return ErrParseUnsupportedSyntax
+	}
+	return ErrInvalidRequestParameter
+}
+
+// SelectObjectContentHandler - POST Object?select&select-type=2
+// ----------
+// This implementation of the POST operation filters a CSV or JSON
+// object with a SQL expression, the results are streamed as they are
+// found without buffering the object.
+func (api objectAPIHandlers) SelectObjectContentHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObject", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// If Content-Length is unknown or zero, deny the request.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		return
+	}
+	if r.ContentLength > maxSelectRequestSize {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+
+	var selectReq s3select.Request
+	if err := xml.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&selectReq); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to parse select request XML.")
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+	s3Select, err := s3select.NewSelect(&selectReq)
+	if err != nil {
+		writeErrorResponse(w, toSelectAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Lock the object before reading.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.GetObjectVersionInfo(r.Context(), bucket, object, versionID)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Delete markers have no content.
+	if objInfo.DeleteMarker {
+		setVersionHeaders(w, objInfo)
+		writeErrorResponse(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	// Encrypted objects need the key they were written with.
+	objectKey, s3Error := getSSEObjectKey(r.Header, "", &objInfo)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// The object is streamed through a pipe, the reader is closed as
+	// soon as the query is done, e.g. once LIMIT is reached.
+	pipeReader, pipeWriter := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var gErr error
+		if objectKey != nil {
+			gErr = getEncryptedObject(r.Context(), objectAPI, objInfo, versionID, objectKey, 0, objInfo.Size, pipeWriter)
+		} else {
+			gErr = objectAPI.GetObjectVersion(r.Context(), bucket, object, versionID, 0, objInfo.Size, pipeWriter)
+		}
+		pipeWriter.CloseWithError(gErr)
+	}()
+	defer func() {
+		pipeReader.Close()
+		<-done
+	}()
+
+	setSSEHeaders(w, objInfo.UserDefined, r.Header)
+	writeSuccessResponseHeadersOnly(w)
+
+	// Errors are sent to the client as part of the event stream.
+	if err = s3Select.Execute(pipeReader, w); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to select from %s/%s.", bucket, object)
+	}
+}
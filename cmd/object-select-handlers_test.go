@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Wrapper for calling SelectObjectContent handler tests for both XL multiple disks and single node setup.
+func TestSelectObjectContentHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testSelectObjectContentHandler, []string{"SelectObjectContent"})
+}
+
+func testSelectObjectContentHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	var data bytes.Buffer
+	data.WriteString("id,name,qty\n")
+	for i := 1; i <= 1000; i++ {
+		fmt.Fprintf(&data, "%d,item-%d,%d\n", i, i, i%10)
+	}
+	var compressed bytes.Buffer
+	gzWriter := gzip.NewWriter(&compressed)
+	gzWriter.Write(data.Bytes())
+	gzWriter.Close()
+
+	for name, content := range map[string][]byte{"data.csv": data.Bytes(), "data.csv.gz": compressed.Bytes()} {
+		_, err := obj.PutObject(context.Background(), bucketName, name, int64(len(content)), bytes.NewReader(content), nil, "")
+		if err != nil {
+			t.Fatalf("%s: Failed to create object: <ERROR> %v", instanceType, err)
+		}
+	}
+
+	selectRequest := func(expression, compression string) string {
+		return `<SelectObjectContentRequest><Expression>` + expression + `</Expression><ExpressionType>SQL</ExpressionType>` +
+			`<InputSerialization><CompressionType>` + compression + `</CompressionType><CSV><FileHeaderInfo>USE</FileHeaderInfo></CSV></InputSerialization>` +
+			`<OutputSerialization><CSV/></OutputSerialization></SelectObjectContentRequest>`
+	}
+
+	testCases := []struct {
+		objectName         string
+		body               string
+		expectedRespStatus int
+		expectedContent    string
+	}{
+		// Test case - 1.
+		// Filter rows.
+		{"data.csv", selectRequest("SELECT s.name FROM S3Object s WHERE s.id = '500'", "NONE"), http.StatusOK, "item-500\n"},
+		// Test case - 2.
+		// Aggregates over a compressed object.
+		{"data.csv.gz", selectRequest("SELECT COUNT(*), SUM(qty) FROM S3Object WHERE CAST(qty AS INT) &gt; 4", "GZIP"), http.StatusOK, "500,3500\n"},
+		// Test case - 3.
+		// Limit stops reading the object.
+		{"data.csv", selectRequest("SELECT id FROM S3Object LIMIT 2", "NONE"), http.StatusOK, "1\n2\n"},
+		// Test case - 4.
+		// Malformed expression.
+		{"data.csv", selectRequest("SELECT FROM S3Object", "NONE"), http.StatusBadRequest, "ParseUnexpectedToken"},
+		// Test case - 5.
+		// Malformed request.
+		{"data.csv", "<SelectObjectContentRequest>", http.StatusBadRequest, "MalformedXML"},
+		// Test case - 6.
+		// Non-existent object.
+		{"missing.csv", selectRequest("SELECT * FROM S3Object", "NONE"), http.StatusNotFound, "NoSuchKey"},
+		// Test case - 7.
+		// Errors while reading the object are part of the response.
+		{"data.csv", selectRequest("SELECT * FROM S3Object", "GZIP"), http.StatusOK, "InvalidCompressionFormat"},
+	}
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("POST", getSelectObjectContentURL("", bucketName, testCase.objectName),
+			int64(len(testCase.body)), strings.NewReader(testCase.body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+			continue
+		}
+		if !bytes.Contains(rec.Body.Bytes(), []byte(testCase.expectedContent)) {
+			t.Errorf("Test %d: %s: Expected the response to contain %q", i+1, instanceType, testCase.expectedContent)
+		}
+	}
+
+	// Anonymous requests need read access to the object.
+	body := selectRequest("SELECT * FROM S3Object", "NONE")
+	anonReq, err := newTestRequest("POST", getSelectObjectContentURL("", bucketName, "data.csv"), int64(len(body)), strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("%s: Failed to create an anonymous request: <ERROR> %v", instanceType, err)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, anonReq)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, rec.Code)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// A tag set of up to maxBucketTags tags fits in 64KiB.
+const maxTaggingSize = 64 * 1024
+
+// Reads and validates the tag set sent with PutObjectTagging and
+// PutBucketTagging.
+func readTagging(r *http.Request, maxTags int) (*tagging, APIErrorCode) {
+	// If Content-Length is unknown or zero, deny the request.
+	if r.ContentLength == -1 || r.ContentLength == 0 {
+		return nil, ErrMissingContentLength
+	}
+	if r.ContentLength > maxTaggingSize {
+		return nil, ErrEntityTooLarge
+	}
+
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, r.Body, r.ContentLength); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to read incoming body.")
+		return nil, toAPIErrorCode(err)
+	}
+
+	tags := &tagging{}
+	if err := xml.Unmarshal(buffer.Bytes(), tags); err != nil {
+		errorIfCtx(r.Context(), err, "Unable to parse tagging XML.")
+		return nil, ErrMalformedXML
+	}
+	if s3Error := validateTags(tags.TagSet, maxTags); s3Error != ErrNone {
+		return nil, s3Error
+	}
+	return tags, ErrNone
+}
+
+// PutObjectTaggingHandler - PUT Object tagging
+// -----------------
+// This implementation of the PUT operation uses the tagging
+// subresource to replace the tags of an object version.
+func (api objectAPIHandlers) PutObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:PutObjectTagging", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	tags, s3Error := readTagging(r, maxObjectTags)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Lock the object before updating its tags.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.UpdateObjectMetadata(r.Context(), bucket, object, versionID, map[string]string{
+		objectTagsMetaKey: encodeTags(tags.TagSet),
+	})
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to update object tags.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	setVersionHeaders(w, objInfo)
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetObjectTaggingHandler - GET Object tagging
+// -----------------
+// This implementation of the GET operation uses the tagging
+// subresource to return the tags of an object version.
+func (api objectAPIHandlers) GetObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:GetObjectTagging", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Lock the object before reading its tags.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.GetObjectVersionInfo(r.Context(), bucket, object, versionID)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to fetch object info.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Delete markers have no tags.
+	if objInfo.DeleteMarker {
+		setVersionHeaders(w, objInfo)
+		writeErrorResponse(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	tags, err := decodeTags(objInfo.UserTags)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to parse object tags.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	taggingBytes, err := xml.Marshal(tagging{TagSet: tags})
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to marshal object tags into XML.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	setVersionHeaders(w, objInfo)
+	writeSuccessResponseXML(w, taggingBytes)
+}
+
+// DeleteObjectTaggingHandler - DELETE Object tagging
+// -----------------
+// This implementation of the DELETE operation uses the tagging
+// subresource to remove all tags of an object version.
+func (api objectAPIHandlers) DeleteObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(r, bucket, "s3:DeleteObjectTagging", serverConfig.GetRegion()); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Lock the object before removing its tags.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	versionID := r.URL.Query().Get("versionId")
+	objInfo, err := objectAPI.UpdateObjectMetadata(r.Context(), bucket, object, versionID, map[string]string{
+		objectTagsMetaKey: "",
+	})
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to remove object tags.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	setVersionHeaders(w, objInfo)
+	writeSuccessNoContent(w)
+}
@@ -0,0 +1,150 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Wrapper for calling Put/Get/DeleteObjectTagging handler tests for both XL multiple disks and single node setup.
+func TestObjectTaggingHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testObjectTaggingHandlers, []string{
+		"PutObjectTagging",
+		"GetObjectTagging",
+		"DeleteObjectTagging",
+		"HeadObject",
+		"CopyObject",
+		"PutObject",
+	})
+}
+
+func testObjectTaggingHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials credential, t *testing.T) {
+
+	// Sends a request and returns the recorded response.
+	sendRequest := func(method, urlStr, body string, header http.Header) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(method, urlStr, int64(len(body)), bytes.NewReader([]byte(body)),
+			credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, urlStr, err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	// Returns the tags of an object as seen by GetObjectTagging.
+	getTags := func(object string) []tag {
+		rec := sendRequest("GET", getObjectTaggingURL("", bucketName, object, ""), "", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+		}
+		tags := tagging{}
+		if err := xml.Unmarshal(rec.Body.Bytes(), &tags); err != nil {
+			t.Fatalf("%s: Unable to parse response %s", instanceType, err)
+		}
+		return tags.TagSet
+	}
+
+	// Object uploaded with tags.
+	header := http.Header{}
+	header.Set(amzTaggingHeader, "project=alpha")
+	if rec := sendRequest("PUT", getPutObjectURL("", bucketName, "object"), "hello", header); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if tags := getTags("object"); len(tags) != 1 || tags[0] != (tag{"project", "alpha"}) {
+		t.Errorf("%s: Unexpected tags %v", instanceType, tags)
+	}
+	rec := sendRequest("HEAD", getHeadObjectURL("", bucketName, "object"), "", nil)
+	if count := rec.Header().Get("x-amz-tagging-count"); count != "1" {
+		t.Errorf("%s: Expected a tagging count of 1, got %q", instanceType, count)
+	}
+	if rec.Header().Get(objectTagsMetaKey) != "" {
+		t.Errorf("%s: Tags leaked into the response headers", instanceType)
+	}
+
+	// Invalid tags are rejected.
+	header.Set(amzTaggingHeader, "aws:project=alpha")
+	if rec = sendRequest("PUT", getPutObjectURL("", bucketName, "invalid"), "hello", header); rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	testCases := []struct {
+		objectName         string
+		body               string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// Valid tags.
+		{"object", `<Tagging><TagSet><Tag><Key>team</Key><Value>blue</Value></Tag><Tag><Key>project</Key><Value>beta</Value></Tag></TagSet></Tagging>`, http.StatusOK},
+		// Test case - 2.
+		// Duplicate keys.
+		{"object", `<Tagging><TagSet><Tag><Key>a</Key><Value>1</Value></Tag><Tag><Key>a</Key><Value>2</Value></Tag></TagSet></Tagging>`, http.StatusBadRequest},
+		// Test case - 3.
+		// Malformed tags.
+		{"object", `<Tagging><TagSet>`, http.StatusBadRequest},
+		// Test case - 4.
+		// Non-existent object.
+		{"missing", `<Tagging><TagSet><Tag><Key>a</Key><Value>1</Value></Tag></TagSet></Tagging>`, http.StatusNotFound},
+	}
+	for i, testCase := range testCases {
+		rec = sendRequest("PUT", getObjectTaggingURL("", bucketName, testCase.objectName, ""), testCase.body, nil)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Errorf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+	if tags := getTags("object"); len(tags) != 2 || tags[0] != (tag{"project", "beta"}) || tags[1] != (tag{"team", "blue"}) {
+		t.Errorf("%s: Unexpected tags %v", instanceType, tags)
+	}
+
+	// Tags are copied by default and replaced on request.
+	header = http.Header{}
+	header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/object"))
+	if rec = sendRequest("PUT", getCopyObjectURL("", bucketName, "copy"), "", header); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if tags := getTags("copy"); len(tags) != 2 {
+		t.Errorf("%s: Unexpected tags %v", instanceType, tags)
+	}
+	header.Set(amzTaggingDirectiveHeader, "REPLACE")
+	header.Set(amzTaggingHeader, "copy=true")
+	if rec = sendRequest("PUT", getCopyObjectURL("", bucketName, "copy"), "", header); rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if tags := getTags("copy"); len(tags) != 1 || tags[0] != (tag{"copy", "true"}) {
+		t.Errorf("%s: Unexpected tags %v", instanceType, tags)
+	}
+	header.Set(amzTaggingDirectiveHeader, "MERGE")
+	if rec = sendRequest("PUT", getCopyObjectURL("", bucketName, "copy"), "", header); rec.Code != http.StatusBadRequest {
+		t.Errorf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	// Remove the tags.
+	if rec = sendRequest("DELETE", getObjectTaggingURL("", bucketName, "object", ""), "", nil); rec.Code != http.StatusNoContent {
+		t.Errorf("%s: Unexpected http response %d", instanceType, rec.Code)
+	}
+	if tags := getTags("object"); len(tags) != 0 {
+		t.Errorf("%s: Unexpected tags %v", instanceType, tags)
+	}
+}
@@ -0,0 +1,273 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio-go/pkg/set"
+)
+
+const (
+	// Reserved metadata entry carrying the URL encoded tags of an
+	// object, never returned as user defined metadata.
+	objectTagsMetaKey = "X-Minio-Internal-Tagging"
+
+	// Tagging headers of PutObject and CopyObject.
+	amzTaggingHeader          = "X-Amz-Tagging"
+	amzTaggingDirectiveHeader = "X-Amz-Tagging-Directive"
+
+	// Maximum number of tags of an object and of a bucket.
+	maxObjectTags = 10
+	maxBucketTags = 50
+
+	// Maximum length of tag keys and values.
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+
+	// Tag keys with this prefix are reserved.
+	reservedTagKeyPrefix = "aws:"
+)
+
+// tag - a single key value pair of a tag set.
+type tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// tagging - represents the tag set of an object or a bucket as set by
+// PutObjectTagging and PutBucketTagging.
+type tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	TagSet  []tag    `xml:"TagSet>Tag"`
+}
+
+// Validates a tag set holding at most maxTags tags.
+func validateTags(tags []tag, maxTags int) APIErrorCode {
+	if len(tags) > maxTags {
+		return ErrTooManyTags
+	}
+	keys := make(map[string]struct{}, len(tags))
+	for _, t := range tags {
+		if t.Key == "" || len(t.Key) > maxTagKeyLength || strings.HasPrefix(t.Key, reservedTagKeyPrefix) {
+			return ErrInvalidTagKey
+		}
+		if len(t.Value) > maxTagValueLength {
+			return ErrInvalidTagValue
+		}
+		if _, ok := keys[t.Key]; ok {
+			return ErrDuplicateTagKey
+		}
+		keys[t.Key] = struct{}{}
+	}
+	return ErrNone
+}
+
+// encodeTags - returns the URL encoded form of a tag set, the form
+// used by the `x-amz-tagging` header.
+func encodeTags(tags []tag) string {
+	values := make(url.Values)
+	for _, t := range tags {
+		values.Set(t.Key, t.Value)
+	}
+	return values.Encode()
+}
+
+// decodeTags - parses URL encoded tags, the tag set is sorted by key.
+func decodeTags(encoded string) ([]tag, error) {
+	values, err := url.ParseQuery(encoded)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]tag, 0, len(values))
+	for key, value := range values {
+		if len(value) != 1 {
+			return nil, errInvalidArgument
+		}
+		tags = append(tags, tag{Key: key, Value: value[0]})
+	}
+	sort.Sort(byTagKey(tags))
+	return tags, nil
+}
+
+// Sorts tags by key.
+type byTagKey []tag
+
+func (t byTagKey) Len() int           { return len(t) }
+func (t byTagKey) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t byTagKey) Less(i, j int) bool { return t[i].Key < t[j].Key }
+
+// Returns the tags of the `x-amz-tagging` header, validated and URL
+// encoded again in a canonical form.
+func getTaggingFromHeader(header http.Header) (string, APIErrorCode) {
+	tags, err := decodeTags(header.Get(amzTaggingHeader))
+	if err != nil {
+		return "", ErrInvalidTagKey
+	}
+	if s3Error := validateTags(tags, maxObjectTags); s3Error != ErrNone {
+		return "", s3Error
+	}
+	return encodeTags(tags), ErrNone
+}
+
+// setObjectTagsFromHeader - saves the tags of the `x-amz-tagging` header
+// into the metadata of an object about to be written.
+func setObjectTagsFromHeader(header http.Header, metadata map[string]string) APIErrorCode {
+	if header.Get(amzTaggingHeader) == "" {
+		return ErrNone
+	}
+	tags, s3Error := getTaggingFromHeader(header)
+	if s3Error != ErrNone {
+		return s3Error
+	}
+	metadata[objectTagsMetaKey] = tags
+	return ErrNone
+}
+
+// Verify if the tagging directive of CopyObject is valid.
+func isTaggingDirectiveValid(header http.Header) bool {
+	if _, ok := header[amzTaggingDirectiveHeader]; !ok {
+		return true
+	}
+	directive := header.Get(amzTaggingDirectiveHeader)
+	return directive == "COPY" || directive == "REPLACE"
+}
+
+// Check if the tags of the copy source are to be replaced.
+func isTaggingReplace(header http.Header) bool {
+	return header.Get(amzTaggingDirectiveHeader) == "REPLACE"
+}
+
+// extractObjectTags - removes the reserved tagging entry from the
+// metadata of an object and returns its value.
+func extractObjectTags(meta map[string]string) string {
+	tags := meta[objectTagsMetaKey]
+	delete(meta, objectTagsMetaKey)
+	return tags
+}
+
+// Returns the number of tags of an object, as reported by the
+// `x-amz-tagging-count` header.
+func objectTagsCount(encoded string) int {
+	if encoded == "" {
+		return 0
+	}
+	return strings.Count(encoded, "&") + 1
+}
+
+// Returns true if the tag set holds all tags of the filter.
+func containsTags(tags []tag, filter []tag) bool {
+	for _, f := range filter {
+		found := false
+		for _, t := range tags {
+			if t == f {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Policy condition keys of object tags, tag conditions carry the key of
+// the tag after the slash, e.g `s3:ExistingObjectTag/project`.
+const (
+	existingObjectTagConditionKey    = "s3:ExistingObjectTag/"
+	requestObjectTagConditionKey     = "s3:RequestObjectTag/"
+	requestObjectTagKeysConditionKey = "s3:RequestObjectTagKeys"
+)
+
+// Returns true if the policy condition key refers to object tags.
+func isObjectTagConditionKey(key string) bool {
+	return key == requestObjectTagKeysConditionKey ||
+		(strings.HasPrefix(key, existingObjectTagConditionKey) && key != existingObjectTagConditionKey) ||
+		(strings.HasPrefix(key, requestObjectTagConditionKey) && key != requestObjectTagConditionKey)
+}
+
+// Returns true if any statement of the policy has conditions on the tags
+// of existing objects.
+func policyHasExistingObjectTagConditions(policy *bucketPolicy) bool {
+	for _, statement := range policy.Statements {
+		for _, conditionKeyVal := range statement.Conditions {
+			for key := range conditionKeyVal {
+				if strings.HasPrefix(key, existingObjectTagConditionKey) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// addObjectTagConditions - adds the tags sent with a request and the
+// tags of the existing object to the policy conditions, keyed like the
+// policy condition keys without the `s3:` prefix. Tags of the existing
+// object are only looked up if the policy refers to them.
+func addObjectTagConditions(conditions map[string]set.StringSet, policy *bucketPolicy, bucket, object string, reqHeader http.Header) {
+	if reqHeader != nil && reqHeader.Get(amzTaggingHeader) != "" {
+		if tags, err := decodeTags(reqHeader.Get(amzTaggingHeader)); err == nil {
+			keys := set.NewStringSet()
+			for _, t := range tags {
+				conditions[strings.TrimPrefix(requestObjectTagConditionKey, "s3:")+t.Key] = set.CreateStringSet(t.Value)
+				keys.Add(t.Key)
+			}
+			conditions[strings.TrimPrefix(requestObjectTagKeysConditionKey, "s3:")] = keys
+		}
+	}
+
+	if object == "" || !policyHasExistingObjectTagConditions(policy) {
+		return
+	}
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return
+	}
+	objInfo, err := objAPI.GetObjectInfo(context.Background(), bucket, object)
+	if err != nil {
+		return
+	}
+	tags, err := decodeTags(objInfo.UserTags)
+	if err != nil {
+		return
+	}
+	for _, t := range tags {
+		conditions[strings.TrimPrefix(existingObjectTagConditionKey, "s3:")+t.Key] = set.CreateStringSet(t.Value)
+	}
+}
+
+// objectTagConditionsMatch - verifies the object tag keys of a policy
+// condition. StringEquals needs any of the values of a key to match the
+// tags of the request, StringNotEquals needs none of them to match.
+func objectTagConditionsMatch(condition string, conditions map[string]set.StringSet, conditionKeyVal map[string]set.StringSet) bool {
+	for key, values := range conditionKeyVal {
+		if !isObjectTagConditionKey(key) {
+			continue
+		}
+		if !conditionKeyMatch(condition, key, values, conditions) {
+			return false
+		}
+	}
+	return true
+}
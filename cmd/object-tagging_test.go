@@ -0,0 +1,218 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// Tests validation of tag sets.
+func TestValidateTags(t *testing.T) {
+	testCases := []struct {
+		tags          []tag
+		maxTags       int
+		expectedError APIErrorCode
+	}{
+		// Test case - 1.
+		// Valid tags.
+		{[]tag{{"project", "alpha"}, {"team", ""}}, maxObjectTags, ErrNone},
+		// Test case - 2.
+		// Too many tags.
+		{[]tag{{"a", "1"}, {"b", "2"}}, 1, ErrTooManyTags},
+		// Test case - 3.
+		// Empty key.
+		{[]tag{{"", "1"}}, maxObjectTags, ErrInvalidTagKey},
+		// Test case - 4.
+		// Reserved key.
+		{[]tag{{"aws:project", "1"}}, maxObjectTags, ErrInvalidTagKey},
+		// Test case - 5.
+		// Key too long.
+		{[]tag{{strings.Repeat("k", maxTagKeyLength+1), "1"}}, maxObjectTags, ErrInvalidTagKey},
+		// Test case - 6.
+		// Value too long.
+		{[]tag{{"k", strings.Repeat("v", maxTagValueLength+1)}}, maxObjectTags, ErrInvalidTagValue},
+		// Test case - 7.
+		// Duplicate keys.
+		{[]tag{{"k", "1"}, {"k", "2"}}, maxObjectTags, ErrDuplicateTagKey},
+	}
+	for i, testCase := range testCases {
+		if s3Error := validateTags(testCase.tags, testCase.maxTags); s3Error != testCase.expectedError {
+			t.Errorf("Test %d: Expected error %d, got %d", i+1, testCase.expectedError, s3Error)
+		}
+	}
+}
+
+// Tests parsing of the `x-amz-tagging` header.
+func TestGetTaggingFromHeader(t *testing.T) {
+	testCases := []struct {
+		header        string
+		expectedTags  string
+		expectedError APIErrorCode
+	}{
+		{"team=blue&project=alpha", "project=alpha&team=blue", ErrNone},
+		{"key%20one=value%2Fone", "key+one=value%2Fone", ErrNone},
+		{"a=1&a=2", "", ErrInvalidTagKey},
+		{"aws:a=1", "", ErrInvalidTagKey},
+		{"a=1&b=2&c=3&d=4&e=5&f=6&g=7&h=8&i=9&j=10&k=11", "", ErrTooManyTags},
+	}
+	for i, testCase := range testCases {
+		header := http.Header{}
+		header.Set(amzTaggingHeader, testCase.header)
+		tags, s3Error := getTaggingFromHeader(header)
+		if s3Error != testCase.expectedError {
+			t.Errorf("Test %d: Expected error %d, got %d", i+1, testCase.expectedError, s3Error)
+			continue
+		}
+		if tags != testCase.expectedTags {
+			t.Errorf("Test %d: Expected tags %q, got %q", i+1, testCase.expectedTags, tags)
+		}
+	}
+
+	decoded, err := decodeTags("team=blue&project=alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []tag{{"project", "alpha"}, {"team", "blue"}}; !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Expected %v, got %v", expected, decoded)
+	}
+	if count := objectTagsCount(encodeTags(decoded)); count != 2 {
+		t.Errorf("Expected 2 tags, got %d", count)
+	}
+}
+
+// Tests object tag policy conditions.
+func TestObjectTagConditionsMatch(t *testing.T) {
+	conditions := map[string]set.StringSet{
+		"RequestObjectTag/project": set.CreateStringSet("alpha"),
+		"ExistingObjectTag/team":   set.CreateStringSet("blue"),
+	}
+	testCases := []struct {
+		condition       string
+		conditionKeyVal map[string]set.StringSet
+		expected        bool
+	}{
+		{"StringEquals", map[string]set.StringSet{"s3:RequestObjectTag/project": set.CreateStringSet("alpha", "beta")}, true},
+		{"StringEquals", map[string]set.StringSet{"s3:RequestObjectTag/project": set.CreateStringSet("beta")}, false},
+		{"StringEquals", map[string]set.StringSet{"s3:ExistingObjectTag/team": set.CreateStringSet("blue")}, true},
+		{"StringEquals", map[string]set.StringSet{"s3:ExistingObjectTag/owner": set.CreateStringSet("blue")}, false},
+		{"StringNotEquals", map[string]set.StringSet{"s3:ExistingObjectTag/team": set.CreateStringSet("blue")}, false},
+		{"StringNotEquals", map[string]set.StringSet{"s3:ExistingObjectTag/team": set.CreateStringSet("red")}, true},
+		{"StringEquals", map[string]set.StringSet{"s3:prefix": set.CreateStringSet("red")}, true},
+	}
+	for i, testCase := range testCases {
+		if matches := objectTagConditionsMatch(testCase.condition, conditions, testCase.conditionKeyVal); matches != testCase.expected {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, matches)
+		}
+	}
+}
+
+// Wrapper for calling object tagging tests for both XL multiple disks and single node setup.
+func TestUpdateObjectMetadata(t *testing.T) {
+	ExecObjectLayerTest(t, testUpdateObjectMetadata)
+}
+
+// Tests updating the tags of objects and of older object versions.
+func testUpdateObjectMetadata(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucket := "tagging-bucket"
+	if err := obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	// Tags saved along with the object.
+	metadata := map[string]string{objectTagsMetaKey: "project=alpha"}
+	if _, err := obj.PutObject(context.Background(), bucket, "object", int64(len("hello")), bytes.NewBufferString("hello"), metadata, ""); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	objInfo, err := obj.GetObjectInfo(context.Background(), bucket, "object")
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if objInfo.UserTags != "project=alpha" {
+		t.Errorf("%s: Expected tags %q, got %q", instanceType, "project=alpha", objInfo.UserTags)
+	}
+	if _, ok := objInfo.UserDefined[objectTagsMetaKey]; ok {
+		t.Errorf("%s: Tags leaked into user defined metadata", instanceType)
+	}
+
+	// Tags replaced in place.
+	objInfo, err = obj.UpdateObjectMetadata(context.Background(), bucket, "object", "", map[string]string{objectTagsMetaKey: "team=blue"})
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if objInfo.UserTags != "team=blue" || objInfo.Size != int64(len("hello")) {
+		t.Errorf("%s: Unexpected object info %#v", instanceType, objInfo)
+	}
+
+	// Content is still readable.
+	var buffer bytes.Buffer
+	if err = obj.GetObject(context.Background(), bucket, "object", 0, -1, &buffer); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if buffer.String() != "hello" {
+		t.Errorf("%s: Expected %q, got %q", instanceType, "hello", buffer.String())
+	}
+
+	// Tags removed.
+	if _, err = obj.UpdateObjectMetadata(context.Background(), bucket, "object", "", map[string]string{objectTagsMetaKey: ""}); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if objInfo, err = obj.GetObjectInfo(context.Background(), bucket, "object"); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if objInfo.UserTags != "" {
+		t.Errorf("%s: Expected no tags, got %q", instanceType, objInfo.UserTags)
+	}
+
+	if _, err = obj.UpdateObjectMetadata(context.Background(), bucket, "missing", "", map[string]string{objectTagsMetaKey: ""}); !isErrObjectNotFound(err) {
+		t.Errorf("%s: Expected object not found, got %v", instanceType, err)
+	}
+
+	// Tags of an older version.
+	globalBucketVersioning.Set(bucket, &versioningConfig{Status: versioningEnabled})
+	defer globalBucketVersioning.Set(bucket, nil)
+
+	var versionIDs []string
+	for _, content := range []string{"first", "second"} {
+		objInfo, err = obj.PutObject(context.Background(), bucket, "versioned", int64(len(content)), bytes.NewBufferString(content), nil, "")
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		versionIDs = append(versionIDs, objInfo.VersionID)
+	}
+	if _, err = obj.UpdateObjectMetadata(context.Background(), bucket, "versioned", versionIDs[0], map[string]string{objectTagsMetaKey: "old=true"}); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if objInfo, err = obj.GetObjectVersionInfo(context.Background(), bucket, "versioned", versionIDs[0]); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if objInfo.UserTags != "old=true" {
+		t.Errorf("%s: Expected tags %q, got %q", instanceType, "old=true", objInfo.UserTags)
+	}
+	if objInfo, err = obj.GetObjectInfo(context.Background(), bucket, "versioned"); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if objInfo.UserTags != "" || objInfo.VersionID != versionIDs[1] {
+		t.Errorf("%s: Unexpected latest version %#v", instanceType, objInfo)
+	}
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"math/rand"
 	"strconv"
@@ -79,7 +80,7 @@ func (s *ObjectLayerAPISuite) TestMakeBucket(c *C) {
 
 // Tests validate bucket creation.
 func testMakeBucket(obj ObjectLayer, instanceType string, c TestErrHandler) {
-	err := obj.MakeBucket("bucket-unknown")
+	err := obj.MakeBucket(context.Background(), "bucket-unknown")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -92,11 +93,11 @@ func (s *ObjectLayerAPISuite) TestMultipartObjectCreation(c *C) {
 
 // Tests validate creation of part files during Multipart operation.
 func testMultipartObjectCreation(obj ObjectLayer, instanceType string, c TestErrHandler) {
-	err := obj.MakeBucket("bucket")
+	err := obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
-	uploadID, err := obj.NewMultipartUpload("bucket", "key", nil)
+	uploadID, err := obj.NewMultipartUpload(context.Background(), "bucket", "key", nil)
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -107,7 +108,7 @@ func testMultipartObjectCreation(obj ObjectLayer, instanceType string, c TestErr
 		expectedMD5Sumhex := getMD5Hash(data)
 
 		var calculatedMD5sum string
-		calculatedMD5sum, err = obj.PutObjectPart("bucket", "key", uploadID, i, int64(len(data)), bytes.NewBuffer(data), expectedMD5Sumhex, "")
+		calculatedMD5sum, err = obj.PutObjectPart(context.Background(), "bucket", "key", uploadID, i, int64(len(data)), bytes.NewBuffer(data), expectedMD5Sumhex, "")
 		if err != nil {
 			c.Errorf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -116,7 +117,7 @@ func testMultipartObjectCreation(obj ObjectLayer, instanceType string, c TestErr
 		}
 		completedParts.Parts = append(completedParts.Parts, completePart{PartNumber: i, ETag: calculatedMD5sum})
 	}
-	objInfo, err := obj.CompleteMultipartUpload("bucket", "key", uploadID, completedParts.Parts)
+	objInfo, err := obj.CompleteMultipartUpload(context.Background(), "bucket", "key", uploadID, completedParts.Parts)
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -132,11 +133,11 @@ func (s *ObjectLayerAPISuite) TestMultipartObjectAbort(c *C) {
 
 // Tests validate abortion of Multipart operation.
 func testMultipartObjectAbort(obj ObjectLayer, instanceType string, c TestErrHandler) {
-	err := obj.MakeBucket("bucket")
+	err := obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
-	uploadID, err := obj.NewMultipartUpload("bucket", "key", nil)
+	uploadID, err := obj.NewMultipartUpload(context.Background(), "bucket", "key", nil)
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -154,7 +155,7 @@ func testMultipartObjectAbort(obj ObjectLayer, instanceType string, c TestErrHan
 
 		metadata["md5"] = expectedMD5Sumhex
 		var calculatedMD5sum string
-		calculatedMD5sum, err = obj.PutObjectPart("bucket", "key", uploadID, i, int64(len(randomString)), bytes.NewBufferString(randomString), expectedMD5Sumhex, "")
+		calculatedMD5sum, err = obj.PutObjectPart(context.Background(), "bucket", "key", uploadID, i, int64(len(randomString)), bytes.NewBufferString(randomString), expectedMD5Sumhex, "")
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -163,7 +164,7 @@ func testMultipartObjectAbort(obj ObjectLayer, instanceType string, c TestErrHan
 		}
 		parts[i] = expectedMD5Sumhex
 	}
-	err = obj.AbortMultipartUpload("bucket", "key", uploadID)
+	err = obj.AbortMultipartUpload(context.Background(), "bucket", "key", uploadID)
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -177,7 +178,7 @@ func (s *ObjectLayerAPISuite) TestMultipleObjectCreation(c *C) {
 // Tests validate object creation.
 func testMultipleObjectCreation(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	objects := make(map[string][]byte)
-	err := obj.MakeBucket("bucket")
+	err := obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -195,7 +196,7 @@ func testMultipleObjectCreation(obj ObjectLayer, instanceType string, c TestErrH
 		metadata := make(map[string]string)
 		metadata["md5Sum"] = expectedMD5Sumhex
 		var objInfo ObjectInfo
-		objInfo, err = obj.PutObject("bucket", key, int64(len(randomString)), bytes.NewBufferString(randomString), metadata, "")
+		objInfo, err = obj.PutObject(context.Background(), "bucket", key, int64(len(randomString)), bytes.NewBufferString(randomString), metadata, "")
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -206,7 +207,7 @@ func testMultipleObjectCreation(obj ObjectLayer, instanceType string, c TestErrH
 
 	for key, value := range objects {
 		var byteBuffer bytes.Buffer
-		err = obj.GetObject("bucket", key, 0, int64(len(value)), &byteBuffer)
+		err = obj.GetObject(context.Background(), "bucket", key, 0, int64(len(value)), &byteBuffer)
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -214,7 +215,7 @@ func testMultipleObjectCreation(obj ObjectLayer, instanceType string, c TestErrH
 			c.Errorf("%s: Mismatch of GetObject data with the expected one.", instanceType)
 		}
 
-		objInfo, err := obj.GetObjectInfo("bucket", key)
+		objInfo, err := obj.GetObjectInfo(context.Background(), "bucket", key)
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -232,8 +233,8 @@ func (s *ObjectLayerAPISuite) TestPaging(c *C) {
 
 // Tests validate creation of objects and the order of listing using various filters for ListObjects operation.
 func testPaging(obj ObjectLayer, instanceType string, c TestErrHandler) {
-	obj.MakeBucket("bucket")
-	result, err := obj.ListObjects("bucket", "", "", "", 0)
+	obj.MakeBucket(context.Background(), "bucket")
+	result, err := obj.ListObjects(context.Background(), "bucket", "", "", "", 0)
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -248,12 +249,12 @@ func testPaging(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	// check before paging occurs.
 	for i := 0; i < 5; i++ {
 		key := "obj" + strconv.Itoa(i)
-		_, err = obj.PutObject("bucket", key, int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
+		_, err = obj.PutObject(context.Background(), "bucket", key, int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
 
-		result, err = obj.ListObjects("bucket", "", "", "", 5)
+		result, err = obj.ListObjects(context.Background(), "bucket", "", "", "", 5)
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -268,11 +269,11 @@ func testPaging(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	// check after paging occurs pages work.
 	for i := 6; i <= 10; i++ {
 		key := "obj" + strconv.Itoa(i)
-		_, err = obj.PutObject("bucket", key, int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
+		_, err = obj.PutObject(context.Background(), "bucket", key, int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
-		result, err = obj.ListObjects("bucket", "obj", "", "", 5)
+		result, err = obj.ListObjects(context.Background(), "bucket", "obj", "", "", 5)
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -285,15 +286,15 @@ func testPaging(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	}
 	// check paging with prefix at end returns less objects.
 	{
-		_, err = obj.PutObject("bucket", "newPrefix", int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
+		_, err = obj.PutObject(context.Background(), "bucket", "newPrefix", int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
-		_, err = obj.PutObject("bucket", "newPrefix2", int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
+		_, err = obj.PutObject(context.Background(), "bucket", "newPrefix2", int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
-		result, err = obj.ListObjects("bucket", "new", "", "", 5)
+		result, err = obj.ListObjects(context.Background(), "bucket", "new", "", "", 5)
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -304,7 +305,7 @@ func testPaging(obj ObjectLayer, instanceType string, c TestErrHandler) {
 
 	// check ordering of pages.
 	{
-		result, err = obj.ListObjects("bucket", "", "", "", 1000)
+		result, err = obj.ListObjects(context.Background(), "bucket", "", "", "", 1000)
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -327,15 +328,15 @@ func testPaging(obj ObjectLayer, instanceType string, c TestErrHandler) {
 
 	// check delimited results with delimiter and prefix.
 	{
-		_, err = obj.PutObject("bucket", "this/is/delimited", int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
+		_, err = obj.PutObject(context.Background(), "bucket", "this/is/delimited", int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
-		_, err = obj.PutObject("bucket", "this/is/also/a/delimited/file", int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
+		_, err = obj.PutObject(context.Background(), "bucket", "this/is/also/a/delimited/file", int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
-		result, err = obj.ListObjects("bucket", "this/is/", "", "/", 10)
+		result, err = obj.ListObjects(context.Background(), "bucket", "this/is/", "", "/", 10)
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -349,7 +350,7 @@ func testPaging(obj ObjectLayer, instanceType string, c TestErrHandler) {
 
 	// check delimited results with delimiter without prefix.
 	{
-		result, err = obj.ListObjects("bucket", "", "", "/", 1000)
+		result, err = obj.ListObjects(context.Background(), "bucket", "", "", "/", 1000)
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -377,7 +378,7 @@ func testPaging(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	// check results with Marker.
 	{
 
-		result, err = obj.ListObjects("bucket", "", "newPrefix", "", 3)
+		result, err = obj.ListObjects(context.Background(), "bucket", "", "newPrefix", "", 3)
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -393,7 +394,7 @@ func testPaging(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	}
 	// check ordering of results with prefix.
 	{
-		result, err = obj.ListObjects("bucket", "obj", "", "", 1000)
+		result, err = obj.ListObjects(context.Background(), "bucket", "obj", "", "", 1000)
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -415,7 +416,7 @@ func testPaging(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	}
 	// check ordering of results with prefix and no paging.
 	{
-		result, err = obj.ListObjects("bucket", "new", "", "", 5)
+		result, err = obj.ListObjects(context.Background(), "bucket", "new", "", "", 5)
 		if err != nil {
 			c.Fatalf("%s: <ERROR> %s", instanceType, err)
 		}
@@ -435,25 +436,25 @@ func (s *ObjectLayerAPISuite) TestObjectOverwriteWorks(c *C) {
 
 // Tests validate overwriting of an existing object.
 func testObjectOverwriteWorks(obj ObjectLayer, instanceType string, c TestErrHandler) {
-	err := obj.MakeBucket("bucket")
+	err := obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
 
-	_, err = obj.PutObject("bucket", "object", int64(len("The list of parts was not in ascending order. The parts list must be specified in order by part number.")), bytes.NewBufferString("The list of parts was not in ascending order. The parts list must be specified in order by part number."), nil, "")
+	_, err = obj.PutObject(context.Background(), "bucket", "object", int64(len("The list of parts was not in ascending order. The parts list must be specified in order by part number.")), bytes.NewBufferString("The list of parts was not in ascending order. The parts list must be specified in order by part number."), nil, "")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
 
 	uploadContent := "The specified multipart upload does not exist. The upload ID might be invalid, or the multipart upload might have been aborted or completed."
 	length := int64(len(uploadContent))
-	_, err = obj.PutObject("bucket", "object", length, bytes.NewBufferString(uploadContent), nil, "")
+	_, err = obj.PutObject(context.Background(), "bucket", "object", length, bytes.NewBufferString(uploadContent), nil, "")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
 
 	var bytesBuffer bytes.Buffer
-	err = obj.GetObject("bucket", "object", 0, length, &bytesBuffer)
+	err = obj.GetObject(context.Background(), "bucket", "object", 0, length, &bytesBuffer)
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -469,7 +470,7 @@ func (s *ObjectLayerAPISuite) TestNonExistantBucketOperations(c *C) {
 
 // Tests validate that bucket operation on non-existent bucket fails.
 func testNonExistantBucketOperations(obj ObjectLayer, instanceType string, c TestErrHandler) {
-	_, err := obj.PutObject("bucket1", "object", int64(len("one")), bytes.NewBufferString("one"), nil, "")
+	_, err := obj.PutObject(context.Background(), "bucket1", "object", int64(len("one")), bytes.NewBufferString("one"), nil, "")
 	if err == nil {
 		c.Fatal("Expected error but found nil")
 	}
@@ -485,11 +486,11 @@ func (s *ObjectLayerAPISuite) TestBucketRecreateFails(c *C) {
 
 // Tests validate that recreation of the bucket fails.
 func testBucketRecreateFails(obj ObjectLayer, instanceType string, c TestErrHandler) {
-	err := obj.MakeBucket("string")
+	err := obj.MakeBucket(context.Background(), "string")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
-	err = obj.MakeBucket("string")
+	err = obj.MakeBucket(context.Background(), "string")
 	if err == nil {
 		c.Fatalf("%s: Expected error but found nil.", instanceType)
 	}
@@ -510,17 +511,17 @@ func testPutObject(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	length := int64(len(content))
 	readerEOF := newTestReaderEOF(content)
 	readerNoEOF := newTestReaderNoEOF(content)
-	err := obj.MakeBucket("bucket")
+	err := obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
 
 	var bytesBuffer1 bytes.Buffer
-	_, err = obj.PutObject("bucket", "object", length, readerEOF, nil, "")
+	_, err = obj.PutObject(context.Background(), "bucket", "object", length, readerEOF, nil, "")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
-	err = obj.GetObject("bucket", "object", 0, length, &bytesBuffer1)
+	err = obj.GetObject(context.Background(), "bucket", "object", 0, length, &bytesBuffer1)
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -529,11 +530,11 @@ func testPutObject(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	}
 
 	var bytesBuffer2 bytes.Buffer
-	_, err = obj.PutObject("bucket", "object", length, readerNoEOF, nil, "")
+	_, err = obj.PutObject(context.Background(), "bucket", "object", length, readerNoEOF, nil, "")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
-	err = obj.GetObject("bucket", "object", 0, length, &bytesBuffer2)
+	err = obj.GetObject(context.Background(), "bucket", "object", 0, length, &bytesBuffer2)
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -549,7 +550,7 @@ func (s *ObjectLayerAPISuite) TestPutObjectInSubdir(c *C) {
 
 // Tests validate PutObject with subdirectory prefix.
 func testPutObjectInSubdir(obj ObjectLayer, instanceType string, c TestErrHandler) {
-	err := obj.MakeBucket("bucket")
+	err := obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -557,13 +558,13 @@ func testPutObjectInSubdir(obj ObjectLayer, instanceType string, c TestErrHandle
 	uploadContent := `The specified multipart upload does not exist. The upload ID might be invalid, or the multipart
  upload might have been aborted or completed.`
 	length := int64(len(uploadContent))
-	_, err = obj.PutObject("bucket", "dir1/dir2/object", length, bytes.NewBufferString(uploadContent), nil, "")
+	_, err = obj.PutObject(context.Background(), "bucket", "dir1/dir2/object", length, bytes.NewBufferString(uploadContent), nil, "")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
 
 	var bytesBuffer bytes.Buffer
-	err = obj.GetObject("bucket", "dir1/dir2/object", 0, length, &bytesBuffer)
+	err = obj.GetObject(context.Background(), "bucket", "dir1/dir2/object", 0, length, &bytesBuffer)
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -581,7 +582,7 @@ func (s *ObjectLayerAPISuite) TestListBuckets(c *C) {
 // Tests validate ListBuckets.
 func testListBuckets(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	// test empty list.
-	buckets, err := obj.ListBuckets()
+	buckets, err := obj.ListBuckets(context.Background())
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -590,12 +591,12 @@ func testListBuckets(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	}
 
 	// add one and test exists.
-	err = obj.MakeBucket("bucket1")
+	err = obj.MakeBucket(context.Background(), "bucket1")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
 
-	buckets, err = obj.ListBuckets()
+	buckets, err = obj.ListBuckets(context.Background())
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -604,12 +605,12 @@ func testListBuckets(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	}
 
 	// add two and test exists.
-	err = obj.MakeBucket("bucket2")
+	err = obj.MakeBucket(context.Background(), "bucket2")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
 
-	buckets, err = obj.ListBuckets()
+	buckets, err = obj.ListBuckets(context.Background())
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -618,12 +619,12 @@ func testListBuckets(obj ObjectLayer, instanceType string, c TestErrHandler) {
 	}
 
 	// add three and test exists + prefix.
-	err = obj.MakeBucket("bucket22")
+	err = obj.MakeBucket(context.Background(), "bucket22")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
 
-	buckets, err = obj.ListBuckets()
+	buckets, err = obj.ListBuckets(context.Background())
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -642,15 +643,15 @@ func testListBucketsOrder(obj ObjectLayer, instanceType string, c TestErrHandler
 	// if implementation contains a map, order of map keys will vary.
 	// this ensures they return in the same order each time.
 	// add one and test exists.
-	err := obj.MakeBucket("bucket1")
+	err := obj.MakeBucket(context.Background(), "bucket1")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
-	err = obj.MakeBucket("bucket2")
+	err = obj.MakeBucket(context.Background(), "bucket2")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
-	buckets, err := obj.ListBuckets()
+	buckets, err := obj.ListBuckets(context.Background())
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -673,7 +674,7 @@ func (s *ObjectLayerAPISuite) TestListObjectsTestsForNonExistantBucket(c *C) {
 
 // Tests validate that ListObjects operation on a non-existent bucket fails as expected.
 func testListObjectsTestsForNonExistantBucket(obj ObjectLayer, instanceType string, c TestErrHandler) {
-	result, err := obj.ListObjects("bucket", "", "", "", 1000)
+	result, err := obj.ListObjects(context.Background(), "bucket", "", "", "", 1000)
 	if err == nil {
 		c.Fatalf("%s: Expected error but found nil.", instanceType)
 	}
@@ -695,12 +696,12 @@ func (s *ObjectLayerAPISuite) TestNonExistantObjectInBucket(c *C) {
 
 // Tests validate that GetObject fails on a non-existent bucket as expected.
 func testNonExistantObjectInBucket(obj ObjectLayer, instanceType string, c TestErrHandler) {
-	err := obj.MakeBucket("bucket")
+	err := obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
 
-	_, err = obj.GetObjectInfo("bucket", "dir1")
+	_, err = obj.GetObjectInfo(context.Background(), "bucket", "dir1")
 	if err == nil {
 		c.Fatalf("%s: Expected error but found nil", instanceType)
 	}
@@ -732,12 +733,12 @@ func (s *ObjectLayerAPISuite) TestGetDirectoryReturnsObjectNotFound(c *C) {
 
 // Tests validate that GetObject on an existing directory fails as expected.
 func testGetDirectoryReturnsObjectNotFound(obj ObjectLayer, instanceType string, c TestErrHandler) {
-	err := obj.MakeBucket("bucket")
+	err := obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
 
-	_, err = obj.PutObject("bucket", "dir1/dir3/object",
+	_, err = obj.PutObject(context.Background(), "bucket", "dir1/dir3/object",
 		int64(len("The specified multipart upload does not exist. The upload ID might be invalid, or the multipart upload might have been aborted or completed.")),
 		bytes.NewBufferString("One or more of the specified parts could not be found. The part might not have been uploaded, or the specified entity tag might not have matched the part's entity tag."), nil, "")
 
@@ -745,7 +746,7 @@ func testGetDirectoryReturnsObjectNotFound(obj ObjectLayer, instanceType string,
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
 
-	_, err = obj.GetObjectInfo("bucket", "dir1")
+	_, err = obj.GetObjectInfo(context.Background(), "bucket", "dir1")
 	if isErrObjectNotFound(err) {
 		err = errorCause(err)
 		err1 := err.(ObjectNotFound)
@@ -764,7 +765,7 @@ func testGetDirectoryReturnsObjectNotFound(obj ObjectLayer, instanceType string,
 		}
 	}
 
-	_, err = obj.GetObjectInfo("bucket", "dir1/")
+	_, err = obj.GetObjectInfo(context.Background(), "bucket", "dir1/")
 	if isErrObjectNameInvalid(err) {
 		err = errorCause(err)
 		err1 := err.(ObjectNameInvalid)
@@ -791,17 +792,17 @@ func (s *ObjectLayerAPISuite) TestContentType(c *C) {
 
 // Test content-type.
 func testContentType(obj ObjectLayer, instanceType string, c TestErrHandler) {
-	err := obj.MakeBucket("bucket")
+	err := obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
 	uploadContent := "The specified multipart upload does not exist. The upload ID might be invalid, or the multipart upload might have been aborted or completed."
 	// Test empty.
-	_, err = obj.PutObject("bucket", "minio.png", int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
+	_, err = obj.PutObject(context.Background(), "bucket", "minio.png", int64(len(uploadContent)), bytes.NewBufferString(uploadContent), nil, "")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
-	objInfo, err := obj.GetObjectInfo("bucket", "minio.png")
+	objInfo, err := obj.GetObjectInfo(context.Background(), "bucket", "minio.png")
 	if err != nil {
 		c.Fatalf("%s: <ERROR> %s", instanceType, err)
 	}
@@ -0,0 +1,436 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/pkg/s3signer"
+	"github.com/minio/minio-go/pkg/s3utils"
+)
+
+// Objects written to a bucket with a replication configuration are
+// copied asynchronously to the destination of the matching rule.
+// Object created and removed events are queued for a pool of workers,
+// replications which fail are saved per bucket and retried in the
+// background until they succeed.
+
+const (
+	// Number of replications queued in memory, once the queue is full
+	// replications are saved as failed and retried later.
+	replicationQueueSize = 10000
+
+	// Number of workers replicating objects on every node.
+	replicationWorkers = 4
+
+	// Interval between two retries of failed replications.
+	replicationRetryInterval = 5 * time.Minute
+
+	// Failed replications config name.
+	bucketReplicationFailedConfig = "replication-failed.json"
+)
+
+// errReplicationNotSupported - objects encrypted with a client key can
+// not be read by the server and are never replicated.
+var errReplicationNotSupported = errors.New("Objects encrypted with a customer key are not replicated")
+
+// replicationTask - an object version to copy to, or an object to
+// delete from the destination of the replication rule matching it.
+type replicationTask struct {
+	Bucket    string `json:"bucket"`
+	Object    string `json:"object"`
+	VersionID string `json:"versionId,omitempty"`
+	ETag      string `json:"etag,omitempty"`
+	Delete    bool   `json:"delete,omitempty"`
+}
+
+// failedReplications - replications of a bucket waiting to be retried.
+type failedReplications struct {
+	Version string            `json:"version"`
+	Tasks   []replicationTask `json:"tasks"`
+}
+
+// Variable represents replications waiting for a worker.
+var globalReplicationQueue = make(chan replicationTask, replicationQueueSize)
+
+// queueReplication - queues the replication of an object created or
+// removed by a request, objects which are not replicated are ignored.
+func queueReplication(event eventData) {
+	rule := getReplicationRule(event.Bucket, event.ObjInfo.Name)
+	if rule == nil {
+		return
+	}
+
+	task := replicationTask{
+		Bucket: event.Bucket,
+		Object: event.ObjInfo.Name,
+	}
+	if event.Type == ObjectRemovedDelete {
+		// Deletes of a specific version are never replicated.
+		if !rule.replicatesDeletes() || event.ObjInfo.VersionID != "" {
+			return
+		}
+		task.Delete = true
+	} else {
+		// Replicas and objects written before the rule existed are
+		// not pending.
+		if event.ObjInfo.UserDefined[amzReplicationStatus] != replicationPending {
+			return
+		}
+		task.VersionID = event.ObjInfo.VersionID
+		task.ETag = event.ObjInfo.MD5Sum
+	}
+
+	select {
+	case globalReplicationQueue <- task:
+	default:
+		objAPI := newObjectLayerFn()
+		if objAPI == nil {
+			return
+		}
+		errorIf(addFailedReplication(task, objAPI), "Unable to save replication of %s/%s.", task.Bucket, task.Object)
+	}
+}
+
+// Start the replication workers. In a distributed setup only one node
+// needs to retry failed replications.
+func startReplication(objAPI ObjectLayer, retry bool) {
+	for i := 0; i < replicationWorkers; i++ {
+		go func() {
+			for {
+				select {
+				case task := <-globalReplicationQueue:
+					processReplication(task, objAPI)
+				case <-globalServiceDoneCh:
+					return
+				}
+			}
+		}()
+	}
+	if !retry {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(replicationRetryInterval)
+		defer ticker.Stop()
+
+		// Start with random sleep time, so as to not retry right
+		// while the server is busy coming up.
+		time.Sleep(time.Duration(rand.Float64() * float64(replicationRetryInterval)))
+		for {
+			retryFailedReplications(objAPI)
+			select {
+			case <-ticker.C:
+			case <-globalServiceDoneCh:
+				return
+			}
+		}
+	}()
+}
+
+// processReplication - replicates an object and saves the replication
+// for a retry if it fails.
+func processReplication(task replicationTask, objAPI ObjectLayer) {
+	err := replicateObject(task, objAPI)
+	if err == nil {
+		return
+	}
+	errorIf(err, "Unable to replicate %s/%s.", task.Bucket, task.Object)
+	if err == errReplicationNotSupported {
+		return
+	}
+	errorIf(addFailedReplication(task, objAPI), "Unable to save replication of %s/%s.", task.Bucket, task.Object)
+}
+
+// retryFailedReplications - retries the failed replications of all
+// buckets once.
+func retryFailedReplications(objAPI ObjectLayer) {
+	for bucket := range globalBucketReplication.GetAll() {
+		tasks, err := takeFailedReplications(bucket, objAPI)
+		if err != nil {
+			errorIf(err, "Unable to load failed replications of bucket %s.", bucket)
+			continue
+		}
+		for _, task := range tasks {
+			processReplication(task, objAPI)
+		}
+	}
+}
+
+// replicateObject - copies an object to, or deletes an object from the
+// destination of the rule replicating it. The replication status of
+// copied objects is updated as soon as the copy is done.
+func replicateObject(task replicationTask, objAPI ObjectLayer) error {
+	rule := getReplicationRule(task.Bucket, task.Object)
+	if rule == nil {
+		// Replication was turned off in the meantime.
+		return nil
+	}
+	if task.Delete {
+		return deleteReplica(rule.Destination, task.Object)
+	}
+
+	err := putReplica(rule.Destination, task, objAPI)
+	if err == errObjectReplaced {
+		// A newer write is replicated on its own.
+		return nil
+	}
+	status := replicationCompleted
+	if err != nil {
+		status = replicationFailed
+	}
+	if sErr := setReplicationStatus(task, status, objAPI); sErr != nil && sErr != errObjectReplaced {
+		errorIf(sErr, "Unable to update replication status of %s/%s.", task.Bucket, task.Object)
+	}
+	return err
+}
+
+// errObjectReplaced - the replicated object version no longer exists.
+var errObjectReplaced = errors.New("The object was replaced or removed")
+
+// Returns the current state of the object version a task replicates.
+func getReplicationObjectInfo(task replicationTask, objAPI ObjectLayer) (ObjectInfo, error) {
+	objInfo, err := objAPI.GetObjectVersionInfo(context.Background(), task.Bucket, task.Object, task.VersionID)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrVersionNotFound(err) {
+			return objInfo, errObjectReplaced
+		}
+		return objInfo, err
+	}
+	if objInfo.DeleteMarker || objInfo.MD5Sum != task.ETag {
+		return objInfo, errObjectReplaced
+	}
+	return objInfo, nil
+}
+
+// setReplicationStatus - saves the replication status of the object
+// version a task replicates.
+func setReplicationStatus(task replicationTask, status string, objAPI ObjectLayer) error {
+	objectLock := globalNSMutex.NewNSLock(task.Bucket, task.Object)
+	objectLock.Lock()
+	defer objectLock.Unlock()
+
+	if _, err := getReplicationObjectInfo(task, objAPI); err != nil {
+		return err
+	}
+	_, err := objAPI.UpdateObjectMetadata(context.Background(), task.Bucket, task.Object, task.VersionID, map[string]string{
+		amzReplicationStatus: status,
+	})
+	return err
+}
+
+// putReplica - copies an object version to the destination, along with
+// its user metadata and tags. Encrypted objects are encrypted again by
+// the destination.
+func putReplica(dest replicationDestination, task replicationTask, objAPI ObjectLayer) error {
+	objectLock := globalNSMutex.NewNSLock(task.Bucket, task.Object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	objInfo, err := getReplicationObjectInfo(task, objAPI)
+	if err != nil {
+		return err
+	}
+	if isSSECustomerEncrypted(objInfo.UserDefined) {
+		return errReplicationNotSupported
+	}
+	objectKey, s3Error := getSSEObjectKey(http.Header{}, "", &objInfo)
+	if s3Error != ErrNone {
+		return errors.New(getAPIError(s3Error).Description)
+	}
+
+	header := http.Header{}
+	for k, v := range objInfo.UserDefined {
+		if isReplicatedMetadata(k) {
+			header.Set(k, v)
+		}
+	}
+	if objInfo.UserTags != "" {
+		header.Set(amzTaggingHeader, objInfo.UserTags)
+	}
+	if objectKey != nil {
+		header.Set(amzServerSideEncryption, sseAlgorithmAES256)
+		if algorithm := objInfo.UserDefined[sseAlgorithmMetaKey]; algorithm != "" {
+			header.Set(amzServerSideEncryption, algorithm)
+		}
+	}
+	header.Set(amzReplicationStatus, replicationReplica)
+
+	reader, writer := io.Pipe()
+	go func() {
+		var gErr error
+		if objectKey != nil {
+			gErr = getEncryptedObject(context.Background(), objAPI, objInfo, task.VersionID, objectKey, 0, objInfo.Size, writer)
+		} else {
+			gErr = objAPI.GetObjectVersion(context.Background(), task.Bucket, task.Object, task.VersionID, 0, objInfo.Size, writer)
+		}
+		writer.CloseWithError(gErr)
+	}()
+	defer reader.Close()
+
+	return sendReplicationRequest(dest, httpPUT, task.Object, header, reader, objInfo.Size)
+}
+
+// Returns true for the metadata entries of an object which are copied
+// to its replicas. Object lock and encryption state are not copied,
+// they depend on the destination bucket.
+func isReplicatedMetadata(key string) bool {
+	if strings.HasPrefix(http.CanonicalHeaderKey(key), "X-Amz-Meta-") {
+		return true
+	}
+	for _, supportedHeader := range supportedHeaders {
+		if strings.EqualFold(key, supportedHeader) {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteReplica - deletes an object from the destination, objects which
+// do not exist there are already deleted.
+func deleteReplica(dest replicationDestination, object string) error {
+	return sendReplicationRequest(dest, httpDELETE, object, http.Header{}, nil, 0)
+}
+
+// HTTP client sending requests to replication destinations.
+var replicationHTTPClient = &http.Client{}
+
+// sendReplicationRequest - sends a request for an object to the
+// destination, signed with signature V4 and an unsigned payload.
+func sendReplicationRequest(dest replicationDestination, method, object string, header http.Header, body io.Reader, size int64) error {
+	u, err := url.Parse(dest.Endpoint)
+	if err != nil {
+		return err
+	}
+	u.Path = "/" + dest.bucketName() + "/" + object
+	u.RawPath = s3utils.EncodePath(u.Path)
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.ContentLength = size
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	req = s3signer.SignV4(*req, dest.AccessKey, dest.SecretKey, dest.region())
+
+	resp, err := replicationHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK, resp.StatusCode == http.StatusNoContent:
+		return nil
+	case method == httpDELETE && resp.StatusCode == http.StatusNotFound:
+		return nil
+	}
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("%s %s failed with %s: %s", method, u.String(), resp.Status, string(respBody))
+}
+
+// Returns the path of the failed replications of a bucket.
+func getFailedReplicationsPath(bucket string) string {
+	return path.Join(bucketConfigPrefix, bucket, bucketReplicationFailedConfig)
+}
+
+// readFailedReplications - reads the failed replications of a bucket,
+// the caller holds the lock of the path.
+func readFailedReplications(bucket string, objAPI ObjectLayer) (failedReplications, error) {
+	failed := failedReplications{Version: "1"}
+	var buffer bytes.Buffer
+	err := objAPI.GetObject(context.Background(), minioMetaBucket, getFailedReplicationsPath(bucket), 0, -1, &buffer)
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrIncompleteBody(err) {
+			return failed, nil
+		}
+		return failed, errorCause(err)
+	}
+	if err = json.Unmarshal(buffer.Bytes(), &failed); err != nil {
+		return failed, err
+	}
+	return failed, nil
+}
+
+// addFailedReplication - saves a failed replication, an earlier
+// replication of the same object is replaced.
+func addFailedReplication(task replicationTask, objAPI ObjectLayer) error {
+	frPath := getFailedReplicationsPath(task.Bucket)
+
+	// Acquire a write lock on failed replications before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, frPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	failed, err := readFailedReplications(task.Bucket, objAPI)
+	if err != nil {
+		return err
+	}
+	tasks := failed.Tasks[:0]
+	for _, t := range failed.Tasks {
+		if t.Object != task.Object || t.VersionID != task.VersionID {
+			tasks = append(tasks, t)
+		}
+	}
+	failed.Tasks = append(tasks, task)
+
+	buf, err := json.Marshal(failed)
+	if err != nil {
+		return err
+	}
+	sha256Sum := getSHA256Hash(buf)
+	if _, err = objAPI.PutObject(context.Background(), minioMetaBucket, frPath, int64(len(buf)), bytes.NewReader(buf), nil, sha256Sum); err != nil {
+		return errorCause(err)
+	}
+	return nil
+}
+
+// takeFailedReplications - returns and removes the failed replications
+// of a bucket, replications failing again are saved once more.
+func takeFailedReplications(bucket string, objAPI ObjectLayer) ([]replicationTask, error) {
+	frPath := getFailedReplicationsPath(bucket)
+
+	// Acquire a write lock on failed replications before modifying.
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, frPath)
+	objLock.Lock()
+	defer objLock.Unlock()
+
+	failed, err := readFailedReplications(bucket, objAPI)
+	if err != nil || len(failed.Tasks) == 0 {
+		return nil, err
+	}
+	if err = objAPI.DeleteObject(context.Background(), minioMetaBucket, frPath); err != nil && !isErrObjectNotFound(err) {
+		return nil, errorCause(err)
+	}
+	return failed.Tasks, nil
+}
@@ -69,6 +69,11 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 		return nil, err
 	}
 
+	// Add metrics and health check routers, before the web router
+	// which serves everything else under the reserved bucket.
+	registerMetricsRouter(mux)
+	registerHealthRouter(mux)
+
 	// Register web router when its enabled.
 	if globalIsBrowserEnabled {
 		if err := registerWebRouter(mux); err != nil {
@@ -79,6 +84,9 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 	// Add Admin router.
 	registerAdminRouter(mux)
 
+	// Add STS router.
+	registerSTSRouter(mux)
+
 	// Add API router.
 	registerAPIRouter(mux)
 
@@ -105,6 +113,21 @@ func configureServerHandler(srvCmdConfig serverCmdConfig) (http.Handler, error)
 		// routes them accordingly. Client receives a HTTP error for
 		// invalid/unsupported signatures.
 		setAuthHandler,
+		// Records requests against buckets with access logging
+		// enabled, logs are delivered to the target bucket.
+		setAccessLogHandler,
+		// Serves buckets as static websites on the website domain,
+		// website requests never reach the handlers above.
+		setWebsiteHandler,
+		// Records the count, duration and size of S3 requests.
+		setHTTPMetricsHandler,
+		// Traces requests while admins watch them.
+		setTraceHandler,
+		// Records served requests to the audit targets.
+		setAuditHandler,
+		// Assigns a unique ID to every request, carried by the
+		// log entries of the errors it hits.
+		setRequestIDHandler,
 		// Add new handlers here.
 	}
 
@@ -197,3 +197,18 @@ func S3PeersUpdateBucketPolicy(bucket string, pCh policyChange) {
 		)
 	}
 }
+
+// S3PeersLoadConfig - Sends reload request of a bucket configuration,
+// or of IAM users and policies, to all peers. Currently we log an
+// error and continue.
+func S3PeersLoadConfig(config, bucket string) {
+	args := &LoadConfigPeerArgs{Config: config, Bucket: bucket}
+	errs := globalS3Peers.SendUpdate(nil, args)
+	for idx, err := range errs {
+		errorIf(
+			err,
+			"Error sending reload %s to %s - %v",
+			config, globalS3Peers[idx].addr, err,
+		)
+	}
+}
@@ -116,3 +116,31 @@ func (s3 *s3PeerAPIHandlers) SetBucketPolicyPeer(args *SetBucketPolicyPeerArgs,
 
 	return s3.bms.UpdateBucketPolicy(args)
 }
+
+// LoadConfigPeerArgs - Arguments collection for LoadConfigPeer RPC call
+type LoadConfigPeerArgs struct {
+	// For Auth
+	AuthRPCArgs
+
+	// Name of the configuration, e.g. `lifecycle.xml` or `iam`.
+	Config string
+
+	// Bucket of the configuration, empty for IAM.
+	Bucket string
+}
+
+// BucketUpdate - implements bucket configuration updates, the
+// underlying operation is a network call asking all the peers to
+// reload the configuration from the backend.
+func (s *LoadConfigPeerArgs) BucketUpdate(client BucketMetaState) error {
+	return client.LoadConfig(s)
+}
+
+// tell receiving server to reload a bucket configuration or IAM
+func (s3 *s3PeerAPIHandlers) LoadConfigPeer(args *LoadConfigPeerArgs, reply *AuthRPCReply) error {
+	if err := args.IsAuthenticated(); err != nil {
+		return err
+	}
+
+	return s3.bms.LoadConfig(args)
+}
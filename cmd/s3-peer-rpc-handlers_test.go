@@ -100,6 +100,25 @@ func (s *TestRPCS3PeerSuite) testS3PeerRPC(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// Check bucket configuration reload call works.
+	globalBucketLifecycles.Set("bucket", &lifecycleConfig{})
+	LCPArgs := LoadConfigPeerArgs{Config: bucketLifecycleConfig, Bucket: "bucket"}
+	err = client.Call("S3.LoadConfigPeer", &LCPArgs, &AuthRPCReply{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The configuration is not saved in the backend, reloading forgets it.
+	if globalBucketLifecycles.Get("bucket") != nil {
+		t.Fatal("Expected lifecycle configuration to be removed after reload")
+	}
+
+	// Check reload of an unknown configuration fails.
+	LCPArgs = LoadConfigPeerArgs{Config: "unknown.xml", Bucket: "bucket"}
+	err = client.Call("S3.LoadConfigPeer", &LCPArgs, &AuthRPCReply{})
+	if err == nil || err.Error() != errInvalidArgument.Error() {
+		t.Fatalf("Expected %v, got %v", errInvalidArgument, err)
+	}
+
 	// Check event send event call works.
 	evArgs := EventArgs{Event: nil, Arn: "localhost:9000"}
 	err = client.Call("S3.Event", &evArgs, &AuthRPCReply{})
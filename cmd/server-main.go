@@ -62,6 +62,25 @@ ENVIRONMENT VARIABLES:
   BROWSER:
      MINIO_BROWSER: To disable web browser access, set this value to "off".
 
+  WEBSITE:
+     MINIO_WEBSITE_DOMAIN: Domain to serve buckets with a website configuration on, as "<bucket>.<domain>".
+
+  LDAP:
+     MINIO_LDAP_SERVER_URL: LDAP server users log in against, as "ldap://host:port" or "ldaps://host:port". Overrides the "ldap" section of the config.
+     MINIO_LDAP_USER_DN_FORMAT: DN users bind with, "%s" is replaced by the username.
+     MINIO_LDAP_GROUP_SEARCH_BASE_DN: Base DN the groups of a user are searched under.
+     MINIO_LDAP_GROUP_SEARCH_FILTER: Filter of the groups of a user, "%s" is replaced by the username and "%d" by the user DN.
+     MINIO_LDAP_GROUP_NAME_ATTRIBUTE: Attribute of the group name, the IAM policy of the same name applies. Defaults to "cn".
+     MINIO_LDAP_STARTTLS: To secure "ldap://" connections with StartTLS, set this value to "on".
+     MINIO_LDAP_TLS_SKIP_VERIFY: To skip verifying the LDAP server certificate, set this value to "on".
+
+  OPENID:
+     MINIO_IDENTITY_OPENID_ISSUER: Issuer of the ID tokens users log in with, as in the "iss" claim.
+     MINIO_IDENTITY_OPENID_JWKS_URL: URL of the key set verifying the ID tokens. Overrides the "openid" section of the config.
+     MINIO_IDENTITY_OPENID_JWKS_FILE: File holding a static key set, instead of MINIO_IDENTITY_OPENID_JWKS_URL. Overrides the "openid" section of the config.
+     MINIO_IDENTITY_OPENID_CLIENT_ID: Client ID the ID tokens must be issued to, as in the "aud" claim.
+     MINIO_IDENTITY_OPENID_CLAIM_NAME: Claim naming the IAM policies of the user. Defaults to "policy".
+
 EXAMPLES:
   1. Start minio server on "/home/shared" directory.
       $ minio {{.Name}} /home/shared
@@ -388,6 +407,18 @@ func serverMain(c *cli.Context) {
 	// Initialize server config.
 	initServerConfig(c)
 
+	// Initialize the key management service of SSE-S3 and SSE-KMS.
+	initKMS()
+
+	// Initialize LDAP user authentication.
+	initLDAP()
+
+	// Initialize OpenID Connect user authentication.
+	initOpenID()
+
+	// Initialize audit log targets.
+	initAudit()
+
 	// Disks to be used in server init.
 	endpoints, err := parseStorageEndpoints(c.Args())
 	fatalIf(err, "Unable to parse storage endpoints %s", c.Args())
@@ -469,6 +500,19 @@ func serverMain(c *cli.Context) {
 	globalObjectAPI = newObject
 	globalObjLayerMutex.Unlock()
 
+	// Sweep expired objects and incomplete uploads in the background,
+	// only the node serving the first endpoint runs the sweeper.
+	if isLocalStorage(endpoints[0]) {
+		startLifecycleSweeper(newObject)
+	}
+
+	// Deliver access logs of buckets with access logging enabled.
+	startAccessLogFlusher(newObject)
+
+	// Replicate objects of buckets with a replication configuration,
+	// only the node serving the first endpoint retries failures.
+	startReplication(newObject, isLocalStorage(endpoints[0]))
+
 	// Prints the formatted startup message once object layer is initialized.
 	printStartupMessage(apiEndPoints)
 
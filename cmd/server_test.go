@@ -1642,9 +1642,9 @@ func (s *TestSuiteCommon) TestPutBucketErrors(c *C) {
 	verifyError(c, response, "BucketAlreadyOwnedByYou", "Your previous request to create the named bucket succeeded and you already own it.",
 		http.StatusConflict)
 
-	// request for ACL.
-	// Since Minio server doesn't support ACL's the request is expected to fail with  "NotImplemented" error message.
-	request, err = newTestSignedRequest("PUT", s.endPoint+"/"+bucketName+"?acl",
+	// request for request payment.
+	// Since Minio server doesn't support request payment the request is expected to fail with  "NotImplemented" error message.
+	request, err = newTestSignedRequest("PUT", s.endPoint+"/"+bucketName+"?requestPayment",
 		0, nil, s.accessKey, s.secretKey, s.signer)
 	c.Assert(err, IsNil)
 
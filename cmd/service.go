@@ -97,6 +97,7 @@ func (m *ServerMux) handleServiceSignals() error {
 			if err := m.Close(); err != nil {
 				errorIf(err, "Unable to close server gracefully")
 			}
+			globalAuditLogger.Close()
 			if err := restartProcess(); err != nil {
 				errorIf(err, "Unable to restart the server.")
 			}
@@ -105,6 +106,7 @@ func (m *ServerMux) handleServiceSignals() error {
 			if err := m.Close(); err != nil {
 				errorIf(err, "Unable to close server gracefully")
 			}
+			globalAuditLogger.Close()
 			objAPI := newObjectLayerFn()
 			if objAPI == nil {
 				// Server not initialized yet, exit happily.
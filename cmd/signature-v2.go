@@ -65,11 +65,14 @@ var resourceList = []string{
 }
 
 func doesPolicySignatureV2Match(formValues map[string]string) APIErrorCode {
-	cred := serverConfig.GetCredential()
 	accessKey := formValues["Awsaccesskeyid"]
-	if accessKey != cred.AccessKey {
+	cred, ok := getCredentialForAccessKey(accessKey)
+	if !ok {
 		return ErrInvalidAccessKeyID
 	}
+	if s3Error := validateSessionToken(cred, formValues[amzSecurityToken]); s3Error != ErrNone {
+		return s3Error
+	}
 	signature := formValues["Signature"]
 	policy := formValues["Policy"]
 	if signature != calculateSignatureV2(policy, cred.SecretKey) {
@@ -82,9 +85,6 @@ func doesPolicySignatureV2Match(formValues map[string]string) APIErrorCode {
 //     - http://docs.aws.amazon.com/AmazonS3/latest/dev/RESTAuthentication.html#RESTAuthenticationQueryStringAuth
 // returns ErrNone if matches. S3 errors otherwise.
 func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// url.RawPath will be valid if path has any encoded characters, if not it will
 	// be empty - in which case we need to consider url.Path (bug in net/http?)
 	encodedResource := r.URL.RawPath
@@ -121,7 +121,7 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 		}
 		// Check if the query unescaped properly.
 		if err != nil {
-			errorIf(err, "Unable to unescape query values", queries)
+			errorIfCtx(r.Context(), err, "Unable to unescape query values", queries)
 			return ErrInvalidQueryParams
 		}
 	}
@@ -131,11 +131,17 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 		return ErrInvalidQueryParams
 	}
 
-	// Validate if access key id same.
-	if accessKey != cred.AccessKey {
+	// Validate if access key id is known.
+	cred, ok := getCredentialForAccessKey(accessKey)
+	if !ok {
 		return ErrInvalidAccessKeyID
 	}
 
+	// Validate the session token of temporary credentials.
+	if s3Error := validateSessionToken(cred, getSessionToken(r)); s3Error != ErrNone {
+		return s3Error
+	}
+
 	// Make sure the request has not expired.
 	expiresInt, err := strconv.ParseInt(expires, 10, 64)
 	if err != nil {
@@ -147,7 +153,7 @@ func doesPresignV2SignatureMatch(r *http.Request) APIErrorCode {
 		return ErrExpiredPresignRequest
 	}
 
-	expectedSignature := preSignatureV2(r.Method, encodedResource, strings.Join(filteredQueries, "&"), r.Header, expires)
+	expectedSignature := preSignatureV2(cred, r.Method, encodedResource, strings.Join(filteredQueries, "&"), r.Header, expires)
 	if gotSignature != expectedSignature {
 		return ErrSignatureDoesNotMatch
 	}
@@ -198,8 +204,7 @@ func validateV2AuthHeader(v2Auth string) APIErrorCode {
 	}
 
 	// Access credentials.
-	cred := serverConfig.GetCredential()
-	if keySignFields[0] != cred.AccessKey {
+	if _, ok := getCredentialForAccessKey(keySignFields[0]); !ok {
 		return ErrInvalidAccessKeyID
 	}
 
@@ -213,6 +218,14 @@ func doesSignV2Match(r *http.Request) APIErrorCode {
 		return apiError
 	}
 
+	// Credentials of the access key, known to exist after validation.
+	cred, _ := getCredentialForAccessKey(getRequestAccessKey(r))
+
+	// Validate the session token of temporary credentials.
+	if s3Error := validateSessionToken(cred, getSessionToken(r)); s3Error != ErrNone {
+		return s3Error
+	}
+
 	// Encode path:
 	//   url.RawPath will be valid if path has any encoded characters, if not it will
 	//   be empty - in which case we need to consider url.Path (bug in net/http?)
@@ -227,7 +240,7 @@ func doesSignV2Match(r *http.Request) APIErrorCode {
 	// Encode query strings
 	encodedQuery := r.URL.Query().Encode()
 
-	expectedAuth := signatureV2(r.Method, encodedResource, encodedQuery, r.Header)
+	expectedAuth := signatureV2(cred, r.Method, encodedResource, encodedQuery, r.Header)
 	if v2Auth != expectedAuth {
 		return ErrSignatureDoesNotMatch
 	}
@@ -242,15 +255,13 @@ func calculateSignatureV2(stringToSign string, secret string) string {
 }
 
 // Return signature-v2 for the presigned request.
-func preSignatureV2(method string, encodedResource string, encodedQuery string, headers http.Header, expires string) string {
-	cred := serverConfig.GetCredential()
+func preSignatureV2(cred credential, method string, encodedResource string, encodedQuery string, headers http.Header, expires string) string {
 	stringToSign := presignV2STS(method, encodedResource, encodedQuery, headers, expires)
 	return calculateSignatureV2(stringToSign, cred.SecretKey)
 }
 
 // Return signature-v2 authrization header.
-func signatureV2(method string, encodedResource string, encodedQuery string, headers http.Header) string {
-	cred := serverConfig.GetCredential()
+func signatureV2(cred credential, method string, encodedResource string, encodedQuery string, headers http.Header) string {
 	stringToSign := signV2STS(method, encodedResource, encodedQuery, headers)
 	signature := calculateSignatureV2(stringToSign, cred.SecretKey)
 	return fmt.Sprintf("%s %s:%s", signV2Algorithm, cred.AccessKey, signature)
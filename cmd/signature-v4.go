@@ -155,13 +155,22 @@ func doesPolicySignatureMatch(formValues map[string]string) APIErrorCode {
 	return doesPolicySignatureV4Match(formValues)
 }
 
+// Returns the access key a post policy is signed with.
+func getPolicyAccessKey(formValues map[string]string) string {
+	if formValues["Signature"] != "" {
+		return formValues["Awsaccesskeyid"]
+	}
+	credHeader, err := parseCredentialHeader("Credential=" + formValues["X-Amz-Credential"])
+	if err != ErrNone {
+		return ""
+	}
+	return credHeader.accessKey
+}
+
 // doesPolicySignatureMatch - Verify query headers with post policy
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-HTTPPOSTConstructPolicy.html
 // returns ErrNone if the signature matches.
 func doesPolicySignatureV4Match(formValues map[string]string) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// Server region.
 	region := serverConfig.GetRegion()
 
@@ -171,11 +180,17 @@ func doesPolicySignatureV4Match(formValues map[string]string) APIErrorCode {
 		return ErrMissingFields
 	}
 
-	// Verify if the access key id matches.
-	if credHeader.accessKey != cred.AccessKey {
+	// Verify if the access key id is known.
+	cred, ok := getCredentialForAccessKey(credHeader.accessKey)
+	if !ok {
 		return ErrInvalidAccessKeyID
 	}
 
+	// Validate the session token of temporary credentials.
+	if s3Error := validateSessionToken(cred, formValues[amzSecurityToken]); s3Error != ErrNone {
+		return s3Error
+	}
+
 	// Verify if the region is valid.
 	sRegion := credHeader.scope.region
 	if !isValidRegion(sRegion, region) {
@@ -205,9 +220,6 @@ func doesPolicySignatureV4Match(formValues map[string]string) APIErrorCode {
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-query-string-auth.html
 // returns ErrNone if the signature matches.
 func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region string) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// Copy request
 	req := *r
 
@@ -217,11 +229,18 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 		return err
 	}
 
-	// Verify if the access key id matches.
-	if pSignValues.Credential.accessKey != cred.AccessKey {
+	// Verify if the access key id is known.
+	cred, ok := getCredentialForAccessKey(pSignValues.Credential.accessKey)
+	if !ok {
 		return ErrInvalidAccessKeyID
 	}
 
+	// Validate the session token of temporary credentials.
+	sessionToken := getSessionToken(&req)
+	if s3Error := validateSessionToken(cred, sessionToken); s3Error != ErrNone {
+		return s3Error
+	}
+
 	// Hashed payload mismatch, return content sha256 mismatch.
 	contentSha256 := req.URL.Query().Get("X-Amz-Content-Sha256")
 	if contentSha256 != "" && hashedPayload != contentSha256 {
@@ -270,6 +289,9 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 	query.Set("X-Amz-Expires", strconv.Itoa(expireSeconds))
 	query.Set("X-Amz-SignedHeaders", getSignedHeaders(extractedSignedHeaders))
 	query.Set("X-Amz-Credential", cred.AccessKey+"/"+getScope(t, sRegion))
+	if sessionToken != "" {
+		query.Set(amzSecurityToken, sessionToken)
+	}
 
 	// Save other headers available in the request parameters.
 	for k, v := range req.URL.Query() {
@@ -330,9 +352,6 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
 // returns ErrNone if signature matches.
 func doesSignatureMatch(hashedPayload string, r *http.Request, region string) APIErrorCode {
-	// Access credentials.
-	cred := serverConfig.GetCredential()
-
 	// Copy request.
 	req := *r
 
@@ -369,11 +388,17 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 		return errCode
 	}
 
-	// Verify if the access key id matches.
-	if signV4Values.Credential.accessKey != cred.AccessKey {
+	// Verify if the access key id is known.
+	cred, ok := getCredentialForAccessKey(signV4Values.Credential.accessKey)
+	if !ok {
 		return ErrInvalidAccessKeyID
 	}
 
+	// Validate the session token of temporary credentials.
+	if s3Error := validateSessionToken(cred, getSessionToken(&req)); s3Error != ErrNone {
+		return s3Error
+	}
+
 	// Verify if region is valid.
 	sRegion := signV4Values.Credential.scope.region
 	// Region is set to be empty, we use whatever was sent by the
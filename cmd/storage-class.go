@@ -0,0 +1,96 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	// Storage class header of PutObject, CopyObject and
+	// NewMultipartUpload, objects of the reduced redundancy class keep
+	// it in their metadata and report it on GET and HEAD.
+	amzStorageClassHeader = "X-Amz-Storage-Class"
+
+	// Supported storage classes, STANDARD is the default.
+	standardStorageClass          = globalMinioDefaultStorageClass
+	reducedRedundancyStorageClass = "REDUCED_REDUNDANCY"
+
+	// Minimum parity blocks of any storage class.
+	minStorageClassParity = 2
+)
+
+// storageClass - erasure coding of a storage class, zero parity picks
+// the default of the class.
+type storageClass struct {
+	Parity int `json:"parity"`
+}
+
+// storageClassConfig - erasure coding of the storage classes. Objects
+// of the STANDARD class default to half of the disks as parity, objects
+// of the REDUCED_REDUNDANCY class to the minimum parity.
+type storageClassConfig struct {
+	Standard storageClass `json:"standard"`
+	RRS      storageClass `json:"rrs"`
+}
+
+// Returns the parity blocks of the STANDARD and REDUCED_REDUNDANCY
+// classes on a set of disks. Parity has to lie between the minimum
+// parity and half of the disks, REDUCED_REDUNDANCY parity may not
+// exceed STANDARD parity.
+func getStorageClassParity(cfg storageClassConfig, disks int) (standardParity, rrsParity int, err error) {
+	standardParity = cfg.Standard.Parity
+	if standardParity == 0 {
+		standardParity = disks / 2
+	}
+	rrsParity = cfg.RRS.Parity
+	if rrsParity == 0 {
+		rrsParity = minStorageClassParity
+	}
+	if standardParity < minStorageClassParity || standardParity > disks/2 {
+		return 0, 0, fmt.Errorf("Parity %d of storage class %s should be between %d and %d",
+			standardParity, standardStorageClass, minStorageClassParity, disks/2)
+	}
+	if rrsParity < minStorageClassParity || rrsParity > standardParity {
+		return 0, 0, fmt.Errorf("Parity %d of storage class %s should be between %d and %d",
+			rrsParity, reducedRedundancyStorageClass, minStorageClassParity, standardParity)
+	}
+	return standardParity, rrsParity, nil
+}
+
+// setStorageClassFromHeader - saves the storage class of the
+// `x-amz-storage-class` header into the metadata of an object about to
+// be written, objects of the STANDARD class carry no storage class.
+func setStorageClassFromHeader(header http.Header, metadata map[string]string) APIErrorCode {
+	switch header.Get(amzStorageClassHeader) {
+	case "", standardStorageClass:
+	case reducedRedundancyStorageClass:
+		metadata[amzStorageClassHeader] = reducedRedundancyStorageClass
+	default:
+		return ErrInvalidStorageClass
+	}
+	return ErrNone
+}
+
+// Returns the storage class of an object from its metadata.
+func getStorageClass(metadata map[string]string) string {
+	if class, ok := metadata[amzStorageClassHeader]; ok {
+		return class
+	}
+	return standardStorageClass
+}
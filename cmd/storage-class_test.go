@@ -0,0 +1,107 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"testing"
+)
+
+// Tests parity of the storage classes on a set of disks.
+func TestGetStorageClassParity(t *testing.T) {
+	testCases := []struct {
+		config         storageClassConfig
+		disks          int
+		standardParity int
+		rrsParity      int
+		shouldPass     bool
+	}{
+		// Test case - 1.
+		// Defaults.
+		{storageClassConfig{}, 16, 8, 2, true},
+		// Test case - 2.
+		{storageClassConfig{}, 4, 2, 2, true},
+		// Test case - 3.
+		{storageClassConfig{Standard: storageClass{Parity: 4}}, 16, 4, 2, true},
+		// Test case - 4.
+		{storageClassConfig{Standard: storageClass{Parity: 6}, RRS: storageClass{Parity: 3}}, 12, 6, 3, true},
+		// Test case - 5.
+		// Standard parity beyond half of the disks.
+		{storageClassConfig{Standard: storageClass{Parity: 9}}, 16, 0, 0, false},
+		// Test case - 6.
+		// Standard parity below the minimum.
+		{storageClassConfig{Standard: storageClass{Parity: 1}}, 16, 0, 0, false},
+		// Test case - 7.
+		// RRS parity exceeding standard parity.
+		{storageClassConfig{Standard: storageClass{Parity: 4}, RRS: storageClass{Parity: 6}}, 16, 0, 0, false},
+		// Test case - 8.
+		// RRS parity below the minimum.
+		{storageClassConfig{RRS: storageClass{Parity: 1}}, 16, 0, 0, false},
+		// Test case - 9.
+		// Default RRS parity exceeding standard parity.
+		{storageClassConfig{}, 2, 0, 0, false},
+	}
+	for i, testCase := range testCases {
+		standardParity, rrsParity, err := getStorageClassParity(testCase.config, testCase.disks)
+		if err != nil && testCase.shouldPass {
+			t.Errorf("Test %d: Expected to pass, but failed with: %s", i+1, err)
+		}
+		if err == nil && !testCase.shouldPass {
+			t.Errorf("Test %d: Expected to fail, but passed", i+1)
+		}
+		if standardParity != testCase.standardParity || rrsParity != testCase.rrsParity {
+			t.Errorf("Test %d: Expected parity %d/%d, found %d/%d", i+1,
+				testCase.standardParity, testCase.rrsParity, standardParity, rrsParity)
+		}
+	}
+}
+
+// Tests saving the storage class header into object metadata.
+func TestSetStorageClassFromHeader(t *testing.T) {
+	testCases := []struct {
+		storageClass string
+		expected     string
+		s3Error      APIErrorCode
+	}{
+		// Test case - 1.
+		{"", standardStorageClass, ErrNone},
+		// Test case - 2.
+		{"STANDARD", standardStorageClass, ErrNone},
+		// Test case - 3.
+		{"REDUCED_REDUNDANCY", reducedRedundancyStorageClass, ErrNone},
+		// Test case - 4.
+		{"GLACIER", standardStorageClass, ErrInvalidStorageClass},
+		// Test case - 5.
+		{"reduced_redundancy", standardStorageClass, ErrInvalidStorageClass},
+	}
+	for i, testCase := range testCases {
+		header := http.Header{}
+		if testCase.storageClass != "" {
+			header.Set(amzStorageClassHeader, testCase.storageClass)
+		}
+		metadata := make(map[string]string)
+		if s3Error := setStorageClassFromHeader(header, metadata); s3Error != testCase.s3Error {
+			t.Errorf("Test %d: Expected error %d, found %d", i+1, testCase.s3Error, s3Error)
+		}
+		if class := getStorageClass(metadata); class != testCase.expected {
+			t.Errorf("Test %d: Expected storage class %s, found %s", i+1, testCase.expected, class)
+		}
+		if _, ok := metadata[amzStorageClassHeader]; ok && testCase.expected == standardStorageClass {
+			t.Errorf("Test %d: Expected no storage class in the metadata of STANDARD objects", i+1)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// STS API version accepted by the server.
+	stsAPIVersion = "2011-06-15"
+
+	// STS request parameters.
+	stsVersion          = "Version"
+	stsDurationSeconds  = "DurationSeconds"
+	stsPolicy           = "Policy"
+	stsLDAPUsername     = "LDAPUsername"
+	stsLDAPPassword     = "LDAPPassword"
+	stsWebIdentityToken = "WebIdentityToken"
+)
+
+// AssumeRoleResponse - format of the AssumeRole response.
+type AssumeRoleResponse struct {
+	XMLName          xml.Name         `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleResponse" json:"-"`
+	Result           AssumeRoleResult `xml:"AssumeRoleResult"`
+	ResponseMetadata struct {
+		RequestID string `xml:"RequestId"`
+	} `xml:"ResponseMetadata"`
+}
+
+// AssumeRoleResult - temporary credentials issued by AssumeRole.
+type AssumeRoleResult struct {
+	Credentials struct {
+		AccessKeyID     string `xml:"AccessKeyId"`
+		SecretAccessKey string `xml:"SecretAccessKey"`
+		SessionToken    string `xml:"SessionToken"`
+		Expiration      string `xml:"Expiration"`
+	} `xml:"Credentials"`
+}
+
+// AssumeRoleWithLDAPIdentityResponse - format of the
+// AssumeRoleWithLDAPIdentity response.
+type AssumeRoleWithLDAPIdentityResponse struct {
+	XMLName          xml.Name         `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleWithLDAPIdentityResponse" json:"-"`
+	Result           AssumeRoleResult `xml:"AssumeRoleWithLDAPIdentityResult"`
+	ResponseMetadata struct {
+		RequestID string `xml:"RequestId"`
+	} `xml:"ResponseMetadata"`
+}
+
+// AssumeRoleWithWebIdentityResponse - format of the
+// AssumeRoleWithWebIdentity response.
+type AssumeRoleWithWebIdentityResponse struct {
+	XMLName          xml.Name         `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleWithWebIdentityResponse" json:"-"`
+	Result           AssumeRoleResult `xml:"AssumeRoleWithWebIdentityResult"`
+	ResponseMetadata struct {
+		RequestID string `xml:"RequestId"`
+	} `xml:"ResponseMetadata"`
+}
+
+// parseSTSParams - parses the parameters common to all the STS
+// actions, accepted in the query as well as in a form body.
+func parseSTSParams(r *http.Request) (time.Duration, *bucketPolicy, APIErrorCode) {
+	if err := r.ParseForm(); err != nil {
+		return 0, nil, ErrSTSInvalidParameterValue
+	}
+	if version := r.Form.Get(stsVersion); version != "" && version != stsAPIVersion {
+		return 0, nil, ErrSTSInvalidParameterValue
+	}
+
+	duration := defaultSTSDuration
+	if durationStr := r.Form.Get(stsDurationSeconds); durationStr != "" {
+		seconds, err := strconv.Atoi(durationStr)
+		if err != nil {
+			return 0, nil, ErrSTSInvalidParameterValue
+		}
+		duration = time.Duration(seconds) * time.Second
+		if duration < minSTSDuration || duration > maxSTSDuration {
+			return 0, nil, ErrSTSInvalidParameterValue
+		}
+	}
+
+	var policy *bucketPolicy
+	if policyStr := r.Form.Get(stsPolicy); policyStr != "" {
+		if len(policyStr) > maxAccessPolicySize {
+			return 0, nil, ErrSTSMalformedPolicyDocument
+		}
+		policy = &bucketPolicy{}
+		if err := parseIAMPolicy(strings.NewReader(policyStr), policy); err != nil {
+			errorIfCtx(r.Context(), err, "Unable to parse session policy.")
+			return 0, nil, ErrSTSMalformedPolicyDocument
+		}
+	}
+	return duration, policy, ErrNone
+}
+
+// AssumeRoleHandler - POST /?Action=AssumeRole
+// ----------
+// Issues temporary credentials to the user the request is signed by,
+// they expire after DurationSeconds (one hour by default) and are
+// allowed at most what the user is allowed, further limited by the
+// optional session Policy.
+func (sts stsAPIHandlers) AssumeRoleHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Temporary credentials are issued to the signer of the request.
+	var s3Error APIErrorCode
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypePresigned:
+		s3Error = isReqAuthenticated(r, serverConfig.GetRegion())
+	case authTypeSignedV2, authTypePresignedV2:
+		s3Error = isReqAuthenticatedV2(r)
+	default:
+		s3Error = ErrAccessDenied
+	}
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+	parentUser := getRequestAccessKey(r)
+
+	duration, policy, s3Error := parseSTSParams(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	tempUser, err := assumeRole(parentUser, duration, policy, objectAPI)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to issue temporary credentials.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	response := AssumeRoleResponse{}
+	response.Result.Credentials.AccessKeyID = tempUser.Credential.AccessKey
+	response.Result.Credentials.SecretAccessKey = tempUser.Credential.SecretKey
+	response.Result.Credentials.SessionToken = tempUser.Credential.SessionToken
+	response.Result.Credentials.Expiration = tempUser.Expiration.Format(timeFormatAMZLong)
+	response.ResponseMetadata.RequestID = mustGetRequestID(time.Now().UTC())
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// AssumeRoleWithLDAPIdentityHandler - POST /?Action=AssumeRoleWithLDAPIdentity
+// ----------
+// Issues temporary credentials to a user authenticated against the LDAP
+// directory with LDAPUsername and LDAPPassword, the request is not
+// signed. The credentials are allowed what the policies named after
+// the LDAP groups of the user allow, further limited by the optional
+// session Policy.
+func (sts stsAPIHandlers) AssumeRoleWithLDAPIdentityHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	duration, policy, s3Error := parseSTSParams(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if globalLDAPConfig == nil {
+		writeErrorResponse(w, ErrSTSLDAPNotConfigured, r.URL)
+		return
+	}
+	userDN, groups, err := globalLDAPConfig.authenticate(r.Form.Get(stsLDAPUsername), r.Form.Get(stsLDAPPassword))
+	if err != nil {
+		if err != errLDAPAuthentication {
+			errorIfCtx(r.Context(), err, "Unable to authenticate LDAP user.")
+		}
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	tempUser, err := assumeRoleWithLDAP(userDN, groups, duration, policy, objectAPI)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to issue temporary credentials.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	response := AssumeRoleWithLDAPIdentityResponse{}
+	response.Result.Credentials.AccessKeyID = tempUser.Credential.AccessKey
+	response.Result.Credentials.SecretAccessKey = tempUser.Credential.SecretKey
+	response.Result.Credentials.SessionToken = tempUser.Credential.SessionToken
+	response.Result.Credentials.Expiration = tempUser.Expiration.Format(timeFormatAMZLong)
+	response.ResponseMetadata.RequestID = mustGetRequestID(time.Now().UTC())
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// AssumeRoleWithWebIdentityHandler - POST /?Action=AssumeRoleWithWebIdentity
+// ----------
+// Issues temporary credentials to a user authenticated by an ID token
+// of the OpenID Connect provider sent as WebIdentityToken, the request
+// is not signed. The credentials are allowed what the policies named
+// by the configured claim of the token allow, further limited by the
+// optional session Policy.
+func (sts stsAPIHandlers) AssumeRoleWithWebIdentityHandler(w http.ResponseWriter, r *http.Request) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	duration, policy, s3Error := parseSTSParams(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if globalOpenIDConfig == nil {
+		writeErrorResponse(w, ErrSTSOpenIDNotConfigured, r.URL)
+		return
+	}
+	subject, policies, err := globalOpenIDConfig.validateToken(r.Form.Get(stsWebIdentityToken))
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	tempUser, err := assumeRoleWithOpenID(subject, policies, duration, policy, objectAPI)
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to issue temporary credentials.")
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	response := AssumeRoleWithWebIdentityResponse{}
+	response.Result.Credentials.AccessKeyID = tempUser.Credential.AccessKey
+	response.Result.Credentials.SecretAccessKey = tempUser.Credential.SecretKey
+	response.Result.Credentials.SessionToken = tempUser.Credential.SessionToken
+	response.Result.Credentials.Expiration = tempUser.Expiration.Format(timeFormatAMZLong)
+	response.ResponseMetadata.RequestID = mustGetRequestID(time.Now().UTC())
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
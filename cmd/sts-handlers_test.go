@@ -0,0 +1,412 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	router "github.com/gorilla/mux"
+)
+
+// Returns a request signed with signature v4 by temporary credentials.
+func newTestSignedRequestV4WithToken(method, urlStr string, cred credential) (*http.Request, error) {
+	req, err := newTestRequest(method, urlStr, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(amzSecurityToken, cred.SessionToken)
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func TestAssumeRoleHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for STS handler tests.")
+	}
+	defer adminTestBed.TearDown()
+	initGlobalS3Peers(nil)
+
+	mux := router.NewRouter()
+	registerSTSRouter(mux)
+	registerAPIRouter(mux)
+
+	bucketName := getRandomBucketName()
+	if err = adminTestBed.objLayer.MakeBucket(context.Background(), bucketName); err != nil {
+		t.Fatalf("Failed to make bucket - %v", err)
+	}
+	if _, err = adminTestBed.objLayer.PutObject(context.Background(), bucketName, "object", 4, bytes.NewReader([]byte("data")), nil, ""); err != nil {
+		t.Fatalf("Failed to put object - %v", err)
+	}
+
+	// The parent user may read and write objects in the bucket.
+	userCred := credential{AccessKey: "stsuser", SecretKey: "stsuser123"}
+	if err = setIAMUser(userCred, iamUserEnabled, adminTestBed.objLayer); err != nil {
+		t.Fatalf("Failed to add user - %v", err)
+	}
+	readWritePolicy := mustParseIAMPolicy(t, `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject", "s3:PutObject"], "Resource": ["arn:aws:s3:::`+bucketName+`/*"]}]}`)
+	if err = setIAMPolicy("readwrite", readWritePolicy, adminTestBed.objLayer); err != nil {
+		t.Fatalf("Failed to add policy - %v", err)
+	}
+	if err = setIAMUserPolicies(userCred.AccessKey, []string{"readwrite"}, adminTestBed.objLayer); err != nil {
+		t.Fatalf("Failed to attach policy - %v", err)
+	}
+
+	// The session policy only allows reads.
+	sessionPolicy := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::` + bucketName + `/*"]}]}`
+	assumeRoleURL := func(params url.Values) string {
+		params.Set("Action", "AssumeRole")
+		return "/?" + params.Encode()
+	}
+
+	testCases := []struct {
+		urlStr     string
+		cred       credential
+		statusCode int
+	}{
+		// 1. Anonymous requests are rejected.
+		{assumeRoleURL(url.Values{}), credential{}, http.StatusForbidden},
+		// 2. Duration too short.
+		{assumeRoleURL(url.Values{"DurationSeconds": {"60"}}), userCred, http.StatusBadRequest},
+		// 3. Duration too long.
+		{assumeRoleURL(url.Values{"DurationSeconds": {"86400"}}), userCred, http.StatusBadRequest},
+		// 4. Malformed duration.
+		{assumeRoleURL(url.Values{"DurationSeconds": {"hour"}}), userCred, http.StatusBadRequest},
+		// 5. Unknown API version.
+		{assumeRoleURL(url.Values{"Version": {"2006-03-01"}}), userCred, http.StatusBadRequest},
+		// 6. Malformed session policy.
+		{assumeRoleURL(url.Values{"Policy": {`{"Version": "2012-10-17"}`}}), userCred, http.StatusBadRequest},
+		// 7. Valid request.
+		{assumeRoleURL(url.Values{"DurationSeconds": {"900"}, "Policy": {sessionPolicy}}), userCred, http.StatusOK},
+	}
+	var response AssumeRoleResponse
+	for i, test := range testCases {
+		req, err := newTestSignedRequestV4("POST", test.urlStr, 0, nil, test.cred.AccessKey, test.cred.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct AssumeRole request - %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if test.statusCode != rec.Code {
+			t.Errorf("Test %d - Expected HTTP status code %d but received %d: %s",
+				i+1, test.statusCode, rec.Code, rec.Body.String())
+		}
+		if rec.Code == http.StatusOK {
+			if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Test %d - Failed to unmarshal AssumeRole response - %v", i+1, err)
+			}
+		}
+	}
+
+	creds := response.Result.Credentials
+	tempCred := credential{
+		AccessKey:    creds.AccessKeyID,
+		SecretKey:    creds.SecretAccessKey,
+		SessionToken: creds.SessionToken,
+	}
+	expiration, err := time.Parse(timeFormatAMZLong, creds.Expiration)
+	if err != nil {
+		t.Fatalf("Failed to parse expiration %q - %v", creds.Expiration, err)
+	}
+	if d := expiration.Sub(time.Now().UTC()); d <= 0 || d > minSTSDuration {
+		t.Errorf("Unexpected expiration %v", expiration)
+	}
+
+	// Requests signed by temporary credentials.
+	s3TestCases := []struct {
+		method     string
+		urlStr     string
+		cred       credential
+		statusCode int
+	}{
+		// 1. Allowed by the parent user and the session policy.
+		{"GET", getGetObjectURL("", bucketName, "object"), tempCred, http.StatusOK},
+		// 2. Allowed by the parent user but not by the session policy.
+		{"PUT", getPutObjectURL("", bucketName, "object"), tempCred, http.StatusForbidden},
+		// 3. Missing session token.
+		{"GET", getGetObjectURL("", bucketName, "object"), credential{AccessKey: tempCred.AccessKey, SecretKey: tempCred.SecretKey}, http.StatusBadRequest},
+		// 4. Session token sent with permanent credentials.
+		{"GET", getGetObjectURL("", bucketName, "object"), credential{AccessKey: userCred.AccessKey, SecretKey: userCred.SecretKey, SessionToken: tempCred.SessionToken}, http.StatusBadRequest},
+		// 5. Temporary credentials can not assume a role.
+		{"POST", assumeRoleURL(url.Values{}), tempCred, http.StatusForbidden},
+	}
+	for i, test := range s3TestCases {
+		req, err := newTestSignedRequestV4WithToken(test.method, test.urlStr, test.cred)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct S3 request - %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if test.statusCode != rec.Code {
+			t.Errorf("Test %d - Expected HTTP status code %d but received %d: %s",
+				i+1, test.statusCode, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Expired temporary credentials are rejected.
+	tempUser, _ := globalIAMSys.GetTempUser(tempCred.AccessKey)
+	tempUser.Expiration = time.Now().UTC().Add(-time.Minute)
+	if tempUser.Credential.SessionToken, err = newSessionToken(tempCred.AccessKey, userCred.AccessKey, tempUser.Expiration); err != nil {
+		t.Fatalf("Failed to sign session token - %v", err)
+	}
+	globalIAMSys.SetTempUsers(map[string]iamTempUser{tempCred.AccessKey: tempUser})
+	req, err := newTestSignedRequestV4WithToken("GET", getGetObjectURL("", bucketName, "object"), tempUser.Credential)
+	if err != nil {
+		t.Fatalf("Failed to construct S3 request - %v", err)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest || !bytes.Contains(rec.Body.Bytes(), []byte("ExpiredToken")) {
+		t.Errorf("Expected expired token to be rejected, received %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAssumeRoleWithLDAPIdentityHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for STS handler tests.")
+	}
+	defer adminTestBed.TearDown()
+	initGlobalS3Peers(nil)
+
+	server, ldapCfg := newTestLDAPServer()
+	defer server.Close()
+	defer func() { globalLDAPConfig = nil }()
+
+	mux := router.NewRouter()
+	registerSTSRouter(mux)
+	registerAPIRouter(mux)
+
+	bucketName := getRandomBucketName()
+	if err = adminTestBed.objLayer.MakeBucket(context.Background(), bucketName); err != nil {
+		t.Fatalf("Failed to make bucket - %v", err)
+	}
+	if _, err = adminTestBed.objLayer.PutObject(context.Background(), bucketName, "object", 4, bytes.NewReader([]byte("data")), nil, ""); err != nil {
+		t.Fatalf("Failed to put object - %v", err)
+	}
+
+	// Members of the readers group may read objects in the bucket.
+	readPolicy := mustParseIAMPolicy(t, `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::`+bucketName+`/*"]}]}`)
+	if err = setIAMPolicy("readers", readPolicy, adminTestBed.objLayer); err != nil {
+		t.Fatalf("Failed to add policy - %v", err)
+	}
+
+	assumeRoleURL := func(params url.Values) string {
+		params.Set("Action", "AssumeRoleWithLDAPIdentity")
+		return "/?" + params.Encode()
+	}
+	assumeRole := func(params url.Values) *httptest.ResponseRecorder {
+		req, err := newTestRequest("POST", assumeRoleURL(params), 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct AssumeRoleWithLDAPIdentity request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// LDAP authentication is not configured.
+	if rec := assumeRole(url.Values{"LDAPUsername": {"alice"}, "LDAPPassword": {"alice123"}}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected HTTP status code %d but received %d: %s", http.StatusBadRequest, rec.Code, rec.Body.String())
+	}
+	globalLDAPConfig = ldapCfg
+
+	testCases := []struct {
+		params     url.Values
+		statusCode int
+	}{
+		// 1. Wrong password.
+		{url.Values{"LDAPUsername": {"alice"}, "LDAPPassword": {"bob123"}}, http.StatusForbidden},
+		// 2. Missing password.
+		{url.Values{"LDAPUsername": {"alice"}}, http.StatusForbidden},
+		// 3. Duration too short.
+		{url.Values{"LDAPUsername": {"alice"}, "LDAPPassword": {"alice123"}, "DurationSeconds": {"60"}}, http.StatusBadRequest},
+		// 4. Valid request.
+		{url.Values{"LDAPUsername": {"alice"}, "LDAPPassword": {"alice123"}}, http.StatusOK},
+	}
+	var response AssumeRoleWithLDAPIdentityResponse
+	for i, test := range testCases {
+		rec := assumeRole(test.params)
+		if test.statusCode != rec.Code {
+			t.Errorf("Test %d - Expected HTTP status code %d but received %d: %s",
+				i+1, test.statusCode, rec.Code, rec.Body.String())
+		}
+		if rec.Code == http.StatusOK {
+			if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Test %d - Failed to unmarshal AssumeRoleWithLDAPIdentity response - %v", i+1, err)
+			}
+		}
+	}
+	aliceCred := credential{
+		AccessKey:    response.Result.Credentials.AccessKeyID,
+		SecretKey:    response.Result.Credentials.SecretAccessKey,
+		SessionToken: response.Result.Credentials.SessionToken,
+	}
+
+	rec := assumeRole(url.Values{"LDAPUsername": {"bob"}, "LDAPPassword": {"bob123"}})
+	if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal AssumeRoleWithLDAPIdentity response - %v", err)
+	}
+	bobCred := credential{
+		AccessKey:    response.Result.Credentials.AccessKeyID,
+		SecretKey:    response.Result.Credentials.SecretAccessKey,
+		SessionToken: response.Result.Credentials.SessionToken,
+	}
+
+	// Requests signed by temporary credentials.
+	s3TestCases := []struct {
+		method     string
+		urlStr     string
+		cred       credential
+		statusCode int
+	}{
+		// 1. Allowed by the policy of the readers group.
+		{"GET", getGetObjectURL("", bucketName, "object"), aliceCred, http.StatusOK},
+		// 2. The writers group has no policy.
+		{"PUT", getPutObjectURL("", bucketName, "object"), aliceCred, http.StatusForbidden},
+		// 3. Member of no group.
+		{"GET", getGetObjectURL("", bucketName, "object"), bobCred, http.StatusForbidden},
+	}
+	for i, test := range s3TestCases {
+		req, err := newTestSignedRequestV4WithToken(test.method, test.urlStr, test.cred)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct S3 request - %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if test.statusCode != rec.Code {
+			t.Errorf("Test %d - Expected HTTP status code %d but received %d: %s",
+				i+1, test.statusCode, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestAssumeRoleWithWebIdentityHandler(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for STS handler tests.")
+	}
+	defer adminTestBed.TearDown()
+	initGlobalS3Peers(nil)
+
+	provider, openIDCfg := newTestOpenIDProvider(t)
+	defer provider.Close()
+	defer func() { globalOpenIDConfig = nil }()
+
+	mux := router.NewRouter()
+	registerSTSRouter(mux)
+	registerAPIRouter(mux)
+
+	bucketName := getRandomBucketName()
+	if err = adminTestBed.objLayer.MakeBucket(context.Background(), bucketName); err != nil {
+		t.Fatalf("Failed to make bucket - %v", err)
+	}
+	if _, err = adminTestBed.objLayer.PutObject(context.Background(), bucketName, "object", 4, bytes.NewReader([]byte("data")), nil, ""); err != nil {
+		t.Fatalf("Failed to put object - %v", err)
+	}
+	readPolicy := mustParseIAMPolicy(t, `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::`+bucketName+`/*"]}]}`)
+	if err = setIAMPolicy("readers", readPolicy, adminTestBed.objLayer); err != nil {
+		t.Fatalf("Failed to add policy - %v", err)
+	}
+
+	assumeRole := func(params url.Values) *httptest.ResponseRecorder {
+		params.Set("Action", "AssumeRoleWithWebIdentity")
+		req, err := newTestRequest("POST", "/?"+params.Encode(), 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to construct AssumeRoleWithWebIdentity request - %v", err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	idToken := provider.signToken(t, jwtgo.MapClaims{"sub": "alice", "policy": "readers"})
+
+	// OpenID Connect authentication is not configured.
+	if rec := assumeRole(url.Values{"WebIdentityToken": {idToken}}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected HTTP status code %d but received %d: %s", http.StatusBadRequest, rec.Code, rec.Body.String())
+	}
+	globalOpenIDConfig = openIDCfg
+
+	testCases := []struct {
+		params     url.Values
+		statusCode int
+		errCode    string
+	}{
+		// 1. Missing token.
+		{url.Values{}, http.StatusBadRequest, "InvalidIdentityToken"},
+		// 2. Token of another client.
+		{url.Values{"WebIdentityToken": {provider.signToken(t, jwtgo.MapClaims{"sub": "alice", "aud": "other"})}}, http.StatusBadRequest, "InvalidIdentityToken"},
+		// 3. Expired token.
+		{url.Values{"WebIdentityToken": {provider.signToken(t, jwtgo.MapClaims{"sub": "alice", "exp": time.Now().Add(-time.Minute).Unix()})}}, http.StatusBadRequest, "ExpiredToken"},
+		// 4. Duration too long.
+		{url.Values{"WebIdentityToken": {idToken}, "DurationSeconds": {"86400"}}, http.StatusBadRequest, "InvalidParameterValue"},
+		// 5. Valid request.
+		{url.Values{"WebIdentityToken": {idToken}}, http.StatusOK, ""},
+	}
+	var response AssumeRoleWithWebIdentityResponse
+	for i, test := range testCases {
+		rec := assumeRole(test.params)
+		if test.statusCode != rec.Code || !bytes.Contains(rec.Body.Bytes(), []byte(test.errCode)) {
+			t.Errorf("Test %d - Expected HTTP status code %d and %q but received %d: %s",
+				i+1, test.statusCode, test.errCode, rec.Code, rec.Body.String())
+		}
+		if rec.Code == http.StatusOK {
+			if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Test %d - Failed to unmarshal AssumeRoleWithWebIdentity response - %v", i+1, err)
+			}
+		}
+	}
+	tempCred := credential{
+		AccessKey:    response.Result.Credentials.AccessKeyID,
+		SecretKey:    response.Result.Credentials.SecretAccessKey,
+		SessionToken: response.Result.Credentials.SessionToken,
+	}
+
+	// Requests signed by temporary credentials.
+	s3TestCases := []struct {
+		method     string
+		urlStr     string
+		statusCode int
+	}{
+		// 1. Allowed by the policy named in the token.
+		{"GET", getGetObjectURL("", bucketName, "object"), http.StatusOK},
+		// 2. Not allowed by the policy.
+		{"PUT", getPutObjectURL("", bucketName, "object"), http.StatusForbidden},
+	}
+	for i, test := range s3TestCases {
+		req, err := newTestSignedRequestV4WithToken(test.method, test.urlStr, tempCred)
+		if err != nil {
+			t.Fatalf("Test %d - Failed to construct S3 request - %v", i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if test.statusCode != rec.Code {
+			t.Errorf("Test %d - Expected HTTP status code %d but received %d: %s",
+				i+1, test.statusCode, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,38 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import router "github.com/gorilla/mux"
+
+// stsAPIHandlers provides HTTP handlers for the STS API.
+type stsAPIHandlers struct {
+}
+
+// registerSTSRouter - Add handler functions for each STS API route.
+func registerSTSRouter(mux *router.Router) {
+
+	stsAPI := stsAPIHandlers{}
+	// STS router
+	stsRouter := mux.NewRoute().PathPrefix("/").Subrouter()
+
+	// Assume role
+	stsRouter.Methods("POST").Path("/").Queries("Action", "AssumeRole").HandlerFunc(stsAPI.AssumeRoleHandler)
+	// Assume role with LDAP identity
+	stsRouter.Methods("POST").Path("/").Queries("Action", "AssumeRoleWithLDAPIdentity").HandlerFunc(stsAPI.AssumeRoleWithLDAPIdentityHandler)
+	// Assume role with web identity
+	stsRouter.Methods("POST").Path("/").Queries("Action", "AssumeRoleWithWebIdentity").HandlerFunc(stsAPI.AssumeRoleWithWebIdentityHandler)
+}
@@ -0,0 +1,250 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// Temporary credentials file name inside the IAM configuration prefix.
+	iamTempUsersFile = "sts.json"
+
+	// Validity bounds of temporary credentials, same as AWS STS.
+	defaultSTSDuration = 1 * time.Hour
+	minSTSDuration     = 15 * time.Minute
+	maxSTSDuration     = 12 * time.Hour
+
+	// Request header and query parameter carrying the session token.
+	amzSecurityToken = "X-Amz-Security-Token"
+)
+
+var errSTSAssumeRoleNotAllowed = errors.New("Temporary credentials cannot be used to assume a role")
+
+// iamTempUser - temporary credentials issued to a parent user, which
+// are allowed at most what the parent user is allowed further limited
+// by the optional session policy. Credentials issued to an LDAP or
+// OpenID Connect user have no parent user, they are allowed what the
+// named policies of the user allow.
+type iamTempUser struct {
+	Credential credential    `json:"credential"`
+	ParentUser string        `json:"parentUser,omitempty"`
+	LDAPUser   string        `json:"ldapUser,omitempty"`
+	OpenIDUser string        `json:"openIDUser,omitempty"`
+	Policies   []string      `json:"policies,omitempty"`
+	Policy     *bucketPolicy `json:"policy,omitempty"`
+	Expiration time.Time     `json:"expiration"`
+}
+
+// isExpired - checks if the temporary credentials expired.
+func (u iamTempUser) isExpired() bool {
+	return !time.Now().UTC().Before(u.Expiration)
+}
+
+// iamTempUsersConfig - format of the temporary credentials file.
+type iamTempUsersConfig struct {
+	Version string                 `json:"version"`
+	Users   map[string]iamTempUser `json:"users"`
+}
+
+// getSessionToken - returns the session token a request is signed
+// with, sent either as a header or as a query parameter for presigned
+// requests.
+func getSessionToken(r *http.Request) string {
+	if token := r.Header.Get(amzSecurityToken); token != "" {
+		return token
+	}
+	for key, values := range r.URL.Query() {
+		if http.CanonicalHeaderKey(key) == amzSecurityToken && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// newSessionToken - signs a session token for temporary credentials
+// with the server secret key.
+func newSessionToken(accessKey, parentUser string, expiration time.Time) (string, error) {
+	token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, jwtgo.MapClaims{
+		"exp":       expiration.Unix(),
+		"iat":       time.Now().UTC().Unix(),
+		"sub":       parentUser,
+		"accessKey": accessKey,
+	})
+	return token.SignedString([]byte(serverConfig.GetCredential().SecretKey))
+}
+
+// validateSessionToken - checks the session token sent with a request
+// signed with cred. Permanent credentials must not send a token,
+// temporary credentials must send the token issued with them which
+// must not have expired.
+func validateSessionToken(cred credential, token string) APIErrorCode {
+	if cred.SessionToken == "" {
+		if token != "" {
+			return ErrInvalidToken
+		}
+		return ErrNone
+	}
+	if token != cred.SessionToken {
+		return ErrInvalidToken
+	}
+
+	jwtToken, err := jwtgo.Parse(token, keyFuncCallback)
+	if err != nil {
+		if vErr, ok := err.(*jwtgo.ValidationError); ok && vErr.Errors&jwtgo.ValidationErrorExpired != 0 {
+			return ErrExpiredToken
+		}
+		return ErrInvalidToken
+	}
+	claims, ok := jwtToken.Claims.(jwtgo.MapClaims)
+	if !ok || !jwtToken.Valid || claims["accessKey"] != cred.AccessKey {
+		return ErrInvalidToken
+	}
+	return ErrNone
+}
+
+// SetTempUsers - replaces all the temporary credentials.
+func (sys *iamSys) SetTempUsers(tempUsers map[string]iamTempUser) {
+	sys.rwMutex.Lock()
+	defer sys.rwMutex.Unlock()
+
+	sys.tempUsers = tempUsers
+}
+
+// GetTempUser - returns the temporary credentials with the given
+// access key, expired credentials are returned as well so that
+// requests signed with them are told so.
+func (sys *iamSys) GetTempUser(accessKey string) (iamTempUser, bool) {
+	sys.rwMutex.RLock()
+	defer sys.rwMutex.RUnlock()
+
+	tempUser, ok := sys.tempUsers[accessKey]
+	return tempUser, ok
+}
+
+// readIAMTempUsers - reads all the temporary credentials, the caller
+// is expected to hold a lock on the temporary credentials file.
+func readIAMTempUsers(objAPI ObjectLayer) (map[string]iamTempUser, error) {
+	tempUsersCfg := iamTempUsersConfig{}
+	if err := readIAMConfig(iamTempUsersFile, &tempUsersCfg, objAPI); err != nil {
+		return nil, err
+	}
+	if tempUsersCfg.Users == nil {
+		tempUsersCfg.Users = make(map[string]iamTempUser)
+	}
+	return tempUsersCfg.Users, nil
+}
+
+// updateIAMTempUsers - applies fn to the persisted temporary
+// credentials while holding a write lock, expired credentials are
+// dropped. All peers (including self) are notified on success.
+func updateIAMTempUsers(objAPI ObjectLayer, fn func(tempUsers map[string]iamTempUser) error) error {
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, path.Join(iamConfigPrefix, iamTempUsersFile))
+	objLock.Lock()
+	tempUsersCfg := iamTempUsersConfig{}
+	err := readIAMConfig(iamTempUsersFile, &tempUsersCfg, objAPI)
+	if err == nil {
+		if tempUsersCfg.Users == nil {
+			tempUsersCfg.Users = make(map[string]iamTempUser)
+		}
+		for accessKey, tempUser := range tempUsersCfg.Users {
+			if tempUser.isExpired() {
+				delete(tempUsersCfg.Users, accessKey)
+			}
+		}
+		if err = fn(tempUsersCfg.Users); err == nil {
+			tempUsersCfg.Version = iamConfigVersion
+			err = writeIAMConfig(iamTempUsersFile, &tempUsersCfg, objAPI)
+		}
+	}
+	objLock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	S3PeersLoadConfig(iamConfigName, "")
+	return nil
+}
+
+// assumeRole - issues temporary credentials for the parent user valid
+// for the given duration, limited by the optional session policy.
+func assumeRole(parentUser string, duration time.Duration, policy *bucketPolicy, objAPI ObjectLayer) (iamTempUser, error) {
+	// Temporary credentials cannot issue further credentials.
+	if _, ok := globalIAMSys.GetTempUser(parentUser); ok {
+		return iamTempUser{}, errSTSAssumeRoleNotAllowed
+	}
+
+	return addTempUser(iamTempUser{
+		ParentUser: parentUser,
+		Policy:     policy,
+	}, duration, objAPI)
+}
+
+// assumeRoleWithLDAP - issues temporary credentials for an
+// authenticated LDAP user granted the named policies, valid for the
+// given duration and limited by the optional session policy.
+func assumeRoleWithLDAP(ldapUser string, policies []string, duration time.Duration, policy *bucketPolicy, objAPI ObjectLayer) (iamTempUser, error) {
+	return addTempUser(iamTempUser{
+		LDAPUser: ldapUser,
+		Policies: policies,
+		Policy:   policy,
+	}, duration, objAPI)
+}
+
+// assumeRoleWithOpenID - issues temporary credentials for an OpenID
+// Connect user granted the named policies, valid for the given
+// duration and limited by the optional session policy.
+func assumeRoleWithOpenID(openIDUser string, policies []string, duration time.Duration, policy *bucketPolicy, objAPI ObjectLayer) (iamTempUser, error) {
+	return addTempUser(iamTempUser{
+		OpenIDUser: openIDUser,
+		Policies:   policies,
+		Policy:     policy,
+	}, duration, objAPI)
+}
+
+// addTempUser - generates the credentials of a temporary user and
+// persists it.
+func addTempUser(tempUser iamTempUser, duration time.Duration, objAPI ObjectLayer) (iamTempUser, error) {
+	tempUser.Credential = newCredential()
+	tempUser.Expiration = time.Now().UTC().Add(duration)
+
+	subject := tempUser.ParentUser
+	if tempUser.LDAPUser != "" {
+		subject = tempUser.LDAPUser
+	} else if tempUser.OpenIDUser != "" {
+		subject = tempUser.OpenIDUser
+	}
+	token, err := newSessionToken(tempUser.Credential.AccessKey, subject, tempUser.Expiration)
+	if err != nil {
+		return iamTempUser{}, err
+	}
+	tempUser.Credential.SessionToken = token
+
+	err = updateIAMTempUsers(objAPI, func(tempUsers map[string]iamTempUser) error {
+		tempUsers[tempUser.Credential.AccessKey] = tempUser
+		return nil
+	})
+	if err != nil {
+		return iamTempUser{}, err
+	}
+	return tempUser, nil
+}
@@ -0,0 +1,196 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestGetSessionToken(t *testing.T) {
+	testCases := []struct {
+		header http.Header
+		query  string
+		token  string
+	}{
+		{http.Header{}, "", ""},
+		{http.Header{amzSecurityToken: {"header-token"}}, "", "header-token"},
+		{http.Header{}, "X-Amz-Security-Token=query-token", "query-token"},
+		{http.Header{}, "x-amz-security-token=query-token", "query-token"},
+		{http.Header{amzSecurityToken: {"header-token"}}, "X-Amz-Security-Token=query-token", "header-token"},
+	}
+	for i, testCase := range testCases {
+		r := &http.Request{Header: testCase.header, URL: &url.URL{RawQuery: testCase.query}}
+		if token := getSessionToken(r); token != testCase.token {
+			t.Errorf("Test %d: Expected token %q, got %q", i+1, testCase.token, token)
+		}
+	}
+}
+
+func TestValidateSessionToken(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	validToken, err := newSessionToken("tempuser", "parent", time.Now().UTC().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiredToken, err := newSessionToken("tempuser", "parent", time.Now().UTC().Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherToken, err := newSessionToken("otheruser", "parent", time.Now().UTC().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	permanent := credential{AccessKey: "user", SecretKey: "user1234"}
+	temporary := func(token string) credential {
+		return credential{AccessKey: "tempuser", SecretKey: "tempuser123", SessionToken: token}
+	}
+
+	testCases := []struct {
+		cred     credential
+		token    string
+		expected APIErrorCode
+	}{
+		// Permanent credentials without a token.
+		{permanent, "", ErrNone},
+		// Permanent credentials must not send a token.
+		{permanent, validToken, ErrInvalidToken},
+		// Temporary credentials with their token.
+		{temporary(validToken), validToken, ErrNone},
+		// Temporary credentials without a token.
+		{temporary(validToken), "", ErrInvalidToken},
+		// Temporary credentials with another token.
+		{temporary(validToken), otherToken, ErrInvalidToken},
+		// Token issued for another access key.
+		{temporary(otherToken), otherToken, ErrInvalidToken},
+		// Malformed token.
+		{temporary("malformed"), "malformed", ErrInvalidToken},
+		// Expired token.
+		{temporary(expiredToken), expiredToken, ErrExpiredToken},
+	}
+	for i, testCase := range testCases {
+		if s3Error := validateSessionToken(testCase.cred, testCase.token); s3Error != testCase.expected {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.expected, s3Error)
+		}
+	}
+}
+
+func TestGetCredentialForTempUser(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+	defer resetGlobalIAMSys()
+
+	serverCred := serverConfig.GetCredential()
+	globalIAMSys.Set(map[string]iamUser{
+		"enabled-user":  {Credential: credential{AccessKey: "enabled-user", SecretKey: "secret123"}, Status: iamUserEnabled},
+		"disabled-user": {Credential: credential{AccessKey: "disabled-user", SecretKey: "secret123"}, Status: iamUserDisabled},
+	}, nil)
+	tempCred := func(accessKey string) credential {
+		return credential{AccessKey: accessKey, SecretKey: "tempsecret123", SessionToken: "token"}
+	}
+	expiration := time.Now().UTC().Add(time.Hour)
+	globalIAMSys.SetTempUsers(map[string]iamTempUser{
+		"root-temp":     {Credential: tempCred("root-temp"), ParentUser: serverCred.AccessKey, Expiration: expiration},
+		"enabled-temp":  {Credential: tempCred("enabled-temp"), ParentUser: "enabled-user", Expiration: expiration},
+		"disabled-temp": {Credential: tempCred("disabled-temp"), ParentUser: "disabled-user", Expiration: expiration},
+		"orphan-temp":   {Credential: tempCred("orphan-temp"), ParentUser: "removed-user", Expiration: expiration},
+	})
+
+	testCases := []struct {
+		accessKey string
+		found     bool
+	}{
+		{"root-temp", true},
+		{"enabled-temp", true},
+		{"disabled-temp", false},
+		{"orphan-temp", false},
+	}
+	for i, testCase := range testCases {
+		cred, ok := getCredentialForAccessKey(testCase.accessKey)
+		if ok != testCase.found {
+			t.Errorf("Test %d: Expected found %v, got %v", i+1, testCase.found, ok)
+		}
+		if ok && cred != tempCred(testCase.accessKey) {
+			t.Errorf("Test %d: Unexpected credential %v", i+1, cred)
+		}
+	}
+}
+
+// Wrapper for calling temporary credentials persistence tests for both XL and FS.
+func TestAssumeRolePersistence(t *testing.T) {
+	initNSLock(false)
+	ExecObjectLayerTest(t, testAssumeRolePersistence)
+}
+
+// Tests temporary credentials are persisted and expired ones dropped.
+func testAssumeRolePersistence(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+	initGlobalS3Peers(nil)
+	defer resetGlobalIAMSys()
+
+	serverCred := serverConfig.GetCredential()
+	tempUser, err := assumeRole(serverCred.AccessKey, time.Hour, nil, obj)
+	if err != nil {
+		t.Fatalf("%s: Unable to assume role - %v", instanceType, err)
+	}
+
+	// Temporary credentials can not assume a role.
+	if _, err = assumeRole(tempUser.Credential.AccessKey, time.Hour, nil, obj); err != errSTSAssumeRoleNotAllowed {
+		t.Errorf("%s: Expected %v, got %v", instanceType, errSTSAssumeRoleNotAllowed, err)
+	}
+
+	// Expired credentials are dropped on the next update.
+	expiredUser := tempUser
+	expiredUser.Credential.AccessKey = "expired"
+	expiredUser.Expiration = time.Now().UTC().Add(-time.Minute)
+	err = updateIAMTempUsers(obj, func(tempUsers map[string]iamTempUser) error {
+		tempUsers[expiredUser.Credential.AccessKey] = expiredUser
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%s: Unable to update temporary credentials - %v", instanceType, err)
+	}
+	if _, err = assumeRole(serverCred.AccessKey, time.Hour, nil, obj); err != nil {
+		t.Fatalf("%s: Unable to assume role - %v", instanceType, err)
+	}
+
+	// Reload from the backend.
+	resetGlobalIAMSys()
+	if err = loadIAM(obj); err != nil {
+		t.Fatalf("%s: Unable to load IAM - %v", instanceType, err)
+	}
+	loadedUser, ok := globalIAMSys.GetTempUser(tempUser.Credential.AccessKey)
+	if !ok || loadedUser.Credential != tempUser.Credential || loadedUser.ParentUser != serverCred.AccessKey {
+		t.Errorf("%s: Unexpected temporary credentials %v", instanceType, loadedUser)
+	}
+	if _, ok = globalIAMSys.GetTempUser(expiredUser.Credential.AccessKey); ok {
+		t.Errorf("%s: Expected expired credentials to be dropped", instanceType)
+	}
+}
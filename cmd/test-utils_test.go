@@ -19,6 +19,7 @@ package cmd
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/ecdsa"
 	"crypto/hmac"
 	crand "crypto/rand"
@@ -496,6 +497,11 @@ func resetGlobalIsXL() {
 	globalIsXL = false
 }
 
+// reset global IAM users and policies.
+func resetGlobalIAMSys() {
+	globalIAMSys = newIAMSys()
+}
+
 // Resets all the globals used modified in tests.
 // Resetting ensures that the changes made to globals by one test doesn't affect others.
 func resetTestGlobals() {
@@ -513,6 +519,8 @@ func resetTestGlobals() {
 	resetGlobalEndpoints()
 	// Reset global isXL flag.
 	resetGlobalIsXL()
+	// Reset global IAM users and policies.
+	resetGlobalIAMSys()
 }
 
 // Configure the server for the test run.
@@ -1610,6 +1618,76 @@ func getGetBucketNotificationURL(endPoint, bucketName string) string {
 	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
 }
 
+// return URL for get, put and delete of a bucket configuration, e.g.
+// `versioning` or `lifecycle`.
+func getBucketConfigURL(endPoint, bucketName, config string) string {
+	queryValue := url.Values{}
+	queryValue.Set(config, "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for get, put and delete object tagging.
+func getObjectTaggingURL(endPoint, bucketName, objectName, versionID string) string {
+	queryValue := url.Values{}
+	queryValue.Set("tagging", "")
+	if versionID != "" {
+		queryValue.Set("versionId", versionID)
+	}
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
+// return URL for get and put object ACL.
+func getObjectACLURL(endPoint, bucketName, objectName, versionID string) string {
+	queryValue := url.Values{}
+	queryValue.Set("acl", "")
+	if versionID != "" {
+		queryValue.Set("versionId", versionID)
+	}
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
+// return URL for get and put object retention.
+func getObjectRetentionURL(endPoint, bucketName, objectName, versionID string) string {
+	queryValue := url.Values{}
+	queryValue.Set("retention", "")
+	if versionID != "" {
+		queryValue.Set("versionId", versionID)
+	}
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
+// return URL for get and put object legal hold.
+func getObjectLegalHoldURL(endPoint, bucketName, objectName, versionID string) string {
+	queryValue := url.Values{}
+	queryValue.Set("legal-hold", "")
+	if versionID != "" {
+		queryValue.Set("versionId", versionID)
+	}
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
+// return URL for select object content.
+func getSelectObjectContentURL(endPoint, bucketName, objectName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("select", "")
+	queryValue.Set("select-type", "2")
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
+// return URL for list object versions.
+func getListObjectVersionsURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("versions", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for an object version.
+func getObjectVersionURL(endPoint, bucketName, objectName, versionID string) string {
+	queryValue := url.Values{}
+	queryValue.Set("versionId", versionID)
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
 // return URL for listen bucket notification.
 func getListenBucketNotificationURL(endPoint, bucketName string, prefixes, suffixes, events []string) string {
 	queryValue := url.Values{}
@@ -1744,7 +1822,7 @@ func initAPIHandlerTest(obj ObjectLayer, endpoints []string) (bucketName string,
 	bucketName = getRandomBucketName()
 
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, return err.
 		return "", nil, err
@@ -2082,12 +2160,18 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "HeadBucket":
 			// Register HeadBucket handler.
 			bucket.Methods("HEAD").HandlerFunc(api.HeadBucketHandler)
+		case "PutBucket":
+			// Register PutBucket handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketHandler)
 		case "DeleteMultipleObjects":
 			// Register DeleteMultipleObjects handler.
 			bucket.Methods("POST").HandlerFunc(api.DeleteMultipleObjectsHandler).Queries("delete", "")
 		case "NewMultipart":
 			// Register New Multipart upload handler.
 			bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.NewMultipartUploadHandler).Queries("uploads", "")
+		case "CopyObjectPart":
+			// Register CopyObjectPart handler.
+			bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(api.CopyObjectPartHandler).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
 		case "PutObjectPart":
 			// Register PutObjectPart handler.
 			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectPartHandler).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
@@ -2112,6 +2196,117 @@ func registerBucketLevelFunc(bucket *router.Router, api objectAPIHandlers, apiFu
 		case "ListenBucketNotification":
 			// Register ListenBucketNotification Handler.
 			bucket.Methods("GET").HandlerFunc(api.ListenBucketNotificationHandler).Queries("events", "{events:.*}")
+		case "GetBucketVersioning":
+			// Register GetBucketVersioning Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketVersioningHandler).Queries("versioning", "")
+		case "PutBucketVersioning":
+			// Register PutBucketVersioning Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketVersioningHandler).Queries("versioning", "")
+		case "ListObjectVersions":
+			// Register ListObjectVersions Handler.
+			bucket.Methods("GET").HandlerFunc(api.ListObjectVersionsHandler).Queries("versions", "")
+		case "GetBucketLifecycle":
+			// Register GetBucketLifecycle Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketLifecycleHandler).Queries("lifecycle", "")
+		case "PutBucketLifecycle":
+			// Register PutBucketLifecycle Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketLifecycleHandler).Queries("lifecycle", "")
+		case "DeleteBucketLifecycle":
+			// Register DeleteBucketLifecycle Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketLifecycleHandler).Queries("lifecycle", "")
+		case "GetBucketCors":
+			// Register GetBucketCors Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketCorsHandler).Queries("cors", "")
+		case "PutBucketCors":
+			// Register PutBucketCors Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketCorsHandler).Queries("cors", "")
+		case "DeleteBucketCors":
+			// Register DeleteBucketCors Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketCorsHandler).Queries("cors", "")
+		case "GetBucketWebsite":
+			// Register GetBucketWebsite Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketWebsiteHandler).Queries("website", "")
+		case "PutBucketWebsite":
+			// Register PutBucketWebsite Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketWebsiteHandler).Queries("website", "")
+		case "DeleteBucketWebsite":
+			// Register DeleteBucketWebsite Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketWebsiteHandler).Queries("website", "")
+		case "GetBucketLogging":
+			// Register GetBucketLogging Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketLoggingHandler).Queries("logging", "")
+		case "PutBucketLogging":
+			// Register PutBucketLogging Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketLoggingHandler).Queries("logging", "")
+		case "GetBucketReplication":
+			// Register GetBucketReplication Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketReplicationHandler).Queries("replication", "")
+		case "PutBucketReplication":
+			// Register PutBucketReplication Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketReplicationHandler).Queries("replication", "")
+		case "DeleteBucketReplication":
+			// Register DeleteBucketReplication Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketReplicationHandler).Queries("replication", "")
+		case "GetObjectTagging":
+			// Register GetObjectTagging Handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectTaggingHandler).Queries("tagging", "")
+		case "PutObjectTagging":
+			// Register PutObjectTagging Handler.
+			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectTaggingHandler).Queries("tagging", "")
+		case "DeleteObjectTagging":
+			// Register DeleteObjectTagging Handler.
+			bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.DeleteObjectTaggingHandler).Queries("tagging", "")
+		case "GetObjectRetention":
+			// Register GetObjectRetention Handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectRetentionHandler).Queries("retention", "")
+		case "PutObjectRetention":
+			// Register PutObjectRetention Handler.
+			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectRetentionHandler).Queries("retention", "")
+		case "GetObjectLegalHold":
+			// Register GetObjectLegalHold Handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectLegalHoldHandler).Queries("legal-hold", "")
+		case "PutObjectLegalHold":
+			// Register PutObjectLegalHold Handler.
+			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectLegalHoldHandler).Queries("legal-hold", "")
+		case "GetBucketObjectLockConfig":
+			// Register GetBucketObjectLockConfig Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketObjectLockConfigHandler).Queries("object-lock", "")
+		case "PutBucketObjectLockConfig":
+			// Register PutBucketObjectLockConfig Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketObjectLockConfigHandler).Queries("object-lock", "")
+		case "GetBucketTagging":
+			// Register GetBucketTagging Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketTaggingHandler).Queries("tagging", "")
+		case "PutBucketTagging":
+			// Register PutBucketTagging Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketTaggingHandler).Queries("tagging", "")
+		case "DeleteBucketTagging":
+			// Register DeleteBucketTagging Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketTaggingHandler).Queries("tagging", "")
+		case "SelectObjectContent":
+			// Register SelectObjectContent Handler.
+			bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.SelectObjectContentHandler).Queries("select", "", "select-type", "2")
+		case "GetObjectACL":
+			// Register GetObjectACL Handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectACLHandler).Queries("acl", "")
+		case "PutObjectACL":
+			// Register PutObjectACL Handler.
+			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectACLHandler).Queries("acl", "")
+		case "GetBucketACL":
+			// Register GetBucketACL Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketACLHandler).Queries("acl", "")
+		case "PutBucketACL":
+			// Register PutBucketACL Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketACLHandler).Queries("acl", "")
+		case "GetBucketEncryption":
+			// Register GetBucketEncryption Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketEncryptionHandler).Queries("encryption", "")
+		case "PutBucketEncryption":
+			// Register PutBucketEncryption Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketEncryptionHandler).Queries("encryption", "")
+		case "DeleteBucketEncryption":
+			// Register DeleteBucketEncryption Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketEncryptionHandler).Queries("encryption", "")
 		}
 	}
 }
@@ -0,0 +1,207 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/gob"
+	"io"
+	"net"
+	"net/http"
+	"net/rpc"
+	"sync"
+	"time"
+)
+
+// RPC connections carry many calls, they are traced per call by the
+// codec of the RPC server instead of the HTTP handlers.
+
+// Response of rpc.Server to CONNECT requests, expected by RPCClient.
+const rpcConnectedResponse = "HTTP/1.0 200 Connected to Go RPC\n\n"
+
+// rpcHandler - serves RPC connections like rpc.Server, with calls
+// traced while anyone watches them.
+type rpcHandler struct {
+	server *rpc.Server
+}
+
+// newRPCHandler - returns the HTTP handler of an RPC server.
+func newRPCHandler(server *rpc.Server) http.Handler {
+	return rpcHandler{server: server}
+}
+
+func (h rpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "CONNECT" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		io.WriteString(w, "405 must CONNECT\n")
+		return
+	}
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		errorIfCtx(r.Context(), err, "Unable to hijack the RPC connection of %s.", r.RemoteAddr)
+		return
+	}
+	io.WriteString(conn, rpcConnectedResponse)
+	h.server.ServeCodec(newTraceServerCodec(conn, r.URL.Path))
+}
+
+// rpcCountingReader - counts the bytes read from a connection.
+type rpcCountingReader struct {
+	io.Reader
+	n int64
+}
+
+func (cr *rpcCountingReader) Read(p []byte) (int, error) {
+	n, err := cr.Reader.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// rpcCountingWriter - counts the bytes written to a connection.
+type rpcCountingWriter struct {
+	io.Writer
+	n int64
+}
+
+func (cw *rpcCountingWriter) Write(p []byte) (int, error) {
+	n, err := cw.Writer.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// traceServerCodec - the gob codec of rpc.Server, publishing the calls
+// it serves while anyone watches them.
+type traceServerCodec struct {
+	rwc    io.ReadWriteCloser
+	dec    *gob.Decoder
+	enc    *gob.Encoder
+	encBuf *bufio.Writer
+	closed bool
+
+	// Bytes read and written, requests are read by a single goroutine
+	// and responses are written one at a time.
+	received *rpcCountingReader
+	sent     *rpcCountingWriter
+
+	endpoint   string
+	remoteAddr string
+
+	// Calls being served by sequence number, calls are only traced if
+	// anyone watches when they arrive.
+	mutex   *sync.Mutex
+	calls   map[uint64]*requestTrace
+	reading *requestTrace
+}
+
+func newTraceServerCodec(conn net.Conn, endpoint string) rpc.ServerCodec {
+	received := &rpcCountingReader{Reader: conn}
+	sent := &rpcCountingWriter{Writer: conn}
+	encBuf := bufio.NewWriter(sent)
+	return &traceServerCodec{
+		rwc:        conn,
+		dec:        gob.NewDecoder(received),
+		enc:        gob.NewEncoder(encBuf),
+		encBuf:     encBuf,
+		received:   received,
+		sent:       sent,
+		endpoint:   endpoint,
+		remoteAddr: conn.RemoteAddr().String(),
+		mutex:      &sync.Mutex{},
+		calls:      make(map[uint64]*requestTrace),
+	}
+}
+
+func (c *traceServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	c.reading = nil
+	start := c.received.n
+	if err := c.dec.Decode(r); err != nil {
+		return err
+	}
+	// Peers collecting traces are not traced themselves.
+	if !globalTrace.IsTracing() || r.ServiceMethod == "Admin.Trace" {
+		return nil
+	}
+	c.reading = &requestTrace{
+		Node:          globalMinioAddr,
+		Type:          requestTypeInternode,
+		Time:          time.Now().UTC(),
+		API:           r.ServiceMethod,
+		Method:        "RPC",
+		Path:          c.endpoint,
+		RemoteAddr:    c.remoteAddr,
+		BytesReceived: -start,
+	}
+	c.mutex.Lock()
+	c.calls[r.Seq] = c.reading
+	c.mutex.Unlock()
+	return nil
+}
+
+func (c *traceServerCodec) ReadRequestBody(body interface{}) error {
+	err := c.dec.Decode(body)
+	if c.reading != nil {
+		c.reading.BytesReceived += c.received.n
+		c.reading = nil
+	}
+	return err
+}
+
+func (c *traceServerCodec) WriteResponse(r *rpc.Response, body interface{}) (err error) {
+	start := c.sent.n
+	defer func() {
+		c.mutex.Lock()
+		info, ok := c.calls[r.Seq]
+		delete(c.calls, r.Seq)
+		c.mutex.Unlock()
+		if !ok {
+			return
+		}
+		info.Duration = time.Now().UTC().Sub(info.Time)
+		info.BytesSent = c.sent.n - start
+		info.Error = r.Error
+		if info.Error == "" && err != nil {
+			info.Error = err.Error()
+		}
+		globalTrace.Publish(*info)
+	}()
+
+	if err = c.enc.Encode(r); err != nil {
+		if c.encBuf.Flush() == nil {
+			errorIf(err, "Unable to encode the RPC response.")
+			c.Close()
+		}
+		return err
+	}
+	if err = c.enc.Encode(body); err != nil {
+		if c.encBuf.Flush() == nil {
+			errorIf(err, "Unable to encode the RPC response body.")
+			c.Close()
+		}
+		return err
+	}
+	return c.encBuf.Flush()
+}
+
+func (c *traceServerCodec) Close() error {
+	if c.closed {
+		// Only call c.rwc.Close once; otherwise the semantics are undefined.
+		return nil
+	}
+	c.closed = true
+	return c.rwc.Close()
+}
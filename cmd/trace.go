@@ -0,0 +1,323 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Requests are traced only while an admin watches them, the requests
+// served in the meantime are published to all subscribers whose
+// filter they match.
+
+// Replaces secrets in traced and audited headers and queries.
+const redactedValue = "*REDACTED*"
+
+// Maximum number of traced requests buffered per subscriber, requests
+// are dropped for subscribers which do not keep up.
+const traceSubscriberBufferSize = 10000
+
+// Peers are asked for the requests they served for at most this long
+// and at most this many requests at a time.
+const (
+	maxTracePollDuration = time.Second
+	maxTracePollEntries  = 1000
+)
+
+// Headers whose values are never traced nor audited.
+var redactedHeaders = []string{
+	amzSecurityToken,
+	amzSSECustomerKey,
+	amzSSECopySourcePrefix + amzSSECustomerKey,
+}
+
+// Query parameters whose values are never traced nor audited.
+var redactedQueries = []string{
+	"X-Amz-Signature",
+	"Signature",
+	amzSecurityToken,
+	"token",
+}
+
+// requestTrace - a traced request.
+type requestTrace struct {
+	Node          string        `json:"node"`
+	Type          string        `json:"type"`
+	Time          time.Time     `json:"time"`
+	RequestID     string        `json:"requestID,omitempty"`
+	API           string        `json:"api"`
+	Method        string        `json:"method"`
+	Path          string        `json:"path"`
+	Query         string        `json:"query,omitempty"`
+	Header        http.Header   `json:"header,omitempty"`
+	RemoteAddr    string        `json:"remoteAddr,omitempty"`
+	Bucket        string        `json:"bucket,omitempty"`
+	Object        string        `json:"object,omitempty"`
+	StatusCode    int           `json:"statusCode,omitempty"`
+	Error         string        `json:"error,omitempty"`
+	Duration      time.Duration `json:"duration"`
+	BytesReceived int64         `json:"bytesReceived"`
+	BytesSent     int64         `json:"bytesSent"`
+}
+
+// traceFilter - selects the traced requests a subscriber receives,
+// empty fields match all requests. Internode requests are only sent
+// when asked for.
+type traceFilter struct {
+	Bucket     string
+	API        string
+	ErrorsOnly bool
+	Internode  bool
+}
+
+// Checks if a traced request matches the filter.
+func (f traceFilter) matches(info requestTrace) bool {
+	if info.Type == requestTypeInternode && !f.Internode {
+		return false
+	}
+	if f.Bucket != "" && info.Bucket != f.Bucket {
+		return false
+	}
+	if f.API != "" && info.API != f.API {
+		return false
+	}
+	if f.ErrorsOnly && info.StatusCode < http.StatusBadRequest && info.Error == "" {
+		return false
+	}
+	return true
+}
+
+// traceHub - publishes traced requests to subscribers.
+type traceHub struct {
+	mutex       *sync.Mutex
+	subscribers map[chan requestTrace]traceFilter
+
+	// Number of subscribers, read without the mutex on every request.
+	count int32
+}
+
+// Variable represents the subscribers of traced requests.
+var globalTrace = newTraceHub()
+
+func newTraceHub() *traceHub {
+	return &traceHub{
+		mutex:       &sync.Mutex{},
+		subscribers: make(map[chan requestTrace]traceFilter),
+	}
+}
+
+// Subscribe returns a channel receiving the traced requests matching a
+// filter until Unsubscribe is called.
+func (t *traceHub) Subscribe(filter traceFilter) chan requestTrace {
+	ch := make(chan requestTrace, traceSubscriberBufferSize)
+	t.mutex.Lock()
+	t.subscribers[ch] = filter
+	atomic.StoreInt32(&t.count, int32(len(t.subscribers)))
+	t.mutex.Unlock()
+	return ch
+}
+
+// Unsubscribe stops sending traced requests to a channel.
+func (t *traceHub) Unsubscribe(ch chan requestTrace) {
+	t.mutex.Lock()
+	delete(t.subscribers, ch)
+	atomic.StoreInt32(&t.count, int32(len(t.subscribers)))
+	t.mutex.Unlock()
+}
+
+// IsTracing checks if anyone watches the traced requests.
+func (t *traceHub) IsTracing() bool {
+	return atomic.LoadInt32(&t.count) > 0
+}
+
+// Publish sends a traced request to the matching subscribers.
+func (t *traceHub) Publish(info requestTrace) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for ch, filter := range t.subscribers {
+		if !filter.matches(info) {
+			continue
+		}
+		select {
+		case ch <- info:
+		default:
+		}
+	}
+}
+
+// collectTrace - returns the traced requests matching a filter served
+// within a duration, at most maxEntries of them.
+func collectTrace(filter traceFilter, duration time.Duration, maxEntries int) []requestTrace {
+	ch := globalTrace.Subscribe(filter)
+	defer globalTrace.Unsubscribe(ch)
+
+	var entries []requestTrace
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	for len(entries) < maxEntries {
+		select {
+		case info := <-ch:
+			entries = append(entries, info)
+		case <-timer.C:
+			return entries
+		}
+	}
+	return entries
+}
+
+// Returns a copy of the headers with secrets redacted, signatures of
+// the Authorization header are replaced.
+func redactHeader(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for k, v := range header {
+		redacted[k] = append([]string(nil), v...)
+	}
+	for _, k := range redactedHeaders {
+		if _, ok := redacted[k]; ok {
+			redacted.Set(k, redactedValue)
+		}
+	}
+	if auth := redacted.Get("Authorization"); auth != "" {
+		switch {
+		case strings.HasPrefix(auth, signV4Algorithm):
+			if i := strings.Index(auth, "Signature="); i >= 0 {
+				auth = auth[:i+len("Signature=")] + redactedValue
+			}
+		case strings.HasPrefix(auth, signV2Algorithm):
+			if i := strings.LastIndex(auth, ":"); i >= 0 {
+				auth = auth[:i+1] + redactedValue
+			}
+		default:
+			if i := strings.Index(auth, " "); i >= 0 {
+				auth = auth[:i+1] + redactedValue
+			}
+		}
+		redacted.Set("Authorization", auth)
+	}
+	return redacted
+}
+
+// Returns the raw query with secrets redacted.
+func redactQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	redacted := make(url.Values, len(query))
+	for k, v := range query {
+		redacted[k] = v
+	}
+	for _, k := range redactedQueries {
+		if _, ok := redacted[k]; ok {
+			redacted.Set(k, redactedValue)
+		}
+	}
+	return redacted.Encode()
+}
+
+// traceResponseWriter - records the status and the size of a response.
+type traceResponseWriter struct {
+	http.ResponseWriter
+
+	status    int
+	bytesSent int64
+}
+
+func (tw *traceResponseWriter) WriteHeader(status int) {
+	if tw.status == 0 {
+		tw.status = status
+	}
+	tw.ResponseWriter.WriteHeader(status)
+}
+
+func (tw *traceResponseWriter) Write(p []byte) (int, error) {
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+	n, err := tw.ResponseWriter.Write(p)
+	tw.bytesSent += int64(n)
+	return n, err
+}
+
+// Flush - handlers flush responses while writing them.
+func (tw *traceResponseWriter) Flush() {
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// traceHandler - traces S3, admin and browser requests while anyone
+// watches them. RPC connections are traced per call by the RPC
+// servers.
+type traceHandler struct {
+	handler http.Handler
+}
+
+func setTraceHandler(h http.Handler) http.Handler {
+	return traceHandler{handler: h}
+}
+
+func (h traceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !globalTrace.IsTracing() || r.Method == "CONNECT" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	info := requestTrace{
+		Node:       globalMinioAddr,
+		Type:       getRequestType(r),
+		Time:       time.Now().UTC(),
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Query:      redactQuery(r.URL.Query()),
+		Header:     redactHeader(r.Header),
+		RemoteAddr: r.RemoteAddr,
+	}
+	switch info.Type {
+	case requestTypeAdmin:
+		info.API = r.Header.Get(minioAdminOpHeader)
+	case requestTypeBrowser:
+		info.API = strings.TrimPrefix(r.URL.Path, reservedBucket)
+	default:
+		info.Bucket, info.Object = urlPath2BucketObjectName(r.URL)
+		info.API = getAccessLogOperation(r, info.Object)
+	}
+
+	tw := &traceResponseWriter{ResponseWriter: w}
+	var mr *metricsReadCloser
+	if r.Body != nil {
+		mr = &metricsReadCloser{ReadCloser: r.Body}
+		r.Body = mr
+	}
+	h.handler.ServeHTTP(tw, r)
+
+	info.Duration = time.Now().UTC().Sub(info.Time)
+	info.RequestID = w.Header().Get(responseRequestIDKey)
+	info.StatusCode = tw.status
+	if info.StatusCode == 0 {
+		info.StatusCode = http.StatusOK
+	}
+	info.BytesSent = tw.bytesSent
+	if mr != nil {
+		info.BytesReceived = mr.bytesReceived
+	}
+	globalTrace.Publish(info)
+}
@@ -0,0 +1,232 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/rpc"
+	"strings"
+	"testing"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+// Tests that secrets are redacted from traced headers.
+func TestRedactHeader(t *testing.T) {
+	testCases := []struct {
+		header         http.Header
+		key            string
+		expectedHeader string
+	}{
+		// Test case - 1.
+		// Signature v4.
+		{http.Header{"Authorization": []string{signV4Algorithm + " Credential=minio/20170101/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=abcdef"}},
+			"Authorization", signV4Algorithm + " Credential=minio/20170101/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=" + redactedValue},
+		// Test case - 2.
+		// Signature v2.
+		{http.Header{"Authorization": []string{signV2Algorithm + " minio:abcdef"}}, "Authorization", signV2Algorithm + " minio:" + redactedValue},
+		// Test case - 3.
+		// JWT.
+		{http.Header{"Authorization": []string{jwtAlgorithm + " abcdef"}}, "Authorization", jwtAlgorithm + " " + redactedValue},
+		// Test case - 4.
+		// Session token of temporary credentials.
+		{http.Header{amzSecurityToken: []string{"abcdef"}}, amzSecurityToken, redactedValue},
+		// Test case - 5.
+		// SSE-C key.
+		{http.Header{amzSSECustomerKey: []string{"abcdef"}}, amzSSECustomerKey, redactedValue},
+		// Test case - 6.
+		// Other headers are kept.
+		{http.Header{"Content-Type": []string{"text/plain"}}, "Content-Type", "text/plain"},
+	}
+	for i, testCase := range testCases {
+		redacted := redactHeader(testCase.header)
+		if got := redacted.Get(testCase.key); got != testCase.expectedHeader {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.expectedHeader, got)
+		}
+	}
+
+	query := redactQuery(map[string][]string{"X-Amz-Signature": {"abcdef"}, "prefix": {"photos"}})
+	if strings.Contains(query, "abcdef") || !strings.Contains(query, "prefix=photos") {
+		t.Errorf("Unexpected redacted query %s", query)
+	}
+}
+
+// Tests the traced requests filters select.
+func TestTraceFilter(t *testing.T) {
+	s3Trace := requestTrace{Type: requestTypeS3, API: "REST.GET.OBJECT", Bucket: "bucket", StatusCode: http.StatusOK}
+	failedTrace := requestTrace{Type: requestTypeS3, API: "REST.PUT.OBJECT", Bucket: "other", StatusCode: http.StatusForbidden}
+	rpcTrace := requestTrace{Type: requestTypeInternode, API: "Storage.ReadAllHandler", Error: "file not found"}
+
+	testCases := []struct {
+		filter   traceFilter
+		info     requestTrace
+		expected bool
+	}{
+		// Test case - 1.
+		// Empty filter.
+		{traceFilter{}, s3Trace, true},
+		// Test case - 2.
+		// Internode requests are left out by default.
+		{traceFilter{}, rpcTrace, false},
+		// Test case - 3.
+		// Internode requests asked for.
+		{traceFilter{Internode: true}, rpcTrace, true},
+		// Test case - 4.
+		// Bucket filter.
+		{traceFilter{Bucket: "bucket"}, failedTrace, false},
+		// Test case - 5.
+		// API filter.
+		{traceFilter{API: "REST.GET.OBJECT"}, s3Trace, true},
+		// Test case - 6.
+		// Errors only.
+		{traceFilter{ErrorsOnly: true}, s3Trace, false},
+		// Test case - 7.
+		// Failed request.
+		{traceFilter{ErrorsOnly: true}, failedTrace, true},
+		// Test case - 8.
+		// Failed RPC call.
+		{traceFilter{ErrorsOnly: true, Internode: true}, rpcTrace, true},
+	}
+	for i, testCase := range testCases {
+		if got := testCase.filter.matches(testCase.info); got != testCase.expected {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
+
+// Tests streaming traced requests through the trace management API.
+func TestTraceHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer removeAll(rootPath)
+
+	_, xlDirs, err := initTestXLObjLayer()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for trace handler tests.")
+	}
+	defer removeRoots(xlDirs)
+	defer resetGlobalObjectAPI()
+	initNSLock(false)
+
+	mux := router.NewRouter()
+	registerAdminRouter(mux)
+	registerAPIRouter(mux)
+	server := httptest.NewServer(setTraceHandler(mux))
+	defer server.Close()
+
+	req, err := newTestRequest("GET", server.URL+"/?trace&bucket=mybucket&errors=yes", 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to create trace request - %v", err)
+	}
+	req.Header.Set(minioAdminOpHeader, "trace")
+	cred := serverConfig.GetCredential()
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatalf("Failed to sign trace request - %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send trace request - %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d but received %d", http.StatusOK, resp.StatusCode)
+	}
+
+	// Requests of other buckets and successful ones are filtered.
+	for _, path := range []string{"/otherbucket/object", "/mybucket/object"} {
+		var anonResp *http.Response
+		anonResp, err = http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Failed to send request - %v", err)
+		}
+		anonResp.Body.Close()
+	}
+
+	infoCh := make(chan requestTrace)
+	go func() {
+		var info requestTrace
+		if json.NewDecoder(resp.Body).Decode(&info) == nil {
+			infoCh <- info
+		}
+	}()
+	select {
+	case info := <-infoCh:
+		if info.Bucket != "mybucket" || info.Object != "object" || info.API != "REST.GET.OBJECT" || info.StatusCode != http.StatusNotFound {
+			t.Errorf("Unexpected traced request %+v", info)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No traced request received")
+	}
+}
+
+// traceTestService - RPC service of the RPC trace tests.
+type traceTestService struct{}
+
+// Echo - returns its argument, fails for empty ones.
+func (s *traceTestService) Echo(args *string, reply *string) error {
+	if *args == "" {
+		return errInvalidArgument
+	}
+	*reply = *args
+	return nil
+}
+
+// Tests tracing the calls of RPC servers.
+func TestTraceRPC(t *testing.T) {
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("Trace", &traceTestService{}); err != nil {
+		t.Fatal(err)
+	}
+	mux := router.NewRouter()
+	mux.Path("/trace").Handler(newRPCHandler(rpcServer))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	rpcClient := newRPCClient(strings.TrimPrefix(server.URL, "http://"), "/trace", false)
+	defer rpcClient.Close()
+
+	traceCh := globalTrace.Subscribe(traceFilter{Internode: true})
+	defer globalTrace.Unsubscribe(traceCh)
+
+	for _, arg := range []string{"hello", ""} {
+		var reply string
+		err := rpcClient.Call("Trace.Echo", &arg, &reply)
+		if (arg == "") != (err != nil) || reply != arg {
+			t.Fatalf("Unexpected reply %q %v", reply, err)
+		}
+
+		select {
+		case info := <-traceCh:
+			if info.Type != requestTypeInternode || info.API != "Trace.Echo" || info.Path != "/trace" {
+				t.Errorf("Unexpected traced call %+v", info)
+			}
+			if (arg == "") != (info.Error != "") {
+				t.Errorf("Unexpected error of traced call %+v", info)
+			}
+			if info.BytesReceived <= 0 || info.BytesSent <= 0 {
+				t.Errorf("Unexpected size of traced call %+v", info)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("No traced call received")
+		}
+	}
+}
@@ -20,8 +20,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"runtime"
@@ -58,8 +60,9 @@ type ServerInfoRep struct {
 
 // ServerInfo - get server info.
 func (web *webAPIHandlers) ServerInfo(r *http.Request, args *WebGenericArgs, reply *ServerInfoRep) error {
-	if !isHTTPRequestValid(r) {
-		return toJSONError(errAuthentication)
+	// Only the server credentials are allowed.
+	if _, err := webRequestAuthorize(r, "", "", ""); err != nil {
+		return toJSONError(err)
 	}
 	host, err := os.Hostname()
 	if err != nil {
@@ -118,13 +121,13 @@ func (web *webAPIHandlers) MakeBucket(r *http.Request, args *MakeBucketArgs, rep
 	if objectAPI == nil {
 		return toJSONError(errServerNotInitialized)
 	}
-	if !isHTTPRequestValid(r) {
-		return toJSONError(errAuthentication)
+	if _, err := webRequestAuthorize(r, "s3:CreateBucket", args.BucketName, ""); err != nil {
+		return toJSONError(err)
 	}
 	bucketLock := globalNSMutex.NewNSLock(args.BucketName, "")
 	bucketLock.Lock()
 	defer bucketLock.Unlock()
-	if err := objectAPI.MakeBucket(args.BucketName); err != nil {
+	if err := objectAPI.MakeBucket(r.Context(), args.BucketName); err != nil {
 		return toJSONError(err, args.BucketName)
 	}
 	reply.UIVersion = miniobrowser.UIVersion
@@ -151,18 +154,23 @@ func (web *webAPIHandlers) ListBuckets(r *http.Request, args *WebGenericArgs, re
 	if objectAPI == nil {
 		return toJSONError(errServerNotInitialized)
 	}
-	authErr := webReqestAuthenticate(r)
+	accessKey, authErr := webRequestSubject(r)
 	if authErr != nil {
 		return toJSONError(authErr)
 	}
-	buckets, err := objectAPI.ListBuckets()
+	buckets, err := objectAPI.ListBuckets(r.Context())
 	if err != nil {
 		return toJSONError(err)
 	}
+	// Users not allowed to list all the buckets see the ones they can list.
+	listAll := isWebActionAllowed(r, accessKey, "s3:ListAllMyBuckets", "", "")
 	for _, bucket := range buckets {
 		if bucket.Name == path.Base(reservedBucket) {
 			continue
 		}
+		if !listAll && !isWebActionAllowed(r, accessKey, "s3:ListBucket", bucket.Name, "") {
+			continue
+		}
 
 		reply.Buckets = append(reply.Buckets, WebBucketInfo{
 			Name:         bucket.Name,
@@ -206,9 +214,10 @@ func (web *webAPIHandlers) ListObjects(r *http.Request, args *ListObjectsArgs, r
 		return toJSONError(errServerNotInitialized)
 	}
 	prefix := args.Prefix + "test" // To test if GetObject/PutObject with the specified prefix is allowed.
-	readable := isBucketActionAllowed("s3:GetObject", args.BucketName, prefix)
-	writable := isBucketActionAllowed("s3:PutObject", args.BucketName, prefix)
-	authErr := webReqestAuthenticate(r)
+	readable := isBucketActionAllowed(r, "s3:GetObject", args.BucketName, prefix)
+	writable := isBucketActionAllowed(r, "s3:PutObject", args.BucketName, prefix)
+	// Users not allowed to list the bucket fall back to the bucket policy.
+	_, authErr := webRequestAuthorize(r, "s3:ListBucket", args.BucketName, "")
 	switch {
 	case authErr == errAuthentication:
 		return toJSONError(authErr)
@@ -227,7 +236,7 @@ func (web *webAPIHandlers) ListObjects(r *http.Request, args *ListObjectsArgs, r
 	}
 	marker := ""
 	for {
-		lo, err := objectAPI.ListObjects(args.BucketName, args.Prefix, marker, "/", 1000)
+		lo, err := objectAPI.ListObjects(r.Context(), args.BucketName, args.Prefix, marker, "/", 1000)
 		if err != nil {
 			return &json2.Error{Message: err.Error()}
 		}
@@ -265,15 +274,15 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 	if objectAPI == nil {
 		return toJSONError(errServerNotInitialized)
 	}
-	if !isHTTPRequestValid(r) {
-		return toJSONError(errAuthentication)
+	if _, err := webRequestAuthorize(r, "s3:DeleteObject", args.BucketName, args.ObjectName); err != nil {
+		return toJSONError(err)
 	}
 
 	objectLock := globalNSMutex.NewNSLock(args.BucketName, args.ObjectName)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
-	if err := objectAPI.DeleteObject(args.BucketName, args.ObjectName); err != nil {
+	if err := objectAPI.DeleteObject(r.Context(), args.BucketName, args.ObjectName); err != nil {
 		if isErrObjectNotFound(err) {
 			// Ignore object not found error.
 			reply.UIVersion = miniobrowser.UIVersion
@@ -298,10 +307,12 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 	return nil
 }
 
-// LoginArgs - login arguments.
+// LoginArgs - login arguments, either username and password or an
+// OpenID Connect ID token.
 type LoginArgs struct {
 	Username string `json:"username" form:"username"`
 	Password string `json:"password" form:"password"`
+	IDToken  string `json:"idToken" form:"idToken"`
 }
 
 // LoginRep - login reply.
@@ -310,13 +321,25 @@ type LoginRep struct {
 	UIVersion string `json:"uiVersion"`
 }
 
-// Login - user login handler.
+// Login - user login handler. Users other than the server credentials
+// are authenticated against the LDAP directory or by an OpenID Connect
+// ID token if configured, they are issued temporary credentials the
+// auth token is bound to.
 func (web *webAPIHandlers) Login(r *http.Request, args *LoginArgs, reply *LoginRep) error {
-	token, err := authenticateWeb(args.Username, args.Password)
+	var token string
+	var err error
+	if args.IDToken != "" {
+		token, err = authenticateWebOpenID(args.IDToken)
+	} else {
+		token, err = authenticateWeb(args.Username, args.Password)
+		if err != nil && globalLDAPConfig != nil {
+			token, err = authenticateWebLDAP(args.Username, args.Password)
+		}
+	}
 	if err != nil {
 		// Make sure to log errors related to browser login,
 		// for security and auditing reasons.
-		errorIf(err, "Unable to login request from %s", r.RemoteAddr)
+		errorIfCtx(r.Context(), err, "Unable to login request from %s", r.RemoteAddr)
 		return toJSONError(err)
 	}
 
@@ -325,6 +348,49 @@ func (web *webAPIHandlers) Login(r *http.Request, args *LoginArgs, reply *LoginR
 	return nil
 }
 
+// authenticateWebLDAP - authenticates an LDAP user and returns an auth
+// token bound to temporary credentials issued to the user.
+func authenticateWebLDAP(username, password string) (string, error) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		return "", errServerNotInitialized
+	}
+	userDN, groups, err := globalLDAPConfig.authenticate(username, password)
+	if err != nil {
+		if err == errLDAPAuthentication {
+			return "", errAuthentication
+		}
+		return "", err
+	}
+	tempUser, err := assumeRoleWithLDAP(userDN, groups, defaultJWTExpiry, nil, objectAPI)
+	if err != nil {
+		return "", err
+	}
+	return newAuthToken(tempUser.Credential.AccessKey, jwtAudienceWeb, defaultJWTExpiry)
+}
+
+// authenticateWebOpenID - verifies an OpenID Connect ID token and
+// returns an auth token bound to temporary credentials issued to the
+// user.
+func authenticateWebOpenID(idToken string) (string, error) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		return "", errServerNotInitialized
+	}
+	if globalOpenIDConfig == nil {
+		return "", errOpenIDNotConfigured
+	}
+	subject, policies, err := globalOpenIDConfig.validateToken(idToken)
+	if err != nil {
+		return "", errAuthentication
+	}
+	tempUser, err := assumeRoleWithOpenID(subject, policies, defaultJWTExpiry, nil, objectAPI)
+	if err != nil {
+		return "", err
+	}
+	return newAuthToken(tempUser.Credential.AccessKey, jwtAudienceWeb, defaultJWTExpiry)
+}
+
 // GenerateAuthReply - reply for GenerateAuth
 type GenerateAuthReply struct {
 	AccessKey string `json:"accessKey"`
@@ -333,8 +399,9 @@ type GenerateAuthReply struct {
 }
 
 func (web webAPIHandlers) GenerateAuth(r *http.Request, args *WebGenericArgs, reply *GenerateAuthReply) error {
-	if !isHTTPRequestValid(r) {
-		return toJSONError(errAuthentication)
+	// Only the server credentials are allowed.
+	if _, err := webRequestAuthorize(r, "", "", ""); err != nil {
+		return toJSONError(err)
 	}
 	cred := newCredential()
 	reply.AccessKey = cred.AccessKey
@@ -358,8 +425,9 @@ type SetAuthReply struct {
 
 // SetAuth - Set accessKey and secretKey credentials.
 func (web *webAPIHandlers) SetAuth(r *http.Request, args *SetAuthArgs, reply *SetAuthReply) error {
-	if !isHTTPRequestValid(r) {
-		return toJSONError(errAuthentication)
+	// Only the server credentials are allowed.
+	if _, err := webRequestAuthorize(r, "", "", ""); err != nil {
+		return toJSONError(err)
 	}
 
 	// As we already validated the authentication, we save given access/secret keys.
@@ -382,7 +450,7 @@ func (web *webAPIHandlers) SetAuth(r *http.Request, args *SetAuthArgs, reply *Se
 	reply.PeerErrMsgs = make(map[string]string)
 	for svr, errVal := range errsMap {
 		tErr := fmt.Errorf("Unable to change credentials on %s: %v", svr, errVal)
-		errorIf(tErr, "Credentials change could not be propagated successfully!")
+		errorIfCtx(r.Context(), tErr, "Credentials change could not be propagated successfully!")
 		reply.PeerErrMsgs[svr] = errVal.Error()
 	}
 
@@ -422,8 +490,9 @@ type GetAuthReply struct {
 
 // GetAuth - return accessKey and secretKey credentials.
 func (web *webAPIHandlers) GetAuth(r *http.Request, args *WebGenericArgs, reply *GetAuthReply) error {
-	if !isHTTPRequestValid(r) {
-		return toJSONError(errAuthentication)
+	// Only the server credentials are allowed.
+	if _, err := webRequestAuthorize(r, "", "", ""); err != nil {
+		return toJSONError(err)
 	}
 	creds := serverConfig.GetCredential()
 	reply.AccessKey = creds.AccessKey
@@ -444,12 +513,17 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 	bucket := vars["bucket"]
 	object := vars["object"]
 
-	authErr := webReqestAuthenticate(r)
+	// Users not allowed to upload fall back to the bucket policy.
+	_, authErr := webRequestAuthorize(r, "s3:PutObject", bucket, object)
 	if authErr == errAuthentication {
 		writeWebErrorResponse(w, errAuthentication)
 		return
 	}
-	if authErr != nil && !isBucketActionAllowed("s3:PutObject", bucket, object) {
+	if authErr != nil && !isBucketActionAllowed(r, "s3:PutObject", bucket, object) {
+		if authErr == errAccessDenied {
+			writeWebErrorResponse(w, errAccessDenied)
+			return
+		}
 		writeWebErrorResponse(w, errAuthentication)
 		return
 	}
@@ -457,13 +531,41 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 	// Extract incoming metadata if any.
 	metadata := extractMetadataFromHeader(r.Header)
 
+	// Buckets with a default retention retain uploads.
+	if s3Error := setObjectLockMetadata(http.Header{}, bucket, metadata); s3Error != ErrNone {
+		apiErr := getAPIError(s3Error)
+		w.WriteHeader(apiErr.HTTPStatusCode)
+		w.Write([]byte(apiErr.Description))
+		return
+	}
+
+	// Objects matching a replication rule wait to be replicated.
+	setReplicationMetadata(http.Header{}, bucket, object, metadata)
+
+	// Buckets with default encryption encrypt uploads on the fly.
+	var reader io.Reader = r.Body
+	if algorithm, keyID := getBucketEncryption(bucket); algorithm != "" {
+		objectKey, s3Error := newSSEKMSObjectKey(algorithm, keyID, bucket, object, metadata)
+		if s3Error != ErrNone {
+			apiErr := getAPIError(s3Error)
+			w.WriteHeader(apiErr.HTTPStatusCode)
+			w.Write([]byte(apiErr.Description))
+			return
+		}
+		var err error
+		if reader, err = newSSEEncryptReader(reader, -1, objectKey, 1, "", ""); err != nil {
+			writeWebErrorResponse(w, err)
+			return
+		}
+	}
+
 	// Lock the object.
 	objectLock := globalNSMutex.NewNSLock(bucket, object)
 	objectLock.Lock()
 	defer objectLock.Unlock()
 
 	sha256sum := ""
-	objInfo, err := objectAPI.PutObject(bucket, object, -1, r.Body, metadata, sha256sum)
+	objInfo, err := objectAPI.PutObject(r.Context(), bucket, object, -1, reader, metadata, sha256sum)
 	if err != nil {
 		writeWebErrorResponse(w, err)
 		return
@@ -493,7 +595,7 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 	object := vars["object"]
 	token := r.URL.Query().Get("token")
 
-	if !isAuthTokenValid(token) && !isBucketActionAllowed("s3:GetObject", bucket, object) {
+	if !isWebTokenActionAllowed(r, token, "s3:GetObject", bucket, object) && !isBucketActionAllowed(r, "s3:GetObject", bucket, object) {
 		writeWebErrorResponse(w, errAuthentication)
 		return
 	}
@@ -506,13 +608,33 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 	objectLock.RLock()
 	defer objectLock.RUnlock()
 
-	objInfo, err := objectAPI.GetObjectInfo(bucket, object)
+	objInfo, err := objectAPI.GetObjectInfo(r.Context(), bucket, object)
 	if err != nil {
 		writeWebErrorResponse(w, err)
 		return
 	}
+	// SSE-C objects can only be read with the key of their owner, which
+	// is never available to the browser.
+	if isSSECustomerEncrypted(objInfo.UserDefined) {
+		writeWebErrorResponse(w, errEncryptedObject)
+		return
+	}
+
+	// Objects encrypted with a key sealed by the KMS are decrypted like
+	// on GetObject.
+	objectKey, s3Error := getSSEObjectKey(r.Header, "", &objInfo)
+	if s3Error != ErrNone {
+		apiErr := getAPIError(s3Error)
+		w.WriteHeader(apiErr.HTTPStatusCode)
+		w.Write([]byte(apiErr.Description))
+		return
+	}
 	offset := int64(0)
-	err = objectAPI.GetObject(bucket, object, offset, objInfo.Size, w)
+	if objectKey != nil {
+		err = getEncryptedObject(r.Context(), objectAPI, objInfo, "", objectKey, offset, objInfo.Size, w)
+	} else {
+		err = objectAPI.GetObject(r.Context(), bucket, object, offset, objInfo.Size, w)
+	}
 	if err != nil {
 		/// No need to print error, response writer already written to.
 		return
@@ -562,8 +684,8 @@ func (web *webAPIHandlers) GetBucketPolicy(r *http.Request, args *GetBucketPolic
 		return toJSONError(errServerNotInitialized)
 	}
 
-	if !isHTTPRequestValid(r) {
-		return toJSONError(errAuthentication)
+	if _, err := webRequestAuthorize(r, "s3:GetBucketPolicy", args.BucketName, ""); err != nil {
+		return toJSONError(err)
 	}
 
 	policyInfo, err := readBucketAccessPolicy(objectAPI, args.BucketName)
@@ -601,8 +723,8 @@ func (web *webAPIHandlers) ListAllBucketPolicies(r *http.Request, args *ListAllB
 		return toJSONError(errServerNotInitialized)
 	}
 
-	if !isHTTPRequestValid(r) {
-		return toJSONError(errAuthentication)
+	if _, err := webRequestAuthorize(r, "s3:GetBucketPolicy", args.BucketName, ""); err != nil {
+		return toJSONError(err)
 	}
 
 	policyInfo, err := readBucketAccessPolicy(objectAPI, args.BucketName)
@@ -634,8 +756,8 @@ func (web *webAPIHandlers) SetBucketPolicy(r *http.Request, args *SetBucketPolic
 		return toJSONError(errServerNotInitialized)
 	}
 
-	if !isHTTPRequestValid(r) {
-		return toJSONError(errAuthentication)
+	if _, err := webRequestAuthorize(r, "s3:PutBucketPolicy", args.BucketName, ""); err != nil {
+		return toJSONError(err)
 	}
 
 	bucketP := policy.BucketPolicy(args.Policy)
@@ -702,8 +824,9 @@ type PresignedGetRep struct {
 
 // PresignedGET - returns presigned-Get url.
 func (web *webAPIHandlers) PresignedGet(r *http.Request, args *PresignedGetArgs, reply *PresignedGetRep) error {
-	if !isHTTPRequestValid(r) {
-		return toJSONError(errAuthentication)
+	accessKey, err := webRequestAuthorize(r, "s3:GetObject", args.BucketName, args.ObjectName)
+	if err != nil {
+		return toJSONError(err)
 	}
 
 	if args.BucketName == "" || args.ObjectName == "" {
@@ -711,14 +834,18 @@ func (web *webAPIHandlers) PresignedGet(r *http.Request, args *PresignedGetArgs,
 			Message: "Bucket and Object are mandatory arguments.",
 		}
 	}
+	// The URL is signed with the credentials of the user.
+	cred, ok := getCredentialForAccessKey(accessKey)
+	if !ok {
+		return toJSONError(errAuthentication)
+	}
 	reply.UIVersion = miniobrowser.UIVersion
-	reply.URL = presignedGet(args.HostName, args.BucketName, args.ObjectName, args.Expiry)
+	reply.URL = presignedGet(cred, args.HostName, args.BucketName, args.ObjectName, args.Expiry)
 	return nil
 }
 
 // Returns presigned url for GET method.
-func presignedGet(host, bucket, object string, expiry int64) string {
-	cred := serverConfig.GetCredential()
+func presignedGet(cred credential, host, bucket, object string, expiry int64) string {
 	region := serverConfig.GetRegion()
 
 	accessKey := cred.AccessKey
@@ -732,13 +859,17 @@ func presignedGet(host, bucket, object string, expiry int64) string {
 	if expiry < 604800 && expiry > 0 {
 		expiryStr = strconv.FormatInt(expiry, 10)
 	}
-	query := strings.Join([]string{
+	queryParams := []string{
 		"X-Amz-Algorithm=" + signV4Algorithm,
 		"X-Amz-Credential=" + strings.Replace(credential, "/", "%2F", -1),
 		"X-Amz-Date=" + dateStr,
 		"X-Amz-Expires=" + expiryStr,
-		"X-Amz-SignedHeaders=host",
-	}, "&")
+	}
+	// Temporary credentials send their session token, in sorted order.
+	if cred.SessionToken != "" {
+		queryParams = append(queryParams, amzSecurityToken+"="+url.QueryEscape(cred.SessionToken))
+	}
+	query := strings.Join(append(queryParams, "X-Amz-SignedHeaders=host"), "&")
 
 	path := "/" + path.Join(bucket, object)
 
@@ -754,6 +885,37 @@ func presignedGet(host, bucket, object string, expiry int64) string {
 	return host + path + "?" + query + "&" + "X-Amz-Signature=" + signature
 }
 
+// webRequestAuthorize - authenticates the request and checks the user
+// its auth token was issued to is allowed the action on the bucket or
+// object, an empty action is allowed to the server credentials only.
+// Returns the access key of the user, errNoAuthToken if the token is
+// missing, errAccessDenied if the action is not allowed and
+// errAuthentication for all other errors.
+func webRequestAuthorize(r *http.Request, action, bucket, object string) (string, error) {
+	accessKey, err := webRequestSubject(r)
+	if err != nil {
+		return "", err
+	}
+	if !isWebActionAllowed(r, accessKey, action, bucket, object) {
+		return "", errAccessDenied
+	}
+	return accessKey, nil
+}
+
+// isWebTokenActionAllowed - checks if the user the auth token was
+// issued to is allowed the action on the bucket or object.
+func isWebTokenActionAllowed(r *http.Request, token, action, bucket, object string) bool {
+	accessKey, err := authTokenSubject(token)
+	return err == nil && isWebActionAllowed(r, accessKey, action, bucket, object)
+}
+
+// isWebActionAllowed - checks if the user is allowed the action on the
+// bucket or object by its IAM policies and the bucket policy.
+func isWebActionAllowed(r *http.Request, accessKey, action, bucket, object string) bool {
+	reqURL := &url.URL{Path: path.Join("/", bucket, object)}
+	return enforceUserPolicy(accessKey, bucket, action, reqURL, r) == ErrNone
+}
+
 // toJSONError converts regular errors into more user friendly
 // and consumable error message for the browser UI.
 func toJSONError(err error, params ...string) (jerr *json2.Error) {
@@ -791,7 +953,11 @@ func toJSONError(err error, params ...string) (jerr *json2.Error) {
 // toWebAPIError - convert into error into APIError.
 func toWebAPIError(err error) APIError {
 	err = errorCause(err)
-	if err == errAuthentication {
+	// A missing auth token is reported as an authentication failure.
+	if err == errNoAuthToken {
+		err = errAuthentication
+	}
+	if err == errAuthentication || err == errAccessDenied {
 		return APIError{
 			Code:           "AccessDenied",
 			HTTPStatusCode: http.StatusForbidden,
@@ -821,6 +987,10 @@ func toWebAPIError(err error) APIError {
 			HTTPStatusCode: http.StatusForbidden,
 			Description:    err.Error(),
 		}
+	} else if err == errEncryptedObject {
+		return getAPIError(ErrSSEEncryptedObject)
+	} else if err == errOpenIDNotConfigured {
+		return getAPIError(ErrSTSOpenIDNotConfigured)
 	}
 
 	// Convert error type to api error code.
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -27,8 +28,11 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	jwtgo "github.com/dgrijalva/jwt-go"
 	humanize "github.com/dustin/go-humanize"
+	"github.com/gorilla/rpc/v2/json2"
 	"github.com/minio/minio-go/pkg/policy"
 	"github.com/minio/minio-go/pkg/set"
 )
@@ -167,6 +171,214 @@ func testLoginWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler)
 	}
 }
 
+// Wrapper for calling Login Web Handler with LDAP users
+func TestWebHandlerLoginLDAP(t *testing.T) {
+	initNSLock(false)
+	ExecObjectLayerTest(t, testLoginLDAPWebHandler)
+}
+
+// testLoginLDAPWebHandler - Test browser logins of LDAP users, who are
+// allowed what the policies of their groups allow.
+func testLoginLDAPWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+	defer resetGlobalIAMSys()
+	initGlobalS3Peers(nil)
+
+	server, ldapCfg := newTestLDAPServer()
+	defer server.Close()
+	globalLDAPConfig = ldapCfg
+	defer func() { globalLDAPConfig = nil }()
+
+	// Members of the readers group may list and read the first bucket.
+	bucketName := getRandomBucketName()
+	otherBucketName := getRandomBucketName()
+	for _, bucket := range []string{bucketName, otherBucketName} {
+		if err = obj.MakeBucket(context.Background(), bucket); err != nil {
+			t.Fatalf("Failed to make bucket - %v", err)
+		}
+	}
+	data := []byte("data")
+	if _, err = obj.PutObject(context.Background(), bucketName, "object", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatalf("Failed to put object - %v", err)
+	}
+	readPolicy := mustParseIAMPolicy(t, `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": ["s3:ListBucket"], "Resource": ["arn:aws:s3:::`+bucketName+`"]},
+		{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::`+bucketName+`/*"]}]}`)
+	if err = setIAMPolicy("readers", readPolicy, obj); err != nil {
+		t.Fatalf("Failed to add policy - %v", err)
+	}
+
+	if _, err = getWebRPCToken(apiRouter, "alice", "bob123"); err == nil {
+		t.Fatal("Expected login with a wrong password to fail")
+	}
+	authorization, err := getWebRPCToken(apiRouter, "alice", "alice123")
+	if err != nil {
+		t.Fatalf("Failed to login - %v", err)
+	}
+
+	callWebRPC := func(method, authorization string, args, reply interface{}) error {
+		req, err := newTestWebRPCRequest(method, authorization, args)
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: Expected the response status to be 200, but instead found `%d`", method, rec.Code)
+		}
+		return getTestWebRPCResponse(rec, reply)
+	}
+
+	// Only the buckets the user may list are listed.
+	listBucketsReply := &ListBucketsRep{}
+	if err = callWebRPC("Web.ListBuckets", authorization, WebGenericArgs{}, &listBucketsReply); err != nil {
+		t.Fatalf("Failed to list buckets - %v", err)
+	}
+	if len(listBucketsReply.Buckets) != 1 || listBucketsReply.Buckets[0].Name != bucketName {
+		t.Fatalf("Expected only %s to be listed, got %v", bucketName, listBucketsReply.Buckets)
+	}
+
+	// Actions not allowed by the policy are denied.
+	deniedCalls := []struct {
+		method string
+		args   interface{}
+	}{
+		{"Web.GetAuth", WebGenericArgs{}},
+		{"Web.ServerInfo", WebGenericArgs{}},
+		{"Web.MakeBucket", MakeBucketArgs{BucketName: getRandomBucketName()}},
+		{"Web.RemoveObject", RemoveObjectArgs{BucketName: bucketName, ObjectName: "object"}},
+		{"Web.SetBucketPolicy", SetBucketPolicyArgs{BucketName: bucketName, Policy: "readonly"}},
+		{"Web.PresignedGet", PresignedGetArgs{BucketName: otherBucketName, ObjectName: "object"}},
+	}
+	for _, call := range deniedCalls {
+		reply := &WebGenericRep{}
+		err = callWebRPC(call.method, authorization, call.args, &reply)
+		if err == nil || err.Error() != errAccessDenied.Error() {
+			t.Errorf("%s: Expected %v, got %v", call.method, errAccessDenied, err)
+		}
+	}
+
+	// Presigned URLs are signed with the temporary credentials of the user.
+	presignGetRep := &PresignedGetRep{}
+	presignGetReq := PresignedGetArgs{BucketName: bucketName, ObjectName: "object", Expiry: 1000}
+	if err = callWebRPC("Web.PresignedGet", authorization, presignGetReq, &presignGetRep); err != nil {
+		t.Fatalf("Failed to presign - %v", err)
+	}
+	req, err := newTestRequest("GET", presignGetRep.URL, 0, nil)
+	if err != nil {
+		t.Fatal("Failed to initialized a new request", err)
+	}
+	req.Header.Del("x-amz-content-sha256")
+	rec := httptest.NewRecorder()
+	initTestAPIEndPoints(obj, []string{"GetObject"}).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Fatalf("Expected the presigned URL to be readable, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Session tokens of temporary credentials are not auth tokens.
+	for _, tempUser := range globalIAMSys.tempUsers {
+		if err = callWebRPC("Web.StorageInfo", tempUser.Credential.SessionToken, AuthRPCArgs{}, &StorageInfoRep{}); err == nil {
+			t.Error("Expected session token to be rejected")
+		}
+	}
+
+	// Web tokens of LDAP users are not accepted by inter-node RPC.
+	rpcArgs := AuthRPCArgs{AuthToken: authorization, RequestTime: time.Now().UTC()}
+	if err = rpcArgs.IsAuthenticated(); err != errInvalidToken {
+		t.Fatalf("Expected %v for an LDAP web token, got %v", errInvalidToken, err)
+	}
+}
+
+// Wrapper for calling Login Web Handler with OpenID Connect ID tokens
+func TestWebHandlerLoginOpenID(t *testing.T) {
+	initNSLock(false)
+	ExecObjectLayerTest(t, testLoginOpenIDWebHandler)
+}
+
+// testLoginOpenIDWebHandler - Test browser logins with OpenID Connect
+// ID tokens, users are allowed what the policies named in the token
+// allow.
+func testLoginOpenIDWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+	defer resetGlobalIAMSys()
+	initGlobalS3Peers(nil)
+
+	provider, openIDCfg := newTestOpenIDProvider(t)
+	defer provider.Close()
+	defer func() { globalOpenIDConfig = nil }()
+
+	bucketName := getRandomBucketName()
+	for _, bucket := range []string{bucketName, getRandomBucketName()} {
+		if err = obj.MakeBucket(context.Background(), bucket); err != nil {
+			t.Fatalf("Failed to make bucket - %v", err)
+		}
+	}
+	listPolicy := mustParseIAMPolicy(t, `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": ["s3:ListBucket"], "Resource": ["arn:aws:s3:::`+bucketName+`"]}]}`)
+	if err = setIAMPolicy("listers", listPolicy, obj); err != nil {
+		t.Fatalf("Failed to add policy - %v", err)
+	}
+	idToken := provider.signToken(t, jwtgo.MapClaims{"sub": "alice", "policy": []string{"listers"}})
+
+	login := func(idToken string) (string, error) {
+		rec := httptest.NewRecorder()
+		req, err := newTestWebRPCRequest("Web"+loginMethodName, "", LoginArgs{IDToken: idToken})
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		reply := &LoginRep{}
+		err = getTestWebRPCResponse(rec, &reply)
+		return reply.Token, err
+	}
+
+	// OpenID Connect authentication is not configured.
+	if _, err = login(idToken); err == nil {
+		t.Fatal("Expected login to fail without OpenID Connect configuration")
+	}
+	globalOpenIDConfig = openIDCfg
+	if _, err = login(provider.signToken(t, jwtgo.MapClaims{"sub": "alice", "iss": "https://other.example.com"})); err == nil || err.Error() != errAuthentication.Error() {
+		t.Fatalf("Expected %v for a token of another issuer, got %v", errAuthentication, err)
+	}
+	authorization, err := login(idToken)
+	if err != nil {
+		t.Fatalf("Failed to login - %v", err)
+	}
+
+	// Only the buckets the user may list are listed.
+	rec := httptest.NewRecorder()
+	req, err := newTestWebRPCRequest("Web.ListBuckets", authorization, WebGenericArgs{})
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	listBucketsReply := &ListBucketsRep{}
+	if err = getTestWebRPCResponse(rec, &listBucketsReply); err != nil {
+		t.Fatalf("Failed to list buckets - %v", err)
+	}
+	if len(listBucketsReply.Buckets) != 1 || listBucketsReply.Buckets[0].Name != bucketName {
+		t.Fatalf("Expected only %s to be listed, got %v", bucketName, listBucketsReply.Buckets)
+	}
+
+	// Web tokens of OpenID Connect users are not accepted by inter-node RPC.
+	rpcArgs := AuthRPCArgs{AuthToken: authorization, RequestTime: time.Now().UTC()}
+	if err = rpcArgs.IsAuthenticated(); err != errInvalidToken {
+		t.Fatalf("Expected %v for an OpenID Connect web token, got %v", errInvalidToken, err)
+	}
+}
+
 // Wrapper for calling StorageInfo Web Handler
 func TestWebHandlerStorageInfo(t *testing.T) {
 	ExecObjectLayerTest(t, testStorageInfoWebHandler)
@@ -349,7 +561,7 @@ func testListBucketsWebHandler(obj ObjectLayer, instanceType string, t TestErrHa
 
 	bucketName := getRandomBucketName()
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
@@ -409,7 +621,7 @@ func testListObjectsWebHandler(obj ObjectLayer, instanceType string, t TestErrHa
 	objectSize := 1 * humanize.KiByte
 
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
@@ -417,7 +629,7 @@ func testListObjectsWebHandler(obj ObjectLayer, instanceType string, t TestErrHa
 
 	data := bytes.Repeat([]byte("a"), objectSize)
 
-	_, err = obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), map[string]string{"md5Sum": "c9a34cfc85d982698c6ac89f76071abd"}, "")
+	_, err = obj.PutObject(context.Background(), bucketName, objectName, int64(len(data)), bytes.NewReader(data), map[string]string{"md5Sum": "c9a34cfc85d982698c6ac89f76071abd"}, "")
 
 	if err != nil {
 		t.Fatalf("Was not able to upload an object, %v", err)
@@ -512,7 +724,7 @@ func testRemoveObjectWebHandler(obj ObjectLayer, instanceType string, t TestErrH
 	objectSize := 1 * humanize.KiByte
 
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
@@ -520,7 +732,7 @@ func testRemoveObjectWebHandler(obj ObjectLayer, instanceType string, t TestErrH
 
 	data := bytes.Repeat([]byte("a"), objectSize)
 
-	_, err = obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data),
+	_, err = obj.PutObject(context.Background(), bucketName, objectName, int64(len(data)), bytes.NewReader(data),
 		map[string]string{"md5Sum": "c9a34cfc85d982698c6ac89f76071abd"}, "")
 	if err != nil {
 		t.Fatalf("Was not able to upload an object, %v", err)
@@ -758,7 +970,7 @@ func testUploadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler
 		return rec.Code
 	}
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
@@ -771,7 +983,7 @@ func testUploadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler
 	}
 
 	var byteBuffer bytes.Buffer
-	err = obj.GetObject(bucketName, objectName, 0, int64(len(content)), &byteBuffer)
+	err = obj.GetObject(context.Background(), bucketName, objectName, 0, int64(len(content)), &byteBuffer)
 	if err != nil {
 		t.Fatalf("Failed, %v", err)
 	}
@@ -846,14 +1058,14 @@ func testDownloadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandl
 	}
 
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
 	}
 
 	content := []byte("temporary file's content")
-	_, err = obj.PutObject(bucketName, objectName, int64(len(content)), bytes.NewReader(content), map[string]string{"md5Sum": "01ce59706106fe5e02e7f55fffda7f34"}, "")
+	_, err = obj.PutObject(context.Background(), bucketName, objectName, int64(len(content)), bytes.NewReader(content), map[string]string{"md5Sum": "01ce59706106fe5e02e7f55fffda7f34"}, "")
 	if err != nil {
 		t.Fatalf("Was not able to upload an object, %v", err)
 	}
@@ -893,6 +1105,105 @@ func testDownloadWebHandler(obj ObjectLayer, instanceType string, t TestErrHandl
 	}
 }
 
+// Wrapper for calling Upload and Download Handlers on an encrypted bucket
+func TestWebHandlerDownloadEncrypted(t *testing.T) {
+	ExecObjectLayerTest(t, testDownloadEncryptedWebHandler)
+}
+
+// testDownloadEncryptedWebHandler - Test Download web handler on objects
+// encrypted server side
+func testDownloadEncryptedWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+	// initialize the server and obtain the credentials and root.
+	// credentials are necessary to sign the HTTP request.
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	// remove the root directory after the test ends.
+	defer removeAll(rootPath)
+
+	defer func(kms KMS) { globalKMS = kms }(globalKMS)
+	kms, err := newMasterKeyKMS("my-key", bytes.Repeat([]byte{'m'}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalKMS = kms
+
+	credentials := serverConfig.GetCredential()
+
+	authorization, err := getWebRPCToken(apiRouter, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal("Cannot authenticate")
+	}
+
+	bucketName := getRandomBucketName()
+	err = obj.MakeBucket(context.Background(), bucketName)
+	if err != nil {
+		// failed to create newbucket, abort.
+		t.Fatalf("%s : %s", instanceType, err)
+	}
+
+	// Every upload to the bucket is encrypted with SSE-S3.
+	eCfg := &encryptionConfig{Rules: make([]encryptionRule, 1)}
+	eCfg.Rules[0].Default.SSEAlgorithm = sseAlgorithmAES256
+	globalBucketEncryption.Set(bucketName, eCfg)
+	defer globalBucketEncryption.Set(bucketName, nil)
+
+	objectName := "test.file"
+	content := []byte("temporary file's content")
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", "/minio/upload/"+bucketName+"/"+objectName, bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Cannot create upload request, %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+authorization)
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", rec.Code)
+	}
+
+	objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, objectName)
+	if err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if !isEncrypted(objInfo.UserDefined) {
+		t.Fatalf("The uploaded file is not encrypted")
+	}
+
+	download := func(object string) (int, []byte) {
+		rec := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/minio/download/"+bucketName+"/"+object+"?token="+authorization, nil)
+		if err != nil {
+			t.Fatalf("Cannot create download request, %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		return rec.Code, rec.Body.Bytes()
+	}
+
+	// Objects encrypted by the KMS are decrypted on download.
+	code, bodyContent := download(objectName)
+	if code != http.StatusOK {
+		t.Fatalf("Expected the response status to be 200, but instead found `%d`", code)
+	}
+	if !bytes.Equal(bodyContent, content) {
+		t.Fatalf("The downloaded file is corrupted")
+	}
+
+	// SSE-C objects need the key of their owner.
+	ssecObject := "ssec.file"
+	_, err = obj.PutObject(context.Background(), bucketName, ssecObject, int64(len(content)), bytes.NewReader(content),
+		map[string]string{sseSealedKeyMetaKey: "sealed-key", sseCustomerMetaKey: "true"}, "")
+	if err != nil {
+		t.Fatalf("Was not able to upload an object, %v", err)
+	}
+	if code, _ = download(ssecObject); code != http.StatusBadRequest {
+		t.Fatalf("Expected the response status to be 400, but instead found `%d`", code)
+	}
+}
+
 // Wrapper for calling PresignedGet handler
 func TestWebHandlerPresignedGetHandler(t *testing.T) {
 	ExecObjectLayerTest(t, testWebPresignedGetHandler)
@@ -924,14 +1235,14 @@ func testWebPresignedGetHandler(obj ObjectLayer, instanceType string, t TestErrH
 	objectSize := 1 * humanize.KiByte
 
 	// Create bucket.
-	err = obj.MakeBucket(bucketName)
+	err = obj.MakeBucket(context.Background(), bucketName)
 	if err != nil {
 		// failed to create newbucket, abort.
 		t.Fatalf("%s : %s", instanceType, err)
 	}
 
 	data := bytes.Repeat([]byte("a"), objectSize)
-	_, err = obj.PutObject(bucketName, objectName, int64(len(data)), bytes.NewReader(data), map[string]string{"md5Sum": "c9a34cfc85d982698c6ac89f76071abd"}, "")
+	_, err = obj.PutObject(context.Background(), bucketName, objectName, int64(len(data)), bytes.NewReader(data), map[string]string{"md5Sum": "c9a34cfc85d982698c6ac89f76071abd"}, "")
 	if err != nil {
 		t.Fatalf("Was not able to upload an object, %v", err)
 	}
@@ -1033,7 +1344,7 @@ func testWebGetBucketPolicyHandler(obj ObjectLayer, instanceType string, t TestE
 	rec := httptest.NewRecorder()
 
 	bucketName := getRandomBucketName()
-	if err := obj.MakeBucket(bucketName); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
@@ -1116,7 +1427,7 @@ func testWebListAllBucketPoliciesHandler(obj ObjectLayer, instanceType string, t
 	rec := httptest.NewRecorder()
 
 	bucketName := getRandomBucketName()
-	if err := obj.MakeBucket(bucketName); err != nil {
+	if err := obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
@@ -1223,7 +1534,7 @@ func testWebSetBucketPolicyHandler(obj ObjectLayer, instanceType string, t TestE
 
 	// Create a bucket
 	bucketName := getRandomBucketName()
-	if err = obj.MakeBucket(bucketName); err != nil {
+	if err = obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
@@ -1558,3 +1869,23 @@ func TestWebObjectLayerFaultyDisks(t *testing.T) {
 		t.Fatalf("Unexpected error message, expected: `Invalid token`, found: `%s`", resp)
 	}
 }
+
+// Tests that the called browser RPC method is recorded as the API of
+// the request.
+func TestWebRPCCodecMethod(t *testing.T) {
+	body := `{"jsonrpc":"2.0","method":"Web.ListObjects","params":[{}],"id":1}`
+	req, err := http.NewRequest("POST", reservedBucket+"/webrpc", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to create request - %v", err)
+	}
+	info := newReqInfo(req, "ID")
+	req = req.WithContext(contextWithReqInfo(context.Background(), info))
+
+	method, err := webRPCCodec{json2.NewCodec()}.NewRequest(req).Method()
+	if err != nil {
+		t.Fatalf("Unable to read the RPC method. %s", err)
+	}
+	if method != "Web.ListObjects" || info.API != method {
+		t.Errorf("Expected API %s but found %s", method, info.API)
+	}
+}
@@ -43,6 +43,31 @@ func (h indexHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.handler.ServeHTTP(w, r)
 }
 
+// webRPCCodec - json2 codec recording the called method, e.g.
+// Web.ListObjects, as the API of the request.
+type webRPCCodec struct {
+	*json2.Codec
+}
+
+func (c webRPCCodec) NewRequest(r *http.Request) jsonrpc.CodecRequest {
+	return webRPCCodecRequest{CodecRequest: c.Codec.NewRequest(r), r: r}
+}
+
+type webRPCCodecRequest struct {
+	jsonrpc.CodecRequest
+	r *http.Request
+}
+
+func (cr webRPCCodecRequest) Method() (string, error) {
+	method, err := cr.CodecRequest.Method()
+	if err == nil {
+		if info := reqInfoFromContext(cr.r.Context()); info != nil {
+			info.API = method
+		}
+	}
+	return method, err
+}
+
 const assetPrefix = "production"
 
 func assetFS() *assetfs.AssetFS {
@@ -65,7 +90,7 @@ func registerWebRouter(mux *router.Router) error {
 	}
 
 	// Initialize a new json2 codec.
-	codec := json2.NewCodec()
+	codec := webRPCCodec{json2.NewCodec()}
 
 	// Minio browser router.
 	webBrowserRouter := mux.NewRoute().PathPrefix(reservedBucket).Subrouter()
@@ -0,0 +1,233 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Buckets with a website configuration are served as static websites
+// on `<bucket>.<website domain>` when MINIO_WEBSITE_DOMAIN is set.
+// Website requests are anonymous, only objects which the bucket policy
+// allows everyone to read are served.
+
+// websiteHandler - serves website requests, all other requests are
+// passed on to the S3 API.
+type websiteHandler struct {
+	handler http.Handler
+}
+
+func setWebsiteHandler(h http.Handler) http.Handler {
+	return websiteHandler{handler: h}
+}
+
+// Returns the bucket a request to the website domain is for, empty if
+// the request is not a website request.
+func getWebsiteBucket(host string) string {
+	if globalWebsiteDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	suffix := "." + globalWebsiteDomain
+	host = strings.ToLower(host)
+	if !strings.HasSuffix(host, suffix) {
+		return ""
+	}
+	return strings.TrimSuffix(host, suffix)
+}
+
+func (h websiteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket := getWebsiteBucket(r.Host)
+	if bucket == "" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	serveWebsite(w, r, bucket)
+}
+
+// Returns the scheme of a request as seen by the client.
+func getRequestScheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// Serves a website request for the bucket.
+func serveWebsite(w http.ResponseWriter, r *http.Request, bucket string) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeWebsiteErrorPage(w, r, bucket, ErrServerNotInitialized)
+		return
+	}
+	if r.Method != httpGET && r.Method != httpHEAD {
+		writeWebsiteErrorPage(w, r, bucket, ErrMethodNotAllowed)
+		return
+	}
+
+	if _, err := objectAPI.GetBucketInfo(r.Context(), bucket); err != nil {
+		writeWebsiteErrorPage(w, r, bucket, toAPIErrorCode(err))
+		return
+	}
+	wCfg := getBucketWebsite(bucket)
+	if wCfg == nil {
+		writeWebsiteErrorPage(w, r, bucket, ErrNoSuchWebsiteConfiguration)
+		return
+	}
+
+	// All requests go to another host.
+	if redirectAll := wCfg.RedirectAllRequestsTo; redirectAll != nil {
+		protocol := redirectAll.Protocol
+		if protocol == "" {
+			protocol = getRequestScheme(r)
+		}
+		http.Redirect(w, r, protocol+"://"+redirectAll.HostName+r.URL.RequestURI(), http.StatusMovedPermanently)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, slashSeparator)
+	if rule := wCfg.matchRoutingRule(key, 0); rule != nil {
+		redirectWebsiteRequest(w, r, *rule, key)
+		return
+	}
+
+	// Directories are served by their index document.
+	object := key
+	if object == "" || strings.HasSuffix(object, slashSeparator) {
+		object += wCfg.IndexDocument.Suffix
+	}
+
+	s3Error := writeWebsiteObject(w, r, objectAPI, bucket, object, http.StatusOK)
+	if s3Error == ErrNoSuchKey && object == key {
+		// Paths of directories without a trailing slash are
+		// redirected, like on s3.
+		if _, err := objectAPI.GetObjectInfo(r.Context(), bucket, key+slashSeparator+wCfg.IndexDocument.Suffix); err == nil {
+			http.Redirect(w, r, slashSeparator+key+slashSeparator, http.StatusFound)
+			return
+		}
+	}
+	if s3Error == ErrNone {
+		return
+	}
+
+	if rule := wCfg.matchRoutingRule(key, getAPIError(s3Error).HTTPStatusCode); rule != nil {
+		redirectWebsiteRequest(w, r, *rule, key)
+		return
+	}
+	writeWebsiteError(w, r, objectAPI, wCfg, bucket, s3Error)
+}
+
+// Redirects a website request as told by a routing rule.
+func redirectWebsiteRequest(w http.ResponseWriter, r *http.Request, rule websiteRoutingRule, key string) {
+	redirect := rule.Redirect
+	protocol := redirect.Protocol
+	if protocol == "" {
+		protocol = getRequestScheme(r)
+	}
+	host := redirect.HostName
+	if host == "" {
+		host = r.Host
+	}
+	switch {
+	case redirect.ReplaceKeyWith != "":
+		key = redirect.ReplaceKeyWith
+	case redirect.ReplaceKeyPrefixWith != "":
+		prefix := ""
+		if rule.Condition != nil {
+			prefix = rule.Condition.KeyPrefixEquals
+		}
+		key = redirect.ReplaceKeyPrefixWith + strings.TrimPrefix(key, prefix)
+	}
+	code := redirect.HTTPRedirectCode
+	if code == 0 {
+		code = http.StatusMovedPermanently
+	}
+	location := url.URL{Scheme: protocol, Host: host, Path: slashSeparator + key}
+	http.Redirect(w, r, location.String(), code)
+}
+
+// Writes an object of a website with the given status code, only
+// objects which everyone may read are served.
+func writeWebsiteObject(w http.ResponseWriter, r *http.Request, objectAPI ObjectLayer, bucket, object string, statusCode int) APIErrorCode {
+	objectURL := &url.URL{Path: slashSeparator + bucket + slashSeparator + object}
+	if s3Error := enforceBucketPolicy(bucket, "s3:GetObject", objectURL, r); s3Error != ErrNone {
+		return s3Error
+	}
+
+	// Lock the object before reading.
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	objectLock.RLock()
+	defer objectLock.RUnlock()
+
+	objInfo, err := objectAPI.GetObjectInfo(r.Context(), bucket, object)
+	if err != nil {
+		return toAPIErrorCode(err)
+	}
+
+	// Objects encrypted with a client key can not be served.
+	objectKey, s3Error := getSSEObjectKey(http.Header{}, "", &objInfo)
+	if s3Error != ErrNone {
+		return ErrAccessDenied
+	}
+
+	setObjectHeaders(w, objInfo, nil)
+	w.WriteHeader(statusCode)
+	if r.Method == httpHEAD {
+		return ErrNone
+	}
+	if objectKey != nil {
+		err = getEncryptedObject(r.Context(), objectAPI, objInfo, "", objectKey, 0, objInfo.Size, w)
+	} else {
+		err = objectAPI.GetObject(r.Context(), bucket, object, 0, objInfo.Size, w)
+	}
+	// Headers are already sent, errors can only be logged.
+	errorIfCtx(r.Context(), err, "Unable to write website object %s/%s.", bucket, object)
+	return ErrNone
+}
+
+// Writes the error document of a website for client errors, a plain
+// error page otherwise.
+func writeWebsiteError(w http.ResponseWriter, r *http.Request, objectAPI ObjectLayer, wCfg *websiteConfig, bucket string, s3Error APIErrorCode) {
+	statusCode := getAPIError(s3Error).HTTPStatusCode
+	if wCfg.ErrorDocument != nil && statusCode >= 400 && statusCode < 500 {
+		if writeWebsiteObject(w, r, objectAPI, bucket, wCfg.ErrorDocument.Key, statusCode) == ErrNone {
+			return
+		}
+	}
+	writeWebsiteErrorPage(w, r, bucket, s3Error)
+}
+
+// Writes a plain HTML error page.
+func writeWebsiteErrorPage(w http.ResponseWriter, r *http.Request, bucket string, s3Error APIErrorCode) {
+	apiErr := getAPIError(s3Error)
+	title := fmt.Sprintf("%d %s", apiErr.HTTPStatusCode, http.StatusText(apiErr.HTTPStatusCode))
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(apiErr.HTTPStatusCode)
+	if r.Method == httpHEAD {
+		return
+	}
+	io.WriteString(w, fmt.Sprintf("<html>\n<head><title>%s</title></head>\n<body>\n<h1>%s</h1>\n<ul>\n<li>Code: %s</li>\n<li>Message: %s</li>\n<li>BucketName: %s</li>\n</ul>\n</body>\n</html>\n",
+		title, title, html.EscapeString(apiErr.Code), html.EscapeString(apiErr.Description), html.EscapeString(bucket)))
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"sort"
 	"sync"
 )
@@ -30,7 +31,7 @@ var bucketMetadataOpIgnoredErrs = append(bucketOpIgnoredErrs, errVolumeNotFound)
 /// Bucket operations
 
 // MakeBucket - make a bucket.
-func (xl xlObjects) MakeBucket(bucket string) error {
+func (xl xlObjects) MakeBucket(ctx context.Context, bucket string) error {
 	// Verify if bucket is valid.
 	if !IsValidBucketName(bucket) {
 		return traceError(BucketNameInvalid{Bucket: bucket})
@@ -143,7 +144,7 @@ func (xl xlObjects) getBucketInfo(bucketName string) (bucketInfo BucketInfo, err
 }
 
 // GetBucketInfo - returns BucketInfo for a bucket.
-func (xl xlObjects) GetBucketInfo(bucket string) (BucketInfo, error) {
+func (xl xlObjects) GetBucketInfo(ctx context.Context, bucket string) (BucketInfo, error) {
 	// Verify if bucket is valid.
 	if !IsValidBucketName(bucket) {
 		return BucketInfo{}, BucketNameInvalid{Bucket: bucket}
@@ -202,7 +203,7 @@ func (xl xlObjects) listBuckets() (bucketsInfo []BucketInfo, err error) {
 }
 
 // ListBuckets - lists all the buckets, sorted by its name.
-func (xl xlObjects) ListBuckets() ([]BucketInfo, error) {
+func (xl xlObjects) ListBuckets(ctx context.Context) ([]BucketInfo, error) {
 	bucketInfos, err := xl.listBuckets()
 	if err != nil {
 		return nil, toObjectErr(err)
@@ -213,12 +214,17 @@ func (xl xlObjects) ListBuckets() ([]BucketInfo, error) {
 }
 
 // DeleteBucket - deletes a bucket.
-func (xl xlObjects) DeleteBucket(bucket string) error {
+func (xl xlObjects) DeleteBucket(ctx context.Context, bucket string) error {
 	// Verify if bucket is valid.
 	if !IsValidBucketName(bucket) {
 		return BucketNameInvalid{Bucket: bucket}
 	}
 
+	// Saved object versions keep the bucket from being deleted.
+	if xl.hasVersions(bucket) {
+		return toObjectErr(traceError(errVolumeNotEmpty), bucket)
+	}
+
 	// Collect if all disks report volume not found.
 	var wg = &sync.WaitGroup{}
 	var dErrs = make([]error, len(xl.storageDisks))
@@ -145,7 +145,8 @@ func xlHealStat(xl xlObjects, partsMetadata []xlMetaV1, errs []error) HealObject
 	// Less than quorum erasure coded blocks of the object have the same create time.
 	// This object can't be healed with the information we have.
 	modTime, count := commonTime(listObjectModtimes(partsMetadata, errs))
-	if count < xl.readQuorum {
+	readQuorum, _ := xl.objectQuorumFromMeta(partsMetadata, errs)
+	if count < readQuorum {
 		return HealObjectInfo{
 			Status:              quorumUnavailable,
 			MissingDataCount:    0,
@@ -171,9 +172,9 @@ func xlHealStat(xl xlObjects, partsMetadata []xlMetaV1, errs []error) HealObject
 	for i, err := range errs {
 		// xl.json is not found, which implies the erasure
 		// coded blocks are unavailable in the corresponding disk.
-		// First half of the disks are data and the rest are parity.
+		// First disks of the distribution are data and the rest are parity.
 		if realErr := errorCause(err); realErr == errFileNotFound || realErr == errDiskNotFound {
-			if xlMeta.Erasure.Distribution[i]-1 < xl.dataBlocks {
+			if xlMeta.Erasure.Distribution[i]-1 < xlMeta.Erasure.DataBlocks {
 				missingDataCount++
 			} else {
 				missingParityCount++
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"sort"
@@ -58,18 +59,21 @@ func healFormatXL(storageDisks []StorageAPI) (err error) {
 // Heals a bucket if it doesn't exist on one of the disks, additionally
 // also heals the missing entries for bucket metadata files
 // `policy.json, notification.xml, listeners.json`.
-func (xl xlObjects) HealBucket(bucket string) error {
+func (xl xlObjects) HealBucket(ctx context.Context, bucket string) error {
 	if err := checkBucketExist(bucket, xl); err != nil {
 		return err
 	}
 
 	// Heal bucket.
 	if err := healBucket(xl.storageDisks, bucket, xl.writeQuorum); err != nil {
+		globalHealMetrics.RecordBucket(err)
 		return err
 	}
 
 	// Proceed to heal bucket metadata.
-	return healBucketMetadata(xl.storageDisks, bucket, xl.readQuorum)
+	err := healBucketMetadata(xl.storageDisks, bucket, xl.readQuorum)
+	globalHealMetrics.RecordBucket(err)
+	return err
 }
 
 // Heal bucket - create buckets on disks where it does not exist.
@@ -251,7 +255,7 @@ func (xl xlObjects) bucketHealStatus(bucketName string) (healStatus, error) {
 }
 
 // ListBucketsHeal - Find all buckets that need to be healed
-func (xl xlObjects) ListBucketsHeal() ([]BucketInfo, error) {
+func (xl xlObjects) ListBucketsHeal(ctx context.Context) ([]BucketInfo, error) {
 	listBuckets := []BucketInfo{}
 	// List all buckets that can be found in all disks
 	buckets, occ, err := listAllBuckets(xl.storageDisks)
@@ -314,9 +318,14 @@ func quickHeal(storageDisks []StorageAPI, writeQuorum int, readQuorum int) error
 	return nil
 }
 
-// Heals an object only the corrupted/missing erasure blocks.
+// Heals an object only the corrupted/missing erasure blocks. Objects
+// are read with the quorum of their erasure info, quorum is used for
+// objects without a valid `xl.json`.
 func healObject(storageDisks []StorageAPI, bucket string, object string, quorum int) error {
 	partsMetadata, errs := readAllXLMetadata(storageDisks, bucket, object)
+	if readQuorum, _, ok := getObjectQuorum(partsMetadata, errs); ok {
+		quorum = readQuorum
+	}
 	if reducedErr := reduceReadQuorumErrs(errs, nil, quorum); reducedErr != nil {
 		return toObjectErr(reducedErr, bucket, object)
 	}
@@ -440,7 +449,7 @@ func healObject(storageDisks []StorageAPI, bucket string, object string, quorum
 // FIXME: If an object object was deleted and one disk was down,
 // and later the disk comes back up again, heal on the object
 // should delete it.
-func (xl xlObjects) HealObject(bucket, object string) error {
+func (xl xlObjects) HealObject(ctx context.Context, bucket, object string) error {
 	if err := checkGetObjArgs(bucket, object); err != nil {
 		return err
 	}
@@ -451,5 +460,7 @@ func (xl xlObjects) HealObject(bucket, object string) error {
 	defer objectLock.RUnlock()
 
 	// Heal the object.
-	return healObject(xl.storageDisks, bucket, object, xl.readQuorum)
+	err := healObject(xl.storageDisks, bucket, object, xl.readQuorum)
+	globalHealMetrics.RecordObject(err)
+	return err
 }
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -255,7 +256,7 @@ func TestHealFormatXL(t *testing.T) {
 		t.Fatal(err)
 	}
 	xl = obj.(*xlObjects)
-	if err = obj.MakeBucket(getRandomBucketName()); err != nil {
+	if err = obj.MakeBucket(context.Background(), getRandomBucketName()); err != nil {
 		t.Fatal(err)
 	}
 	for i := 0; i <= 2; i++ {
@@ -296,14 +297,14 @@ func TestUndoMakeBucket(t *testing.T) {
 	}
 
 	bucketName := getRandomBucketName()
-	if err = obj.MakeBucket(bucketName); err != nil {
+	if err = obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal(err)
 	}
 	xl := obj.(*xlObjects)
 	undoMakeBucket(xl.storageDisks, bucketName)
 
 	// Validate if bucket was deleted properly.
-	_, err = obj.GetBucketInfo(bucketName)
+	_, err = obj.GetBucketInfo(context.Background(), bucketName)
 	if err != nil {
 		err = errorCause(err)
 		switch err.(type) {
@@ -341,7 +342,7 @@ func TestQuickHeal(t *testing.T) {
 	}
 
 	bucketName := getRandomBucketName()
-	if err = obj.MakeBucket(bucketName); err != nil {
+	if err = obj.MakeBucket(context.Background(), bucketName); err != nil {
 		t.Fatal(err)
 	}
 
@@ -451,13 +452,13 @@ func TestListBucketsHeal(t *testing.T) {
 
 	// Create a bucket that won't get corrupted
 	saneBucket := "sanebucket"
-	if err = obj.MakeBucket(saneBucket); err != nil {
+	if err = obj.MakeBucket(context.Background(), saneBucket); err != nil {
 		t.Fatal(err)
 	}
 
 	// Create a bucket that will be removed in some disks
 	corruptedBucketName := getRandomBucketName()
-	if err = obj.MakeBucket(corruptedBucketName); err != nil {
+	if err = obj.MakeBucket(context.Background(), corruptedBucketName); err != nil {
 		t.Fatal(err)
 	}
 
@@ -471,7 +472,7 @@ func TestListBucketsHeal(t *testing.T) {
 	}
 
 	// List the missing buckets.
-	buckets, err := xl.ListBucketsHeal()
+	buckets, err := xl.ListBucketsHeal(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
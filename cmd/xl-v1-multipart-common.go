@@ -22,8 +22,9 @@ import (
 	"time"
 )
 
-// updateUploadJSON - add or remove upload ID info in all `uploads.json`.
-func (xl xlObjects) updateUploadJSON(bucket, object, uploadID string, initiated time.Time, isRemove bool) error {
+// updateUploadJSON - add or remove upload ID info in all `uploads.json`,
+// writeQuorum is the quorum of the upload.
+func (xl xlObjects) updateUploadJSON(bucket, object, uploadID string, initiated time.Time, writeQuorum int, isRemove bool) error {
 	uploadsPath := path.Join(bucket, object, uploadsJSONFile)
 	tmpUploadsPath := mustGetUUID()
 
@@ -87,7 +88,7 @@ func (xl xlObjects) updateUploadJSON(bucket, object, uploadID string, initiated
 	wg.Wait()
 
 	// Do we have write quorum?
-	if !isDiskQuorum(errs, xl.writeQuorum) {
+	if !isDiskQuorum(errs, writeQuorum) {
 		// No quorum. Perform cleanup on the minority of disks
 		// on which the operation succeeded.
 
@@ -139,20 +140,20 @@ func (xl xlObjects) updateUploadJSON(bucket, object, uploadID string, initiated
 	}
 	wg.Wait()
 
-	if reducedErr := reduceWriteQuorumErrs(errs, objectOpIgnoredErrs, xl.writeQuorum); reducedErr != nil {
+	if reducedErr := reduceWriteQuorumErrs(errs, objectOpIgnoredErrs, writeQuorum); reducedErr != nil {
 		return reducedErr
 	}
 	return nil
 }
 
 // addUploadID - add upload ID and its initiated time to 'uploads.json'.
-func (xl xlObjects) addUploadID(bucket, object string, uploadID string, initiated time.Time) error {
-	return xl.updateUploadJSON(bucket, object, uploadID, initiated, false)
+func (xl xlObjects) addUploadID(bucket, object string, uploadID string, initiated time.Time, writeQuorum int) error {
+	return xl.updateUploadJSON(bucket, object, uploadID, initiated, writeQuorum, false)
 }
 
 // removeUploadID - remove upload ID in 'uploads.json'.
-func (xl xlObjects) removeUploadID(bucket, object string, uploadID string) error {
-	return xl.updateUploadJSON(bucket, object, uploadID, time.Time{}, true)
+func (xl xlObjects) removeUploadID(bucket, object string, uploadID string, writeQuorum int) error {
+	return xl.updateUploadJSON(bucket, object, uploadID, time.Time{}, writeQuorum, true)
 }
 
 // Returns if the prefix is a multipart upload.
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"crypto/md5"
 	"crypto/sha256"
 	"encoding/hex"
@@ -45,15 +46,19 @@ var objectOpIgnoredErrs = []error{
 // CopyObject - copy object source object to destination object.
 // if source object and destination object are same we only
 // update metadata.
-func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string, metadata map[string]string) (ObjectInfo, error) {
+func (xl xlObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, metadata map[string]string) (ObjectInfo, error) {
 	// Read metadata associated with the object from all disks.
 	metaArr, errs := readAllXLMetadata(xl.storageDisks, srcBucket, srcObject)
+
+	// Get quorum of the object from its erasure info.
+	readQuorum, writeQuorum := xl.objectQuorumFromMeta(metaArr, errs)
+
 	// Do we have read quorum?
-	if !isDiskQuorum(errs, xl.readQuorum) {
+	if !isDiskQuorum(errs, readQuorum) {
 		return ObjectInfo{}, traceError(InsufficientReadQuorum{}, errs...)
 	}
 
-	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, xl.readQuorum); reducedErr != nil {
+	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, readQuorum); reducedErr != nil {
 		return ObjectInfo{}, toObjectErr(reducedErr, srcBucket, srcObject)
 	}
 
@@ -73,23 +78,33 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 	length := xlMeta.Stat.Size
 
 	// Check if this request is only metadata update.
+	// Versioned buckets always need a new version saved.
 	cpMetadataOnly := strings.EqualFold(pathJoin(srcBucket, srcObject), pathJoin(dstBucket, dstObject))
+	cpMetadataOnly = cpMetadataOnly && getBucketVersioningStatus(dstBucket) == ""
+	// A new storage class needs the object erasure coded again.
+	cpMetadataOnly = cpMetadataOnly && getStorageClass(metadata) == getStorageClass(xlMeta.Meta)
 	if cpMetadataOnly {
+		// Retained objects can not be replaced.
+		if err = checkObjectRetention(xl, srcBucket, srcObject, "", false); err != nil {
+			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
+		}
+
 		xlMeta.Meta = metadata
-		partsMetadata := make([]xlMetaV1, len(xl.storageDisks))
-		// Update `xl.json` content on each disks.
+		partsMetadata := getOrderedPartsMetadata(xlMeta.Erasure.Distribution, metaArr)
+		// Update `xl.json` content on each disks, each disk keeps its
+		// own erasure checksums.
 		for index := range partsMetadata {
-			partsMetadata[index] = xlMeta
+			partsMetadata[index].Meta = metadata
 		}
 
 		tempObj := mustGetUUID()
 
 		// Write unique `xl.json` for each disk.
-		if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, xl.writeQuorum); err != nil {
+		if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, writeQuorum); err != nil {
 			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
 		}
 		// Rename atomically `xl.json` from tmp location to destination for each disk.
-		if err = renameXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, srcBucket, srcObject, xl.writeQuorum); err != nil {
+		if err = renameXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, srcBucket, srcObject, writeQuorum); err != nil {
 			return ObjectInfo{}, toObjectErr(err, srcBucket, srcObject)
 		}
 
@@ -107,6 +122,7 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 		// need to remove it from xlMetaMap to avoid it from appearing as
 		// part of response headers. e.g, X-Minio-* or X-Amz-*.
 		delete(xlMeta.Meta, "md5Sum")
+		objInfo.UserTags = extractObjectTags(xlMeta.Meta)
 		objInfo.UserDefined = xlMeta.Meta
 		return objInfo, nil
 	}
@@ -116,15 +132,15 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 
 	go func() {
 		startOffset := int64(0) // Read the whole file.
-		if gerr := xl.GetObject(srcBucket, srcObject, startOffset, length, pipeWriter); gerr != nil {
-			errorIf(gerr, "Unable to read %s of the object `%s/%s`.", srcBucket, srcObject)
+		if gerr := xl.GetObject(ctx, srcBucket, srcObject, startOffset, length, pipeWriter); gerr != nil {
+			errorIfCtx(ctx, gerr, "Unable to read %s of the object `%s/%s`.", srcBucket, srcObject)
 			pipeWriter.CloseWithError(toObjectErr(gerr, srcBucket, srcObject))
 			return
 		}
 		pipeWriter.Close() // Close writer explicitly signalling we wrote all data.
 	}()
 
-	objInfo, err := xl.PutObject(dstBucket, dstObject, length, pipeReader, metadata, "")
+	objInfo, err := xl.PutObject(ctx, dstBucket, dstObject, length, pipeReader, metadata, "")
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(err, dstBucket, dstObject)
 	}
@@ -141,11 +157,16 @@ func (xl xlObjects) CopyObject(srcBucket, srcObject, dstBucket, dstObject string
 //
 // startOffset indicates the starting read location of the object.
 // length indicates the total length of the object.
-func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length int64, writer io.Writer) error {
+func (xl xlObjects) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer) error {
 	if err := checkGetObjArgs(bucket, object); err != nil {
 		return err
 	}
+	return xl.getObject(ctx, bucket, object, startOffset, length, writer)
+}
 
+// getObject - wrapper for reading object data, also used for reading
+// saved object versions from minioMetaBucket.
+func (xl xlObjects) getObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer) error {
 	// Start offset cannot be negative.
 	if startOffset < 0 {
 		return traceError(errUnexpected)
@@ -158,12 +179,16 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 
 	// Read metadata associated with the object from all disks.
 	metaArr, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+
+	// Get quorum of the object from its erasure info.
+	readQuorum, _ := xl.objectQuorumFromMeta(metaArr, errs)
+
 	// Do we have read quorum?
-	if !isDiskQuorum(errs, xl.readQuorum) {
+	if !isDiskQuorum(errs, readQuorum) {
 		return traceError(InsufficientReadQuorum{}, errs...)
 	}
 
-	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, xl.readQuorum); reducedErr != nil {
+	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, readQuorum); reducedErr != nil {
 		return toObjectErr(reducedErr, bucket, object)
 	}
 
@@ -287,9 +312,9 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 		}
 
 		// Start erasure decoding and writing to the client.
-		n, err := erasureReadFile(mw, onlineDisks, bucket, pathJoin(object, partName), partOffset, readSize, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, ckSumAlgo, pool)
+		n, err := erasureReadFile(ctx, mw, onlineDisks, bucket, pathJoin(object, partName), partOffset, readSize, partSize, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, checkSums, ckSumAlgo, pool)
 		if err != nil {
-			errorIf(err, "Unable to read %s of the object `%s/%s`.", partName, bucket, object)
+			errorIfCtx(ctx, err, "Unable to read %s of the object `%s/%s`.", partName, bucket, object)
 			return toObjectErr(err, bucket, object)
 		}
 
@@ -306,7 +331,7 @@ func (xl xlObjects) GetObject(bucket, object string, startOffset int64, length i
 }
 
 // GetObjectInfo - reads object metadata and replies back ObjectInfo.
-func (xl xlObjects) GetObjectInfo(bucket, object string) (ObjectInfo, error) {
+func (xl xlObjects) GetObjectInfo(ctx context.Context, bucket, object string) (ObjectInfo, error) {
 	if err := checkGetObjArgs(bucket, object); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -343,7 +368,16 @@ func (xl xlObjects) getObjectInfo(bucket, object string) (objInfo ObjectInfo, er
 	// part of response headers. e.g, X-Minio-* or X-Amz-*.
 
 	delete(xlMetaMap, "md5Sum")
+	objInfo.VersionID, objInfo.DeleteMarker = extractVersionInfo(xlMetaMap)
+	objInfo.UserTags = extractObjectTags(xlMetaMap)
 	objInfo.UserDefined = xlMetaMap
+
+	// Encrypted objects are decrypted part by part.
+	if isEncrypted(xlMetaMap) {
+		if objInfo.Parts, _, err = xl.readXLMetaParts(bucket, object); err != nil {
+			return ObjectInfo{}, err
+		}
+	}
 	return objInfo, nil
 }
 
@@ -439,7 +473,7 @@ func renameObject(disks []StorageAPI, srcBucket, srcObject, dstBucket, dstObject
 // until EOF, erasure codes the data across all disk and additionally
 // writes `xl.json` which carries the necessary metadata for future
 // object operations.
-func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
+func (xl xlObjects) PutObject(ctx context.Context, bucket string, object string, size int64, data io.Reader, metadata map[string]string, sha256sum string) (objInfo ObjectInfo, err error) {
 	// This is a special case with size as '0' and object ends with
 	// a slash separator, we treat it like a valid operation and
 	// return success.
@@ -453,6 +487,12 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	if metadata == nil {
 		metadata = make(map[string]string)
 	}
+	setObjectVersionID(bucket, metadata)
+
+	// Retained objects can not be replaced.
+	if err = checkObjectRetention(xl, bucket, object, "", false); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
 
 	uniqueID := mustGetUUID()
 	tempErasureObj := path.Join(uniqueID, "part.1")
@@ -506,8 +546,12 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	// Tee reader combines incoming data stream and md5, data read from input stream is written to md5.
 	teeReader := io.TeeReader(limitDataReader, mw)
 
-	// Initialize xl meta.
-	xlMeta := newXLMetaV1(object, xl.dataBlocks, xl.parityBlocks)
+	// Initialize xl meta, parity is chosen by the storage class.
+	dataBlocks, parityBlocks := xl.storageClassBlocks(metadata)
+	xlMeta := newXLMetaV1(object, dataBlocks, parityBlocks)
+
+	// Get quorum of the object from its erasure info.
+	_, writeQuorum := objectQuorum(dataBlocks, parityBlocks)
 
 	onlineDisks := getOrderedDisks(xlMeta.Erasure.Distribution, xl.storageDisks)
 
@@ -526,7 +570,7 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	}
 
 	// Erasure code data and write across all disks.
-	sizeWritten, checkSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tempErasureObj, teeReader, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, bitRotAlgo, xl.writeQuorum)
+	sizeWritten, checkSums, err := erasureCreateFile(onlineDisks, minioMetaTmpBucket, tempErasureObj, teeReader, xlMeta.Erasure.BlockSize, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks, bitRotAlgo, writeQuorum)
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(err, minioMetaTmpBucket, tempErasureObj)
 	}
@@ -582,6 +626,11 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		return ObjectInfo{}, toObjectErr(traceError(errFileAccessDenied), bucket, object)
 	}
 
+	// Save the current version of the object on versioned buckets.
+	if err = archiveCurrentVersion(xl, bucket, object); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
 	// Rename if an object already exists to temporary location.
 	newUniqueID := mustGetUUID()
 	if xl.isObject(bucket, object) {
@@ -591,7 +640,7 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		// NOTE: Do not use online disks slice here.
 		// The reason is that existing object should be purged
 		// regardless of `xl.json` status and rolled back in case of errors.
-		err = renameObject(xl.storageDisks, bucket, object, minioMetaTmpBucket, newUniqueID, xl.writeQuorum)
+		err = renameObject(xl.storageDisks, bucket, object, minioMetaTmpBucket, newUniqueID, writeQuorum)
 		if err != nil {
 			return ObjectInfo{}, toObjectErr(err, bucket, object)
 		}
@@ -617,12 +666,12 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 	}
 
 	// Write unique `xl.json` for each disk.
-	if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, xl.writeQuorum); err != nil {
+	if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, writeQuorum); err != nil {
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
 
 	// Rename the successfully written temporary object to final location.
-	err = renameObject(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, xl.writeQuorum)
+	err = renameObject(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, writeQuorum)
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
@@ -644,6 +693,8 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 		ContentEncoding: xlMeta.Meta["content-encoding"],
 		UserDefined:     xlMeta.Meta,
 	}
+	objInfo.VersionID, objInfo.DeleteMarker = extractVersionInfo(objInfo.UserDefined)
+	objInfo.UserTags = extractObjectTags(objInfo.UserDefined)
 
 	// Success, return object info.
 	return objInfo, nil
@@ -653,6 +704,9 @@ func (xl xlObjects) PutObject(bucket string, object string, size int64, data io.
 // all the disks in parallel, including `xl.json` associated with the
 // object.
 func (xl xlObjects) deleteObject(bucket, object string) error {
+	// Get quorum of the object from its erasure info.
+	writeQuorum := xl.objectWriteQuorum(bucket, object)
+
 	// Initialize sync waitgroup.
 	var wg = &sync.WaitGroup{}
 
@@ -678,7 +732,7 @@ func (xl xlObjects) deleteObject(bucket, object string) error {
 	wg.Wait()
 
 	// Do we have write quorum?
-	if !isDiskQuorum(dErrs, xl.writeQuorum) {
+	if !isDiskQuorum(dErrs, writeQuorum) {
 		// Return errXLWriteQuorum if errors were more than allowed write quorum.
 		return traceError(errXLWriteQuorum)
 	}
@@ -688,12 +742,19 @@ func (xl xlObjects) deleteObject(bucket, object string) error {
 
 // DeleteObject - deletes an object, this call doesn't necessary reply
 // any error as it is not necessary for the handler to reply back a
-// response to the client request.
-func (xl xlObjects) DeleteObject(bucket, object string) (err error) {
+// response to the client request. On versioned buckets a delete
+// marker is placed instead.
+func (xl xlObjects) DeleteObject(ctx context.Context, bucket, object string) (err error) {
 	if err = checkDelObjArgs(bucket, object); err != nil {
 		return err
 	}
 
+	_, err = deleteObjectVersion(ctx, xl, bucket, object, "")
+	return err
+}
+
+// removeObject - removes the current version of an object.
+func (xl xlObjects) removeObject(bucket, object string) (err error) {
 	// Validate object exists.
 	if !xl.isObject(bucket, object) {
 		return traceError(ObjectNotFound{bucket, object})
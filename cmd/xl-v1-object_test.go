@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"io/ioutil"
 	"math/rand"
 	"os"
@@ -42,23 +43,23 @@ func TestRepeatPutObjectPart(t *testing.T) {
 	// cleaning up of temporary test directories
 	defer removeRoots(disks)
 
-	err = objLayer.MakeBucket("bucket1")
+	err = objLayer.MakeBucket(context.Background(), "bucket1")
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	uploadID, err := objLayer.NewMultipartUpload("bucket1", "mpartObj1", nil)
+	uploadID, err := objLayer.NewMultipartUpload(context.Background(), "bucket1", "mpartObj1", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 	fiveMBBytes := bytes.Repeat([]byte("a"), 5*humanize.MiByte)
 	md5Hex := getMD5Hash(fiveMBBytes)
-	_, err = objLayer.PutObjectPart("bucket1", "mpartObj1", uploadID, 1, 5*humanize.MiByte, bytes.NewReader(fiveMBBytes), md5Hex, "")
+	_, err = objLayer.PutObjectPart(context.Background(), "bucket1", "mpartObj1", uploadID, 1, 5*humanize.MiByte, bytes.NewReader(fiveMBBytes), md5Hex, "")
 	if err != nil {
 		t.Fatal(err)
 	}
 	// PutObjectPart should succeed even if part already exists. ref: https://github.com/minio/minio/issues/1930
-	_, err = objLayer.PutObjectPart("bucket1", "mpartObj1", uploadID, 1, 5*humanize.MiByte, bytes.NewReader(fiveMBBytes), md5Hex, "")
+	_, err = objLayer.PutObjectPart(context.Background(), "bucket1", "mpartObj1", uploadID, 1, 5*humanize.MiByte, bytes.NewReader(fiveMBBytes), md5Hex, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -86,18 +87,18 @@ func TestXLDeleteObjectBasic(t *testing.T) {
 	}
 
 	// Make bucket for Test 7 to pass
-	err = xl.MakeBucket("bucket")
+	err = xl.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Create object "obj" under bucket "bucket" for Test 7 to pass
-	_, err = xl.PutObject("bucket", "obj", int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+	_, err = xl.PutObject(context.Background(), "bucket", "obj", int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 	if err != nil {
 		t.Fatalf("XL Object upload failed: <ERROR> %s", err)
 	}
 	for i, test := range testCases {
-		actualErr := xl.DeleteObject(test.bucket, test.object)
+		actualErr := xl.DeleteObject(context.Background(), test.bucket, test.object)
 		actualErr = errorCause(actualErr)
 		if test.expectedErr != nil && actualErr != test.expectedErr {
 			t.Errorf("Test %d: Expected to fail with %s, but failed with %s", i+1, test.expectedErr, actualErr)
@@ -120,7 +121,7 @@ func TestXLDeleteObjectDiskNotFound(t *testing.T) {
 	xl := obj.(*xlObjects)
 
 	// Create "bucket"
-	err = obj.MakeBucket("bucket")
+	err = obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -128,7 +129,7 @@ func TestXLDeleteObjectDiskNotFound(t *testing.T) {
 	bucket := "bucket"
 	object := "object"
 	// Create object "obj" under bucket "bucket".
-	_, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+	_, err = obj.PutObject(context.Background(), bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -137,13 +138,13 @@ func TestXLDeleteObjectDiskNotFound(t *testing.T) {
 	for i := range xl.storageDisks[:7] {
 		xl.storageDisks[i] = newNaughtyDisk(xl.storageDisks[i].(*retryStorage), nil, errFaultyDisk)
 	}
-	err = obj.DeleteObject(bucket, object)
+	err = obj.DeleteObject(context.Background(), bucket, object)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Create "obj" under "bucket".
-	_, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+	_, err = obj.PutObject(context.Background(), bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -151,7 +152,7 @@ func TestXLDeleteObjectDiskNotFound(t *testing.T) {
 	// Remove one more disk to 'lose' quorum, by setting it to nil.
 	xl.storageDisks[7] = nil
 	xl.storageDisks[8] = nil
-	err = obj.DeleteObject(bucket, object)
+	err = obj.DeleteObject(context.Background(), bucket, object)
 	err = errorCause(err)
 	if err != toObjectErr(errXLWriteQuorum, bucket, object) {
 		t.Errorf("Expected deleteObject to fail with %v, but failed with %v", toObjectErr(errXLWriteQuorum, bucket, object), err)
@@ -170,7 +171,7 @@ func TestGetObjectNoQuorum(t *testing.T) {
 	xl := obj.(*xlObjects)
 
 	// Create "bucket"
-	err = obj.MakeBucket("bucket")
+	err = obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -178,7 +179,7 @@ func TestGetObjectNoQuorum(t *testing.T) {
 	bucket := "bucket"
 	object := "object"
 	// Create "object" under "bucket".
-	_, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+	_, err = obj.PutObject(context.Background(), bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -202,7 +203,7 @@ func TestGetObjectNoQuorum(t *testing.T) {
 			}
 		}
 		// Fetch object from store.
-		err = xl.GetObject(bucket, object, 0, int64(len("abcd")), ioutil.Discard)
+		err = xl.GetObject(context.Background(), bucket, object, 0, int64(len("abcd")), ioutil.Discard)
 		err = errorCause(err)
 		if err != toObjectErr(errXLReadQuorum, bucket, object) {
 			t.Errorf("Expected putObject to fail with %v, but failed with %v", toObjectErr(errXLWriteQuorum, bucket, object), err)
@@ -222,7 +223,7 @@ func TestPutObjectNoQuorum(t *testing.T) {
 	xl := obj.(*xlObjects)
 
 	// Create "bucket"
-	err = obj.MakeBucket("bucket")
+	err = obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -230,7 +231,7 @@ func TestPutObjectNoQuorum(t *testing.T) {
 	bucket := "bucket"
 	object := "object"
 	// Create "object" under "bucket".
-	_, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+	_, err = obj.PutObject(context.Background(), bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -253,7 +254,7 @@ func TestPutObjectNoQuorum(t *testing.T) {
 			}
 		}
 		// Upload new content to same object "object"
-		_, err = obj.PutObject(bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
+		_, err = obj.PutObject(context.Background(), bucket, object, int64(len("abcd")), bytes.NewReader([]byte("abcd")), nil, "")
 		err = errorCause(err)
 		if err != toObjectErr(errXLWriteQuorum, bucket, object) {
 			t.Errorf("Expected putObject to fail with %v, but failed with %v", toObjectErr(errXLWriteQuorum, bucket, object), err)
@@ -273,7 +274,7 @@ func TestHealing(t *testing.T) {
 	xl := obj.(*xlObjects)
 
 	// Create "bucket"
-	err = obj.MakeBucket("bucket")
+	err = obj.MakeBucket(context.Background(), "bucket")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -288,7 +289,7 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err = obj.PutObject(bucket, object, length, bytes.NewReader(data), nil, "")
+	_, err = obj.PutObject(context.Background(), bucket, object, length, bytes.NewReader(data), nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -306,7 +307,7 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = xl.HealObject(bucket, object)
+	err = xl.HealObject(context.Background(), bucket, object)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -330,7 +331,7 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = xl.HealObject(bucket, object)
+	err = xl.HealObject(context.Background(), bucket, object)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -352,7 +353,7 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 	// This would create the bucket.
-	err = xl.HealBucket(bucket)
+	err = xl.HealBucket(context.Background(), bucket)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -362,3 +363,88 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// Tests that objects are erasure coded with the parity of their storage
+// class and read with the quorum of their own.
+func TestPutObjectStorageClass(t *testing.T) {
+	obj, fsDirs, err := prepareXL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+	// Disable caching to avoid returning early and not covering other code-paths
+	xl.objCacheEnabled = false
+
+	bucket := "bucket"
+	if err = obj.MakeBucket(context.Background(), bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("abcd")
+	rrsMeta := map[string]string{amzStorageClassHeader: reducedRedundancyStorageClass}
+	if _, err = obj.PutObject(context.Background(), bucket, "standard", int64(len(data)), bytes.NewReader(data), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject(context.Background(), bucket, "rrs", int64(len(data)), bytes.NewReader(data), rrsMeta, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Parts of multipart uploads are erasure coded with the parity of
+	// the upload.
+	uploadID, err := obj.NewMultipartUpload(context.Background(), bucket, "rrs-multipart", rrsMeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md5Hex, err := obj.PutObjectPart(context.Background(), bucket, "rrs-multipart", uploadID, 1, int64(len(data)), bytes.NewReader(data), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.CompleteMultipartUpload(context.Background(), bucket, "rrs-multipart", uploadID, []completePart{{PartNumber: 1, ETag: md5Hex}}); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		object       string
+		dataBlocks   int
+		parityBlocks int
+	}{
+		// Test case - 1.
+		{"standard", 8, 8},
+		// Test case - 2.
+		{"rrs", 14, 2},
+		// Test case - 3.
+		{"rrs-multipart", 14, 2},
+	}
+	for i, testCase := range testCases {
+		xlMeta, err := readXLMeta(xl.storageDisks[0], bucket, testCase.object)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if xlMeta.Erasure.DataBlocks != testCase.dataBlocks || xlMeta.Erasure.ParityBlocks != testCase.parityBlocks {
+			t.Errorf("Test %d: Expected %d data and %d parity blocks, found %d and %d", i+1,
+				testCase.dataBlocks, testCase.parityBlocks, xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks)
+		}
+	}
+
+	// Make 3 disks offline, reduced redundancy objects can only lose 2.
+	for i := range xl.storageDisks[:3] {
+		xl.storageDisks[i] = newNaughtyDisk(xl.storageDisks[i].(*retryStorage), nil, errFaultyDisk)
+	}
+	if err = xl.GetObject(context.Background(), bucket, "standard", 0, int64(len(data)), ioutil.Discard); err != nil {
+		t.Errorf("Expected standard object to be read with 3 disks offline, but failed with %v", err)
+	}
+	err = xl.GetObject(context.Background(), bucket, "rrs", 0, int64(len(data)), ioutil.Discard)
+	if errorCause(err) != toObjectErr(errXLReadQuorum, bucket, "rrs") {
+		t.Errorf("Expected reduced redundancy object to fail with %v, but failed with %v", toObjectErr(errXLReadQuorum, bucket, "rrs"), err)
+	}
+
+	// Deletes need the write quorum of the object as well.
+	if err = obj.DeleteObject(context.Background(), bucket, "standard"); err != nil {
+		t.Errorf("Expected standard object to be deleted with 3 disks offline, but failed with %v", err)
+	}
+	err = obj.DeleteObject(context.Background(), bucket, "rrs-multipart")
+	if errorCause(err) != toObjectErr(errXLWriteQuorum, bucket, "rrs-multipart") {
+		t.Errorf("Expected reduced redundancy object to fail with %v, but failed with %v", toObjectErr(errXLWriteQuorum, bucket, "rrs-multipart"), err)
+	}
+}
@@ -99,6 +99,59 @@ func isDiskQuorum(errs []error, minQuorumCount int) bool {
 	return count >= minQuorumCount
 }
 
+// objectQuorum - returns the read and write quorum of an object erasure
+// coded into dataBlocks and parityBlocks. Any dataBlocks disks are
+// enough to read the object, writes need one more disk when data and
+// parity are split evenly so that two writes can't both succeed.
+func objectQuorum(dataBlocks, parityBlocks int) (readQuorum, writeQuorum int) {
+	readQuorum = dataBlocks
+	writeQuorum = dataBlocks
+	if dataBlocks == parityBlocks {
+		writeQuorum++
+	}
+	return readQuorum, writeQuorum
+}
+
+// getObjectQuorum - returns the read and write quorum of an object from
+// the erasure info of its latest `xl.json`, ok is false if there is no
+// valid `xl.json`.
+func getObjectQuorum(partsMetadata []xlMetaV1, errs []error) (readQuorum, writeQuorum int, ok bool) {
+	modTime, _ := commonTime(listObjectModtimes(partsMetadata, errs))
+	xlMeta, err := pickValidXLMeta(partsMetadata, modTime)
+	if err != nil || xlMeta.Erasure.DataBlocks == 0 {
+		return 0, 0, false
+	}
+	readQuorum, writeQuorum = objectQuorum(xlMeta.Erasure.DataBlocks, xlMeta.Erasure.ParityBlocks)
+	return readQuorum, writeQuorum, true
+}
+
+// objectQuorumFromMeta - returns the read and write quorum of an object
+// from the erasure info of its latest `xl.json`. Objects without a
+// valid `xl.json` get the quorum of the STANDARD storage class.
+func (xl xlObjects) objectQuorumFromMeta(partsMetadata []xlMetaV1, errs []error) (readQuorum, writeQuorum int) {
+	if readQuorum, writeQuorum, ok := getObjectQuorum(partsMetadata, errs); ok {
+		return readQuorum, writeQuorum
+	}
+	return objectQuorum(xl.dataBlocks, xl.parityBlocks)
+}
+
+// objectWriteQuorum - returns the write quorum of an existing object
+// from the erasure info of its `xl.json` on all disks.
+func (xl xlObjects) objectWriteQuorum(bucket, object string) int {
+	partsMetadata, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+	_, writeQuorum := xl.objectQuorumFromMeta(partsMetadata, errs)
+	return writeQuorum
+}
+
+// storageClassBlocks - returns the data and parity blocks of a new
+// object of the storage class saved in its metadata.
+func (xl xlObjects) storageClassBlocks(metadata map[string]string) (dataBlocks, parityBlocks int) {
+	if getStorageClass(metadata) == reducedRedundancyStorageClass {
+		return len(xl.storageDisks) - xl.rrsParityBlocks, xl.rrsParityBlocks
+	}
+	return xl.dataBlocks, xl.parityBlocks
+}
+
 // Similar to 'len(slice)' but returns  the actual elements count
 // skipping the unallocated elements.
 func diskCount(disks []StorageAPI) int {
@@ -243,16 +296,19 @@ func xlMetaV1UnmarshalJSON(xlMetaBuf []byte) (xlMetaV1, error) {
 }
 
 // read xl.json from the given disk, parse and return xlV1MetaV1.Parts.
-func readXLMetaParts(disk StorageAPI, bucket string, object string) ([]objectPartInfo, error) {
+func readXLMetaParts(disk StorageAPI, bucket string, object string) ([]objectPartInfo, map[string]string, error) {
 	// Reads entire `xl.json`.
 	xlMetaBuf, err := disk.ReadAll(bucket, path.Join(object, xlMetaJSONFile))
 	if err != nil {
-		return nil, traceError(err)
+		return nil, nil, traceError(err)
 	}
 	// obtain xlMetaV1{}.Partsusing `github.com/tidwall/gjson`.
 	xlMetaParts := parseXLParts(xlMetaBuf)
 
-	return xlMetaParts, nil
+	// obtain xlMetaV1{}.Meta using `github.com/tidwall/gjson`.
+	xlMetaMap := parseXLMetaMap(xlMetaBuf)
+
+	return xlMetaParts, xlMetaMap, nil
 }
 
 // read xl.json from the given disk and parse xlV1Meta.Stat and xlV1Meta.Meta using gjson.
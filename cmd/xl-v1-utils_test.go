@@ -95,6 +95,43 @@ func TestReduceErrs(t *testing.T) {
 	}
 }
 
+// Tests quorum of objects from their erasure info.
+func TestObjectQuorumFromMeta(t *testing.T) {
+	xl := xlObjects{dataBlocks: 8, parityBlocks: 8}
+	newMetaArr := func(dataBlocks, parityBlocks int) []xlMetaV1 {
+		xlMeta := newXLMetaV1("object", dataBlocks, parityBlocks)
+		xlMeta.Stat.ModTime = time.Unix(1, 0).UTC()
+		metaArr := make([]xlMetaV1, dataBlocks+parityBlocks)
+		for i := range metaArr {
+			metaArr[i] = xlMeta
+		}
+		return metaArr
+	}
+	testCases := []struct {
+		metaArr     []xlMetaV1
+		errs        []error
+		readQuorum  int
+		writeQuorum int
+	}{
+		// Test case - 1.
+		// Standard storage class, evenly split.
+		{newMetaArr(8, 8), make([]error, 16), 8, 9},
+		// Test case - 2.
+		// Reduced redundancy storage class.
+		{newMetaArr(14, 2), make([]error, 16), 14, 14},
+		// Test case - 3.
+		// No valid `xl.json`, defaults to the standard storage class.
+		{make([]xlMetaV1, 16), make([]error, 16), 8, 9},
+	}
+	for i, testCase := range testCases {
+		readQuorum, writeQuorum := xl.objectQuorumFromMeta(testCase.metaArr, testCase.errs)
+		if readQuorum != testCase.readQuorum || writeQuorum != testCase.writeQuorum {
+			t.Errorf("Test %d: Expected quorum %d/%d, found %d/%d", i+1,
+				testCase.readQuorum, testCase.writeQuorum, readQuorum, writeQuorum)
+		}
+	}
+}
+
 // TestHashOrder - test order of ints in array
 func TestHashOrder(t *testing.T) {
 	testCases := []struct {
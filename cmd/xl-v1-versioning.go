@@ -0,0 +1,266 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"path"
+	"time"
+)
+
+// GetObjectVersion - reads a version of an object, an empty versionID
+// reads the current version.
+func (xl xlObjects) GetObjectVersion(ctx context.Context, bucket, object, versionID string, startOffset int64, length int64, writer io.Writer) error {
+	if err := checkGetObjArgs(bucket, object); err != nil {
+		return err
+	}
+	return getObjectVersion(ctx, xl, bucket, object, versionID, startOffset, length, writer)
+}
+
+// GetObjectVersionInfo - reads metadata of a version of an object.
+func (xl xlObjects) GetObjectVersionInfo(ctx context.Context, bucket, object, versionID string) (ObjectInfo, error) {
+	if err := checkGetObjArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+	return getObjectVersionInfo(xl, bucket, object, versionID)
+}
+
+// DeleteObjectVersion - removes a version of an object for good, an
+// empty versionID behaves like DeleteObject.
+func (xl xlObjects) DeleteObjectVersion(ctx context.Context, bucket, object, versionID string) (ObjectInfo, error) {
+	if err := checkDelObjArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+	return deleteObjectVersion(ctx, xl, bucket, object, versionID)
+}
+
+// UpdateObjectMetadata - updates metadata entries of a version of an
+// object in place, an empty versionID updates the current version.
+func (xl xlObjects) UpdateObjectMetadata(ctx context.Context, bucket, object, versionID string, updates map[string]string) (ObjectInfo, error) {
+	if err := checkGetObjArgs(bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+	return updateObjectVersionMetadata(xl, bucket, object, versionID, updates)
+}
+
+// ListObjectVersions - lists all versions of objects in a bucket.
+func (xl xlObjects) ListObjectVersions(ctx context.Context, bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int) (ListObjectVersionsInfo, error) {
+	return listObjectVersions(ctx, xl, bucket, prefix, keyMarker, versionIDMarker, delimiter, maxKeys)
+}
+
+// archiveObject - moves the current version of an object to the
+// versions area.
+func (xl xlObjects) archiveObject(bucket, object, versionID string) error {
+	versionPath := objectVersionPath(bucket, object, versionID)
+
+	// Clear any stale copy left at the destination.
+	if err := xl.deleteObject(minioMetaBucket, versionPath); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	writeQuorum := xl.objectWriteQuorum(bucket, object)
+	if err := renameObject(xl.storageDisks, bucket, object, minioMetaBucket, versionPath, writeQuorum); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	if xl.objCacheEnabled {
+		// Delete from the cache.
+		xl.objCache.Delete(pathJoin(bucket, object))
+	}
+	return nil
+}
+
+// restoreVersion - moves a saved version back as the current version
+// of an object.
+func (xl xlObjects) restoreVersion(bucket, object, versionID string) error {
+	versionPath := objectVersionPath(bucket, object, versionID)
+	writeQuorum := xl.objectWriteQuorum(minioMetaBucket, versionPath)
+	if err := renameObject(xl.storageDisks, minioMetaBucket, versionPath, bucket, object, writeQuorum); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	return nil
+}
+
+// putDeleteMarker - saves a delete marker, delete markers are objects
+// without any parts.
+func (xl xlObjects) putDeleteMarker(bucket, object, versionID string, modTime time.Time) error {
+	xlMeta := newXLMetaV1(object, xl.dataBlocks, xl.parityBlocks)
+	xlMeta.Stat.ModTime = modTime
+	xlMeta.Meta = map[string]string{
+		versionIDMetaKey:    versionID,
+		deleteMarkerMetaKey: "true",
+	}
+
+	tempObj := mustGetUUID()
+	defer xl.deleteObject(minioMetaTmpBucket, tempObj)
+
+	if err := writeSameXLMetadata(xl.storageDisks, minioMetaTmpBucket, tempObj, xlMeta, xl.writeQuorum, xl.readQuorum); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	versionPath := objectVersionPath(bucket, object, versionID)
+	if err := renameObject(xl.storageDisks, minioMetaTmpBucket, tempObj, minioMetaBucket, versionPath, xl.writeQuorum); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	return nil
+}
+
+// getVersionInfo - reads metadata of a saved version.
+func (xl xlObjects) getVersionInfo(bucket, object, versionID string) (ObjectInfo, error) {
+	versionPath := objectVersionPath(bucket, object, versionID)
+	objInfo, err := xl.getObjectInfo(minioMetaBucket, versionPath)
+	if err != nil {
+		return ObjectInfo{}, toVersionErr(err, bucket, object, versionID)
+	}
+	objInfo.Bucket = bucket
+	objInfo.Name = object
+	objInfo.VersionID = versionID
+	return objInfo, nil
+}
+
+// getVersion - reads data of a saved version.
+func (xl xlObjects) getVersion(ctx context.Context, bucket, object, versionID string, startOffset int64, length int64, writer io.Writer) error {
+	versionPath := objectVersionPath(bucket, object, versionID)
+	if err := xl.getObject(ctx, minioMetaBucket, versionPath, startOffset, length, writer); err != nil {
+		return toVersionErr(err, bucket, object, versionID)
+	}
+	return nil
+}
+
+// deleteVersion - removes a saved version.
+func (xl xlObjects) deleteVersion(bucket, object, versionID string) error {
+	versionPath := objectVersionPath(bucket, object, versionID)
+	if !xl.isObject(minioMetaBucket, versionPath) {
+		return traceError(VersionNotFound{Bucket: bucket, Object: object, VersionID: versionID})
+	}
+	if err := xl.deleteObject(minioMetaBucket, versionPath); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	if xl.objCacheEnabled {
+		// Delete from the cache.
+		xl.objCache.Delete(pathJoin(minioMetaBucket, versionPath))
+	}
+	return nil
+}
+
+// listVersions - lists saved versions of objects starting with prefix
+// and listed after marker, of at most maxObjects objects unless zero.
+func (xl xlObjects) listVersions(bucket, prefix, marker string, maxObjects int) (versions []ObjectInfo, isTruncated bool, err error) {
+	isLeaf := xl.isObject
+	listDir := listDirFactory(isLeaf, xlTreeWalkIgnoredErrs, xl.getLoadBalancedDisks()...)
+	isTruncated, err = walkVersions(bucket, prefix, marker, maxObjects, listDir, isLeaf, func(object, versionID string) error {
+		objInfo, vErr := xl.getVersionInfo(bucket, object, versionID)
+		if vErr != nil {
+			return vErr
+		}
+		versions = append(versions, objInfo)
+		return nil
+	})
+	return versions, isTruncated, err
+}
+
+// hasVersions - returns true if saved versions of any object of the
+// bucket exist.
+func (xl xlObjects) hasVersions(bucket string) bool {
+	for _, disk := range xl.getLoadBalancedDisks() {
+		if disk == nil {
+			continue
+		}
+		entries, err := disk.ListDir(minioMetaBucket, path.Join(versionsPrefix, bucket))
+		if err == nil {
+			return len(entries) > 0
+		}
+		// Ignore for file not found, disk not found or faulty disk.
+		if isErrIgnored(err, xlTreeWalkIgnoredErrs...) {
+			continue
+		}
+		errorIf(err, "Unable to list versions of bucket %s", bucket)
+	}
+	return false
+}
+
+// updateMetadata - rewrites `xl.json` of an object with the updated
+// metadata entries, the object data is left untouched.
+func (xl xlObjects) updateMetadata(bucket, object string, updates map[string]string) error {
+	// Read metadata associated with the object from all disks.
+	metaArr, errs := readAllXLMetadata(xl.storageDisks, bucket, object)
+
+	// Get quorum of the object from its erasure info.
+	readQuorum, writeQuorum := xl.objectQuorumFromMeta(metaArr, errs)
+
+	// Do we have read quorum?
+	if !isDiskQuorum(errs, readQuorum) {
+		return traceError(InsufficientReadQuorum{}, errs...)
+	}
+
+	if reducedErr := reduceReadQuorumErrs(errs, objectOpIgnoredErrs, readQuorum); reducedErr != nil {
+		return reducedErr
+	}
+
+	// List all online disks.
+	onlineDisks, modTime := listOnlineDisks(xl.storageDisks, metaArr, errs)
+
+	// Pick latest valid metadata.
+	xlMeta, err := pickValidXLMeta(metaArr, modTime)
+	if err != nil {
+		return err
+	}
+
+	// Update `xl.json` content on each disk, each disk keeps its own
+	// erasure checksums.
+	for index := range metaArr {
+		if onlineDisks[index] == nil {
+			continue
+		}
+		if metaArr[index].Meta == nil {
+			metaArr[index].Meta = make(map[string]string)
+		}
+		applyMetadataUpdates(metaArr[index].Meta, updates)
+	}
+
+	// Reorder online disks and metadata based on erasure distribution order.
+	onlineDisks = getOrderedDisks(xlMeta.Erasure.Distribution, onlineDisks)
+	partsMetadata := getOrderedPartsMetadata(xlMeta.Erasure.Distribution, metaArr)
+
+	tempObj := mustGetUUID()
+
+	// Write unique `xl.json` for each disk.
+	if err = writeUniqueXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, partsMetadata, writeQuorum); err != nil {
+		return err
+	}
+	// Rename atomically `xl.json` from tmp location to destination for each disk.
+	return renameXLMetadata(onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, writeQuorum)
+}
+
+// updateObjectMetadata - updates metadata entries of the current
+// version of an object.
+func (xl xlObjects) updateObjectMetadata(bucket, object string, updates map[string]string) error {
+	if err := xl.updateMetadata(bucket, object, updates); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	return nil
+}
+
+// updateVersionMetadata - updates metadata entries of a saved version.
+func (xl xlObjects) updateVersionMetadata(bucket, object, versionID string, updates map[string]string) error {
+	versionPath := objectVersionPath(bucket, object, versionID)
+	if err := xl.updateMetadata(minioMetaBucket, versionPath, updates); err != nil {
+		return toVersionErr(err, bucket, object, versionID)
+	}
+	return nil
+}
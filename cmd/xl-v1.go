@@ -58,11 +58,14 @@ const (
 type xlObjects struct {
 	mutex        *sync.Mutex
 	storageDisks []StorageAPI // Collection of initialized backend disks.
-	dataBlocks   int          // dataBlocks count caculated for erasure.
-	parityBlocks int          // parityBlocks count calculated for erasure.
+	dataBlocks   int          // dataBlocks count of the STANDARD storage class.
+	parityBlocks int          // parityBlocks count of the STANDARD storage class.
 	readQuorum   int          // readQuorum minimum required disks to read data.
 	writeQuorum  int          // writeQuorum minimum required disks to write data.
 
+	// parityBlocks count of the REDUCED_REDUNDANCY storage class.
+	rrsParityBlocks int
+
 	// ListObjects pool management.
 	listPool *treeWalkPool
 
@@ -86,6 +89,14 @@ func newXLObjectLayer(storageDisks []StorageAPI) (ObjectLayer, error) {
 	err = initBucketPolicies(objAPI)
 	fatalIf(err, "Unable to load all bucket policies.")
 
+	// Initialize and load bucket configurations.
+	err = initBucketConfigs(objAPI)
+	fatalIf(err, "Unable to load all bucket configurations.")
+
+	// Initialize IAM users and policies.
+	err = initIAMSys(objAPI)
+	fatalIf(err, "Unable to load IAM users and policies.")
+
 	// Initialize a new event notifier.
 	err = initEventNotifier(objAPI)
 	fatalIf(err, "Unable to initialize event notification.")
@@ -109,8 +120,17 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 		return nil, fmt.Errorf("Unable to recognize backend format, %s", err)
 	}
 
-	// Calculate data and parity blocks.
-	dataBlocks, parityBlocks := len(newStorageDisks)/2, len(newStorageDisks)/2
+	// Calculate data and parity blocks of the storage classes, object
+	// layers initialized without a server config use the defaults.
+	var scConfig storageClassConfig
+	if serverConfig != nil {
+		scConfig = serverConfig.GetStorageClass()
+	}
+	standardParity, rrsParity, err := getStorageClassParity(scConfig, len(newStorageDisks))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid storage class configuration, %s", err)
+	}
+	dataBlocks, parityBlocks := len(newStorageDisks)-standardParity, standardParity
 
 	// Initialize list pool.
 	listPool := newTreeWalkPool(globalLookupTimeout)
@@ -125,6 +145,8 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 		dataBlocks:   dataBlocks,
 		parityBlocks: parityBlocks,
 		listPool:     listPool,
+
+		rrsParityBlocks: rrsParity,
 	}
 
 	// Object cache is enabled when _MINIO_CACHE env is missing.
@@ -147,7 +169,8 @@ func newXLObjects(storageDisks []StorageAPI) (ObjectLayer, error) {
 	}
 
 	// Figure out read and write quorum based on number of storage disks.
-	// READ and WRITE quorum is always set to (N/2) number of disks.
+	// READ and WRITE quorum of buckets and metadata is always set to
+	// (N/2) number of disks, objects have a quorum of their own.
 	xl.readQuorum = readQuorum
 	xl.writeQuorum = writeQuorum
 
@@ -223,8 +246,9 @@ func sortValidDisksInfo(disksInfo []disk.Info) []disk.Info {
 	return validDisksInfo
 }
 
-// Get an aggregated storage info across all disks.
-func getStorageInfo(disks []StorageAPI) StorageInfo {
+// Get an aggregated storage info across all disks, capacity is given
+// for objects erasure coded into dataBlocks.
+func getStorageInfo(disks []StorageAPI, dataBlocks int) StorageInfo {
 	disksInfo, onlineDisks, offlineDisks := getDisksInfo(disks)
 
 	// Sort so that the first element is the smallest.
@@ -238,10 +262,11 @@ func getStorageInfo(disks []StorageAPI) StorageInfo {
 
 	// Return calculated storage info, choose the lowest Total and
 	// Free as the total aggregated values. Total capacity is always
-	// the multiple of smallest disk among the disk list.
+	// the multiple of smallest disk among the disk list, less the
+	// share of parity.
 	storageInfo := StorageInfo{
-		Total: validDisksInfo[0].Total * int64(onlineDisks) / 2,
-		Free:  validDisksInfo[0].Free * int64(onlineDisks) / 2,
+		Total: validDisksInfo[0].Total * int64(onlineDisks) * int64(dataBlocks) / int64(len(disks)),
+		Free:  validDisksInfo[0].Free * int64(onlineDisks) * int64(dataBlocks) / int64(len(disks)),
 	}
 
 	storageInfo.Backend.Type = XL
@@ -252,7 +277,7 @@ func getStorageInfo(disks []StorageAPI) StorageInfo {
 
 // StorageInfo - returns underlying storage statistics.
 func (xl xlObjects) StorageInfo() StorageInfo {
-	storageInfo := getStorageInfo(xl.storageDisks)
+	storageInfo := getStorageInfo(xl.storageDisks, xl.dataBlocks)
 	storageInfo.Backend.ReadQuorum = xl.readQuorum
 	storageInfo.Backend.WriteQuorum = xl.writeQuorum
 	return storageInfo
@@ -170,8 +170,36 @@ func TestNewXL(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Unable to format disks for erasure, %s", err)
 	}
-	_, err = newXLObjects(formattedDisks)
+	objLayer, err := newXLObjects(formattedDisks)
 	if err != nil {
 		t.Fatalf("Unable to initialize erasure, %s", err)
 	}
+	xl := objLayer.(*xlObjects)
+	if xl.dataBlocks != 8 || xl.parityBlocks != 8 || xl.rrsParityBlocks != 2 {
+		t.Fatalf("Unexpected default parity %d/%d/%d", xl.dataBlocks, xl.parityBlocks, xl.rrsParityBlocks)
+	}
+
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	defer removeAll(rootPath)
+
+	// Parity of the storage classes is set by the server config.
+	serverConfig.SetStorageClass(storageClassConfig{Standard: storageClass{Parity: 4}, RRS: storageClass{Parity: 3}})
+	objLayer, err = newXLObjects(formattedDisks)
+	if err != nil {
+		t.Fatalf("Unable to initialize erasure, %s", err)
+	}
+	xl = objLayer.(*xlObjects)
+	if xl.dataBlocks != 12 || xl.parityBlocks != 4 || xl.rrsParityBlocks != 3 {
+		t.Fatalf("Unexpected configured parity %d/%d/%d", xl.dataBlocks, xl.parityBlocks, xl.rrsParityBlocks)
+	}
+
+	// Invalid parity is rejected.
+	serverConfig.SetStorageClass(storageClassConfig{Standard: storageClass{Parity: 10}})
+	if _, err = newXLObjects(formattedDisks); err == nil {
+		t.Fatal("Expected invalid storage class parity to fail")
+	}
+	serverConfig.SetStorageClass(storageClassConfig{})
 }
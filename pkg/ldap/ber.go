@@ -0,0 +1,248 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import (
+	"errors"
+	"io"
+)
+
+// BER classes.
+const (
+	ClassUniversal   = 0x00
+	ClassApplication = 0x40
+	ClassContext     = 0x80
+)
+
+// BER universal tags used by LDAP.
+const (
+	TagBoolean     = 0x01
+	TagInteger     = 0x02
+	TagOctetString = 0x04
+	TagNull        = 0x05
+	TagEnumerated  = 0x0a
+	TagSequence    = 0x10
+	TagSet         = 0x11
+)
+
+// Largest packet accepted, LDAP messages exchanged by the client are
+// small.
+const maxPacketSize = 16 << 20
+
+// Deepest nesting of constructed packets accepted, LDAP messages are
+// only a few levels deep.
+const maxPacketDepth = 32
+
+var errMalformedPacket = errors.New("ldap: malformed BER packet")
+
+// Packet - a BER encoded element, either primitive with a value or
+// constructed with children.
+type Packet struct {
+	Class       byte
+	Constructed bool
+	Tag         byte
+	Value       []byte
+	Children    []*Packet
+}
+
+// NewPrimitive - returns a primitive packet.
+func NewPrimitive(class, tag byte, value []byte) *Packet {
+	return &Packet{Class: class, Tag: tag, Value: value}
+}
+
+// NewConstructed - returns a constructed packet.
+func NewConstructed(class, tag byte, children ...*Packet) *Packet {
+	return &Packet{Class: class, Constructed: true, Tag: tag, Children: children}
+}
+
+// NewString - returns an octet string.
+func NewString(s string) *Packet {
+	return NewPrimitive(ClassUniversal, TagOctetString, []byte(s))
+}
+
+// NewInteger - returns an integer with the given universal tag,
+// TagInteger or TagEnumerated.
+func NewInteger(tag byte, n int64) *Packet {
+	var value []byte
+	for {
+		value = append([]byte{byte(n)}, value...)
+		n >>= 8
+		// Stop once the remaining bits are the sign extension of
+		// the most significant byte written.
+		if (n == 0 && value[0]&0x80 == 0) || (n == -1 && value[0]&0x80 != 0) {
+			break
+		}
+	}
+	return NewPrimitive(ClassUniversal, tag, value)
+}
+
+// NewBoolean - returns a boolean.
+func NewBoolean(b bool) *Packet {
+	if b {
+		return NewPrimitive(ClassUniversal, TagBoolean, []byte{0xff})
+	}
+	return NewPrimitive(ClassUniversal, TagBoolean, []byte{0x00})
+}
+
+// NewSequence - returns a universal sequence.
+func NewSequence(children ...*Packet) *Packet {
+	return NewConstructed(ClassUniversal, TagSequence, children...)
+}
+
+// Int - returns the value of an integer or enumerated packet.
+func (p *Packet) Int() int64 {
+	var n int64
+	for i, b := range p.Value {
+		if i == 0 && b&0x80 != 0 {
+			n = -1
+		}
+		n = n<<8 | int64(b)
+	}
+	return n
+}
+
+// String - returns the value of a primitive packet as a string.
+func (p *Packet) String() string {
+	return string(p.Value)
+}
+
+// Is - checks the class and tag of the packet.
+func (p *Packet) Is(class, tag byte) bool {
+	return p.Class == class && p.Tag == tag
+}
+
+// Bytes - returns the BER encoding of the packet.
+func (p *Packet) Bytes() []byte {
+	value := p.Value
+	if p.Constructed {
+		value = nil
+		for _, child := range p.Children {
+			value = append(value, child.Bytes()...)
+		}
+	}
+
+	identifier := p.Class | p.Tag
+	if p.Constructed {
+		identifier |= 0x20
+	}
+	buf := []byte{identifier}
+	if len(value) < 0x80 {
+		buf = append(buf, byte(len(value)))
+	} else {
+		var length []byte
+		for n := len(value); n > 0; n >>= 8 {
+			length = append([]byte{byte(n)}, length...)
+		}
+		buf = append(buf, 0x80|byte(len(length)))
+		buf = append(buf, length...)
+	}
+	return append(buf, value...)
+}
+
+// ReadPacket - reads a single BER encoded packet.
+func ReadPacket(r io.Reader) (*Packet, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if header[0]&0x1f == 0x1f {
+		// High tag numbers are not used by LDAP.
+		return nil, errMalformedPacket
+	}
+
+	length := int(header[1])
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if n == 0 || n > 4 {
+			return nil, errMalformedPacket
+		}
+		lengthBytes := make([]byte, n)
+		if _, err := io.ReadFull(r, lengthBytes); err != nil {
+			return nil, err
+		}
+		length = 0
+		for _, b := range lengthBytes {
+			length = length<<8 | int(b)
+		}
+	}
+	// Four length bytes overflow a 32 bit int.
+	if length < 0 || length > maxPacketSize {
+		return nil, errMalformedPacket
+	}
+
+	value := make([]byte, length)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return nil, err
+	}
+	return decodePacket(header[0], value, 0)
+}
+
+// decodePacket - decodes a packet given its identifier and value,
+// depth is the number of constructed packets it is nested in.
+func decodePacket(identifier byte, value []byte, depth int) (*Packet, error) {
+	p := &Packet{
+		Class:       identifier & 0xc0,
+		Constructed: identifier&0x20 != 0,
+		Tag:         identifier & 0x1f,
+	}
+	if !p.Constructed {
+		p.Value = value
+		return p, nil
+	}
+	if depth >= maxPacketDepth {
+		return nil, errMalformedPacket
+	}
+	for len(value) > 0 {
+		child, rest, err := splitPacket(value, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		p.Children = append(p.Children, child)
+		value = rest
+	}
+	return p, nil
+}
+
+// splitPacket - decodes the first packet of buf and returns the
+// remaining bytes.
+func splitPacket(buf []byte, depth int) (*Packet, []byte, error) {
+	if len(buf) < 2 || buf[0]&0x1f == 0x1f {
+		return nil, nil, errMalformedPacket
+	}
+	identifier := buf[0]
+	length := int(buf[1])
+	buf = buf[2:]
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if n == 0 || n > 4 || len(buf) < n {
+			return nil, nil, errMalformedPacket
+		}
+		length = 0
+		for _, b := range buf[:n] {
+			length = length<<8 | int(b)
+		}
+		buf = buf[n:]
+	}
+	if length < 0 || length > len(buf) {
+		return nil, nil, errMalformedPacket
+	}
+	p, err := decodePacket(identifier, buf[:length], depth)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, buf[length:], nil
+}
@@ -0,0 +1,139 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIntegerEncoding(t *testing.T) {
+	testCases := []struct {
+		n        int64
+		expected []byte
+	}{
+		{0, []byte{0x02, 0x01, 0x00}},
+		{3, []byte{0x02, 0x01, 0x03}},
+		{127, []byte{0x02, 0x01, 0x7f}},
+		{128, []byte{0x02, 0x02, 0x00, 0x80}},
+		{256, []byte{0x02, 0x02, 0x01, 0x00}},
+		{-1, []byte{0x02, 0x01, 0xff}},
+		{-128, []byte{0x02, 0x01, 0x80}},
+		{-129, []byte{0x02, 0x02, 0xff, 0x7f}},
+	}
+	for i, testCase := range testCases {
+		p := NewInteger(TagInteger, testCase.n)
+		if encoded := p.Bytes(); !bytes.Equal(encoded, testCase.expected) {
+			t.Errorf("Test %d: Expected %x, got %x", i+1, testCase.expected, encoded)
+		}
+		if n := p.Int(); n != testCase.n {
+			t.Errorf("Test %d: Expected %d, got %d", i+1, testCase.n, n)
+		}
+	}
+}
+
+func TestPacketRoundTrip(t *testing.T) {
+	longValue := strings.Repeat("x", 300)
+	p := NewSequence(
+		NewInteger(TagInteger, 1),
+		NewConstructed(ClassApplication, AppBindRequest,
+			NewInteger(TagInteger, 3),
+			NewString("uid=user,dc=example,dc=com"),
+			NewPrimitive(ClassContext, 0, []byte(longValue)),
+		),
+		NewBoolean(true),
+	)
+	decoded, err := ReadPacket(bytes.NewReader(p.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Bytes(), p.Bytes()) {
+		t.Fatalf("Expected %x, got %x", p.Bytes(), decoded.Bytes())
+	}
+	bind := decoded.Children[1]
+	if !bind.Is(ClassApplication, AppBindRequest) || !bind.Constructed {
+		t.Errorf("Unexpected bind request %+v", bind)
+	}
+	if bind.Children[2].String() != longValue {
+		t.Errorf("Unexpected long value of length %d", len(bind.Children[2].Value))
+	}
+}
+
+func TestReadMalformedPacket(t *testing.T) {
+	// Sequences nested deeper than any LDAP message.
+	deep := NewString("leaf")
+	for i := 0; i < maxPacketDepth+1; i++ {
+		deep = NewSequence(deep)
+	}
+
+	testCases := [][]byte{
+		// Truncated value.
+		{0x04, 0x05, 'a', 'b'},
+		// Truncated identifier and length.
+		{0x04},
+		// Truncated long form length.
+		{0x04, 0x82, 0x01},
+		// Truncated long form length of a child.
+		{0x30, 0x02, 0x04, 0x82},
+		// High tag number.
+		{0x1f, 0x01, 0x00},
+		// High tag number of a child.
+		{0x30, 0x02, 0x1f, 0x00},
+		// Indefinite length.
+		{0x30, 0x80, 0x00, 0x00},
+		// Indefinite length of a child.
+		{0x30, 0x04, 0x30, 0x80, 0x00, 0x00},
+		// Length prefix longer than four bytes.
+		{0x04, 0x85, 0x00, 0x00, 0x00, 0x00, 0x01, 'a'},
+		// Length prefix of a child longer than four bytes.
+		{0x30, 0x08, 0x04, 0x85, 0x00, 0x00, 0x00, 0x00, 0x01, 'a'},
+		// Length beyond the largest packet.
+		{0x04, 0x84, 0x01, 0x00, 0x00, 0x01},
+		// Largest length prefix, negative in a 32 bit int.
+		{0x04, 0x84, 0xff, 0xff, 0xff, 0xff},
+		// Largest length prefix of a child.
+		{0x30, 0x06, 0x04, 0x84, 0xff, 0xff, 0xff, 0xff},
+		// Child overflowing its parent.
+		{0x30, 0x03, 0x04, 0x05, 'a'},
+		// Nesting too deep.
+		deep.Bytes(),
+	}
+	for i, testCase := range testCases {
+		if _, err := ReadPacket(bytes.NewReader(testCase)); err == nil {
+			t.Errorf("Test %d: Expected an error", i+1)
+		}
+	}
+}
+
+func TestReadTruncatedPacket(t *testing.T) {
+	p := NewSequence(
+		NewInteger(TagInteger, 1),
+		NewConstructed(ClassApplication, AppBindRequest,
+			NewInteger(TagInteger, 3),
+			NewString("uid=user,dc=example,dc=com"),
+			NewPrimitive(ClassContext, 0, []byte(strings.Repeat("x", 300))),
+		),
+	)
+	encoded := p.Bytes()
+	for n := 0; n < len(encoded); n++ {
+		if _, err := ReadPacket(bytes.NewReader(encoded[:n])); err == nil {
+			t.Errorf("Expected an error reading the first %d of %d bytes", n, len(encoded))
+		}
+	}
+}
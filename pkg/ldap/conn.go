@@ -0,0 +1,329 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ldap implements the subset of the LDAP v3 protocol (RFC 4511)
+// needed to authenticate users, simple binds and searches optionally
+// secured by TLS or StartTLS.
+package ldap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// Protocol operations, application specific tags of LDAPMessage.
+const (
+	AppBindRequest       = 0
+	AppBindResponse      = 1
+	AppUnbindRequest     = 2
+	AppSearchRequest     = 3
+	AppSearchResultEntry = 4
+	AppSearchResultDone  = 5
+	AppSearchResultRef   = 19
+	AppExtendedRequest   = 23
+	AppExtendedResponse  = 24
+)
+
+// Result codes.
+const (
+	ResultSuccess            = 0
+	ResultProtocolError      = 2
+	ResultNoSuchObject       = 32
+	ResultInvalidCredentials = 49
+	ResultUnwillingToPerform = 53
+)
+
+// Search scopes.
+const (
+	ScopeBaseObject   = 0
+	ScopeSingleLevel  = 1
+	ScopeWholeSubtree = 2
+)
+
+// StartTLS extended operation.
+const oidStartTLS = "1.3.6.1.4.1.1466.20037"
+
+// Default timeout of a connection attempt and of each operation.
+const defaultTimeout = 30 * time.Second
+
+// Error - a result code other than success returned by the server.
+type Error struct {
+	ResultCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("ldap: result code %d: %s", e.ResultCode, e.Message)
+}
+
+// IsErrorWithCode - checks if err is an LDAP error with the result code.
+func IsErrorWithCode(err error, resultCode int) bool {
+	e, ok := err.(*Error)
+	return ok && e.ResultCode == resultCode
+}
+
+// Entry - an entry returned by a search.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// GetAttributeValues - returns the values of an attribute, attribute
+// names are case insensitive.
+func (e *Entry) GetAttributeValues(attr string) []string {
+	for name, values := range e.Attributes {
+		if strings.EqualFold(name, attr) {
+			return values
+		}
+	}
+	return nil
+}
+
+// Conn - a connection to an LDAP server, operations are synchronous.
+type Conn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	messageID int64
+	timeout   time.Duration
+}
+
+// Dial - connects to an LDAP server, tlsConfig is used for `ldaps://`
+// URLs and ignored for `ldap://` URLs.
+func Dial(serverURL string, tlsConfig *tls.Config) (*Conn, error) {
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: defaultTimeout}
+	switch {
+	case strings.HasPrefix(serverURL, "ldaps://"):
+		conn, err = tls.DialWithDialer(dialer, "tcp", hostPort(strings.TrimPrefix(serverURL, "ldaps://"), "636"), tlsConfig)
+	case strings.HasPrefix(serverURL, "ldap://"):
+		conn, err = dialer.Dial("tcp", hostPort(strings.TrimPrefix(serverURL, "ldap://"), "389"))
+	default:
+		return nil, fmt.Errorf("ldap: unsupported server URL %q", serverURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewConn(conn), nil
+}
+
+// hostPort - adds the default port to an address without one.
+func hostPort(addr, defaultPort string) string {
+	addr = strings.TrimSuffix(addr, "/")
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return net.JoinHostPort(addr, defaultPort)
+	}
+	return addr
+}
+
+// NewConn - returns an LDAP connection over an established connection.
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		timeout: defaultTimeout,
+	}
+}
+
+// Close - unbinds and closes the connection.
+func (c *Conn) Close() error {
+	c.messageID++
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	c.conn.Write(NewSequence(
+		NewInteger(TagInteger, c.messageID),
+		NewPrimitive(ClassApplication, AppUnbindRequest, nil),
+	).Bytes())
+	return c.conn.Close()
+}
+
+// StartTLS - upgrades the connection to TLS.
+func (c *Conn) StartTLS(tlsConfig *tls.Config) error {
+	request := NewConstructed(ClassApplication, AppExtendedRequest,
+		NewPrimitive(ClassContext, 0, []byte(oidStartTLS)),
+	)
+	responses, err := c.do(request, AppExtendedResponse)
+	if err != nil {
+		return err
+	}
+	if err = resultError(responses[len(responses)-1]); err != nil {
+		return err
+	}
+
+	tlsConn := tls.Client(c.conn, tlsConfig)
+	tlsConn.SetDeadline(time.Now().Add(c.timeout))
+	if err = tlsConn.Handshake(); err != nil {
+		return err
+	}
+	c.conn = tlsConn
+	c.reader = bufio.NewReader(tlsConn)
+	return nil
+}
+
+// Bind - authenticates with a simple bind. An empty password would be
+// an unauthenticated bind (RFC 4513) which succeeds for any DN, it is
+// rejected.
+func (c *Conn) Bind(dn, password string) error {
+	if password == "" {
+		return &Error{ResultCode: ResultUnwillingToPerform, Message: "empty password"}
+	}
+	request := NewConstructed(ClassApplication, AppBindRequest,
+		NewInteger(TagInteger, 3),
+		NewString(dn),
+		NewPrimitive(ClassContext, 0, []byte(password)),
+	)
+	responses, err := c.do(request, AppBindResponse)
+	if err != nil {
+		return err
+	}
+	return resultError(responses[len(responses)-1])
+}
+
+// Search - returns the entries under baseDN in scope matching filter,
+// with the requested attributes.
+func (c *Conn) Search(baseDN string, scope int, filter string, attributes []string) ([]*Entry, error) {
+	filterPacket, err := CompileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	attrs := NewSequence()
+	for _, attr := range attributes {
+		attrs.Children = append(attrs.Children, NewString(attr))
+	}
+	request := NewConstructed(ClassApplication, AppSearchRequest,
+		NewString(baseDN),
+		NewInteger(TagEnumerated, int64(scope)),
+		NewInteger(TagEnumerated, 0), // Never dereference aliases.
+		NewInteger(TagInteger, 0),    // No size limit.
+		NewInteger(TagInteger, 0),    // No time limit.
+		NewBoolean(false),
+		filterPacket,
+		attrs,
+	)
+	responses, err := c.do(request, AppSearchResultDone)
+	if err != nil {
+		return nil, err
+	}
+	if err = resultError(responses[len(responses)-1]); err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	for _, response := range responses[:len(responses)-1] {
+		if !response.Is(ClassApplication, AppSearchResultEntry) {
+			// Referrals are not followed.
+			continue
+		}
+		entry, err := parseEntry(response)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// do - sends a request and reads responses until one with the final
+// tag, all the protocol operations received are returned.
+func (c *Conn) do(request *Packet, finalTag byte) ([]*Packet, error) {
+	c.messageID++
+	messageID := c.messageID
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	defer c.conn.SetDeadline(time.Time{})
+
+	if _, err := c.conn.Write(NewSequence(NewInteger(TagInteger, messageID), request).Bytes()); err != nil {
+		return nil, err
+	}
+
+	var responses []*Packet
+	for {
+		message, err := ReadPacket(c.reader)
+		if err != nil {
+			return nil, err
+		}
+		if !message.Is(ClassUniversal, TagSequence) || len(message.Children) < 2 {
+			return nil, errMalformedPacket
+		}
+		if message.Children[0].Int() != messageID {
+			// Unsolicited notifications are not supported.
+			return nil, fmt.Errorf("ldap: unexpected message ID %d", message.Children[0].Int())
+		}
+		op := message.Children[1]
+		if op.Class != ClassApplication {
+			return nil, errMalformedPacket
+		}
+		responses = append(responses, op)
+		if op.Tag == finalTag {
+			return responses, nil
+		}
+	}
+}
+
+// resultError - returns the error of an LDAPResult, nil on success.
+func resultError(result *Packet) error {
+	if len(result.Children) < 3 {
+		return errMalformedPacket
+	}
+	resultCode := int(result.Children[0].Int())
+	if resultCode == ResultSuccess {
+		return nil
+	}
+	return &Error{ResultCode: resultCode, Message: result.Children[2].String()}
+}
+
+// parseEntry - parses a SearchResultEntry.
+func parseEntry(p *Packet) (*Entry, error) {
+	if len(p.Children) != 2 {
+		return nil, errMalformedPacket
+	}
+	entry := &Entry{
+		DN:         p.Children[0].String(),
+		Attributes: make(map[string][]string),
+	}
+	for _, attr := range p.Children[1].Children {
+		if len(attr.Children) != 2 {
+			return nil, errMalformedPacket
+		}
+		name := attr.Children[0].String()
+		for _, value := range attr.Children[1].Children {
+			entry.Attributes[name] = append(entry.Attributes[name], value.String())
+		}
+	}
+	return entry, nil
+}
+
+// EscapeDN - escapes a value to be used literally as an attribute
+// value of a distinguished name (RFC 4514).
+func EscapeDN(s string) string {
+	var buf []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ',' || c == '+' || c == '"' || c == '\\' || c == '<' || c == '>' || c == ';' || c == '=':
+			buf = append(buf, '\\', c)
+		case c == '#' && i == 0, c == ' ' && (i == 0 || i == len(s)-1):
+			buf = append(buf, '\\', c)
+		case c == 0:
+			buf = append(buf, "\\00"...)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return string(buf)
+}
@@ -0,0 +1,173 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/teamwork/minio/pkg/ldap"
+	"github.com/teamwork/minio/pkg/ldap/ldaptest"
+)
+
+func newTestServer() *ldaptest.Server {
+	return ldaptest.NewServer([]ldaptest.Entry{
+		{
+			DN:       "uid=alice,ou=people,dc=example,dc=com",
+			Password: "alice123",
+			Attributes: map[string][]string{
+				"uid": {"alice"},
+			},
+		},
+		{
+			DN: "cn=developers,ou=groups,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"objectClass": {"groupOfNames"},
+				"cn":          {"developers"},
+				"member":      {"uid=alice,ou=people,dc=example,dc=com"},
+			},
+		},
+		{
+			DN: "cn=admins,ou=groups,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"objectClass": {"groupOfNames"},
+				"cn":          {"admins"},
+				"member":      {"uid=bob,ou=people,dc=example,dc=com"},
+			},
+		},
+	})
+}
+
+func TestBind(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	testCases := []struct {
+		dn         string
+		password   string
+		resultCode int
+	}{
+		{"uid=alice,ou=people,dc=example,dc=com", "alice123", ldap.ResultSuccess},
+		{"uid=alice,ou=people,dc=example,dc=com", "wrong", ldap.ResultInvalidCredentials},
+		{"uid=carol,ou=people,dc=example,dc=com", "alice123", ldap.ResultInvalidCredentials},
+		// Unauthenticated binds are never sent.
+		{"uid=alice,ou=people,dc=example,dc=com", "", ldap.ResultUnwillingToPerform},
+	}
+	for i, testCase := range testCases {
+		conn, err := ldap.Dial(server.URL, nil)
+		if err != nil {
+			t.Fatalf("Test %d: Unable to connect - %v", i+1, err)
+		}
+		err = conn.Bind(testCase.dn, testCase.password)
+		if testCase.resultCode == ldap.ResultSuccess && err != nil {
+			t.Errorf("Test %d: Unexpected error %v", i+1, err)
+		}
+		if testCase.resultCode != ldap.ResultSuccess && !ldap.IsErrorWithCode(err, testCase.resultCode) {
+			t.Errorf("Test %d: Expected result code %d, got %v", i+1, testCase.resultCode, err)
+		}
+		conn.Close()
+	}
+}
+
+func TestSearch(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	conn, err := ldap.Dial(server.URL, nil)
+	if err != nil {
+		t.Fatalf("Unable to connect - %v", err)
+	}
+	defer conn.Close()
+	if err = conn.Bind("uid=alice,ou=people,dc=example,dc=com", "alice123"); err != nil {
+		t.Fatalf("Unable to bind - %v", err)
+	}
+
+	filter := "(&(objectClass=groupOfNames)(member=" + ldap.EscapeFilter("uid=alice,ou=people,dc=example,dc=com") + "))"
+	entries, err := conn.Search("ou=groups,dc=example,dc=com", ldap.ScopeWholeSubtree, filter, []string{"cn"})
+	if err != nil {
+		t.Fatalf("Unable to search - %v", err)
+	}
+	if len(entries) != 1 || entries[0].DN != "cn=developers,ou=groups,dc=example,dc=com" {
+		t.Fatalf("Unexpected entries %v", entries)
+	}
+	if cn := entries[0].GetAttributeValues("CN"); len(cn) != 1 || cn[0] != "developers" {
+		t.Errorf("Unexpected cn %v", cn)
+	}
+
+	// Nothing outside the base DN.
+	entries, err = conn.Search("ou=people,dc=example,dc=com", ldap.ScopeWholeSubtree, filter, nil)
+	if err != nil {
+		t.Fatalf("Unable to search - %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Unexpected entries %v", entries)
+	}
+
+	// StartTLS is not supported by the test server.
+	if err = conn.StartTLS(nil); !ldap.IsErrorWithCode(err, ldap.ResultProtocolError) {
+		t.Errorf("Expected StartTLS to fail, got %v", err)
+	}
+}
+
+func TestMalformedResponse(t *testing.T) {
+	result := func(code int64) []*ldap.Packet {
+		return []*ldap.Packet{
+			ldap.NewInteger(ldap.TagEnumerated, code),
+			ldap.NewString(""),
+			ldap.NewString(""),
+		}
+	}
+	bindResponse := func(messageID int64, children ...*ldap.Packet) []byte {
+		return ldap.NewSequence(
+			ldap.NewInteger(ldap.TagInteger, messageID),
+			ldap.NewConstructed(ldap.ClassApplication, ldap.AppBindResponse, children...),
+		).Bytes()
+	}
+
+	testCases := [][]byte{
+		// Truncated message.
+		bindResponse(1, result(ldap.ResultSuccess)...)[:5],
+		// Indefinite length.
+		{0x30, 0x80, 0x02, 0x01, 0x01, 0x00, 0x00},
+		// Oversized length prefix.
+		{0x30, 0x85, 0x00, 0x00, 0x00, 0x00, 0x03, 0x02, 0x01, 0x01},
+		// Message without a protocol operation.
+		ldap.NewSequence(ldap.NewInteger(ldap.TagInteger, 1)).Bytes(),
+		// Message which is not a sequence.
+		ldap.NewString("response").Bytes(),
+		// Response without a result.
+		bindResponse(1),
+		// Response to another message.
+		bindResponse(2, result(ldap.ResultSuccess)...),
+	}
+	for i, testCase := range testCases {
+		client, server := net.Pipe()
+		go func(response []byte) {
+			defer server.Close()
+			if _, err := ldap.ReadPacket(server); err != nil {
+				return
+			}
+			server.Write(response)
+		}(testCase)
+
+		conn := ldap.NewConn(client)
+		if err := conn.Bind("uid=alice,ou=people,dc=example,dc=com", "alice123"); err == nil {
+			t.Errorf("Test %d: Expected an error", i+1)
+		}
+		client.Close()
+	}
+}
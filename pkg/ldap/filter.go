@@ -0,0 +1,266 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Filter choices, context specific tags of the Filter CHOICE.
+const (
+	FilterAnd        = 0
+	FilterOr         = 1
+	FilterNot        = 2
+	FilterEquality   = 3
+	FilterSubstrings = 4
+	FilterPresent    = 7
+)
+
+// Substring choices.
+const (
+	substringInitial = 0
+	substringAny     = 1
+	substringFinal   = 2
+)
+
+// EscapeFilter - escapes a value to be used literally in a filter.
+func EscapeFilter(s string) string {
+	var buf []byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '*', '(', ')', '\\', 0:
+			buf = append(buf, fmt.Sprintf("\\%02x", c)...)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return string(buf)
+}
+
+// CompileFilter - compiles a string filter (RFC 4515) into its BER
+// representation. Equality, presence and substring items combined
+// with and, or and not are supported.
+func CompileFilter(filter string) (*Packet, error) {
+	p, rest, err := compileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("ldap: unexpected %q after filter", rest)
+	}
+	return p, nil
+}
+
+// compileFilter - compiles the parenthesized filter at the start of s
+// and returns the remaining string.
+func compileFilter(s string) (*Packet, string, error) {
+	if !strings.HasPrefix(s, "(") {
+		return nil, "", fmt.Errorf("ldap: filter %q does not start with '('", s)
+	}
+	s = s[1:]
+	if s == "" {
+		return nil, "", fmt.Errorf("ldap: unterminated filter")
+	}
+
+	switch s[0] {
+	case '&', '|':
+		tag := byte(FilterAnd)
+		if s[0] == '|' {
+			tag = FilterOr
+		}
+		p := NewConstructed(ClassContext, tag)
+		s = s[1:]
+		for strings.HasPrefix(s, "(") {
+			child, rest, err := compileFilter(s)
+			if err != nil {
+				return nil, "", err
+			}
+			p.Children = append(p.Children, child)
+			s = rest
+		}
+		if len(p.Children) == 0 || !strings.HasPrefix(s, ")") {
+			return nil, "", fmt.Errorf("ldap: malformed filter list")
+		}
+		return p, s[1:], nil
+	case '!':
+		child, rest, err := compileFilter(s[1:])
+		if err != nil {
+			return nil, "", err
+		}
+		if !strings.HasPrefix(rest, ")") {
+			return nil, "", fmt.Errorf("ldap: malformed not filter")
+		}
+		return NewConstructed(ClassContext, FilterNot, child), rest[1:], nil
+	}
+
+	end := strings.Index(s, ")")
+	if end < 0 {
+		return nil, "", fmt.Errorf("ldap: unterminated filter")
+	}
+	p, err := compileItem(s[:end])
+	if err != nil {
+		return nil, "", err
+	}
+	return p, s[end+1:], nil
+}
+
+// compileItem - compiles a single `attr=value` item.
+func compileItem(item string) (*Packet, error) {
+	i := strings.Index(item, "=")
+	if i <= 0 {
+		return nil, fmt.Errorf("ldap: malformed filter item %q", item)
+	}
+	attr, value := item[:i], item[i+1:]
+	if strings.HasSuffix(attr, ">") || strings.HasSuffix(attr, "<") || strings.HasSuffix(attr, "~") {
+		return nil, fmt.Errorf("ldap: unsupported filter item %q", item)
+	}
+
+	if value == "*" {
+		return NewPrimitive(ClassContext, FilterPresent, []byte(attr)), nil
+	}
+	if !strings.Contains(value, "*") {
+		unescaped, err := unescapeFilterValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return NewConstructed(ClassContext, FilterEquality, NewString(attr), NewString(unescaped)), nil
+	}
+
+	parts := strings.Split(value, "*")
+	substrings := NewSequence()
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		unescaped, err := unescapeFilterValue(part)
+		if err != nil {
+			return nil, err
+		}
+		tag := byte(substringAny)
+		if i == 0 {
+			tag = substringInitial
+		} else if i == len(parts)-1 {
+			tag = substringFinal
+		}
+		substrings.Children = append(substrings.Children, NewPrimitive(ClassContext, tag, []byte(unescaped)))
+	}
+	return NewConstructed(ClassContext, FilterSubstrings, NewString(attr), substrings), nil
+}
+
+// unescapeFilterValue - replaces `\XX` escapes by the byte they stand for.
+func unescapeFilterValue(s string) (string, error) {
+	var buf []byte
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			buf = append(buf, s[i])
+			continue
+		}
+		if i+2 >= len(s) {
+			return "", fmt.Errorf("ldap: malformed escape in %q", s)
+		}
+		b, err := hex.DecodeString(s[i+1 : i+3])
+		if err != nil {
+			return "", fmt.Errorf("ldap: malformed escape in %q", s)
+		}
+		buf = append(buf, b[0])
+		i += 2
+	}
+	return string(buf), nil
+}
+
+// MatchFilter - evaluates a compiled filter against the attributes of
+// an entry. Attribute names and values are compared case insensitively.
+func MatchFilter(filter *Packet, attributes map[string][]string) bool {
+	values := func(attr string) []string {
+		for name, vals := range attributes {
+			if strings.EqualFold(name, attr) {
+				return vals
+			}
+		}
+		return nil
+	}
+
+	switch filter.Tag {
+	case FilterAnd:
+		for _, child := range filter.Children {
+			if !MatchFilter(child, attributes) {
+				return false
+			}
+		}
+		return true
+	case FilterOr:
+		for _, child := range filter.Children {
+			if MatchFilter(child, attributes) {
+				return true
+			}
+		}
+		return false
+	case FilterNot:
+		return len(filter.Children) == 1 && !MatchFilter(filter.Children[0], attributes)
+	case FilterPresent:
+		return len(values(filter.String())) > 0
+	case FilterEquality:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		for _, v := range values(filter.Children[0].String()) {
+			if strings.EqualFold(v, filter.Children[1].String()) {
+				return true
+			}
+		}
+		return false
+	case FilterSubstrings:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		for _, v := range values(filter.Children[0].String()) {
+			if matchSubstrings(strings.ToLower(v), filter.Children[1].Children) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// matchSubstrings - checks if the value matches the substrings in order.
+func matchSubstrings(value string, substrings []*Packet) bool {
+	for _, sub := range substrings {
+		s := strings.ToLower(sub.String())
+		switch sub.Tag {
+		case substringInitial:
+			if !strings.HasPrefix(value, s) {
+				return false
+			}
+			value = value[len(s):]
+		case substringAny:
+			i := strings.Index(value, s)
+			if i < 0 {
+				return false
+			}
+			value = value[i+len(s):]
+		case substringFinal:
+			if !strings.HasSuffix(value, s) {
+				return false
+			}
+			value = ""
+		}
+	}
+	return true
+}
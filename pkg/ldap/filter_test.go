@@ -0,0 +1,85 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import "testing"
+
+func TestEscapeFilter(t *testing.T) {
+	if escaped := EscapeFilter(`a*(b)\c`); escaped != `a\2a\28b\29\5cc` {
+		t.Errorf("Unexpected escaped value %s", escaped)
+	}
+}
+
+func TestEscapeDN(t *testing.T) {
+	if escaped := EscapeDN(`#a,b=c `); escaped != `\#a\,b\=c\ ` {
+		t.Errorf("Unexpected escaped value %s", escaped)
+	}
+}
+
+func TestCompileFilter(t *testing.T) {
+	testCases := []string{
+		"cn=admins",
+		"(cn=admins",
+		"(cn>=admins)",
+		"(&)",
+		"(cn=a)(cn=b)",
+		`(cn=\zz)`,
+	}
+	for i, testCase := range testCases {
+		if _, err := CompileFilter(testCase); err == nil {
+			t.Errorf("Test %d: Expected %q to be rejected", i+1, testCase)
+		}
+	}
+}
+
+func TestMatchFilter(t *testing.T) {
+	attributes := map[string][]string{
+		"objectClass": {"groupOfNames"},
+		"cn":          {"Developers"},
+		"member":      {"uid=alice,ou=people,dc=example,dc=com", "uid=bob,ou=people,dc=example,dc=com"},
+	}
+	testCases := []struct {
+		filter  string
+		matches bool
+	}{
+		{"(cn=developers)", true},
+		{"(CN=Developers)", true},
+		{"(cn=admins)", false},
+		{"(objectclass=*)", true},
+		{"(description=*)", false},
+		{"(cn=dev*)", true},
+		{"(cn=*elop*)", true},
+		{"(cn=*ers)", true},
+		{"(cn=*admin*)", false},
+		{"(&(objectClass=groupOfNames)(member=uid=alice,ou=people,dc=example,dc=com))", true},
+		{"(&(objectClass=groupOfNames)(member=uid=carol,ou=people,dc=example,dc=com))", false},
+		{"(|(cn=admins)(cn=developers))", true},
+		{"(!(cn=admins))", true},
+		{"(!(cn=developers))", false},
+		{`(cn=\44evelopers)`, true},
+		{"(cn=" + EscapeFilter("dev*") + ")", false},
+	}
+	for i, testCase := range testCases {
+		filter, err := CompileFilter(testCase.filter)
+		if err != nil {
+			t.Fatalf("Test %d: Unable to compile %q - %v", i+1, testCase.filter, err)
+		}
+		if matches := MatchFilter(filter, attributes); matches != testCase.matches {
+			t.Errorf("Test %d: Expected %q to match %v, got %v", i+1, testCase.filter, testCase.matches, matches)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ldaptest provides an in-process LDAP server for tests,
+// serving simple binds and searches over a static directory.
+package ldaptest
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/teamwork/minio/pkg/ldap"
+)
+
+// Entry - an entry of the directory, the password is checked by binds
+// to the entry DN.
+type Entry struct {
+	DN         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// Server - an LDAP server listening on a local address.
+type Server struct {
+	// URL of the server, `ldap://127.0.0.1:<port>`.
+	URL string
+
+	listener net.Listener
+	entries  []Entry
+	wg       sync.WaitGroup
+}
+
+// NewServer - starts a server serving the entries.
+func NewServer(entries []Entry) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("ldaptest: failed to listen: " + err.Error())
+	}
+	s := &Server{
+		URL:      "ldap://" + listener.Addr().String(),
+		listener: listener,
+		entries:  entries,
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Close - stops the server and waits for open connections to end.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn - answers the requests of a connection until it is unbound
+// or closed.
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		message, err := ldap.ReadPacket(reader)
+		if err != nil || len(message.Children) < 2 {
+			return
+		}
+		messageID := message.Children[0].Int()
+		request := message.Children[1]
+
+		var responses []*ldap.Packet
+		switch request.Tag {
+		case ldap.AppBindRequest:
+			responses = []*ldap.Packet{s.bind(request)}
+		case ldap.AppSearchRequest:
+			responses = s.search(request)
+		case ldap.AppExtendedRequest:
+			responses = []*ldap.Packet{result(ldap.AppExtendedResponse, ldap.ResultProtocolError, "unsupported extended operation")}
+		default:
+			// Unbind and unknown requests end the connection.
+			return
+		}
+		for _, response := range responses {
+			packet := ldap.NewSequence(ldap.NewInteger(ldap.TagInteger, messageID), response)
+			if _, err = conn.Write(packet.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// bind - checks the password of a simple bind.
+func (s *Server) bind(request *ldap.Packet) *ldap.Packet {
+	if len(request.Children) != 3 {
+		return result(ldap.AppBindResponse, ldap.ResultProtocolError, "malformed bind request")
+	}
+	dn := request.Children[1].String()
+	password := request.Children[2].String()
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.DN, dn) && entry.Password != "" && entry.Password == password {
+			return result(ldap.AppBindResponse, ldap.ResultSuccess, "")
+		}
+	}
+	return result(ldap.AppBindResponse, ldap.ResultInvalidCredentials, "invalid credentials")
+}
+
+// search - returns the entries under the base DN matching the filter,
+// the scope is ignored and every search is a subtree search.
+func (s *Server) search(request *ldap.Packet) []*ldap.Packet {
+	if len(request.Children) != 8 {
+		return []*ldap.Packet{result(ldap.AppSearchResultDone, ldap.ResultProtocolError, "malformed search request")}
+	}
+	baseDN := strings.ToLower(request.Children[0].String())
+	filter := request.Children[6]
+
+	var responses []*ldap.Packet
+	for _, entry := range s.entries {
+		dn := strings.ToLower(entry.DN)
+		if dn != baseDN && !strings.HasSuffix(dn, ","+baseDN) {
+			continue
+		}
+		if !ldap.MatchFilter(filter, entry.Attributes) {
+			continue
+		}
+		attrs := ldap.NewSequence()
+		for name, values := range entry.Attributes {
+			vals := ldap.NewConstructed(ldap.ClassUniversal, ldap.TagSet)
+			for _, value := range values {
+				vals.Children = append(vals.Children, ldap.NewString(value))
+			}
+			attrs.Children = append(attrs.Children, ldap.NewSequence(ldap.NewString(name), vals))
+		}
+		responses = append(responses, ldap.NewConstructed(ldap.ClassApplication, ldap.AppSearchResultEntry,
+			ldap.NewString(entry.DN), attrs))
+	}
+	return append(responses, result(ldap.AppSearchResultDone, ldap.ResultSuccess, ""))
+}
+
+// result - returns an LDAPResult with the given application tag.
+func result(tag byte, resultCode int, message string) *ldap.Packet {
+	return ldap.NewConstructed(ldap.ClassApplication, tag,
+		ldap.NewInteger(ldap.TagEnumerated, int64(resultCode)),
+		ldap.NewString(""),
+		ldap.NewString(message),
+	)
+}
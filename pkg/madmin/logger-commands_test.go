@@ -0,0 +1,62 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Tests reloading the loggers.
+func TestReloadLogger(t *testing.T) {
+	reloaded := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.Header.Get(minioAdminOpHeader) != "reload" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, ok := r.URL.Query()["logger"]; !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if reloaded {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`<Error><Code>XMinioAdminInvalidLogger</Code><Message>The logger configuration of the config file is invalid.</Message></Error>`))
+			return
+		}
+		reloaded = true
+	}))
+	defer server.Close()
+
+	adm, err := New(strings.TrimPrefix(server.URL, "http://"), "minio", "minio123", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = adm.ReloadLogger(); err != nil {
+		t.Fatalf("Unable to reload loggers. %s", err)
+	}
+
+	// Invalid logger configs are reported.
+	err = adm.ReloadLogger()
+	if errResp, ok := err.(ErrorResponse); !ok || errResp.Code != "XMinioAdminInvalidLogger" {
+		t.Errorf("Expected XMinioAdminInvalidLogger, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Types of traced requests.
+const (
+	TraceS3        = "s3"
+	TraceAdmin     = "admin"
+	TraceBrowser   = "browser"
+	TraceInternode = "internode"
+)
+
+// TraceOpts - selects the requests Trace returns, empty fields select
+// all requests.
+type TraceOpts struct {
+	// Only S3 requests on this bucket.
+	Bucket string
+	// Only requests of this API, an S3 operation like REST.GET.OBJECT,
+	// an admin operation or an RPC method.
+	API string
+	// Only failed requests.
+	ErrorsOnly bool
+	// Include internode RPC calls.
+	Internode bool
+}
+
+// TraceInfo - represents a request served by a server, secrets in
+// headers and queries are redacted. Err is set if tracing failed.
+type TraceInfo struct {
+	Node          string        `json:"node"`
+	Type          string        `json:"type"`
+	Time          time.Time     `json:"time"`
+	RequestID     string        `json:"requestID,omitempty"`
+	API           string        `json:"api"`
+	Method        string        `json:"method"`
+	Path          string        `json:"path"`
+	Query         string        `json:"query,omitempty"`
+	Header        http.Header   `json:"header,omitempty"`
+	RemoteAddr    string        `json:"remoteAddr,omitempty"`
+	Bucket        string        `json:"bucket,omitempty"`
+	Object        string        `json:"object,omitempty"`
+	StatusCode    int           `json:"statusCode,omitempty"`
+	Error         string        `json:"error,omitempty"`
+	Duration      time.Duration `json:"duration"`
+	BytesReceived int64         `json:"bytesReceived"`
+	BytesSent     int64         `json:"bytesSent"`
+
+	Err error `json:"-"`
+}
+
+// Trace - Calls Trace Management API to stream the requests served by
+// all servers until doneCh is closed. The channel is closed when
+// tracing stops, after a TraceInfo carrying the error if it failed.
+func (adm *AdminClient) Trace(opts TraceOpts, doneCh <-chan struct{}) <-chan TraceInfo {
+	traceInfoCh := make(chan TraceInfo)
+
+	go func() {
+		defer close(traceInfoCh)
+
+		queryVal := make(url.Values)
+		queryVal.Set("trace", "")
+		if opts.Bucket != "" {
+			queryVal.Set("bucket", opts.Bucket)
+		}
+		if opts.API != "" {
+			queryVal.Set("api", opts.API)
+		}
+		if opts.ErrorsOnly {
+			queryVal.Set("errors", "yes")
+		}
+		if opts.Internode {
+			queryVal.Set("internode", "yes")
+		}
+
+		hdrs := make(http.Header)
+		hdrs.Set(minioAdminOpHeader, "trace")
+
+		reqData := requestData{
+			queryValues:   queryVal,
+			customHeaders: hdrs,
+		}
+
+		// Sends the error tracing stopped with, unless the caller is done.
+		sendErr := func(err error) {
+			select {
+			case traceInfoCh <- TraceInfo{Err: err}:
+			case <-doneCh:
+			}
+		}
+
+		// Execute GET on /?trace to stream traced requests.
+		resp, err := adm.executeMethod("GET", reqData)
+		if err != nil {
+			sendErr(err)
+			return
+		}
+		if resp.StatusCode != http.StatusOK {
+			closeResponse(resp)
+			sendErr(errors.New("Got HTTP Status: " + resp.Status))
+			return
+		}
+
+		// The response never ends, closing the body stops reading it.
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+		go func() {
+			select {
+			case <-doneCh:
+			case <-stopCh:
+			}
+			resp.Body.Close()
+		}()
+
+		// Traced requests are json lines, empty lines in between
+		// keep the connection alive.
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var info TraceInfo
+			if err = dec.Decode(&info); err != nil {
+				select {
+				case <-doneCh:
+				default:
+					sendErr(err)
+				}
+				return
+			}
+			select {
+			case traceInfoCh <- info:
+			case <-doneCh:
+				return
+			}
+		}
+	}()
+
+	return traceInfoCh
+}
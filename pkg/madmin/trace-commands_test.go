@@ -0,0 +1,70 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Tests reading traced requests streamed as json lines.
+func TestTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(minioAdminOpHeader) != "trace" || r.URL.Query().Get("bucket") != "mybucket" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"node":"node1","type":"s3","api":"REST.GET.OBJECT","statusCode":200}` + "\n\n"))
+		w.Write([]byte(`{"node":"node2","type":"s3","api":"REST.PUT.OBJECT","statusCode":403}` + "\n"))
+	}))
+	defer server.Close()
+
+	adm, err := New(strings.TrimPrefix(server.URL, "http://"), "minio", "minio123", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	var infos []TraceInfo
+	for info := range adm.Trace(TraceOpts{Bucket: "mybucket"}, doneCh) {
+		infos = append(infos, info)
+	}
+
+	// Both requests followed by the end of the stream.
+	if len(infos) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %+v", len(infos), infos)
+	}
+	if infos[0].Node != "node1" || infos[0].API != "REST.GET.OBJECT" || infos[1].StatusCode != http.StatusForbidden {
+		t.Errorf("Unexpected traced requests %+v", infos[:2])
+	}
+	if infos[2].Err == nil {
+		t.Error("Expected the end of the stream to be reported")
+	}
+
+	// Failed requests are reported.
+	var failed []TraceInfo
+	for info := range adm.Trace(TraceOpts{}, doneCh) {
+		failed = append(failed, info)
+	}
+	if len(failed) != 1 || failed[0].Err == nil {
+		t.Errorf("Expected an error, got %+v", failed)
+	}
+}
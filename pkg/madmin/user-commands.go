@@ -0,0 +1,167 @@
+/*
+ * Minio Cloud Storage, (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// User statuses.
+const (
+	UserEnabled  = "enabled"
+	UserDisabled = "disabled"
+)
+
+// UserInfo - represents an IAM user, the secret key is never returned
+// by the server.
+type UserInfo struct {
+	Status   string   `json:"status"`
+	Policies []string `json:"policies,omitempty"`
+}
+
+// addUserReq - add user request body.
+type addUserReq struct {
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	Status    string `json:"status,omitempty"`
+}
+
+// execIAMMethod - executes an IAM management API call and returns the
+// response body.
+func (adm *AdminClient) execIAMMethod(method, op string, queryVal url.Values, body []byte) ([]byte, error) {
+	if queryVal == nil {
+		queryVal = make(url.Values)
+	}
+	queryVal.Set("iam", "")
+
+	hdrs := make(http.Header)
+	hdrs.Set(minioAdminOpHeader, op)
+
+	reqData := requestData{
+		queryValues:   queryVal,
+		customHeaders: hdrs,
+	}
+	if body != nil {
+		reqData.contentBody = bytes.NewReader(body)
+		reqData.contentLength = int64(len(body))
+		reqData.contentMD5Bytes = sumMD5(body)
+		reqData.contentSHA256Bytes = sum256(body)
+	}
+
+	resp, err := adm.executeMethod(method, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// AddUser - creates an enabled user with its own access and secret
+// keys, or updates the secret key of an existing user.
+func (adm *AdminClient) AddUser(accessKey, secretKey string) error {
+	body, err := json.Marshal(addUserReq{AccessKey: accessKey, SecretKey: secretKey})
+	if err != nil {
+		return err
+	}
+	_, err = adm.execIAMMethod("POST", "add-user", nil, body)
+	return err
+}
+
+// RemoveUser - removes a user.
+func (adm *AdminClient) RemoveUser(accessKey string) error {
+	queryVal := make(url.Values)
+	queryVal.Set("accessKey", accessKey)
+	_, err := adm.execIAMMethod("POST", "remove-user", queryVal, nil)
+	return err
+}
+
+// SetUserStatus - enables or disables a user, status is one of
+// UserEnabled and UserDisabled.
+func (adm *AdminClient) SetUserStatus(accessKey, status string) error {
+	queryVal := make(url.Values)
+	queryVal.Set("accessKey", accessKey)
+	queryVal.Set("status", status)
+	_, err := adm.execIAMMethod("POST", "set-user-status", queryVal, nil)
+	return err
+}
+
+// ListUsers - lists all users indexed by their access key.
+func (adm *AdminClient) ListUsers() (map[string]UserInfo, error) {
+	respBytes, err := adm.execIAMMethod("GET", "list-users", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[string]UserInfo)
+	if err = json.Unmarshal(respBytes, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// AddPolicy - creates or replaces a named policy, the policy uses the
+// bucket policy JSON grammar.
+func (adm *AdminClient) AddPolicy(name, policy string) error {
+	queryVal := make(url.Values)
+	queryVal.Set("name", name)
+	_, err := adm.execIAMMethod("POST", "add-policy", queryVal, []byte(policy))
+	return err
+}
+
+// RemovePolicy - removes a named policy.
+func (adm *AdminClient) RemovePolicy(name string) error {
+	queryVal := make(url.Values)
+	queryVal.Set("name", name)
+	_, err := adm.execIAMMethod("POST", "remove-policy", queryVal, nil)
+	return err
+}
+
+// ListPolicies - lists all named policies as JSON documents.
+func (adm *AdminClient) ListPolicies() (map[string]json.RawMessage, error) {
+	respBytes, err := adm.execIAMMethod("GET", "list-policies", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(respBytes, &policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// SetUserPolicy - replaces the policies attached to a user, no
+// policies detaches all of them.
+func (adm *AdminClient) SetUserPolicy(accessKey string, policies ...string) error {
+	queryVal := make(url.Values)
+	queryVal.Set("accessKey", accessKey)
+	queryVal.Set("policies", strings.Join(policies, ","))
+	_, err := adm.execIAMMethod("POST", "set-user-policy", queryVal, nil)
+	return err
+}
@@ -73,6 +73,10 @@ type Cache struct {
 	// totalEvicted counter to keep track of total expirys
 	totalEvicted int
 
+	// hits and misses count the lookups of Open.
+	hits   uint64
+	misses uint64
+
 	// map of objectName and its contents
 	entries map[string]*buffer
 
@@ -223,17 +227,44 @@ func (c *Cache) Open(key string, objModTime time.Time) (io.ReadSeeker, error) {
 	defer c.mutex.Unlock()
 	buf, ok := c.entries[key]
 	if !ok {
+		c.misses++
 		return nil, ErrKeyNotFoundInCache
 	}
 	// Check if buf is recent copy of the object on disk.
 	if buf.lastAccessed.Before(objModTime) {
 		c.delete(key)
+		c.misses++
 		return nil, ErrKeyNotFoundInCache
 	}
+	c.hits++
 	buf.lastAccessed = time.Now().UTC()
 	return bytes.NewReader(buf.value), nil
 }
 
+// Stats - usage statistics of the cache.
+type Stats struct {
+	Hits    uint64 // Lookups which found a recent entry.
+	Misses  uint64 // Lookups which found no entry or a stale one.
+	Evicted int    // Entries removed from the cache.
+	Entries int    // Entries currently in the cache.
+	Size    uint64 // Current size of the cache in bytes.
+	MaxSize uint64 // Maximum size of the cache in bytes.
+}
+
+// Stats - returns the usage statistics of the cache.
+func (c *Cache) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return Stats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Evicted: c.totalEvicted,
+		Entries: len(c.entries),
+		Size:    c.currentSize,
+		MaxSize: c.maxSize,
+	}
+}
+
 // Delete - delete deletes an entry from the cache.
 func (c *Cache) Delete(key string) {
 	c.mutex.Lock()
@@ -284,3 +284,34 @@ func TestStaleEntryPurge(t *testing.T) {
 		t.Errorf("Test case expected to return ErrKeyNotFoundInCache, instead returned %s", err)
 	}
 }
+
+// TestStats - tests the hit and miss counts and the size reported by Stats.
+func TestStats(t *testing.T) {
+	cache := New(1024, NoExpiry)
+	w, err := cache.Create("test", 5)
+	if err != nil {
+		t.Fatalf("Test case expected to pass, failed instead %s", err)
+	}
+	w.Write([]byte("Hello"))
+	if err = w.Close(); err != nil {
+		t.Fatalf("Test case expected to pass, failed instead %s", err)
+	}
+
+	if _, err = cache.Open("test", time.Time{}); err != nil {
+		t.Errorf("Test case expected to pass, failed instead %s", err)
+	}
+	if _, err = cache.Open("missing", time.Time{}); err != ErrKeyNotFoundInCache {
+		t.Errorf("Test case expected to return ErrKeyNotFoundInCache, instead returned %s", err)
+	}
+	stats := cache.Stats()
+	expected := Stats{Hits: 1, Misses: 1, Entries: 1, Size: 5, MaxSize: 1024}
+	if stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+
+	cache.Delete("test")
+	stats = cache.Stats()
+	if stats.Entries != 0 || stats.Size != 0 || stats.Evicted != 1 {
+		t.Errorf("Expected an empty cache, got %+v", stats)
+	}
+}
@@ -0,0 +1,551 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Values of expressions are one of nil (NULL), bool, float64, string,
+// json.Number, or the map[string]interface{} and []interface{} of
+// nested JSON documents.
+type value interface{}
+
+// record - a single row of the input.
+type record interface {
+	// Returns the value of a column, nil if it does not exist.
+	get(path []string) value
+}
+
+// expr - a node of a parsed expression.
+type expr interface {
+	eval(r record) (value, error)
+}
+
+// Returns an error for invalid arguments of an operator or function.
+func evalError(format string, args ...interface{}) error {
+	return &Error{ErrCodeEvaluatorInvalidArguments, fmt.Sprintf(format, args...)}
+}
+
+// Converts a value to a number, ok is false if it is none.
+func toNumber(v value) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// Converts a scalar value to its text representation.
+func toString(v value) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return string(v)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// Interprets a value as a condition, NULL and non boolean values are
+// not true.
+func isTrue(v value) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// literal - a constant.
+type literal struct {
+	v value
+}
+
+func (e *literal) eval(r record) (value, error) {
+	return e.v, nil
+}
+
+// column - a reference to a column of the record.
+type column struct {
+	path []string
+}
+
+func (e *column) eval(r record) (value, error) {
+	return r.get(e.path), nil
+}
+
+// logicalExpr - AND and OR with the three valued logic of SQL.
+type logicalExpr struct {
+	op          string
+	left, right expr
+}
+
+func (e *logicalExpr) eval(r record) (value, error) {
+	left, err := e.left.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	lb, lok := left.(bool)
+	if e.op == "AND" && lok && !lb {
+		return false, nil
+	}
+	if e.op == "OR" && lok && lb {
+		return true, nil
+	}
+	right, err := e.right.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	rb, rok := right.(bool)
+	if e.op == "AND" && rok && !rb {
+		return false, nil
+	}
+	if e.op == "OR" && rok && rb {
+		return true, nil
+	}
+	if !lok || !rok {
+		return nil, nil
+	}
+	return e.op == "AND", nil
+}
+
+// notExpr - negation of a condition.
+type notExpr struct {
+	e expr
+}
+
+func (e *notExpr) eval(r record) (value, error) {
+	v, err := e.e.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	if b, ok := v.(bool); ok {
+		return !b, nil
+	}
+	return nil, nil
+}
+
+// Compares two values, numbers compare numerically with numbers and
+// numeric strings, everything else compares as text. ok is false if
+// either value is NULL.
+func compareValues(a, b value) (cmp int, ok bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+	_, aString := a.(string)
+	_, bString := b.(string)
+	if !aString || !bString {
+		af, aNumber := toNumber(a)
+		bf, bNumber := toNumber(b)
+		if aNumber && bNumber {
+			switch {
+			case af < bf:
+				return -1, true
+			case af > bf:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	return strings.Compare(toString(a), toString(b)), true
+}
+
+// compareExpr - the comparison operators.
+type compareExpr struct {
+	op          string
+	left, right expr
+}
+
+func (e *compareExpr) eval(r record) (value, error) {
+	left, err := e.left.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	cmp, ok := compareValues(left, right)
+	if !ok {
+		return nil, nil
+	}
+	switch e.op {
+	case "=":
+		return cmp == 0, nil
+	case "!=", "<>":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	}
+	return cmp >= 0, nil
+}
+
+// isNullExpr - IS [NOT] NULL, empty CSV fields are not NULL.
+type isNullExpr struct {
+	e   expr
+	not bool
+}
+
+func (e *isNullExpr) eval(r record) (value, error) {
+	v, err := e.e.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	return (v == nil) != e.not, nil
+}
+
+// betweenExpr - [NOT] BETWEEN, both bounds are inclusive.
+type betweenExpr struct {
+	e, low, high expr
+	not          bool
+}
+
+func (e *betweenExpr) eval(r record) (value, error) {
+	var values [3]value
+	for i, operand := range []expr{e.e, e.low, e.high} {
+		v, err := operand.eval(r)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	low, lok := compareValues(values[0], values[1])
+	high, hok := compareValues(values[0], values[2])
+	if !lok || !hok {
+		return nil, nil
+	}
+	return (low >= 0 && high <= 0) != e.not, nil
+}
+
+// inExpr - [NOT] IN (...).
+type inExpr struct {
+	e    expr
+	list []expr
+	not  bool
+}
+
+func (e *inExpr) eval(r record) (value, error) {
+	v, err := e.e.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+	for _, item := range e.list {
+		iv, err := item.eval(r)
+		if err != nil {
+			return nil, err
+		}
+		if cmp, ok := compareValues(v, iv); ok && cmp == 0 {
+			return !e.not, nil
+		}
+	}
+	return e.not, nil
+}
+
+// likeExpr - [NOT] LIKE, `%` matches any sequence and `_` any single
+// character.
+type likeExpr struct {
+	e, pattern, escape expr
+	not                bool
+}
+
+func (e *likeExpr) eval(r record) (value, error) {
+	v, err := e.e.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := e.pattern.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil || pattern == nil {
+		return nil, nil
+	}
+	escape := rune(-1)
+	if e.escape != nil {
+		ev, err := e.escape.eval(r)
+		if err != nil {
+			return nil, err
+		}
+		s := toString(ev)
+		if utf8.RuneCountInString(s) != 1 {
+			return nil, evalError("LIKE escape must be a single character")
+		}
+		escape, _ = utf8.DecodeRuneInString(s)
+	}
+	return matchLike([]rune(toString(v)), []rune(toString(pattern)), escape) != e.not, nil
+}
+
+// Matches text against a LIKE pattern.
+func matchLike(text, pattern []rune, escape rune) bool {
+	for len(pattern) > 0 {
+		switch c := pattern[0]; {
+		case c == escape && len(pattern) > 1:
+			if len(text) == 0 || text[0] != pattern[1] {
+				return false
+			}
+			text, pattern = text[1:], pattern[2:]
+		case c == '%':
+			for len(pattern) > 0 && pattern[0] == '%' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := 0; i <= len(text); i++ {
+				if matchLike(text[i:], pattern, escape) {
+					return true
+				}
+			}
+			return false
+		case c == '_':
+			if len(text) == 0 {
+				return false
+			}
+			text, pattern = text[1:], pattern[1:]
+		default:
+			if len(text) == 0 || text[0] != c {
+				return false
+			}
+			text, pattern = text[1:], pattern[1:]
+		}
+	}
+	return len(text) == 0
+}
+
+// arithExpr - arithmetic operators and the `||` string concatenation.
+type arithExpr struct {
+	op          string
+	left, right expr
+}
+
+func (e *arithExpr) eval(r record) (value, error) {
+	left, err := e.left.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	if e.op == "||" {
+		return toString(left) + toString(right), nil
+	}
+	a, aok := toNumber(left)
+	b, bok := toNumber(right)
+	if !aok || !bok {
+		return nil, evalError("Operator %s requires numeric operands, got %q and %q", e.op, toString(left), toString(right))
+	}
+	switch e.op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	}
+	if b == 0 {
+		return nil, evalError("Division by zero")
+	}
+	if e.op == "%" {
+		return math.Mod(a, b), nil
+	}
+	return a / b, nil
+}
+
+// castExpr - CAST(<expr> AS <type>).
+type castExpr struct {
+	e   expr
+	typ string
+}
+
+func (e *castExpr) eval(r record) (value, error) {
+	v, err := e.e.eval(r)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	switch e.typ {
+	case "STRING", "VARCHAR":
+		return toString(v), nil
+	case "BOOL", "BOOLEAN":
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(toString(v)))
+		if err != nil {
+			return nil, &Error{ErrCodeCastFailed, fmt.Sprintf("Unable to cast %q to %s", toString(v), e.typ)}
+		}
+		return b, nil
+	}
+	f, ok := toNumber(v)
+	if !ok {
+		return nil, &Error{ErrCodeCastFailed, fmt.Sprintf("Unable to cast %q to %s", toString(v), e.typ)}
+	}
+	if e.typ == "INT" || e.typ == "INTEGER" {
+		return math.Trunc(f), nil
+	}
+	return f, nil
+}
+
+// funcExpr - scalar string functions.
+type funcExpr struct {
+	fn string
+	e  expr
+}
+
+func (e *funcExpr) eval(r record) (value, error) {
+	v, err := e.e.eval(r)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	s := toString(v)
+	switch e.fn {
+	case "LOWER":
+		return strings.ToLower(s), nil
+	case "UPPER":
+		return strings.ToUpper(s), nil
+	case "TRIM":
+		return strings.TrimSpace(s), nil
+	}
+	return float64(utf8.RuneCountInString(s)), nil
+}
+
+// aggregate - an aggregate function, accumulated over all records
+// matching the query by update.
+type aggregate struct {
+	fn string
+	// Argument of the function, nil for COUNT(*).
+	e expr
+
+	count int64
+	sum   float64
+	min   value
+	max   value
+}
+
+// Adds a record to the aggregate, NULL values are skipped.
+func (e *aggregate) update(r record) error {
+	if e.e == nil {
+		e.count++
+		return nil
+	}
+	v, err := e.e.eval(r)
+	if err != nil || v == nil {
+		return err
+	}
+	switch e.fn {
+	case "SUM", "AVG":
+		f, ok := toNumber(v)
+		if !ok {
+			return evalError("%s requires numeric values, got %q", e.fn, toString(v))
+		}
+		e.sum += f
+	case "MIN":
+		if cmp, ok := compareValues(v, e.min); !ok && e.min == nil || ok && cmp < 0 {
+			e.min = v
+		}
+	case "MAX":
+		if cmp, ok := compareValues(v, e.max); !ok && e.max == nil || ok && cmp > 0 {
+			e.max = v
+		}
+	}
+	e.count++
+	return nil
+}
+
+// Returns the result of the aggregate.
+func (e *aggregate) eval(r record) (value, error) {
+	switch e.fn {
+	case "COUNT":
+		return float64(e.count), nil
+	case "SUM":
+		if e.count == 0 {
+			return nil, nil
+		}
+		return e.sum, nil
+	case "AVG":
+		if e.count == 0 {
+			return nil, nil
+		}
+		return e.sum / float64(e.count), nil
+	case "MIN":
+		return e.min, nil
+	}
+	return e.max, nil
+}
+
+// Reports whether an expression refers to columns outside of aggregate
+// functions.
+func hasColumnOutsideAggregate(e expr) bool {
+	switch e := e.(type) {
+	case *column:
+		return true
+	case *aggregate:
+		return false
+	case *logicalExpr:
+		return hasColumnOutsideAggregate(e.left) || hasColumnOutsideAggregate(e.right)
+	case *compareExpr:
+		return hasColumnOutsideAggregate(e.left) || hasColumnOutsideAggregate(e.right)
+	case *arithExpr:
+		return hasColumnOutsideAggregate(e.left) || hasColumnOutsideAggregate(e.right)
+	case *notExpr:
+		return hasColumnOutsideAggregate(e.e)
+	case *isNullExpr:
+		return hasColumnOutsideAggregate(e.e)
+	case *castExpr:
+		return hasColumnOutsideAggregate(e.e)
+	case *funcExpr:
+		return hasColumnOutsideAggregate(e.e)
+	case *likeExpr:
+		return hasColumnOutsideAggregate(e.e) || hasColumnOutsideAggregate(e.pattern) ||
+			(e.escape != nil && hasColumnOutsideAggregate(e.escape))
+	case *betweenExpr:
+		return hasColumnOutsideAggregate(e.e) || hasColumnOutsideAggregate(e.low) || hasColumnOutsideAggregate(e.high)
+	case *inExpr:
+		for _, item := range append([]expr{e.e}, e.list...) {
+			if hasColumnOutsideAggregate(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// inputRecord - a record of the input which can be written as a whole
+// by `SELECT *`.
+type inputRecord interface {
+	record
+
+	// Returns the names and values of all columns in input order.
+	columns() ([]string, []value)
+}
+
+// recordReader - reads the records of an object.
+type recordReader interface {
+	// Returns the next record, io.EOF after the last one.
+	Read() (inputRecord, error)
+}
+
+// csvHeader - column names of a CSV input with a header line.
+type csvHeader struct {
+	names []string
+	index map[string]int
+	// Case insensitive lookup of names which are not matched exactly.
+	lowerIndex map[string]int
+}
+
+func newCSVHeader(names []string) *csvHeader {
+	h := &csvHeader{
+		names:      names,
+		index:      make(map[string]int, len(names)),
+		lowerIndex: make(map[string]int, len(names)),
+	}
+	for i := len(names) - 1; i >= 0; i-- {
+		h.index[names[i]] = i
+		h.lowerIndex[strings.ToLower(names[i])] = i
+	}
+	return h
+}
+
+// csvRecord - a line of a CSV input.
+type csvRecord struct {
+	header *csvHeader
+	fields []string
+}
+
+func (r *csvRecord) get(path []string) value {
+	if len(path) != 1 {
+		return nil
+	}
+	name := path[0]
+	if r.header != nil {
+		i, ok := r.header.index[name]
+		if !ok {
+			i, ok = r.header.lowerIndex[strings.ToLower(name)]
+		}
+		if ok {
+			if i < len(r.fields) {
+				return r.fields[i]
+			}
+			return nil
+		}
+	}
+
+	// Positional references _1, _2, ...
+	if strings.HasPrefix(name, "_") {
+		if i, err := strconv.Atoi(name[1:]); err == nil && i >= 1 && i <= len(r.fields) {
+			return r.fields[i-1]
+		}
+	}
+	return nil
+}
+
+func (r *csvRecord) columns() ([]string, []value) {
+	names := make([]string, len(r.fields))
+	values := make([]value, len(r.fields))
+	for i, field := range r.fields {
+		if r.header != nil && i < len(r.header.names) {
+			names[i] = r.header.names[i]
+		} else {
+			names[i] = "_" + strconv.Itoa(i+1)
+		}
+		values[i] = field
+	}
+	return names, values
+}
+
+// csvReader - reads records of a CSV input.
+type csvReader struct {
+	reader *csv.Reader
+	header *csvHeader
+	// Whether the first line still has to be handled.
+	first      bool
+	headerInfo string
+}
+
+// Returns a reader for CSV input, options are validated by NewSelect.
+func newCSVReader(r io.Reader, opts *CSVInput) recordReader {
+	if opts.RecordDelimiter != "" && opts.RecordDelimiter != "\n" && opts.RecordDelimiter != "\r\n" {
+		r = &replaceReader{r: bufio.NewReader(r), old: opts.RecordDelimiter[0], new: '\n'}
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if opts.FieldDelimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(opts.FieldDelimiter)
+	}
+	if opts.Comments != "" {
+		reader.Comment, _ = utf8.DecodeRuneInString(opts.Comments)
+	}
+	return &csvReader{
+		reader:     reader,
+		first:      true,
+		headerInfo: strings.ToUpper(opts.FileHeaderInfo),
+	}
+}
+
+func (r *csvReader) Read() (inputRecord, error) {
+	fields, err := r.reader.Read()
+	if err != nil {
+		if _, ok := err.(*csv.ParseError); ok {
+			return nil, &Error{ErrCodeCSVParsingError, err.Error()}
+		}
+		return nil, err
+	}
+	if r.first {
+		r.first = false
+		switch r.headerInfo {
+		case fileHeaderUse:
+			r.header = newCSVHeader(fields)
+			return r.Read()
+		case fileHeaderIgnore:
+			return r.Read()
+		}
+	}
+	return &csvRecord{header: r.header, fields: fields}, nil
+}
+
+// replaceReader - replaces a single byte record delimiter by newlines,
+// encoding/csv only knows about `\n` and `\r\n`.
+type replaceReader struct {
+	r        io.Reader
+	old, new byte
+}
+
+func (r *replaceReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	for i := 0; i < n; i++ {
+		if p[i] == r.old {
+			p[i] = r.new
+		}
+	}
+	return n, err
+}
+
+// jsonRecord - a JSON object of the input.
+type jsonRecord struct {
+	keys   []string
+	values map[string]interface{}
+	raw    []byte
+}
+
+// Parses a JSON object keeping the order of its keys.
+func parseJSONRecord(raw []byte) (*jsonRecord, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, &Error{ErrCodeJSONParsingError, "JSON records have to be objects"}
+	}
+	r := &jsonRecord{values: make(map[string]interface{}), raw: raw}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, &Error{ErrCodeJSONParsingError, err.Error()}
+		}
+		key, _ := t.(string)
+		var v interface{}
+		if err = dec.Decode(&v); err != nil {
+			return nil, &Error{ErrCodeJSONParsingError, err.Error()}
+		}
+		if _, ok := r.values[key]; !ok {
+			r.keys = append(r.keys, key)
+		}
+		r.values[key] = v
+	}
+	return r, nil
+}
+
+func (r *jsonRecord) get(path []string) value {
+	var v interface{} = r.values
+	for _, name := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if v, ok = m[name]; !ok {
+			return nil
+		}
+	}
+	return v
+}
+
+func (r *jsonRecord) columns() ([]string, []value) {
+	values := make([]value, len(r.keys))
+	for i, key := range r.keys {
+		values[i] = r.values[key]
+	}
+	return r.keys, values
+}
+
+// jsonReader - reads records of a JSON input, either a sequence of
+// objects (JSON lines or concatenated documents) or arrays of objects.
+type jsonReader struct {
+	dec *json.Decoder
+	// Remaining elements of a top level array, arrays are decoded as a
+	// whole.
+	pending []json.RawMessage
+}
+
+func newJSONReader(r io.Reader) recordReader {
+	return &jsonReader{dec: json.NewDecoder(r)}
+}
+
+func (r *jsonReader) Read() (inputRecord, error) {
+	for len(r.pending) == 0 {
+		var raw json.RawMessage
+		if err := r.dec.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			if _, ok := err.(*json.SyntaxError); ok || err == io.ErrUnexpectedEOF {
+				return nil, &Error{ErrCodeJSONParsingError, err.Error()}
+			}
+			return nil, err
+		}
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+			if err := json.Unmarshal(trimmed, &r.pending); err != nil {
+				return nil, &Error{ErrCodeJSONParsingError, err.Error()}
+			}
+			continue
+		}
+		r.pending = []json.RawMessage{raw}
+	}
+	raw := r.pending[0]
+	r.pending = r.pending[1:]
+	return parseJSONRecord(raw)
+}
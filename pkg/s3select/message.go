@@ -0,0 +1,132 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Responses are streamed as messages of the AWS event stream encoding,
+// each message is laid out as
+//
+//   total length (4) | headers length (4) | prelude crc (4) |
+//   headers | payload | message crc (4)
+//
+// All integers are big endian, both checksums are CRC32 (IEEE). A
+// header is encoded as
+//
+//   name length (1) | name | value type (1) | value length (2) | value
+//
+// only string values (type 7) are used.
+
+// Size of the prelude and the trailing checksum of a message.
+const messageOverhead = 16
+
+// Value type of string headers.
+const headerTypeString = 7
+
+// header - a header of an event stream message.
+type header struct {
+	name, value string
+}
+
+// Encodes a single message.
+func encodeMessage(headers []header, payload []byte) []byte {
+	var hbuf bytes.Buffer
+	for _, h := range headers {
+		hbuf.WriteByte(byte(len(h.name)))
+		hbuf.WriteString(h.name)
+		hbuf.WriteByte(headerTypeString)
+		binary.Write(&hbuf, binary.BigEndian, uint16(len(h.value)))
+		hbuf.WriteString(h.value)
+	}
+
+	totalLength := messageOverhead + hbuf.Len() + len(payload)
+	msg := make([]byte, 12, totalLength)
+	binary.BigEndian.PutUint32(msg[0:4], uint32(totalLength))
+	binary.BigEndian.PutUint32(msg[4:8], uint32(hbuf.Len()))
+	binary.BigEndian.PutUint32(msg[8:12], crc32.ChecksumIEEE(msg[0:8]))
+	msg = append(msg, hbuf.Bytes()...)
+	msg = append(msg, payload...)
+	var crc [4]byte
+	binary.BigEndian.PutUint32(crc[:], crc32.ChecksumIEEE(msg))
+	return append(msg, crc[:]...)
+}
+
+// eventWriter - writes the events of a Select response.
+type eventWriter struct {
+	w   io.Writer
+	err error
+}
+
+// Writes a message and flushes it to the client, the first error is
+// kept and returned for all further messages.
+func (ew *eventWriter) writeMessage(headers []header, payload []byte) error {
+	if ew.err != nil {
+		return ew.err
+	}
+	if _, ew.err = ew.w.Write(encodeMessage(headers, payload)); ew.err != nil {
+		return ew.err
+	}
+	if f, ok := ew.w.(interface {
+		Flush()
+	}); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// Writes a Records event.
+func (ew *eventWriter) writeRecords(payload []byte) error {
+	return ew.writeMessage([]header{
+		{":event-type", "Records"},
+		{":content-type", "application/octet-stream"},
+		{":message-type", "event"},
+	}, payload)
+}
+
+// Writes a Progress or Stats event.
+func (ew *eventWriter) writeStats(eventType string, scanned, processed, returned int64) error {
+	payload := fmt.Sprintf("<?xml version=\"1.0\" encoding=\"UTF-8\"?><%s><BytesScanned>%d</BytesScanned><BytesProcessed>%d</BytesProcessed><BytesReturned>%d</BytesReturned></%s>",
+		eventType, scanned, processed, returned, eventType)
+	return ew.writeMessage([]header{
+		{":event-type", eventType},
+		{":content-type", "text/xml"},
+		{":message-type", "event"},
+	}, []byte(payload))
+}
+
+// Writes the End event, the last message of a successful response.
+func (ew *eventWriter) writeEnd() error {
+	return ew.writeMessage([]header{
+		{":event-type", "End"},
+		{":message-type", "event"},
+	}, nil)
+}
+
+// Writes an error message, no further messages follow.
+func (ew *eventWriter) writeError(code, message string) error {
+	return ew.writeMessage([]header{
+		{":error-code", code},
+		{":error-message", message},
+		{":message-type", "error"},
+	}, nil)
+}
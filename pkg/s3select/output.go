@@ -0,0 +1,110 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// recordWriter - serializes result records.
+type recordWriter interface {
+	write(buf *bytes.Buffer, names []string, values []value)
+}
+
+// csvWriter - writes records as CSV.
+type csvWriter struct {
+	fieldDelimiter  string
+	recordDelimiter string
+	quote           string
+	quoteEscape     string
+	quoteAlways     bool
+}
+
+func newCSVWriter(opts *CSVOutput) recordWriter {
+	w := &csvWriter{
+		fieldDelimiter:  ",",
+		recordDelimiter: "\n",
+		quote:           `"`,
+		quoteAlways:     strings.EqualFold(opts.QuoteFields, quoteFieldsAlways),
+	}
+	if opts.FieldDelimiter != "" {
+		w.fieldDelimiter = opts.FieldDelimiter
+	}
+	if opts.RecordDelimiter != "" {
+		w.recordDelimiter = opts.RecordDelimiter
+	}
+	if opts.QuoteCharacter != "" {
+		w.quote = opts.QuoteCharacter
+	}
+	w.quoteEscape = w.quote
+	if opts.QuoteEscapeCharacter != "" {
+		w.quoteEscape = opts.QuoteEscapeCharacter
+	}
+	return w
+}
+
+func (w *csvWriter) write(buf *bytes.Buffer, names []string, values []value) {
+	for i, v := range values {
+		if i > 0 {
+			buf.WriteString(w.fieldDelimiter)
+		}
+		field := toString(v)
+		if w.quoteAlways || strings.Contains(field, w.fieldDelimiter) || strings.Contains(field, w.quote) ||
+			strings.ContainsAny(field, "\r\n") || strings.Contains(field, w.recordDelimiter) {
+			buf.WriteString(w.quote)
+			buf.WriteString(strings.Replace(field, w.quote, w.quoteEscape+w.quote, -1))
+			buf.WriteString(w.quote)
+			continue
+		}
+		buf.WriteString(field)
+	}
+	buf.WriteString(w.recordDelimiter)
+}
+
+// jsonWriter - writes records as JSON objects.
+type jsonWriter struct {
+	recordDelimiter string
+}
+
+func newJSONWriter(opts *JSONOutput) recordWriter {
+	w := &jsonWriter{recordDelimiter: "\n"}
+	if opts.RecordDelimiter != "" {
+		w.recordDelimiter = opts.RecordDelimiter
+	}
+	return w
+}
+
+func (w *jsonWriter) write(buf *bytes.Buffer, names []string, values []value) {
+	buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(names[i])
+		buf.Write(name)
+		buf.WriteByte(':')
+		data, err := json.Marshal(v)
+		if err != nil {
+			data, _ = json.Marshal(toString(v))
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	buf.WriteString(w.recordDelimiter)
+}
@@ -0,0 +1,413 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package s3select implements SelectObjectContent, filtering CSV and
+// JSON objects with a subset of SQL while streaming them.
+package s3select
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Error codes of Select requests.
+const (
+	ErrCodeInvalidExpressionType     = "InvalidExpressionType"
+	ErrCodeInvalidCompressionFormat  = "InvalidCompressionFormat"
+	ErrCodeInvalidRequestParameter   = "InvalidRequestParameter"
+	ErrCodeMissingRequiredParameter  = "MissingRequiredParameter"
+	ErrCodeParseUnexpectedToken      = "ParseUnexpectedToken"
+	ErrCodeParseUnsupportedSyntax    = "ParseUnsupportedSyntax"
+	ErrCodeEvaluatorInvalidArguments = "EvaluatorInvalidArguments"
+	ErrCodeCastFailed                = "CastFailed"
+	ErrCodeCSVParsingError           = "CSVParsingError"
+	ErrCodeJSONParsingError          = "JSONParsingError"
+	ErrCodeInternalError             = "InternalError"
+)
+
+// Error - a failed Select request, Code is the S3 error code.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Values of the serialization options.
+const (
+	compressionNone = "NONE"
+	compressionGzip = "GZIP"
+
+	fileHeaderNone   = "NONE"
+	fileHeaderUse    = "USE"
+	fileHeaderIgnore = "IGNORE"
+
+	jsonTypeDocument = "DOCUMENT"
+	jsonTypeLines    = "LINES"
+
+	quoteFieldsAlways   = "ALWAYS"
+	quoteFieldsAsNeeded = "ASNEEDED"
+)
+
+// Results are sent in Records events of at most this size, a single
+// larger record is sent on its own.
+const maxRecordsPayload = 128 * 1024
+
+// CSVInput - CSV serialization of the object.
+type CSVInput struct {
+	FileHeaderInfo             string `xml:"FileHeaderInfo"`
+	RecordDelimiter            string `xml:"RecordDelimiter"`
+	FieldDelimiter             string `xml:"FieldDelimiter"`
+	QuoteCharacter             string `xml:"QuoteCharacter"`
+	QuoteEscapeCharacter       string `xml:"QuoteEscapeCharacter"`
+	Comments                   string `xml:"Comments"`
+	AllowQuotedRecordDelimiter bool   `xml:"AllowQuotedRecordDelimiter"`
+}
+
+// JSONInput - JSON serialization of the object.
+type JSONInput struct {
+	Type string `xml:"Type"`
+}
+
+// InputSerialization - format of the object.
+type InputSerialization struct {
+	CompressionType string     `xml:"CompressionType"`
+	CSV             *CSVInput  `xml:"CSV"`
+	JSON            *JSONInput `xml:"JSON"`
+}
+
+// CSVOutput - CSV serialization of the results.
+type CSVOutput struct {
+	QuoteFields          string `xml:"QuoteFields"`
+	RecordDelimiter      string `xml:"RecordDelimiter"`
+	FieldDelimiter       string `xml:"FieldDelimiter"`
+	QuoteCharacter       string `xml:"QuoteCharacter"`
+	QuoteEscapeCharacter string `xml:"QuoteEscapeCharacter"`
+}
+
+// JSONOutput - JSON serialization of the results.
+type JSONOutput struct {
+	RecordDelimiter string `xml:"RecordDelimiter"`
+}
+
+// OutputSerialization - format of the results.
+type OutputSerialization struct {
+	CSV  *CSVOutput  `xml:"CSV"`
+	JSON *JSONOutput `xml:"JSON"`
+}
+
+// Request - body of a SelectObjectContent request.
+type Request struct {
+	XMLName             xml.Name            `xml:"SelectObjectContentRequest"`
+	Expression          string              `xml:"Expression"`
+	ExpressionType      string              `xml:"ExpressionType"`
+	InputSerialization  InputSerialization  `xml:"InputSerialization"`
+	OutputSerialization OutputSerialization `xml:"OutputSerialization"`
+	RequestProgress     struct {
+		Enabled bool `xml:"Enabled"`
+	} `xml:"RequestProgress"`
+}
+
+// Select - a validated Select request, ready to be executed once.
+type Select struct {
+	query    *query
+	progress bool
+	gzip     bool
+
+	newReader func(io.Reader) recordReader
+	writer    recordWriter
+
+	// Whether `SELECT *` over JSON input can return records unchanged.
+	rawJSON bool
+}
+
+// Returns an error for an invalid request parameter.
+func invalidParameter(name, v string) error {
+	return &Error{ErrCodeInvalidRequestParameter, "Invalid value " + strconv.Quote(v) + " of " + name}
+}
+
+// Validates a delimiter option, at most maxLength bytes.
+func checkDelimiter(name, v string, maxLength int) error {
+	if len(v) > maxLength || (v != "" && !utf8.ValidString(v)) {
+		return invalidParameter(name, v)
+	}
+	return nil
+}
+
+// NewSelect - validates a request and parses its expression.
+func NewSelect(req *Request) (*Select, error) {
+	if !strings.EqualFold(req.ExpressionType, "SQL") {
+		return nil, &Error{ErrCodeInvalidExpressionType, "The ExpressionType is invalid. Only SQL expressions are supported."}
+	}
+	if strings.TrimSpace(req.Expression) == "" {
+		return nil, &Error{ErrCodeMissingRequiredParameter, "The Expression is missing."}
+	}
+
+	s := &Select{progress: req.RequestProgress.Enabled}
+	in := req.InputSerialization
+	switch strings.ToUpper(in.CompressionType) {
+	case "", compressionNone:
+	case compressionGzip:
+		s.gzip = true
+	default:
+		return nil, &Error{ErrCodeInvalidCompressionFormat, "The file is not in a supported compression format. Only GZIP is supported."}
+	}
+
+	switch {
+	case in.CSV != nil && in.JSON == nil:
+		opts := *in.CSV
+		switch strings.ToUpper(opts.FileHeaderInfo) {
+		case "", fileHeaderNone, fileHeaderUse, fileHeaderIgnore:
+		default:
+			return nil, invalidParameter("FileHeaderInfo", opts.FileHeaderInfo)
+		}
+		if opts.RecordDelimiter != "\r\n" {
+			if err := checkDelimiter("RecordDelimiter", opts.RecordDelimiter, 1); err != nil {
+				return nil, err
+			}
+		}
+		if utf8.RuneCountInString(opts.FieldDelimiter) > 1 || opts.FieldDelimiter == "\n" || opts.FieldDelimiter == "\r" || opts.FieldDelimiter == `"` {
+			return nil, invalidParameter("FieldDelimiter", opts.FieldDelimiter)
+		}
+		if utf8.RuneCountInString(opts.Comments) > 1 {
+			return nil, invalidParameter("Comments", opts.Comments)
+		}
+		// Quotes are always `"`, escaped by doubling them.
+		if opts.QuoteCharacter != "" && opts.QuoteCharacter != `"` {
+			return nil, invalidParameter("QuoteCharacter", opts.QuoteCharacter)
+		}
+		if opts.QuoteEscapeCharacter != "" && opts.QuoteEscapeCharacter != `"` {
+			return nil, invalidParameter("QuoteEscapeCharacter", opts.QuoteEscapeCharacter)
+		}
+		s.newReader = func(r io.Reader) recordReader {
+			return newCSVReader(r, &opts)
+		}
+	case in.JSON != nil && in.CSV == nil:
+		switch strings.ToUpper(in.JSON.Type) {
+		case "", jsonTypeDocument, jsonTypeLines:
+		default:
+			return nil, invalidParameter("Type", in.JSON.Type)
+		}
+		s.newReader = newJSONReader
+	default:
+		return nil, &Error{ErrCodeInvalidRequestParameter, "Exactly one of CSV and JSON input serialization has to be set."}
+	}
+
+	out := req.OutputSerialization
+	switch {
+	case out.CSV != nil && out.JSON == nil:
+		opts := *out.CSV
+		switch strings.ToUpper(opts.QuoteFields) {
+		case "", quoteFieldsAlways, quoteFieldsAsNeeded:
+		default:
+			return nil, invalidParameter("QuoteFields", opts.QuoteFields)
+		}
+		for name, v := range map[string]string{
+			"RecordDelimiter":      opts.RecordDelimiter,
+			"FieldDelimiter":       opts.FieldDelimiter,
+			"QuoteCharacter":       opts.QuoteCharacter,
+			"QuoteEscapeCharacter": opts.QuoteEscapeCharacter,
+		} {
+			if err := checkDelimiter(name, v, 2); err != nil {
+				return nil, err
+			}
+		}
+		s.writer = newCSVWriter(&opts)
+	case out.JSON != nil && out.CSV == nil:
+		if err := checkDelimiter("RecordDelimiter", out.JSON.RecordDelimiter, 2); err != nil {
+			return nil, err
+		}
+		s.writer = newJSONWriter(out.JSON)
+	default:
+		return nil, &Error{ErrCodeInvalidRequestParameter, "Exactly one of CSV and JSON output serialization has to be set."}
+	}
+
+	q, err := parseQuery(req.Expression)
+	if err != nil {
+		return nil, err
+	}
+	s.query = q
+	s.rawJSON = in.JSON != nil && out.JSON != nil && q.projections == nil
+	return s, nil
+}
+
+// countingReader - counts the bytes read from a reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// Execute - runs the query over the object read from r and writes the
+// results to w as an event stream. Errors after the first message are
+// sent as an error message and returned.
+func (s *Select) Execute(r io.Reader, w io.Writer) error {
+	ew := &eventWriter{w: w}
+	if err := s.execute(r, ew); err != nil {
+		if ew.err != nil {
+			// The client is gone.
+			return ew.err
+		}
+		e, ok := err.(*Error)
+		if !ok {
+			e = &Error{ErrCodeInternalError, err.Error()}
+		}
+		ew.writeError(e.Code, e.Message)
+		return err
+	}
+	return nil
+}
+
+func (s *Select) execute(r io.Reader, ew *eventWriter) error {
+	scanned := &countingReader{r: r}
+	input := io.Reader(scanned)
+	if s.gzip {
+		gzReader, err := gzip.NewReader(scanned)
+		if err != nil {
+			return &Error{ErrCodeInvalidCompressionFormat, "The object is not a valid GZIP file."}
+		}
+		defer gzReader.Close()
+		input = gzReader
+	}
+	processed := &countingReader{r: input}
+	reader := s.newReader(processed)
+
+	var returned int64
+	var buf bytes.Buffer
+	flushRecords := func() error {
+		if buf.Len() == 0 {
+			return nil
+		}
+		returned += int64(buf.Len())
+		if err := ew.writeRecords(buf.Bytes()); err != nil {
+			return err
+		}
+		buf.Reset()
+		if s.progress {
+			return ew.writeStats("Progress", scanned.n, processed.n, returned)
+		}
+		return nil
+	}
+
+	q := s.query
+	var count int64
+	for q.limit < 0 || count < q.limit || len(q.aggregates) > 0 {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if err == gzip.ErrChecksum || err == gzip.ErrHeader {
+				return &Error{ErrCodeInvalidCompressionFormat, err.Error()}
+			}
+			return err
+		}
+
+		if q.where != nil {
+			v, err := q.where.eval(rec)
+			if err != nil {
+				return err
+			}
+			if !isTrue(v) {
+				continue
+			}
+		}
+
+		if len(q.aggregates) > 0 {
+			for _, agg := range q.aggregates {
+				if err = agg.update(rec); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err = s.writeRecord(&buf, rec); err != nil {
+			return err
+		}
+		count++
+		if buf.Len() >= maxRecordsPayload {
+			if err = flushRecords(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Aggregates return a single record.
+	if len(q.aggregates) > 0 && q.limit != 0 {
+		if err := s.writeRecord(&buf, nil); err != nil {
+			return err
+		}
+	}
+	if err := flushRecords(); err != nil {
+		return err
+	}
+	if err := ew.writeStats("Stats", scanned.n, processed.n, returned); err != nil {
+		return err
+	}
+	return ew.writeEnd()
+}
+
+// Serializes the projections of a record.
+func (s *Select) writeRecord(buf *bytes.Buffer, rec inputRecord) error {
+	q := s.query
+	if q.projections == nil {
+		if jsonRec, ok := rec.(*jsonRecord); ok && s.rawJSON {
+			if err := json.Compact(buf, jsonRec.raw); err != nil {
+				return &Error{ErrCodeJSONParsingError, err.Error()}
+			}
+			buf.WriteString(s.writer.(*jsonWriter).recordDelimiter)
+			return nil
+		}
+		names, values := rec.columns()
+		s.writer.write(buf, names, values)
+		return nil
+	}
+
+	names := make([]string, len(q.projections))
+	values := make([]value, len(q.projections))
+	for i, proj := range q.projections {
+		v, err := proj.expr.eval(rec)
+		if err != nil {
+			return err
+		}
+		values[i] = v
+		switch e := proj.expr.(type) {
+		case *column:
+			names[i] = e.path[len(e.path)-1]
+		default:
+			names[i] = "_" + strconv.Itoa(i+1)
+		}
+		if proj.alias != "" {
+			names[i] = proj.alias
+		}
+	}
+	s.writer.write(buf, names, values)
+	return nil
+}
@@ -0,0 +1,278 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Decodes the message at the start of data, returns the headers, the
+// payload and the remaining data.
+func decodeMessage(data []byte) (map[string]string, []byte, []byte, error) {
+	if len(data) < messageOverhead {
+		return nil, nil, nil, io.ErrUnexpectedEOF
+	}
+	totalLength := binary.BigEndian.Uint32(data[0:4])
+	headersLength := binary.BigEndian.Uint32(data[4:8])
+	if crc32.ChecksumIEEE(data[0:8]) != binary.BigEndian.Uint32(data[8:12]) {
+		return nil, nil, nil, fmt.Errorf("prelude checksum mismatch")
+	}
+	if uint32(len(data)) < totalLength || totalLength < messageOverhead+headersLength {
+		return nil, nil, nil, io.ErrUnexpectedEOF
+	}
+	if crc32.ChecksumIEEE(data[:totalLength-4]) != binary.BigEndian.Uint32(data[totalLength-4:totalLength]) {
+		return nil, nil, nil, fmt.Errorf("message checksum mismatch")
+	}
+
+	headers := make(map[string]string)
+	hdata := data[12 : 12+headersLength]
+	for len(hdata) > 0 {
+		nameLength := int(hdata[0])
+		if len(hdata) < 1+nameLength+3 || hdata[1+nameLength] != headerTypeString {
+			return nil, nil, nil, fmt.Errorf("malformed header")
+		}
+		name := string(hdata[1 : 1+nameLength])
+		hdata = hdata[1+nameLength+1:]
+		valueLength := int(binary.BigEndian.Uint16(hdata[0:2]))
+		if len(hdata) < 2+valueLength {
+			return nil, nil, nil, fmt.Errorf("malformed header")
+		}
+		headers[name] = string(hdata[2 : 2+valueLength])
+		hdata = hdata[2+valueLength:]
+	}
+	return headers, data[12+headersLength : totalLength-4], data[totalLength:], nil
+}
+
+// Runs a Select request over data, returns the records, the error code
+// and the headers of the last message.
+func runSelect(t *testing.T, requestXML string, data []byte) (string, string, map[string]string) {
+	req := &Request{}
+	if err := xml.Unmarshal([]byte(requestXML), req); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSelect(req)
+	if err != nil {
+		if e, ok := err.(*Error); ok {
+			return "", e.Code, nil
+		}
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	s.Execute(bytes.NewReader(data), &out)
+
+	var records bytes.Buffer
+	var headers map[string]string
+	for rest := out.Bytes(); len(rest) > 0; {
+		var payload []byte
+		if headers, payload, rest, err = decodeMessage(rest); err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case headers[":message-type"] == "error":
+			return records.String(), headers[":error-code"], headers
+		case headers[":event-type"] == "Records":
+			records.Write(payload)
+		}
+	}
+	if headers[":event-type"] != "End" {
+		t.Fatalf("Expected End event, got %v", headers)
+	}
+	return records.String(), "", headers
+}
+
+// Returns a request XML for a query and serialization options.
+func selectRequest(query, input, output string) string {
+	return fmt.Sprintf(`<SelectObjectContentRequest xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+<Expression>%s</Expression><ExpressionType>SQL</ExpressionType>
+<InputSerialization>%s</InputSerialization>
+<OutputSerialization>%s</OutputSerialization>
+</SelectObjectContentRequest>`, query, input, output)
+}
+
+// Tests Select over CSV objects.
+func TestSelectCSV(t *testing.T) {
+	data := []byte("name,qty,price\nWidget,12,2.50\nGadget,3,10\n\"Thing, large\",7,1\n")
+	csvUse := "<CSV><FileHeaderInfo>USE</FileHeaderInfo></CSV>"
+	csvOut := "<CSV/>"
+	testCases := []struct {
+		query, input, output string
+		expected             string
+		expectedCode         string
+	}{
+		{"SELECT * FROM S3Object", "<CSV/>", csvOut, string(data), ""},
+		{"SELECT * FROM S3Object", "<CSV><FileHeaderInfo>IGNORE</FileHeaderInfo></CSV>", csvOut, "Widget,12,2.50\nGadget,3,10\n\"Thing, large\",7,1\n", ""},
+		{"SELECT s.name FROM S3Object s WHERE CAST(s.qty AS INT) &gt; 5", csvUse, csvOut, "Widget\n\"Thing, large\"\n", ""},
+		{"SELECT _1, _3 FROM S3Object LIMIT 1", csvUse, csvOut, "Widget,2.50\n", ""},
+		{"SELECT name, price FROM S3Object WHERE name = 'Gadget'", csvUse, "<JSON/>", `{"name":"Gadget","price":"10"}` + "\n", ""},
+		{"SELECT * FROM S3Object WHERE qty = 3", csvUse, "<JSON/>", `{"name":"Gadget","qty":"3","price":"10"}` + "\n", ""},
+		{"SELECT COUNT(*), SUM(qty), AVG(price), MIN(name), MAX(CAST(qty AS INT)) FROM S3Object", csvUse, csvOut, "3,22,4.5,Gadget,12\n", ""},
+		{"SELECT COUNT(*) AS n FROM S3Object WHERE name LIKE '%dget'", csvUse, "<JSON/>", `{"n":2}` + "\n", ""},
+		{"SELECT name FROM S3Object", csvUse, "<CSV><QuoteFields>ALWAYS</QuoteFields><RecordDelimiter>;</RecordDelimiter></CSV>", `"Widget";"Gadget";"Thing, large";`, ""},
+		{"SELECT * FROM S3Object", "<CSV><FieldDelimiter>,</FieldDelimiter><RecordDelimiter>\n</RecordDelimiter><QuoteCharacter>'</QuoteCharacter></CSV>", csvOut, "", ErrCodeInvalidRequestParameter},
+		{"SELECT * FROM S3Object", "<CSV><FileHeaderInfo>FIRST</FileHeaderInfo></CSV>", csvOut, "", ErrCodeInvalidRequestParameter},
+		{"SELECT * FROM S3Object", "<CSV/><JSON/>", csvOut, "", ErrCodeInvalidRequestParameter},
+		{"SELECT * FROM S3Object", "<CompressionType>BZIP2</CompressionType><CSV/>", csvOut, "", ErrCodeInvalidCompressionFormat},
+		{"SELECT * FROM S3Object", "<CompressionType>GZIP</CompressionType><CSV/>", csvOut, "", ErrCodeInvalidCompressionFormat},
+		{"SELECT * FROM", "<CSV/>", csvOut, "", ErrCodeParseUnexpectedToken},
+		{"SELECT name FROM S3Object WHERE name + 1 = 2", csvUse, csvOut, "", ErrCodeEvaluatorInvalidArguments},
+	}
+	for i, testCase := range testCases {
+		records, code, _ := runSelect(t, selectRequest(testCase.query, testCase.input, testCase.output), data)
+		if code != testCase.expectedCode {
+			t.Errorf("Test %d: Expected error %q, got %q", i+1, testCase.expectedCode, code)
+			continue
+		}
+		if records != testCase.expected {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.expected, records)
+		}
+	}
+
+	// Expression types other than SQL are rejected.
+	req := strings.Replace(selectRequest("SELECT * FROM S3Object", "<CSV/>", csvOut), "<ExpressionType>SQL", "<ExpressionType>XPATH", 1)
+	if _, code, _ := runSelect(t, req, data); code != ErrCodeInvalidExpressionType {
+		t.Errorf("Expected %s, got %q", ErrCodeInvalidExpressionType, code)
+	}
+
+	// Custom record delimiters.
+	records, _, _ := runSelect(t, selectRequest("SELECT _2 FROM S3Object", "<CSV><RecordDelimiter>|</RecordDelimiter><FieldDelimiter>\t</FieldDelimiter></CSV>", csvOut), []byte("a\t1|b\t2|"))
+	if records != "1\n2\n" {
+		t.Errorf("Expected %q, got %q", "1\n2\n", records)
+	}
+}
+
+// Tests Select over JSON objects.
+func TestSelectJSON(t *testing.T) {
+	lines := []byte(`{"id":1,"user":{"name":"alice"},"tags":["a","b"],"active":true}
+{"id":2,"user":{"name":"bob"},"active":false}
+{"id":12345678901234567890,"user":null,"active":true}
+`)
+	document := []byte(`[
+  {"id": 1, "user": {"name": "alice"}},
+  {"id": 2, "user": {"name": "bob"}}
+]`)
+	jsonOut := "<JSON/>"
+	testCases := []struct {
+		query    string
+		data     []byte
+		output   string
+		expected string
+	}{
+		{"SELECT * FROM S3Object", lines, jsonOut, string(lines)},
+		{"SELECT s.id FROM S3Object s WHERE s.active = true", lines, jsonOut, `{"id":1}` + "\n" + `{"id":12345678901234567890}` + "\n"},
+		{"SELECT s.user.name AS who, s.tags FROM S3Object s WHERE s.user.name IS NOT NULL", lines, jsonOut, `{"who":"alice","tags":["a","b"]}` + "\n" + `{"who":"bob","tags":null}` + "\n"},
+		{"SELECT s.id, s.user.name FROM S3Object[*] s WHERE s.id &gt; 1", document, "<CSV/>", "2,bob\n"},
+		{"SELECT * FROM S3Object[*] s LIMIT 1", document, jsonOut, `{"id":1,"user":{"name":"alice"}}` + "\n"},
+		{"SELECT * FROM S3Object s WHERE s.id = 2", lines, "<CSV/>", `2,"{""name"":""bob""}",false` + "\n"},
+		{"SELECT COUNT(*), SUM(s.id) FROM S3Object[*] s", document, jsonOut, `{"_1":2,"_2":3}` + "\n"},
+	}
+	for i, testCase := range testCases {
+		records, code, _ := runSelect(t, selectRequest(testCase.query, "<JSON><Type>LINES</Type></JSON>", testCase.output), testCase.data)
+		if code != "" {
+			t.Errorf("Test %d: Unexpected error %s", i+1, code)
+			continue
+		}
+		if records != testCase.expected {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.expected, records)
+		}
+	}
+
+	// Malformed input ends the response with an error message.
+	_, code, _ := runSelect(t, selectRequest("SELECT * FROM S3Object", "<JSON/>", jsonOut), []byte("{\"a\":1}\n{\"a\":"))
+	if code != ErrCodeJSONParsingError {
+		t.Errorf("Expected %s, got %q", ErrCodeJSONParsingError, code)
+	}
+	if _, code, _ = runSelect(t, selectRequest("SELECT * FROM S3Object", "<JSON/>", jsonOut), []byte("[1, 2]")); code != ErrCodeJSONParsingError {
+		t.Errorf("Expected %s, got %q", ErrCodeJSONParsingError, code)
+	}
+}
+
+// Tests Select over compressed objects and the response framing.
+func TestSelectGzip(t *testing.T) {
+	var data bytes.Buffer
+	for i := 0; i < 20000; i++ {
+		fmt.Fprintf(&data, "%d,row %d\n", i, i)
+	}
+	var compressed bytes.Buffer
+	gzWriter := gzip.NewWriter(&compressed)
+	gzWriter.Write(data.Bytes())
+	gzWriter.Close()
+
+	req := &Request{}
+	if err := xml.Unmarshal([]byte(selectRequest("SELECT * FROM S3Object", "<CompressionType>GZIP</CompressionType><CSV/>", "<CSV/>")), req); err != nil {
+		t.Fatal(err)
+	}
+	req.RequestProgress.Enabled = true
+	s, err := NewSelect(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err = s.Execute(bytes.NewReader(compressed.Bytes()), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var records bytes.Buffer
+	events := make(map[string]int)
+	var stats []byte
+	for rest := out.Bytes(); len(rest) > 0; {
+		headers, payload, remaining, err := decodeMessage(rest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rest = remaining
+		events[headers[":event-type"]]++
+		switch headers[":event-type"] {
+		case "Records":
+			if len(payload) > maxRecordsPayload+64 {
+				t.Errorf("Records event of %d bytes", len(payload))
+			}
+			records.Write(payload)
+		case "Stats":
+			stats = payload
+		}
+	}
+	if !bytes.Equal(records.Bytes(), data.Bytes()) {
+		t.Error("Unexpected records")
+	}
+	if events["Records"] < 2 || events["Progress"] != events["Records"] || events["Stats"] != 1 || events["End"] != 1 {
+		t.Errorf("Unexpected events %v", events)
+	}
+	expectedStats := fmt.Sprintf("<BytesScanned>%d</BytesScanned><BytesProcessed>%d</BytesProcessed><BytesReturned>%d</BytesReturned>",
+		compressed.Len(), data.Len(), data.Len())
+	if !strings.Contains(string(stats), expectedStats) {
+		t.Errorf("Expected %s, got %s", expectedStats, stats)
+	}
+
+	// Corrupt messages are detected.
+	msg := encodeMessage([]header{{":event-type", "End"}}, []byte("payload"))
+	msg[len(msg)-5] ^= 0xff
+	if _, _, _, err = decodeMessage(msg); err == nil {
+		t.Error("Expected a checksum mismatch")
+	}
+	binary.BigEndian.PutUint32(msg[0:4], 0)
+	if _, _, _, err = decodeMessage(msg); err == nil {
+		t.Error("Expected a checksum mismatch")
+	}
+}
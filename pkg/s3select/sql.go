@@ -0,0 +1,648 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Token kinds of the SQL lexer.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+// token - a single lexeme of a query.
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+}
+
+// Keywords are identifiers which can not be used as column names
+// unless quoted.
+var keywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "LIMIT": true, "AS": true,
+	"AND": true, "OR": true, "NOT": true, "LIKE": true, "ESCAPE": true,
+	"IS": true, "NULL": true, "TRUE": true, "FALSE": true, "BETWEEN": true,
+	"IN": true, "CAST": true,
+}
+
+// Splits a query into tokens.
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '\'' || c == '"':
+			// Strings and quoted identifiers, quotes are escaped by
+			// doubling them.
+			var text []rune
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == c {
+					if j+1 < len(runes) && runes[j+1] == c {
+						text = append(text, c)
+						j++
+						continue
+					}
+					break
+				}
+				text = append(text, runes[j])
+			}
+			if j >= len(runes) {
+				return nil, parseError(i, "unterminated %c", c)
+			}
+			kind := tokenString
+			if c == '"' {
+				kind = tokenQuotedIdent
+			}
+			tokens = append(tokens, token{kind, string(text), i})
+			i = j + 1
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			if j < len(runes) && (runes[j] == 'e' || runes[j] == 'E') {
+				j++
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				for j < len(runes) && unicode.IsDigit(runes[j]) {
+					j++
+				}
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[i:j]), i})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[i:j]), i})
+			i = j
+		default:
+			op := string(c)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "<=", ">=", "<>", "!=", "||":
+					op = two
+				}
+			}
+			if !strings.Contains("()[],.*=<>+-/%", op) && len(op) == 1 {
+				return nil, parseError(i, "unexpected character %q", c)
+			}
+			tokens = append(tokens, token{tokenOperator, op, i})
+			i += len([]rune(op))
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(runes)}), nil
+}
+
+// Returns an error for a malformed query at offset.
+func parseError(offset int, format string, args ...interface{}) error {
+	return &Error{
+		Code:    ErrCodeParseUnexpectedToken,
+		Message: fmt.Sprintf("Syntax error at position %d: %s", offset+1, fmt.Sprintf(format, args...)),
+	}
+}
+
+// query - a parsed SELECT statement.
+type query struct {
+	// Projections of the statement, nil for `SELECT *`.
+	projections []projection
+	where       expr
+	limit       int64
+
+	// Aggregate functions of the projections, a query either has
+	// aggregates in every projection or none at all.
+	aggregates []*aggregate
+}
+
+// projection - a single expression of the select list.
+type projection struct {
+	expr  expr
+	alias string
+}
+
+// parser - recursive descent parser of the supported SQL subset.
+type parser struct {
+	tokens []token
+	pos    int
+	// Alias of S3Object, column references may be qualified by it.
+	alias string
+	// Aggregates found while parsing.
+	aggregates []*aggregate
+}
+
+// parseQuery - parses a query of the form
+//
+//   SELECT <projections> FROM S3Object[[*]] [[AS] alias]
+//     [WHERE <condition>] [LIMIT <number>]
+func parseQuery(sql string) (*query, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, alias: "s3object"}
+	q := &query{limit: -1}
+
+	if err = p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+
+	// Projections are parsed after the FROM clause has named the alias
+	// they may refer to.
+	projectionStart := p.pos
+	for depth := 0; ; p.pos++ {
+		t := p.peek()
+		if t.kind == tokenEOF {
+			return nil, parseError(t.start, "missing FROM")
+		}
+		if t.kind == tokenOperator && t.text == "(" {
+			depth++
+		} else if t.kind == tokenOperator && t.text == ")" {
+			depth--
+		} else if depth == 0 && p.isKeyword("FROM") {
+			break
+		}
+	}
+	fromPos := p.pos
+	p.pos++
+	if t := p.next(); t.kind != tokenIdent || !strings.EqualFold(t.text, "S3Object") {
+		return nil, parseError(t.start, "expected S3Object")
+	}
+	if p.isOperator("[") {
+		p.pos++
+		if err = p.expectOperator("*"); err != nil {
+			return nil, err
+		}
+		if err = p.expectOperator("]"); err != nil {
+			return nil, err
+		}
+	}
+	if p.isKeyword("AS") {
+		p.pos++
+	}
+	if t := p.peek(); t.kind == tokenIdent && !keywords[strings.ToUpper(t.text)] {
+		p.alias = strings.ToLower(t.text)
+		p.pos++
+	}
+	fromEnd := p.pos
+
+	// Projections.
+	p.pos = projectionStart
+	if p.isOperator("*") && p.pos+1 == fromPos {
+		p.pos++
+	} else {
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			proj := projection{expr: e}
+			if p.isKeyword("AS") {
+				p.pos++
+				t := p.next()
+				if t.kind != tokenIdent && t.kind != tokenQuotedIdent {
+					return nil, parseError(t.start, "expected alias")
+				}
+				proj.alias = t.text
+			} else if t := p.peek(); t.kind == tokenQuotedIdent || (t.kind == tokenIdent && !keywords[strings.ToUpper(t.text)]) {
+				proj.alias = t.text
+				p.pos++
+			}
+			q.projections = append(q.projections, proj)
+			if !p.isOperator(",") {
+				break
+			}
+			p.pos++
+		}
+		if p.pos != fromPos {
+			t := p.peek()
+			return nil, parseError(t.start, "unexpected %q", t.text)
+		}
+	}
+	q.aggregates = p.aggregates
+	p.aggregates = nil
+
+	// Conditions and limit.
+	p.pos = fromEnd
+	if p.isKeyword("WHERE") {
+		p.pos++
+		if q.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		if len(p.aggregates) > 0 {
+			return nil, &Error{ErrCodeParseUnsupportedSyntax, "Aggregate functions are not allowed in WHERE"}
+		}
+	}
+	if p.isKeyword("LIMIT") {
+		p.pos++
+		t := p.next()
+		if t.kind != tokenNumber {
+			return nil, parseError(t.start, "expected a number")
+		}
+		if q.limit, err = strconv.ParseInt(t.text, 10, 64); err != nil || q.limit < 0 {
+			return nil, parseError(t.start, "invalid limit %s", t.text)
+		}
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, parseError(t.start, "unexpected %q", t.text)
+	}
+
+	// Aggregates can not be mixed with columns outside of them.
+	if len(q.aggregates) > 0 {
+		for _, proj := range q.projections {
+			if hasColumnOutsideAggregate(proj.expr) {
+				return nil, &Error{ErrCodeParseUnsupportedSyntax, "Columns must be used inside aggregate functions"}
+			}
+		}
+	}
+	return q, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+func (p *parser) isOperator(op string) bool {
+	t := p.peek()
+	return t.kind == tokenOperator && t.text == op
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if t := p.next(); t.kind != tokenIdent || !strings.EqualFold(t.text, keyword) {
+		return parseError(t.start, "expected %s", keyword)
+	}
+	return nil
+}
+
+func (p *parser) expectOperator(op string) error {
+	if t := p.next(); t.kind != tokenOperator || t.text != op {
+		return parseError(t.start, "expected %q", op)
+	}
+	return nil
+}
+
+// Operator precedence, from loosest to tightest binding:
+//   OR, AND, NOT, comparisons, + - ||, * / %, unary -
+func (p *parser) parseExpr() (expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.isKeyword("NOT") {
+		p.pos++
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{e}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind == tokenOperator {
+		switch t.text {
+		case "=", "!=", "<>", "<", "<=", ">", ">=":
+			p.pos++
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			return &compareExpr{op: t.text, left: left, right: right}, nil
+		}
+		return left, nil
+	}
+
+	if p.isKeyword("IS") {
+		p.pos++
+		not := false
+		if p.isKeyword("NOT") {
+			p.pos++
+			not = true
+		}
+		if err = p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNullExpr{e: left, not: not}, nil
+	}
+
+	not := false
+	if p.isKeyword("NOT") {
+		p.pos++
+		not = true
+	}
+	switch {
+	case p.isKeyword("LIKE"):
+		p.pos++
+		pattern, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		like := &likeExpr{e: left, pattern: pattern, not: not}
+		if p.isKeyword("ESCAPE") {
+			p.pos++
+			if like.escape, err = p.parseAdditive(); err != nil {
+				return nil, err
+			}
+		}
+		return like, nil
+	case p.isKeyword("BETWEEN"):
+		p.pos++
+		low, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &betweenExpr{e: left, low: low, high: high, not: not}, nil
+	case p.isKeyword("IN"):
+		p.pos++
+		if err = p.expectOperator("("); err != nil {
+			return nil, err
+		}
+		in := &inExpr{e: left, not: not}
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			in.list = append(in.list, e)
+			if !p.isOperator(",") {
+				break
+			}
+			p.pos++
+		}
+		if err = p.expectOperator(")"); err != nil {
+			return nil, err
+		}
+		return in, nil
+	}
+	if not {
+		t := p.peek()
+		return nil, parseError(t.start, "expected LIKE, BETWEEN or IN")
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+") || p.isOperator("-") || p.isOperator("||") {
+		op := p.next().text
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithExpr{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*") || p.isOperator("/") || p.isOperator("%") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithExpr{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.isOperator("-") {
+		p.pos++
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &arithExpr{op: "-", left: &literal{float64(0)}, right: e}, nil
+	}
+	if p.isOperator("+") {
+		p.pos++
+		return p.parseUnary()
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, parseError(t.start, "invalid number %s", t.text)
+		}
+		return &literal{f}, nil
+	case tokenString:
+		return &literal{t.text}, nil
+	case tokenQuotedIdent:
+		return p.parseColumn(t)
+	case tokenOperator:
+		if t.text == "(" {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expectOperator(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		}
+	case tokenIdent:
+		switch strings.ToUpper(t.text) {
+		case "NULL":
+			return &literal{nil}, nil
+		case "TRUE":
+			return &literal{true}, nil
+		case "FALSE":
+			return &literal{false}, nil
+		case "CAST":
+			return p.parseCast()
+		}
+		if keywords[strings.ToUpper(t.text)] {
+			break
+		}
+		if p.isOperator("(") {
+			return p.parseFunction(t)
+		}
+		return p.parseColumn(t)
+	}
+	return nil, parseError(t.start, "unexpected %q", t.text)
+}
+
+// Parses `CAST(<expr> AS <type>)`.
+func (p *parser) parseCast() (expr, error) {
+	if err := p.expectOperator("("); err != nil {
+		return nil, err
+	}
+	e, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expectKeyword("AS"); err != nil {
+		return nil, err
+	}
+	t := p.next()
+	typ := strings.ToUpper(t.text)
+	switch typ {
+	case "INT", "INTEGER", "FLOAT", "DECIMAL", "NUMERIC", "STRING", "VARCHAR", "BOOL", "BOOLEAN":
+	default:
+		return nil, parseError(t.start, "unsupported type %q", t.text)
+	}
+	if err = p.expectOperator(")"); err != nil {
+		return nil, err
+	}
+	return &castExpr{e: e, typ: typ}, nil
+}
+
+// Parses aggregate and scalar function calls.
+func (p *parser) parseFunction(name token) (expr, error) {
+	p.pos++
+	fn := strings.ToUpper(name.text)
+	switch fn {
+	case "COUNT", "SUM", "AVG", "MIN", "MAX":
+		agg := &aggregate{fn: fn}
+		if fn == "COUNT" && p.isOperator("*") {
+			p.pos++
+		} else {
+			// Aggregates can not be nested.
+			outer := p.aggregates
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if len(p.aggregates) != len(outer) {
+				return nil, &Error{ErrCodeParseUnsupportedSyntax, "Aggregate functions can not be nested"}
+			}
+			agg.e = e
+		}
+		if err := p.expectOperator(")"); err != nil {
+			return nil, err
+		}
+		p.aggregates = append(p.aggregates, agg)
+		return agg, nil
+	case "LOWER", "UPPER", "CHAR_LENGTH", "CHARACTER_LENGTH", "TRIM":
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expectOperator(")"); err != nil {
+			return nil, err
+		}
+		return &funcExpr{fn: fn, e: e}, nil
+	}
+	return nil, &Error{ErrCodeParseUnsupportedSyntax, fmt.Sprintf("Unsupported function %s", name.text)}
+}
+
+// Parses a possibly qualified column reference, a leading S3Object
+// alias is dropped.
+func (p *parser) parseColumn(first token) (expr, error) {
+	path := []string{first.text}
+	for p.isOperator(".") {
+		p.pos++
+		t := p.next()
+		if t.kind != tokenIdent && t.kind != tokenQuotedIdent {
+			return nil, parseError(t.start, "expected column name")
+		}
+		path = append(path, t.text)
+	}
+	if len(path) > 1 && first.kind == tokenIdent && strings.ToLower(first.text) == p.alias {
+		path = path[1:]
+	}
+	return &column{path: path}, nil
+}
@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import "testing"
+
+// Tests parsing of supported and malformed queries.
+func TestParseQuery(t *testing.T) {
+	testCases := []struct {
+		query        string
+		expectedCode string
+	}{
+		{"SELECT * FROM S3Object", ""},
+		{"select * from s3object s where s.a = 'x' limit 10", ""},
+		{"SELECT s._1, s._2 AS second FROM S3Object AS s", ""},
+		{"SELECT * FROM S3Object[*] s WHERE s.a.b IS NOT NULL", ""},
+		{`SELECT "quoted name" FROM S3Object WHERE "quoted name" LIKE 'a%'`, ""},
+		{"SELECT COUNT(*), SUM(CAST(price AS FLOAT)), MAX(name) FROM S3Object WHERE qty BETWEEN 1 AND 10", ""},
+		{"SELECT AVG(a) / COUNT(*) FROM S3Object", ""},
+		{"SELECT a FROM S3Object WHERE a IN ('x', 'y') AND NOT (b > 1 OR c <= -2.5e1)", ""},
+		{"SELECT LOWER(a) || '-' || UPPER(b) FROM S3Object", ""},
+		{"", ErrCodeParseUnexpectedToken},
+		{"SELECT * FROM", ErrCodeParseUnexpectedToken},
+		{"SELECT * FROM S3Object WHERE", ErrCodeParseUnexpectedToken},
+		{"SELECT * FROM mytable", ErrCodeParseUnexpectedToken},
+		{"SELECT a, FROM S3Object", ErrCodeParseUnexpectedToken},
+		{"SELECT * FROM S3Object WHERE a = 'unterminated", ErrCodeParseUnexpectedToken},
+		{"SELECT * FROM S3Object LIMIT -1", ErrCodeParseUnexpectedToken},
+		{"SELECT * FROM S3Object WHERE a ! b", ErrCodeParseUnexpectedToken},
+		{"SELECT a, COUNT(*) FROM S3Object", ErrCodeParseUnsupportedSyntax},
+		{"SELECT COUNT(MAX(a)) FROM S3Object", ErrCodeParseUnsupportedSyntax},
+		{"SELECT * FROM S3Object WHERE COUNT(*) > 1", ErrCodeParseUnsupportedSyntax},
+		{"SELECT SOUNDEX(a) FROM S3Object", ErrCodeParseUnsupportedSyntax},
+	}
+	for i, testCase := range testCases {
+		_, err := parseQuery(testCase.query)
+		if testCase.expectedCode == "" {
+			if err != nil {
+				t.Errorf("Test %d: Unexpected error %v", i+1, err)
+			}
+			continue
+		}
+		if e, ok := err.(*Error); !ok || e.Code != testCase.expectedCode {
+			t.Errorf("Test %d: Expected %s, got %v", i+1, testCase.expectedCode, err)
+		}
+	}
+}
+
+// Tests evaluation of conditions over a CSV record.
+func TestEvalWhere(t *testing.T) {
+	rec := &csvRecord{
+		header: newCSVHeader([]string{"name", "qty", "price", "empty"}),
+		fields: []string{"Widget", "12", "2.50", ""},
+	}
+	testCases := []struct {
+		where    string
+		expected bool
+	}{
+		{"name = 'Widget'", true},
+		{"Name = 'Widget'", true},
+		{"s.name = 'Widget'", true},
+		{"_1 = 'Widget'", true},
+		{"qty = 12", true},
+		{"qty > 9", true},
+		{"qty > '9'", false},
+		{"CAST(qty AS INT) > 9", true},
+		{"price * qty = 30", true},
+		{"qty % 5 = 2", true},
+		{"name LIKE 'W%t'", true},
+		{"name LIKE 'w%'", false},
+		{"name LIKE '_idge_'", true},
+		{"name NOT LIKE '%x%'", true},
+		{"'50%' LIKE '50!%' ESCAPE '!'", true},
+		{"qty BETWEEN 10 AND 12", true},
+		{"qty NOT BETWEEN 10 AND 12", false},
+		{"name IN ('Gadget', 'Widget')", true},
+		{"qty NOT IN (1, 2)", true},
+		{"empty = ''", true},
+		{"empty IS NULL", false},
+		{"missing IS NULL", true},
+		{"missing = 1", false},
+		{"NOT missing = 1", false},
+		{"missing = 1 OR qty = 12", true},
+		{"missing = 1 AND qty = 12", false},
+		{"CHAR_LENGTH(name) = 6 AND LOWER(name) = 'widget'", true},
+		{"TRUE AND NOT FALSE", true},
+	}
+	for i, testCase := range testCases {
+		q, err := parseQuery("SELECT * FROM S3Object s WHERE " + testCase.where)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		v, err := q.where.eval(rec)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if isTrue(v) != testCase.expected {
+			t.Errorf("Test %d: %s: Expected %t, got %v", i+1, testCase.where, testCase.expected, v)
+		}
+	}
+
+	// Arithmetic on text and failed casts are errors.
+	for i, where := range []string{"name + 1 = 2", "CAST(name AS INT) = 1", "qty / 0 = 1"} {
+		q, err := parseQuery("SELECT * FROM S3Object WHERE " + where)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if _, err = q.where.eval(rec); err == nil {
+			t.Errorf("Test %d: %s: Expected an error", i+1, where)
+		}
+	}
+}